@@ -2,20 +2,58 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/alerting"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
 	"github.com/Tomas-vilte/GoMusicBot/internal/cache"
+	"github.com/Tomas-vilte/GoMusicBot/internal/charts"
+	"github.com/Tomas-vilte/GoMusicBot/internal/cluster"
 	"github.com/Tomas-vilte/GoMusicBot/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/grpcserver"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/httpapi"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/queueapi"
+	"github.com/Tomas-vilte/GoMusicBot/internal/dashboard"
+	"github.com/Tomas-vilte/GoMusicBot/internal/devmode"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/file_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/rest"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/shard"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/health"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/identify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/lavalink"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/internal/metrics"
 	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/plugin"
 	"github.com/Tomas-vilte/GoMusicBot/internal/profiler"
+	"github.com/Tomas-vilte/GoMusicBot/internal/radio"
+	"github.com/Tomas-vilte/GoMusicBot/internal/recommend"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
 	"github.com/Tomas-vilte/GoMusicBot/internal/services/providers/youtube_provider"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotifysync"
+	"github.com/Tomas-vilte/GoMusicBot/internal/stats"
+	"github.com/Tomas-vilte/GoMusicBot/internal/voicestatus"
+	"github.com/Tomas-vilte/GoMusicBot/internal/ytwatch"
 	"github.com/bwmarrin/discordgo"
+	"github.com/getsentry/sentry-go"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Las imágenes de Docker no traen la base de zonas horarias de las alarmas y reproducciones programadas.
 )
 
 var (
@@ -25,25 +63,74 @@ var (
 )
 
 func main() {
+	dev := flag.Bool("dev", false, "modo desarrollo: reproduce localmente y lee los comandos de la entrada estándar")
+	flag.Parse()
+	// Cargar la configuración antes del logger, ya que este depende de ella.
+	envErr := envconfig.Process("", cfg)
+	if envErr != nil {
+		cfg.Log = logging.DefaultConfig
+	}
+	if *dev {
+		cfg.Dev.Enabled = true
+	}
 	// Crear un nuevo logger usando la librería zap.
-	logger, err := logging.NewZapLogger(true)
+	logger, err := logging.NewLogger(cfg.Log)
 	if err != nil {
 		panic("Error creando el logger: " + err.Error())
 	}
-	promRegistry := metrics.NewPrometheusRegistry()
-	commandUsageCounter := metrics.NewCommandUsageCounter()
-	cacheMetrics := metrics.NewCacheMetrics()
-	promRegistry.Register(commandUsageCounter)
-	promRegistry.RegisterCacheMetrics(cacheMetrics)
+	if cfg.Sentry.DSN != "" {
+		if err := sentry.Init(sentry.ClientOptions{
+			Dsn:         cfg.Sentry.DSN,
+			Environment: cfg.Sentry.Environment,
+			SampleRate:  cfg.Sentry.SampleRate,
+		}); err != nil {
+			logger.Error("Error al inicializar Sentry", zap.Error(err))
+		} else {
+			logger = logger.WithSentry(sentry.CurrentHub())
+			defer sentry.Flush(2 * time.Second)
+		}
+	}
+	if envErr != nil {
+		logger.Error("error al cargar las variables de entorno", zap.Error(envErr))
+	}
+	metricSet, err := config.GetMetrics(cfg)
+	if err != nil {
+		logger.Error("Error al crear las métricas, se desactivan", zap.Error(err))
+		metricSet = metrics.NewRecorderSet(metrics.NopRecorder{})
+	}
+	commandUsage := metricSet.CommandUsage
+	cacheMetrics := metricSet.Cache
+	audioMetrics := metricSet.Audio
+	fetcherMetrics := metricSet.Fetcher
+	processGauge := metricSet.Processes
+	if cfg.CloudWatch.EMF {
+		commandUsage = metrics.NewEMFCounter(commandUsage, logger.Named("emf"), cfg.CloudWatch.Namespace, "CommandUsage", "command")
+		fetcherMetrics = metrics.NewEMFFetcherMetrics(fetcherMetrics, logger.Named("emf"), cfg.CloudWatch.Namespace)
+	}
 
-	promHTTPServer := metrics.NewPrometheusHTTPServer(":8080", promRegistry)
+	if metricSet.Registry != nil {
+		promHTTPServer := metrics.NewPrometheusHTTPServer(":8080", metricSet.Registry)
 
-	go func() {
-		if err := promHTTPServer.Start(); err != nil {
-			logger.Error("Error al iniciar el servidor HTTP de métricas Prometheus: ", zap.Error(err))
-		}
-	}()
+		go func() {
+			if err := promHTTPServer.Start(); err != nil {
+				logger.Error("Error al iniciar el servidor HTTP de métricas Prometheus: ", zap.Error(err))
+			}
+		}()
+	}
 	profiler.StartProfiler()
+	if cfg.Pprof.Enabled {
+		pprofServer, err := profiler.NewPprofServer(cfg.Pprof.Address)
+		if err != nil {
+			logger.Error("Error al crear el servidor de pprof", zap.Error(err))
+		} else {
+			go func() {
+				if err := pprofServer.Start(); err != nil {
+					logger.Error("Error al iniciar el servidor de pprof", zap.Error(err))
+				}
+			}()
+			defer pprofServer.Close()
+		}
+	}
 	defer func() {
 		// Cerrar el logger cuando la función termine.
 		err := logger.Close()
@@ -51,44 +138,378 @@ func main() {
 			logger.Error("Error cerrando el logger", zap.Error(err))
 		}
 	}()
+	voiceSend, err := config.GetVoiceSendConfig(cfg)
+	if err != nil {
+		logger.Error("Configuración de envío de voz inválida, se usan los valores por defecto", zap.Error(err))
+		voiceSend = codec.SendConfig{}
+	}
 	ctx, cancelCtx = context.WithCancel(context.Background())
 	defer cancelCtx()
-	if err := envconfig.Process("", cfg); err != nil {
-		logger.Error("error al cargar las variables de entorno", zap.Error(err))
+	if cfg.Dev.Enabled {
+		runDevMode(logger, metricSet, voiceSend)
+		return
 	}
-	dg, err := discordgo.New("Bot " + cfg.DiscordToken)
+	shards, err := shard.NewManager(cfg.DiscordToken, cfg.Shards.Count, logger.Named("shard"))
 	if err != nil {
 		logger.Error("error al crear la session de messaging", zap.Error(err))
 		return
 	}
+	shards.WithIntents(discordgo.IntentsAll)
+	dg := shards.Session()
+
+	var alerter alerting.Alerter = alerting.NopAlerter{}
+	if cfg.Alerting.WebhookURL != "" {
+		alerter = alerting.NewWebhookAlerter(cfg.Alerting.WebhookURL, logger.Named("alerting")).
+			WithDedupWindow(cfg.Alerting.DedupWindow).
+			WithMaxPerHour(cfg.Alerting.MaxPerHour)
+	}
+	voiceFailures := alerting.NewSpikeTracker(alerter, alerting.Alert{
+		Key:      alerting.KeyVoiceConnectFailures,
+		Title:    "Pico de fallas de conexión a voz",
+		Message:  fmt.Sprintf("Fallaron %d conexiones a canales de voz en los últimos %s", cfg.Alerting.VoiceFailureThreshold, cfg.Alerting.VoiceFailureWindow),
+		Severity: alerting.SeverityCritical,
+	}, cfg.Alerting.VoiceFailureThreshold, cfg.Alerting.VoiceFailureWindow)
+
+	healthServer := health.NewServer(cfg.Health.Address).AddReadinessCheck("discord", health.DiscordGatewayCheck(shards.Sessions()...))
+	for name, check := range config.GetReadinessChecks(cfg) {
+		healthServer.AddReadinessCheck(name, check)
+		go alerting.WatchCheck(ctx, alerter, name, check, cfg.Alerting.StoreCheckInterval)
+	}
+	go func() {
+		if err := healthServer.Start(); err != nil {
+			logger.Error("Error al iniciar el servidor HTTP de health checks", zap.Error(err))
+		}
+	}()
+	defer func() {
+		if err := healthServer.Shutdown(context.Background()); err != nil {
+			logger.Error("Error al detener el servidor HTTP de health checks", zap.Error(err))
+		}
+	}()
 
 	storage := discord.NewInMemoryStorage()
-	cacheStorage := cache.NewCache(logger, cacheMetrics, cache.DefaultCacheConfig, "metadata_cache")
-	audioCache := cache.NewAudioCache(logger, cache.DefaultCacheConfigAudio, cacheMetrics, "audio_cache")
-	realYouTubeClient, err := youtube_provider.NewRealYouTubeClient(cfg.YoutubeApiKey)
+	cacheStorage := cache.NewCache(logger.Named("cache"), cacheMetrics, cache.DefaultCacheConfig, "metadata_cache")
+	audioCache := cache.NewAudioCache(logger.Named("cache"), cache.DefaultCacheConfigAudio, cacheMetrics, "audio_cache")
+	var s3AudioCache *cache.S3AudioCache
+	if cfg.Transcode.QueueURL != "" {
+		if s3AudioCache, err = config.GetS3AudioCache(ctx, cfg, audioCache, logger.Named("cache")); err != nil {
+			logger.Error("Error al crear la caché de audio de S3; las canciones las transcodifica el bot", zap.Error(err))
+		} else {
+			audioCache = s3AudioCache
+		}
+	}
+	var addressRotator *fetcher.AddressRotator
+	if cfg.Outbound.IPv6Block != "" {
+		if addressRotator, err = fetcher.NewAddressRotator(cfg.Outbound.IPv6Block); err != nil {
+			logger.Error("Error al configurar la rotación de direcciones IPv6", zap.Error(err))
+			return
+		}
+	}
+	var realYouTubeClient *youtube_provider.RealYouTubeClient
+	if addressRotator != nil {
+		realYouTubeClient, err = youtube_provider.NewRealYouTubeClientWithTransport(cfg.YoutubeApiKey, addressRotator.Transport())
+	} else {
+		realYouTubeClient, err = youtube_provider.NewRealYouTubeClient(cfg.YoutubeApiKey)
+	}
 	if err != nil {
 		logger.Error("Error al crear el client de youtube_provider", zap.Error(err))
 		return
 	}
-	youtubeService := youtube_provider.NewYouTubeProvider(cfg.YoutubeApiKey, logger, realYouTubeClient)
+	lookupPool := fetcher.NewLookupPool(cfg.Lookup.Workers, cfg.Lookup.RatePerSecond, cfg.Lookup.Burst)
+	go lookupPool.Run(ctx)
+	youtubeService := fetcher.NewRateLimitedYouTubeService(youtube_provider.NewYouTubeProvider(cfg.YoutubeApiKey, logger.Named("youtube"), realYouTubeClient), lookupPool)
 	executorCommand := fetcher.NewCommandExecutor()
 
-	youtubeFetcher := fetcher.NewYoutubeFetcher(logger, cacheStorage, youtubeService, audioCache, executorCommand)
+	circuitBreaker := fetcher.NewCircuitBreaker(cfg.Circuit.FailureThreshold, cfg.Circuit.OpenTimeout).WithOnOpen(func() {
+		alerter.Alert(alerting.Alert{
+			Key:      alerting.KeyFetcherCircuitOpen,
+			Title:    "Circuit breaker de YouTube abierto",
+			Message:  fmt.Sprintf("Fallaron %d solicitudes seguidas a YouTube; se rechazan las búsquedas y descargas por %s", cfg.Circuit.FailureThreshold, cfg.Circuit.OpenTimeout),
+			Severity: alerting.SeverityCritical,
+		})
+	})
+	processLimiter := fetcher.NewProcessLimiter(cfg.Downloads.MaxConcurrent, cfg.Downloads.MaxConcurrentPerGuild)
+	youtubeFetcher := fetcher.NewYoutubeFetcher(logger.Named("fetcher"), cacheStorage, youtubeService, audioCache, executorCommand).WithMetrics(fetcherMetrics).WithProcessGauge(processGauge).WithCircuitBreaker(circuitBreaker).WithProcessLimiter(processLimiter).WithPlaylistResolution(cfg.Lookup.PlaylistMaxSize, cfg.Lookup.PlaylistEager, cfg.Lookup.PlaylistWorkers)
+	auditStore, err := config.GetAuditStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de auditoría", zap.Error(err))
+		return
+	}
+	auditor := audit.NewAuditor(auditStore, cfg.Audit.Retention, logger.Named("audit"))
+	go auditor.StartRetention(ctx, time.Hour)
+	savedPlaylistStore, err := config.GetSavedPlaylistStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de listas guardadas", zap.Error(err))
+		return
+	}
+	settingsStore, err := config.GetSettingsStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de configuración por servidor", zap.Error(err))
+		return
+	}
+	historyStore, err := config.GetHistoryStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store del historial de reproducción", zap.Error(err))
+		return
+	}
+	scheduleStore, err := config.GetScheduleStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de las reproducciones programadas", zap.Error(err))
+		return
+	}
+	scheduleLocation, err := time.LoadLocation(cfg.Schedules.Timezone)
+	if err != nil {
+		logger.Error("Zona horaria de las reproducciones programadas inválida", zap.String("timezone", cfg.Schedules.Timezone), zap.Error(err))
+		return
+	}
+
+	spotifyLinkStore, err := config.GetSpotifySyncStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de las listas de Spotify vinculadas", zap.Error(err))
+		return
+	}
+
+	youtubeSubscriptionStore, err := config.GetYouTubeWatchStore(cfg)
+	if err != nil {
+		logger.Error("Error al crear el store de las suscripciones a canales de YouTube", zap.Error(err))
+		return
+	}
+
 	responseHandler := discord.NewDiscordResponseHandler(logger)
-	sessionService := discord.NewSessionService(dg)
+	sessionService := discord.NewAuditSessionService(discord.NewRetrySessionService(discord.NewSessionService(dg), rest.DefaultRetryPolicy, logger.Named("rest")), auditor)
 
-	handler := discord.NewInteractionHandler(ctx, cfg.DiscordToken, responseHandler, sessionService, youtubeFetcher, storage, cfg, logger, commandUsageCounter, cacheStorage, audioCache, youtubeService, executorCommand).WithLogger(logger)
+	var songLooker fetcher.SongLooker = youtubeFetcher
+	var lavalinkClient *lavalink.Client
+	if cfg.Lavalink.Enabled {
+		botUser, err := dg.User("@me")
+		if err != nil {
+			logger.Error("Error al obtener el usuario del bot", zap.Error(err))
+			return
+		}
+		lavalinkClient = lavalink.NewClient(cfg.Lavalink.Address, cfg.Lavalink.Password, cfg.Lavalink.Secure, logger.Named("lavalink"))
+		if err := lavalinkClient.Connect(ctx, botUser.ID); err != nil {
+			logger.Error("Error al conectar con Lavalink", zap.Error(err))
+			return
+		}
+		healthServer.AddReadinessCheck("lavalink", lavalinkClient.Ready)
+		shards.AddHandler(lavalinkClient.OnVoiceStateUpdate)
+		shards.AddHandler(lavalinkClient.OnVoiceServerUpdate)
+		songLooker = lavalink.NewSongLooker(lavalinkClient)
+	}
+	var spotifyClient *spotify.Client
+	if cfg.Spotify.ClientID != "" {
+		spotifyClient = spotify.NewClient(cfg.Spotify.ClientID, cfg.Spotify.ClientSecret)
+		songLooker = fetcher.NewSpotifyFetcher(logger.Named("spotify"), spotifyClient, songLooker).WithMaxTracks(cfg.Spotify.MaxTracks)
+	}
+	plugins := plugin.NewManager(logger.Named("plugins"))
+	songLooker = plugins.SongLooker(songLooker)
+
+	handler := discord.NewInteractionHandler(ctx, cfg.DiscordToken, responseHandler, sessionService, songLooker, storage, cfg, logger, commandUsage, cacheStorage, audioCache, youtubeService, executorCommand).WithLogger(logger).WithAuditLog(auditor).WithAudioMetrics(audioMetrics).WithVoiceSendConfig(voiceSend).WithVoiceFailureTracker(voiceFailures).WithFetcherMetrics(fetcherMetrics).WithProcessGauge(processGauge).WithCircuitBreaker(circuitBreaker).WithProcessLimiter(processLimiter).WithSavedPlaylists(playlists.NewService(savedPlaylistStore)).WithSettings(settingsStore).WithHistory(historyStore).WithSchedules(scheduleStore, scheduleLocation).WithPresencePolicy(cfg.Presence.Policy, cfg.Presence.GracePeriod)
+	if len(cfg.Guilds.Allowed) > 0 {
+		handler.WithAllowedGuilds(cfg.Guilds.Allowed)
+	}
+	if lavalinkClient != nil {
+		handler.WithLavalink(lavalinkClient)
+	}
+	handler.WithCharts(charts.NewAppleClient(), cfg.Trending.Region, cfg.Trending.Limit)
+	youtubeFeeds := ytwatch.NewFeedClient()
+	handler.WithYouTubeWatch(youtubeFeeds, youtubeSubscriptionStore, discordmessenger.NewMessageSenderImpl(dg, logger))
+	if spotifyClient != nil {
+		handler.WithSpotifySync(spotifyClient, spotifyLinkStore, discordmessenger.NewMessageSenderImpl(dg, logger))
+	}
+	if lavalinkClient == nil {
+		handler.WithMetadataRefresh(youtubeFetcher, cfg.Lookup.MetadataTTL)
+	}
+	if searcher, ok := songLooker.(fetcher.SongSearcher); ok {
+		handler.WithRecommender(recommend.NewSearchProvider(searcher, logger.Named("recommend")))
+		handler.WithRadio(radio.NewSearchSource(searcher, logger.Named("radio")))
+	}
+	if addressRotator != nil {
+		youtubeFetcher.WithAddressRotation(addressRotator)
+		handler.WithAddressRotation(addressRotator)
+	}
+	if len(cfg.YtDlp.Clients) > 0 {
+		clientRotator := fetcher.NewClientRotator(cfg.YtDlp.Clients, cfg.YtDlp.BlockCooldown)
+		if cfg.YtDlp.TokenCommand != "" {
+			clientRotator.WithTokenProvider(fetcher.NewCommandTokenProvider(executorCommand, cfg.YtDlp.TokenCommand, cfg.YtDlp.TokenTTL))
+		} else if cfg.YtDlp.POToken != "" {
+			clientRotator.WithTokenProvider(fetcher.NewStaticTokenProvider(fetcher.POToken{Token: cfg.YtDlp.POToken, VisitorData: cfg.YtDlp.VisitorData}))
+		}
+		youtubeFetcher.WithClientRotation(clientRotator)
+		handler.WithClientRotation(clientRotator)
+	}
+	if cfg.Identify.AcoustIDKey != "" {
+		handler.WithIdentifier(identify.NewAcoustID(cfg.Identify.AcoustIDKey, cfg.Identify.Fpcalc, executorCommand))
+	}
+	for i, token := range cfg.Assistants.Tokens {
+		assistantShards, err := shard.NewManager(token, 0, logger.Named("assistant"))
+		if err != nil {
+			logger.Error("Error al crear la sesión del asistente", zap.Int("assistant", i), zap.Error(err))
+			continue
+		}
+		assistantShards.WithIntents(discordgo.IntentsGuilds | discordgo.IntentsGuildVoiceStates)
+		if err := assistantShards.Open(); err != nil {
+			logger.Error("Error al abrir la sesión del asistente", zap.Int("assistant", i), zap.Error(err))
+			continue
+		}
+		defer func() {
+			if err := assistantShards.Close(); err != nil {
+				logger.Error("Error al cerrar la sesión del asistente", zap.Error(err))
+			}
+		}()
+		handler.WithAssistants(assistantShards)
+	}
+	if cfg.Jobs.Backend != "" {
+		jobQueue, err := config.GetJobQueue(ctx, cfg, logger.Named("jobs"))
+		if err != nil {
+			logger.Error("Error al crear la cola de trabajos; las búsquedas se procesan en cada interacción", zap.Error(err))
+		} else {
+			handler.WithJobQueue(jobQueue, cfg.Jobs.PrefetchAhead)
+			dispatcher := jobs.NewDispatcher(jobQueue, cfg.Jobs.Workers, logger.Named("jobs"))
+			handler.RegisterJobHandlers(dispatcher)
+			go dispatcher.Run(ctx)
+			defer func() {
+				if err := jobQueue.Close(); err != nil {
+					logger.Error("Error al cerrar la cola de trabajos", zap.Error(err))
+				}
+			}()
+		}
+	}
+	if s3AudioCache != nil {
+		transcodeQueue, err := config.GetTranscodeQueue(ctx, cfg, logger.Named("transcode"))
+		if err != nil {
+			logger.Error("Error al crear la cola de transcodificación; las canciones las transcodifica el bot", zap.Error(err))
+		} else {
+			handler.WithRemoteTranscoding(transcodeQueue, s3AudioCache.Key, cfg.Transcode.PrefetchAhead)
+			defer func() {
+				if err := transcodeQueue.Close(); err != nil {
+					logger.Error("Error al cerrar la cola de transcodificación", zap.Error(err))
+				}
+			}()
+		}
+	}
+	if cfg.Scheduled.QueueURL != "" {
+		scheduledQueue, err := config.GetScheduledQueue(ctx, cfg, logger.Named("scheduled"))
+		if err != nil {
+			logger.Error("Error al crear la cola de eventos programados", zap.Error(err))
+		} else {
+			dispatcher := jobs.NewDispatcher(scheduledQueue, cfg.Scheduled.Workers, logger.Named("scheduled"))
+			scheduled.NewHandler(handler, dg, logger.Named("scheduled")).Register(dispatcher)
+			go dispatcher.Run(ctx)
+		}
+	}
+	if cfg.Remote.Backend != "" {
+		remoteQueue, err := config.GetRemoteQueue(ctx, cfg, logger.Named("remote"))
+		if err != nil {
+			logger.Error("Error al crear la cola de comandos externos", zap.Error(err))
+		} else {
+			dispatcher := jobs.NewDispatcher(remoteQueue, cfg.Remote.Workers, logger.Named("remote"))
+			queueapi.NewConsumer(handler, auditor, logger.Named("remote")).Register(dispatcher)
+			go dispatcher.Run(ctx)
+			defer func() {
+				if err := remoteQueue.Close(); err != nil {
+					logger.Error("Error al cerrar la cola de comandos externos", zap.Error(err))
+				}
+			}()
+		}
+	}
 	commandHandler := discord.NewSlashCommandRouter(cfg.CommandPrefix).
+		Namespace(cfg.CommandNamespace).
 		PlayHandler(handler.PlaySong).
+		PlayFromHandler(handler.PlayFrom).
 		SkipHandler(handler.SkipSong).
-		StopHandler(handler.StopPlaying).
+		SeekCommandHandler(handler.DJOnly(handler.SeekSong)).
+		ReplayHandler(handler.DJOnly(handler.Replay)).
+		JumpHandler(handler.DJOnly(handler.JumpToSong)).
+		LoopHandler(handler.DJOnly(handler.Loop)).
+		PauseHandler(handler.DJOnly(handler.PauseSong)).
+		ResumeHandler(handler.DJOnly(handler.ResumeSong)).
+		StopHandler(handler.DJOnly(handler.StopPlaying)).
 		ListHandler(handler.ListPlaylist).
-		RemoveHandler(handler.RemoveSong).
+		RemoveHandler(handler.DJOnly(handler.RemoveSong)).
+		MoveHandler(handler.DJOnly(handler.MoveSong)).
+		UndoHandler(handler.DJOnly(handler.Undo)).
+		ShuffleHandler(handler.DJOnly(handler.Shuffle)).
+		DedupeHandler(handler.DJOnly(handler.Dedupe)).
+		PartyShuffleHandler(handler.DJOnly(handler.PartyShuffle)).
+		RadioHandler(handler.DJOnly(handler.Radio)).
+		MoveBotHandler(handler.DJOnly(handler.MoveBot)).
 		PlayingNowHandler(handler.GetPlayingSong).
+		AuditHandler(handler.AuditLogCommand).
+		DebugHandler(handler.Debug).
+		QueueExportHandler(handler.ExportQueue).
+		QueueImportHandler(handler.ImportQueue).
+		QueueCreateHandler(handler.CreateQueue).
+		QueueSwitchHandler(handler.DJOnly(handler.SwitchQueue)).
+		QueueListHandler(handler.ListQueues).
+		QueueDeleteHandler(handler.DJOnly(handler.DeleteQueue)).
+		QueueTransferHandler(handler.DJOnly(handler.TransferSong)).
+		PlaylistSaveHandler(handler.SavePlaylist).
+		PlaylistLoadHandler(handler.LoadPlaylist).
+		PlaylistListHandler(handler.ListPlaylists).
+		PlaylistDeleteHandler(handler.DeletePlaylist).
+		PlaylistShareHandler(handler.SharePlaylist).
+		PlaylistImportHandler(handler.ImportPlaylist).
+		PlaylistBrowseHandler(handler.BrowsePlaylists).
+		PlaylistPrefetchHandler(handler.PrefetchPlaylist).
+		SettingsDuplicatesHandler(handler.SetDuplicatePolicy).
+		SettingsExplicitHandler(handler.SetExplicitPolicy).
+		SettingsAutoJoinHandler(handler.SetAutoJoin).
+		SettingsTimezoneHandler(handler.SetTimezone).
+		SettingsVoiceChannelsHandler(handler.SetVoiceChannels).
+		SettingsAntiSpamHandler(handler.SetAntiSpam).
+		SettingsMaxVolumeHandler(handler.SetMaxVolume).
+		SettingsVoteSkipHandler(handler.SetVoteSkip).
+		SettingsDJRoleHandler(handler.SetDJRole).
+		DuplicateSongHandler(handler.ConfirmDuplicateSong).
+		SeekHandler(handler.DJOnlyComponent(handler.SeekButton)).
+		ChapterHandler(handler.DJOnlyComponent(handler.ChapterButton)).
+		IdentifyQueueHandler(handler.IdentifyQueue).
+		QueuePageHandler(handler.QueuePage).
+		TrendingHandler(handler.Trending).
+		TrendingQueueHandler(handler.TrendingQueue).
+		RetrySongHandler(handler.RetrySong).
+		BlocklistAddHandler(handler.BlocklistAdd).
+		BlocklistRemoveHandler(handler.BlocklistRemove).
+		BlocklistListHandler(handler.BlocklistList).
+		BotBanAddHandler(handler.BotBanAdd).
+		BotBanRemoveHandler(handler.BotBanRemove).
+		BotBanListHandler(handler.BotBanList).
+		TriviaStartHandler(handler.StartTrivia).
+		TriviaStopHandler(handler.StopTrivia).
+		MyStatsHandler(handler.MyStats).
+		HistoryHandler(handler.History).
+		HistoryPlayHandler(handler.HistoryPlay).
+		RecommendHandler(handler.Recommend).
+		IdentifyHandler(handler.Identify).
+		ScheduleAddHandler(handler.ScheduleAdd).
+		ScheduleListHandler(handler.ScheduleList).
+		ScheduleRemoveHandler(handler.ScheduleRemove).
+		SpotifyLinkHandler(handler.SpotifyLink).
+		SpotifyUnlinkHandler(handler.SpotifyUnlink).
+		SpotifyListHandler(handler.SpotifyList).
+		YouTubeSubscribeHandler(handler.YouTubeSubscribe).
+		YouTubeUnsubscribeHandler(handler.YouTubeUnsubscribe).
+		YouTubeListHandler(handler.YouTubeList).
+		PartyScheduleHandler(handler.PartySchedule).
+		AlarmSetHandler(handler.AlarmSet).
+		AlarmListHandler(handler.AlarmList).
+		AlarmCancelHandler(handler.AlarmCancel).
+		AliasAddHandler(handler.AliasAdd).
+		AliasRemoveHandler(handler.AliasRemove).
+		AliasListHandler(handler.AliasList).
+		AliasResolver(handler.GuildAliases).
 		AddSongOrPlaylistHandler(handler.AddSongOrPlaylist)
+	handler.WithAliases(commandHandler)
+	if err := plugins.Load(cfg.Plugins.Enabled, plugin.Host{Controller: handler, Logger: logger.Named("plugins")}, commandHandler); err != nil {
+		logger.Error("Error al cargar los plugins", zap.Error(err))
+		return
+	}
 
-	handler.RegisterEventHandlers(dg)
-	dg.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	for _, session := range shards.Sessions() {
+		handler.RegisterEventHandlers(session)
+	}
+	go handler.CheckVoiceChannelsPresence()
+	interactionHandler := func(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		switch i.Type {
 		case discordgo.InteractionMessageComponent:
 			if h, ok := commandHandler.GetComponentHandlers()[i.MessageComponentData().CustomID]; ok {
@@ -98,25 +519,134 @@ func main() {
 		default:
 			if h, ok := commandHandler.GetCommandHandlers()[i.ApplicationCommandData().Name]; ok {
 				h(s, i)
+			} else {
+				commandHandler.HandleAlias(s, i)
 			}
 		}
-		handler.CheckVoiceChannelsPresence()
-	})
-	dg.Identify.Intents = discordgo.IntentsAll
-	err = dg.Open()
+	}
+	interactionHandler = handler.BanMiddleware(interactionHandler)
+	if cfg.GRPC.Enabled {
+		if cfg.GRPC.Token == "" {
+			logger.Error("La API gRPC requiere GRPC_TOKEN; no se inicia")
+		} else if listener, err := net.Listen("tcp", cfg.GRPC.Address); err != nil {
+			logger.Error("Error al escuchar en la dirección de la API gRPC", zap.Error(err))
+		} else {
+			grpcServer := grpcserver.NewServer(handler, cfg.GRPC.Token, logger.Named("grpc"))
+			go func() {
+				if err := grpcServer.Serve(listener); err != nil {
+					logger.Error("Error al iniciar la API gRPC", zap.Error(err))
+				}
+			}()
+			defer grpcServer.Shutdown()
+		}
+	}
+	eventBus := events.NewBus()
+	handler.WithEventPublisher(eventBus)
+	go plugins.Run(ctx, eventBus)
+	historyEvents, cancelHistory := eventBus.Subscribe(256)
+	defer cancelHistory()
+	go history.NewRecorder(historyStore, cfg.History.Retention, logger.Named("history")).Run(ctx, historyEvents, time.Hour)
+	go scheduled.NewScheduler(scheduleStore, scheduleLocation, handler.RunSchedule, logger.Named("schedules")).WithGuildLocations(handler.GuildLocation).Run(ctx, 15*time.Second)
+	go ytwatch.NewWatcher(youtubeSubscriptionStore, youtubeFeeds, handler.NotifyYouTubeUploads, logger.Named("ytwatch")).Run(ctx, cfg.YouTubeWatch.Interval)
+	if spotifyClient != nil {
+		go spotifysync.NewSyncer(spotifyLinkStore, spotifyClient, handler.SyncSpotify, logger.Named("spotify")).Run(ctx, cfg.SpotifySync.Interval)
+	}
+	if cfg.VoiceStatus.Enabled {
+		statusEvents, cancelStatus := eventBus.Subscribe(256)
+		defer cancelStatus()
+		go voicestatus.NewUpdater(voicestatus.NewSessionSetter(dg), cfg.VoiceStatus.MinInterval, cfg.VoiceStatus.ClearDelay, logger.Named("voicestatus")).Run(ctx, statusEvents)
+	}
+	if cfg.Stats.Enabled {
+		statsEvents, cancelStats := eventBus.Subscribe(256)
+		defer cancelStats()
+		go stats.NewRecorder(stats.NewRedisStore(config.GetRedisClient(cfg)), logger.Named("stats")).Run(ctx, statsEvents)
+	}
+	apiServer := httpapi.NewServer(cfg.API.Address, handler, cfg.API.Token, logger.Named("api")).WithEvents(eventBus)
+	if cfg.API.Enabled {
+		if cfg.API.Token == "" {
+			logger.Error("La API HTTP requiere API_TOKEN; no se inicia")
+		} else {
+			go func() {
+				if err := apiServer.Start(); err != nil {
+					logger.Error("Error al iniciar la API HTTP", zap.Error(err))
+				}
+			}()
+			defer func() {
+				if err := apiServer.Shutdown(context.Background()); err != nil {
+					logger.Error("Error al detener la API HTTP", zap.Error(err))
+				}
+			}()
+		}
+	}
+	if cfg.Dashboard.Enabled {
+		dashboardServer := dashboard.NewServer(cfg.Dashboard.Address, dashboard.Config{
+			ClientID:      cfg.Dashboard.ClientID,
+			ClientSecret:  cfg.Dashboard.ClientSecret,
+			RedirectURL:   cfg.Dashboard.RedirectURL,
+			FrontendURL:   cfg.Dashboard.FrontendURL,
+			SessionTTL:    cfg.Dashboard.SessionTTL,
+			SecureCookies: cfg.Dashboard.SecureCookies,
+		}, handler, apiServer.Routes(), logger.Named("dashboard")).WithSettings(handler)
+		go func() {
+			if err := dashboardServer.Start(); err != nil {
+				logger.Error("Error al iniciar el dashboard", zap.Error(err))
+			}
+		}()
+		defer func() {
+			if err := dashboardServer.Shutdown(context.Background()); err != nil {
+				logger.Error("Error al detener el dashboard", zap.Error(err))
+			}
+		}()
+	}
+	var handoff *cluster.HandoffCoordinator
+	if cfg.Cluster.Enabled {
+		instanceID := cfg.Cluster.InstanceID
+		if instanceID == "" {
+			instanceID, _ = os.Hostname()
+		}
+		leaseStore, err := config.GetLeaseStore(cfg)
+		if err != nil {
+			logger.Error("Error al crear el store de leases", zap.Error(err))
+			return
+		}
+		router := cluster.NewGuildRouter(leaseStore, instanceID, cfg.Cluster.LeaseTTL, logger.Named("cluster")).
+			WithOnLost(func(ctx context.Context, guildID string) {
+				// Otra instancia tomó el servidor: se sale del canal de voz para que haya una sola conexión.
+				yieldCtx, cancel := context.WithTimeout(ctx, cfg.Cluster.DrainTimeout)
+				defer cancel()
+				if err := handler.YieldGuild(yieldCtx, guildID); err != nil {
+					logger.Error("Error al dejar el canal de voz del servidor", zap.String("guildID", guildID), zap.Error(err))
+				}
+			})
+		go router.Run(ctx)
+		handler.WithDeferredResume().WithVoiceGate(router)
+		handoff = cluster.NewHandoffCoordinator(cluster.NewRedisHandoffStore(config.GetRedisClient(cfg)), router, handler, cfg.Cluster.HandoffInterval, logger.Named("handoff"))
+		go handoff.Run(ctx)
+		defer func() {
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			router.ReleaseAll(releaseCtx)
+		}()
+		interactionHandler = discord.OwnershipMiddleware(ctx, router)(interactionHandler)
+		logger.Info("Modo cluster activado", zap.String("instanceID", instanceID))
+	}
+	recoverMiddleware := discord.RecoverMiddleware(logger)
+	shards.AddHandler(recoverMiddleware(handler.CorrelationMiddleware(interactionHandler)))
+	err = shards.Open()
 	if err != nil {
 		logger.Error("error al abrir la session de discord", zap.Error(err))
 	}
-	defer func(dg *discordgo.Session) {
-		err := dg.Close()
+	defer func() {
+		err := shards.Close()
 		if err != nil {
 			logger.Error("Hubo un error al cerrar session", zap.Error(err))
 		}
-	}(dg)
+	}()
 	slashCommands := commandHandler.GetSlashCommands()
 	registeredCommands, err := dg.ApplicationCommandBulkOverwrite(dg.State.User.ID, cfg.GuildID, slashCommands)
 	if err != nil {
 		logger.Error("no se pudo realizar el comando de sobrescritura masiva", zap.Error(err))
+		return
 	}
 	if cfg.GuildID != "" {
 		defer func() {
@@ -131,4 +661,56 @@ func main() {
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
+	if handoff != nil {
+		// Se suspende la reproducción antes de cerrar los shards, que se necesitan para salir de los canales de voz.
+		drainCtx, cancel := context.WithTimeout(context.Background(), cfg.Cluster.DrainTimeout)
+		handoff.Drain(drainCtx)
+		cancel()
+	}
+}
+
+// runDevMode corre el reproductor de un servidor ficticio sin conectarse a Discord: las canciones se piden por la
+// entrada estándar y el audio se escribe en un archivo o se reproduce localmente.
+func runDevMode(logger *logging.ZapLogger, metricSet metrics.Set, voiceSend codec.SendConfig) {
+	devCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	realYouTubeClient, err := youtube_provider.NewRealYouTubeClient(cfg.YoutubeApiKey)
+	if err != nil {
+		logger.Error("Error al crear el client de youtube_provider", zap.Error(err))
+		return
+	}
+	youtubeService := youtube_provider.NewYouTubeProvider(cfg.YoutubeApiKey, logger.Named("youtube"), realYouTubeClient)
+	cacheStorage := cache.NewCache(logger.Named("cache"), metricSet.Cache, cache.DefaultCacheConfig, "metadata_cache")
+	audioCache := cache.NewAudioCache(logger.Named("cache"), cache.DefaultCacheConfigAudio, metricSet.Cache, "audio_cache")
+	youtubeFetcher := fetcher.NewYoutubeFetcher(logger.Named("fetcher"), cacheStorage, youtubeService, audioCache, fetcher.NewCommandExecutor()).WithMetrics(metricSet.Fetcher).WithProcessGauge(metricSet.Processes).WithPlaylistResolution(cfg.Lookup.PlaylistMaxSize, cfg.Lookup.PlaylistEager, cfg.Lookup.PlaylistWorkers)
+
+	pause := codec.NewPause()
+	streamer := codec.NewDCAStreamerImpl(logger.Named("dca")).WithSendConfig(voiceSend).WithPause(pause)
+	var gain *codec.Gain
+	if cfg.Voice.Gain {
+		gain = codec.NewGain(cfg.Voice.Fade)
+		streamer.WithGain(gain).WithProcessGauge(metricSet.Processes)
+	}
+	session := devmode.NewLocalSession(cfg.Dev.Output, strings.Fields(cfg.Dev.Player), streamer, logger.Named("devmode"))
+	messenger := devmode.NewMessenger(os.Stdout)
+	songStorage, stateStorage := config.GetPlaylistStore(cfg, devmode.GuildID, logger, file_storage.NewJSONStatePersistent())
+	player := bot.NewGuildPlayer(devCtx, devmode.GuildID, session, songStorage, stateStorage, youtubeFetcher.GetDCAData, messenger, logger.Named("player")).WithSeekableAudio(youtubeFetcher.GetDCADataFrom).WithSlowOpThresholds(cfg.SlowOps).WithMetadataRefresh(youtubeFetcher, cfg.Lookup.MetadataTTL).WithPause(pause)
+	if gain != nil {
+		player.WithGain(gain)
+	}
+	go func() {
+		if err := player.Run(devCtx); err != nil {
+			logger.Error("Error en el reproductor del modo desarrollo", zap.Error(err))
+		}
+	}()
+	defer func() {
+		if err := player.Close(); err != nil {
+			logger.Error("Error al cerrar el reproductor del modo desarrollo", zap.Error(err))
+		}
+	}()
+
+	if err := devmode.NewConsole(player, youtubeFetcher, messenger).Run(devCtx, os.Stdin); err != nil {
+		logger.Error("Error al leer la entrada estándar", zap.Error(err))
+	}
 }
@@ -0,0 +1,118 @@
+package alerting
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/health"
+	"sync"
+	"time"
+)
+
+// Claves de las alertas conocidas. Las alertas con la misma clave se deduplican.
+const (
+	KeyVoiceConnectFailures = "voice_connect_failures"
+	KeyFetcherCircuitOpen   = "fetcher_circuit_open"
+	KeyStoreUnavailable     = "store_unavailable"
+)
+
+// Severity indica la gravedad de una alerta.
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert es una alerta dirigida a los operadores del bot.
+type Alert struct {
+	Key      string   // Key identifica el tipo de alerta y se usa para deduplicar.
+	Title    string   // Title es el título de la alerta.
+	Message  string   // Message describe la condición que disparó la alerta.
+	Severity Severity // Severity es la gravedad de la alerta.
+}
+
+// Alerter define el método para enviar alertas a los operadores.
+type Alerter interface {
+	Alert(alert Alert)
+}
+
+// NopAlerter es un Alerter que descarta todas las alertas. Se usa cuando no hay un destino configurado.
+type NopAlerter struct{}
+
+// Alert no hace nada.
+func (NopAlerter) Alert(Alert) {}
+
+// Tracker define el método para registrar la ocurrencia de un evento que puede disparar una alerta.
+type Tracker interface {
+	Track()
+}
+
+// SpikeTracker dispara una alerta cuando un evento ocurre al menos threshold veces dentro de la ventana indicada.
+type SpikeTracker struct {
+	mu        sync.Mutex
+	alerter   Alerter
+	alert     Alert
+	threshold int
+	window    time.Duration
+	events    []time.Time
+	now       func() time.Time
+}
+
+// NewSpikeTracker crea una nueva instancia de SpikeTracker.
+func NewSpikeTracker(alerter Alerter, alert Alert, threshold int, window time.Duration) *SpikeTracker {
+	return &SpikeTracker{
+		alerter:   alerter,
+		alert:     alert,
+		threshold: threshold,
+		window:    window,
+		now:       time.Now,
+	}
+}
+
+// Track registra una ocurrencia del evento y envía la alerta si se superó el umbral.
+func (t *SpikeTracker) Track() {
+	t.mu.Lock()
+	now := t.now()
+	cutoff := now.Add(-t.window)
+	recent := t.events[:0]
+	for _, event := range t.events {
+		if event.After(cutoff) {
+			recent = append(recent, event)
+		}
+	}
+	t.events = append(recent, now)
+	fire := len(t.events) >= t.threshold
+	if fire {
+		t.events = t.events[:0]
+	}
+	t.mu.Unlock()
+
+	if fire {
+		t.alerter.Alert(t.alert)
+	}
+}
+
+// WatchCheck ejecuta periódicamente la verificación indicada y envía una alerta cada vez que falla,
+// hasta que el contexto se cancele. La deduplicación queda a cargo del Alerter.
+func WatchCheck(ctx context.Context, alerter Alerter, name string, check health.Check, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			err := check(checkCtx)
+			cancel()
+			if err != nil {
+				alerter.Alert(Alert{
+					Key:      KeyStoreUnavailable + ":" + name,
+					Title:    "Store no disponible",
+					Message:  name + ": " + err.Error(),
+					Severity: SeverityCritical,
+				})
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
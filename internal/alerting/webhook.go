@@ -0,0 +1,144 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDedupWindow = 15 * time.Minute
+	defaultMaxPerHour  = 20
+	webhookTimeout     = 5 * time.Second
+)
+
+// webhookPayload es el cuerpo que se envía al webhook de Discord.
+type webhookPayload struct {
+	Username string         `json:"username"`
+	Embeds   []webhookEmbed `json:"embeds"`
+}
+
+type webhookEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// WebhookAlerter envía las alertas a un webhook de Discord configurado por el operador.
+// Las alertas con la misma clave se deduplican dentro de una ventana de tiempo y la cantidad
+// total de alertas por hora está limitada para no inundar el canal.
+type WebhookAlerter struct {
+	mu          sync.Mutex
+	url         string
+	client      *http.Client
+	logger      logging.Logger
+	dedupWindow time.Duration
+	maxPerHour  int
+	lastSent    map[string]time.Time
+	sent        []time.Time
+	now         func() time.Time
+}
+
+// NewWebhookAlerter crea una nueva instancia de WebhookAlerter.
+func NewWebhookAlerter(url string, logger logging.Logger) *WebhookAlerter {
+	return &WebhookAlerter{
+		url:         url,
+		client:      &http.Client{Timeout: webhookTimeout},
+		logger:      logger,
+		dedupWindow: defaultDedupWindow,
+		maxPerHour:  defaultMaxPerHour,
+		lastSent:    make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// WithDedupWindow establece la ventana dentro de la cual se descartan las alertas repetidas.
+func (a *WebhookAlerter) WithDedupWindow(window time.Duration) *WebhookAlerter {
+	a.dedupWindow = window
+	return a
+}
+
+// WithMaxPerHour establece la cantidad máxima de alertas que se envían por hora.
+func (a *WebhookAlerter) WithMaxPerHour(max int) *WebhookAlerter {
+	a.maxPerHour = max
+	return a
+}
+
+// Alert envía la alerta al webhook en segundo plano, salvo que esté duplicada o se haya superado el límite.
+func (a *WebhookAlerter) Alert(alert Alert) {
+	if !a.allow(alert.Key) {
+		return
+	}
+	go func() {
+		if err := a.send(alert); err != nil {
+			a.logger.Error("Error al enviar la alerta al webhook", zap.String("key", alert.Key), zap.Error(err))
+		}
+	}()
+}
+
+// allow decide si la alerta se envía, aplicando la deduplicación y el límite por hora.
+func (a *WebhookAlerter) allow(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if last, ok := a.lastSent[key]; ok && now.Sub(last) < a.dedupWindow {
+		return false
+	}
+
+	cutoff := now.Add(-time.Hour)
+	recent := a.sent[:0]
+	for _, sent := range a.sent {
+		if sent.After(cutoff) {
+			recent = append(recent, sent)
+		}
+	}
+	a.sent = recent
+	if len(a.sent) >= a.maxPerHour {
+		a.logger.Info("Alerta descartada por límite de envíos", zap.String("key", key))
+		return false
+	}
+
+	a.sent = append(a.sent, now)
+	a.lastSent[key] = now
+	return true
+}
+
+func (a *WebhookAlerter) send(alert Alert) error {
+	payload := webhookPayload{
+		Username: "GoMusicBot Alertas",
+		Embeds: []webhookEmbed{{
+			Title:       fmt.Sprintf("🚨 %s", alert.Title),
+			Description: alert.Message,
+			Color:       severityColor(alert.Severity),
+			Timestamp:   a.now().Format(time.RFC3339),
+		}},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Post(a.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("el webhook respondió con estado %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func severityColor(severity Severity) int {
+	if severity == SeverityCritical {
+		return 0xE74C3C
+	}
+	return 0xF1C40F
+}
@@ -0,0 +1,85 @@
+package alerting
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookAlerter_SendsEmbed(t *testing.T) {
+	received := make(chan webhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alerter := NewWebhookAlerter(server.URL, new(MockLogger))
+	alerter.Alert(Alert{Key: KeyVoiceConnectFailures, Title: "Fallas de voz", Message: "5 fallas", Severity: SeverityCritical})
+
+	select {
+	case payload := <-received:
+		if assert.Len(t, payload.Embeds, 1) {
+			assert.Equal(t, "🚨 Fallas de voz", payload.Embeds[0].Title)
+			assert.Equal(t, "5 fallas", payload.Embeds[0].Description)
+			assert.Equal(t, 0xE74C3C, payload.Embeds[0].Color)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("el webhook no recibió la alerta")
+	}
+}
+
+func TestWebhookAlerter_Allow(t *testing.T) {
+	t.Run("deduplica alertas con la misma clave", func(t *testing.T) {
+		now := time.Now()
+		alerter := NewWebhookAlerter("", new(MockLogger)).WithDedupWindow(time.Minute)
+		alerter.now = func() time.Time { return now }
+
+		assert.True(t, alerter.allow("a"))
+		assert.False(t, alerter.allow("a"))
+		assert.True(t, alerter.allow("b"))
+
+		now = now.Add(2 * time.Minute)
+		assert.True(t, alerter.allow("a"))
+	})
+
+	t.Run("limita la cantidad de alertas por hora", func(t *testing.T) {
+		logger := new(MockLogger)
+		logger.On("Info", "Alerta descartada por límite de envíos", mock.Anything).Return()
+		now := time.Now()
+		alerter := NewWebhookAlerter("", logger).WithMaxPerHour(2)
+		alerter.now = func() time.Time { return now }
+
+		assert.True(t, alerter.allow("a"))
+		assert.True(t, alerter.allow("b"))
+		assert.False(t, alerter.allow("c"))
+
+		now = now.Add(time.Hour + time.Second)
+		assert.True(t, alerter.allow("c"))
+		logger.AssertExpectations(t)
+	})
+}
+
+func TestSpikeTracker_Track(t *testing.T) {
+	alert := Alert{Key: KeyVoiceConnectFailures}
+	alerter := new(MockAlerter)
+	alerter.On("Alert", alert).Return().Once()
+	now := time.Now()
+	tracker := NewSpikeTracker(alerter, alert, 3, time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Track()
+	now = now.Add(2 * time.Minute)
+	tracker.Track()
+	tracker.Track()
+	alerter.AssertNotCalled(t, "Alert", mock.Anything)
+
+	tracker.Track()
+	alerter.AssertExpectations(t)
+}
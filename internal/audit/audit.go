@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"time"
+)
+
+// EntryType identifica el origen de una entrada de auditoría.
+type EntryType string
+
+const (
+	// EntryTypeCommand representa la invocación de un comando por parte de un usuario.
+	EntryTypeCommand EntryType = "command"
+	// EntryTypePlayback representa un evento de reproducción generado por el reproductor.
+	EntryTypePlayback EntryType = "playback"
+	// EntryTypeExternal representa un comando que llegó de otro sistema por la cola de comandos.
+	EntryTypeExternal EntryType = "external"
+)
+
+type (
+	// Entry representa un registro de auditoría estructurado.
+	Entry struct {
+		Timestamp time.Time `json:"timestamp"`
+		Type      EntryType `json:"type"`
+		GuildID   string    `json:"guild_id"`
+		UserID    string    `json:"user_id,omitempty"`
+		UserName  string    `json:"user_name,omitempty"`
+		Command   string    `json:"command"`
+		Args      string    `json:"args,omitempty"`
+		Result    string    `json:"result,omitempty"`
+	}
+
+	// Filter define los criterios para consultar entradas de auditoría.
+	// Los campos vacíos no se tienen en cuenta.
+	Filter struct {
+		GuildID string
+		UserID  string
+		Command string
+		Type    EntryType
+		Since   time.Time
+		Limit   int
+	}
+
+	// Store define los métodos para persistir y consultar entradas de auditoría.
+	Store interface {
+		// Append agrega una entrada al almacenamiento.
+		Append(entry Entry) error
+		// Query devuelve las entradas que cumplen con el filtro, de la más reciente a la más antigua.
+		Query(filter Filter) ([]Entry, error)
+		// Purge elimina las entradas anteriores a la fecha indicada y devuelve cuántas se eliminaron.
+		Purge(before time.Time) (int, error)
+	}
+
+	// Recorder define la interfaz que usan los componentes del bot para registrar eventos auditables.
+	Recorder interface {
+		Record(entry Entry)
+	}
+)
+
+// Matches indica si la entrada cumple con el filtro.
+func (f Filter) Matches(entry Entry) bool {
+	if f.GuildID != "" && entry.GuildID != f.GuildID {
+		return false
+	}
+	if f.UserID != "" && entry.UserID != f.UserID {
+		return false
+	}
+	if f.Command != "" && entry.Command != f.Command {
+		return false
+	}
+	if f.Type != "" && entry.Type != f.Type {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// NopRecorder es un Recorder que descarta todas las entradas.
+type NopRecorder struct{}
+
+// Record no hace nada.
+func (NopRecorder) Record(Entry) {}
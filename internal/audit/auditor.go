@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Auditor registra entradas de auditoría en un Store y aplica la política de retención.
+type Auditor struct {
+	store     Store
+	retention time.Duration
+	logger    logging.Logger
+	now       func() time.Time
+}
+
+// NewAuditor crea una nueva instancia de Auditor. Una retención de 0 conserva las entradas indefinidamente.
+func NewAuditor(store Store, retention time.Duration, logger logging.Logger) *Auditor {
+	return &Auditor{
+		store:     store,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Record guarda una entrada de auditoría. Los errores se registran pero no se propagan,
+// para que una falla en la auditoría no interrumpa los comandos.
+func (a *Auditor) Record(entry Entry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = a.now()
+	}
+	if err := a.store.Append(entry); err != nil {
+		a.logger.Error("Error al registrar la entrada de auditoría", zap.String("command", entry.Command), zap.Error(err))
+	}
+}
+
+// Query devuelve las entradas que cumplen con el filtro.
+func (a *Auditor) Query(filter Filter) ([]Entry, error) {
+	return a.store.Query(filter)
+}
+
+// ApplyRetention elimina las entradas más antiguas que el período de retención configurado.
+func (a *Auditor) ApplyRetention() {
+	if a.retention <= 0 {
+		return
+	}
+	purged, err := a.store.Purge(a.now().Add(-a.retention))
+	if err != nil {
+		a.logger.Error("Error al aplicar la retención de auditoría", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		a.logger.Info("Entradas de auditoría expiradas eliminadas", zap.Int("cantidad", purged))
+	}
+}
+
+// StartRetention aplica la política de retención periódicamente hasta que el contexto se cancele.
+func (a *Auditor) StartRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	a.ApplyRetention()
+	for {
+		select {
+		case <-ticker.C:
+			a.ApplyRetention()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
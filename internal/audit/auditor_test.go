@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type failingStore struct {
+	InMemoryStore
+}
+
+func (s *failingStore) Append(Entry) error {
+	return errors.New("disco lleno")
+}
+
+func TestAuditor_RecordSetsTimestamp(t *testing.T) {
+	mockLogger := new(MockLogger)
+	store := NewInMemoryStore()
+	auditor := NewAuditor(store, 0, mockLogger)
+	fixed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	auditor.now = func() time.Time { return fixed }
+
+	auditor.Record(Entry{Type: EntryTypeCommand, Command: "play"})
+
+	entries, err := auditor.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, fixed, entries[0].Timestamp)
+}
+
+func TestAuditor_RecordLogsStoreErrors(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Error", "Error al registrar la entrada de auditoría", mock.AnythingOfType("[]zapcore.Field")).Return()
+	auditor := NewAuditor(&failingStore{}, 0, mockLogger)
+
+	auditor.Record(Entry{Command: "play"})
+
+	mockLogger.AssertExpectations(t)
+}
+
+func TestAuditor_ApplyRetention(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", "Entradas de auditoría expiradas eliminadas", mock.AnythingOfType("[]zapcore.Field")).Return()
+	store := NewInMemoryStore()
+	auditor := NewAuditor(store, 24*time.Hour, mockLogger)
+
+	now := time.Now()
+	auditor.Record(Entry{Timestamp: now.Add(-25 * time.Hour), Command: "viejo"})
+	auditor.Record(Entry{Timestamp: now, Command: "nuevo"})
+
+	auditor.ApplyRetention()
+
+	entries, err := auditor.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "nuevo", entries[0].Command)
+	mockLogger.AssertExpectations(t)
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_AppendAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	store, err := NewFileStore(path)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, store.Append(Entry{Timestamp: now.Add(-2 * time.Minute), Type: EntryTypeCommand, GuildID: "g1", UserID: "u1", Command: "play"}))
+	assert.NoError(t, store.Append(Entry{Timestamp: now.Add(-time.Minute), Type: EntryTypeCommand, GuildID: "g2", UserID: "u1", Command: "skip"}))
+	assert.NoError(t, store.Append(Entry{Timestamp: now, Type: EntryTypePlayback, GuildID: "g1", Command: "song_started"}))
+
+	entries, err := store.Query(Filter{GuildID: "g1"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "song_started", entries[0].Command)
+	assert.Equal(t, "play", entries[1].Command)
+
+	entries, err = store.Query(Filter{UserID: "u1", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "skip", entries[0].Command)
+}
+
+func TestFileStore_Purge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	store, err := NewFileStore(path)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, store.Append(Entry{Timestamp: now.Add(-48 * time.Hour), Command: "old"}))
+	assert.NoError(t, store.Append(Entry{Timestamp: now, Command: "new"}))
+
+	purged, err := store.Purge(now.Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	entries, err := store.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "new", entries[0].Command)
+}
+
+func TestFileStore_QuerySkipsCorruptedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	assert.NoError(t, os.WriteFile(path, []byte("no es json\n{\"command\":\"play\"}\n"), 0644))
+
+	store, err := NewFileStore(path)
+	assert.NoError(t, err)
+
+	entries, err := store.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "play", entries[0].Command)
+}
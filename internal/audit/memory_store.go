@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"sync"
+	"time"
+)
+
+// InMemoryStore implementa Store guardando las entradas en memoria.
+type InMemoryStore struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		entries: make([]Entry, 0),
+	}
+}
+
+// Append agrega una entrada al almacenamiento.
+func (s *InMemoryStore) Append(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Query devuelve las entradas que cumplen con el filtro, de la más reciente a la más antigua.
+func (s *InMemoryStore) Query(filter Filter) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryEntries(s.entries, filter), nil
+}
+
+// Purge elimina las entradas anteriores a la fecha indicada.
+func (s *InMemoryStore) Purge(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.entries[:0]
+	for _, entry := range s.entries {
+		if !entry.Timestamp.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	purged := len(s.entries) - len(kept)
+	s.entries = kept
+	return purged, nil
+}
+
+// queryEntries recorre las entradas desde la más reciente y devuelve las que cumplen con el filtro.
+func queryEntries(entries []Entry, filter Filter) []Entry {
+	result := make([]Entry, 0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !filter.Matches(entries[i]) {
+			continue
+		}
+		result = append(result, entries[i])
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+// s3Timeout es el tiempo máximo para descargar el audio de una canción de S3.
+const s3Timeout = 30 * time.Second
+
+// S3API son las operaciones de S3 que usa S3AudioCache.
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// S3AudioCache implementa AudioCaching sobre una caché local y un bucket de S3 en el que la lambda
+// download_extract_upload_audio deja el audio ya transcodificado a DCA. Get busca primero en la caché local y, si
+// no está, en S3; lo que encuentra en S3 lo guarda en la caché local. Set solo guarda en la caché local: al bucket
+// escribe únicamente la lambda.
+type S3AudioCache struct {
+	local  AudioCaching
+	client S3API
+	bucket string
+	prefix string
+	logger logging.Logger
+}
+
+// NewS3AudioCache crea una caché de audio que lee del bucket las claves {prefix}{sha256 de la URL}.dca.
+func NewS3AudioCache(local AudioCaching, client S3API, bucket, prefix string, logger logging.Logger) *S3AudioCache {
+	return &S3AudioCache{local: local, client: client, bucket: bucket, prefix: prefix, logger: logger}
+}
+
+// Key devuelve la clave de S3 del audio de la URL.
+func (c *S3AudioCache) Key(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return c.prefix + hex.EncodeToString(sum[:]) + ".dca"
+}
+
+func (c *S3AudioCache) Get(url string) ([]byte, bool) {
+	if data, ok := c.local.Get(url); ok {
+		return data, true
+	}
+
+	data, err := c.download(url)
+	if err != nil {
+		var notFound *types.NoSuchKey
+		if !errors.As(err, &notFound) {
+			c.logger.Error("Error al obtener el audio de S3", zap.String("URL", url), zap.Error(err))
+		}
+		return nil, false
+	}
+	c.local.Set(url, data)
+	return data, true
+}
+
+func (c *S3AudioCache) Set(url string, data []byte) {
+	c.local.Set(url, data)
+}
+
+func (c *S3AudioCache) Size() int {
+	return c.local.Size()
+}
+
+func (c *S3AudioCache) download(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3Timeout)
+	defer cancel()
+
+	key := c.Key(url)
+	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer %s de S3: %w", key, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestS3AudioCache_Get(t *testing.T) {
+	const url = "https://www.youtube.com/watch?v=abc"
+
+	t.Run("usa la caché local sin consultar S3", func(t *testing.T) {
+		local := new(MockAudioCaching)
+		client := new(MockS3API)
+		local.On("Get", url).Return([]byte("local"), true)
+
+		c := NewS3AudioCache(local, client, "bucket", "audio/", new(MockLogger))
+		data, ok := c.Get(url)
+
+		assert.True(t, ok)
+		assert.Equal(t, []byte("local"), data)
+		client.AssertNotCalled(t, "GetObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("descarga de S3 y guarda en la caché local", func(t *testing.T) {
+		local := new(MockAudioCaching)
+		client := new(MockS3API)
+		c := NewS3AudioCache(local, client, "bucket", "audio/", new(MockLogger))
+		local.On("Get", url).Return(nil, false)
+		local.On("Set", url, []byte("dca")).Return()
+		client.On("GetObject", mock.Anything, mock.MatchedBy(func(in *s3.GetObjectInput) bool {
+			return *in.Bucket == "bucket" && *in.Key == c.Key(url)
+		})).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("dca"))}, nil)
+
+		data, ok := c.Get(url)
+
+		assert.True(t, ok)
+		assert.Equal(t, []byte("dca"), data)
+		local.AssertExpectations(t)
+	})
+
+	t.Run("no está en S3", func(t *testing.T) {
+		local := new(MockAudioCaching)
+		client := new(MockS3API)
+		logger := new(MockLogger)
+		local.On("Get", url).Return(nil, false)
+		client.On("GetObject", mock.Anything, mock.Anything).Return(nil, &types.NoSuchKey{})
+
+		_, ok := NewS3AudioCache(local, client, "bucket", "audio/", logger).Get(url)
+
+		assert.False(t, ok)
+		logger.AssertNotCalled(t, "Error", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error de S3", func(t *testing.T) {
+		local := new(MockAudioCaching)
+		client := new(MockS3API)
+		logger := new(MockLogger)
+		local.On("Get", url).Return(nil, false)
+		client.On("GetObject", mock.Anything, mock.Anything).Return(nil, errors.New("access denied"))
+		logger.On("Error", "Error al obtener el audio de S3", mock.Anything).Return()
+
+		_, ok := NewS3AudioCache(local, client, "bucket", "audio/", logger).Get(url)
+
+		assert.False(t, ok)
+		logger.AssertExpectations(t)
+	})
+}
+
+func TestS3AudioCache_Key(t *testing.T) {
+	c := NewS3AudioCache(new(MockAudioCaching), new(MockS3API), "bucket", "audio/", new(MockLogger))
+
+	key := c.Key("https://www.youtube.com/watch?v=abc")
+
+	assert.True(t, strings.HasPrefix(key, "audio/"))
+	assert.True(t, strings.HasSuffix(key, ".dca"))
+	assert.Len(t, key, len("audio/")+64+len(".dca"))
+	assert.NotEqual(t, key, c.Key("https://www.youtube.com/watch?v=def"))
+}
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"container/list"
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -48,6 +50,10 @@ func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) With(fields ...zap.Field) {
 	m.Called(fields)
 }
@@ -134,3 +140,34 @@ func (m *MockTimerInterface) Stop() bool {
 	args := m.Called()
 	return args.Bool(0)
 }
+
+// MockAudioCaching es un mock para AudioCaching.
+type MockAudioCaching struct {
+	mock.Mock
+}
+
+func (m *MockAudioCaching) Get(url string) ([]byte, bool) {
+	args := m.Called(url)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Bool(1)
+}
+
+func (m *MockAudioCaching) Set(url string, data []byte) {
+	m.Called(url, data)
+}
+
+func (m *MockAudioCaching) Size() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+// MockS3API es un mock para S3API.
+type MockS3API struct {
+	mock.Mock
+}
+
+func (m *MockS3API) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	args := m.Called(ctx, params)
+	out, _ := args.Get(0).(*s3.GetObjectOutput)
+	return out, args.Error(1)
+}
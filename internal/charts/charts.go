@@ -0,0 +1,119 @@
+// Package charts obtiene los rankings de canciones más escuchadas por país, para /trending.
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAppleURL es la API de los feeds RSS de Apple Music.
+	DefaultAppleURL = "https://rss.applemarketingtools.com/api/v2"
+	// requestTimeout es el tiempo máximo de cada pedido del ranking.
+	requestTimeout = 10 * time.Second
+	// MaxLimit es la cantidad máxima de canciones que devuelve el feed de Apple Music.
+	MaxLimit = 100
+)
+
+var (
+	// ErrInvalidRegion indica que la región no es un código de país de dos letras.
+	ErrInvalidRegion = errors.New("región inválida")
+	// ErrRegionNotFound indica que no hay ranking para esa región.
+	ErrRegionNotFound = errors.New("no hay ranking para esa región")
+
+	regionPattern = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+type (
+	// Entry es una canción del ranking.
+	Entry struct {
+		Rank   int
+		Title  string
+		Artist string
+	}
+
+	// Provider devuelve las canciones más escuchadas de una región.
+	Provider interface {
+		Top(ctx context.Context, region string, limit int) ([]Entry, error)
+	}
+)
+
+// Query devuelve la búsqueda con la que se encuentra la canción en YouTube, como "Artista - Título".
+func (e Entry) Query() string {
+	if e.Artist == "" {
+		return e.Title
+	}
+	return e.Artist + " - " + e.Title
+}
+
+// ParseRegion normaliza el código de país de dos letras (ISO 3166-1), como "AR" o "us".
+func ParseRegion(region string) (string, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if !regionPattern.MatchString(region) {
+		return "", ErrInvalidRegion
+	}
+	return region, nil
+}
+
+// AppleClient lee el ranking de canciones más escuchadas de Apple Music, que no necesita credenciales.
+type AppleClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewAppleClient crea un AppleClient.
+func NewAppleClient() *AppleClient {
+	return &AppleClient{client: &http.Client{Timeout: requestTimeout}, baseURL: DefaultAppleURL}
+}
+
+// appleFeed es la respuesta del feed de canciones más escuchadas.
+type appleFeed struct {
+	Feed struct {
+		Results []struct {
+			Name       string `json:"name"`
+			ArtistName string `json:"artistName"`
+		} `json:"results"`
+	} `json:"feed"`
+}
+
+// Top devuelve las limit canciones más escuchadas de la región, de la primera a la última.
+func (c *AppleClient) Top(ctx context.Context, region string, limit int) ([]Entry, error) {
+	region, err := ParseRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	limit = min(max(limit, 1), MaxLimit)
+	endpoint := fmt.Sprintf("%s/%s/music/most-played/%d/songs.json", c.baseURL, region, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el pedido del ranking: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar el ranking: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrRegionNotFound
+	default:
+		return nil, fmt.Errorf("Apple Music respondió con el estado %d", resp.StatusCode)
+	}
+	var feed appleFeed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("error al leer el ranking: %w", err)
+	}
+	entries := make([]Entry, 0, len(feed.Feed.Results))
+	for i, result := range feed.Feed.Results {
+		entries = append(entries, Entry{Rank: i + 1, Title: result.Name, Artist: result.ArtistName})
+	}
+	return entries, nil
+}
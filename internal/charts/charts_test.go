@@ -0,0 +1,49 @@
+package charts
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRegion(t *testing.T) {
+	region, err := ParseRegion(" AR ")
+	require.NoError(t, err)
+	assert.Equal(t, "ar", region)
+
+	for _, input := range []string{"", "arg", "a1", "es-AR"} {
+		_, err := ParseRegion(input)
+		assert.ErrorIs(t, err, ErrInvalidRegion, input)
+	}
+}
+
+func TestAppleClient_Top(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ar/music/most-played/2/songs.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"feed":{"title":"Top Songs","results":[
+			{"artistName":"Soda Stereo","name":"De música ligera"},
+			{"artistName":"Charly García","name":"Demoliendo hoteles"}
+		]}}`))
+	}))
+	defer server.Close()
+	client := NewAppleClient()
+	client.baseURL = server.URL
+
+	entries, err := client.Top(context.Background(), "AR", 2)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, Entry{Rank: 1, Title: "De música ligera", Artist: "Soda Stereo"}, entries[0])
+	assert.Equal(t, "Charly García - Demoliendo hoteles", entries[1].Query())
+
+	_, err = client.Top(context.Background(), "zz", 2)
+	assert.ErrorIs(t, err, ErrRegionNotFound)
+	_, err = client.Top(context.Background(), "argentina", 2)
+	assert.ErrorIs(t, err, ErrInvalidRegion)
+}
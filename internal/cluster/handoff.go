@@ -0,0 +1,235 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// handoffKey es el hash compartido con los servidores que una instancia dejó para que otra los retome.
+// Cada campo es el ID del servidor y su valor, el ID de la instancia que lo dejó.
+const handoffKey = "gomusicbot:handoff"
+
+// HandoffStore define el almacenamiento compartido de los servidores pendientes de traspaso.
+type HandoffStore interface {
+	// Mark deja el servidor pendiente de traspaso.
+	Mark(ctx context.Context, guildID, instanceID string) error
+	// Pending devuelve los servidores pendientes de traspaso.
+	Pending(ctx context.Context) ([]string, error)
+	// Claim toma el traspaso del servidor. Devuelve false si otra instancia ya lo tomó.
+	Claim(ctx context.Context, guildID string) (bool, error)
+}
+
+// RedisHandoffStore implementa HandoffStore sobre Redis.
+type RedisHandoffStore struct {
+	client redis.Commander
+}
+
+// NewRedisHandoffStore crea un HandoffStore sobre Redis.
+func NewRedisHandoffStore(client redis.Commander) *RedisHandoffStore {
+	return &RedisHandoffStore{client: client}
+}
+
+func (s *RedisHandoffStore) Mark(ctx context.Context, guildID, instanceID string) error {
+	if _, err := s.client.Do(ctx, "HSET", handoffKey, guildID, instanceID); err != nil {
+		return fmt.Errorf("error al marcar el traspaso del servidor %s: %w", guildID, err)
+	}
+	return nil
+}
+
+func (s *RedisHandoffStore) Pending(ctx context.Context) ([]string, error) {
+	guilds, err := redis.Strings(s.client.Do(ctx, "HKEYS", handoffKey))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los traspasos pendientes: %w", err)
+	}
+	return guilds, nil
+}
+
+func (s *RedisHandoffStore) Claim(ctx context.Context, guildID string) (bool, error) {
+	// HDEL es atómico: solo la instancia que borra el campo se queda con el traspaso.
+	deleted, err := redis.Int(s.client.Do(ctx, "HDEL", handoffKey, guildID))
+	if err != nil {
+		return false, fmt.Errorf("error al tomar el traspaso del servidor %s: %w", guildID, err)
+	}
+	return deleted == 1, nil
+}
+
+// InMemoryHandoffStore implementa HandoffStore en memoria. Solo sirve para pruebas.
+type InMemoryHandoffStore struct {
+	mu     sync.Mutex
+	guilds map[string]string
+}
+
+// NewInMemoryHandoffStore crea un HandoffStore en memoria.
+func NewInMemoryHandoffStore() *InMemoryHandoffStore {
+	return &InMemoryHandoffStore{guilds: make(map[string]string)}
+}
+
+func (s *InMemoryHandoffStore) Mark(_ context.Context, guildID, instanceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guilds[guildID] = instanceID
+	return nil
+}
+
+func (s *InMemoryHandoffStore) Pending(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	guilds := make([]string, 0, len(s.guilds))
+	for guildID := range s.guilds {
+		guilds = append(guilds, guildID)
+	}
+	return guilds, nil
+}
+
+func (s *InMemoryHandoffStore) Claim(_ context.Context, guildID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.guilds[guildID]; !ok {
+		return false, nil
+	}
+	delete(s.guilds, guildID)
+	return true, nil
+}
+
+// PlaybackHandoff es el lado del bot que suspende y retoma la reproducción de los servidores.
+type PlaybackHandoff interface {
+	// ActiveGuilds devuelve los servidores con una reproducción en curso.
+	ActiveGuilds() []string
+	// SuspendGuild detiene la reproducción sin perder la lista ni la posición, y sale del canal de voz.
+	SuspendGuild(ctx context.Context, guildID string) error
+	// ResumeGuild vuelve a conectarse al canal de voz y retoma la reproducción desde la posición guardada.
+	ResumeGuild(ctx context.Context, guildID string) error
+	// InterruptedGuilds devuelve los servidores con una reproducción a medias que no está sonando en esta instancia.
+	InterruptedGuilds() []string
+}
+
+// HandoffCoordinator traspasa la reproducción de los servidores entre instancias durante un reinicio escalonado:
+// la instancia que se apaga suspende sus servidores, los marca en el store y libera sus leases; la que queda
+// los toma con el próximo ciclo de Run y retoma la reproducción. Si una instancia se cae sin llegar a traspasar
+// sus servidores, otra los toma cuando vencen sus leases (failover).
+type HandoffCoordinator struct {
+	store      HandoffStore
+	router     *GuildRouter
+	playback   PlaybackHandoff
+	instanceID string
+	interval   time.Duration
+	logger     logging.Logger
+	draining   atomic.Bool
+}
+
+// NewHandoffCoordinator crea un HandoffCoordinator para la instancia del router.
+func NewHandoffCoordinator(store HandoffStore, router *GuildRouter, playback PlaybackHandoff, interval time.Duration, logger logging.Logger) *HandoffCoordinator {
+	return &HandoffCoordinator{
+		store:      store,
+		router:     router,
+		playback:   playback,
+		instanceID: router.instanceID,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run busca periódicamente servidores pendientes de traspaso hasta que se cancele el contexto.
+func (c *HandoffCoordinator) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.pickUp(ctx)
+			c.failover(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pickUp toma los traspasos pendientes de los servidores cuyo lease consigue esta instancia.
+// Si el lease todavía lo tiene la instancia que se está apagando, se reintenta en el próximo ciclo.
+func (c *HandoffCoordinator) pickUp(ctx context.Context) {
+	if c.draining.Load() {
+		return
+	}
+	guilds, err := c.store.Pending(ctx)
+	if err != nil {
+		c.logger.Error("Error al obtener los traspasos pendientes", zap.Error(err))
+		return
+	}
+	for _, guildID := range guilds {
+		if !c.router.Owns(ctx, guildID) {
+			continue
+		}
+		claimed, err := c.store.Claim(ctx, guildID)
+		if err != nil {
+			c.logger.Error("Error al tomar el traspaso del servidor", zap.String("guildID", guildID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := c.playback.ResumeGuild(ctx, guildID); err != nil {
+			c.logger.Error("Error al retomar la reproducción del servidor", zap.String("guildID", guildID), zap.Error(err))
+			continue
+		}
+		c.logger.Info("Reproducción retomada tras el traspaso", zap.String("guildID", guildID))
+	}
+}
+
+// failover retoma las reproducciones interrumpidas de los servidores cuyo lease consigue esta instancia. Mientras
+// la instancia que las reproducía siga viva, renueva el lease y ninguna otra lo consigue; si se cae, el lease vence
+// y lo toma la primera instancia que lo pida.
+func (c *HandoffCoordinator) failover(ctx context.Context) {
+	if c.draining.Load() {
+		return
+	}
+	for _, guildID := range c.playback.InterruptedGuilds() {
+		if !c.router.Owns(ctx, guildID) {
+			continue
+		}
+		// Si el servidor también quedó marcado para traspaso, se toma acá para no retomarlo dos veces.
+		if _, err := c.store.Claim(ctx, guildID); err != nil {
+			c.logger.Error("Error al tomar el traspaso del servidor", zap.String("guildID", guildID), zap.Error(err))
+			continue
+		}
+		if err := c.playback.ResumeGuild(ctx, guildID); err != nil {
+			c.logger.Error("Error al retomar la reproducción del servidor", zap.String("guildID", guildID), zap.Error(err))
+			continue
+		}
+		c.logger.Info("Reproducción retomada tras la caída de otra instancia", zap.String("guildID", guildID))
+	}
+}
+
+// Drain suspende la reproducción de los servidores que atiende esta instancia, los deja marcados para que
+// otra instancia los retome y libera los leases. Se llama al recibir la señal de apagado, antes de cerrar
+// las conexiones con Discord.
+func (c *HandoffCoordinator) Drain(ctx context.Context) {
+	c.draining.Store(true)
+
+	owned := make(map[string]struct{})
+	for _, guildID := range c.router.ownedGuilds() {
+		owned[guildID] = struct{}{}
+	}
+	for _, guildID := range c.playback.ActiveGuilds() {
+		if _, ok := owned[guildID]; !ok {
+			continue
+		}
+		// Se sale del canal de voz antes de marcar el servidor, para que la otra instancia no se una mientras
+		// esta sigue conectada.
+		if err := c.playback.SuspendGuild(ctx, guildID); err != nil {
+			c.logger.Error("Error al suspender la reproducción del servidor", zap.String("guildID", guildID), zap.Error(err))
+		}
+		if err := c.store.Mark(ctx, guildID, c.instanceID); err != nil {
+			c.logger.Error("Error al marcar el traspaso del servidor", zap.String("guildID", guildID), zap.Error(err))
+			continue
+		}
+		c.logger.Info("Servidor marcado para traspaso", zap.String("guildID", guildID))
+	}
+	c.router.ReleaseAll(ctx)
+}
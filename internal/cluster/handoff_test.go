@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestRedisHandoffStore(t *testing.T) {
+	ctx := context.Background()
+	client := new(MockCommander)
+	client.On("Do", []string{"HSET", handoffKey, "1", "instancia"}).Return(int64(1), nil)
+	client.On("Do", []string{"HKEYS", handoffKey}).Return([]interface{}{"1"}, nil)
+	client.On("Do", []string{"HDEL", handoffKey, "1"}).Return(int64(1), nil).Once()
+	client.On("Do", []string{"HDEL", handoffKey, "1"}).Return(int64(0), nil).Once()
+	store := NewRedisHandoffStore(client)
+
+	assert.NoError(t, store.Mark(ctx, "1", "instancia"))
+	pending, err := store.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, pending)
+
+	claimed, err := store.Claim(ctx, "1")
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = store.Claim(ctx, "1")
+	assert.NoError(t, err)
+	assert.False(t, claimed, "solo una instancia puede tomar el traspaso")
+	client.AssertExpectations(t)
+}
+
+func TestHandoffCoordinator(t *testing.T) {
+	ctx := context.Background()
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	leases := NewInMemoryLeaseStore()
+	handoffs := NewInMemoryHandoffStore()
+
+	oldRouter := NewGuildRouter(leases, "vieja", time.Minute, logger)
+	newRouter := NewGuildRouter(leases, "nueva", time.Minute, logger)
+	oldPlayback := new(MockPlaybackHandoff)
+	newPlayback := new(MockPlaybackHandoff)
+	oldCoordinator := NewHandoffCoordinator(handoffs, oldRouter, oldPlayback, time.Second, logger)
+	newCoordinator := NewHandoffCoordinator(handoffs, newRouter, newPlayback, time.Second, logger)
+
+	// Mientras la instancia vieja tenga el lease, la nueva no toma el traspaso.
+	assert.True(t, oldRouter.Owns(ctx, "1"))
+	assert.NoError(t, handoffs.Mark(ctx, "1", "vieja"))
+	newCoordinator.pickUp(ctx)
+	newPlayback.AssertNotCalled(t, "ResumeGuild", "1")
+
+	// La instancia vieja solo traspasa los servidores que atiende y que están reproduciendo.
+	oldPlayback.On("ActiveGuilds").Return([]string{"1", "2"})
+	oldPlayback.On("SuspendGuild", "1").Return(nil).Once()
+	oldCoordinator.Drain(ctx)
+	oldPlayback.AssertExpectations(t)
+	pending, _ := handoffs.Pending(ctx)
+	assert.Equal(t, []string{"1"}, pending)
+
+	// Una instancia que se está apagando no retoma servidores.
+	oldCoordinator.pickUp(ctx)
+	oldPlayback.AssertNotCalled(t, "ResumeGuild", "1")
+
+	newPlayback.On("ResumeGuild", "1").Return(nil).Once()
+	newCoordinator.pickUp(ctx)
+	newPlayback.AssertExpectations(t)
+	assert.True(t, newRouter.Owns(ctx, "1"))
+	pending, _ = handoffs.Pending(ctx)
+	assert.Empty(t, pending)
+}
+
+func TestHandoffCoordinator_Failover(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	leases := NewInMemoryLeaseStore()
+	leases.now = func() time.Time { return now }
+	handoffs := NewInMemoryHandoffStore()
+
+	deadRouter := NewGuildRouter(leases, "caida", time.Minute, logger)
+	router := NewGuildRouter(leases, "viva", time.Minute, logger)
+	playback := new(MockPlaybackHandoff)
+	playback.On("InterruptedGuilds").Return([]string{"1"})
+	coordinator := NewHandoffCoordinator(handoffs, router, playback, time.Second, logger)
+
+	// Mientras la instancia que reproduce renueva el lease, nadie más retoma el servidor.
+	assert.True(t, deadRouter.Owns(ctx, "1"))
+	coordinator.failover(ctx)
+	playback.AssertNotCalled(t, "ResumeGuild", "1")
+
+	// Si se cae, el lease vence y la reproducción la retoma otra instancia.
+	now = now.Add(2 * time.Minute)
+	playback.On("ResumeGuild", "1").Return(nil).Once()
+	coordinator.failover(ctx)
+	playback.AssertExpectations(t)
+	assert.True(t, router.Owns(ctx, "1"))
+	assert.False(t, deadRouter.Owns(ctx, "1"))
+}
@@ -0,0 +1,271 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// serviceAccountDir es donde Kubernetes monta el token, el namespace y la CA de la service account del pod.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// microTimeFormat es el formato de los campos MicroTime de la API de Kubernetes.
+const microTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// KubernetesLeaseStore implementa LeaseStore con objetos Lease (coordination.k8s.io/v1), el mismo mecanismo de
+// leader election que usan los controladores de Kubernetes. Cada clave es un Lease en el namespace del bot; la
+// concurrencia entre instancias la resuelve la API con el resourceVersion de cada objeto. La service account del
+// pod necesita permisos get, create, update y delete sobre leases.
+type KubernetesLeaseStore struct {
+	client  *http.Client
+	baseURL string
+	token   func() (string, error)
+	now     func() time.Time
+}
+
+// NewKubernetesLeaseStore crea un LeaseStore sobre la API de Kubernetes en apiURL. token devuelve el token con
+// el que se autentican las solicitudes; se pide en cada solicitud porque los tokens de las service accounts rotan.
+func NewKubernetesLeaseStore(client *http.Client, apiURL, namespace string, token func() (string, error)) *KubernetesLeaseStore {
+	return &KubernetesLeaseStore{
+		client:  client,
+		baseURL: fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", strings.TrimSuffix(apiURL, "/"), namespace),
+		token:   token,
+		now:     time.Now,
+	}
+}
+
+// NewInClusterLeaseStore crea un LeaseStore con la service account del pod en el que corre el bot. Si namespace
+// está vacío, se usa el namespace del pod.
+func NewInClusterLeaseStore(namespace string) (*KubernetesLeaseStore, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("el bot no está corriendo dentro de Kubernetes")
+	}
+	if namespace == "" {
+		data, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el namespace del pod: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la CA de Kubernetes: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("la CA de Kubernetes es inválida")
+	}
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+	}
+	token := func() (string, error) {
+		data, err := os.ReadFile(filepath.Join(serviceAccountDir, "token"))
+		if err != nil {
+			return "", fmt.Errorf("error al leer el token de la service account: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return NewKubernetesLeaseStore(client, "https://"+net.JoinHostPort(host, port), namespace, token), nil
+}
+
+type (
+	kubernetesLease struct {
+		APIVersion string               `json:"apiVersion"`
+		Kind       string               `json:"kind"`
+		Metadata   kubernetesObjectMeta `json:"metadata"`
+		Spec       kubernetesLeaseSpec  `json:"spec"`
+	}
+
+	kubernetesObjectMeta struct {
+		Name            string `json:"name"`
+		ResourceVersion string `json:"resourceVersion,omitempty"`
+	}
+
+	kubernetesLeaseSpec struct {
+		HolderIdentity       string `json:"holderIdentity,omitempty"`
+		LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+		AcquireTime          string `json:"acquireTime,omitempty"`
+		RenewTime            string `json:"renewTime,omitempty"`
+		LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+	}
+)
+
+// expired indica si el dueño del lease dejó de renovarlo.
+func (l *kubernetesLease) expired(now time.Time) bool {
+	renewTime, err := time.Parse(microTimeFormat, l.Spec.RenewTime)
+	if err != nil {
+		return true
+	}
+	return now.After(renewTime.Add(time.Duration(l.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+// leaseName convierte una clave de lease en un nombre de objeto válido para Kubernetes.
+func leaseName(key string) string {
+	return strings.ToLower(strings.NewReplacer(":", "-", "_", "-").Replace(key))
+}
+
+// leaseDurationSeconds redondea el TTL a segundos, la resolución de los Lease, sin bajar de un segundo.
+func leaseDurationSeconds(ttl time.Duration) int {
+	seconds := int((ttl + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func (s *KubernetesLeaseStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	name := leaseName(key)
+	current, err := s.get(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	now := s.now().UTC().Format(microTimeFormat)
+	if current == nil {
+		return s.write(ctx, http.MethodPost, s.baseURL, &kubernetesLease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   kubernetesObjectMeta{Name: name},
+			Spec: kubernetesLeaseSpec{
+				HolderIdentity:       owner,
+				LeaseDurationSeconds: leaseDurationSeconds(ttl),
+				AcquireTime:          now,
+				RenewTime:            now,
+			},
+		})
+	}
+
+	holder := current.Spec.HolderIdentity
+	if holder != "" && holder != owner && !current.expired(s.now()) {
+		return false, nil
+	}
+	if holder != owner {
+		current.Spec.HolderIdentity = owner
+		current.Spec.AcquireTime = now
+		current.Spec.LeaseTransitions++
+	}
+	current.Spec.LeaseDurationSeconds = leaseDurationSeconds(ttl)
+	current.Spec.RenewTime = now
+	// El PUT lleva el resourceVersion leído: si otra instancia modificó el lease mientras tanto, la API responde 409.
+	return s.write(ctx, http.MethodPut, s.baseURL+"/"+name, current)
+}
+
+func (s *KubernetesLeaseStore) Release(ctx context.Context, key, owner string) error {
+	name := leaseName(key)
+	current, err := s.get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Spec.HolderIdentity != owner {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion":    "v1",
+		"kind":          "DeleteOptions",
+		"preconditions": map[string]string{"resourceVersion": current.Metadata.ResourceVersion},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.baseURL+"/"+name, body)
+	if err != nil {
+		return fmt.Errorf("error al liberar el lease %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound, http.StatusConflict:
+		// Si el lease ya no existe o cambió de dueño, no hay nada que liberar.
+		return nil
+	default:
+		return fmt.Errorf("error al liberar el lease %s: %s", name, responseError(resp))
+	}
+}
+
+// get obtiene el lease; devuelve nil si no existe.
+func (s *KubernetesLeaseStore) get(ctx context.Context, name string) (*kubernetesLease, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.baseURL+"/"+name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener el lease %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var lease kubernetesLease
+		if err := json.NewDecoder(resp.Body).Decode(&lease); err != nil {
+			return nil, fmt.Errorf("error al leer el lease %s: %w", name, err)
+		}
+		return &lease, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("error al obtener el lease %s: %s", name, responseError(resp))
+	}
+}
+
+// write crea o actualiza el lease. Devuelve false si otra instancia se adelantó (409 Conflict).
+func (s *KubernetesLeaseStore) write(ctx context.Context, method, url string, lease *kubernetesLease) (bool, error) {
+	body, err := json.Marshal(lease)
+	if err != nil {
+		return false, err
+	}
+	resp, err := s.do(ctx, method, url, body)
+	if err != nil {
+		return false, fmt.Errorf("error al tomar el lease %s: %w", lease.Metadata.Name, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, fmt.Errorf("error al tomar el lease %s: %s", lease.Metadata.Name, responseError(resp))
+	}
+}
+
+func (s *KubernetesLeaseStore) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	token, err := s.token()
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.client.Do(req)
+}
+
+// responseError describe una respuesta inesperada de la API, con el mensaje del Status que devuelve Kubernetes.
+func responseError(resp *http.Response) string {
+	var status struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&status); err == nil && status.Message != "" {
+		return fmt.Sprintf("%s: %s", resp.Status, status.Message)
+	}
+	return resp.Status
+}
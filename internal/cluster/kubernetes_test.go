@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLeaseAPI simula los endpoints de leases de la API de Kubernetes, con el control de concurrencia
+// por resourceVersion.
+type fakeLeaseAPI struct {
+	mu      sync.Mutex
+	leases  map[string]*kubernetesLease
+	version int
+}
+
+func (f *fakeLeaseAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const prefix = "/apis/coordination.k8s.io/v1/namespaces/bot/leases"
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	current := f.leases[name]
+	switch r.Method {
+	case http.MethodGet:
+		if current == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(current)
+	case http.MethodPost, http.MethodPut:
+		var lease kubernetesLease
+		_ = json.NewDecoder(r.Body).Decode(&lease)
+		if r.Method == http.MethodPost && f.leases[lease.Metadata.Name] != nil ||
+			r.Method == http.MethodPut && (current == nil || current.Metadata.ResourceVersion != lease.Metadata.ResourceVersion) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.version++
+		lease.Metadata.ResourceVersion = strconv.Itoa(f.version)
+		f.leases[lease.Metadata.Name] = &lease
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		var options struct {
+			Preconditions struct {
+				ResourceVersion string `json:"resourceVersion"`
+			} `json:"preconditions"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&options)
+		if current == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if current.Metadata.ResourceVersion != options.Preconditions.ResourceVersion {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(f.leases, name)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func TestKubernetesLeaseStore(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeLeaseAPI{leases: make(map[string]*kubernetesLease)}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	now := time.Now()
+	store := NewKubernetesLeaseStore(server.Client(), server.URL, "bot", func() (string, error) { return "token", nil })
+	store.now = func() time.Time { return now }
+	key := guildLeasePrefix + "1"
+
+	acquired, err := store.Acquire(ctx, key, "a", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	lease := api.leases["gomusicbot-guild-owner-1"]
+	assert.Equal(t, "a", lease.Spec.HolderIdentity)
+	assert.Equal(t, 30, lease.Spec.LeaseDurationSeconds)
+
+	acquired, _ = store.Acquire(ctx, key, "b", 30*time.Second)
+	assert.False(t, acquired, "otra instancia no puede tomar un lease vigente")
+
+	acquired, _ = store.Acquire(ctx, key, "a", 30*time.Second)
+	assert.True(t, acquired, "el dueño puede renovar su lease")
+
+	now = now.Add(time.Minute)
+	acquired, _ = store.Acquire(ctx, key, "b", 30*time.Second)
+	assert.True(t, acquired, "un lease vencido se puede tomar")
+	assert.Equal(t, 1, api.leases["gomusicbot-guild-owner-1"].Spec.LeaseTransitions)
+
+	assert.NoError(t, store.Release(ctx, key, "a"))
+	assert.Contains(t, api.leases, "gomusicbot-guild-owner-1", "solo el dueño puede liberar el lease")
+	assert.NoError(t, store.Release(ctx, key, "b"))
+	assert.NotContains(t, api.leases, "gomusicbot-guild-owner-1")
+}
+
+func TestKubernetesLeaseStore_Conflict(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeLeaseAPI{leases: make(map[string]*kubernetesLease)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Otra instancia renueva el lease entre la lectura y la escritura.
+		if r.Method == http.MethodPut {
+			api.mu.Lock()
+			api.leases["clave"].Metadata.ResourceVersion = "otra"
+			api.mu.Unlock()
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	store := NewKubernetesLeaseStore(server.Client(), server.URL, "bot", func() (string, error) { return "token", nil })
+
+	acquired, err := store.Acquire(ctx, "clave", "a", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = store.Acquire(ctx, "clave", "a", time.Second)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+}
+
+func TestKubernetesLeaseStore_Unauthorized(t *testing.T) {
+	api := &fakeLeaseAPI{leases: make(map[string]*kubernetesLease)}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	store := NewKubernetesLeaseStore(server.Client(), server.URL, "bot", func() (string, error) { return "otro", nil })
+
+	_, err := store.Acquire(context.Background(), "clave", "a", time.Second)
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestLeaseDurationSeconds(t *testing.T) {
+	assert.Equal(t, 1, leaseDurationSeconds(100*time.Millisecond))
+	assert.Equal(t, 30, leaseDurationSeconds(30*time.Second))
+	assert.Equal(t, 31, leaseDurationSeconds(30*time.Second+time.Millisecond))
+}
@@ -0,0 +1,103 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LeaseStore define un almacenamiento de leases: locks con vencimiento que pertenecen a un dueño.
+type LeaseStore interface {
+	// Acquire toma el lease si está libre o lo renueva si ya pertenece al dueño. Devuelve false si lo tiene otro.
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Release libera el lease si pertenece al dueño.
+	Release(ctx context.Context, key, owner string) error
+}
+
+// acquireScript toma o renueva el lease de forma atómica.
+const acquireScript = `
+local current = redis.call('GET', KEYS[1])
+if current == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+if current == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0`
+
+// releaseScript borra el lease solo si sigue perteneciendo al dueño.
+const releaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// RedisLeaseStore implementa LeaseStore sobre Redis, compartido por todas las instancias del bot.
+type RedisLeaseStore struct {
+	client redis.Commander
+}
+
+// NewRedisLeaseStore crea un LeaseStore sobre Redis.
+func NewRedisLeaseStore(client redis.Commander) *RedisLeaseStore {
+	return &RedisLeaseStore{client: client}
+}
+
+func (s *RedisLeaseStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := redis.Int(s.client.Do(ctx, "EVAL", acquireScript, "1", key, owner, strconv.FormatInt(ttl.Milliseconds(), 10)))
+	if err != nil {
+		return false, fmt.Errorf("error al tomar el lease %s: %w", key, err)
+	}
+	return acquired == 1, nil
+}
+
+func (s *RedisLeaseStore) Release(ctx context.Context, key, owner string) error {
+	if _, err := s.client.Do(ctx, "EVAL", releaseScript, "1", key, owner); err != nil {
+		return fmt.Errorf("error al liberar el lease %s: %w", key, err)
+	}
+	return nil
+}
+
+// InMemoryLeaseStore implementa LeaseStore en memoria. Solo sirve para una única instancia y para pruebas.
+type InMemoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]lease
+	now    func() time.Time
+}
+
+type lease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// NewInMemoryLeaseStore crea un LeaseStore en memoria.
+func NewInMemoryLeaseStore() *InMemoryLeaseStore {
+	return &InMemoryLeaseStore{leases: make(map[string]lease), now: time.Now}
+}
+
+func (s *InMemoryLeaseStore) Acquire(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	current, ok := s.leases[key]
+	if ok && current.owner != owner && now.Before(current.expiresAt) {
+		return false, nil
+	}
+	s.leases[key] = lease{owner: owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *InMemoryLeaseStore) Release(_ context.Context, key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.leases[key]; ok && current.owner == owner {
+		delete(s.leases, key)
+	}
+	return nil
+}
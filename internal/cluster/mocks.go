@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"time"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockLeaseStore struct {
+	mock.Mock
+}
+
+func (m *MockLeaseStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	args := m.Called(key, owner, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLeaseStore) Release(ctx context.Context, key, owner string) error {
+	args := m.Called(key, owner)
+	return args.Error(0)
+}
+
+type MockCommander struct {
+	mock.Mock
+}
+
+func (m *MockCommander) Do(ctx context.Context, args ...string) (interface{}, error) {
+	ret := m.Called(args)
+	return ret.Get(0), ret.Error(1)
+}
+
+type MockPlaybackHandoff struct {
+	mock.Mock
+}
+
+func (m *MockPlaybackHandoff) ActiveGuilds() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockPlaybackHandoff) SuspendGuild(ctx context.Context, guildID string) error {
+	args := m.Called(guildID)
+	return args.Error(0)
+}
+
+func (m *MockPlaybackHandoff) ResumeGuild(ctx context.Context, guildID string) error {
+	args := m.Called(guildID)
+	return args.Error(0)
+}
+
+func (m *MockPlaybackHandoff) InterruptedGuilds() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// guildLeasePrefix es el prefijo de las claves de los leases de cada servidor.
+const guildLeasePrefix = "gomusicbot:guild-owner:"
+
+// GuildRouter coordina qué instancia del bot atiende cada servidor cuando corren varias a la vez.
+// La instancia que toma el lease de un servidor lo atiende mientras lo siga renovando; si se apaga
+// (o deja de renovarlo), cualquier otra instancia lo toma con la próxima interacción.
+type GuildRouter struct {
+	store      LeaseStore
+	instanceID string
+	ttl        time.Duration
+	logger     logging.Logger
+	onLost     func(ctx context.Context, guildID string) // onLost es opcional; se llama cuando otra instancia toma un servidor atendido.
+
+	mu    sync.Mutex
+	owned map[string]struct{}
+}
+
+// NewGuildRouter crea un GuildRouter para la instancia indicada.
+func NewGuildRouter(store LeaseStore, instanceID string, ttl time.Duration, logger logging.Logger) *GuildRouter {
+	return &GuildRouter{
+		store:      store,
+		instanceID: instanceID,
+		ttl:        ttl,
+		logger:     logger,
+		owned:      make(map[string]struct{}),
+	}
+}
+
+// WithOnLost establece la función que se llama cuando esta instancia pierde el lease de un servidor que atendía,
+// por ejemplo porque no pudo renovarlo a tiempo y lo tomó otra instancia.
+func (r *GuildRouter) WithOnLost(fn func(ctx context.Context, guildID string)) *GuildRouter {
+	r.onLost = fn
+	return r
+}
+
+// Owns indica si esta instancia atiende el servidor, tomando su lease si está libre.
+// Si el store no responde, se mantiene la decisión anterior para no dejar el servidor sin atender
+// ni atenderlo dos veces.
+func (r *GuildRouter) Owns(ctx context.Context, guildID string) bool {
+	acquired, err := r.store.Acquire(ctx, guildLeasePrefix+guildID, r.instanceID, r.ttl)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.logger.Error("Error al tomar el lease del servidor", zap.String("guildID", guildID), zap.Error(err))
+		_, owned := r.owned[guildID]
+		return owned
+	}
+	if acquired {
+		r.owned[guildID] = struct{}{}
+	} else {
+		delete(r.owned, guildID)
+	}
+	return acquired
+}
+
+// Run renueva los leases de los servidores atendidos hasta que se cancele el contexto.
+func (r *GuildRouter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, guildID := range r.ownedGuilds() {
+				if !r.Owns(ctx, guildID) {
+					r.logger.Warn("Se perdió el lease del servidor", zap.String("guildID", guildID))
+					if r.onLost != nil {
+						r.onLost(ctx, guildID)
+					}
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ReleaseAll libera los leases de todos los servidores atendidos por esta instancia, para que otra
+// instancia los tome sin esperar a que venzan. Se llama al apagar la instancia.
+func (r *GuildRouter) ReleaseAll(ctx context.Context) {
+	for _, guildID := range r.ownedGuilds() {
+		if err := r.store.Release(ctx, guildLeasePrefix+guildID, r.instanceID); err != nil {
+			r.logger.Error("Error al liberar el lease del servidor", zap.String("guildID", guildID), zap.Error(err))
+		}
+		r.mu.Lock()
+		delete(r.owned, guildID)
+		r.mu.Unlock()
+	}
+}
+
+func (r *GuildRouter) ownedGuilds() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	guilds := make([]string, 0, len(r.owned))
+	for guildID := range r.owned {
+		guilds = append(guilds, guildID)
+	}
+	return guilds
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestInMemoryLeaseStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewInMemoryLeaseStore()
+	store.now = func() time.Time { return now }
+
+	acquired, err := store.Acquire(ctx, "guild", "a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, _ = store.Acquire(ctx, "guild", "b", time.Minute)
+	assert.False(t, acquired, "otra instancia no puede tomar un lease vigente")
+
+	acquired, _ = store.Acquire(ctx, "guild", "a", time.Minute)
+	assert.True(t, acquired, "el dueño puede renovar su lease")
+
+	now = now.Add(2 * time.Minute)
+	acquired, _ = store.Acquire(ctx, "guild", "b", time.Minute)
+	assert.True(t, acquired, "un lease vencido se puede tomar")
+
+	assert.NoError(t, store.Release(ctx, "guild", "a"))
+	acquired, _ = store.Acquire(ctx, "guild", "a", time.Minute)
+	assert.False(t, acquired, "solo el dueño puede liberar el lease")
+
+	assert.NoError(t, store.Release(ctx, "guild", "b"))
+	acquired, _ = store.Acquire(ctx, "guild", "a", time.Minute)
+	assert.True(t, acquired)
+}
+
+func TestGuildRouter_Owns(t *testing.T) {
+	ctx := context.Background()
+	logger := new(MockLogger)
+	logger.On("Error", "Error al tomar el lease del servidor", mock.Anything).Return()
+	store := new(MockLeaseStore)
+	store.On("Acquire", guildLeasePrefix+"1", "instancia", time.Minute).Return(true, nil).Once()
+	store.On("Acquire", guildLeasePrefix+"1", "instancia", time.Minute).Return(false, errors.New("redis caído")).Once()
+	store.On("Acquire", guildLeasePrefix+"2", "instancia", time.Minute).Return(false, nil).Once()
+	store.On("Acquire", guildLeasePrefix+"2", "instancia", time.Minute).Return(false, errors.New("redis caído")).Once()
+	store.On("Release", guildLeasePrefix+"1", "instancia").Return(nil).Once()
+	router := NewGuildRouter(store, "instancia", time.Minute, logger)
+
+	assert.True(t, router.Owns(ctx, "1"))
+	assert.True(t, router.Owns(ctx, "1"), "si el store falla se mantiene la decisión anterior")
+	assert.False(t, router.Owns(ctx, "2"))
+	assert.False(t, router.Owns(ctx, "2"))
+
+	router.ReleaseAll(ctx)
+	assert.Empty(t, router.ownedGuilds())
+	store.AssertExpectations(t)
+}
+
+func TestRedisLeaseStore(t *testing.T) {
+	client := new(MockCommander)
+	client.On("Do", []string{"EVAL", acquireScript, "1", "clave", "instancia", "30000"}).Return(int64(1), nil).Once()
+	client.On("Do", []string{"EVAL", acquireScript, "1", "clave", "instancia", "30000"}).Return(int64(0), nil).Once()
+	client.On("Do", []string{"EVAL", releaseScript, "1", "clave", "instancia"}).Return(int64(1), nil).Once()
+	store := NewRedisLeaseStore(client)
+
+	acquired, err := store.Acquire(context.Background(), "clave", "instancia", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = store.Acquire(context.Background(), "clave", "instancia", 30*time.Second)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	assert.NoError(t, store.Release(context.Background(), "clave", "instancia"))
+	client.AssertExpectations(t)
+}
+
+func TestGuildRouter_RunCallsOnLost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := new(MockLogger)
+	logger.On("Warn", "Se perdió el lease del servidor", mock.Anything).Return()
+	store := new(MockLeaseStore)
+	store.On("Acquire", guildLeasePrefix+"1", "instancia", 30*time.Millisecond).Return(true, nil).Once()
+	store.On("Acquire", guildLeasePrefix+"1", "instancia", 30*time.Millisecond).Return(false, nil).Once()
+	lost := make(chan string, 1)
+	router := NewGuildRouter(store, "instancia", 30*time.Millisecond, logger).WithOnLost(func(_ context.Context, guildID string) {
+		lost <- guildID
+	})
+
+	assert.True(t, router.Owns(ctx, "1"))
+	go router.Run(ctx)
+
+	assert.Equal(t, "1", <-lost)
+	store.AssertExpectations(t)
+}
@@ -1,21 +1,87 @@
 package config
 
 import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
+	"github.com/Tomas-vilte/GoMusicBot/internal/cache"
+	"github.com/Tomas-vilte/GoMusicBot/internal/cluster"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/health"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/metrics"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotifysync"
+	"github.com/Tomas-vilte/GoMusicBot/internal/ytwatch"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/file_storage"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/inmemory_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/redis_storage"
 )
 
 type Config struct {
-	DiscordToken  string `required:"true"`
-	GuildID       string
-	CommandPrefix string `required:"true"`
-	YoutubeApiKey string `required:"true"`
-	Store         StoreConfig
+	DiscordToken     string `required:"true"`
+	GuildID          string
+	CommandPrefix    string `required:"true"`
+	CommandNamespace string
+	Owners           []string
+	YoutubeApiKey    string `required:"true"`
+	Store            StoreConfig
+	Audit            AuditConfig
+	Log              logging.Config
+	Sentry           SentryConfig
+	Health           HealthConfig
+	Pprof            PprofConfig
+	Alerting         AlertingConfig
+	SlowOps          logging.SlowOpThresholds
+	Shards           ShardConfig
+	Redis            RedisConfig
+	Cluster          ClusterConfig
+	Lavalink         LavalinkConfig
+	Voice            VoiceConfig
+	Lookup           LookupConfig
+	Circuit          CircuitConfig
+	Downloads        DownloadsConfig
+	YtDlp            YtDlpConfig
+	Outbound         OutboundConfig
+	GRPC             GRPCConfig
+	API              APIConfig
+	Dashboard        DashboardConfig
+	Jobs             JobsConfig
+	Plugins          PluginsConfig
+	Assistants       AssistantsConfig
+	Stats            StatsConfig
+	Scheduled        ScheduledConfig
+	Remote           RemoteConfig
+	Transcode        TranscodeConfig
+	CloudWatch       CloudWatchConfig
+	Metrics          MetricsConfig
+	Dev              DevConfig
+	Playlists        PlaylistsConfig
+	Settings         SettingsConfig
+	History          HistoryConfig
+	Schedules        SchedulesConfig
+	Spotify          SpotifyConfig
+	SpotifySync      SpotifySyncConfig
+	YouTubeWatch     YouTubeWatchConfig
+	Trending         TrendingConfig
+	Presence         PresenceConfig
+	VoiceStatus      VoiceStatusConfig
+	Identify         IdentifyConfig
+	Guilds           GuildsConfig
 }
 
 type StoreConfig struct {
@@ -27,6 +93,405 @@ type FileStoreConfig struct {
 	Dir string `default:"./playlist"`
 }
 
+type AuditConfig struct {
+	Type      string        `default:"memory"`
+	Retention time.Duration `default:"720h"`
+	File      AuditFileConfig
+}
+
+type AuditFileConfig struct {
+	Path string `default:"./audit/audit.log"`
+}
+
+// PlaylistsConfig contiene la configuración de las listas guardadas, que se comparten entre servidores con un código.
+type PlaylistsConfig struct {
+	Type string `default:"memory"`
+	File PlaylistsFileConfig
+}
+
+type PlaylistsFileConfig struct {
+	Path string `default:"./playlists/playlists.json"`
+}
+
+// SettingsConfig contiene la configuración del almacenamiento de la configuración por servidor (/settings).
+type SettingsConfig struct {
+	Type string `default:"memory"`
+	File SettingsFileConfig
+}
+
+type SettingsFileConfig struct {
+	Path string `default:"./settings/settings.json"`
+}
+
+// HistoryConfig contiene la configuración del historial de reproducción, del que salen las estadísticas de /mystats.
+type HistoryConfig struct {
+	Type      string        `default:"memory"`
+	Retention time.Duration `default:"2160h"`
+	File      HistoryFileConfig
+}
+
+type HistoryFileConfig struct {
+	Path string `default:"./history/history.log"`
+}
+
+// SchedulesConfig contiene la configuración de las reproducciones programadas con /schedule. Timezone es la zona
+// horaria en la que se interpretan las expresiones, con el nombre de la base de datos IANA.
+type SchedulesConfig struct {
+	Type     string `default:"memory"`
+	Timezone string `default:"UTC"`
+	File     SchedulesFileConfig
+}
+
+type SchedulesFileConfig struct {
+	Path string `default:"./schedules/schedules.json"`
+}
+
+// SpotifyConfig contiene las credenciales de una aplicación de Spotify, que se usan con el flujo de client
+// credentials para leer canciones, álbumes y listas públicas. MaxTracks es la cantidad máxima de canciones que se
+// cargan de un álbum o una lista pedidos con /play.
+type SpotifyConfig struct {
+	ClientID     string
+	ClientSecret string
+	MaxTracks    int `default:"100"`
+}
+
+// SpotifySyncConfig contiene la configuración de las listas de Spotify vinculadas con /spotify link, que se revisan
+// cada Interval para sumar las canciones nuevas.
+type SpotifySyncConfig struct {
+	Type     string        `default:"memory"`
+	Interval time.Duration `default:"15m"`
+	File     SpotifySyncFileConfig
+}
+
+type SpotifySyncFileConfig struct {
+	Path string `default:"./spotify/links.json"`
+}
+
+// YouTubeWatchConfig contiene la configuración de las suscripciones a canales de YouTube (/youtube subscribe), cuyos
+// feeds se revisan cada Interval para anunciar los videos nuevos.
+type YouTubeWatchConfig struct {
+	Type     string        `default:"memory"`
+	Interval time.Duration `default:"10m"`
+	File     YouTubeWatchFileConfig
+}
+
+type YouTubeWatchFileConfig struct {
+	Path string `default:"./youtube/subscriptions.json"`
+}
+
+// TrendingConfig contiene el ranking que muestra /trending: Region es el código de país que se usa si no se indica
+// otro, y Limit la cantidad de canciones (como mucho 10).
+type TrendingConfig struct {
+	Region string `default:"us"`
+	Limit  int    `default:"10"`
+}
+
+// PresenceConfig contiene qué hace el bot cuando se queda solo en el canal de voz. Con Policy "stop" detiene la
+// reproducción y limpia la lista; con "pause" la pausa y la retoma si alguien vuelve antes de GracePeriod.
+type PresenceConfig struct {
+	Policy      string        `default:"stop"`
+	GracePeriod time.Duration `default:"5m"`
+}
+
+// VoiceStatusConfig contiene la configuración del estado de los canales de voz. Si Enabled es true, el bot pone en el
+// estado del canal la canción que está sonando y lo borra cuando deja de sonar. Cada canal se actualiza como mucho
+// una vez por MinInterval, y al terminar una canción se espera ClearDelay antes de borrar el estado.
+type VoiceStatusConfig struct {
+	Enabled     bool          `default:"false"`
+	MinInterval time.Duration `default:"10s"`
+	ClearDelay  time.Duration `default:"5s"`
+}
+
+// GuildsConfig contiene los servidores en los que puede estar el bot, para instancias privadas. Si Allowed no está
+// vacío, el bot sale de cualquier otro servidor al que lo agreguen, avisando por qué.
+type GuildsConfig struct {
+	Allowed []string
+}
+
+// IdentifyConfig contiene la configuración de /identify, que reconoce canciones con AcoustID. Sin AcoustIDKey, el
+// comando no está habilitado. Fpcalc es el binario de Chromaprint que calcula las huellas acústicas.
+type IdentifyConfig struct {
+	AcoustIDKey string
+	Fpcalc      string `default:"fpcalc"`
+}
+
+// HealthConfig contiene la configuración del servidor de health checks (/healthz y /readyz).
+type HealthConfig struct {
+	Address string `default:":8081"`
+}
+
+// RedisConfig contiene la conexión a Redis, usada por el store "redis" y por el modo cluster. PoolSize es la cantidad
+// máxima de conexiones abiertas a la vez (0 usa 10 por CPU) y TLS cifra las conexiones, como piden los Redis
+// administrados. Username es opcional, para los servidores con usuarios (ACL).
+type RedisConfig struct {
+	Address  string `default:"localhost:6379"`
+	Username string
+	Password string
+	DB       int  `default:"0"`
+	PoolSize int  `default:"0"`
+	TLS      bool `default:"false"`
+}
+
+// ClusterConfig contiene la configuración para correr varias instancias del bot. Si Enabled es true, las instancias
+// se reparten los servidores con leases: solo la dueña del lease de un servidor responde sus interacciones y tiene
+// su conexión de voz. Conviene usarlo junto con el store "redis" para compartir las colas.
+type ClusterConfig struct {
+	Enabled    bool `default:"false"`
+	InstanceID string
+	LeaseTTL   time.Duration `default:"30s"`
+	// LeaseBackend es donde se guardan los leases: "redis" u objetos Lease de "kubernetes".
+	LeaseBackend string `default:"redis"`
+	// KubernetesNamespace es el namespace de los Lease; si está vacío, se usa el del pod.
+	KubernetesNamespace string
+	// HandoffInterval es cada cuánto se buscan servidores que otra instancia dejó al apagarse, para retomar su reproducción.
+	HandoffInterval time.Duration `default:"2s"`
+	// DrainTimeout es el tiempo máximo para suspender la reproducción de los servidores al apagar la instancia.
+	DrainTimeout time.Duration `default:"15s"`
+}
+
+// LavalinkConfig contiene la configuración del backend de audio Lavalink. Si Enabled es true, las búsquedas y la
+// reproducción se delegan al nodo en lugar de descargar y codificar el audio en el bot.
+type LavalinkConfig struct {
+	Enabled  bool   `default:"false"`
+	Address  string `default:"localhost:2333"`
+	Password string `default:"youshallnotpass"`
+	Secure   bool   `default:"false"`
+	// Fade es la duración del fundido al saltar, detener, mover o pausar una canción. Sin Lavalink se usa
+	// VoiceConfig.Fade.
+	Fade time.Duration `default:"500ms"`
+}
+
+// VoiceConfig configura el envío de audio al canal de voz cuando no se usa Lavalink. Timer es la fuente de
+// temporización: "connection" deja el ritmo a la conexión de voz y "ticker" usa un ticker propio que envía
+// FrameBatch frames por tick. SendAhead es la cantidad de frames que se leen por adelantado; si es mayor a 0, un
+// buffer adaptativo acumula entre JitterMin y JitterMax frames (0 usa SendAhead) antes de enviar, y crece cuando
+// el audio se corta. Sirve para eliminar el audio entrecortado en hosts con latencia alta. Gain hace pasar el audio
+// por una etapa de ganancia que aplica el volumen y fundidos de duración Fade al saltar, detener, mover o retomar
+// una canción. Está apagada por defecto porque necesita ffmpeg con libopus y corre dos procesos de ffmpeg por cada
+// servidor que está reproduciendo, que ocupan un lugar de DownloadsConfig mientras suena la canción.
+type VoiceConfig struct {
+	Timer      string        `default:"connection"`
+	FrameBatch int           `default:"1"`
+	SendAhead  int           `default:"0"`
+	JitterMin  int           `default:"2"`
+	JitterMax  int           `default:"0"`
+	Gain       bool          `default:"false"`
+	Fade       time.Duration `default:"500ms"`
+}
+
+// LookupConfig contiene los límites globales de las solicitudes salientes a YouTube. Un RatePerSecond de 0 desactiva
+// el límite de tasa. MetadataTTL es la antigüedad a partir de la cual se revalida la metadata de una canción antes de
+// reproducirla; 0 no la revalida. De las playlists se cargan hasta PlaylistMaxSize canciones y se busca al momento la
+// metadata de las primeras PlaylistEager, con PlaylistWorkers búsquedas a la vez.
+type LookupConfig struct {
+	Workers         int           `default:"4"`
+	RatePerSecond   float64       `default:"5"`
+	Burst           int           `default:"10"`
+	MetadataTTL     time.Duration `default:"6h"`
+	PlaylistMaxSize int           `default:"500"`
+	PlaylistEager   int           `default:"20"`
+	PlaylistWorkers int           `default:"8"`
+}
+
+// DownloadsConfig limita los pipelines de descarga (yt-dlp, ffmpeg y dca) que corren a la vez, en total y por
+// servidor. Las descargas que no entran esperan su turno. Un límite de 0 no limita.
+type DownloadsConfig struct {
+	MaxConcurrent         int `default:"0"`
+	MaxConcurrentPerGuild int `default:"0"`
+}
+
+// YtDlpConfig configura la rotación de clientes de YouTube de yt-dlp. Clients son los clientes a probar en orden
+// (por ejemplo web,android,ios); si está vacío yt-dlp usa los suyos y no se rota. Cuando YouTube bloquea un cliente
+// se deja de usar por BlockCooldown. Los PO tokens de los clientes web se toman de POToken y VisitorData o se generan
+// con TokenCommand, que tiene que imprimir un JSON con poToken y visitorData.
+type YtDlpConfig struct {
+	Clients       []string
+	BlockCooldown time.Duration `default:"10m"`
+	POToken       string
+	VisitorData   string
+	TokenCommand  string
+	TokenTTL      time.Duration `default:"6h"`
+}
+
+// OutboundConfig configura las solicitudes salientes a YouTube. Si IPv6Block tiene un bloque IPv6 (por ejemplo un
+// /64 ruteado al host), cada búsqueda y cada descarga sale desde una dirección distinta del bloque.
+type OutboundConfig struct {
+	IPv6Block string
+}
+
+// CircuitConfig contiene la configuración del circuit breaker que protege las solicitudes a YouTube.
+type CircuitConfig struct {
+	FailureThreshold int           `default:"5"`
+	OpenTimeout      time.Duration `default:"1m"`
+}
+
+// GRPCConfig contiene la configuración de la API gRPC de control de los reproductores. Si Enabled es true,
+// Token es obligatorio: las llamadas sin ese token se rechazan.
+type GRPCConfig struct {
+	Enabled bool   `default:"false"`
+	Address string `default:":50051"`
+	Token   string
+}
+
+// APIConfig contiene la configuración de la API HTTP de control de los reproductores. Si Enabled es true,
+// Token es obligatorio: las solicitudes sin ese token se rechazan.
+type APIConfig struct {
+	Enabled bool   `default:"false"`
+	Address string `default:":8082"`
+	Token   string
+}
+
+// DashboardConfig contiene la configuración del backend del dashboard web. ClientID, ClientSecret y RedirectURL
+// son los de la aplicación OAuth2 de Discord; RedirectURL tiene que apuntar a /auth/callback.
+type DashboardConfig struct {
+	Enabled       bool   `default:"false"`
+	Address       string `default:":8083"`
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	FrontendURL   string        `default:"/"`
+	SessionTTL    time.Duration `default:"168h"`
+	SecureCookies bool          `default:"true"`
+}
+
+// JobsConfig contiene la configuración de la cola de trabajos pesados (búsquedas de /play y transcodificación por
+// adelantado de las próximas canciones). Backend puede ser "memory", "nats" o "sqs"; si está vacío, las búsquedas
+// se procesan dentro del manejo de la interacción. En modo cluster cada instancia tiene que usar su propia cola,
+// ya que los trabajos los procesa la instancia que tiene el reproductor del servidor.
+type JobsConfig struct {
+	Backend       string
+	Workers       int `default:"4"`
+	PrefetchAhead int `default:"2"`
+	QueueSize     int `default:"100"` // QueueSize es la capacidad de la cola "memory".
+	NATS          JobsNATSConfig
+	SQS           JobsSQSConfig
+}
+
+// JobsNATSConfig contiene la conexión a NATS de la cola de trabajos. Los consumidores comparten el queue group,
+// así que cada trabajo lo procesa uno solo.
+type JobsNATSConfig struct {
+	URL     string `default:"nats://localhost:4222"`
+	Subject string `default:"gomusicbot.jobs"`
+	Group   string `default:"gomusicbot-workers"`
+}
+
+// JobsSQSConfig contiene la cola de SQS de los trabajos. Las credenciales y la región se toman de la
+// configuración estándar de AWS (variables de entorno, perfil o rol de la instancia).
+type JobsSQSConfig struct {
+	QueueURL string
+}
+
+// PluginsConfig contiene los plugins que se cargan al iniciar el bot, por nombre. Los plugins se registran al
+// importar su paquete en main; un nombre que no está registrado es un error de configuración.
+type PluginsConfig struct {
+	Enabled []string
+}
+
+// AssistantsConfig contiene los tokens de los bots asistentes: otras aplicaciones de Discord que reproducen en
+// otros canales de voz de un servidor cuando el bot principal ya está ocupado en uno. Cada asistente tiene que ser
+// invitado a los servidores en los que se quiere usar; los comandos siguen siendo los del bot principal.
+type AssistantsConfig struct {
+	Tokens []string
+}
+
+// StatsConfig contiene la configuración de las estadísticas de reproducción. Si Enabled es true, cada canción que
+// empieza a sonar se suma a las estadísticas semanales del servidor en Redis, de donde las lee la lambda del
+// resumen semanal.
+type StatsConfig struct {
+	Enabled bool `default:"false"`
+}
+
+// ScheduledConfig contiene la cola de SQS a la que las reglas de EventBridge envían los eventos programados
+// (reproducciones y anuncios). Si QueueURL está vacío, el bot no consume eventos programados.
+type ScheduledConfig struct {
+	QueueURL string
+	Workers  int `default:"1"`
+}
+
+// RemoteConfig contiene la cola por la que otros sistemas (el sitio web, las lambdas) mandan comandos al bot:
+// agregar canciones, saltar y detener. Backend puede ser "sqs" o "nats"; si está vacío, el bot no consume
+// comandos externos.
+type RemoteConfig struct {
+	Backend string
+	Workers int `default:"2"`
+	NATS    RemoteNATSConfig
+	SQS     JobsSQSConfig
+}
+
+// RemoteNATSConfig contiene la conexión a NATS de la cola de comandos externos.
+type RemoteNATSConfig struct {
+	URL     string `default:"nats://localhost:4222"`
+	Subject string `default:"gomusicbot.commands"`
+	Group   string `default:"gomusicbot-commands"`
+}
+
+// TranscodeConfig contiene la cola de SQS de la lambda download_extract_upload_audio y el bucket en el que deja el
+// audio transcodificado. Si QueueURL está vacío, las canciones las transcodifica el bot.
+type TranscodeConfig struct {
+	QueueURL      string
+	Bucket        string
+	Prefix        string `default:"audio/"`
+	PrefetchAhead int    `default:"2"`
+}
+
+// ShardConfig contiene la configuración del sharding del gateway. Si Count es 0, se usa la cantidad recomendada por Discord.
+type ShardConfig struct {
+	Count int `default:"0"`
+}
+
+// CloudWatchConfig contiene la publicación de métricas en CloudWatch. Si EMF es true, los contadores principales
+// (uso de comandos y errores del fetcher) también se escriben en los logs con Embedded Metric Format; CloudWatch
+// Logs los convierte en métricas en Namespace. Los logs tienen que estar en JSON (LOG_ENCODING=json).
+type CloudWatchConfig struct {
+	EMF       bool   `default:"false"`
+	Namespace string `default:"GoMusicBot"`
+}
+
+// MetricsConfig contiene el backend de métricas. Backend puede ser "prometheus" (expone /metrics en el puerto 8080),
+// "statsd", "datadog" (StatsD con las etiquetas de DogStatsD) o "none".
+type MetricsConfig struct {
+	Backend string `default:"prometheus"`
+	StatsD  StatsDConfig
+}
+
+// StatsDConfig contiene la dirección del servidor StatsD o del agente de Datadog, y el prefijo de las métricas.
+type StatsDConfig struct {
+	Address string `default:"127.0.0.1:8125"`
+	Prefix  string `default:"gomusicbot."`
+}
+
+// DevConfig contiene el modo desarrollo, para probar el reproductor y el fetcher sin un servidor de Discord: el bot
+// no se conecta a Discord, los comandos se leen de la entrada estándar y el audio se escribe como Ogg Opus en
+// Output o, si Player no está vacío, se reproduce con ese comando (por ejemplo, "ffplay -nodisp -autoexit -").
+type DevConfig struct {
+	Enabled bool   `default:"false"`
+	Output  string `default:"devmode.ogg"`
+	Player  string
+}
+
+// PprofConfig contiene la configuración del endpoint de pprof. Solo puede escuchar en localhost.
+type PprofConfig struct {
+	Enabled bool   `default:"false"`
+	Address string `default:"localhost:6060"`
+}
+
+// AlertingConfig contiene la configuración de las alertas a operadores. Si WebhookURL está vacío, las alertas se descartan.
+type AlertingConfig struct {
+	WebhookURL            string
+	DedupWindow           time.Duration `default:"15m"`
+	MaxPerHour            int           `default:"20"`
+	VoiceFailureThreshold int           `default:"5"`
+	VoiceFailureWindow    time.Duration `default:"5m"`
+	StoreCheckInterval    time.Duration `default:"1m"`
+}
+
+// SentryConfig contiene la configuración del reporte de errores a Sentry. Si DSN está vacío, Sentry queda desactivado.
+type SentryConfig struct {
+	DSN         string
+	Environment string  `default:"production"`
+	SampleRate  float64 `default:"1.0"`
+}
+
 func GetPlaylistStore(cfg *Config, guildID string, logger logging.Logger, persistent file_storage.StatePersistent) (store.SongStorage, store.StateStorage) {
 	switch cfg.Store.Type {
 	case "memory":
@@ -42,7 +507,269 @@ func GetPlaylistStore(cfg *Config, guildID string, logger logging.Logger, persis
 		}
 		stateStore := inmemory_storage.NewInmemoryStateStorage(logger)
 		return songStore, stateStore
+	case "redis":
+		client := GetRedisClient(cfg)
+		return redis_storage.NewRedisSongStorage(client, guildID, logger), redis_storage.NewRedisStateStorage(client, guildID, logger)
 	default:
 		panic("tipo de store invalido")
 	}
 }
+
+// GetReadinessChecks devuelve las verificaciones de disponibilidad de los stores configurados.
+func GetReadinessChecks(cfg *Config) map[string]health.Check {
+	checks := make(map[string]health.Check)
+	if cfg.Store.Type == "file" {
+		checks["playlist_store"] = health.DirCheck(cfg.Store.File.Dir)
+	}
+	if cfg.Audit.Type == "file" {
+		checks["audit_store"] = health.FileDirCheck(cfg.Audit.File.Path)
+	}
+	if cfg.Playlists.Type == "file" {
+		checks["saved_playlists_store"] = health.FileDirCheck(cfg.Playlists.File.Path)
+	}
+	if cfg.Settings.Type == "file" {
+		checks["settings_store"] = health.FileDirCheck(cfg.Settings.File.Path)
+	}
+	if cfg.History.Type == "file" {
+		checks["history_store"] = health.FileDirCheck(cfg.History.File.Path)
+	}
+	if cfg.Schedules.Type == "file" {
+		checks["schedules_store"] = health.FileDirCheck(cfg.Schedules.File.Path)
+	}
+	if cfg.SpotifySync.Type == "file" {
+		checks["spotify_sync_store"] = health.FileDirCheck(cfg.SpotifySync.File.Path)
+	}
+	if cfg.YouTubeWatch.Type == "file" {
+		checks["youtube_watch_store"] = health.FileDirCheck(cfg.YouTubeWatch.File.Path)
+	}
+	if cfg.Store.Type == "redis" || cfg.Cluster.Enabled {
+		checks["redis"] = func(ctx context.Context) error {
+			_, err := GetRedisClient(cfg).Do(ctx, "PING")
+			return err
+		}
+	}
+	return checks
+}
+
+var (
+	redisOnce   sync.Once
+	redisClient *redis.Client
+)
+
+// GetRedisClient devuelve el cliente de Redis compartido por los stores y el modo cluster.
+func GetRedisClient(cfg *Config) *redis.Client {
+	redisOnce.Do(func() {
+		redisClient = redis.NewClient(redis.Options{
+			Address:  cfg.Redis.Address,
+			Username: cfg.Redis.Username,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+			PoolSize: cfg.Redis.PoolSize,
+			TLS:      cfg.Redis.TLS,
+		})
+	})
+	return redisClient
+}
+
+// GetJobQueue devuelve la cola de trabajos configurada.
+func GetJobQueue(ctx context.Context, cfg *Config, logger logging.Logger) (jobs.Queue, error) {
+	switch cfg.Jobs.Backend {
+	case "memory":
+		return jobs.NewInMemoryQueue(cfg.Jobs.QueueSize), nil
+	case "nats":
+		return jobs.NewNATSQueue(cfg.Jobs.NATS.URL, cfg.Jobs.NATS.Subject, cfg.Jobs.NATS.Group, logger)
+	case "sqs":
+		if cfg.Jobs.SQS.QueueURL == "" {
+			return nil, fmt.Errorf("la cola de trabajos sqs requiere JOBS_SQS_QUEUEURL")
+		}
+		return newSQSQueue(ctx, cfg.Jobs.SQS.QueueURL, logger)
+	default:
+		return nil, fmt.Errorf("tipo de cola de trabajos inválido: %s", cfg.Jobs.Backend)
+	}
+}
+
+// GetScheduledQueue devuelve la cola de SQS de los eventos programados con EventBridge.
+func GetScheduledQueue(ctx context.Context, cfg *Config, logger logging.Logger) (jobs.Queue, error) {
+	return newSQSQueue(ctx, cfg.Scheduled.QueueURL, logger)
+}
+
+// GetRemoteQueue devuelve la cola de comandos externos configurada.
+func GetRemoteQueue(ctx context.Context, cfg *Config, logger logging.Logger) (jobs.Queue, error) {
+	switch cfg.Remote.Backend {
+	case "nats":
+		return jobs.NewNATSQueue(cfg.Remote.NATS.URL, cfg.Remote.NATS.Subject, cfg.Remote.NATS.Group, logger)
+	case "sqs":
+		if cfg.Remote.SQS.QueueURL == "" {
+			return nil, fmt.Errorf("la cola de comandos sqs requiere REMOTE_SQS_QUEUEURL")
+		}
+		return newSQSQueue(ctx, cfg.Remote.SQS.QueueURL, logger)
+	default:
+		return nil, fmt.Errorf("tipo de cola de comandos inválido: %s", cfg.Remote.Backend)
+	}
+}
+
+// GetTranscodeQueue devuelve la cola de SQS en la que el bot pide la transcodificación de las canciones a la lambda.
+func GetTranscodeQueue(ctx context.Context, cfg *Config, logger logging.Logger) (jobs.Queue, error) {
+	return newSQSQueue(ctx, cfg.Transcode.QueueURL, logger)
+}
+
+// GetS3AudioCache devuelve la caché de audio que lee de S3 lo que transcodifica la lambda, sobre la caché local.
+func GetS3AudioCache(ctx context.Context, cfg *Config, local cache.AudioCaching, logger logging.Logger) (*cache.S3AudioCache, error) {
+	if cfg.Transcode.Bucket == "" {
+		return nil, fmt.Errorf("la transcodificación en la lambda requiere TRANSCODE_BUCKET")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al cargar la configuración de AWS: %w", err)
+	}
+	return cache.NewS3AudioCache(local, s3.NewFromConfig(awsCfg), cfg.Transcode.Bucket, cfg.Transcode.Prefix, logger), nil
+}
+
+// newSQSQueue crea una cola de SQS con la configuración estándar de AWS.
+func newSQSQueue(ctx context.Context, queueURL string, logger logging.Logger) (jobs.Queue, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al cargar la configuración de AWS: %w", err)
+	}
+	return jobs.NewSQSQueue(sqs.NewFromConfig(awsCfg), queueURL, logger), nil
+}
+
+// GetLeaseStore devuelve el almacenamiento de leases del modo cluster.
+func GetLeaseStore(cfg *Config) (cluster.LeaseStore, error) {
+	switch cfg.Cluster.LeaseBackend {
+	case "redis":
+		return cluster.NewRedisLeaseStore(GetRedisClient(cfg)), nil
+	case "kubernetes":
+		return cluster.NewInClusterLeaseStore(cfg.Cluster.KubernetesNamespace)
+	default:
+		return nil, fmt.Errorf("tipo de lease inválido: %s", cfg.Cluster.LeaseBackend)
+	}
+}
+
+// GetAuditStore devuelve el almacenamiento de auditoría configurado.
+func GetAuditStore(cfg *Config) (audit.Store, error) {
+	switch cfg.Audit.Type {
+	case "memory":
+		return audit.NewInMemoryStore(), nil
+	case "file":
+		return audit.NewFileStore(cfg.Audit.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de auditoría inválido: %s", cfg.Audit.Type)
+	}
+}
+
+// GetSavedPlaylistStore devuelve el almacenamiento configurado de las listas guardadas.
+func GetSavedPlaylistStore(cfg *Config) (playlists.Store, error) {
+	switch cfg.Playlists.Type {
+	case "memory":
+		return playlists.NewInMemoryStore(), nil
+	case "file":
+		return playlists.NewFileStore(cfg.Playlists.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de listas guardadas inválido: %s", cfg.Playlists.Type)
+	}
+}
+
+// GetSettingsStore devuelve el almacenamiento configurado de la configuración por servidor.
+func GetSettingsStore(cfg *Config) (settings.Store, error) {
+	switch cfg.Settings.Type {
+	case "memory":
+		return settings.NewInMemoryStore(), nil
+	case "file":
+		return settings.NewFileStore(cfg.Settings.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de configuración inválido: %s", cfg.Settings.Type)
+	}
+}
+
+// GetHistoryStore devuelve el almacenamiento configurado del historial de reproducción.
+func GetHistoryStore(cfg *Config) (history.Store, error) {
+	switch cfg.History.Type {
+	case "memory":
+		return history.NewInMemoryStore(), nil
+	case "file":
+		return history.NewFileStore(cfg.History.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de historial inválido: %s", cfg.History.Type)
+	}
+}
+
+// GetMetrics devuelve las métricas del backend configurado.
+func GetMetrics(cfg *Config) (metrics.Set, error) {
+	switch cfg.Metrics.Backend {
+	case "prometheus":
+		return metrics.NewPrometheusSet(), nil
+	case "statsd", "datadog":
+		recorder, err := metrics.NewStatsDRecorder(cfg.Metrics.StatsD.Address, cfg.Metrics.StatsD.Prefix, cfg.Metrics.Backend == "datadog")
+		if err != nil {
+			return metrics.Set{}, err
+		}
+		return metrics.NewRecorderSet(recorder), nil
+	case "none":
+		return metrics.NewRecorderSet(metrics.NopRecorder{}), nil
+	default:
+		return metrics.Set{}, fmt.Errorf("backend de métricas inválido: %s", cfg.Metrics.Backend)
+	}
+}
+
+// GetVoiceSendConfig devuelve la configuración del envío de audio al canal de voz, o un error si no es válida.
+func GetVoiceSendConfig(cfg *Config) (codec.SendConfig, error) {
+	voice := cfg.Voice
+	switch voice.Timer {
+	case codec.TimerConnection, codec.TimerTicker:
+	default:
+		return codec.SendConfig{}, fmt.Errorf("timer de voz inválido: %s", voice.Timer)
+	}
+	if voice.FrameBatch < 1 {
+		return codec.SendConfig{}, fmt.Errorf("la cantidad de frames por tick tiene que ser al menos 1: %d", voice.FrameBatch)
+	}
+	if voice.SendAhead < 0 || voice.JitterMin < 0 || voice.JitterMax < 0 {
+		return codec.SendConfig{}, fmt.Errorf("los buffers de voz no pueden ser negativos")
+	}
+	if voice.SendAhead > 0 && (voice.JitterMin > voice.SendAhead || voice.JitterMax > voice.SendAhead || (voice.JitterMax > 0 && voice.JitterMin > voice.JitterMax)) {
+		return codec.SendConfig{}, fmt.Errorf("el buffer adaptativo (%d-%d frames) no entra en la lectura por adelantado (%d frames)", voice.JitterMin, voice.JitterMax, voice.SendAhead)
+	}
+	return codec.SendConfig{
+		Timer:      voice.Timer,
+		FrameBatch: voice.FrameBatch,
+		SendAhead:  voice.SendAhead,
+		JitterMin:  voice.JitterMin,
+		JitterMax:  voice.JitterMax,
+	}, nil
+}
+
+// GetScheduleStore devuelve el almacenamiento configurado de las reproducciones programadas.
+func GetScheduleStore(cfg *Config) (scheduled.Store, error) {
+	switch cfg.Schedules.Type {
+	case "memory":
+		return scheduled.NewInMemoryStore(), nil
+	case "file":
+		return scheduled.NewFileStore(cfg.Schedules.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de programaciones inválido: %s", cfg.Schedules.Type)
+	}
+}
+
+// GetSpotifySyncStore devuelve el almacenamiento configurado de las listas de Spotify vinculadas.
+func GetSpotifySyncStore(cfg *Config) (spotifysync.Store, error) {
+	switch cfg.SpotifySync.Type {
+	case "memory":
+		return spotifysync.NewInMemoryStore(), nil
+	case "file":
+		return spotifysync.NewFileStore(cfg.SpotifySync.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de listas de Spotify inválido: %s", cfg.SpotifySync.Type)
+	}
+}
+
+// GetYouTubeWatchStore devuelve el almacenamiento configurado de las suscripciones a canales de YouTube.
+func GetYouTubeWatchStore(cfg *Config) (ytwatch.Store, error) {
+	switch cfg.YouTubeWatch.Type {
+	case "memory":
+		return ytwatch.NewInMemoryStore(), nil
+	case "file":
+		return ytwatch.NewFileStore(cfg.YouTubeWatch.File.Path)
+	default:
+		return nil, fmt.Errorf("tipo de store de suscripciones a canales inválido: %s", cfg.YouTubeWatch.Type)
+	}
+}
@@ -0,0 +1,75 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"time"
+)
+
+var (
+	// ErrPlayerNotFound indica que no hay un reproductor para el servidor pedido.
+	ErrPlayerNotFound = errors.New("no hay un reproductor para el servidor")
+	// ErrNoVoiceChannel indica que no se indicó un canal de voz y el reproductor no tiene uno guardado.
+	ErrNoVoiceChannel = errors.New("no hay un canal de voz donde reproducir")
+	// ErrNoSongsFound indica que la búsqueda no devolvió canciones.
+	ErrNoSongsFound = errors.New("no se encontraron canciones")
+	// ErrInvalidVolume indica que el volumen pedido está fuera del rango permitido.
+	ErrInvalidVolume = fmt.Errorf("el volumen tiene que estar entre %d y %d", MinVolume, MaxVolume)
+	// ErrSongsRejected indica que el servidor no permite agregar las canciones pedidas, por ejemplo porque están bloqueadas.
+	ErrSongsRejected = errors.New("el servidor no permite agregar las canciones")
+	// ErrVolumeNotSupported indica que el backend de audio no permite cambiar el volumen.
+	ErrVolumeNotSupported = errors.New("el backend de audio no permite cambiar el volumen")
+)
+
+// Rango permitido del volumen, en porcentaje del volumen original.
+const (
+	MinVolume = 0
+	MaxVolume = 200
+)
+
+// PlayerStatus resume el estado del reproductor de un servidor.
+type PlayerStatus struct {
+	GuildID     string
+	Playing     bool
+	CurrentSong *voice.PlayedSong
+	QueueLength int
+}
+
+// Queue es la lista de reproducción de un servidor junto con la canción que está sonando.
+type Queue struct {
+	CurrentSong *voice.PlayedSong
+	Songs       []*voice.Song
+}
+
+// EnqueueRequest describe una canción a agregar desde fuera de Discord. Si los canales están vacíos,
+// se usan los que el reproductor tiene guardados.
+type EnqueueRequest struct {
+	GuildID        string
+	Input          string
+	VoiceChannelID string
+	TextChannelID  string
+	RequestedBy    string
+}
+
+// Stats contiene las estadísticas generales del bot.
+type Stats struct {
+	Guilds        int
+	ActivePlayers int
+	QueuedSongs   int
+	Uptime        time.Duration
+	Goroutines    int
+}
+
+// Controller define las operaciones para controlar los reproductores desde fuera de Discord: la API gRPC,
+// la API REST y el dashboard.
+type Controller interface {
+	ListPlayers() []PlayerStatus
+	GetQueue(guildID string) (*Queue, error)
+	Enqueue(ctx context.Context, req EnqueueRequest) ([]*voice.Song, error)
+	Skip(guildID string) error
+	Stop(guildID string) error
+	SetVolume(ctx context.Context, guildID string, volume int) error
+	Stats() Stats
+}
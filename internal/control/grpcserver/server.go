@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/controlpb"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+)
+
+// Server expone el control de los reproductores por gRPC, protegido por un token.
+type Server struct {
+	controlpb.UnimplementedPlayerControlServer
+	controller control.Controller
+	token      string
+	logger     logging.Logger
+	server     *grpc.Server
+}
+
+// NewServer crea el servidor gRPC. Todas las llamadas deben enviar el token en el header "authorization".
+func NewServer(controller control.Controller, token string, logger logging.Logger) *Server {
+	s := &Server{
+		controller: controller,
+		token:      token,
+		logger:     logger,
+	}
+	s.server = grpc.NewServer(grpc.UnaryInterceptor(s.authenticate))
+	controlpb.RegisterPlayerControlServer(s.server, s)
+	return s
+}
+
+// Serve atiende las conexiones del listener hasta que se llame a Shutdown.
+func (s *Server) Serve(listener net.Listener) error {
+	s.logger.Info("API gRPC escuchando", zap.String("address", listener.Addr().String()))
+	return s.server.Serve(listener)
+}
+
+// Shutdown espera a que terminen las llamadas en curso y cierra el servidor.
+func (s *Server) Shutdown() {
+	s.server.GracefulStop()
+}
+
+// authenticate rechaza las llamadas que no envían el token configurado.
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		s.logger.Warn("Llamada gRPC sin un token válido", zap.String("method", info.FullMethod))
+		return nil, status.Error(codes.Unauthenticated, "token inválido")
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) ListPlayers(_ context.Context, _ *controlpb.ListPlayersRequest) (*controlpb.ListPlayersResponse, error) {
+	players := s.controller.ListPlayers()
+	resp := &controlpb.ListPlayersResponse{Players: make([]*controlpb.Player, 0, len(players))}
+	for _, player := range players {
+		resp.Players = append(resp.Players, &controlpb.Player{
+			GuildId:     player.GuildID,
+			Playing:     player.Playing,
+			CurrentSong: toPlayedSong(player.CurrentSong),
+			QueueLength: int32(player.QueueLength),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetQueue(_ context.Context, req *controlpb.GetQueueRequest) (*controlpb.GetQueueResponse, error) {
+	queue, err := s.controller.GetQueue(req.GetGuildId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &controlpb.GetQueueResponse{
+		CurrentSong: toPlayedSong(queue.CurrentSong),
+		Songs:       toSongs(queue.Songs),
+	}, nil
+}
+
+func (s *Server) EnqueueSong(ctx context.Context, req *controlpb.EnqueueSongRequest) (*controlpb.EnqueueSongResponse, error) {
+	if req.GetInput() == "" {
+		return nil, status.Error(codes.InvalidArgument, "falta la canción a agregar")
+	}
+	songs, err := s.controller.Enqueue(ctx, control.EnqueueRequest{
+		GuildID:        req.GetGuildId(),
+		Input:          req.GetInput(),
+		VoiceChannelID: req.GetVoiceChannelId(),
+		TextChannelID:  req.GetTextChannelId(),
+		RequestedBy:    req.GetRequestedBy(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &controlpb.EnqueueSongResponse{Songs: toSongs(songs)}, nil
+}
+
+func (s *Server) Skip(_ context.Context, req *controlpb.SkipRequest) (*controlpb.SkipResponse, error) {
+	if err := s.controller.Skip(req.GetGuildId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &controlpb.SkipResponse{}, nil
+}
+
+func (s *Server) Stop(_ context.Context, req *controlpb.StopRequest) (*controlpb.StopResponse, error) {
+	if err := s.controller.Stop(req.GetGuildId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &controlpb.StopResponse{}, nil
+}
+
+func (s *Server) GetStats(_ context.Context, _ *controlpb.GetStatsRequest) (*controlpb.GetStatsResponse, error) {
+	stats := s.controller.Stats()
+	return &controlpb.GetStatsResponse{
+		Guilds:        int32(stats.Guilds),
+		ActivePlayers: int32(stats.ActivePlayers),
+		QueuedSongs:   int32(stats.QueuedSongs),
+		UptimeSeconds: int64(stats.Uptime.Seconds()),
+		Goroutines:    int32(stats.Goroutines),
+	}, nil
+}
+
+// toStatus traduce los errores del controlador a códigos gRPC.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, control.ErrPlayerNotFound), errors.Is(err, control.ErrNoSongsFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, control.ErrNoVoiceChannel):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, control.ErrSongsRejected):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toSong(song *voice.Song) *controlpb.Song {
+	pb := &controlpb.Song{
+		Title:      song.Title,
+		Url:        song.URL,
+		DurationMs: song.Duration.Milliseconds(),
+	}
+	if song.RequestedBy != nil {
+		pb.RequestedBy = *song.RequestedBy
+	}
+	if song.ThumbnailURL != nil {
+		pb.ThumbnailUrl = *song.ThumbnailURL
+	}
+	return pb
+}
+
+func toSongs(songs []*voice.Song) []*controlpb.Song {
+	pbs := make([]*controlpb.Song, 0, len(songs))
+	for _, song := range songs {
+		pbs = append(pbs, toSong(song))
+	}
+	return pbs
+}
+
+func toPlayedSong(song *voice.PlayedSong) *controlpb.PlayedSong {
+	if song == nil {
+		return nil
+	}
+	return &controlpb.PlayedSong{
+		Song:       toSong(&song.Song),
+		PositionMs: song.Position.Milliseconds(),
+	}
+}
@@ -0,0 +1,100 @@
+package grpcserver
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/controlpb"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, controller control.Controller) controlpb.PlayerControlClient {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(controller, "secreto", logger)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Shutdown)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return controlpb.NewPlayerControlClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	controller := new(MockController)
+	client := newTestClient(t, controller)
+
+	_, err := client.GetStats(context.Background(), &controlpb.GetStatsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetStats(withToken("otro"), &controlpb.GetStatsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	controller.On("Stats").Return(control.Stats{Guilds: 2, ActivePlayers: 1, Uptime: time.Minute})
+	stats, err := client.GetStats(withToken("secreto"), &controlpb.GetStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), stats.Guilds)
+	assert.Equal(t, int32(1), stats.ActivePlayers)
+	assert.Equal(t, int64(60), stats.UptimeSeconds)
+}
+
+func TestServer_Players(t *testing.T) {
+	controller := new(MockController)
+	client := newTestClient(t, controller)
+	ctx := withToken("secreto")
+	requestedBy := "tomas"
+	song := &voice.Song{Title: "Canción", URL: "https://youtu.be/1", Duration: 3 * time.Minute, RequestedBy: &requestedBy}
+
+	controller.On("ListPlayers").Return([]control.PlayerStatus{{GuildID: "1", Playing: true, QueueLength: 3, CurrentSong: &voice.PlayedSong{Song: *song, Position: time.Second}}})
+	players, err := client.ListPlayers(ctx, &controlpb.ListPlayersRequest{})
+	require.NoError(t, err)
+	require.Len(t, players.Players, 1)
+	assert.Equal(t, "1", players.Players[0].GuildId)
+	assert.Equal(t, int64(1000), players.Players[0].CurrentSong.PositionMs)
+	assert.Equal(t, "tomas", players.Players[0].CurrentSong.Song.RequestedBy)
+
+	controller.On("GetQueue", "1").Return(&control.Queue{Songs: []*voice.Song{song}}, nil)
+	controller.On("GetQueue", "2").Return(nil, control.ErrPlayerNotFound)
+	queue, err := client.GetQueue(ctx, &controlpb.GetQueueRequest{GuildId: "1"})
+	require.NoError(t, err)
+	assert.Nil(t, queue.CurrentSong)
+	assert.Equal(t, int64(180000), queue.Songs[0].DurationMs)
+	_, err = client.GetQueue(ctx, &controlpb.GetQueueRequest{GuildId: "2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	controller.On("Enqueue", control.EnqueueRequest{GuildID: "1", Input: "canción", RequestedBy: "api"}).Return([]*voice.Song{song}, nil)
+	enqueued, err := client.EnqueueSong(ctx, &controlpb.EnqueueSongRequest{GuildId: "1", Input: "canción", RequestedBy: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "Canción", enqueued.Songs[0].Title)
+	_, err = client.EnqueueSong(ctx, &controlpb.EnqueueSongRequest{GuildId: "1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	controller.On("Skip", "1").Return(nil)
+	controller.On("Stop", "1").Return(control.ErrNoVoiceChannel)
+	_, err = client.Skip(ctx, &controlpb.SkipRequest{GuildId: "1"})
+	assert.NoError(t, err)
+	_, err = client.Stop(ctx, &controlpb.StopRequest{GuildId: "1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	controller.AssertExpectations(t)
+}
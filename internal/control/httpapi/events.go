@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	// eventBuffer es la cantidad de eventos que se encolan por conexión antes de empezar a descartarlos.
+	eventBuffer = 64
+	// pingInterval es cada cuánto se envía un ping para detectar conexiones caídas.
+	pingInterval = 30 * time.Second
+	// writeTimeout es el tiempo máximo para escribir un mensaje en la conexión.
+	writeTimeout = 10 * time.Second
+)
+
+// EventSubscriber permite suscribirse a los eventos de reproducción.
+type EventSubscriber interface {
+	Subscribe(buffer int) (<-chan events.Event, func())
+}
+
+// upgrader acepta conexiones de cualquier origen: la API se autentica por token, que otra página no puede enviar. El
+// dashboard monta este endpoint detrás de su cookie de sesión y verifica el origen antes de delegarle la conexión.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// streamEvents envía por WebSocket los eventos de reproducción en tiempo real. Con el parámetro guild_id
+// se reciben solo los eventos de ese servidor.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	guildID := r.URL.Query().Get("guild_id")
+
+	// La suscripción se hace antes del handshake para no perder los eventos publicados apenas se conecta el cliente.
+	stream, unsubscribe := s.events.Subscribe(eventBuffer)
+	defer unsubscribe()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Warn("Error al abrir la conexión WebSocket", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// Se leen los mensajes del cliente solo para detectar que cerró la conexión.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.done:
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeTimeout))
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			if guildID != "" && event.GuildID != guildID {
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
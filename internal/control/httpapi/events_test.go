@@ -0,0 +1,45 @@
+package httpapi
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_StreamEvents(t *testing.T) {
+	server, _ := newTestServer()
+	bus := events.NewBus()
+	server.WithEvents(bus)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/api/v1/events?guild_id=1"
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"&access_token=secreto", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	bus.Publish(events.NewQueueEvent(events.TypeQueueChanged, "2", 1))
+	bus.Publish(events.NewSongEvent(events.TypeTrackStarted, "1", &voice.Song{Title: "Canción"}, 0))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var event events.Event
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, events.TypeTrackStarted, event.Type, "solo se reciben los eventos del servidor pedido")
+	assert.Equal(t, "Canción", event.Title)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+}
@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockController struct {
+	mock.Mock
+}
+
+func (m *MockController) ListPlayers() []control.PlayerStatus {
+	args := m.Called()
+	return args.Get(0).([]control.PlayerStatus)
+}
+
+func (m *MockController) GetQueue(guildID string) (*control.Queue, error) {
+	args := m.Called(guildID)
+	queue, _ := args.Get(0).(*control.Queue)
+	return queue, args.Error(1)
+}
+
+func (m *MockController) Enqueue(ctx context.Context, req control.EnqueueRequest) ([]*voice.Song, error) {
+	args := m.Called(req)
+	songs, _ := args.Get(0).([]*voice.Song)
+	return songs, args.Error(1)
+}
+
+func (m *MockController) Skip(guildID string) error {
+	args := m.Called(guildID)
+	return args.Error(0)
+}
+
+func (m *MockController) Stop(guildID string) error {
+	args := m.Called(guildID)
+	return args.Error(0)
+}
+
+func (m *MockController) SetVolume(ctx context.Context, guildID string, volume int) error {
+	args := m.Called(guildID, volume)
+	return args.Error(0)
+}
+
+func (m *MockController) Stats() control.Stats {
+	args := m.Called()
+	return args.Get(0).(control.Stats)
+}
@@ -0,0 +1,313 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// guildsPrefix es el prefijo de los endpoints de cada servidor: /api/v1/guilds/{guildID}/{recurso}.
+const guildsPrefix = "/api/v1/guilds/"
+
+// maxBodySize es el tamaño máximo del cuerpo de las solicitudes.
+const maxBodySize = 64 * 1024
+
+// Server expone una API HTTP autenticada para controlar los reproductores e inspeccionar las colas.
+type Server struct {
+	controller control.Controller
+	token      string
+	logger     logging.Logger
+	events     EventSubscriber // events es opcional; si está configurado, se expone /api/v1/events.
+	server     *http.Server
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewServer crea el servidor HTTP que escucha en la dirección indicada. Todas las solicitudes deben enviar
+// el header "Authorization: Bearer <token>".
+func NewServer(address string, controller control.Controller, token string, logger logging.Logger) *Server {
+	s := &Server{
+		controller: controller,
+		token:      token,
+		logger:     logger,
+		done:       make(chan struct{}),
+	}
+	s.server = &http.Server{
+		Addr:              address,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// WithEvents expone los eventos de reproducción por WebSocket en /api/v1/events.
+func (s *Server) WithEvents(subscriber EventSubscriber) *Server {
+	s.events = subscriber
+	return s
+}
+
+// Handler devuelve el http.Handler con los endpoints de la API, protegidos por el token.
+func (s *Server) Handler() http.Handler {
+	return s.authenticate(s.Routes())
+}
+
+// Routes devuelve los endpoints de la API sin autenticación, para montarlos detrás de otro mecanismo
+// de autenticación (por ejemplo, el login del dashboard).
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/players", s.listPlayers)
+	mux.HandleFunc("/api/v1/stats", s.stats)
+	mux.HandleFunc(guildsPrefix, s.guild)
+	if s.events != nil {
+		mux.HandleFunc("/api/v1/events", s.streamEvents)
+	}
+	return mux
+}
+
+// Start inicia el servidor HTTP. Bloquea hasta que el servidor se cierra.
+func (s *Server) Start() error {
+	s.logger.Info("API HTTP escuchando", zap.String("address", s.server.Addr))
+	s.server.Handler = s.Handler()
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown detiene el servidor HTTP y cierra las conexiones WebSocket abiertas.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.server.Shutdown(ctx)
+}
+
+// authenticate rechaza las solicitudes que no envían el token configurado. Como los navegadores no permiten
+// enviar headers al abrir un WebSocket, el token también se acepta en el parámetro access_token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("access_token")
+		}
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			s.logger.Warn("Solicitud a la API sin un token válido", zap.String("path", r.URL.Path))
+			writeError(w, http.StatusUnauthorized, errors.New("token inválido"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listPlayers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	players := s.controller.ListPlayers()
+	resp := make([]Player, 0, len(players))
+	for _, player := range players {
+		resp = append(resp, Player{
+			GuildID:     player.GuildID,
+			Playing:     player.Playing,
+			CurrentSong: toPlayedSong(player.CurrentSong),
+			QueueLength: player.QueueLength,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	stats := s.controller.Stats()
+	writeJSON(w, http.StatusOK, Stats{
+		Guilds:        stats.Guilds,
+		ActivePlayers: stats.ActivePlayers,
+		QueuedSongs:   stats.QueuedSongs,
+		UptimeSeconds: int64(stats.Uptime.Seconds()),
+		Goroutines:    stats.Goroutines,
+	})
+}
+
+// guild enruta los endpoints de un servidor según el recurso pedido.
+func (s *Server) guild(w http.ResponseWriter, r *http.Request) {
+	guildID, resource, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, guildsPrefix), "/")
+	if !ok || guildID == "" {
+		writeError(w, http.StatusNotFound, errors.New("recurso no encontrado"))
+		return
+	}
+
+	switch resource {
+	case "queue":
+		switch r.Method {
+		case http.MethodGet:
+			s.getQueue(w, guildID)
+		case http.MethodPost:
+			s.enqueue(w, r, guildID)
+		default:
+			allowMethod(w, r, http.MethodGet, http.MethodPost)
+		}
+	case "now-playing":
+		if allowMethod(w, r, http.MethodGet) {
+			s.nowPlaying(w, guildID)
+		}
+	case "skip":
+		if allowMethod(w, r, http.MethodPost) {
+			s.respondNoContent(w, s.controller.Skip(guildID))
+		}
+	case "stop":
+		if allowMethod(w, r, http.MethodPost) {
+			s.respondNoContent(w, s.controller.Stop(guildID))
+		}
+	case "volume":
+		if allowMethod(w, r, http.MethodPut) {
+			s.setVolume(w, r, guildID)
+		}
+	default:
+		writeError(w, http.StatusNotFound, errors.New("recurso no encontrado"))
+	}
+}
+
+func (s *Server) getQueue(w http.ResponseWriter, guildID string) {
+	queue, err := s.controller.GetQueue(guildID)
+	if err != nil {
+		s.writeControlError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Queue{
+		CurrentSong: toPlayedSong(queue.CurrentSong),
+		Songs:       toSongs(queue.Songs),
+	})
+}
+
+func (s *Server) nowPlaying(w http.ResponseWriter, guildID string) {
+	queue, err := s.controller.GetQueue(guildID)
+	if err != nil {
+		s.writeControlError(w, err)
+		return
+	}
+	if queue.CurrentSong == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPlayedSong(queue.CurrentSong))
+}
+
+func (s *Server) enqueue(w http.ResponseWriter, r *http.Request, guildID string) {
+	var req EnqueueRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("cuerpo inválido"))
+		return
+	}
+	if req.Input == "" {
+		writeError(w, http.StatusBadRequest, errors.New("falta la canción a agregar"))
+		return
+	}
+	songs, err := s.controller.Enqueue(r.Context(), control.EnqueueRequest{
+		GuildID:        guildID,
+		Input:          req.Input,
+		VoiceChannelID: req.VoiceChannelID,
+		TextChannelID:  req.TextChannelID,
+		RequestedBy:    req.RequestedBy,
+	})
+	if err != nil {
+		s.writeControlError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toSongs(songs))
+}
+
+func (s *Server) setVolume(w http.ResponseWriter, r *http.Request, guildID string) {
+	var req VolumeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil || req.Volume == nil {
+		writeError(w, http.StatusBadRequest, errors.New("cuerpo inválido"))
+		return
+	}
+	s.respondNoContent(w, s.controller.SetVolume(r.Context(), guildID, *req.Volume))
+}
+
+func (s *Server) respondNoContent(w http.ResponseWriter, err error) {
+	if err != nil {
+		s.writeControlError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeControlError traduce los errores del controlador a códigos HTTP.
+func (s *Server) writeControlError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, control.ErrPlayerNotFound), errors.Is(err, control.ErrNoSongsFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, control.ErrInvalidVolume):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, control.ErrSongsRejected):
+		writeError(w, http.StatusForbidden, err)
+	case errors.Is(err, control.ErrNoVoiceChannel):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, control.ErrVolumeNotSupported):
+		writeError(w, http.StatusNotImplemented, err)
+	default:
+		s.logger.Error("Error en la API HTTP", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// allowMethod responde 405 si el método de la solicitud no es uno de los permitidos.
+func allowMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("método no permitido"))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, Error{Error: err.Error()})
+}
+
+func toSong(song *voice.Song) Song {
+	resp := Song{
+		Title:      song.Title,
+		URL:        song.URL,
+		DurationMs: song.Duration.Milliseconds(),
+	}
+	if song.RequestedBy != nil {
+		resp.RequestedBy = *song.RequestedBy
+	}
+	if song.ThumbnailURL != nil {
+		resp.ThumbnailURL = *song.ThumbnailURL
+	}
+	return resp
+}
+
+func toSongs(songs []*voice.Song) []Song {
+	resp := make([]Song, 0, len(songs))
+	for _, song := range songs {
+		resp = append(resp, toSong(song))
+	}
+	return resp
+}
+
+func toPlayedSong(song *voice.PlayedSong) *PlayedSong {
+	if song == nil {
+		return nil
+	}
+	return &PlayedSong{Song: toSong(&song.Song), PositionMs: song.Position.Milliseconds()}
+}
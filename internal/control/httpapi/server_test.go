@@ -0,0 +1,117 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer() (*Server, *MockController) {
+	logger := new(MockLogger)
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	controller := new(MockController)
+	return NewServer(":0", controller, "secreto", logger), controller
+}
+
+func serve(server *Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secreto")
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Authentication(t *testing.T) {
+	server, _ := newTestServer()
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil)
+	req.Header.Set("Authorization", "Bearer otro")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServer_Queue(t *testing.T) {
+	server, controller := newTestServer()
+	song := &voice.Song{Title: "Canción", URL: "https://youtu.be/1", Duration: time.Minute}
+
+	controller.On("GetQueue", "1").Return(&control.Queue{CurrentSong: &voice.PlayedSong{Song: *song, Position: time.Second}, Songs: []*voice.Song{song}}, nil)
+	controller.On("GetQueue", "2").Return(&control.Queue{}, nil)
+	controller.On("GetQueue", "3").Return(nil, control.ErrPlayerNotFound)
+
+	rec := serve(server, http.MethodGet, "/api/v1/guilds/1/queue", "")
+	var queue Queue
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&queue))
+	assert.Equal(t, int64(1000), queue.CurrentSong.PositionMs)
+	assert.Equal(t, "Canción", queue.Songs[0].Title)
+
+	rec = serve(server, http.MethodGet, "/api/v1/guilds/1/now-playing", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(server, http.MethodGet, "/api/v1/guilds/2/now-playing", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serve(server, http.MethodGet, "/api/v1/guilds/3/queue", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Enqueue(t *testing.T) {
+	server, controller := newTestServer()
+	controller.On("Enqueue", control.EnqueueRequest{GuildID: "1", Input: "canción", VoiceChannelID: "vc"}).Return([]*voice.Song{{Title: "Canción"}}, nil)
+	controller.On("Enqueue", control.EnqueueRequest{GuildID: "2", Input: "canción"}).Return(nil, control.ErrNoVoiceChannel)
+
+	rec := serve(server, http.MethodPost, "/api/v1/guilds/1/queue", `{"input":"canción","voice_channel_id":"vc"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = serve(server, http.MethodPost, "/api/v1/guilds/2/queue", `{"input":"canción"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = serve(server, http.MethodPost, "/api/v1/guilds/1/queue", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = serve(server, http.MethodDelete, "/api/v1/guilds/1/queue", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	controller.AssertExpectations(t)
+}
+
+func TestServer_Controls(t *testing.T) {
+	server, controller := newTestServer()
+	controller.On("Skip", "1").Return(nil)
+	controller.On("Stop", "1").Return(nil)
+	controller.On("SetVolume", "1", 80).Return(nil)
+	controller.On("SetVolume", "1", 500).Return(control.ErrInvalidVolume)
+	controller.On("SetVolume", "2", 80).Return(control.ErrVolumeNotSupported)
+
+	assert.Equal(t, http.StatusNoContent, serve(server, http.MethodPost, "/api/v1/guilds/1/skip", "").Code)
+	assert.Equal(t, http.StatusNoContent, serve(server, http.MethodPost, "/api/v1/guilds/1/stop", "").Code)
+	assert.Equal(t, http.StatusNoContent, serve(server, http.MethodPut, "/api/v1/guilds/1/volume", `{"volume":80}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(server, http.MethodPut, "/api/v1/guilds/1/volume", `{"volume":500}`).Code)
+	assert.Equal(t, http.StatusNotImplemented, serve(server, http.MethodPut, "/api/v1/guilds/2/volume", `{"volume":80}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(server, http.MethodPut, "/api/v1/guilds/1/volume", `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(server, http.MethodGet, "/api/v1/guilds/1/otro", "").Code)
+	controller.AssertExpectations(t)
+}
+
+func TestServer_PlayersAndStats(t *testing.T) {
+	server, controller := newTestServer()
+	controller.On("ListPlayers").Return([]control.PlayerStatus{{GuildID: "1", Playing: true, QueueLength: 2}})
+	controller.On("Stats").Return(control.Stats{Guilds: 1, ActivePlayers: 1, Uptime: time.Hour})
+
+	var players []Player
+	rec := serve(server, http.MethodGet, "/api/v1/players", "")
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&players))
+	assert.Equal(t, []Player{{GuildID: "1", Playing: true, QueueLength: 2}}, players)
+
+	var stats Stats
+	rec = serve(server, http.MethodGet, "/api/v1/stats", "")
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&stats))
+	assert.Equal(t, int64(3600), stats.UptimeSeconds)
+}
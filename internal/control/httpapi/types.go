@@ -0,0 +1,58 @@
+package httpapi
+
+// Song es una canción en las respuestas de la API.
+type Song struct {
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	DurationMs   int64  `json:"duration_ms"`
+	RequestedBy  string `json:"requested_by,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// PlayedSong es la canción que está sonando, con su posición.
+type PlayedSong struct {
+	Song
+	PositionMs int64 `json:"position_ms"`
+}
+
+// Player es el estado del reproductor de un servidor.
+type Player struct {
+	GuildID     string      `json:"guild_id"`
+	Playing     bool        `json:"playing"`
+	CurrentSong *PlayedSong `json:"current_song,omitempty"`
+	QueueLength int         `json:"queue_length"`
+}
+
+// Queue es la lista de reproducción de un servidor.
+type Queue struct {
+	CurrentSong *PlayedSong `json:"current_song,omitempty"`
+	Songs       []Song      `json:"songs"`
+}
+
+// Stats son las estadísticas generales del bot.
+type Stats struct {
+	Guilds        int   `json:"guilds"`
+	ActivePlayers int   `json:"active_players"`
+	QueuedSongs   int   `json:"queued_songs"`
+	UptimeSeconds int64 `json:"uptime_seconds"`
+	Goroutines    int   `json:"goroutines"`
+}
+
+// EnqueueRequest es el cuerpo de POST /api/v1/guilds/{guildID}/queue. Los canales son opcionales: por
+// defecto se usan los últimos del reproductor.
+type EnqueueRequest struct {
+	Input          string `json:"input"`
+	VoiceChannelID string `json:"voice_channel_id,omitempty"`
+	TextChannelID  string `json:"text_channel_id,omitempty"`
+	RequestedBy    string `json:"requested_by,omitempty"`
+}
+
+// VolumeRequest es el cuerpo de PUT /api/v1/guilds/{guildID}/volume.
+type VolumeRequest struct {
+	Volume *int `json:"volume"`
+}
+
+// Error es el cuerpo de las respuestas de error.
+type Error struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,188 @@
+// Package queueapi aplica los comandos que otros sistemas (el sitio web, las lambdas) publican en una cola:
+// agregar canciones, saltar la actual o detener la reproducción de un servidor.
+package queueapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// Tipos de los mensajes de la cola. El tipo va en el campo type del mensaje (o en el detail-type, si el mensaje
+// lo publica EventBridge).
+const (
+	CommandEnqueue = "enqueue"
+	CommandSkip    = "skip"
+	CommandStop    = "stop"
+)
+
+// maxInputLength es el largo máximo de la canción pedida, el mismo que acepta Discord en una opción de texto.
+const maxInputLength = 6000
+
+// errInvalidCommand indica que el mensaje no pasó la validación.
+var errInvalidCommand = errors.New("comando inválido")
+
+type (
+	// EnqueueCommand es el payload de CommandEnqueue. Si los canales están vacíos, se usan los que el reproductor
+	// tiene guardados.
+	EnqueueCommand struct {
+		Input          string `json:"input"`
+		VoiceChannelID string `json:"voice_channel_id,omitempty"`
+		TextChannelID  string `json:"text_channel_id,omitempty"`
+		RequestedBy    string `json:"requested_by,omitempty"`
+		Source         string `json:"source,omitempty"`
+	}
+
+	// PlayerCommand es el payload de CommandSkip y CommandStop.
+	PlayerCommand struct {
+		RequestedBy string `json:"requested_by,omitempty"`
+		Source      string `json:"source,omitempty"`
+	}
+)
+
+// Consumer aplica los comandos de la cola al reproductor del servidor de cada mensaje y deja un registro de
+// auditoría de cada uno, se haya aplicado o no.
+type Consumer struct {
+	controller control.Controller
+	recorder   audit.Recorder
+	logger     logging.Logger
+}
+
+// NewConsumer crea un Consumer.
+func NewConsumer(controller control.Controller, recorder audit.Recorder, logger logging.Logger) *Consumer {
+	return &Consumer{controller: controller, recorder: recorder, logger: logger}
+}
+
+// Register registra los handlers de los comandos en el dispatcher.
+func (c *Consumer) Register(dispatcher *jobs.Dispatcher) {
+	dispatcher.
+		Handle(CommandEnqueue, c.handleEnqueue).
+		Handle(CommandSkip, c.handleSkip).
+		Handle(CommandStop, c.handleStop)
+}
+
+func (c *Consumer) handleEnqueue(ctx context.Context, job *jobs.Job) error {
+	var cmd EnqueueCommand
+	if err := job.Decode(&cmd); err != nil {
+		return c.finish(ctx, job, PlayerCommand{}, "", fmt.Errorf("%w: %v", errInvalidCommand, err))
+	}
+	caller := PlayerCommand{RequestedBy: cmd.RequestedBy, Source: cmd.Source}
+	args := fmt.Sprintf("input=%s", cmd.Input)
+	if err := ValidateEnqueue(job.GuildID, cmd); err != nil {
+		return c.finish(ctx, job, caller, args, err)
+	}
+
+	songs, err := c.controller.Enqueue(ctx, control.EnqueueRequest{
+		GuildID:        job.GuildID,
+		Input:          cmd.Input,
+		VoiceChannelID: cmd.VoiceChannelID,
+		TextChannelID:  cmd.TextChannelID,
+		RequestedBy:    cmd.RequestedBy,
+	})
+	if err == nil {
+		args = fmt.Sprintf("%s songs=%d", args, len(songs))
+	}
+	return c.finish(ctx, job, caller, args, err)
+}
+
+func (c *Consumer) handleSkip(ctx context.Context, job *jobs.Job) error {
+	return c.handlePlayer(ctx, job, c.controller.Skip)
+}
+
+func (c *Consumer) handleStop(ctx context.Context, job *jobs.Job) error {
+	return c.handlePlayer(ctx, job, c.controller.Stop)
+}
+
+// handlePlayer aplica un comando que solo necesita el servidor.
+func (c *Consumer) handlePlayer(ctx context.Context, job *jobs.Job, apply func(guildID string) error) error {
+	var cmd PlayerCommand
+	if len(job.Payload) > 0 {
+		if err := job.Decode(&cmd); err != nil {
+			return c.finish(ctx, job, cmd, "", fmt.Errorf("%w: %v", errInvalidCommand, err))
+		}
+	}
+	if !isSnowflake(job.GuildID) {
+		return c.finish(ctx, job, cmd, "", fmt.Errorf("%w: guild_id inválido", errInvalidCommand))
+	}
+	return c.finish(ctx, job, cmd, "", apply(job.GuildID))
+}
+
+// finish registra el resultado del comando en la auditoría y en los logs. Los errores que no se resuelven
+// reintentando (validación, servidor sin reproductor, búsqueda sin resultados) descartan el mensaje; el resto
+// se devuelve para que la cola lo reentregue.
+func (c *Consumer) finish(ctx context.Context, job *jobs.Job, caller PlayerCommand, args string, err error) error {
+	if caller.Source != "" {
+		args = strings.TrimSpace(fmt.Sprintf("%s source=%s", args, caller.Source))
+	}
+	result := "ok"
+	if err != nil {
+		result = fmt.Sprintf("error: %v", err)
+	}
+	c.recorder.Record(audit.Entry{
+		Type:     audit.EntryTypeExternal,
+		GuildID:  job.GuildID,
+		UserName: caller.RequestedBy,
+		Command:  job.Type,
+		Args:     args,
+		Result:   result,
+	})
+
+	logger := logging.FromContext(ctx, c.logger)
+	fields := []zap.Field{zap.String("command", job.Type), zap.String("guildID", job.GuildID), zap.String("source", caller.Source)}
+	switch {
+	case err == nil:
+		logger.Info("Se aplicó un comando externo", fields...)
+		return nil
+	case permanent(err):
+		logger.Warn("Se descartó un comando externo", append(fields, zap.Error(err))...)
+		return nil
+	default:
+		return fmt.Errorf("error al aplicar el comando %s: %w", job.Type, err)
+	}
+}
+
+// ValidateEnqueue valida el servidor, los canales y la canción pedida. La usan también quienes publican los
+// comandos, para rechazar un pedido inválido antes de encolarlo.
+func ValidateEnqueue(guildID string, cmd EnqueueCommand) error {
+	switch {
+	case !isSnowflake(guildID):
+		return fmt.Errorf("%w: guild_id inválido", errInvalidCommand)
+	case strings.TrimSpace(cmd.Input) == "":
+		return fmt.Errorf("%w: falta la canción a agregar", errInvalidCommand)
+	case len(cmd.Input) > maxInputLength:
+		return fmt.Errorf("%w: la canción pedida supera los %d caracteres", errInvalidCommand, maxInputLength)
+	case cmd.VoiceChannelID != "" && !isSnowflake(cmd.VoiceChannelID):
+		return fmt.Errorf("%w: voice_channel_id inválido", errInvalidCommand)
+	case cmd.TextChannelID != "" && !isSnowflake(cmd.TextChannelID):
+		return fmt.Errorf("%w: text_channel_id inválido", errInvalidCommand)
+	}
+	return nil
+}
+
+// permanent indica si el error no se resuelve volviendo a procesar el mensaje.
+func permanent(err error) bool {
+	return errors.Is(err, errInvalidCommand) ||
+		errors.Is(err, control.ErrPlayerNotFound) ||
+		errors.Is(err, control.ErrNoVoiceChannel) ||
+		errors.Is(err, control.ErrNoSongsFound) ||
+		errors.Is(err, control.ErrSongsRejected)
+}
+
+// isSnowflake indica si id tiene el formato de un ID de Discord.
+func isSnowflake(id string) bool {
+	if len(id) < 15 || len(id) > 20 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package queueapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+const guildID = "123456789012345678"
+
+func newJob(t *testing.T, command, guildID string, payload interface{}) *jobs.Job {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	return &jobs.Job{Type: command, GuildID: guildID, Payload: data}
+}
+
+func TestConsumer_Enqueue(t *testing.T) {
+	controller := new(MockController)
+	recorder := new(MockRecorder)
+	logger := new(MockLogger)
+	logger.On("Info", "Se aplicó un comando externo", mock.Anything).Return()
+	controller.On("Enqueue", control.EnqueueRequest{GuildID: guildID, Input: "lofi", RequestedBy: "tomas"}).
+		Return([]*voice.Song{{Title: "lofi hip hop radio"}}, nil)
+	recorder.On("Record", audit.Entry{
+		Type:     audit.EntryTypeExternal,
+		GuildID:  guildID,
+		UserName: "tomas",
+		Command:  CommandEnqueue,
+		Args:     "input=lofi songs=1 source=web",
+		Result:   "ok",
+	}).Return()
+
+	err := NewConsumer(controller, recorder, logger).handleEnqueue(context.Background(),
+		newJob(t, CommandEnqueue, guildID, EnqueueCommand{Input: "lofi", RequestedBy: "tomas", Source: "web"}))
+
+	assert.NoError(t, err)
+	controller.AssertExpectations(t)
+	recorder.AssertExpectations(t)
+}
+
+func TestConsumer_EnqueueValidation(t *testing.T) {
+	tests := map[string]struct {
+		guildID string
+		cmd     EnqueueCommand
+	}{
+		"guild inválido":        {guildID: "abc", cmd: EnqueueCommand{Input: "lofi"}},
+		"sin canción":           {guildID: guildID, cmd: EnqueueCommand{Input: "  "}},
+		"canal de voz inválido": {guildID: guildID, cmd: EnqueueCommand{Input: "lofi", VoiceChannelID: "general"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller := new(MockController)
+			recorder := new(MockRecorder)
+			logger := new(MockLogger)
+			logger.On("Warn", "Se descartó un comando externo", mock.Anything).Return()
+			recorder.On("Record", mock.MatchedBy(func(entry audit.Entry) bool { return entry.Result != "ok" })).Return()
+
+			err := NewConsumer(controller, recorder, logger).handleEnqueue(context.Background(), newJob(t, CommandEnqueue, tt.guildID, tt.cmd))
+
+			assert.NoError(t, err)
+			controller.AssertNotCalled(t, "Enqueue", mock.Anything)
+			recorder.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConsumer_SkipAndStop(t *testing.T) {
+	controller := new(MockController)
+	recorder := new(MockRecorder)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	recorder.On("Record", mock.Anything).Return()
+	controller.On("Skip", guildID).Return(nil)
+	controller.On("Stop", guildID).Return(control.ErrPlayerNotFound)
+	consumer := NewConsumer(controller, recorder, logger)
+
+	assert.NoError(t, consumer.handleSkip(context.Background(), &jobs.Job{Type: CommandSkip, GuildID: guildID}))
+	assert.NoError(t, consumer.handleStop(context.Background(), newJob(t, CommandStop, guildID, PlayerCommand{Source: "lambda"})))
+
+	controller.AssertExpectations(t)
+	logger.AssertNumberOfCalls(t, "Warn", 1)
+	recorder.AssertNumberOfCalls(t, "Record", 2)
+}
+
+func TestConsumer_RetriesTransientErrors(t *testing.T) {
+	controller := new(MockController)
+	recorder := new(MockRecorder)
+	recorder.On("Record", mock.Anything).Return()
+	controller.On("Skip", guildID).Return(errors.New("se cerró la conexión de voz"))
+
+	err := NewConsumer(controller, recorder, new(MockLogger)).handleSkip(context.Background(), &jobs.Job{Type: CommandSkip, GuildID: guildID})
+
+	assert.ErrorContains(t, err, "se cerró la conexión de voz")
+}
@@ -0,0 +1,75 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Permisos de Discord que habilitan a editar las colas y la configuración desde el dashboard.
+const (
+	permissionAdministrator = 0x8
+	permissionManageGuild   = 0x20
+)
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Avatar   string `json:"avatar"`
+}
+
+type discordGuild struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Icon        string `json:"icon"`
+	Owner       bool   `json:"owner"`
+	Permissions string `json:"permissions"`
+}
+
+// canManage indica si el usuario puede administrar el servidor.
+func (g discordGuild) canManage() bool {
+	if g.Owner {
+		return true
+	}
+	permissions, err := strconv.ParseInt(g.Permissions, 10, 64)
+	if err != nil {
+		return false
+	}
+	return permissions&(permissionAdministrator|permissionManageGuild) != 0
+}
+
+// fetchSession obtiene el usuario y sus servidores desde la API de Discord con el cliente autenticado por OAuth2.
+func (s *Server) fetchSession(ctx context.Context, client *http.Client) (*Session, error) {
+	var user discordUser
+	if err := s.getDiscord(ctx, client, "/users/@me", &user); err != nil {
+		return nil, err
+	}
+	var guilds []discordGuild
+	if err := s.getDiscord(ctx, client, "/users/@me/guilds", &guilds); err != nil {
+		return nil, err
+	}
+
+	session := &Session{UserID: user.ID, Username: user.Username, Avatar: user.Avatar}
+	for _, guild := range guilds {
+		session.Guilds = append(session.Guilds, Guild{ID: guild.ID, Name: guild.Name, Icon: guild.Icon, CanManage: guild.canManage()})
+	}
+	return session, nil
+}
+
+func (s *Server) getDiscord(ctx context.Context, client *http.Client, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.discordAPI+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al consultar %s en Discord: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Discord respondió %d al consultar %s", resp.StatusCode, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
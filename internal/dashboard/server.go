@@ -0,0 +1,343 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// sessionCookie es la cookie con el ID de la sesión del usuario.
+	sessionCookie = "gomusicbot_session"
+	// stateCookie es la cookie con el state del flujo OAuth2, para evitar CSRF en el callback.
+	stateCookie = "gomusicbot_oauth_state"
+	// guildsPrefix es el prefijo de los endpoints de cada servidor, los mismos que expone la API HTTP.
+	guildsPrefix = "/api/v1/guilds/"
+	// maxBodySize es el tamaño máximo del cuerpo de las solicitudes.
+	maxBodySize = 64 * 1024
+)
+
+// discordEndpoint son los endpoints OAuth2 de Discord.
+var discordEndpoint = oauth2.Endpoint{
+	AuthURL:   "https://discord.com/oauth2/authorize",
+	TokenURL:  "https://discord.com/api/oauth2/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// Config contiene la configuración de la aplicación OAuth2 de Discord usada por el dashboard.
+type Config struct {
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string        // RedirectURL es la URL de /auth/callback registrada en la aplicación de Discord.
+	FrontendURL   string        // FrontendURL es adonde se redirige al usuario después de iniciar o cerrar sesión.
+	SessionTTL    time.Duration // SessionTTL es la duración de las sesiones.
+	SecureCookies bool          // SecureCookies marca las cookies como Secure; desactivarlo solo para desarrollo sin HTTPS.
+}
+
+// Server es el backend del dashboard web. Los usuarios inician sesión con Discord y pueden ver las colas de
+// los servidores que comparten con el bot; para editarlas necesitan el permiso "Gestionar servidor".
+// Los endpoints de las colas y los eventos son los de la API HTTP, montados detrás de la sesión; los de la
+// configuración del servidor (/api/v1/guilds/{id}/settings) son propios y piden siempre ese permiso. Las páginas las
+// sirve el frontend, en FrontendURL.
+type Server struct {
+	oauth      *oauth2.Config
+	cfg        Config
+	controller control.Controller
+	api        http.Handler
+	settings   SettingsController
+	sessions   *SessionStore
+	discordAPI string
+	logger     logging.Logger
+	server     *http.Server
+}
+
+// NewServer crea el backend del dashboard. api son las rutas de la API HTTP sin autenticar (httpapi.Server.Routes).
+func NewServer(address string, cfg Config, controller control.Controller, api http.Handler, logger logging.Logger) *Server {
+	s := &Server{
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       []string{"identify", "guilds"},
+			Endpoint:     discordEndpoint,
+		},
+		cfg:        cfg,
+		controller: controller,
+		api:        api,
+		sessions:   NewSessionStore(cfg.SessionTTL),
+		discordAPI: "https://discord.com/api/v10",
+		logger:     logger,
+	}
+	s.server = &http.Server{
+		Addr:              address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// WithSettings habilita los endpoints de la configuración del servidor.
+func (s *Server) WithSettings(settings SettingsController) *Server {
+	s.settings = settings
+	return s
+}
+
+// Handler devuelve el http.Handler con los endpoints del dashboard.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", s.login)
+	mux.HandleFunc("/auth/callback", s.callback)
+	mux.HandleFunc("/auth/logout", s.logout)
+	mux.HandleFunc("/api/v1/me", s.requireSession(s.me))
+	mux.HandleFunc(guildsPrefix, s.requireSession(s.guild))
+	mux.HandleFunc("/api/v1/events", s.requireSession(s.events))
+	return mux
+}
+
+// Start inicia el servidor HTTP. Bloquea hasta que el servidor se cierra.
+func (s *Server) Start() error {
+	s.logger.Info("Dashboard escuchando", zap.String("address", s.server.Addr))
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown detiene el servidor HTTP.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// login redirige al usuario a la pantalla de autorización de Discord.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	state, err := randomToken(16)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.New("no se pudo iniciar sesión"))
+		return
+	}
+	http.SetCookie(w, s.cookie(stateCookie, state, 10*time.Minute))
+	http.Redirect(w, r, s.oauth.AuthCodeURL(state), http.StatusFound)
+}
+
+// callback recibe la autorización de Discord, obtiene el usuario y sus servidores y crea la sesión.
+func (s *Server) callback(w http.ResponseWriter, r *http.Request) {
+	state, err := r.Cookie(stateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		writeError(w, http.StatusBadRequest, errors.New("state inválido"))
+		return
+	}
+	http.SetCookie(w, s.cookie(stateCookie, "", -1))
+
+	token, err := s.oauth.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		s.logger.Warn("Error al obtener el token de Discord", zap.Error(err))
+		writeError(w, http.StatusUnauthorized, errors.New("no se pudo iniciar sesión con Discord"))
+		return
+	}
+	session, err := s.fetchSession(r.Context(), s.oauth.Client(r.Context(), token))
+	if err != nil {
+		s.logger.Error("Error al obtener el usuario de Discord", zap.Error(err))
+		writeError(w, http.StatusBadGateway, errors.New("no se pudo obtener el usuario de Discord"))
+		return
+	}
+	id, err := s.sessions.Create(session)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.New("no se pudo iniciar sesión"))
+		return
+	}
+
+	s.logger.Info("Usuario inició sesión en el dashboard", zap.String("userID", session.UserID))
+	http.SetCookie(w, s.cookie(sessionCookie, id, s.cfg.SessionTTL))
+	http.Redirect(w, r, s.cfg.FrontendURL, http.StatusFound)
+}
+
+// logout cierra la sesión del usuario.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("método no permitido"))
+		return
+	}
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		s.sessions.Delete(cookie.Value)
+	}
+	http.SetCookie(w, s.cookie(sessionCookie, "", -1))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cookie crea una cookie HttpOnly. Con SameSite=Lax el navegador no la envía en los POST de otros sitios,
+// lo que protege los endpoints que editan las colas de CSRF.
+func (s *Server) cookie(name, value string, ttl time.Duration) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   s.cfg.SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if ttl < 0 {
+		cookie.MaxAge = -1
+	} else {
+		cookie.MaxAge = int(ttl.Seconds())
+	}
+	return cookie
+}
+
+type sessionContextKey struct{}
+
+// requireSession rechaza las solicitudes sin una sesión válida y guarda la sesión en el contexto.
+func (s *Server) requireSession(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, errors.New("no iniciaste sesión"))
+			return
+		}
+		session, ok := s.sessions.Get(cookie.Value)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, errors.New("la sesión venció"))
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, session)))
+	}
+}
+
+func sessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return session
+}
+
+// me devuelve el usuario y los servidores que comparte con el bot.
+func (s *Server) me(w http.ResponseWriter, r *http.Request) {
+	session := sessionFromContext(r.Context())
+	botGuilds := make(map[string]struct{})
+	for _, player := range s.controller.ListPlayers() {
+		botGuilds[player.GuildID] = struct{}{}
+	}
+
+	guilds := make([]Guild, 0)
+	for _, guild := range session.Guilds {
+		if _, ok := botGuilds[guild.ID]; ok {
+			guilds = append(guilds, guild)
+		}
+	}
+	writeJSON(w, http.StatusOK, Me{ID: session.UserID, Username: session.Username, Avatar: session.Avatar, Guilds: guilds})
+}
+
+// guild controla los permisos del usuario sobre el servidor y delega la solicitud a la API HTTP. Cualquier miembro
+// puede ver la cola y agregar canciones; saltar, detener y cambiar el volumen requiere poder administrar el servidor.
+func (s *Server) guild(w http.ResponseWriter, r *http.Request) {
+	session := sessionFromContext(r.Context())
+	guildID, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, guildsPrefix), "/")
+	guild, ok := session.guild(guildID)
+	if !ok {
+		writeError(w, http.StatusForbidden, errors.New("no sos miembro de este servidor"))
+		return
+	}
+	if resource == "settings" {
+		if !guild.CanManage {
+			writeError(w, http.StatusForbidden, errors.New("necesitás el permiso de gestionar el servidor"))
+			return
+		}
+		s.guildSettings(w, r, guildID)
+		return
+	}
+
+	enqueue := resource == "queue" && r.Method == http.MethodPost
+	if r.Method != http.MethodGet && !enqueue && !guild.CanManage {
+		writeError(w, http.StatusForbidden, errors.New("necesitás el permiso de gestionar el servidor"))
+		return
+	}
+	if enqueue {
+		// La canción queda a nombre del usuario de la sesión, sin importar lo que mande el cliente.
+		if err := setRequestedBy(r, session.Username); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("cuerpo inválido"))
+			return
+		}
+	}
+	s.api.ServeHTTP(w, r)
+}
+
+// events delega el stream de eventos a la API HTTP, solo para un servidor del que el usuario es miembro.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedOrigin(r) {
+		writeError(w, http.StatusForbidden, errors.New("origen no permitido"))
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if _, ok := session.guild(r.URL.Query().Get("guild_id")); !ok {
+		writeError(w, http.StatusForbidden, errors.New("no sos miembro de este servidor"))
+		return
+	}
+	s.api.ServeHTTP(w, r)
+}
+
+// allowedOrigin indica si la solicitud viene del dashboard: del mismo host que el backend o del de FrontendURL. Los
+// navegadores abren un WebSocket hacia cualquier sitio con sus cookies, así que sin esta verificación otra página
+// podría leer los eventos con la sesión del usuario. Los clientes que no son navegadores no envían Origin.
+func (s *Server) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originURL.Host, r.Host) {
+		return true
+	}
+	frontend, err := url.Parse(s.cfg.FrontendURL)
+	return err == nil && frontend.Host != "" && strings.EqualFold(originURL.Scheme, frontend.Scheme) &&
+		strings.EqualFold(originURL.Host, frontend.Host)
+}
+
+// setRequestedBy reemplaza el campo requested_by del cuerpo de la solicitud.
+func setRequestedBy(r *http.Request, username string) error {
+	var body map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&body); err != nil {
+		return err
+	}
+	body["requested_by"] = username
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.ContentLength = int64(len(data))
+	return nil
+}
+
+// Me es la respuesta de /api/v1/me.
+type Me struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Avatar   string  `json:"avatar,omitempty"`
+	Guilds   []Guild `json:"guilds"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Error: err.Error()})
+}
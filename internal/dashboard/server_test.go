@@ -0,0 +1,255 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFakeDiscord simula los endpoints OAuth2 y de usuario de Discord.
+func newFakeDiscord(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "codigo", r.Form.Get("code"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"token-discord","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/users/@me", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token-discord", r.Header.Get("Authorization"))
+		_, _ = io.WriteString(w, `{"id":"u1","username":"tomas"}`)
+	})
+	mux.HandleFunc("/users/@me/guilds", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[
+			{"id":"1","name":"Admin","permissions":"32"},
+			{"id":"2","name":"Miembro","permissions":"1024"},
+			{"id":"3","name":"Sin bot","owner":true,"permissions":"0"}
+		]`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// login recorre el flujo OAuth2 y devuelve la cookie de sesión.
+func login(t *testing.T, server *Server) *http.Cookie {
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+	require.Equal(t, http.StatusFound, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	state := location.Query().Get("state")
+	require.NotEmpty(t, state)
+	stateCookie := rec.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/callback?code=codigo&state="+state, nil)
+	req.AddCookie(stateCookie)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/dashboard", rec.Header().Get("Location"))
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == sessionCookie {
+			return cookie
+		}
+	}
+	t.Fatal("no se creó la cookie de sesión")
+	return nil
+}
+
+func newTestServer(t *testing.T, api http.Handler) (*Server, *MockController) {
+	discord := newFakeDiscord(t)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	controller := new(MockController)
+	server := NewServer(":0", Config{ClientID: "cliente", ClientSecret: "secreto", FrontendURL: "/dashboard", SessionTTL: time.Hour}, controller, api, logger)
+	server.oauth.Endpoint.TokenURL = discord.URL + "/api/oauth2/token"
+	server.discordAPI = discord.URL
+	return server, controller
+}
+
+func TestServer_LoginAndMe(t *testing.T) {
+	server, controller := newTestServer(t, http.NotFoundHandler())
+	controller.On("ListPlayers").Return([]control.PlayerStatus{{GuildID: "1"}, {GuildID: "2"}})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/callback?code=codigo&state=otro", nil)
+	req.AddCookie(&http.Cookie{Name: stateCookie, Value: "state"})
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	cookie := login(t, server)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	var me Me
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&me))
+	assert.Equal(t, "tomas", me.Username)
+	assert.Equal(t, []Guild{{ID: "1", Name: "Admin", CanManage: true}, {ID: "2", Name: "Miembro"}}, me.Guilds,
+		"solo se muestran los servidores compartidos con el bot")
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.AddCookie(cookie)
+	server.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServer_GuildPermissions(t *testing.T) {
+	var delegated []string
+	var body string
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delegated = append(delegated, r.Method+" "+r.URL.Path)
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server, _ := newTestServer(t, api)
+	cookie := login(t, server)
+
+	serve := func(method, path, reqBody string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(reqBody))
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodGet, "/api/v1/guilds/2/queue", ""))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodPost, "/api/v1/guilds/2/queue", `{"input":"canción","requested_by":"otro"}`))
+	assert.JSONEq(t, `{"input":"canción","requested_by":"tomas"}`, body)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/api/v1/guilds/2/skip", ""))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodPut, "/api/v1/guilds/1/volume", `{"volume":50}`))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/guilds/9/queue", ""))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/events?guild_id=9", ""))
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodGet, "/api/v1/events?guild_id=1", ""))
+
+	assert.Equal(t, []string{
+		"GET /api/v1/guilds/2/queue",
+		"POST /api/v1/guilds/2/queue",
+		"PUT /api/v1/guilds/1/volume",
+		"GET /api/v1/events",
+	}, delegated)
+}
+
+func TestServer_GuildSettings(t *testing.T) {
+	server, _ := newTestServer(t, http.NotFoundHandler())
+	controller := new(MockSettingsController)
+	server.WithSettings(controller)
+	cookie := login(t, server)
+	controller.On("GuildSettings", "1").Return(settings.Guild{GuildID: "1", VoteSkip: 50}, nil)
+	controller.On("UpdateGuildSettings", "1").Return(settings.Guild{GuildID: "1", VoteSkip: 50}, nil)
+
+	serve := func(method, path, reqBody string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(reqBody))
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/api/v1/guilds/1/settings", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"duplicates":"confirm","explicit":"allow","timezone":"","max_volume":0,"vote_skip":50,"dj_role":""}`, rec.Body.String())
+
+	rec = serve(http.MethodPut, "/api/v1/guilds/1/settings", `{"duplicates":"skip","timezone":"America/Argentina/Buenos_Aires","dj_role":"123"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"duplicates":"skip","explicit":"allow","timezone":"America/Argentina/Buenos_Aires","max_volume":0,"vote_skip":50,"dj_role":"123"}`, rec.Body.String())
+
+	for _, body := range []string{`{"duplicates":"nunca"}`, `{"timezone":"Marte/Olimpo"}`, `{"max_volume":500}`, `{"vote_skip":-1}`, `{"dj_role":"dj"}`, `no es json`} {
+		assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, "/api/v1/guilds/1/settings", body).Code, body)
+	}
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/guilds/2/settings", "").Code, "ver la configuración pide gestionar el servidor")
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/api/v1/guilds/2/settings", `{"vote_skip":10}`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodDelete, "/api/v1/guilds/1/settings", "").Code)
+	controller.AssertNumberOfCalls(t, "UpdateGuildSettings", 1)
+}
+
+func TestServer_GuildSettingsVolumeNotSupported(t *testing.T) {
+	server, _ := newTestServer(t, http.NotFoundHandler())
+	controller := new(MockSettingsController)
+	server.WithSettings(controller)
+	cookie := login(t, server)
+	controller.On("UpdateGuildSettings", "1").Return(settings.Guild{GuildID: "1"}, control.ErrVolumeNotSupported)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/guilds/1/settings", strings.NewReader(`{"max_volume":80}`))
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+func TestServer_EventsOrigin(t *testing.T) {
+	var delegated int
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delegated++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server, _ := newTestServer(t, api)
+	cookie := login(t, server)
+	server.cfg.FrontendURL = "https://musica.example.com/dashboard"
+
+	tests := []struct {
+		name   string
+		origin string
+		want   int
+	}{
+		{name: "sin origen", want: http.StatusNoContent},
+		{name: "mismo host que el backend", origin: "http://example.com", want: http.StatusNoContent},
+		{name: "frontend configurado", origin: "https://musica.example.com", want: http.StatusNoContent},
+		{name: "frontend con otro esquema", origin: "http://musica.example.com", want: http.StatusForbidden},
+		{name: "otro sitio", origin: "https://malicioso.example.net", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delegated = 0
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/events?guild_id=1", nil)
+			req.AddCookie(cookie)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, tt.want == http.StatusNoContent, delegated == 1, "solo se abre la conexión desde el dashboard")
+		})
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	now := time.Now()
+	store := NewSessionStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	id, err := store.Create(&Session{UserID: "u1"})
+	require.NoError(t, err)
+	session, ok := store.Get(id)
+	assert.True(t, ok)
+	assert.Equal(t, "u1", session.UserID)
+
+	now = now.Add(2 * time.Hour)
+	_, ok = store.Get(id)
+	assert.False(t, ok, "las sesiones vencidas se descartan")
+}
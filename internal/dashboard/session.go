@@ -0,0 +1,99 @@
+package dashboard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// Guild es un servidor del usuario, con los permisos que tiene en él.
+type Guild struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Icon      string `json:"icon,omitempty"`
+	CanManage bool   `json:"can_manage"`
+}
+
+// Session es la sesión de un usuario que inició sesión con Discord.
+type Session struct {
+	UserID    string
+	Username  string
+	Avatar    string
+	Guilds    []Guild
+	ExpiresAt time.Time
+}
+
+// guild devuelve el servidor del usuario con el ID indicado.
+func (s *Session) guild(guildID string) (Guild, bool) {
+	for _, guild := range s.Guilds {
+		if guild.ID == guildID {
+			return guild, true
+		}
+	}
+	return Guild{}, false
+}
+
+// SessionStore guarda las sesiones en memoria. Al reiniciar el bot, los usuarios tienen que volver a iniciar sesión.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewSessionStore crea un SessionStore cuyas sesiones vencen después de ttl.
+func NewSessionStore(ttl time.Duration) *SessionStore {
+	return &SessionStore{sessions: make(map[string]*Session), ttl: ttl, now: time.Now}
+}
+
+// Create guarda la sesión y devuelve su ID, que se envía al navegador en una cookie.
+func (s *SessionStore) Create(session *Session) (string, error) {
+	id, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, existing := range s.sessions {
+		if now.After(existing.ExpiresAt) {
+			delete(s.sessions, key)
+		}
+	}
+	session.ExpiresAt = now.Add(s.ttl)
+	s.sessions[id] = session
+	return id, nil
+}
+
+// Get devuelve la sesión con el ID indicado, si existe y no venció.
+func (s *SessionStore) Get(id string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	if s.now().After(session.ExpiresAt) {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	return session, true
+}
+
+// Delete elimina la sesión.
+func (s *SessionStore) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// randomToken genera un token aleatorio de n bytes codificado en base64.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
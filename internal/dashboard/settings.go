@@ -0,0 +1,158 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+// SettingsController lee y cambia la configuración de los servidores.
+type SettingsController interface {
+	// GuildSettings devuelve la configuración del servidor.
+	GuildSettings(guildID string) (settings.Guild, error)
+	// UpdateGuildSettings le aplica update a la configuración del servidor y la devuelve actualizada.
+	UpdateGuildSettings(ctx context.Context, guildID string, update func(*settings.Guild)) (settings.Guild, error)
+}
+
+// GuildSettings es la configuración del servidor que se ve y se edita desde el dashboard, la misma de los comandos
+// /settings. Las listas de bloqueo, los baneos y los alias se siguen administrando desde Discord.
+type GuildSettings struct {
+	Duplicates string `json:"duplicates"`
+	Explicit   string `json:"explicit"`
+	Timezone   string `json:"timezone"`
+	MaxVolume  int    `json:"max_volume"`
+	VoteSkip   int    `json:"vote_skip"`
+	DJRole     string `json:"dj_role"`
+}
+
+// settingsUpdate es el cuerpo de un cambio de configuración; los campos que no vienen quedan como estaban.
+type settingsUpdate struct {
+	Duplicates *string `json:"duplicates"`
+	Explicit   *string `json:"explicit"`
+	Timezone   *string `json:"timezone"`
+	MaxVolume  *int    `json:"max_volume"`
+	VoteSkip   *int    `json:"vote_skip"`
+	DJRole     *string `json:"dj_role"`
+}
+
+func newGuildSettings(g settings.Guild) GuildSettings {
+	return GuildSettings{
+		Duplicates: string(g.DuplicatePolicy()),
+		Explicit:   string(g.ExplicitPolicy()),
+		Timezone:   g.Timezone,
+		MaxVolume:  g.MaxVolume,
+		VoteSkip:   g.VoteSkip,
+		DJRole:     g.DJRole,
+	}
+}
+
+// apply valida el cambio con los mismos límites que los comandos y devuelve la función que lo aplica. Una zona
+// horaria o un rol vacíos vuelven al valor por defecto.
+func (u settingsUpdate) apply() (func(*settings.Guild), error) {
+	var duplicates settings.DuplicatePolicy
+	if u.Duplicates != nil {
+		policy, err := settings.ParseDuplicatePolicy(*u.Duplicates)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = policy
+	}
+	var explicit settings.ExplicitPolicy
+	if u.Explicit != nil {
+		policy, err := settings.ParseExplicitPolicy(*u.Explicit)
+		if err != nil {
+			return nil, err
+		}
+		explicit = policy
+	}
+	var timezone string
+	if u.Timezone != nil && *u.Timezone != "" {
+		name, err := settings.ParseTimezone(*u.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		timezone = name
+	}
+	if u.MaxVolume != nil && (*u.MaxVolume < 0 || *u.MaxVolume > control.MaxVolume) {
+		return nil, fmt.Errorf("%w: el volumen máximo va de 0 a %d", settings.ErrInvalidValue, control.MaxVolume)
+	}
+	if u.VoteSkip != nil && (*u.VoteSkip < 0 || *u.VoteSkip > 100) {
+		return nil, fmt.Errorf("%w: el porcentaje de votos va de 0 a 100", settings.ErrInvalidValue)
+	}
+	if u.DJRole != nil && *u.DJRole != "" {
+		if _, err := strconv.ParseUint(*u.DJRole, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: %s no es el ID de un rol", settings.ErrInvalidValue, *u.DJRole)
+		}
+	}
+
+	return func(g *settings.Guild) {
+		if u.Duplicates != nil {
+			g.Duplicates = duplicates
+		}
+		if u.Explicit != nil {
+			g.Explicit = explicit
+		}
+		if u.Timezone != nil {
+			g.Timezone = timezone
+		}
+		if u.MaxVolume != nil {
+			g.MaxVolume = *u.MaxVolume
+		}
+		if u.VoteSkip != nil {
+			g.VoteSkip = *u.VoteSkip
+		}
+		if u.DJRole != nil {
+			g.DJRole = *u.DJRole
+		}
+	}, nil
+}
+
+// guildSettings muestra (GET) o cambia (PUT) la configuración del servidor. Como /settings, las dos cosas piden el
+// permiso de gestionar el servidor, que ya se comprobó.
+func (s *Server) guildSettings(w http.ResponseWriter, r *http.Request, guildID string) {
+	if s.settings == nil {
+		writeError(w, http.StatusNotFound, errors.New("la configuración por servidor no está habilitada"))
+		return
+	}
+
+	var (
+		guild settings.Guild
+		err   error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		guild, err = s.settings.GuildSettings(guildID)
+	case http.MethodPut:
+		var req settingsUpdate
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("cuerpo inválido"))
+			return
+		}
+		var update func(*settings.Guild)
+		if update, err = req.apply(); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		guild, err = s.settings.UpdateGuildSettings(r.Context(), guildID, update)
+		if errors.Is(err, control.ErrVolumeNotSupported) {
+			writeError(w, http.StatusNotImplemented, err)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("método no permitido"))
+		return
+	}
+	if err != nil {
+		s.logger.Error("Error con la configuración del servidor", zap.String("guild_id", guildID), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, errors.New("ocurrió un error con la configuración"))
+		return
+	}
+	writeJSON(w, http.StatusOK, newGuildSettings(guild))
+}
@@ -0,0 +1,230 @@
+package devmode
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// GuildID es el servidor ficticio del modo desarrollo.
+	GuildID = "dev"
+	// ChannelID es el canal ficticio, de texto y de voz, del modo desarrollo.
+	ChannelID = "dev"
+
+	consoleHelp = `Comandos:
+  play <canción o URL>  agrega una canción a la lista
+  skip                  salta la canción actual
+  pause                 pausa la canción actual
+  resume                retoma la canción pausada
+  stop                  detiene la reproducción y limpia la lista
+  list                  muestra la lista de reproducción
+  playing               muestra la canción actual
+  volume <porcentaje>   cambia el volumen
+  quit                  sale del modo desarrollo`
+)
+
+// Player son las operaciones del reproductor que usa la consola; lo implementa bot.GuildPlayer.
+type Player interface {
+	AddSong(ctx context.Context, textChannelID, voiceChannelID *string, songs ...*voice.Song) error
+	SkipSong()
+	Pause(ctx context.Context) (bool, error)
+	Unpause(ctx context.Context) (bool, error)
+	Stop() error
+	GetSongs() ([]*voice.Song, error)
+	GetPlayedSong() (*voice.PlayedSong, error)
+	SetVolume(ctx context.Context, volume int) error
+}
+
+// Messenger implementa discordmessenger.ChatMessageSender escribiendo los mensajes del reproductor en la consola.
+type Messenger struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewMessenger crea un Messenger que escribe en out.
+func NewMessenger(out io.Writer) *Messenger {
+	return &Messenger{out: out}
+}
+
+// Printf escribe una línea en la consola.
+func (m *Messenger) Printf(format string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, _ = fmt.Fprintf(m.out, format+"\n", args...)
+}
+
+func (m *Messenger) SendMessage(_, message string) error {
+	m.Printf("%s", message)
+	return nil
+}
+
+func (m *Messenger) SendPlayMessage(_ string, message *voice.PlayMessage) (string, error) {
+	m.Printf("▶ Reproduciendo: %s (%s)", message.Song.GetHumanName(), message.Song.Duration)
+	return ChannelID, nil
+}
+
+// EditPlayMessage no escribe nada: el reproductor actualiza la posición cada pocos segundos y llenaría la consola.
+func (m *Messenger) EditPlayMessage(string, string, *voice.PlayMessage) error {
+	return nil
+}
+
+// Console maneja el reproductor con comandos que se leen de la entrada estándar, parecidos a los slash commands.
+type Console struct {
+	player    Player
+	looker    fetcher.SongLooker
+	messenger *Messenger
+}
+
+// NewConsole crea una consola que busca las canciones con looker y las reproduce con player.
+func NewConsole(player Player, looker fetcher.SongLooker, messenger *Messenger) *Console {
+	return &Console{player: player, looker: looker, messenger: messenger}
+}
+
+// Run lee comandos de in, uno por línea, hasta que se cancela ctx, termina la entrada o se ejecuta quit.
+func (c *Console) Run(ctx context.Context, in io.Reader) error {
+	c.messenger.Printf("Modo desarrollo: escribí help para ver los comandos")
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				return <-scanErr
+			}
+			if c.Exec(ctx, line) {
+				return nil
+			}
+		}
+	}
+}
+
+// Exec ejecuta un comando y devuelve true si es quit.
+func (c *Console) Exec(ctx context.Context, line string) bool {
+	command, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	switch command {
+	case "":
+	case "play":
+		c.play(ctx, arg)
+	case "skip":
+		c.player.SkipSong()
+		c.messenger.Printf("Canción saltada")
+	case "pause":
+		if _, err := c.player.Pause(ctx); err != nil {
+			c.messenger.Printf("Error al pausar la reproducción: %v", err)
+			return false
+		}
+		c.messenger.Printf("Reproducción pausada")
+	case "resume":
+		if _, err := c.player.Unpause(ctx); err != nil {
+			c.messenger.Printf("Error al retomar la reproducción: %v", err)
+			return false
+		}
+		c.messenger.Printf("Reproducción retomada")
+	case "stop":
+		if err := c.player.Stop(); err != nil {
+			c.messenger.Printf("Error al detener la reproducción: %v", err)
+			return false
+		}
+		c.messenger.Printf("Reproducción detenida")
+	case "list":
+		c.list()
+	case "playing":
+		c.playing()
+	case "volume":
+		c.volume(ctx, arg)
+	case "quit", "exit":
+		return true
+	case "help":
+		c.messenger.Printf(consoleHelp)
+	default:
+		c.messenger.Printf("Comando desconocido: %s. Escribí help para ver los comandos", command)
+	}
+	return false
+}
+
+func (c *Console) play(ctx context.Context, input string) {
+	if input == "" {
+		c.messenger.Printf("Uso: play <canción o URL>")
+		return
+	}
+	songs, err := c.looker.LookupSongs(ctx, input)
+	if err != nil {
+		c.messenger.Printf("Error al buscar la canción: %v", err)
+		return
+	}
+	if len(songs) == 0 {
+		c.messenger.Printf("No se encontró ninguna canción para %q", input)
+		return
+	}
+
+	channelID := ChannelID
+	if err := c.player.AddSong(ctx, &channelID, &channelID, songs...); err != nil {
+		c.messenger.Printf("Error al agregar la canción: %v", err)
+		return
+	}
+	if len(songs) == 1 {
+		c.messenger.Printf("Agregada a la lista: %s", songs[0].GetHumanName())
+		return
+	}
+	c.messenger.Printf("Agregadas %d canciones a la lista", len(songs))
+}
+
+func (c *Console) list() {
+	songs, err := c.player.GetSongs()
+	if err != nil {
+		c.messenger.Printf("Error al obtener la lista de reproducción: %v", err)
+		return
+	}
+	if len(songs) == 0 {
+		c.messenger.Printf("La lista de reproducción está vacía")
+		return
+	}
+	for i, song := range songs {
+		c.messenger.Printf("%d. %s", i+1, song.GetHumanName())
+	}
+}
+
+func (c *Console) playing() {
+	played, err := c.player.GetPlayedSong()
+	if err != nil {
+		c.messenger.Printf("Error al obtener la canción actual: %v", err)
+		return
+	}
+	if played == nil {
+		c.messenger.Printf("No se está reproduciendo nada")
+		return
+	}
+	c.messenger.Printf("%s (%s / %s)", played.GetHumanName(), played.Position, played.Duration)
+}
+
+func (c *Console) volume(ctx context.Context, arg string) {
+	volume, err := strconv.Atoi(arg)
+	if err != nil || volume < 0 {
+		c.messenger.Printf("Uso: volume <porcentaje>")
+		return
+	}
+	if err := c.player.SetVolume(ctx, volume); err != nil {
+		c.messenger.Printf("Error al cambiar el volumen: %v", err)
+		return
+	}
+	c.messenger.Printf("Volumen: %d%%", volume)
+}
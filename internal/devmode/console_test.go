@@ -0,0 +1,86 @@
+package devmode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestConsole() (*Console, *MockPlayer, *MockSongLooker, *bytes.Buffer) {
+	player := new(MockPlayer)
+	looker := new(MockSongLooker)
+	out := &bytes.Buffer{}
+	return NewConsole(player, looker, NewMessenger(out)), player, looker, out
+}
+
+func TestConsole_Play(t *testing.T) {
+	console, player, looker, out := newTestConsole()
+	song := &voice.Song{Title: "La Bamba", URL: "https://youtube.com/watch?v=1"}
+	looker.On("LookupSongs", mock.Anything, "la bamba").Return([]*voice.Song{song}, nil)
+	player.On("AddSong", mock.Anything, mock.Anything, mock.Anything, []*voice.Song{song}).Return(nil)
+
+	quit := console.Exec(context.Background(), "play la bamba")
+
+	assert.False(t, quit)
+	assert.Contains(t, out.String(), "Agregada a la lista: La Bamba")
+	player.AssertExpectations(t)
+}
+
+func TestConsole_PlayLookupError(t *testing.T) {
+	console, player, looker, out := newTestConsole()
+	looker.On("LookupSongs", mock.Anything, "nada").Return([]*voice.Song(nil), errors.New("sin resultados"))
+
+	console.Exec(context.Background(), "play nada")
+
+	assert.Contains(t, out.String(), "Error al buscar la canción: sin resultados")
+	player.AssertNotCalled(t, "AddSong", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsole_Commands(t *testing.T) {
+	console, player, _, out := newTestConsole()
+	player.On("SkipSong").Return()
+	player.On("GetSongs").Return([]*voice.Song{{Title: "Siguiente"}}, nil)
+	player.On("GetPlayedSong").Return(&voice.PlayedSong{Song: voice.Song{Title: "La Bamba", Duration: 3 * time.Minute}, Position: time.Minute}, nil)
+	player.On("SetVolume", mock.Anything, 50).Return(nil)
+	player.On("Pause", mock.Anything).Return(true, nil)
+	player.On("Unpause", mock.Anything).Return(false, errors.New("no hay ninguna canción sonando"))
+
+	console.Exec(context.Background(), "skip")
+	console.Exec(context.Background(), "pause")
+	console.Exec(context.Background(), "resume")
+	console.Exec(context.Background(), "list")
+	console.Exec(context.Background(), "playing")
+	console.Exec(context.Background(), "volume 50")
+	console.Exec(context.Background(), "volume fuerte")
+	console.Exec(context.Background(), "bailar")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{
+		"Canción saltada",
+		"Reproducción pausada",
+		"Error al retomar la reproducción: no hay ninguna canción sonando",
+		"1. Siguiente",
+		"La Bamba (1m0s / 3m0s)",
+		"Volumen: 50%",
+		"Uso: volume <porcentaje>",
+		"Comando desconocido: bailar. Escribí help para ver los comandos",
+	}, lines)
+	player.AssertExpectations(t)
+}
+
+func TestConsole_RunStopsOnQuit(t *testing.T) {
+	console, player, _, _ := newTestConsole()
+	player.On("Stop").Return(nil)
+
+	err := console.Run(context.Background(), strings.NewReader("stop\nquit\nskip\n"))
+
+	assert.NoError(t, err)
+	player.AssertExpectations(t)
+	player.AssertNotCalled(t, "SkipSong")
+}
@@ -0,0 +1,85 @@
+package devmode
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockSongLooker struct {
+	mock.Mock
+}
+
+func (m *MockSongLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]*voice.Song), args.Error(1)
+}
+
+func (m *MockSongLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	args := m.Called(ctx, searchTerm)
+	return args.String(0), args.Error(1)
+}
+
+type MockPlayer struct {
+	mock.Mock
+}
+
+func (m *MockPlayer) AddSong(ctx context.Context, textChannelID, voiceChannelID *string, songs ...*voice.Song) error {
+	args := m.Called(ctx, textChannelID, voiceChannelID, songs)
+	return args.Error(0)
+}
+
+func (m *MockPlayer) SkipSong() {
+	m.Called()
+}
+
+func (m *MockPlayer) Pause(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPlayer) Unpause(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPlayer) Stop() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockPlayer) GetSongs() ([]*voice.Song, error) {
+	args := m.Called()
+	return args.Get(0).([]*voice.Song), args.Error(1)
+}
+
+func (m *MockPlayer) GetPlayedSong() (*voice.PlayedSong, error) {
+	args := m.Called()
+	return args.Get(0).(*voice.PlayedSong), args.Error(1)
+}
+
+func (m *MockPlayer) SetVolume(ctx context.Context, volume int) error {
+	args := m.Called(ctx, volume)
+	return args.Error(0)
+}
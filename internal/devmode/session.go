@@ -0,0 +1,153 @@
+package devmode
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// frameInterval es cada cuánto consume un frame la sesión local, igual que la conexión de voz de Discord, para
+// que las posiciones y los tiempos del reproductor sean los reales.
+const frameInterval = 20 * time.Millisecond
+
+// LocalSession es una sesión de voz que, en lugar de enviar el audio a Discord, lo escribe como Ogg Opus en un
+// archivo o en la entrada de un reproductor local como ffplay. Sirve para probar el reproductor y el fetcher sin
+// un servidor de Discord.
+type LocalSession struct {
+	output   string   // output es el archivo donde se escribe el audio si no hay reproductor.
+	player   []string // player es el comando del reproductor local, que recibe cada canción por su entrada; es opcional.
+	streamer codec.DCAStreamer
+	logger   logging.Logger
+	interval time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewLocalSession crea una sesión que escribe el audio en output o, si player no está vacío, lo reproduce con ese
+// comando.
+func NewLocalSession(output string, player []string, streamer codec.DCAStreamer, logger logging.Logger) *LocalSession {
+	return &LocalSession{
+		output:   output,
+		player:   player,
+		streamer: streamer,
+		logger:   logger,
+		interval: frameInterval,
+	}
+}
+
+// Close cierra el archivo de audio, si está abierto.
+func (s *LocalSession) Close() error {
+	return s.LeaveVoiceChannel()
+}
+
+// JoinVoiceChannel abre el archivo de audio. Las canciones se escriben una detrás de otra, como streams Ogg
+// encadenados, hasta que la sesión sale del canal.
+func (s *LocalSession) JoinVoiceChannel(channelID string) error {
+	s.logger.Info("Uniéndose al canal de voz local", zap.String("channelID", channelID))
+	if len(s.player) > 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		return nil
+	}
+	file, err := os.Create(s.output)
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo de audio %s: %w", s.output, err)
+	}
+	s.file = file
+	return nil
+}
+
+// LeaveVoiceChannel cierra el archivo de audio.
+func (s *LocalSession) LeaveVoiceChannel() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// SendAudio decodifica el flujo DCA y escribe sus frames en el archivo o en el reproductor local.
+func (s *LocalSession) SendAudio(ctx context.Context, reader io.Reader, positionCallback func(time.Duration)) error {
+	if len(s.player) > 0 {
+		return s.play(ctx, reader, positionCallback)
+	}
+
+	s.mu.Lock()
+	file := s.file
+	s.mu.Unlock()
+	if file == nil {
+		return fmt.Errorf("la sesión local no está en un canal de voz")
+	}
+	return s.stream(ctx, file, reader, positionCallback)
+}
+
+// play reproduce la canción con un proceso nuevo del reproductor, que se corta si se cancela ctx.
+func (s *LocalSession) play(ctx context.Context, reader io.Reader, positionCallback func(time.Duration)) error {
+	cmd := exec.CommandContext(ctx, s.player[0], s.player[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error al iniciar el reproductor local %s: %w", s.player[0], err)
+	}
+
+	streamErr := s.stream(ctx, stdin, reader, positionCallback)
+	_ = stdin.Close()
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		s.logger.Error("El reproductor local terminó con error", zap.Error(err))
+	}
+	return streamErr
+}
+
+// stream envía los frames del flujo DCA a w a la velocidad de reproducción.
+func (s *LocalSession) stream(ctx context.Context, w io.Writer, reader io.Reader, positionCallback func(time.Duration)) error {
+	frames := make(chan []byte)
+	written := make(chan error, 1)
+	go func() {
+		written <- s.writeFrames(w, frames)
+	}()
+
+	err := s.streamer.StreamDCAData(ctx, reader, frames, positionCallback)
+	close(frames)
+	if writeErr := <-written; err == nil {
+		err = writeErr
+	}
+	return err
+}
+
+// writeFrames escribe los frames como un stream Ogg Opus. Si falla la escritura, sigue consumiendo los frames para
+// no bloquear al DCAStreamer y devuelve el error al final.
+func (s *LocalSession) writeFrames(w io.Writer, frames <-chan []byte) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	ogg, err := codec.NewOggOpusWriter(w, rand.Uint32())
+	for frame := range frames {
+		if err != nil {
+			continue
+		}
+		<-ticker.C
+		err = ogg.WritePacket(frame)
+	}
+	if err != nil {
+		return fmt.Errorf("error al escribir el audio: %w", err)
+	}
+	return ogg.Close()
+}
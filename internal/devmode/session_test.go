@@ -0,0 +1,62 @@
+package devmode
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// dcaFrames arma un flujo DCA con los frames indicados.
+func dcaFrames(frames ...[]byte) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	for _, frame := range frames {
+		_ = binary.Write(buf, binary.LittleEndian, int16(len(frame)))
+		buf.Write(frame)
+	}
+	return buf
+}
+
+func TestLocalSession_WritesOggFile(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	output := filepath.Join(t.TempDir(), "dev.ogg")
+	session := NewLocalSession(output, nil, codec.NewDCAStreamerImpl(logger), logger)
+	session.interval = time.Millisecond
+
+	require.NoError(t, session.JoinVoiceChannel(ChannelID))
+	require.NoError(t, session.SendAudio(context.Background(), dcaFrames([]byte{1}, []byte{2}), nil))
+	require.NoError(t, session.SendAudio(context.Background(), dcaFrames([]byte{3}), nil))
+	require.NoError(t, session.LeaveVoiceChannel())
+
+	file, err := os.Open(output)
+	require.NoError(t, err)
+	defer file.Close()
+	// Cada canción es un stream encadenado, con sus propios encabezados.
+	reader := codec.NewOggOpusReader(file)
+	for _, want := range [][]byte{{1}, {2}, {3}} {
+		packet, err := reader.ReadPacket()
+		require.NoError(t, err)
+		assert.Equal(t, want, packet)
+	}
+	_, err = reader.ReadPacket()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestLocalSession_NotJoined(t *testing.T) {
+	logger := new(MockLogger)
+	session := NewLocalSession(filepath.Join(t.TempDir(), "dev.ogg"), nil, codec.NewDCAStreamerImpl(logger), logger)
+
+	err := session.SendAudio(context.Background(), dcaFrames([]byte{1}), nil)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// alarmRequester es el nombre con el que se agregan las canciones de las alarmas.
+const alarmRequester = "⏰ Alarma"
+
+// GuildLocation devuelve la zona horaria del servidor, configurada con /settings timezone, o la de las
+// reproducciones programadas si no tiene.
+func (handler *InteractionHandler) GuildLocation(guildID string) *time.Location {
+	fallback := handler.scheduleLocation
+	if fallback == nil {
+		fallback = time.UTC
+	}
+	if handler.settings == nil {
+		return fallback
+	}
+	return handler.guildSettings(guildID).Location(fallback)
+}
+
+// SetTimezone cambia la zona horaria en la que se interpretan las horas de las alarmas y las reproducciones
+// programadas del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) SetTimezone(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetTimezone")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	timezone, err := settings.ParseTimezone(strings.TrimSpace(commandOptions(opt)["timezone"].StringValue()))
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 No conozco esa zona horaria. Usá un nombre como `America/Argentina/Buenos_Aires` o `Europe/Madrid`"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	message := fmt.Sprintf("⚙️ Zona horaria: `%s` (ahora son las %s)", timezone, time.Now().In(settings.Guild{Timezone: timezone}.Location(time.UTC)).Format("15:04"))
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.Timezone = timezone }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+// AlarmSet programa una alarma que entra al canal de voz a la hora pedida, en la zona horaria del servidor, y
+// reproduce la canción con el volumen indicado.
+func (handler *InteractionHandler) AlarmSet(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AlarmSet")
+	if !handler.alarmsEnabled(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	var voiceChannelID string
+	if option, ok := options["channel"]; ok {
+		voiceChannelID = option.Value.(string)
+	} else if g, err := s.State.Guild(ic.GuildID); err == nil {
+		if vs := getUsersVoiceState(g, ic.Member.User); vs != nil {
+			voiceChannelID = vs.ChannelID
+		}
+	}
+	if voiceChannelID == "" {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Elegí un canal de voz o conectate a uno"); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	var volume int
+	if option, ok := options["volume"]; ok {
+		volume = int(option.IntValue())
+	}
+
+	input := options["song"].StringValue()
+	at, err := scheduled.NextClock(options["time"].StringValue(), time.Now().In(handler.GuildLocation(ic.GuildID)))
+	var alarm scheduled.Schedule
+	if err == nil {
+		alarm, err = scheduled.NewAlarm(ic.GuildID, voiceChannelID, ic.ChannelID, input, volume, at, interactionUser(ic.Interaction).ID)
+	}
+	if err == nil {
+		err = scheduled.Add(handler.schedules, alarm)
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, scheduled.ErrInvalidSpec):
+		message = "🤷🏽 Usá la hora en formato HH:MM, por ejemplo `07:30`"
+	case errors.Is(err, scheduled.ErrTooMany):
+		message = fmt.Sprintf("🙅 El servidor ya tiene %d alarmas", scheduled.MaxSchedulesPerGuild)
+	case err != nil:
+		logger.Error("falló al guardar la alarma", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la alarma")
+	default:
+		message = fmt.Sprintf("⏰ Alarma `%s` para <t:%d:F> (<t:%d:R>) en <#%s>: **%s**", alarm.ID, at.Unix(), at.Unix(), voiceChannelID, input)
+		if volume > 0 {
+			message += fmt.Sprintf(" al %d%% de volumen", volume)
+		}
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con la alarma", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el error de la alarma", zap.Error(err))
+	}
+}
+
+// AlarmList muestra las alarmas pendientes del servidor.
+func (handler *InteractionHandler) AlarmList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AlarmList")
+	if !handler.alarmsEnabled(ic) {
+		return
+	}
+
+	schedules, err := handler.schedules.List(ic.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener las alarmas", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las alarmas")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateAlarmsEmbed(scheduled.Filter(schedules, true))},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las alarmas", zap.Error(err))
+	}
+}
+
+// AlarmCancel cancela una alarma. Solo puede hacerlo quien la creó o un administrador.
+func (handler *InteractionHandler) AlarmCancel(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AlarmCancel")
+	if !handler.alarmsEnabled(ic) {
+		return
+	}
+
+	id := strings.ToLower(strings.TrimSpace(commandOptions(opt)["id"].StringValue()))
+	message := fmt.Sprintf("🗑️ Alarma `%s` cancelada", id)
+	alarm, err := handler.findAlarm(ic.GuildID, id)
+	switch {
+	case errors.Is(err, scheduled.ErrNotFound):
+		message = "🤷🏽 No encontré esa alarma"
+	case err != nil:
+		logger.Error("falló al obtener las alarmas", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al cancelar la alarma")
+	case alarm.CreatedBy != interactionUser(ic.Interaction).ID && !isGuildAdmin(ic.Member):
+		message = "🔒 Solo quien creó la alarma o un administrador puede cancelarla"
+	default:
+		if err := handler.schedules.Delete(ic.GuildID, id); err != nil && !errors.Is(err, scheduled.ErrNotFound) {
+			logger.Error("falló al cancelar la alarma", zap.Error(err))
+			message = withErrorCode(ctx, "Ocurrió un error al cancelar la alarma")
+		}
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la alarma", zap.Error(err))
+	}
+}
+
+// runAlarm cambia el volumen, si la alarma lo pide y el backend de audio lo permite, y agrega la canción a la lista
+// de reproducción del canal de voz de la alarma.
+func (handler *InteractionHandler) runAlarm(ctx context.Context, alarm scheduled.Schedule) error {
+	if alarm.Volume > 0 {
+		if err := handler.SetVolume(ctx, alarm.GuildID, alarm.Volume); err != nil {
+			handler.logger.Warn("No se pudo cambiar el volumen de la alarma", zap.String("guildID", alarm.GuildID), zap.Error(err))
+		}
+	}
+	_, err := handler.Enqueue(ctx, control.EnqueueRequest{
+		GuildID:        alarm.GuildID,
+		Input:          alarm.Input,
+		VoiceChannelID: alarm.VoiceChannelID,
+		TextChannelID:  alarm.TextChannelID,
+		RequestedBy:    alarmRequester,
+	})
+	return err
+}
+
+// findAlarm busca una alarma del servidor por su ID.
+func (handler *InteractionHandler) findAlarm(guildID, id string) (scheduled.Schedule, error) {
+	schedules, err := handler.schedules.List(guildID)
+	if err != nil {
+		return scheduled.Schedule{}, err
+	}
+	for _, alarm := range scheduled.Filter(schedules, true) {
+		if alarm.ID == id {
+			return alarm, nil
+		}
+	}
+	return scheduled.Schedule{}, scheduled.ErrNotFound
+}
+
+// alarmsEnabled responde al usuario si las alarmas no están habilitadas. Usan el mismo almacenamiento que las
+// reproducciones programadas.
+func (handler *InteractionHandler) alarmsEnabled(ic *discordgo.InteractionCreate) bool {
+	if handler.schedules != nil {
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Las alarmas no están habilitadas"); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// GenerateAlarmsEmbed genera un embed con las alarmas pendientes.
+func GenerateAlarmsEmbed(alarms []scheduled.Schedule) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "⏰ Alarmas"}
+	if len(alarms) == 0 {
+		embed.Description = "No hay alarmas en este servidor"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, alarm := range alarms {
+		builder.WriteString(fmt.Sprintf("`%s` <t:%d:F> en <#%s>: **%s**", alarm.ID, alarm.At.Unix(), alarm.VoiceChannelID, alarm.Input))
+		if alarm.Volume > 0 {
+			builder.WriteString(fmt.Sprintf(" (%d%%)", alarm.Volume))
+		}
+		builder.WriteString(fmt.Sprintf(" · <@%s>\n", alarm.CreatedBy))
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
@@ -0,0 +1,182 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+// AliasCommands arma los comandos de Discord de los alias de comandos. Lo implementa SlashCommandRouter.
+type AliasCommands interface {
+	// AliasCommand arma el comando del alias y devuelve también el subcomando al que apunta, normalizado.
+	AliasCommand(alias, target string) (*discordgo.ApplicationCommand, string, error)
+}
+
+// WithAliases habilita los comandos /alias para que cada servidor cree sus propios atajos de los comandos.
+func (handler *InteractionHandler) WithAliases(commands AliasCommands) *InteractionHandler {
+	handler.aliasCommands = commands
+	return handler
+}
+
+// GuildAliases devuelve los alias de comandos del servidor. Es el resolver de alias de SlashCommandRouter.
+func (handler *InteractionHandler) GuildAliases(guildID string) map[string]string {
+	if handler.settings == nil {
+		return nil
+	}
+	return handler.guildSettings(guildID).Aliases
+}
+
+// AliasAdd crea o cambia un alias de un subcomando en el servidor y lo registra en Discord como un comando del
+// servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) AliasAdd(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AliasAdd")
+	if !handler.aliasesAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	alias, err := settings.ParseAlias(options["alias"].StringValue())
+	var command *discordgo.ApplicationCommand
+	var target string
+	if err == nil {
+		command, target, err = handler.aliasCommands.AliasCommand(alias, options["command"].StringValue())
+	}
+	aliases := handler.guildSettings(ic.GuildID).Aliases
+	if _, exists := aliases[alias]; err == nil && !exists && len(aliases) >= settings.MaxAliases {
+		err = errTooManyAliases
+	}
+	if err == nil {
+		_, err = s.ApplicationCommandCreate(s.State.User.ID, ic.GuildID, command)
+	}
+	if err == nil {
+		_, err = settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+			if g.Aliases == nil {
+				g.Aliases = make(map[string]string)
+			}
+			g.Aliases[alias] = target
+		})
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, settings.ErrInvalidValue):
+		message = "🤷🏽 El alias tiene que ser una sola palabra de hasta 32 letras, números, `-` o `_`"
+	case errors.Is(err, ErrAliasConflict):
+		message = fmt.Sprintf("🙅 `%s` ya es un comando del bot", alias)
+	case errors.Is(err, ErrUnknownCommand):
+		message = fmt.Sprintf("🤷🏽 No existe el comando `%s`. Usá el nombre del subcomando, como `play` o `playlist save`", options["command"].StringValue())
+	case errors.Is(err, errTooManyAliases):
+		message = fmt.Sprintf("🙅 El servidor ya tiene %d alias", settings.MaxAliases)
+	case err != nil:
+		logger.Error("falló al crear el alias", zap.String("alias", alias), zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al crear el alias")
+	default:
+		message = fmt.Sprintf("✅ Ahora `/%s` es `%s`", alias, handler.commandName(target))
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el alias", zap.Error(err))
+	}
+}
+
+// AliasRemove elimina un alias del servidor y su comando en Discord. Solo disponible para administradores.
+func (handler *InteractionHandler) AliasRemove(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AliasRemove")
+	if !handler.aliasesAdmin(ic) {
+		return
+	}
+
+	alias, err := settings.ParseAlias(commandOptions(opt)["alias"].StringValue())
+	if _, exists := handler.guildSettings(ic.GuildID).Aliases[alias]; err != nil || !exists {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 No encontré ese alias"); err != nil {
+			logger.Error("falló al responder con el error del alias", zap.Error(err))
+		}
+		return
+	}
+
+	message := fmt.Sprintf("🗑️ Alias `/%s` eliminado", alias)
+	err = deleteGuildCommand(s, ic.GuildID, alias)
+	if err == nil {
+		_, err = settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { delete(g.Aliases, alias) })
+	}
+	if err != nil {
+		logger.Error("falló al eliminar el alias", zap.String("alias", alias), zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al eliminar el alias")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el alias", zap.Error(err))
+	}
+}
+
+// AliasList muestra los alias de comandos del servidor.
+func (handler *InteractionHandler) AliasList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("AliasList")
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateAliasesEmbed(handler.GuildAliases(ic.GuildID), handler.commandName)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con los alias", zap.Error(err))
+	}
+}
+
+// errTooManyAliases indica que el servidor llegó a settings.MaxAliases.
+var errTooManyAliases = errors.New("el servidor tiene demasiados alias")
+
+// aliasesAdmin responde al usuario si no es administrador o si los alias no están habilitados. Los alias se guardan
+// en la configuración del servidor.
+func (handler *InteractionHandler) aliasesAdmin(ic *discordgo.InteractionCreate) bool {
+	if !handler.settingsAdmin(ic) {
+		return false
+	}
+	if handler.aliasCommands != nil {
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Los alias no están habilitados"); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// deleteGuildCommand elimina el comando del servidor con ese nombre, si está registrado.
+func deleteGuildCommand(s *discordgo.Session, guildID, name string) error {
+	commands, err := s.ApplicationCommands(s.State.User.ID, guildID)
+	if err != nil {
+		return fmt.Errorf("error al obtener los comandos del servidor: %w", err)
+	}
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return s.ApplicationCommandDelete(s.State.User.ID, guildID, cmd.ID)
+		}
+	}
+	return nil
+}
+
+// GenerateAliasesEmbed genera un embed con los alias de comandos del servidor, ordenados por nombre. commandName
+// devuelve cómo se escribe el subcomando al que apunta cada alias.
+func GenerateAliasesEmbed(aliases map[string]string, commandName func(string) string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "🔀 Alias"}
+	if len(aliases) == 0 {
+		embed.Description = "No hay alias en este servidor"
+		return embed
+	}
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	builder := strings.Builder{}
+	for _, alias := range names {
+		builder.WriteString(fmt.Sprintf("`/%s` → `%s`\n", alias, commandName(aliases[alias])))
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
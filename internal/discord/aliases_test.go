@@ -0,0 +1,77 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSlashCommandRouter_AliasCommand(t *testing.T) {
+	router := NewSlashCommandRouter("seso")
+
+	command, target, err := router.AliasCommand("p", "/seso play")
+	require.NoError(t, err)
+	assert.Equal(t, "play", target)
+	assert.Equal(t, "p", command.Name)
+	require.Len(t, command.Options, 1)
+	assert.Equal(t, "input", command.Options[0].Name)
+
+	_, target, err = router.AliasCommand("ps", "Playlist  Save")
+	require.NoError(t, err)
+	assert.Equal(t, "playlist save", target)
+
+	for _, alias := range []string{"seso", "play", "playlist"} {
+		_, _, err := router.AliasCommand(alias, "skip")
+		assert.ErrorIs(t, err, ErrAliasConflict, alias)
+	}
+	for _, target := range []string{"nope", "playlist", "playlist nope", "play input", ""} {
+		_, _, err := router.AliasCommand("x", target)
+		assert.ErrorIs(t, err, ErrUnknownCommand, target)
+	}
+}
+
+func TestSlashCommandRouter_HandleAlias(t *testing.T) {
+	var played, saved *discordgo.ApplicationCommandInteractionDataOption
+	router := NewSlashCommandRouter("seso").
+		PlayHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			played = opt
+		}).
+		PlaylistSaveHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			saved = opt
+		}).
+		AliasResolver(func(guildID string) map[string]string {
+			return map[string]string{"p": "play", "ps": "playlist save"}
+		})
+	input := &discordgo.ApplicationCommandInteractionDataOption{Name: "input", Type: discordgo.ApplicationCommandOptionString, Value: "lofi"}
+	interaction := func(name string) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "1",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:    name,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{input},
+			},
+		}}
+	}
+
+	router.HandleAlias(nil, interaction("p"))
+	require.NotNil(t, played)
+	assert.Equal(t, "lofi", commandOptions(played)["input"].StringValue())
+
+	router.HandleAlias(nil, interaction("ps"))
+	require.NotNil(t, saved)
+	assert.Equal(t, "save", saved.Name)
+
+	played = nil
+	router.HandleAlias(nil, interaction("np"))
+	assert.Nil(t, played)
+}
+
+func TestGenerateAliasesEmbed(t *testing.T) {
+	commandName := func(sub string) string { return CommandName("seso", "", sub) }
+	embed := GenerateAliasesEmbed(map[string]string{"p": "play", "np": "playing"}, commandName)
+	assert.Equal(t, "`/np` → `/seso playing`\n`/p` → `/seso play`", embed.Description)
+
+	assert.Equal(t, "No hay alias en este servidor", GenerateAliasesEmbed(nil, commandName).Description)
+}
@@ -0,0 +1,72 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"sort"
+)
+
+// guildNotAllowedMessage es el mensaje que deja el bot antes de salir de un servidor que no está permitido.
+const guildNotAllowedMessage = "👋 Esta instancia del bot es privada y no está habilitada para este servidor, así que me voy. Si querés usarlo, podés hospedar tu propia instancia: https://github.com/Tomas-vilte/GoMusicBot"
+
+// WithAllowedGuilds hace que el bot solo funcione en los servidores indicados: si lo agregan a otro, avisa por qué no
+// se queda y sale. Como GuildCreate también llega al conectarse, sale de los que ya estaba y no están permitidos.
+func (handler *InteractionHandler) WithAllowedGuilds(guildIDs []string) *InteractionHandler {
+	handler.allowedGuilds = make(map[string]struct{}, len(guildIDs))
+	for _, guildID := range guildIDs {
+		handler.allowedGuilds[guildID] = struct{}{}
+	}
+	return handler
+}
+
+// guildAllowed indica si el bot puede quedarse en el servidor. Sin servidores permitidos, puede quedarse en todos.
+func (handler *InteractionHandler) guildAllowed(guildID string) bool {
+	if len(handler.allowedGuilds) == 0 {
+		return true
+	}
+	_, ok := handler.allowedGuilds[guildID]
+	return ok
+}
+
+// leaveGuild avisa en el servidor que no está permitido y sale.
+func (handler *InteractionHandler) leaveGuild(s *discordgo.Session, guild *discordgo.Guild) {
+	logger := logging.WithFields(handler.logger, zap.String("guildID", guild.ID), zap.String("servidor", guild.Name))
+	logger.Warn("el servidor no está permitido; el bot sale")
+	for _, channelID := range noticeChannels(s, guild) {
+		if _, err := s.ChannelMessageSend(channelID, guildNotAllowedMessage); err == nil {
+			break
+		}
+	}
+	if err := s.GuildLeave(guild.ID); err != nil {
+		logger.Error("falló al salir del servidor no permitido", zap.Error(err))
+	}
+}
+
+// noticeChannels devuelve los canales de texto en los que se puede avisar algo al servidor, en orden de preferencia:
+// primero el canal del sistema y después los demás en el orden en que aparecen.
+func noticeChannels(s *discordgo.Session, guild *discordgo.Guild) []string {
+	channels := make([]*discordgo.Channel, 0, len(guild.Channels))
+	for _, channel := range guild.Channels {
+		if channel.Type != discordgo.ChannelTypeGuildText || channel.ID == guild.SystemChannelID {
+			continue
+		}
+		if s.State != nil && s.State.User != nil {
+			permissions, err := s.State.UserChannelPermissions(s.State.User.ID, channel.ID)
+			if err == nil && permissions&discordgo.PermissionSendMessages == 0 {
+				continue
+			}
+		}
+		channels = append(channels, channel)
+	}
+	sort.SliceStable(channels, func(i, j int) bool { return channels[i].Position < channels[j].Position })
+
+	var channelIDs []string
+	if guild.SystemChannelID != "" {
+		channelIDs = append(channelIDs, guild.SystemChannelID)
+	}
+	for _, channel := range channels {
+		channelIDs = append(channelIDs, channel.ID)
+	}
+	return channelIDs
+}
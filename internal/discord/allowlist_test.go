@@ -0,0 +1,31 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGuildAllowed(t *testing.T) {
+	handler := &InteractionHandler{}
+	assert.True(t, handler.guildAllowed("1"))
+
+	handler.WithAllowedGuilds([]string{"1", "2"})
+	assert.True(t, handler.guildAllowed("2"))
+	assert.False(t, handler.guildAllowed("3"))
+}
+
+func TestNoticeChannels(t *testing.T) {
+	guild := &discordgo.Guild{
+		ID:              "1",
+		SystemChannelID: "sistema",
+		Channels: []*discordgo.Channel{
+			{ID: "voz", Type: discordgo.ChannelTypeGuildVoice, Position: 0},
+			{ID: "general", Type: discordgo.ChannelTypeGuildText, Position: 2},
+			{ID: "sistema", Type: discordgo.ChannelTypeGuildText, Position: 3},
+			{ID: "reglas", Type: discordgo.ChannelTypeGuildText, Position: 1},
+		},
+	}
+
+	assert.Equal(t, []string{"sistema", "reglas", "general"}, noticeChannels(&discordgo.Session{}, guild))
+}
@@ -0,0 +1,193 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// spamMaxCooldown es la espera máxima, por más que alguien reincida.
+	spamMaxCooldown = time.Hour
+	// spamOffenseReset es cuánto tiempo sin reincidir hace falta para que la espera vuelva a la inicial.
+	spamOffenseReset = time.Hour
+	// spamSweepSize es la cantidad de usuarios registrados a partir de la cual se descartan los que ya no hacen falta.
+	spamSweepSize = 1000
+)
+
+// spamRequest es un pedido de canción de un usuario.
+type spamRequest struct {
+	at    time.Time
+	input string
+}
+
+// spamRecord son los pedidos recientes de un usuario en un servidor y su espera, si está frenado.
+type spamRecord struct {
+	requests     []spamRequest
+	blockedUntil time.Time
+	offenses     int
+	lastOffense  time.Time
+}
+
+// spamTracker lleva los pedidos de canciones de cada usuario de cada servidor para frenar a quien pide lo mismo
+// muchas veces seguidas o llena la lista. El valor cero está listo para usar.
+type spamTracker struct {
+	mu    sync.Mutex
+	users map[string]*spamRecord
+}
+
+// request registra un pedido de input. Si quien lo hizo ya estaba frenado, devuelve cuánto le falta esperar; si con
+// este pedido se pasa de los límites, lo frena y devuelve la espera con offense en true. Si puede pedir, devuelve 0.
+func (t *spamTracker) request(guildID, userID, input string, limits settings.AntiSpam, now time.Time) (wait time.Duration, offense bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.users == nil {
+		t.users = make(map[string]*spamRecord)
+	}
+	if len(t.users) >= spamSweepSize {
+		t.sweep(limits.Window, now)
+	}
+
+	key := guildID + ":" + userID
+	record, ok := t.users[key]
+	if !ok {
+		record = &spamRecord{}
+		t.users[key] = record
+	}
+	if now.Before(record.blockedUntil) {
+		return record.blockedUntil.Sub(now), false
+	}
+
+	input = strings.ToLower(strings.TrimSpace(input))
+	recent := record.requests[:0]
+	repeats := 1
+	for _, r := range record.requests {
+		if now.Sub(r.at) >= limits.Window {
+			continue
+		}
+		recent = append(recent, r)
+		if r.input == input {
+			repeats++
+		}
+	}
+	record.requests = append(recent, spamRequest{at: now, input: input})
+	if repeats <= limits.MaxRepeats && len(record.requests) <= limits.MaxRequests {
+		return 0, false
+	}
+
+	if now.Sub(record.lastOffense) >= spamOffenseReset {
+		record.offenses = 0
+	}
+	record.offenses++
+	record.lastOffense = now
+	record.requests = nil
+	wait = spamMaxCooldown
+	if shift := record.offenses - 1; shift < 16 && limits.Cooldown<<shift < spamMaxCooldown {
+		wait = limits.Cooldown << shift
+	}
+	record.blockedUntil = now.Add(wait)
+	return wait, true
+}
+
+// sweep descarta a los usuarios que no pidieron nada dentro de la ventana, no están frenados y ya no arrastran
+// reincidencias.
+func (t *spamTracker) sweep(window time.Duration, now time.Time) {
+	for key, record := range t.users {
+		idle := len(record.requests) == 0 || now.Sub(record.requests[len(record.requests)-1].at) >= window
+		if idle && !now.Before(record.blockedUntil) && now.Sub(record.lastOffense) >= spamOffenseReset {
+			delete(t.users, key)
+		}
+	}
+}
+
+// respondSpam aplica el anti-spam del servidor al pedido de input. Si quien lo hizo está pidiendo demasiado, le
+// responde cuánto tiene que esperar y devuelve true. Los administradores no tienen límites.
+func (handler *InteractionHandler) respondSpam(ic *discordgo.InteractionCreate, input string) bool {
+	limits := handler.guildSettings(ic.GuildID).AntiSpam.Limits()
+	if limits.Disabled || isGuildAdmin(ic.Member) {
+		return false
+	}
+	user := interactionUser(ic.Interaction)
+	if user == nil {
+		return false
+	}
+	wait, offense := handler.spam.request(ic.GuildID, user.ID, input, limits, time.Now())
+	if wait == 0 {
+		return false
+	}
+
+	logger := logging.WithFields(handler.logger, zap.String("guildID", ic.GuildID), zap.String("userID", user.ID))
+	message := fmt.Sprintf("⏳ Todavía tenés que esperar %s para pedir canciones", formatSpamWait(wait))
+	if offense {
+		logger.Info("Usuario frenado por el anti-spam", zap.String("input", input), zap.Duration("espera", wait))
+		message = fmt.Sprintf("🐢 Pará un poco: estás pidiendo demasiadas canciones, o la misma muchas veces. Podés volver a pedir en %s", formatSpamWait(wait))
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el aviso del anti-spam", zap.Error(err))
+	}
+	return true
+}
+
+// formatSpamWait redondea la espera a segundos, para que no se muestren fracciones.
+func formatSpamWait(wait time.Duration) string {
+	return wait.Round(time.Second).String()
+}
+
+// SetAntiSpam configura los límites del anti-spam del servidor. Sin opciones muestra los límites actuales. Solo
+// disponible para administradores.
+func (handler *InteractionHandler) SetAntiSpam(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetAntiSpam")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	var message string
+	guild, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		updateAntiSpam(&g.AntiSpam, options)
+	})
+	if err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	} else {
+		message = describeAntiSpam(guild.AntiSpam)
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+// updateAntiSpam aplica a los límites del anti-spam las opciones de /settings antispam.
+func updateAntiSpam(antiSpam *settings.AntiSpam, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if option, ok := options["enabled"]; ok {
+		antiSpam.Disabled = !option.BoolValue()
+	}
+	if option, ok := options["window"]; ok {
+		antiSpam.Window = time.Duration(option.IntValue()) * time.Second
+	}
+	if option, ok := options["repeats"]; ok {
+		antiSpam.MaxRepeats = int(option.IntValue())
+	}
+	if option, ok := options["requests"]; ok {
+		antiSpam.MaxRequests = int(option.IntValue())
+	}
+	if option, ok := options["cooldown"]; ok {
+		antiSpam.Cooldown = time.Duration(option.IntValue()) * time.Second
+	}
+}
+
+// describeAntiSpam describe los límites del anti-spam del servidor.
+func describeAntiSpam(antiSpam settings.AntiSpam) string {
+	if antiSpam.Disabled {
+		return "⚙️ Anti-spam deshabilitado"
+	}
+	limits := antiSpam.Limits()
+	return fmt.Sprintf("⚙️ Anti-spam: cada %s se puede pedir la misma canción hasta %d veces y hasta %d canciones en total. Quien se pasa espera %s, y el doble cada vez que reincide",
+		limits.Window, limits.MaxRepeats, limits.MaxRequests, limits.Cooldown)
+}
@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSpamTracker_Repeats(t *testing.T) {
+	var tracker spamTracker
+	limits := settings.AntiSpam{MaxRepeats: 2, Cooldown: 10 * time.Second}.Limits()
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		wait, _ := tracker.request("1", "u1", "La Bamba", limits, now)
+		assert.Zero(t, wait)
+	}
+	wait, offense := tracker.request("1", "u1", " la bamba ", limits, now)
+	assert.Equal(t, 10*time.Second, wait)
+	assert.True(t, offense)
+
+	// Mientras espera no puede pedir nada, y otros usuarios o servidores no se ven afectados.
+	wait, offense = tracker.request("1", "u1", "Otra", limits, now.Add(4*time.Second))
+	assert.Equal(t, 6*time.Second, wait)
+	assert.False(t, offense)
+	wait, _ = tracker.request("1", "u2", "La Bamba", limits, now)
+	assert.Zero(t, wait)
+	wait, _ = tracker.request("2", "u1", "La Bamba", limits, now)
+	assert.Zero(t, wait)
+}
+
+func TestSpamTracker_EscalatesAndWindow(t *testing.T) {
+	var tracker spamTracker
+	limits := settings.AntiSpam{Window: time.Minute, MaxRequests: 2, Cooldown: 10 * time.Second}.Limits()
+	now := time.Now()
+
+	// Fuera de la ventana los pedidos viejos no cuentan.
+	tracker.request("1", "u1", "a", limits, now)
+	tracker.request("1", "u1", "b", limits, now.Add(time.Second))
+	wait, _ := tracker.request("1", "u1", "c", limits, now.Add(2*time.Minute))
+	assert.Zero(t, wait)
+
+	now = now.Add(2 * time.Minute)
+	tracker.request("1", "u1", "d", limits, now)
+	wait, _ = tracker.request("1", "u1", "e", limits, now)
+	assert.Equal(t, 10*time.Second, wait)
+
+	// Si reincide, la espera se duplica.
+	now = now.Add(10 * time.Second)
+	for _, input := range []string{"f", "g"} {
+		wait, _ = tracker.request("1", "u1", input, limits, now)
+		assert.Zero(t, wait)
+	}
+	wait, _ = tracker.request("1", "u1", "h", limits, now)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Después de un rato sin reincidir vuelve a la espera inicial.
+	now = now.Add(spamOffenseReset)
+	for _, input := range []string{"i", "j"} {
+		tracker.request("1", "u1", input, limits, now)
+	}
+	wait, _ = tracker.request("1", "u1", "k", limits, now)
+	assert.Equal(t, 10*time.Second, wait)
+}
+
+func TestSpamTracker_MaxCooldown(t *testing.T) {
+	var tracker spamTracker
+	limits := settings.AntiSpam{MaxRepeats: 1, Cooldown: 40 * time.Minute}.Limits()
+	now := time.Now()
+
+	tracker.request("1", "u1", "a", limits, now)
+	wait, _ := tracker.request("1", "u1", "a", limits, now)
+	assert.Equal(t, 40*time.Minute, wait)
+
+	now = now.Add(wait)
+	tracker.request("1", "u1", "a", limits, now)
+	wait, _ = tracker.request("1", "u1", "a", limits, now)
+	assert.Equal(t, spamMaxCooldown, wait)
+}
+
+func TestUpdateAntiSpam(t *testing.T) {
+	var antiSpam settings.AntiSpam
+	assert.Equal(t, "⚙️ Anti-spam: cada 1m0s se puede pedir la misma canción hasta 3 veces y hasta 10 canciones en total. Quien se pasa espera 30s, y el doble cada vez que reincide", describeAntiSpam(antiSpam))
+
+	updateAntiSpam(&antiSpam, map[string]*discordgo.ApplicationCommandInteractionDataOption{
+		"window":   {Name: "window", Type: discordgo.ApplicationCommandOptionInteger, Value: 120.0},
+		"repeats":  {Name: "repeats", Type: discordgo.ApplicationCommandOptionInteger, Value: 2.0},
+		"cooldown": {Name: "cooldown", Type: discordgo.ApplicationCommandOptionInteger, Value: 60.0},
+	})
+	assert.Equal(t, settings.AntiSpam{Window: 2 * time.Minute, MaxRepeats: 2, Cooldown: time.Minute}, antiSpam)
+
+	updateAntiSpam(&antiSpam, map[string]*discordgo.ApplicationCommandInteractionDataOption{
+		"enabled": {Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: false},
+	})
+	assert.Equal(t, "⚙️ Anti-spam deshabilitado", describeAntiSpam(antiSpam))
+}
@@ -0,0 +1,115 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// AssistantSessions son las sesiones de un bot asistente: otra aplicación de Discord (con su propio token) que
+// reproduce en un canal de voz distinto del que ocupa el bot principal en el mismo servidor. Los asistentes no
+// registran comandos; los comandos del bot principal se dirigen al bot que está en el canal de voz del usuario.
+type AssistantSessions interface {
+	SessionForGuild(guildID string) *discordgo.Session
+}
+
+// assistant es un bot asistente con un reproductor por servidor. Cada reproductor tiene su propia lista de
+// reproducción, separada de la del bot principal.
+type assistant struct {
+	sessions AssistantSessions
+	players  map[GuildID]*bot.GuildPlayer
+}
+
+// WithAssistants agrega bots asistentes para reproducir en varios canales de voz de un servidor a la vez.
+func (handler *InteractionHandler) WithAssistants(assistants ...AssistantSessions) *InteractionHandler {
+	for _, sessions := range assistants {
+		handler.assistants = append(handler.assistants, &assistant{sessions: sessions, players: make(map[GuildID]*bot.GuildPlayer)})
+	}
+	return handler
+}
+
+// playerForVoiceChannel devuelve el reproductor que tiene que reproducir en el canal de voz: el asistente que ya
+// está en ese canal, el bot principal si está libre o ya está en ese canal o, si no, un asistente libre que esté
+// en el servidor. Si todos están ocupados, la canción se encola en el bot principal.
+func (handler *InteractionHandler) playerForVoiceChannel(main *bot.GuildPlayer, guildID, voiceChannelID string) *bot.GuildPlayer {
+	if len(handler.assistants) == 0 {
+		return main
+	}
+
+	handler.playersMu.Lock()
+	defer handler.playersMu.Unlock()
+	var idle *assistant
+	for _, a := range handler.assistants {
+		player, ok := a.players[GuildID(guildID)]
+		if ok && playingIn(player, voiceChannelID) {
+			return player
+		}
+		if idle == nil && (!ok || !player.IsPlaying()) && a.inGuild(guildID) {
+			idle = a
+		}
+	}
+	if idle == nil || !main.IsPlaying() || playingIn(main, voiceChannelID) {
+		return main
+	}
+	return handler.assistantPlayer(idle, guildID)
+}
+
+// memberPlayer devuelve el reproductor que manejan los comandos del usuario: el del asistente que está
+// reproduciendo en su canal de voz o, si no hay ninguno, el del bot principal.
+func (handler *InteractionHandler) memberPlayer(main *bot.GuildPlayer, guild *discordgo.Guild, member *discordgo.Member) *bot.GuildPlayer {
+	if len(handler.assistants) == 0 || member == nil || member.User == nil {
+		return main
+	}
+	vs := getUsersVoiceState(guild, member.User)
+	if vs == nil {
+		return main
+	}
+
+	handler.playersMu.RLock()
+	defer handler.playersMu.RUnlock()
+	for _, a := range handler.assistants {
+		if player, ok := a.players[GuildID(guild.ID)]; ok && playingIn(player, vs.ChannelID) {
+			return player
+		}
+	}
+	return main
+}
+
+// assistantPlayer devuelve el reproductor del asistente en el servidor y lo crea si no existe. Se llama con
+// playersMu tomado.
+func (handler *InteractionHandler) assistantPlayer(a *assistant, guildID string) *bot.GuildPlayer {
+	if player, ok := a.players[GuildID(guildID)]; ok {
+		return player
+	}
+	s := a.sessions.SessionForGuild(guildID)
+	// Los asistentes reproducen con el pipeline local: el cliente de Lavalink está autenticado como el bot principal.
+	player := handler.newGuildPlayer(GuildID(guildID), s, guildID+"-"+s.State.User.ID, nil)
+	a.players[GuildID(guildID)] = player
+	handler.logger.Info("Asistente asignado al servidor", zap.String("guildID", guildID), zap.String("assistantID", s.State.User.ID))
+	player.StartListeningEvents(s)
+	go func() {
+		if err := player.Run(handler.ctx); err != nil {
+			handler.logger.Error("ocurrió un error al ejecutar el reproductor del asistente", zap.Error(err))
+		}
+	}()
+	return player
+}
+
+// inGuild indica si el asistente fue agregado al servidor.
+func (a *assistant) inGuild(guildID string) bool {
+	s := a.sessions.SessionForGuild(guildID)
+	if s == nil || s.State == nil || s.State.User == nil {
+		return false
+	}
+	_, err := s.State.Guild(guildID)
+	return err == nil
+}
+
+// playingIn indica si el reproductor está sonando en el canal de voz.
+func playingIn(player *bot.GuildPlayer, voiceChannelID string) bool {
+	if !player.IsPlaying() {
+		return false
+	}
+	current, err := player.GetVoiceChannel()
+	return err == nil && current == voiceChannelID
+}
@@ -0,0 +1,64 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeAssistantSessions struct {
+	session *discordgo.Session
+}
+
+func (f *fakeAssistantSessions) SessionForGuild(string) *discordgo.Session {
+	return f.session
+}
+
+// newAssistantSession crea una sesión con el estado de un bot que está en los servidores indicados.
+func newAssistantSession(t *testing.T, userID string, guildIDs ...string) *discordgo.Session {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: userID}
+	for _, guildID := range guildIDs {
+		assert.NoError(t, state.GuildAdd(&discordgo.Guild{ID: guildID}))
+	}
+	return &discordgo.Session{State: state}
+}
+
+func TestPlayerForVoiceChannel_WithoutAssistants(t *testing.T) {
+	handler := &InteractionHandler{logger: new(MockLogger)}
+	main := &bot.GuildPlayer{}
+
+	assert.Same(t, main, handler.playerForVoiceChannel(main, "1", "voz"))
+}
+
+func TestPlayerForVoiceChannel_MainIdle(t *testing.T) {
+	handler := (&InteractionHandler{logger: new(MockLogger)}).
+		WithAssistants(&fakeAssistantSessions{session: newAssistantSession(t, "asistente", "1")})
+	main := &bot.GuildPlayer{}
+
+	assert.Same(t, main, handler.playerForVoiceChannel(main, "1", "voz"))
+	assert.Empty(t, handler.assistants[0].players)
+}
+
+func TestMemberPlayer(t *testing.T) {
+	assistantPlayer := &bot.GuildPlayer{}
+	handler := (&InteractionHandler{logger: new(MockLogger)}).
+		WithAssistants(&fakeAssistantSessions{session: newAssistantSession(t, "asistente", "1")})
+	handler.assistants[0].players["1"] = assistantPlayer
+	main := &bot.GuildPlayer{}
+	guild := &discordgo.Guild{ID: "1", VoiceStates: []*discordgo.VoiceState{{UserID: "usuario", ChannelID: "voz"}}}
+
+	// El asistente no está reproduciendo, así que los comandos los maneja el bot principal.
+	assert.Same(t, main, handler.memberPlayer(main, guild, &discordgo.Member{User: &discordgo.User{ID: "usuario"}}))
+	assert.Same(t, main, handler.memberPlayer(main, guild, &discordgo.Member{User: &discordgo.User{ID: "otro"}}))
+	assert.Same(t, main, handler.memberPlayer(main, guild, nil))
+}
+
+func TestAssistant_InGuild(t *testing.T) {
+	a := &assistant{sessions: &fakeAssistantSessions{session: newAssistantSession(t, "asistente", "1")}}
+
+	assert.True(t, a.inGuild("1"))
+	assert.False(t, a.inGuild("2"))
+	assert.False(t, (&assistant{sessions: &fakeAssistantSessions{}}).inGuild("1"))
+}
@@ -0,0 +1,214 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+const (
+	defaultAuditLimit = 15
+	maxAuditLimit     = 50
+)
+
+// AuditLog define los métodos para registrar y consultar la auditoría del bot.
+type AuditLog interface {
+	audit.Recorder
+	Query(filter audit.Filter) ([]audit.Entry, error)
+}
+
+// AuditSessionService es un SessionService que registra en la auditoría cada respuesta que el bot
+// envía a una interacción, junto con el comando, los argumentos y el usuario que la originó.
+type AuditSessionService struct {
+	session  SessionService
+	recorder audit.Recorder
+}
+
+// NewAuditSessionService crea una nueva instancia de AuditSessionService.
+func NewAuditSessionService(session SessionService, recorder audit.Recorder) *AuditSessionService {
+	return &AuditSessionService{
+		session:  session,
+		recorder: recorder,
+	}
+}
+
+// InteractionRespond responde a la interacción y registra el resultado. Las respuestas diferidas
+// no se registran porque el resultado real llega en el mensaje de seguimiento.
+func (s *AuditSessionService) InteractionRespond(i *discordgo.Interaction, r *discordgo.InteractionResponse) error {
+	err := s.session.InteractionRespond(i, r)
+	if r.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		s.record(i, summarizeResponse(r.Data), err)
+	}
+	return err
+}
+
+// FollowupMessageCreate crea el mensaje de seguimiento y registra el resultado.
+func (s *AuditSessionService) FollowupMessageCreate(i *discordgo.Interaction, wait bool, params *discordgo.WebhookParams) (*discordgo.Message, error) {
+	msg, err := s.session.FollowupMessageCreate(i, wait, params)
+	if params != nil {
+		s.record(i, summarizeMessage(params.Content, params.Embeds), err)
+	}
+	return msg, err
+}
+
+func (s *AuditSessionService) record(i *discordgo.Interaction, result string, err error) {
+	command, args := describeInteraction(i)
+	if err != nil {
+		result = fmt.Sprintf("%s (error: %v)", result, err)
+	}
+	entry := audit.Entry{
+		Type:    audit.EntryTypeCommand,
+		GuildID: i.GuildID,
+		Command: command,
+		Args:    args,
+		Result:  result,
+	}
+	if user := interactionUser(i); user != nil {
+		entry.UserID = user.ID
+		entry.UserName = user.Username
+	}
+	s.recorder.Record(entry)
+}
+
+// describeInteraction devuelve el nombre del comando y sus argumentos en formato clave=valor.
+func describeInteraction(i *discordgo.Interaction) (string, string) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		data := i.ApplicationCommandData()
+		if len(data.Options) == 0 {
+			return data.Name, ""
+		}
+		sub := data.Options[0]
+		if sub.Value != nil {
+			// Con namespace o con un alias, el comando no tiene subcomando y las opciones son los argumentos.
+			sub = &discordgo.ApplicationCommandInteractionDataOption{Name: data.Name, Options: data.Options}
+		}
+		name := sub.Name
+		if sub.Type == discordgo.ApplicationCommandOptionSubCommandGroup && len(sub.Options) > 0 {
+			// En los grupos, como /queue export, el subcomando viene como única opción del grupo.
+			sub = sub.Options[0]
+			name += " " + sub.Name
+		}
+		args := make([]string, 0, len(sub.Options))
+		for _, opt := range sub.Options {
+			args = append(args, fmt.Sprintf("%s=%v", opt.Name, opt.Value))
+		}
+		return name, strings.Join(args, " ")
+	case discordgo.InteractionMessageComponent:
+		data := i.MessageComponentData()
+		return data.CustomID, strings.Join(data.Values, ",")
+	default:
+		return i.Type.String(), ""
+	}
+}
+
+// interactionUser devuelve el usuario que originó la interacción.
+func interactionUser(i *discordgo.Interaction) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+func summarizeResponse(data *discordgo.InteractionResponseData) string {
+	if data == nil {
+		return ""
+	}
+	return summarizeMessage(data.Content, data.Embeds)
+}
+
+func summarizeMessage(content string, embeds []*discordgo.MessageEmbed) string {
+	if content != "" {
+		return content
+	}
+	if len(embeds) > 0 && embeds[0] != nil {
+		return embeds[0].Title
+	}
+	return ""
+}
+
+// AuditLogCommand muestra las últimas entradas de auditoría del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) AuditLogCommand(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("AuditLog")
+	if !isGuildAdmin(ic.Member) {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, ErrorMessageAdminOnly); err != nil {
+			handler.logger.Error("falló al responder con el error de permisos", zap.Error(err))
+		}
+		return
+	}
+	if handler.auditLog == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "La auditoría no está habilitada"); err != nil {
+			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	filter := audit.Filter{GuildID: ic.GuildID, Limit: defaultAuditLimit}
+	for _, option := range opt.Options {
+		switch option.Name {
+		case "user":
+			filter.UserID = option.UserValue(nil).ID
+		case "command":
+			filter.Command = option.StringValue()
+		case "limit":
+			filter.Limit = min(max(int(option.IntValue()), 1), maxAuditLimit)
+		}
+	}
+
+	entries, err := handler.auditLog.Query(filter)
+	if err != nil {
+		handler.logger.Error("falló al consultar la auditoría", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Ocurrió un error al consultar la auditoría"); err != nil {
+			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateAuditLogEmbed(entries)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		handler.logger.Error("falló al responder con la auditoría", zap.Error(err))
+	}
+}
+
+// GenerateAuditLogEmbed genera un embed con las entradas de auditoría.
+func GenerateAuditLogEmbed(entries []audit.Entry) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "📋 Auditoría"}
+	if len(entries) == 0 {
+		embed.Description = "No hay entradas de auditoría para mostrar"
+		return embed
+	}
+
+	builder := strings.Builder{}
+	for _, entry := range entries {
+		line := formatAuditEntry(entry)
+		if len(line)+builder.Len() > 4000 {
+			builder.WriteString("...")
+			break
+		}
+		builder.WriteString(line)
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
+
+func formatAuditEntry(entry audit.Entry) string {
+	actor := entry.UserName
+	if actor == "" {
+		actor = "bot"
+	}
+	line := fmt.Sprintf("<t:%d:f> **%s** `%s`", entry.Timestamp.Unix(), actor, entry.Command)
+	if entry.Args != "" {
+		line += fmt.Sprintf(" %s", entry.Args)
+	}
+	if entry.Result != "" {
+		line += fmt.Sprintf(" → %s", entry.Result)
+	}
+	return line + "\n"
+}
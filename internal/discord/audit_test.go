@@ -0,0 +1,68 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newPlayCommandInteraction() *discordgo.Interaction {
+	return &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "guild",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: "user", Username: "butakero"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "bot",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "play",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "input", Type: discordgo.ApplicationCommandOptionString, Value: "la bamba"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestAuditSessionService_RecordsFollowup(t *testing.T) {
+	mockSession := new(MockSessionService)
+	store := audit.NewInMemoryStore()
+	auditor := audit.NewAuditor(store, 0, new(MockLogger))
+	service := NewAuditSessionService(mockSession, auditor)
+
+	interaction := newPlayCommandInteraction()
+	params := &discordgo.WebhookParams{Embeds: []*discordgo.MessageEmbed{{Title: "La Bamba"}}}
+	mockSession.On("FollowupMessageCreate", interaction, true, params).Return(&discordgo.Message{}, nil)
+
+	_, err := service.FollowupMessageCreate(interaction, true, params)
+	assert.NoError(t, err)
+
+	entries, err := store.Query(audit.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, audit.EntryTypeCommand, entries[0].Type)
+	assert.Equal(t, "guild", entries[0].GuildID)
+	assert.Equal(t, "user", entries[0].UserID)
+	assert.Equal(t, "play", entries[0].Command)
+	assert.Equal(t, "input=la bamba", entries[0].Args)
+	assert.Equal(t, "La Bamba", entries[0].Result)
+}
+
+func TestAuditSessionService_SkipsDeferredResponses(t *testing.T) {
+	mockSession := new(MockSessionService)
+	store := audit.NewInMemoryStore()
+	service := NewAuditSessionService(mockSession, audit.NewAuditor(store, 0, new(MockLogger)))
+
+	interaction := newPlayCommandInteraction()
+	response := &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource}
+	mockSession.On("InteractionRespond", interaction, response).Return(nil)
+
+	assert.NoError(t, service.InteractionRespond(interaction, response))
+
+	entries, err := store.Query(audit.Filter{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
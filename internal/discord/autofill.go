@@ -0,0 +1,117 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// autoFillSource elige las canciones que agrega un relleno automático. Lo implementan la radio y el party shuffle.
+type autoFillSource interface {
+	// Songs devuelve hasta count canciones para agregar a la lista del servidor. exclude son las URLs de las
+	// canciones de la lista y de la que está sonando.
+	Songs(ctx context.Context, guildID string, count int, exclude map[string]bool) ([]*voice.Song, error)
+}
+
+// autoFill es el relleno automático activo de un servidor: mientras está activo, el bot mantiene la lista de
+// reproducción con ahead canciones pendientes elegidas por source. Cada servidor tiene a lo sumo uno, así que
+// activar la radio reemplaza al party shuffle y viceversa.
+type autoFill struct {
+	source         autoFillSource
+	ahead          int
+	voiceChannelID string
+	textChannelID  string
+	mu             sync.Mutex // mu evita que se llene la lista dos veces a la vez.
+}
+
+// startAutoFill activa el relleno automático del servidor, reemplazando al que hubiera.
+func (handler *InteractionHandler) startAutoFill(guildID string, fill *autoFill) {
+	handler.autoFills.Store(guildID, fill)
+}
+
+// stopAutoFill desactiva el relleno automático del servidor si is acepta su fuente. Devuelve false si no había
+// uno así activo.
+func (handler *InteractionHandler) stopAutoFill(guildID string, is func(autoFillSource) bool) bool {
+	value, ok := handler.autoFills.Load(guildID)
+	if !ok || !is(value.(*autoFill).source) {
+		return false
+	}
+	return handler.autoFills.CompareAndDelete(guildID, value)
+}
+
+// refillAutoFill agrega canciones a la lista de reproducción del servidor hasta que tenga las pendientes que pide
+// su relleno automático, si hay uno activo.
+func (handler *InteractionHandler) refillAutoFill(ctx context.Context, guildID string) error {
+	value, ok := handler.autoFills.Load(guildID)
+	if !ok {
+		return nil
+	}
+	fill := value.(*autoFill)
+	if !fill.mu.TryLock() {
+		// Ya la está llenando otro evento.
+		return nil
+	}
+	defer fill.mu.Unlock()
+
+	main, err := handler.playerFor(guildID)
+	if err != nil {
+		return err
+	}
+	player := handler.playerForVoiceChannel(main, guildID, fill.voiceChannelID)
+	queue, err := player.GetSongs()
+	if err != nil {
+		return err
+	}
+	need := fill.ahead - len(queue)
+	if need <= 0 {
+		return nil
+	}
+
+	exclude := make(map[string]bool, len(queue)+1)
+	for _, song := range queue {
+		exclude[song.URL] = true
+	}
+	if played, err := player.GetPlayedSong(); err == nil && played != nil {
+		exclude[played.URL] = true
+	}
+	songs, err := fill.source.Songs(ctx, guildID, need, exclude)
+	if err != nil {
+		return err
+	}
+	var rejected *bot.RejectedSongsError
+	if err := player.AddSong(ctx, &fill.textChannelID, &fill.voiceChannelID, songs...); err != nil && !errors.As(err, &rejected) {
+		return err
+	}
+	return nil
+}
+
+// autoFillPublisher reenvía los eventos de un reproductor y, mientras el servidor tiene un relleno automático
+// activo, vuelve a llenar la lista en otra goroutine cuando empieza una canción o cambia la lista. Al detener la
+// reproducción lo desactiva.
+type autoFillPublisher struct {
+	handler *InteractionHandler
+	next    events.Publisher // next es opcional; es el publicador que se usaría sin el relleno automático.
+}
+
+func (p *autoFillPublisher) Publish(event events.Event) {
+	if p.next != nil {
+		p.next.Publish(event)
+	}
+	switch event.Type {
+	case events.TypePlaybackStopped:
+		p.handler.autoFills.Delete(event.GuildID)
+	case events.TypeTrackStarted, events.TypeQueueChanged:
+		if _, ok := p.handler.autoFills.Load(event.GuildID); !ok {
+			return
+		}
+		go func() {
+			if err := p.handler.refillAutoFill(p.handler.ctx, event.GuildID); err != nil {
+				p.handler.logger.Warn("falló al llenar la lista automáticamente", zap.String("guildID", event.GuildID), zap.Error(err))
+			}
+		}()
+	}
+}
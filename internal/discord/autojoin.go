@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// autoJoinRequester es el nombre con el que se agregan las canciones de la lista que se reproduce al entrar solo.
+const autoJoinRequester = "Auto-join"
+
+// SetAutoJoin configura el canal de voz al que el bot entra solo cuando alguien se conecta y la lista guardada que
+// reproduce. Sin canal, lo deshabilita. Solo disponible para administradores.
+func (handler *InteractionHandler) SetAutoJoin(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetAutoJoin")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	autoJoin := settings.AutoJoin{TextChannelID: ic.ChannelID}
+	if option, ok := options["channel"]; ok {
+		autoJoin.ChannelID = option.Value.(string)
+	}
+	if option, ok := options["playlist"]; ok {
+		autoJoin.Playlist = strings.TrimSpace(option.StringValue())
+	}
+	message := "⚙️ Entrada automática deshabilitada"
+	if autoJoin.ChannelID != "" {
+		if autoJoin.Playlist == "" {
+			if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Elegí la lista guardada que se reproduce al entrar"); err != nil {
+				logger.Error("falló al responder con el error del servidor", zap.Error(err))
+			}
+			return
+		}
+		if !handler.savedPlaylistsEnabled(ic) {
+			return
+		}
+		playlist, err := handler.savedPlaylists.Get(ic.GuildID, autoJoin.Playlist)
+		if err != nil {
+			handler.respondSavedPlaylistError(ic, err)
+			return
+		}
+		autoJoin.Playlist = playlist.Name
+		message = fmt.Sprintf("⚙️ Cuando alguien entre a <#%s> me conecto y pongo **%s**; las canciones se anuncian en este canal", autoJoin.ChannelID, playlist.Name)
+	}
+
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.AutoJoin = autoJoin }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+// AutoJoin se llama cuando alguien entra o sale de un canal de voz. Si el servidor tiene entrada automática y el
+// canal configurado pasa de vacío a ocupado, el bot entra y reproduce la lista guardada; si vuelve a quedar vacío,
+// detiene la reproducción y sale.
+func (handler *InteractionHandler) AutoJoin(s *discordgo.Session, vs *discordgo.VoiceStateUpdate) {
+	if handler.settings == nil || handler.savedPlaylists == nil || s.State.User == nil || vs.UserID == s.State.User.ID {
+		return
+	}
+	if vs.Member != nil && vs.Member.User != nil && vs.Member.User.Bot {
+		return
+	}
+	before := ""
+	if vs.BeforeUpdate != nil {
+		before = vs.BeforeUpdate.ChannelID
+	}
+	if before == vs.ChannelID {
+		// Cambió otra cosa del estado de voz, como el silencio.
+		return
+	}
+	autoJoin := handler.guildSettings(vs.GuildID).AutoJoin
+	if !autoJoin.Enabled() || (vs.ChannelID != autoJoin.ChannelID && before != autoJoin.ChannelID) {
+		return
+	}
+
+	logger := logging.WithFields(handler.logger, zap.String("guildID", vs.GuildID), zap.String("canal", autoJoin.ChannelID))
+	g, err := s.State.Guild(vs.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener el servidor", zap.Error(err))
+		return
+	}
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, autoJoin.ChannelID)
+	switch listeners := voiceChannelListeners(g, autoJoin.ChannelID, s.State.User.ID); {
+	case vs.ChannelID == autoJoin.ChannelID && listeners == 1:
+		handler.startAutoJoin(logger, player, g.ID, autoJoin)
+	case before == autoJoin.ChannelID && listeners == 0 && playingIn(player, autoJoin.ChannelID):
+		logger.Info("el canal de entrada automática quedó vacío, se detiene la reproducción")
+		if err := player.Stop(); err != nil {
+			logger.Error("falló al detener la reproducción", zap.Error(err))
+		}
+	}
+}
+
+// startAutoJoin agrega la lista guardada de la entrada automática a la lista de reproducción del canal. Si el
+// reproductor ya está sonando en otro canal, no hace nada.
+func (handler *InteractionHandler) startAutoJoin(logger logging.Logger, player *bot.GuildPlayer, guildID string, autoJoin settings.AutoJoin) {
+	if player.IsPlaying() {
+		logger.Info("el reproductor ya está sonando, no se entra al canal de entrada automática")
+		return
+	}
+	playlist, err := handler.savedPlaylists.Get(guildID, autoJoin.Playlist)
+	if err != nil {
+		logger.Error("falló al obtener la lista de entrada automática", zap.String("playlist", autoJoin.Playlist), zap.Error(err))
+		return
+	}
+	logger.Info("entrando al canal de entrada automática", zap.String("playlist", playlist.Name))
+	var rejected *bot.RejectedSongsError
+	if err := player.AddSong(handler.ctx, &autoJoin.TextChannelID, &autoJoin.ChannelID, playlist.VoiceSongs(autoJoinRequester)...); errors.As(err, &rejected) {
+		logger.Info("canciones de la lista de entrada automática rechazadas", zap.Int("cantidad", len(rejected.Rejections)))
+	} else if err != nil {
+		logger.Error("falló al agregar la lista de entrada automática", zap.Error(err))
+	}
+}
+
+// voiceChannelListeners cuenta las personas conectadas al canal de voz, sin contar al bot ni a otros bots.
+func voiceChannelListeners(g *discordgo.Guild, channelID, botID string) int {
+	return len(voiceChannelMembers(g, channelID, botID))
+}
+
+// voiceChannelMembers devuelve los IDs de las personas conectadas al canal de voz, sin el bot ni otros bots.
+func voiceChannelMembers(g *discordgo.Guild, channelID, botID string) []string {
+	var members []string
+	for _, vs := range g.VoiceStates {
+		if vs.ChannelID != channelID || vs.UserID == botID {
+			continue
+		}
+		if vs.Member != nil && vs.Member.User != nil && vs.Member.User.Bot {
+			continue
+		}
+		members = append(members, vs.UserID)
+	}
+	return members
+}
@@ -0,0 +1,21 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVoiceChannelListeners(t *testing.T) {
+	g := &discordgo.Guild{VoiceStates: []*discordgo.VoiceState{
+		{UserID: "bot", ChannelID: "voz"},
+		{UserID: "ana", ChannelID: "voz"},
+		{UserID: "otro-bot", ChannelID: "voz", Member: &discordgo.Member{User: &discordgo.User{ID: "otro-bot", Bot: true}}},
+		{UserID: "juan", ChannelID: "voz", Member: &discordgo.Member{User: &discordgo.User{ID: "juan"}}},
+		{UserID: "pedro", ChannelID: "otro"},
+	}}
+
+	assert.Equal(t, 2, voiceChannelListeners(g, "voz", "bot"))
+	assert.Equal(t, 1, voiceChannelListeners(g, "otro", "bot"))
+	assert.Equal(t, 0, voiceChannelListeners(g, "vacio", "bot"))
+}
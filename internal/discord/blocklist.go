@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+const (
+	// maxEmbedFieldLength es el largo máximo que Discord acepta en el valor de un campo de un embed.
+	maxEmbedFieldLength = 1024
+	// maxEmbedDescriptionLength es el largo máximo que Discord acepta en la descripción de un embed.
+	maxEmbedDescriptionLength = 4096
+)
+
+// rejectSong es el filtro del reproductor que rechaza las canciones que no permite la configuración del servidor,
+// como las bloqueadas o las explícitas.
+func (handler *InteractionHandler) rejectSong(guildID string, song *voice.Song) string {
+	return handler.guildSettings(guildID).Reject(song)
+}
+
+// BlocklistAdd bloquea una canción, un canal o una palabra en el servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) BlocklistAdd(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("BlocklistAdd")
+	handler.updateBlocklist(ic, opt, func(b *settings.Blocklist, kind settings.BlockKind, value string) string {
+		if !b.Add(kind, value) {
+			return fmt.Sprintf("🤷🏽 `%s` ya estaba bloqueado", value)
+		}
+		return fmt.Sprintf("🚫 Bloqueado: %s `%s`", describeBlockKind(kind), value)
+	})
+}
+
+// BlocklistRemove desbloquea una canción, un canal o una palabra en el servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) BlocklistRemove(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("BlocklistRemove")
+	handler.updateBlocklist(ic, opt, func(b *settings.Blocklist, kind settings.BlockKind, value string) string {
+		if !b.Remove(kind, value) {
+			return fmt.Sprintf("🤷🏽 `%s` no estaba bloqueado", value)
+		}
+		return fmt.Sprintf("✅ Desbloqueado: %s `%s`", describeBlockKind(kind), value)
+	})
+}
+
+// BlocklistList muestra lo que está bloqueado en el servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) BlocklistList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("BlocklistList")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateBlocklistEmbed(handler.guildSettings(ic.GuildID).Blocklist)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con la lista de bloqueo", zap.Error(err))
+	}
+}
+
+// updateBlocklist normaliza la entrada pedida, aplica update sobre la lista de bloqueo del servidor y responde con
+// el mensaje que devuelve.
+func (handler *InteractionHandler) updateBlocklist(ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption, update func(b *settings.Blocklist, kind settings.BlockKind, value string) string) {
+	ctx, logger := handler.requestContext(ic)
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	kind := settings.BlockKind(options["type"].StringValue())
+	value, err := settings.NormalizeBlock(kind, options["value"].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Opción no válida"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	var message string
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		message = update(&g.Blocklist, kind, value)
+	}); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista de bloqueo", zap.Error(err))
+	}
+}
+
+// settingsAdmin responde al usuario si no es administrador o si la configuración por servidor no está habilitada.
+func (handler *InteractionHandler) settingsAdmin(ic *discordgo.InteractionCreate) bool {
+	message := ""
+	switch {
+	case !isGuildAdmin(ic.Member):
+		message = ErrorMessageAdminOnly
+	case handler.settings == nil:
+		message = "La configuración por servidor no está habilitada"
+	default:
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// GenerateBlocklistEmbed genera un embed con las canciones, canales y palabras bloqueadas en el servidor.
+func GenerateBlocklistEmbed(blocklist settings.Blocklist) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "🚫 Lista de bloqueo"}
+	if blocklist.Len() == 0 {
+		embed.Description = "No hay nada bloqueado en este servidor"
+		return embed
+	}
+	for _, field := range []struct {
+		name    string
+		entries []string
+	}{
+		{"Canciones", blocklist.Videos},
+		{"Canales", blocklist.Channels},
+		{"Palabras", blocklist.Keywords},
+	} {
+		if len(field.entries) == 0 {
+			continue
+		}
+		value := "`" + strings.Join(field.entries, "`, `") + "`"
+		if runes := []rune(value); len(runes) > maxEmbedFieldLength {
+			value = string(runes[:maxEmbedFieldLength-3]) + "..."
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: field.name, Value: value})
+	}
+	return embed
+}
+
+func describeBlockKind(kind settings.BlockKind) string {
+	switch kind {
+	case settings.BlockVideo:
+		return "canción"
+	case settings.BlockChannel:
+		return "canal"
+	default:
+		return "palabra"
+	}
+}
@@ -0,0 +1,53 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/inmemory_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRejectSong_PlayerFilter(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	store := settings.NewInMemoryStore()
+	_ = store.Put(settings.Guild{GuildID: "1", Blocklist: settings.Blocklist{Keywords: []string{"nightcore"}}})
+	handler := (&InteractionHandler{ctx: context.Background(), logger: logger}).WithSettings(store)
+
+	songStorage := inmemory_storage.NewInmemorySongStorage(logger)
+	player := bot.NewGuildPlayer(context.Background(), "1", nil, songStorage, inmemory_storage.NewInmemoryStateStorage(logger), nil, nil, logger).
+		WithSongFilter(bot.SongFilterFunc(handler.rejectSong))
+
+	blocked := &voice.Song{Title: "Tema (Nightcore)", URL: "https://youtu.be/abc"}
+	allowed := &voice.Song{Title: "Tema", URL: "https://youtu.be/def"}
+	textChannel, voiceChannel := "texto", "voz"
+	err := player.AddSong(context.Background(), &textChannel, &voiceChannel, blocked, allowed)
+
+	var rejected *bot.RejectedSongsError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, 1, rejected.Added)
+	require.Len(t, rejected.Rejections, 1)
+	assert.Same(t, blocked, rejected.Rejections[0].Song)
+	assert.Contains(t, rejected.Rejections[0].Reason, "nightcore")
+	assert.Equal(t, []*voice.Song{allowed}, addedSongs([]*voice.Song{blocked, allowed}, rejected))
+
+	songs, err := player.GetSongs()
+	require.NoError(t, err)
+	assert.Equal(t, []*voice.Song{allowed}, songs)
+}
+
+func TestGenerateBlocklistEmbed(t *testing.T) {
+	assert.Empty(t, GenerateBlocklistEmbed(settings.Blocklist{}).Fields)
+
+	embed := GenerateBlocklistEmbed(settings.Blocklist{Videos: []string{"youtube:abc"}, Keywords: []string{"remix", "nightcore"}})
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "Canciones", embed.Fields[0].Name)
+	assert.Equal(t, "`remix`, `nightcore`", embed.Fields[1].Value)
+}
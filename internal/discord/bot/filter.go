@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"strings"
+)
+
+// SongFilter decide si una canción se puede agregar a la lista de reproducción de un servidor. Devuelve el motivo
+// del rechazo, o una cadena vacía si la canción se puede agregar.
+type SongFilter interface {
+	Reject(guildID string, song *voice.Song) string
+}
+
+// SongFilterFunc permite usar una función como SongFilter.
+type SongFilterFunc func(guildID string, song *voice.Song) string
+
+func (f SongFilterFunc) Reject(guildID string, song *voice.Song) string {
+	return f(guildID, song)
+}
+
+// Rejection es una canción que un filtro no dejó agregar.
+type Rejection struct {
+	Song   *voice.Song
+	Reason string
+}
+
+// RejectedSongsError indica que AddSong no agregó algunas canciones porque las rechazó un filtro. Las demás sí se
+// agregaron.
+type RejectedSongsError struct {
+	Rejections []Rejection
+	Added      int
+}
+
+func (e *RejectedSongsError) Error() string {
+	reasons := make([]string, 0, len(e.Rejections))
+	for _, r := range e.Rejections {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", r.Song.GetHumanName(), r.Reason))
+	}
+	return fmt.Sprintf("canciones rechazadas (%s)", strings.Join(reasons, "; "))
+}
+
+// Rejected indica si la canción está entre las rechazadas.
+func (e *RejectedSongsError) Rejected(song *voice.Song) bool {
+	for _, r := range e.Rejections {
+		if r.Song == song {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSongFilter agrega un filtro que se aplica a todas las canciones antes de agregarlas a la lista de
+// reproducción, vengan de un comando, de la API o de otra instancia. Los filtros se aplican en el orden en que se
+// agregaron y gana el primero que rechaza.
+func (p *GuildPlayer) WithSongFilter(f SongFilter) *GuildPlayer {
+	p.songFilters = append(p.songFilters, f)
+	return p
+}
+
+// filterSongs separa las canciones que se pueden agregar de las que rechazó algún filtro.
+func (p *GuildPlayer) filterSongs(songs []*voice.Song) ([]*voice.Song, []Rejection) {
+	if len(p.songFilters) == 0 {
+		return songs, nil
+	}
+	allowed := make([]*voice.Song, 0, len(songs))
+	var rejections []Rejection
+	for _, song := range songs {
+		if reason := p.rejectReason(song); reason != "" {
+			rejections = append(rejections, Rejection{Song: song, Reason: reason})
+			continue
+		}
+		allowed = append(allowed, song)
+	}
+	return allowed, rejections
+}
+
+func (p *GuildPlayer) rejectReason(song *voice.Song) string {
+	for _, f := range p.songFilters {
+		if reason := f.Reject(p.guildID, song); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
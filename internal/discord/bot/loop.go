@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"io"
+)
+
+// LoopMode es el modo de repetición del reproductor.
+type LoopMode string
+
+const (
+	LoopOff   LoopMode = "off"   // LoopOff no repite: cada canción suena una vez.
+	LoopTrack LoopMode = "track" // LoopTrack repite la canción actual hasta saltarla o cambiar de modo.
+	LoopQueue LoopMode = "queue" // LoopQueue vuelve a agregar al final de la lista cada canción que termina.
+)
+
+// loopAudioMaxSize es el tamaño máximo del audio que se guarda para repetir una canción sin volver a descargarla
+// (unos 50 minutos de audio). Las canciones más largas se vuelven a pedir en cada repetición.
+const loopAudioMaxSize = 64 * 1024 * 1024
+
+// ErrInvalidLoopMode indica que el modo de repetición no existe.
+var ErrInvalidLoopMode = errors.New("modo de repetición inválido")
+
+// loopAudio es el audio completo de la canción que se repite, para volver a enviarlo sin pedirlo de nuevo.
+type loopAudio struct {
+	url  string
+	data []byte
+}
+
+// SetLoopMode cambia el modo de repetición. Al detener la reproducción vuelve a LoopOff.
+func (p *GuildPlayer) SetLoopMode(mode LoopMode) error {
+	switch mode {
+	case LoopOff, LoopTrack, LoopQueue:
+	default:
+		return ErrInvalidLoopMode
+	}
+	p.mu.Lock()
+	p.loopMode = mode
+	if mode != LoopTrack {
+		p.loopAudio = nil
+	}
+	p.mu.Unlock()
+	p.recordPlayback("loop_"+string(mode), nil)
+	p.logger.Info("Modo de repetición cambiado", zap.String("modo", string(mode)))
+	return nil
+}
+
+// LoopMode devuelve el modo de repetición actual.
+func (p *GuildPlayer) LoopMode() LoopMode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loopMode == "" {
+		return LoopOff
+	}
+	return p.loopMode
+}
+
+// takeSkipped devuelve si la canción actual se saltó y limpia la marca para la siguiente.
+func (p *GuildPlayer) takeSkipped() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	skipped := p.skipped
+	p.skipped = false
+	return skipped
+}
+
+// takeReplayed devuelve si la canción actual se reinició con Replay y limpia la marca para la siguiente.
+func (p *GuildPlayer) takeReplayed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	replayed := p.replayed
+	p.replayed = false
+	return replayed
+}
+
+// requeueLooped vuelve a encolar la canción que terminó según el modo de repetición: al principio de la lista para
+// repetirla, salvo que se haya saltado, o al final para repetir la lista.
+func (p *GuildPlayer) requeueLooped(logger logging.Logger, song *voice.Song, skipped bool) error {
+	again := *song
+	again.StartPosition = 0
+	switch p.LoopMode() {
+	case LoopTrack:
+		if skipped {
+			p.mu.Lock()
+			p.loopAudio = nil
+			p.mu.Unlock()
+			return nil
+		}
+		if err := p.songStorage.PrependSong(&again); err != nil {
+			logger.Error("Error al volver a encolar la canción repetida", zap.Error(err))
+			return err
+		}
+	case LoopQueue:
+		if err := p.songStorage.AppendSong(&again); err != nil {
+			logger.Error("Error al volver a encolar la canción al final de la lista", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// loopedAudio devuelve el audio guardado de la canción, si se está repitiendo.
+func (p *GuildPlayer) loopedAudio(song *voice.Song) (io.Reader, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loopMode != LoopTrack || p.loopAudio == nil || p.loopAudio.url != song.URL {
+		return nil, false
+	}
+	return bytes.NewReader(p.loopAudio.data), true
+}
+
+// recordLoopAudio envuelve el audio de la canción para guardarlo mientras se envía, si se está repitiendo la
+// canción. La función devuelta guarda el audio cuando se envió completo.
+func (p *GuildPlayer) recordLoopAudio(song *voice.Song, dca io.Reader) (io.Reader, func()) {
+	if p.LoopMode() != LoopTrack {
+		return dca, func() {}
+	}
+	recorder := &limitedBuffer{limit: loopAudioMaxSize}
+	return io.TeeReader(dca, recorder), func() {
+		if recorder.overflow {
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.loopMode == LoopTrack {
+			p.loopAudio = &loopAudio{url: song.URL, data: recorder.buf.Bytes()}
+		}
+	}
+}
+
+// limitedBuffer guarda lo que se escribe hasta limit bytes; si se pasa, deja de guardar y lo marca.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if b.overflow || b.buf.Len()+len(data) > b.limit {
+		b.overflow = true
+		b.buf = bytes.Buffer{}
+		return len(data), nil
+	}
+	return b.buf.Write(data)
+}
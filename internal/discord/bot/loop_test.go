@@ -0,0 +1,254 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSongStorage es una lista de reproducción en memoria. No se usa inmemory_storage porque importa este paquete.
+type testSongStorage struct {
+	mu    sync.Mutex
+	songs []*voice.Song
+}
+
+func (s *testSongStorage) PrependSong(song *voice.Song) error {
+	return s.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		return append([]*voice.Song{song}, songs...), nil
+	})
+}
+
+func (s *testSongStorage) AppendSong(song *voice.Song) error {
+	return s.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		return append(songs, song), nil
+	})
+}
+
+func (s *testSongStorage) RemoveSong(position int) (*voice.Song, error) {
+	var removed *voice.Song
+	err := s.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		if position < 1 || position > len(songs) {
+			return nil, ErrRemoveInvalidPosition
+		}
+		removed = songs[position-1]
+		return slices.Delete(songs, position-1, position), nil
+	})
+	return removed, err
+}
+
+func (s *testSongStorage) MoveSong(from, to int) (*voice.Song, error) {
+	var moved *voice.Song
+	err := s.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		if from < 1 || from > len(songs) || to < 1 || to > len(songs) {
+			return nil, ErrRemoveInvalidPosition
+		}
+		moved = songs[from-1]
+		return slices.Insert(slices.Delete(songs, from-1, from), to-1, moved), nil
+	})
+	return moved, err
+}
+
+func (s *testSongStorage) UpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updated, err := update(slices.Clone(s.songs))
+	if err != nil {
+		return err
+	}
+	s.songs = updated
+	return nil
+}
+
+func (s *testSongStorage) ClearPlaylist() error {
+	return s.UpdateSongs(func([]*voice.Song) ([]*voice.Song, error) { return nil, nil })
+}
+
+func (s *testSongStorage) GetSongs() ([]*voice.Song, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.songs), nil
+}
+
+func (s *testSongStorage) PopFirstSong() (*voice.Song, error) {
+	return s.RemoveSong(1)
+}
+
+// testStateStorage es el estado del reproductor en memoria.
+type testStateStorage struct {
+	mu                        sync.Mutex
+	current                   *voice.PlayedSong
+	voiceChannel, textChannel string
+}
+
+func (s *testStateStorage) GetCurrentSong() (*voice.PlayedSong, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current, nil
+}
+
+func (s *testStateStorage) SetCurrentSong(song *voice.PlayedSong) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = song
+	return nil
+}
+
+func (s *testStateStorage) GetVoiceChannel() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.voiceChannel, nil
+}
+
+func (s *testStateStorage) SetVoiceChannel(channelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.voiceChannel = channelID
+	return nil
+}
+
+func (s *testStateStorage) GetTextChannel() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.textChannel, nil
+}
+
+func (s *testStateStorage) SetTextChannel(channelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.textChannel = channelID
+	return nil
+}
+
+// newTestPlayer crea un reproductor con almacenamiento en memoria y la lista de reproducción indicada.
+func newTestPlayer(titles ...string) (*GuildPlayer, *testSongStorage, *testStateStorage) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	songStorage := &testSongStorage{}
+	for _, title := range titles {
+		_ = songStorage.AppendSong(&voice.Song{Title: title, URL: "https://youtu.be/" + title})
+	}
+	stateStorage := &testStateStorage{}
+	player := NewGuildPlayer(context.Background(), "1", nil, songStorage, stateStorage, nil, nil, logger)
+	return player, songStorage, stateStorage
+}
+
+// queueTitles devuelve los títulos de la lista de reproducción, en orden.
+func queueTitles(t *testing.T, songStorage *testSongStorage) []string {
+	t.Helper()
+	songs, err := songStorage.GetSongs()
+	require.NoError(t, err)
+	titles := make([]string, len(songs))
+	for i, song := range songs {
+		titles[i] = song.Title
+	}
+	return titles
+}
+
+func TestLimitedBuffer_Write(t *testing.T) {
+	tests := []struct {
+		name     string
+		writes   []string
+		want     string
+		overflow bool
+	}{
+		{name: "debajo del límite", writes: []string{"ab", "c"}, want: "abc"},
+		{name: "justo en el límite", writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "se pasa del límite", writes: []string{"ab", "cde"}, overflow: true},
+		{name: "después de pasarse no guarda más", writes: []string{"abcde", "a"}, overflow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &limitedBuffer{limit: 4}
+			for _, data := range tt.writes {
+				n, err := buffer.Write([]byte(data))
+				require.NoError(t, err)
+				assert.Equal(t, len(data), n, "la escritura no falla aunque no se guarde, para no cortar el envío")
+			}
+			assert.Equal(t, tt.overflow, buffer.overflow)
+			assert.Equal(t, tt.want, buffer.buf.String())
+		})
+	}
+}
+
+func TestGuildPlayer_RequeueLooped(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          LoopMode
+		skipped       bool
+		want          []string
+		keepLoopAudio bool
+	}{
+		{name: "sin repetición", mode: LoopOff, want: []string{"b", "c"}},
+		{name: "repite la canción al principio", mode: LoopTrack, want: []string{"a", "b", "c"}, keepLoopAudio: true},
+		{name: "la canción saltada no se repite", mode: LoopTrack, skipped: true, want: []string{"b", "c"}},
+		{name: "repite la lista al final", mode: LoopQueue, want: []string{"b", "c", "a"}},
+		{name: "la canción saltada vuelve al final de la lista", mode: LoopQueue, skipped: true, want: []string{"b", "c", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, songStorage, _ := newTestPlayer("b", "c")
+			require.NoError(t, player.SetLoopMode(tt.mode))
+			if tt.mode == LoopTrack {
+				player.loopAudio = &loopAudio{url: "https://youtu.be/a", data: []byte("audio")}
+			}
+			song := &voice.Song{Title: "a", URL: "https://youtu.be/a", StartPosition: 30 * time.Second}
+
+			require.NoError(t, player.requeueLooped(player.logger, song, tt.skipped))
+
+			assert.Equal(t, tt.want, queueTitles(t, songStorage))
+			songs, _ := songStorage.GetSongs()
+			for _, queued := range songs {
+				assert.Zero(t, queued.StartPosition, "la canción repetida arranca desde el principio")
+			}
+			assert.Equal(t, 30*time.Second, song.StartPosition, "no se modifica la canción que terminó")
+			assert.Equal(t, tt.keepLoopAudio, player.loopAudio != nil)
+		})
+	}
+}
+
+func TestGuildPlayer_RecordLoopAudio(t *testing.T) {
+	song := &voice.Song{Title: "a", URL: "https://youtu.be/a"}
+	tests := []struct {
+		name      string
+		mode      LoopMode
+		changeTo  LoopMode
+		wantSaved bool
+	}{
+		{name: "sin repetición no se guarda", mode: LoopOff},
+		{name: "repitiendo la lista no se guarda", mode: LoopQueue},
+		{name: "repitiendo la canción se guarda", mode: LoopTrack, wantSaved: true},
+		{name: "no se guarda si se dejó de repetir mientras sonaba", mode: LoopTrack, changeTo: LoopOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, _, _ := newTestPlayer()
+			require.NoError(t, player.SetLoopMode(tt.mode))
+			reader, save := player.recordLoopAudio(song, bytes.NewReader([]byte("audio")))
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, "audio", string(data))
+			if tt.changeTo != "" {
+				require.NoError(t, player.SetLoopMode(tt.changeTo))
+			}
+			save()
+
+			saved, ok := player.loopedAudio(song)
+			assert.Equal(t, tt.wantSaved, ok)
+			if tt.wantSaved {
+				data, _ := io.ReadAll(saved)
+				assert.Equal(t, "audio", string(data))
+				_, ok := player.loopedAudio(&voice.Song{URL: "https://youtu.be/otra"})
+				assert.False(t, ok, "el audio guardado es solo de esa canción")
+			}
+		})
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/alerting"
+	"github.com/Tomas-vilte/GoMusicBot/internal/audit"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 	"io"
@@ -21,34 +26,90 @@ var (
 	ErrNoSongs = errors.New("canción no disponible")
 	// ErrRemoveInvalidPosition indica que la posición de eliminación de la canción es inválida.
 	ErrRemoveInvalidPosition = errors.New("posición inválida")
+	// ErrNotPlaying indica que no hay ninguna canción sonando.
+	ErrNotPlaying = errors.New("no hay ninguna canción sonando")
+	// ErrVolumeNotSupported indica que la sesión de voz no permite cambiar el volumen.
+	ErrVolumeNotSupported = errors.New("el backend de audio no permite cambiar el volumen")
+	// ErrPauseNotSupported indica que la sesión de voz no permite pausar la reproducción.
+	ErrPauseNotSupported = errors.New("el backend de audio no permite pausar la reproducción")
 )
 
+// ErrorMessageUpstreamUnavailable es el mensaje que se envía al canal cuando no se puede reproducir porque YouTube está caído.
+const ErrorMessageUpstreamUnavailable = "🔌 YouTube está teniendo problemas, así que frené la reproducción. Probá de nuevo en unos minutos."
+
+// ErrorMessageVoiceChannelDenied es el mensaje que se envía al canal cuando no se reproduce porque el servidor no deja
+// que el bot entre al canal de voz.
+const ErrorMessageVoiceChannelDenied = "🔒 No tengo permitido entrar a ese canal de voz en este servidor. Las canciones siguen en la cola."
+
+// defaultVolume es el volumen con el que arranca una sesión de voz: el original.
+const defaultVolume = 100
+
 // Trigger representa un disparador para comandos relacionados con la reproducción de música.
 type Trigger struct {
 	Command        string
 	VoiceChannelID *string
 	TextChannelID  *string
+	CorrelationID  string // ID de correlación de la interacción que originó el disparador.
 }
 
 // DCADataGetter es una función para obtener datos de audio codificados en DCA para una canción específica.
 type DCADataGetter func(ctx context.Context, song *voice.Song) (io.Reader, error)
 
+// SeekableDCADataGetter obtiene los datos de audio codificados en DCA de una canción a partir de offset.
+type SeekableDCADataGetter func(ctx context.Context, song *voice.Song, offset time.Duration) (io.Reader, error)
+
 // GuildPlayer es el reproductor de música para un servidor específico en Discord.
 type GuildPlayer struct {
 	ctx             context.Context                    // Contexto para la gestión de la vida útil del reproductor.
+	guildID         string                             // ID del servidor al que pertenece el reproductor.
 	triggerCh       chan Trigger                       // Canal para recibir disparadores de comandos relacionados con la reproducción de música.
 	session         voice.VoiceChatSession             // Sesión de chat de voz que define métodos para interactuar con la sesión de voz del bot de Discord.
 	songCtxCancel   context.CancelFunc                 // Función de cancelación del contexto de la canción actual.
 	songStorage     store.SongStorage                  // Almacenamiento de canciones para la lista de reproducción.
 	stateStorage    store.StateStorage                 // Almacenamiento de estado para el reproductor de música.
 	dCADataGetter   DCADataGetter                      // Función para obtener datos de audio codificados en DCA para una canción específica.
+	seekableGetter  SeekableDCADataGetter              // Obtiene el audio desde una posición, sin codificar el principio; es opcional.
 	audioBufferSize int                                // Tamaño del búfer de audio para la transmisión de música.
 	logger          logging.Logger                     // Registro de eventos y errores.
 	voiceChannelMap map[string]VoiceChannelInfo        // Mapa que contiene información sobre los canales de voz y su estado.
 	message         discordmessenger.ChatMessageSender // Interfaz para enviar mensajes de chat a Discord.
+	auditor         audit.Recorder                     // Registro de auditoría de los eventos de reproducción.
+	voiceFailures   alerting.Tracker                   // Registro de las fallas al conectarse a un canal de voz, usado para alertar picos.
+	slowOps         logging.SlowOpThresholds           // Umbrales para advertir sobre operaciones lentas.
+	events          events.Publisher                   // Publicador de los eventos de reproducción; es opcional.
+	deferResume     bool                               // Si es true, Run no retoma la reproducción guardada; la retoma quien llame a Resume.
+	draining        bool                               // Indica que la reproducción se suspendió para traspasarla a otra instancia.
+	playDone        chan struct{}                      // Se cierra cuando termina la reproducción en curso; es nil si no se está reproduciendo.
+	voiceGate       VoiceGate                          // Decide si esta instancia puede conectarse al canal de voz; es opcional.
+	channelFilter   VoiceChannelFilter                 // Decide si el servidor deja entrar al bot al canal de voz; es opcional.
+	songFilters     []SongFilter                       // Filtros que deciden si una canción se puede agregar a la lista de reproducción.
+	seekTo          *time.Duration                     // Posición a la que se movió la canción actual; la reproducción la retoma desde ahí.
+	refresher       fetcher.SongRefresher              // Revalida la metadata de las canciones antes de reproducirlas; es opcional.
+	metadataTTL     time.Duration                      // Antigüedad a partir de la cual se revalida la metadata de una canción.
+	volumeLimit     VolumeLimit                        // Limita el volumen al tope del servidor; es opcional.
+	volume          int                                // Volumen elegido con SetVolume, sin limitar al tope.
+	pause           *codec.Pause                       // Suspende el envío del audio de la canción actual sin salir del canal de voz.
+	gain            *codec.Gain                        // Volumen y fundidos del audio que se envía sin backend de audio; es opcional.
+	loopMode        LoopMode                           // Modo de repetición; vuelve a LoopOff al detener la reproducción.
+	loopAudio       *loopAudio                         // Audio de la canción que se repite con LoopTrack; es nil si no se guardó.
+	skipped         bool                               // Indica que la canción actual se saltó, para no repetirla con LoopTrack.
+	replayed        bool                               // Indica que la canción actual se volvió a encolar con Replay, para no repetirla otra vez.
+	skipVotes       map[string]bool                    // IDs de quienes votaron para saltar la canción actual; se vacía con cada canción.
 	mu              sync.Mutex
 }
 
+// VoiceGate decide si esta instancia del bot puede tener la conexión de voz de un servidor. En modo cluster
+// garantiza que una sola instancia se conecte a cada servidor.
+type VoiceGate interface {
+	Owns(ctx context.Context, guildID string) bool
+}
+
+// VoiceChannelFilter decide si el bot puede entrar a un canal de voz de un servidor, según su configuración.
+type VoiceChannelFilter func(guildID, voiceChannelID string) bool
+
+// VolumeLimit limita el volumen pedido al tope que permite el servidor. Los volúmenes son porcentajes del original.
+type VolumeLimit func(guildID string, volume int) int
+
 // VoiceChannelInfo contiene información sobre un canal de voz y su estado.
 type VoiceChannelInfo struct {
 	GuildID         string
@@ -63,9 +124,10 @@ type VoiceChannelInfo struct {
 }
 
 // NewGuildPlayer crea una nueva instancia de GuildPlayer con los parámetros proporcionados.
-func NewGuildPlayer(ctx context.Context, session voice.VoiceChatSession, songStorage store.SongStorage, stateStorage store.StateStorage, dCADataGetter DCADataGetter, message discordmessenger.ChatMessageSender, logger logging.Logger) *GuildPlayer {
+func NewGuildPlayer(ctx context.Context, guildID string, session voice.VoiceChatSession, songStorage store.SongStorage, stateStorage store.StateStorage, dCADataGetter DCADataGetter, message discordmessenger.ChatMessageSender, logger logging.Logger) *GuildPlayer {
 	return &GuildPlayer{
 		ctx:             ctx,
+		guildID:         guildID,
 		songStorage:     songStorage,
 		stateStorage:    stateStorage,
 		triggerCh:       make(chan Trigger),
@@ -75,6 +137,9 @@ func NewGuildPlayer(ctx context.Context, session voice.VoiceChatSession, songSto
 		audioBufferSize: 1024 * 1024, // 1 MiB
 		voiceChannelMap: make(map[string]VoiceChannelInfo),
 		message:         message,
+		auditor:         audit.NopRecorder{},
+		volume:          defaultVolume,
+		pause:           codec.NewPause(),
 	}
 }
 
@@ -84,6 +149,134 @@ func (p *GuildPlayer) WithLogger(l logging.Logger) *GuildPlayer {
 	return p
 }
 
+// WithSeekableAudio hace que las canciones que arrancan desde una posición (al moverlas o al retomarlas) pidan el
+// audio desde esa posición con getter, en lugar de codificarlas desde el principio y descartar lo anterior.
+func (p *GuildPlayer) WithSeekableAudio(getter SeekableDCADataGetter) *GuildPlayer {
+	p.seekableGetter = getter
+	return p
+}
+
+// WithAuditRecorder establece el registro de auditoría para los eventos de reproducción.
+func (p *GuildPlayer) WithAuditRecorder(r audit.Recorder) *GuildPlayer {
+	p.auditor = r
+	return p
+}
+
+// WithVoiceFailureTracker establece el registro de fallas de conexión a los canales de voz.
+func (p *GuildPlayer) WithVoiceFailureTracker(t alerting.Tracker) *GuildPlayer {
+	p.voiceFailures = t
+	return p
+}
+
+// WithSlowOpThresholds establece los umbrales para advertir sobre conexiones de voz y codificaciones lentas.
+func (p *GuildPlayer) WithSlowOpThresholds(t logging.SlowOpThresholds) *GuildPlayer {
+	p.slowOps = t
+	return p
+}
+
+// WithEventPublisher establece el publicador de los eventos de reproducción.
+func (p *GuildPlayer) WithEventPublisher(pub events.Publisher) *GuildPlayer {
+	p.events = pub
+	return p
+}
+
+// publishSong publica un evento con la canción, si hay un publicador configurado.
+func (p *GuildPlayer) publishSong(eventType events.Type, song *voice.Song, position time.Duration) {
+	if p.events != nil {
+		event := events.NewSongEvent(eventType, p.guildID, song, position)
+		event.VoiceChannelID = p.eventVoiceChannel(eventType)
+		p.events.Publish(event)
+	}
+}
+
+// publishQueue publica un evento con la cantidad de canciones de la lista, si hay un publicador configurado.
+func (p *GuildPlayer) publishQueue(eventType events.Type) {
+	if p.events == nil {
+		return
+	}
+	songs, err := p.songStorage.GetSongs()
+	if err != nil {
+		p.logger.Error("Error al obtener la lista de reproducción para el evento", zap.Error(err))
+		return
+	}
+	event := events.NewQueueEvent(eventType, p.guildID, len(songs))
+	event.VoiceChannelID = p.eventVoiceChannel(eventType)
+	p.events.Publish(event)
+}
+
+// eventVoiceChannel devuelve el canal de voz en el que está el reproductor para los eventos de canción que empieza o
+// termina y de reproducción detenida, o "" si no se sabe. Los demás eventos no lo llevan, porque se publican seguido.
+func (p *GuildPlayer) eventVoiceChannel(eventType events.Type) string {
+	switch eventType {
+	case events.TypeTrackStarted, events.TypeTrackFinished, events.TypePlaybackStopped:
+	default:
+		return ""
+	}
+	channelID, err := p.stateStorage.GetVoiceChannel()
+	if err != nil {
+		return ""
+	}
+	return channelID
+}
+
+// WithDeferredResume hace que Run no retome la reproducción guardada al iniciar. Se usa en modo cluster,
+// donde la reproducción la retoma la instancia que recibe el traspaso del servidor.
+func (p *GuildPlayer) WithDeferredResume() *GuildPlayer {
+	p.deferResume = true
+	return p
+}
+
+// WithVoiceGate establece quién decide si el reproductor puede conectarse al canal de voz.
+func (p *GuildPlayer) WithVoiceGate(g VoiceGate) *GuildPlayer {
+	p.voiceGate = g
+	return p
+}
+
+// WithVoiceChannelFilter establece quién decide a qué canales de voz puede entrar el reproductor.
+func (p *GuildPlayer) WithVoiceChannelFilter(f VoiceChannelFilter) *GuildPlayer {
+	p.channelFilter = f
+	return p
+}
+
+// WithVolumeLimit establece quién limita el volumen del reproductor al tope del servidor. El tope se aplica a todos
+// los cambios de volumen, los pida quien los pida.
+func (p *GuildPlayer) WithVolumeLimit(l VolumeLimit) *GuildPlayer {
+	p.volumeLimit = l
+	return p
+}
+
+// WithPause establece la pausa que comparte el reproductor con el DCAStreamer de la sesión: al pausar la
+// reproducción se suspende el envío del audio. Sin ella el reproductor usa una pausa propia, que solo sirve con las
+// sesiones que pausan la canción por su cuenta.
+func (p *GuildPlayer) WithPause(pause *codec.Pause) *GuildPlayer {
+	p.pause = pause
+	return p
+}
+
+// WithGain establece la ganancia con la que el DCAStreamer de la sesión envía el audio, para que el reproductor
+// aplique en ella el volumen y los fundidos al arrancar cada canción.
+func (p *GuildPlayer) WithGain(g *codec.Gain) *GuildPlayer {
+	p.gain = g
+	return p
+}
+
+// recordPlayback registra un evento de reproducción en la auditoría.
+func (p *GuildPlayer) recordPlayback(event string, song *voice.Song) {
+	entry := audit.Entry{
+		Type:    audit.EntryTypePlayback,
+		GuildID: p.guildID,
+		Command: event,
+	}
+	if song != nil {
+		entry.Args = song.URL
+		entry.Result = song.GetHumanName()
+		if song.RequestedBy != nil {
+			entry.UserName = *song.RequestedBy
+		}
+	}
+	p.auditor.Record(entry)
+}
+
 // UpdateVoiceState actualiza el mapa de información sobre los canales de voz.
 func (p *GuildPlayer) UpdateVoiceState(s *discordgo.Session, vs *discordgo.VoiceStateUpdate) {
 	p.mu.Lock()
@@ -143,7 +336,12 @@ func (p *GuildPlayer) UpdateVoiceState(s *discordgo.Session, vs *discordgo.Voice
 
 // Close cierra el reproductor de música.
 func (p *GuildPlayer) Close() error {
-	p.songCtxCancel()
+	p.mu.Lock()
+	cancel := p.songCtxCancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
 	return p.session.Close()
 }
 
@@ -155,6 +353,7 @@ func (p *GuildPlayer) updateSongPosition(song *voice.Song, position time.Duratio
 	if err := p.message.EditPlayMessage(textChannel, playMsgID, &voice.PlayMessage{Song: song, Position: position}); err != nil {
 		p.logger.Error("Error fallo al editar el mensaje")
 	}
+	p.publishSong(events.TypePosition, song, position)
 }
 
 // GetVoiceChannelInfo devuelve el mapa con toda la información de los canales de voz y su estado.
@@ -170,8 +369,20 @@ func (p *GuildPlayer) StartListeningEvents(s *discordgo.Session) {
 	p.logger.Info("Comenzando a escuchar eventos relevantes")
 }
 
-// AddSong agrega una o más canciones a la lista de reproducción.
-func (p *GuildPlayer) AddSong(textChannelID, voiceChannelID *string, songs ...*voice.Song) error {
+// AddSong agrega una o más canciones a la lista de reproducción. El ID de correlación del contexto
+// se propaga a la reproducción que dispara. Las canciones que rechaza un filtro no se agregan y se informan
+// con un *RejectedSongsError después de agregar las demás.
+func (p *GuildPlayer) AddSong(ctx context.Context, textChannelID, voiceChannelID *string, songs ...*voice.Song) error {
+	songs, rejections := p.filterSongs(songs)
+	var rejected error
+	if len(rejections) > 0 {
+		rejected = &RejectedSongsError{Rejections: rejections, Added: len(songs)}
+		p.logger.Info("Canciones rechazadas por los filtros", zap.Int("cantidad", len(rejections)))
+		if len(songs) == 0 {
+			return rejected
+		}
+	}
+
 	for _, song := range songs {
 		if err := p.songStorage.AppendSong(song); err != nil {
 			p.logger.Error("Error al agregar canción a la lista de reproducción", zap.Error(err))
@@ -179,41 +390,282 @@ func (p *GuildPlayer) AddSong(textChannelID, voiceChannelID *string, songs ...*v
 		}
 	}
 
+	p.publishQueue(events.TypeQueueChanged)
+
 	go func() {
 		p.triggerCh <- Trigger{
 			Command:        "play",
 			VoiceChannelID: voiceChannelID,
 			TextChannelID:  textChannelID,
+			CorrelationID:  logging.CorrelationIDFromContext(ctx),
 		}
 	}()
 
 	p.logger.Info("Canciones agregadas a la lista de reproducción", zap.Int("cantidad", len(songs)))
-	return nil
+	return rejected
 }
 
 // SkipSong salta la canción actual.
 func (p *GuildPlayer) SkipSong() {
-	if p.songCtxCancel != nil {
-		p.songCtxCancel()
+	p.takeSeek()
+	p.mu.Lock()
+	p.skipped = true
+	cancel := p.songCtxCancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		p.recordPlayback("song_skipped", nil)
 		p.logger.Info("Canción actual saltada")
 	}
 }
 
-// Stop detiene la reproducción y limpia la lista de reproducción.
+// Replay vuelve a reproducir la canción actual desde el principio: la encola de nuevo al principio de la lista y la
+// salta. El audio se vuelve a tomar de la caché o, con LoopTrack, del guardado en memoria. Devuelve la canción.
+func (p *GuildPlayer) Replay() (*voice.Song, error) {
+	current, err := p.stateStorage.GetCurrentSong()
+	if err != nil {
+		p.logger.Error("Error al obtener la canción actual", zap.Error(err))
+		return nil, err
+	}
+	if current == nil || !p.IsPlaying() {
+		return nil, ErrNotPlaying
+	}
+	again := current.Song
+	again.StartPosition = 0
+	if err := p.songStorage.PrependSong(&again); err != nil {
+		p.logger.Error("Error al volver a encolar la canción actual", zap.Error(err))
+		return nil, err
+	}
+
+	p.takeSeek()
+	p.mu.Lock()
+	p.replayed = true
+	cancel := p.songCtxCancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	p.recordPlayback("song_replayed", &again)
+	p.logger.Info("Canción actual reiniciada", zap.String("URL", again.URL))
+	return &again, nil
+}
+
+// Pause pausa la reproducción sin salir del canal de voz; la canción actual queda en la posición en que iba. La pausa
+// sigue para las canciones siguientes hasta llamar a Unpause. Devuelve false si ya estaba pausada.
+func (p *GuildPlayer) Pause(ctx context.Context) (bool, error) {
+	return p.setPaused(ctx, true)
+}
+
+// Unpause retoma la reproducción pausada desde donde quedó. Devuelve false si no estaba pausada.
+func (p *GuildPlayer) Unpause(ctx context.Context) (bool, error) {
+	return p.setPaused(ctx, false)
+}
+
+// IsPaused indica si la reproducción está pausada.
+func (p *GuildPlayer) IsPaused() bool {
+	return p.pause.Paused()
+}
+
+func (p *GuildPlayer) setPaused(ctx context.Context, paused bool) (bool, error) {
+	if !p.IsPlaying() {
+		return false, ErrNotPlaying
+	}
+	if p.pause.Paused() == paused {
+		return false, nil
+	}
+	// Las sesiones que reproducen la canción por su cuenta la pausan ellas; a las demás se les deja de enviar audio.
+	if _, ok := p.session.(voice.TrackPlayer); ok {
+		controller, ok := p.session.(voice.PauseController)
+		if !ok {
+			return false, ErrPauseNotSupported
+		}
+		if err := controller.SetPaused(ctx, paused); err != nil {
+			p.logger.Error("Error al pausar la reproducción", zap.Bool("pausada", paused), zap.Error(err))
+			return false, err
+		}
+	}
+	if !p.pause.Set(paused) {
+		return false, nil
+	}
+	if paused {
+		p.recordPlayback("playback_paused", nil)
+		p.logger.Info("Reproducción pausada")
+	} else {
+		p.recordPlayback("playback_resumed", nil)
+		p.logger.Info("Reproducción retomada")
+	}
+	return true, nil
+}
+
+// Stop detiene la reproducción y limpia la lista de reproducción. Si no hay reproducción en curso, también descarta
+// la canción actual guardada.
 func (p *GuildPlayer) Stop() error {
 	if err := p.songStorage.ClearPlaylist(); err != nil {
 		p.logger.Error("Error al limpiar la lista de reproducción", zap.Error(err))
 		return fmt.Errorf("al limpiar la lista de reproducción: %w", err)
 	}
 
-	if p.songCtxCancel != nil {
-		p.songCtxCancel()
+	if !p.IsPlaying() {
+		// Sin reproducción en curso puede haber quedado guardada la canción de una reproducción suspendida con
+		// Yield; se descarta para que no se retome después.
+		if err := p.stateStorage.SetCurrentSong(nil); err != nil {
+			p.logger.Error("Error al limpiar la canción actual", zap.Error(err))
+			return fmt.Errorf("al limpiar la canción actual: %w", err)
+		}
+	}
+
+	p.takeSeek()
+	// Sin repetición, la canción que se corta no vuelve a la lista.
+	p.mu.Lock()
+	p.loopMode = LoopOff
+	p.loopAudio = nil
+	cancel := p.songCtxCancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		p.recordPlayback("playback_stopped", nil)
 		p.logger.Info("Reproducción detenida y lista de reproducción limpia")
 	}
+	p.publishQueue(events.TypePlaybackStopped)
 
 	return nil
 }
 
+// Seek mueve la canción actual delta hacia adelante, o hacia atrás si es negativo, y devuelve la nueva posición.
+// La canción vuelve a arrancar desde esa posición; si queda después del final, termina y sigue la próxima.
+func (p *GuildPlayer) Seek(delta time.Duration) (time.Duration, error) {
+	return p.seek(func(current time.Duration) time.Duration { return current + delta })
+}
+
+// SeekTo mueve la canción actual a position, como el comienzo de un capítulo, y devuelve la nueva posición.
+func (p *GuildPlayer) SeekTo(position time.Duration) (time.Duration, error) {
+	return p.seek(func(time.Duration) time.Duration { return position })
+}
+
+// seek hace que la canción actual vuelva a arrancar desde la posición que devuelve target a partir de la actual.
+func (p *GuildPlayer) seek(target func(current time.Duration) time.Duration) (time.Duration, error) {
+	current, err := p.stateStorage.GetCurrentSong()
+	if err != nil {
+		p.logger.Error("Error al obtener la canción actual", zap.Error(err))
+		return 0, err
+	}
+	if current == nil || !p.IsPlaying() {
+		return 0, ErrNotPlaying
+	}
+	position := target(current.Position)
+	if position < 0 {
+		position = 0
+	}
+
+	p.mu.Lock()
+	p.seekTo = &position
+	cancel := p.songCtxCancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	p.recordPlayback("song_seeked", &current.Song)
+	p.logger.Info("Canción actual movida", zap.Duration("posición", position))
+	return position, nil
+}
+
+// takeSeek devuelve y limpia la posición a la que se movió la canción actual, si hay una pendiente.
+func (p *GuildPlayer) takeSeek() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seekTo == nil {
+		return 0, false
+	}
+	position := *p.seekTo
+	p.seekTo = nil
+	return position, true
+}
+
+// SetVolume cambia el volumen de la reproducción, si la sesión de voz lo permite. Si el servidor tiene un tope, el
+// volumen se limita a ese tope.
+func (p *GuildPlayer) SetVolume(ctx context.Context, volume int) error {
+	controller, ok := p.volumeController()
+	if !ok {
+		return ErrVolumeNotSupported
+	}
+	p.mu.Lock()
+	p.volume = volume
+	p.mu.Unlock()
+	return p.applyVolume(ctx, controller)
+}
+
+// ApplyVolumeLimit vuelve a aplicar el volumen elegido con el tope actual del servidor, para que un cambio de tope
+// se note en la reproducción en curso.
+func (p *GuildPlayer) ApplyVolumeLimit(ctx context.Context) error {
+	controller, ok := p.volumeController()
+	if !ok || !p.IsPlaying() {
+		return nil
+	}
+	return p.applyVolume(ctx, controller)
+}
+
+// restoreVolume aplica el volumen elegido al conectarse al canal de voz: la sesión nueva arranca con el volumen
+// original, que puede pasarse del tope del servidor.
+func (p *GuildPlayer) restoreVolume(ctx context.Context, logger logging.Logger) {
+	controller, ok := p.volumeController()
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	volume := p.volume
+	p.mu.Unlock()
+	if p.limitVolume(volume) == defaultVolume {
+		return
+	}
+	if err := p.applyVolume(ctx, controller); err != nil {
+		logger.Warn("No se pudo aplicar el volumen al conectarse", zap.Error(err))
+	}
+}
+
+// volumeController devuelve con qué se cambia el volumen: la sesión de voz si lo permite o, si no, la ganancia con
+// la que se envía el audio.
+func (p *GuildPlayer) volumeController() (voice.VolumeController, bool) {
+	if controller, ok := p.session.(voice.VolumeController); ok {
+		return controller, true
+	}
+	if p.gain != nil {
+		return gainVolume{gain: p.gain}, true
+	}
+	return nil, false
+}
+
+// gainVolume cambia el volumen en la ganancia con la que el DCAStreamer envía el audio.
+type gainVolume struct {
+	gain *codec.Gain
+}
+
+func (g gainVolume) SetVolume(_ context.Context, volume int) error {
+	g.gain.SetVolume(volume)
+	return nil
+}
+
+// applyVolume aplica a la sesión de voz el volumen elegido, limitado al tope del servidor.
+func (p *GuildPlayer) applyVolume(ctx context.Context, controller voice.VolumeController) error {
+	p.mu.Lock()
+	requested := p.volume
+	p.mu.Unlock()
+	volume := p.limitVolume(requested)
+	if err := controller.SetVolume(ctx, volume); err != nil {
+		p.logger.Error("Error al cambiar el volumen", zap.Error(err))
+		return err
+	}
+	p.logger.Info("Volumen cambiado", zap.Int("volumen", volume), zap.Int("pedido", requested))
+	return nil
+}
+
+// limitVolume limita el volumen al tope del servidor, si tiene uno.
+func (p *GuildPlayer) limitVolume(volume int) int {
+	if p.volumeLimit == nil {
+		return volume
+	}
+	return p.volumeLimit(p.guildID, volume)
+}
+
 // RemoveSong elimina una canción de la lista de reproducción por posición.
 func (p *GuildPlayer) RemoveSong(position int) (*voice.Song, error) {
 	song, err := p.songStorage.RemoveSong(position)
@@ -223,9 +675,90 @@ func (p *GuildPlayer) RemoveSong(position int) (*voice.Song, error) {
 	}
 
 	p.logger.Info("Canción eliminada de la lista de reproducción", zap.String("título", song.Title))
+	p.publishQueue(events.TypeQueueChanged)
 	return song, nil
 }
 
+// MoveSong mueve la canción de la posición from a la posición to de la lista de reproducción, empezando por 1.
+func (p *GuildPlayer) MoveSong(from, to int) (*voice.Song, error) {
+	song, err := p.songStorage.MoveSong(from, to)
+	if err != nil {
+		p.logger.Error("Error al mover canción en la lista de reproducción", zap.Error(err))
+		return nil, fmt.Errorf("al mover canción: %w", err)
+	}
+
+	p.logger.Info("Canción movida en la lista de reproducción", zap.String("título", song.Title), zap.Int("desde", from), zap.Int("hasta", to))
+	p.publishQueue(events.TypeQueueChanged)
+	return song, nil
+}
+
+// JumpTo hace sonar ya la canción en position (empezando por 1) de la lista de reproducción, saltando la actual. Las
+// canciones anteriores se descartan o, si keep es true, quedan en la lista después de ella. Devuelve la canción.
+func (p *GuildPlayer) JumpTo(ctx context.Context, position int, keep bool) (*voice.Song, error) {
+	var target *voice.Song
+	err := p.UpdateSongs(ctx, func(songs []*voice.Song) ([]*voice.Song, error) {
+		if position < 1 || position > len(songs) {
+			return nil, ErrRemoveInvalidPosition
+		}
+		target = songs[position-1]
+		reordered := []*voice.Song{target}
+		if keep {
+			reordered = append(reordered, songs[:position-1]...)
+		}
+		return append(reordered, songs[position:]...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Sin reproducción en curso, UpdateSongs ya la arranca desde la canción elegida.
+	if p.IsPlaying() {
+		p.SkipSong()
+	}
+	p.logger.Info("Salto a una canción de la lista de reproducción", zap.Int("posición", position), zap.Bool("conservar", keep))
+	return target, nil
+}
+
+// ReplaceSongs reemplaza la lista de reproducción por songs, por ejemplo para cambiar de cola. Si quedan canciones,
+// se reproducen en los canales guardados cuando no haya una reproducción en curso.
+func (p *GuildPlayer) ReplaceSongs(ctx context.Context, songs []*voice.Song) error {
+	return p.UpdateSongs(ctx, func([]*voice.Song) ([]*voice.Song, error) {
+		return songs, nil
+	})
+}
+
+// UpdateSongs reemplaza atómicamente la lista de reproducción por lo que devuelve update a partir de la actual, así
+// no se pierden las canciones que se agregan ni vuelve a la lista la que empieza a sonar mientras tanto. Si update
+// devuelve un error, la lista no cambia y se devuelve ese error. Si quedan canciones, se reproducen en los canales
+// guardados cuando no haya una reproducción en curso.
+func (p *GuildPlayer) UpdateSongs(ctx context.Context, update func([]*voice.Song) ([]*voice.Song, error)) error {
+	var count int
+	var updateErr error
+	err := p.songStorage.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		updated, err := update(songs)
+		count, updateErr = len(updated), err
+		return updated, err
+	})
+	if updateErr != nil {
+		return updateErr
+	}
+	if err != nil {
+		p.logger.Error("Error al actualizar la lista de reproducción", zap.Error(err))
+		return fmt.Errorf("al actualizar la lista de reproducción: %w", err)
+	}
+	p.publishQueue(events.TypeQueueChanged)
+
+	if count > 0 {
+		go func() {
+			p.triggerCh <- Trigger{
+				Command:       "play",
+				CorrelationID: logging.CorrelationIDFromContext(ctx),
+			}
+		}()
+	}
+	p.logger.Info("Lista de reproducción reemplazada", zap.Int("cantidad", count))
+	return nil
+}
+
 // GetPlaylist obtiene la lista de reproducción actual.
 func (p *GuildPlayer) GetPlaylist() ([]string, error) {
 	songs, err := p.songStorage.GetSongs()
@@ -243,6 +776,21 @@ func (p *GuildPlayer) GetPlaylist() ([]string, error) {
 	return playlist, nil
 }
 
+// GetSongs obtiene las canciones de la lista de reproducción actual.
+func (p *GuildPlayer) GetSongs() ([]*voice.Song, error) {
+	songs, err := p.songStorage.GetSongs()
+	if err != nil {
+		p.logger.Error("Error al obtener la lista de reproducción", zap.Error(err))
+		return nil, fmt.Errorf("al obtener canciones: %w", err)
+	}
+	return songs, nil
+}
+
+// GetVoiceChannel obtiene el canal de voz guardado del reproductor.
+func (p *GuildPlayer) GetVoiceChannel() (string, error) {
+	return p.stateStorage.GetVoiceChannel()
+}
+
 // GetPlayedSong obtiene la canción que se está reproduciendo actualmente.
 func (p *GuildPlayer) GetPlayedSong() (*voice.PlayedSong, error) {
 	currentSong, err := p.stateStorage.GetCurrentSong()
@@ -254,18 +802,138 @@ func (p *GuildPlayer) GetPlayedSong() (*voice.PlayedSong, error) {
 	return currentSong, nil
 }
 
-// Run inicia el bucle principal del reproductor de música.
-func (p *GuildPlayer) Run(ctx context.Context) error {
+// IsPlaying indica si hay una reproducción en curso.
+func (p *GuildPlayer) IsPlaying() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playDone != nil
+}
+
+// PlayerState es el estado del reproductor, para diagnosticar problemas.
+type PlayerState string
+
+const (
+	StateIdle        PlayerState = "idle"        // StateIdle indica que no hay una reproducción en curso.
+	StatePlaying     PlayerState = "playing"     // StatePlaying indica que se está reproduciendo una canción.
+	StatePaused      PlayerState = "paused"      // StatePaused indica que la reproducción en curso está pausada.
+	StateDraining    PlayerState = "draining"    // StateDraining indica que la reproducción se suspendió para traspasarla a otra instancia.
+	StateInterrupted PlayerState = "interrupted" // StateInterrupted indica que quedó una reproducción guardada que no está sonando.
+)
+
+// State devuelve el estado actual del reproductor.
+func (p *GuildPlayer) State() PlayerState {
+	switch {
+	case p.isDraining():
+		return StateDraining
+	case p.IsPlaying() && p.IsPaused():
+		return StatePaused
+	case p.IsPlaying():
+		return StatePlaying
+	case p.Interrupted():
+		return StateInterrupted
+	default:
+		return StateIdle
+	}
+}
+
+// Volume devuelve el volumen de la reproducción, ya limitado al tope del servidor.
+func (p *GuildPlayer) Volume() int {
+	p.mu.Lock()
+	volume := p.volume
+	p.mu.Unlock()
+	return p.limitVolume(volume)
+}
+
+// isDraining indica si la reproducción se suspendió para traspasarla a otra instancia.
+func (p *GuildPlayer) isDraining() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.draining
+}
+
+// Drain detiene la reproducción sin limpiar la lista ni la canción actual, para que otra instancia la retome
+// desde la misma posición. Espera a que el bot salga del canal de voz o a que se cancele el contexto.
+func (p *GuildPlayer) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	p.draining = true
+	cancel := p.songCtxCancel
+	done := p.playDone
+	p.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	if cancel != nil {
+		cancel()
+	}
+	select {
+	case <-done:
+		p.logger.Info("Reproducción suspendida para el traspaso")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Yield suspende la reproducción como Drain, pero deja el reproductor listo para volver a reproducir. Se usa
+// cuando otra instancia toma la conexión de voz del servidor.
+func (p *GuildPlayer) Yield(ctx context.Context) error {
+	err := p.Drain(ctx)
+	p.mu.Lock()
+	p.draining = false
+	p.mu.Unlock()
+	return err
+}
+
+// MoveTo pasa la reproducción a otro canal de voz del servidor: la suspende como Yield, guarda el canal nuevo y la
+// retoma ahí con la misma lista, desde la posición en que iba la canción actual.
+func (p *GuildPlayer) MoveTo(ctx context.Context, voiceChannelID string) error {
+	if err := p.Yield(ctx); err != nil {
+		return err
+	}
+	if err := p.stateStorage.SetVoiceChannel(voiceChannelID); err != nil {
+		p.logger.Error("Error al establecer el canal de voz", zap.Error(err))
+		return err
+	}
+	return p.Resume()
+}
+
+// Interrupted indica si quedó una reproducción a medias en el almacenamiento (una canción actual guardada)
+// que no está sonando en este reproductor: por ejemplo, porque se cayó la instancia que la reproducía.
+func (p *GuildPlayer) Interrupted() bool {
+	if p.IsPlaying() || p.isDraining() {
+		return false
+	}
+	currentSong, err := p.stateStorage.GetCurrentSong()
+	if err != nil {
+		p.logger.Error("Error al obtener la canción actual", zap.Error(err))
+		return false
+	}
+	return currentSong != nil
+}
+
+// Resume retoma la reproducción guardada en el almacenamiento: vuelve a encolar la canción actual desde la
+// posición en que quedó y, si hay canciones, se une al canal de voz guardado y empieza a reproducir.
+// La canción actual se limpia al encolarla, así que llamar a Resume de nuevo no la duplica.
+func (p *GuildPlayer) Resume() error {
+	p.mu.Lock()
+	p.draining = false
+	p.mu.Unlock()
+
 	currentSong, err := p.stateStorage.GetCurrentSong()
 	if err != nil {
 		p.logger.Info("falló al obtener la canción actual", zap.Error(err))
 		return err
 	} else if currentSong != nil {
-		currentSong.StartPosition += currentSong.Position
+		currentSong.StartPosition = currentSong.Position
 		if err := p.songStorage.PrependSong(&currentSong.Song); err != nil {
 			p.logger.Info("falló al agregar la canción actual en la lista de reproducción", zap.Error(err))
 			return err
 		}
+		if err := p.stateStorage.SetCurrentSong(nil); err != nil {
+			p.logger.Error("Error al limpiar la canción actual", zap.Error(err))
+			return err
+		}
 	}
 
 	songs, err := p.songStorage.GetSongs()
@@ -294,6 +962,16 @@ func (p *GuildPlayer) Run(ctx context.Context) error {
 			}
 		}()
 	}
+	return nil
+}
+
+// Run inicia el bucle principal del reproductor de música.
+func (p *GuildPlayer) Run(ctx context.Context) error {
+	if !p.deferResume {
+		if err := p.Resume(); err != nil {
+			return err
+		}
+	}
 
 	for {
 		p.logger.Info("Esperando triggers")
@@ -322,11 +1000,15 @@ func (p *GuildPlayer) Run(ctx context.Context) error {
 					continue
 				}
 
-				if len(songs) == 0 {
+				if len(songs) == 0 || p.isDraining() {
 					continue
 				}
 
-				if err := p.playPlaylist(ctx); err != nil {
+				playCtx := ctx
+				if trigger.CorrelationID != "" {
+					playCtx = logging.ContextWithCorrelationID(ctx, trigger.CorrelationID)
+				}
+				if err := p.playPlaylist(playCtx); err != nil {
 					p.logger.Error("falló al reproducir la lista de reproducción", zap.Error(err))
 				}
 			}
@@ -334,84 +1016,221 @@ func (p *GuildPlayer) Run(ctx context.Context) error {
 	}
 }
 
+// streamSong obtiene el audio codificado de la canción y lo envía al canal de voz.
+func (p *GuildPlayer) streamSong(ctx context.Context, logger logging.Logger, song *voice.Song, positionCallback func(time.Duration)) error {
+	encodeStart := time.Now()
+	skip := song.StartPosition
+	var dcaData io.Reader
+	var err error
+	saveLoopAudio := func() {}
+	if looped, ok := p.loopedAudio(song); ok {
+		// Al repetir la canción se reenvía el audio guardado, sin volver a pedirlo a YouTube.
+		dcaData = looped
+	} else if p.seekableGetter != nil && song.StartPosition > 0 {
+		dcaData, err = p.seekableGetter(ctx, song, song.StartPosition)
+		skip = 0
+	} else {
+		dcaData, err = p.dCADataGetter(ctx, song)
+		if err == nil {
+			dcaData, saveLoopAudio = p.recordLoopAudio(song, dcaData)
+		}
+	}
+	if err != nil {
+		logger.Error("Error al obtener datos DCA de la cancion", zap.Any("Cancion", song), zap.Error(err))
+		return err
+	}
+	audioReader := bufio.NewReaderSize(dcaData, p.audioBufferSize)
+	// Peek espera a que llegue el primer byte codificado sin consumirlo, para medir el arranque de la codificación.
+	// Si falla, el error se maneja al enviar el audio.
+	_, _ = audioReader.Peek(1)
+	logging.WarnIfSlow(logger, "encode_start", time.Since(encodeStart), p.slowOps.EncodeStart,
+		zap.String("guildID", p.guildID), zap.String("input", song.URL))
+	if skip > 0 {
+		// Sin un getter que arranque desde una posición, el audio se codifica desde el principio y se descarta lo
+		// anterior a la posición pedida.
+		if err := codec.SkipFrames(audioReader, skip); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				logger.Info("la posición pedida está después del final de la canción", zap.Duration("posición", song.StartPosition))
+				return nil
+			}
+			logger.Error("Error al adelantar el audio hasta la posición pedida", zap.Error(err))
+			return err
+		}
+	}
+	logger.Info("enviando flujo de audio")
+	if p.gain != nil {
+		// Como con el backend de audio, al retomar una canción desde una posición el audio entra con un fundido.
+		p.gain.Start(song.StartPosition > 0)
+	}
+	// La sesión informa la posición desde el inicio del flujo; se le suma la posición de inicio para que, como en
+	// el backend de audio, sea la posición en la canción.
+	if err := p.session.SendAudio(ctx, audioReader, func(d time.Duration) { positionCallback(song.StartPosition + d) }); err != nil {
+		logger.Error("Error al enviar datos de audio", zap.Error(err))
+		return err
+	}
+	if ctx.Err() == nil {
+		saveLoopAudio()
+	}
+	return nil
+}
+
 // playPlaylist reproduce la lista de reproducción de canciones.
 func (p *GuildPlayer) playPlaylist(ctx context.Context) error {
-	p.logger.Info("playPlaylist iniciado")
+	logger := logging.FromContext(ctx, p.logger)
+	logger.Info("playPlaylist iniciado")
 	voiceChannel, err := p.stateStorage.GetVoiceChannel()
 	if err != nil {
-		p.logger.Error("Erorr al obtener el canal ve voz", zap.Error(err))
+		logger.Error("Erorr al obtener el canal ve voz", zap.Error(err))
 		return err
 	}
 
 	textChannel, err := p.stateStorage.GetTextChannel()
 	if err != nil {
-		p.logger.Error("Erorr al obtener el canal de texto", zap.Error(err))
+		logger.Error("Erorr al obtener el canal de texto", zap.Error(err))
 		return err
 	}
 
-	p.logger.Info("uniéndose al canal de voz", zap.String("canal", voiceChannel))
-	if err := p.session.JoinVoiceChannel(voiceChannel); err != nil {
-		p.logger.Error("Error fallo al unirse al canal de voz", zap.Error(err))
+	if p.voiceGate != nil && !p.voiceGate.Owns(ctx, p.guildID) {
+		// Las canciones quedan en la lista compartida; las reproduce la instancia que tiene el servidor.
+		logger.Warn("Otra instancia tiene la conexión de voz del servidor; no se reproduce en esta", zap.String("guildID", p.guildID))
+		return nil
+	}
+
+	if p.channelFilter != nil && !p.channelFilter(p.guildID, voiceChannel) {
+		logger.Warn("El servidor no permite entrar al canal de voz; no se reproduce", zap.String("canal", voiceChannel))
+		if err := p.message.SendMessage(textChannel, ErrorMessageVoiceChannelDenied); err != nil {
+			logger.Error("Error al avisar que el canal de voz no está permitido", zap.Error(err))
+		}
+		return nil
+	}
+
+	logger.Info("uniéndose al canal de voz", zap.String("canal", voiceChannel))
+	joinStart := time.Now()
+	err = p.session.JoinVoiceChannel(voiceChannel)
+	logging.WarnIfSlow(logger, "voice_connect", time.Since(joinStart), p.slowOps.VoiceConnect,
+		zap.String("guildID", p.guildID), zap.String("canal", voiceChannel))
+	if err != nil {
+		logger.Error("Error fallo al unirse al canal de voz", zap.Error(err))
+		if p.voiceFailures != nil {
+			p.voiceFailures.Track()
+		}
 		return err
 	}
+	p.restoreVolume(ctx, logger)
+
+	done := make(chan struct{})
+	p.mu.Lock()
+	p.playDone = done
+	p.mu.Unlock()
 
 	defer func() {
-		p.logger.Info("saliendo del canal de voz", zap.String("canal", voiceChannel))
+		logger.Info("saliendo del canal de voz", zap.String("canal", voiceChannel))
 		if err := p.session.LeaveVoiceChannel(); err != nil {
-			p.logger.Error("Error falló al salir del canal de voz", zap.Error(err))
+			logger.Error("Error falló al salir del canal de voz", zap.Error(err))
 		}
+		p.mu.Lock()
+		p.playDone = nil
+		p.mu.Unlock()
+		// La próxima reproducción arranca sin pausa; con Lavalink, la sesión la olvida al salir del canal.
+		p.pause.Set(false)
+		close(done)
 	}()
 
 	for {
+		if p.isDraining() {
+			return nil
+		}
 		song, err := p.songStorage.PopFirstSong()
 		if errors.Is(err, ErrNoSongs) {
-			p.logger.Info("la lista de reproducción está vacía")
+			logger.Info("la lista de reproducción está vacía")
 			break
 		}
 		if err != nil {
-			p.logger.Error("Error al obtener la primera cancion", zap.Error(err))
+			logger.Error("Error al obtener la primera cancion", zap.Error(err))
 			return err
 		}
 
-		if err := p.stateStorage.SetCurrentSong(&voice.PlayedSong{Song: *song}); err != nil {
-			p.logger.Error("Error al establecer la cancion actual", zap.Error(err))
+		song, ok := p.refreshSong(ctx, logger, textChannel, song)
+		if !ok {
+			continue
+		}
+		p.resolveAhead(ctx, logger)
+
+		if err := p.stateStorage.SetCurrentSong(&voice.PlayedSong{Song: *song, Position: song.StartPosition}); err != nil {
+			logger.Error("Error al establecer la cancion actual", zap.Error(err))
 			return err
 		}
 
 		songCtx, cancel := context.WithCancel(ctx)
 		p.mu.Lock()
 		p.songCtxCancel = cancel
+		p.skipped = false
+		p.replayed = false
+		p.skipVotes = nil
 		p.mu.Unlock()
 
-		p.logger.With(zap.String("título", song.Title), zap.String("URL", song.URL))
+		logger.With(zap.String("título", song.Title), zap.String("URL", song.URL))
 
 		playMsgID, err := p.message.SendPlayMessage(textChannel, &voice.PlayMessage{Song: song})
 		if err != nil {
-			p.logger.Error("Error al enviar el mensaje con el nombre de la cancion", zap.Error(err))
+			logger.Error("Error al enviar el mensaje con el nombre de la cancion", zap.Error(err))
 			return err
 		}
 
-		dcaData, err := p.dCADataGetter(songCtx, song)
-		if err != nil {
-			p.logger.Error("Error al obtener datos DCA de la cancion", zap.Any("Cancion", song), zap.Error(err))
-			return err
-		}
-		audioReader := bufio.NewReaderSize(dcaData, p.audioBufferSize)
-		p.logger.Info("enviando flujo de audio")
-		if err := p.session.SendAudio(songCtx, audioReader, func(d time.Duration) {
+		p.recordPlayback("song_started", song)
+		p.publishSong(events.TypeTrackStarted, song, song.StartPosition)
+		p.publishQueue(events.TypeQueueChanged)
+
+		positionCallback := func(d time.Duration) {
 			p.updateSongPosition(song, d, textChannel, playMsgID)
-		}); err != nil {
-			p.logger.Error("Error al enviar datos de audio", zap.Error(err))
+		}
+		for {
+			if trackPlayer, ok := p.session.(voice.TrackPlayer); ok {
+				logger.Info("reproduciendo la canción en el backend de audio")
+				if err = trackPlayer.PlayTrack(songCtx, song, positionCallback); err != nil && !p.isDraining() {
+					logger.Error("Error al reproducir la canción", zap.Error(err))
+				}
+			} else if err = p.streamSong(songCtx, logger, song, positionCallback); errors.Is(err, fetcher.ErrCircuitOpen) {
+				if err := p.message.SendMessage(textChannel, ErrorMessageUpstreamUnavailable); err != nil {
+					logger.Error("Error al avisar que YouTube no está disponible", zap.Error(err))
+				}
+			}
+			// Si se movió la canción, se vuelve a reproducir desde la posición pedida con el mismo mensaje.
+			position, ok := p.takeSeek()
+			if !ok || p.isDraining() {
+				break
+			}
+			logger.Info("moviendo la canción", zap.Duration("posición", position))
+			song.StartPosition = position
+			songCtx, cancel = context.WithCancel(ctx)
+			p.mu.Lock()
+			p.songCtxCancel = cancel
+			p.mu.Unlock()
+		}
+		// Al suspender la reproducción para el traspaso, la canción actual y su posición quedan guardadas
+		// para que la retome otra instancia.
+		if p.isDraining() {
+			return nil
+		}
+		if err != nil {
 			return err
 		}
-		p.logger.Info("Reproduccion detenida")
+		logger.Info("Reproduccion detenida")
+		p.recordPlayback("song_finished", song)
+		p.publishSong(events.TypeTrackFinished, song, song.Duration)
 		p.updateSongPosition(song, song.Duration, textChannel, playMsgID)
 		if err := p.stateStorage.SetCurrentSong(nil); err != nil {
-			p.logger.Error("Error al establecer la cancion actual", zap.Error(err))
+			logger.Error("Error al establecer la cancion actual", zap.Error(err))
 			return err
 		}
+		// La canción reiniciada con Replay ya volvió al principio de la lista.
+		if !p.takeReplayed() {
+			if err := p.requeueLooped(logger, song, p.takeSkipped()); err != nil {
+				return err
+			}
+		}
 		time.Sleep(250 * time.Millisecond)
 	}
-	p.logger.Info("playPlaylist finalizado")
+	logger.Info("playPlaylist finalizado")
 	return nil
 }
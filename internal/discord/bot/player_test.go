@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// startTestPlayback hace como si el reproductor estuviera sonando y devuelve el contexto de la canción actual, que se
+// cancela al saltarla.
+func startTestPlayback(player *GuildPlayer) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	player.mu.Lock()
+	player.playDone = make(chan struct{})
+	player.songCtxCancel = cancel
+	player.mu.Unlock()
+	return ctx
+}
+
+func TestGuildPlayer_Replay(t *testing.T) {
+	tests := []struct {
+		name    string
+		current *voice.PlayedSong
+		playing bool
+		wantErr error
+		want    []string
+	}{
+		{
+			name:    "sonando vuelve a encolar la canción al principio",
+			current: &voice.PlayedSong{Song: voice.Song{Title: "a", URL: "https://youtu.be/a", StartPosition: 10 * time.Second}, Position: 42 * time.Second},
+			playing: true,
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "detenido no hay nada que repetir",
+			current: &voice.PlayedSong{Song: voice.Song{Title: "a", URL: "https://youtu.be/a"}},
+			wantErr: ErrNotPlaying,
+			want:    []string{"b", "c"},
+		},
+		{
+			name:    "sin canción actual no hay nada que repetir",
+			playing: true,
+			wantErr: ErrNotPlaying,
+			want:    []string{"b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, songStorage, stateStorage := newTestPlayer("b", "c")
+			require.NoError(t, stateStorage.SetCurrentSong(tt.current))
+			songCtx := context.Background()
+			if tt.playing {
+				songCtx = startTestPlayback(player)
+			}
+			seekTo := 5 * time.Second
+			player.seekTo = &seekTo
+
+			song, err := player.Replay()
+
+			assert.Equal(t, tt.want, queueTitles(t, songStorage))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, song)
+				assert.False(t, player.takeReplayed())
+				_, seeking := player.takeSeek()
+				assert.True(t, seeking, "la posición a la que se movió la canción sigue pendiente")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "a", song.Title)
+			assert.Zero(t, song.StartPosition, "la canción vuelve a arrancar desde el principio")
+			assert.Error(t, songCtx.Err(), "se corta la canción que estaba sonando")
+			assert.True(t, player.takeReplayed())
+			_, seeking := player.takeSeek()
+			assert.False(t, seeking, "se descarta la posición a la que se había movido la canción")
+		})
+	}
+}
+
+func TestGuildPlayer_JumpTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		keep     bool
+		wantErr  error
+		want     []string
+	}{
+		{name: "posición cero", position: 0, wantErr: ErrRemoveInvalidPosition, want: []string{"a", "b", "c"}},
+		{name: "posición negativa", position: -1, wantErr: ErrRemoveInvalidPosition, want: []string{"a", "b", "c"}},
+		{name: "después del final", position: 4, wantErr: ErrRemoveInvalidPosition, want: []string{"a", "b", "c"}},
+		{name: "a la primera no cambia la lista", position: 1, want: []string{"a", "b", "c"}},
+		{name: "descarta las anteriores", position: 2, want: []string{"b", "c"}},
+		{name: "conserva las anteriores después de ella", position: 2, keep: true, want: []string{"b", "a", "c"}},
+		{name: "a la última", position: 3, want: []string{"c"}},
+		{name: "a la última conservando las anteriores", position: 3, keep: true, want: []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		for _, playing := range []bool{false, true} {
+			name := tt.name
+			if playing {
+				name += " sonando"
+			}
+			t.Run(name, func(t *testing.T) {
+				player, songStorage, _ := newTestPlayer("a", "b", "c")
+				songCtx := context.Background()
+				if playing {
+					songCtx = startTestPlayback(player)
+				}
+
+				song, err := player.JumpTo(context.Background(), tt.position, tt.keep)
+
+				assert.Equal(t, tt.want, queueTitles(t, songStorage))
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					assert.Nil(t, song)
+					assert.NoError(t, songCtx.Err(), "la canción actual sigue sonando")
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.want[0], song.Title)
+				// La lista cambió, así que el reproductor recibe la orden de reproducir.
+				select {
+				case trigger := <-player.triggerCh:
+					assert.Equal(t, "play", trigger.Command)
+				case <-time.After(time.Second):
+					t.Fatal("el reproductor no recibió la orden de reproducir")
+				}
+				if playing {
+					assert.Error(t, songCtx.Err(), "se salta la canción que estaba sonando")
+					assert.True(t, player.takeSkipped())
+				}
+			})
+		}
+	}
+}
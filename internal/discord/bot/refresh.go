@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"go.uber.org/zap"
+	"time"
+)
+
+// resolveAheadSongs es la cantidad de canciones próximas de la lista cuya metadata sin resolver se busca mientras
+// suena la actual, para que no haya que esperarla al pasar de canción.
+const resolveAheadSongs = 2
+
+// WithMetadataRefresh hace que el reproductor vuelva a validar la metadata de cada canción justo antes de
+// reproducirla, si es más vieja que ttl o si es una transmisión en vivo, y que complete la de las canciones de
+// playlists sin resolver. Con un ttl de 0 solo se completan las sin resolver. Las canciones que ya no están
+// disponibles se saltean avisando en el canal de texto.
+func (p *GuildPlayer) WithMetadataRefresh(r fetcher.SongRefresher, ttl time.Duration) *GuildPlayer {
+	p.refresher = r
+	p.metadataTTL = ttl
+	return p
+}
+
+// staleMetadata indica si hay que revalidar la metadata de la canción: las sin resolver nunca se buscaron, las
+// transmisiones en vivo cambian de estado y duración, y el resto envejece con el tiempo.
+func (p *GuildPlayer) staleMetadata(song *voice.Song) bool {
+	if song.Unresolved {
+		return true
+	}
+	if p.metadataTTL <= 0 {
+		return false
+	}
+	if !song.Playable || song.LookedUpAt == nil {
+		return true
+	}
+	return time.Since(*song.LookedUpAt) >= p.metadataTTL
+}
+
+// refreshSong revalida la metadata de la canción si hace falta y devuelve la que se tiene que reproducir, que puede
+// ser otra si la original ya no está disponible. Devuelve false si hay que saltearla. Si no se puede revalidar por
+// otro motivo, se reproduce con la metadata que tenía.
+func (p *GuildPlayer) refreshSong(ctx context.Context, logger logging.Logger, textChannel string, song *voice.Song) (*voice.Song, bool) {
+	if p.refresher == nil || !p.staleMetadata(song) {
+		return song, true
+	}
+	refreshed, err := p.refresher.RefreshSong(ctx, song)
+	if err != nil {
+		if !errors.Is(err, fetcher.ErrSongUnavailable) {
+			logger.Warn("No se pudo revalidar la metadata de la canción; se reproduce con la guardada", zap.String("URL", song.URL), zap.Error(err))
+			return song, true
+		}
+		logger.Info("La canción ya no está disponible; se saltea", zap.String("URL", song.URL))
+		p.recordPlayback("song_unavailable", song)
+		p.sendNote(logger, textChannel, fmt.Sprintf("⏭️ Salteé **%s**: ya no está disponible.", song.GetHumanName()))
+		return nil, false
+	}
+
+	if refreshed.CanonicalID() != song.CanonicalID() {
+		if reason := p.rejectReason(refreshed); reason != "" {
+			logger.Info("El reemplazo de la canción no disponible fue rechazado", zap.String("URL", refreshed.URL), zap.String("motivo", reason))
+			p.sendNote(logger, textChannel, fmt.Sprintf("⏭️ Salteé **%s**: ya no está disponible.", song.GetHumanName()))
+			return nil, false
+		}
+		logger.Info("La canción ya no está disponible; se reemplaza", zap.String("URL", song.URL), zap.String("reemplazo", refreshed.URL))
+		p.sendNote(logger, textChannel, fmt.Sprintf("🔁 **%s** ya no está disponible, en su lugar va **%s**.", song.GetHumanName(), refreshed.GetHumanName()))
+	}
+	return refreshed, true
+}
+
+// resolveAhead busca en segundo plano la metadata de las próximas canciones sin resolver, que queda en la caché del
+// refresher para cuando les toque sonar.
+func (p *GuildPlayer) resolveAhead(ctx context.Context, logger logging.Logger) {
+	if p.refresher == nil {
+		return
+	}
+	songs, err := p.songStorage.GetSongs()
+	if err != nil {
+		logger.Warn("No se pudieron obtener las próximas canciones para resolverlas", zap.Error(err))
+		return
+	}
+	var pending []*voice.Song
+	for _, song := range songs[:min(len(songs), resolveAheadSongs)] {
+		if song.Unresolved {
+			pending = append(pending, song)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	go func() {
+		for _, song := range pending {
+			if _, err := p.refresher.RefreshSong(ctx, song); err != nil {
+				logger.Warn("No se pudo resolver por adelantado la metadata de la canción", zap.String("URL", song.URL), zap.Error(err))
+			}
+		}
+	}()
+}
+
+func (p *GuildPlayer) sendNote(logger logging.Logger, textChannel, message string) {
+	if err := p.message.SendMessage(textChannel, message); err != nil {
+		logger.Error("Error al avisar en el canal de texto", zap.Error(err))
+	}
+}
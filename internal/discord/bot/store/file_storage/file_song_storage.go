@@ -7,6 +7,7 @@ import (
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"go.uber.org/zap"
 	"os"
+	"slices"
 	"sync"
 )
 
@@ -108,6 +109,59 @@ func (s *FileSongStorage) RemoveSong(position int) (*voice.Song, error) {
 	return song, nil
 }
 
+// MoveSong mueve la canción de la posición from a la posición to de la lista de reproducción.
+func (s *FileSongStorage) MoveSong(from, to int) (*voice.Song, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.persistent.ReadState(s.filepath)
+	if err != nil {
+		s.logger.Error("Error al leer el estado", zap.String("filepath", s.filepath), zap.Error(err))
+		return nil, err
+	}
+
+	if from < 1 || from > len(state.Songs) || to < 1 || to > len(state.Songs) {
+		s.logger.Error("Posición de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+
+	song := state.Songs[from-1]
+	state.Songs = slices.Insert(slices.Delete(state.Songs, from-1, from), to-1, song)
+
+	if err := s.persistent.WriteState(s.filepath, state); err != nil {
+		s.logger.Error("Error al escribir el estado", zap.String("filepath", s.filepath), zap.Error(err))
+		return nil, err
+	}
+
+	return song, nil
+}
+
+// UpdateSongs reemplaza la lista de reproducción por lo que devuelve update a partir de la actual, con una sola
+// escritura del archivo.
+func (s *FileSongStorage) UpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, err := s.persistent.ReadState(s.filepath)
+	if err != nil {
+		s.logger.Error("Error al leer el estado", zap.String("filepath", s.filepath), zap.Error(err))
+		return err
+	}
+
+	songs, err := update(slices.Clone(state.Songs))
+	if err != nil {
+		return err
+	}
+	state.Songs = songs
+
+	if err := s.persistent.WriteState(s.filepath, state); err != nil {
+		s.logger.Error("Error al escribir el estado", zap.String("filepath", s.filepath), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 // ClearPlaylist elimina todas las canciones de la lista de reproducción.
 func (s *FileSongStorage) ClearPlaylist() error {
 	s.mutex.Lock()
@@ -253,6 +253,86 @@ func TestFileSongStorage_RemoveSong_WriteStateError(t *testing.T) {
 	mockLogger.AssertExpectations(t)
 }
 
+func TestFileSongStorage_MoveSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockPersistent := new(MockStatePersistent)
+
+	filepath := "test_state.json"
+	songs := []*voice.Song{{Title: "1"}, {Title: "2"}, {Title: "3"}}
+	initialState := &FileState{
+		Songs: append([]*voice.Song(nil), songs...),
+	}
+
+	mockPersistent.On("ReadState", filepath).Return(initialState, nil)
+	mockPersistent.On("WriteState", filepath, mock.MatchedBy(func(state *FileState) bool {
+		return assert.ObjectsAreEqual([]*voice.Song{songs[2], songs[0], songs[1]}, state.Songs)
+	})).Return(nil)
+
+	storage, err := NewFileSongStorage(filepath, mockLogger, mockPersistent)
+	assert.NoError(t, err)
+
+	moved, err := storage.MoveSong(3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, songs[2], moved)
+
+	mockPersistent.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestFileSongStorage_MoveSong_InvalidPosition(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockPersistent := new(MockStatePersistent)
+
+	filepath := "test_state.json"
+	initialState := &FileState{
+		Songs: []*voice.Song{{Title: "1"}},
+	}
+
+	mockPersistent.On("ReadState", filepath).Return(initialState, nil)
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+	storage, err := NewFileSongStorage(filepath, mockLogger, mockPersistent)
+	assert.NoError(t, err)
+
+	_, err = storage.MoveSong(1, 2)
+	assert.Equal(t, bot.ErrRemoveInvalidPosition, err)
+
+	mockPersistent.AssertNotCalled(t, "WriteState", mock.Anything, mock.Anything)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestFileSongStorage_UpdateSongs(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockPersistent := new(MockStatePersistent)
+
+	filepath := "test_state.json"
+	songs := []*voice.Song{{Title: "1"}, {Title: "2"}, {Title: "3"}}
+	initialState := &FileState{
+		Songs: append([]*voice.Song(nil), songs...),
+	}
+
+	mockPersistent.On("ReadState", filepath).Return(initialState, nil)
+	mockPersistent.On("WriteState", filepath, mock.MatchedBy(func(state *FileState) bool {
+		return assert.ObjectsAreEqual([]*voice.Song{songs[2], songs[1], songs[0]}, state.Songs)
+	})).Return(nil).Once()
+
+	storage, err := NewFileSongStorage(filepath, mockLogger, mockPersistent)
+	assert.NoError(t, err)
+
+	err = storage.UpdateSongs(func(current []*voice.Song) ([]*voice.Song, error) {
+		return []*voice.Song{current[2], current[1], current[0]}, nil
+	})
+	assert.NoError(t, err)
+
+	err = storage.UpdateSongs(func(current []*voice.Song) ([]*voice.Song, error) {
+		return nil, bot.ErrRemoveInvalidPosition
+	})
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+
+	mockPersistent.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
 func TestFileSongStorage_ClearPlaylist(t *testing.T) {
 	mockLogger := new(MockLogger)
 	mockPersistent := new(MockStatePersistent)
@@ -17,6 +17,10 @@ func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) With(fields ...zap.Field) {
 	m.Called(fields)
 }
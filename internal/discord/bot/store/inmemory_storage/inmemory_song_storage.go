@@ -4,6 +4,7 @@ import (
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"slices"
 	"sync"
 )
 
@@ -64,6 +65,36 @@ func (s *InmemorySongStorage) RemoveSong(position int) (*voice.Song, error) {
 	return song, nil
 }
 
+// MoveSong mueve la canción de la posición from a la posición to de la lista de reproducción.
+func (s *InmemorySongStorage) MoveSong(from, to int) (*voice.Song, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if from < 1 || from > len(s.songs) || to < 1 || to > len(s.songs) {
+		s.logger.Info("Posición de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+
+	song := s.songs[from-1]
+	s.songs = slices.Insert(slices.Delete(s.songs, from-1, from), to-1, song)
+	s.logger.Info("Canción movida en la lista de reproducción")
+	return song, nil
+}
+
+// UpdateSongs reemplaza la lista de reproducción por lo que devuelve update a partir de la actual.
+func (s *InmemorySongStorage) UpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	songs, err := update(slices.Clone(s.songs))
+	if err != nil {
+		return err
+	}
+	s.songs = slices.Clone(songs)
+	s.logger.Info("Lista de reproducción actualizada")
+	return nil
+}
+
 // ClearPlaylist elimina todas las canciones de la lista de reproducción.
 func (s *InmemorySongStorage) ClearPlaylist() error {
 	s.mutex.Lock()
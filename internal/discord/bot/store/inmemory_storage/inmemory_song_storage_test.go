@@ -83,6 +83,63 @@ func TestInmemorySongStorage_RemoveSong(t *testing.T) {
 	mockLogger.AssertExpectations(t)
 }
 
+func TestInmemorySongStorage_MoveSong(t *testing.T) {
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+
+	storage := NewInmemorySongStorage(mockLogger)
+	for _, title := range []string{"1", "2", "3", "4"} {
+		assert.NoError(t, storage.AppendSong(&voice.Song{Title: title}))
+	}
+
+	moved, err := storage.MoveSong(4, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", moved.Title)
+
+	moved, err = storage.MoveSong(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", moved.Title)
+
+	songs, err := storage.GetSongs()
+	assert.NoError(t, err)
+	var titles []string
+	for _, song := range songs {
+		titles = append(titles, song.Title)
+	}
+	assert.Equal(t, []string{"4", "2", "3", "1"}, titles)
+
+	_, err = storage.MoveSong(0, 1)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+	_, err = storage.MoveSong(1, 5)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+}
+
+func TestInmemorySongStorage_UpdateSongs(t *testing.T) {
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+
+	storage := NewInmemorySongStorage(mockLogger)
+	for _, title := range []string{"1", "2", "3"} {
+		assert.NoError(t, storage.AppendSong(&voice.Song{Title: title}))
+	}
+
+	err := storage.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		return []*voice.Song{songs[2], songs[0]}, nil
+	})
+	assert.NoError(t, err)
+	songs, err := storage.GetSongs()
+	assert.NoError(t, err)
+	assert.Equal(t, []*voice.Song{{Title: "3"}, {Title: "1"}}, songs)
+
+	err = storage.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		return nil, bot.ErrRemoveInvalidPosition
+	})
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+	songs, err = storage.GetSongs()
+	assert.NoError(t, err)
+	assert.Len(t, songs, 2, "si update falla la lista no cambia")
+}
+
 func TestInmemorySongStorage_GetSongs(t *testing.T) {
 	mockLogger := &MockLogger{}
 	mockLogger.On("Info", "Canción agregada al final de la lista de reproducción", mock.Anything).Return()
@@ -0,0 +1,36 @@
+package redis_storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockCommander struct {
+	mock.Mock
+}
+
+func (m *MockCommander) Do(ctx context.Context, args ...string) (interface{}, error) {
+	ret := m.Called(args)
+	return ret.Get(0), ret.Error(1)
+}
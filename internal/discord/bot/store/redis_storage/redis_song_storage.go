@@ -0,0 +1,267 @@
+package redis_storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"strconv"
+	"time"
+)
+
+// commandTimeout es el tiempo máximo que puede tardar cada comando enviado a Redis.
+const commandTimeout = 5 * time.Second
+
+// removeSongScript elimina atómicamente la canción en la posición indicada y la devuelve.
+const removeSongScript = `
+local song = redis.call('LINDEX', KEYS[1], ARGV[1])
+if not song then return false end
+redis.call('LSET', KEYS[1], ARGV[1], '__eliminada__')
+redis.call('LREM', KEYS[1], 1, '__eliminada__')
+return song`
+
+// moveSongScript mueve atómicamente la canción de una posición a otra y la devuelve. Redis no inserta por índice,
+// así que se reescribe la lista.
+const moveSongScript = `
+local songs = redis.call('LRANGE', KEYS[1], 0, -1)
+local from, to = tonumber(ARGV[1]) + 1, tonumber(ARGV[2]) + 1
+if from > #songs or to > #songs then return false end
+local song = table.remove(songs, from)
+table.insert(songs, to, song)
+redis.call('DEL', KEYS[1])
+for _, item in ipairs(songs) do redis.call('RPUSH', KEYS[1], item) end
+return song`
+
+// replaceSongsScript reemplaza la lista por las canciones de ARGV a partir de ARGV[1] + 2, solo si sigue teniendo las
+// ARGV[1] canciones que se leyeron antes. Devuelve 0 si la lista cambió mientras tanto.
+const replaceSongsScript = `
+local count = tonumber(ARGV[1])
+local songs = redis.call('LRANGE', KEYS[1], 0, -1)
+if #songs ~= count then return 0 end
+for i = 1, count do
+  if songs[i] ~= ARGV[i + 1] then return 0 end
+end
+redis.call('DEL', KEYS[1])
+for i = count + 2, #ARGV do redis.call('RPUSH', KEYS[1], ARGV[i]) end
+return 1`
+
+// updateAttempts es la cantidad de veces que UpdateSongs vuelve a intentar si otro cambia la lista mientras tanto.
+const updateAttempts = 5
+
+// RedisSongStorage implementa la interfaz SongStorage guardando la lista de reproducción en una lista de Redis,
+// para que varias instancias del bot compartan la cola de cada servidor.
+type RedisSongStorage struct {
+	client redis.Commander // client es el cliente de Redis.
+	key    string          // key es la clave de la lista de reproducción del servidor.
+	logger logging.Logger  // logger es un registrador para registrar mensajes de depuración y errores.
+}
+
+// NewRedisSongStorage crea una nueva instancia de RedisSongStorage para el servidor indicado.
+func NewRedisSongStorage(client redis.Commander, guildID string, logger logging.Logger) *RedisSongStorage {
+	return &RedisSongStorage{
+		client: client,
+		key:    "gomusicbot:playlist:" + guildID,
+		logger: logger,
+	}
+}
+
+// PrependSong agrega una canción al principio de la lista de reproducción.
+func (s *RedisSongStorage) PrependSong(song *voice.Song) error {
+	if err := s.push("LPUSH", song); err != nil {
+		return err
+	}
+	s.logger.Info("Canción agregada al principio de la lista de reproducción")
+	return nil
+}
+
+// AppendSong agrega una canción al final de la lista de reproducción.
+func (s *RedisSongStorage) AppendSong(song *voice.Song) error {
+	if err := s.push("RPUSH", song); err != nil {
+		return err
+	}
+	s.logger.Info("Canción agregada al final de la lista de reproducción")
+	return nil
+}
+
+// RemoveSong elimina una canción de la lista de reproducción por posición.
+func (s *RedisSongStorage) RemoveSong(position int) (*voice.Song, error) {
+	index := position - 1
+	if index < 0 {
+		s.logger.Info("Posición de eliminación de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	data, err := redis.String(s.client.Do(ctx, "EVAL", removeSongScript, "1", s.key, strconv.Itoa(index)))
+	if errors.Is(err, redis.ErrNil) {
+		s.logger.Info("Posición de eliminación de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al eliminar la canción de Redis: %w", err)
+	}
+
+	song, err := decodeSong(data)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Canción eliminada de la lista de reproducción")
+	return song, nil
+}
+
+// MoveSong mueve la canción de la posición from a la posición to de la lista de reproducción.
+func (s *RedisSongStorage) MoveSong(from, to int) (*voice.Song, error) {
+	if from < 1 || to < 1 {
+		s.logger.Info("Posición de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	data, err := redis.String(s.client.Do(ctx, "EVAL", moveSongScript, "1", s.key, strconv.Itoa(from-1), strconv.Itoa(to-1)))
+	if errors.Is(err, redis.ErrNil) {
+		s.logger.Info("Posición de canción inválida")
+		return nil, bot.ErrRemoveInvalidPosition
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al mover la canción en Redis: %w", err)
+	}
+
+	song, err := decodeSong(data)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Canción movida en la lista de reproducción")
+	return song, nil
+}
+
+// UpdateSongs reemplaza la lista de reproducción por lo que devuelve update a partir de la actual. Como otras
+// instancias pueden cambiar la lista mientras tanto, solo se escribe si sigue igual a la que se leyó; si no, se
+// vuelve a leer y a llamar a update.
+func (s *RedisSongStorage) UpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) error {
+	for attempt := 0; attempt < updateAttempts; attempt++ {
+		replaced, err := s.tryUpdateSongs(update)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			s.logger.Info("Lista de reproducción actualizada")
+			return nil
+		}
+	}
+	return fmt.Errorf("la lista de reproducción cambió %d veces mientras se actualizaba", updateAttempts)
+}
+
+func (s *RedisSongStorage) tryUpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	items, err := redis.Strings(s.client.Do(ctx, "LRANGE", s.key, "0", "-1"))
+	if err != nil {
+		return false, fmt.Errorf("error al obtener la lista de reproducción de Redis: %w", err)
+	}
+	songs := make([]*voice.Song, 0, len(items))
+	for _, item := range items {
+		song, err := decodeSong(item)
+		if err != nil {
+			return false, err
+		}
+		songs = append(songs, song)
+	}
+
+	updated, err := update(songs)
+	if err != nil {
+		return false, err
+	}
+	args := append([]string{"EVAL", replaceSongsScript, "1", s.key, strconv.Itoa(len(items))}, items...)
+	for _, song := range updated {
+		data, err := json.Marshal(song)
+		if err != nil {
+			return false, fmt.Errorf("error al serializar la canción: %w", err)
+		}
+		args = append(args, string(data))
+	}
+	replaced, err := redis.Int(s.client.Do(ctx, args...))
+	if err != nil {
+		return false, fmt.Errorf("error al actualizar la lista de reproducción en Redis: %w", err)
+	}
+	return replaced == 1, nil
+}
+
+// ClearPlaylist elimina todas las canciones de la lista de reproducción.
+func (s *RedisSongStorage) ClearPlaylist() error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if _, err := s.client.Do(ctx, "DEL", s.key); err != nil {
+		return fmt.Errorf("error al borrar la lista de reproducción de Redis: %w", err)
+	}
+	s.logger.Info("Lista de reproducción borrada")
+	return nil
+}
+
+// GetSongs devuelve todas las canciones de la lista de reproducción.
+func (s *RedisSongStorage) GetSongs() ([]*voice.Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	items, err := redis.Strings(s.client.Do(ctx, "LRANGE", s.key, "0", "-1"))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la lista de reproducción de Redis: %w", err)
+	}
+
+	songs := make([]*voice.Song, 0, len(items))
+	for _, item := range items {
+		song, err := decodeSong(item)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+	s.logger.Info("Obteniendo todas las canciones de la lista de reproducción")
+	return songs, nil
+}
+
+// PopFirstSong elimina y devuelve la primera canción de la lista de reproducción.
+func (s *RedisSongStorage) PopFirstSong() (*voice.Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	data, err := redis.String(s.client.Do(ctx, "LPOP", s.key))
+	if errors.Is(err, redis.ErrNil) {
+		s.logger.Info("No hay canciones para eliminar")
+		return nil, bot.ErrNoSongs
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la primera canción de Redis: %w", err)
+	}
+
+	song, err := decodeSong(data)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Primera canción eliminada de la lista de reproducción")
+	return song, nil
+}
+
+func (s *RedisSongStorage) push(command string, song *voice.Song) error {
+	data, err := json.Marshal(song)
+	if err != nil {
+		return fmt.Errorf("error al serializar la canción: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if _, err := s.client.Do(ctx, command, s.key, string(data)); err != nil {
+		return fmt.Errorf("error al guardar la canción en Redis: %w", err)
+	}
+	return nil
+}
+
+func decodeSong(data string) (*voice.Song, error) {
+	var song voice.Song
+	if err := json.Unmarshal([]byte(data), &song); err != nil {
+		return nil, fmt.Errorf("error al deserializar la canción: %w", err)
+	}
+	return &song, nil
+}
@@ -0,0 +1,133 @@
+package redis_storage
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const playlistKey = "gomusicbot:playlist:guild"
+
+func TestRedisSongStorage_AppendAndPrependSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"RPUSH", playlistKey, `{"Type":"","Title":"1","URL":"","Playable":false,"ThumbnailURL":null,"Duration":0,"StartPosition":0,"RequestedBy":null}`}).Return(int64(1), nil)
+	mockClient.On("Do", mock.MatchedBy(func(args []string) bool { return args[0] == "LPUSH" && args[1] == playlistKey })).Return(int64(2), nil)
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	assert.NoError(t, storage.AppendSong(&voice.Song{Title: "1"}))
+	assert.NoError(t, storage.PrependSong(&voice.Song{Title: "0"}))
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisSongStorage_GetSongs(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"LRANGE", playlistKey, "0", "-1"}).Return([]interface{}{`{"Title":"1"}`, `{"Title":"2"}`}, nil)
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	songs, err := storage.GetSongs()
+
+	assert.NoError(t, err)
+	assert.Len(t, songs, 2)
+	assert.Equal(t, "1", songs[0].Title)
+	assert.Equal(t, "2", songs[1].Title)
+}
+
+func TestRedisSongStorage_RemoveSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"EVAL", removeSongScript, "1", playlistKey, "1"}).Return(`{"Title":"2"}`, nil)
+	mockClient.On("Do", []string{"EVAL", removeSongScript, "1", playlistKey, "9"}).Return(nil, redis.ErrNil)
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	song, err := storage.RemoveSong(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", song.Title)
+
+	_, err = storage.RemoveSong(10)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+
+	_, err = storage.RemoveSong(0)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+}
+
+func TestRedisSongStorage_MoveSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"EVAL", moveSongScript, "1", playlistKey, "2", "0"}).Return(`{"Title":"3"}`, nil)
+	mockClient.On("Do", []string{"EVAL", moveSongScript, "1", playlistKey, "0", "9"}).Return(nil, redis.ErrNil)
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	song, err := storage.MoveSong(3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", song.Title)
+
+	_, err = storage.MoveSong(1, 10)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+
+	_, err = storage.MoveSong(0, 1)
+	assert.ErrorIs(t, err, bot.ErrRemoveInvalidPosition)
+}
+
+func TestRedisSongStorage_UpdateSongs(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	// La primera vez otra instancia cambia la lista entre la lectura y la escritura, así que se vuelve a intentar.
+	mockClient.On("Do", []string{"LRANGE", playlistKey, "0", "-1"}).Return([]interface{}{`{"Title":"1"}`}, nil).Once()
+	mockClient.On("Do", []string{"EVAL", replaceSongsScript, "1", playlistKey, "1", `{"Title":"1"}`, `{"Type":"","Title":"1","URL":"","Playable":false,"ThumbnailURL":null,"Duration":0,"StartPosition":0,"RequestedBy":null}`}).Return(int64(0), nil).Once()
+	mockClient.On("Do", []string{"LRANGE", playlistKey, "0", "-1"}).Return([]interface{}{`{"Title":"1"}`, `{"Title":"2"}`}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(args []string) bool {
+		return len(args) == 9 && args[0] == "EVAL" && args[4] == "2" && strings.Contains(args[7], `"Title":"2"`) && strings.Contains(args[8], `"Title":"1"`)
+	})).Return(int64(1), nil).Once()
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	calls := 0
+	err := storage.UpdateSongs(func(songs []*voice.Song) ([]*voice.Song, error) {
+		calls++
+		slices.Reverse(songs)
+		return songs, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisSongStorage_PopFirstSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"LPOP", playlistKey}).Return(`{"Title":"1"}`, nil).Once()
+	mockClient.On("Do", []string{"LPOP", playlistKey}).Return(nil, redis.ErrNil).Once()
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	song, err := storage.PopFirstSong()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", song.Title)
+
+	_, err = storage.PopFirstSong()
+	assert.ErrorIs(t, err, bot.ErrNoSongs)
+}
+
+func TestRedisSongStorage_ClearPlaylist(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"DEL", playlistKey}).Return(int64(1), nil)
+	storage := NewRedisSongStorage(mockClient, "guild", mockLogger)
+
+	assert.NoError(t, storage.ClearPlaylist())
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,121 @@
+package redis_storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+)
+
+// Campos del hash de Redis donde se guarda el estado del reproductor.
+const (
+	fieldCurrentSong  = "current_song"
+	fieldVoiceChannel = "voice_channel"
+	fieldTextChannel  = "text_channel"
+)
+
+// RedisStateStorage implementa la interfaz StateStorage guardando el estado del reproductor en un hash de Redis.
+type RedisStateStorage struct {
+	client redis.Commander // client es el cliente de Redis.
+	key    string          // key es la clave del hash con el estado del servidor.
+	logger logging.Logger  // logger es un registrador para registrar mensajes de depuración y errores.
+}
+
+// NewRedisStateStorage crea una nueva instancia de RedisStateStorage para el servidor indicado.
+func NewRedisStateStorage(client redis.Commander, guildID string, logger logging.Logger) *RedisStateStorage {
+	return &RedisStateStorage{
+		client: client,
+		key:    "gomusicbot:state:" + guildID,
+		logger: logger,
+	}
+}
+
+// GetCurrentSong devuelve la canción actual que se está reproduciendo.
+func (s *RedisStateStorage) GetCurrentSong() (*voice.PlayedSong, error) {
+	data, err := s.get(fieldCurrentSong)
+	if err != nil || data == "" {
+		return nil, err
+	}
+	var song voice.PlayedSong
+	if err := json.Unmarshal([]byte(data), &song); err != nil {
+		return nil, fmt.Errorf("error al deserializar la canción actual: %w", err)
+	}
+	return &song, nil
+}
+
+// SetCurrentSong establece la canción actual que se está reproduciendo.
+func (s *RedisStateStorage) SetCurrentSong(song *voice.PlayedSong) error {
+	if song == nil {
+		return s.del(fieldCurrentSong)
+	}
+	data, err := json.Marshal(song)
+	if err != nil {
+		return fmt.Errorf("error al serializar la canción actual: %w", err)
+	}
+	return s.set(fieldCurrentSong, string(data))
+}
+
+// GetVoiceChannel devuelve el ID del canal de voz.
+func (s *RedisStateStorage) GetVoiceChannel() (string, error) {
+	s.logger.Info("Obteniendo el canal de voz")
+	return s.get(fieldVoiceChannel)
+}
+
+// SetVoiceChannel establece el ID del canal de voz.
+func (s *RedisStateStorage) SetVoiceChannel(channelID string) error {
+	if err := s.set(fieldVoiceChannel, channelID); err != nil {
+		return err
+	}
+	s.logger.Info("Canal de voz establecido")
+	return nil
+}
+
+// GetTextChannel devuelve el ID del canal de texto.
+func (s *RedisStateStorage) GetTextChannel() (string, error) {
+	s.logger.Info("Obteniendo el canal de texto")
+	return s.get(fieldTextChannel)
+}
+
+// SetTextChannel establece el ID del canal de texto.
+func (s *RedisStateStorage) SetTextChannel(channelID string) error {
+	if err := s.set(fieldTextChannel, channelID); err != nil {
+		return err
+	}
+	s.logger.Info("Canal de texto establecido")
+	return nil
+}
+
+// get devuelve el valor del campo, o un string vacío si no está definido.
+func (s *RedisStateStorage) get(field string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	value, err := redis.String(s.client.Do(ctx, "HGET", s.key, field))
+	if errors.Is(err, redis.ErrNil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error al leer el estado de Redis: %w", err)
+	}
+	return value, nil
+}
+
+func (s *RedisStateStorage) set(field, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if _, err := s.client.Do(ctx, "HSET", s.key, field, value); err != nil {
+		return fmt.Errorf("error al guardar el estado en Redis: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStateStorage) del(field string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if _, err := s.client.Do(ctx, "HDEL", s.key, field); err != nil {
+		return fmt.Errorf("error al guardar el estado en Redis: %w", err)
+	}
+	return nil
+}
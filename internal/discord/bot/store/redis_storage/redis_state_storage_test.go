@@ -0,0 +1,59 @@
+package redis_storage
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+const stateKey = "gomusicbot:state:guild"
+
+func TestRedisStateStorage_CurrentSong(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockClient := new(MockCommander)
+	mockClient.On("Do", mock.MatchedBy(func(args []string) bool {
+		return len(args) == 4 && args[0] == "HSET" && args[1] == stateKey && args[2] == fieldCurrentSong
+	})).Return(int64(1), nil)
+	mockClient.On("Do", []string{"HGET", stateKey, fieldCurrentSong}).Return(`{"Title":"1","Position":1000000000}`, nil).Once()
+	mockClient.On("Do", []string{"HGET", stateKey, fieldCurrentSong}).Return(nil, redis.ErrNil).Once()
+	mockClient.On("Do", []string{"HDEL", stateKey, fieldCurrentSong}).Return(int64(1), nil)
+	storage := NewRedisStateStorage(mockClient, "guild", mockLogger)
+
+	assert.NoError(t, storage.SetCurrentSong(&voice.PlayedSong{Song: voice.Song{Title: "1"}, Position: time.Second}))
+	song, err := storage.GetCurrentSong()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", song.Title)
+	assert.Equal(t, time.Second, song.Position)
+
+	song, err = storage.GetCurrentSong()
+	assert.NoError(t, err)
+	assert.Nil(t, song)
+
+	assert.NoError(t, storage.SetCurrentSong(nil))
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisStateStorage_Channels(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockClient := new(MockCommander)
+	mockClient.On("Do", []string{"HSET", stateKey, fieldVoiceChannel, "voz"}).Return(int64(1), nil)
+	mockClient.On("Do", []string{"HSET", stateKey, fieldTextChannel, "texto"}).Return(int64(1), nil)
+	mockClient.On("Do", []string{"HGET", stateKey, fieldVoiceChannel}).Return("voz", nil)
+	mockClient.On("Do", []string{"HGET", stateKey, fieldTextChannel}).Return("texto", nil)
+	storage := NewRedisStateStorage(mockClient, "guild", mockLogger)
+
+	assert.NoError(t, storage.SetVoiceChannel("voz"))
+	assert.NoError(t, storage.SetTextChannel("texto"))
+
+	voiceChannel, err := storage.GetVoiceChannel()
+	assert.NoError(t, err)
+	assert.Equal(t, "voz", voiceChannel)
+
+	textChannel, err := storage.GetTextChannel()
+	assert.NoError(t, err)
+	assert.Equal(t, "texto", textChannel)
+}
@@ -10,6 +10,12 @@ type SongStorage interface {
 	AppendSong(*voice.Song) error
 	// RemoveSong elimina una canción de la lista de reproducción por su posición.
 	RemoveSong(int) (*voice.Song, error)
+	// MoveSong mueve la canción de una posición a otra de la lista de reproducción y la devuelve.
+	MoveSong(from, to int) (*voice.Song, error)
+	// UpdateSongs reemplaza atómicamente la lista de reproducción por lo que devuelve update a partir de la actual, sin
+	// que se cuelen cambios de otros entre la lectura y la escritura. Si update devuelve un error, la lista no cambia
+	// y se devuelve ese error. update puede llamarse más de una vez y no debe modificar las canciones que recibe.
+	UpdateSongs(update func([]*voice.Song) ([]*voice.Song, error)) error
 	// ClearPlaylist elimina todas las canciones de la lista de reproducción.
 	ClearPlaylist() error
 	// GetSongs devuelve todas las canciones en la lista de reproducción.
@@ -0,0 +1,40 @@
+package bot
+
+import "go.uber.org/zap"
+
+// SkipVote es el resultado de votar para saltar la canción actual.
+type SkipVote struct {
+	Votes   int  // Votes son los votos de quienes siguen en el canal de voz, incluido el nuevo.
+	Needed  int  // Needed son los votos que hacen falta para saltar la canción.
+	Already bool // Already indica que quien votó ya había votado por esta canción.
+	Skipped bool // Skipped indica que se alcanzaron los votos y se saltó la canción.
+}
+
+// VoteSkip registra el voto de userID para saltar la canción actual y la salta si votaron needed de listeners, los
+// IDs de quienes están en el canal de voz. Los votos de quienes se fueron del canal no cuentan, y se descartan
+// cuando empieza la canción siguiente.
+func (p *GuildPlayer) VoteSkip(userID string, listeners []string, needed int) (SkipVote, error) {
+	if !p.IsPlaying() {
+		return SkipVote{}, ErrNotPlaying
+	}
+
+	p.mu.Lock()
+	if p.skipVotes == nil {
+		p.skipVotes = make(map[string]bool)
+	}
+	vote := SkipVote{Needed: needed, Already: p.skipVotes[userID]}
+	p.skipVotes[userID] = true
+	for _, listener := range listeners {
+		if p.skipVotes[listener] {
+			vote.Votes++
+		}
+	}
+	p.mu.Unlock()
+
+	p.logger.Info("Voto para saltar la canción", zap.String("userID", userID), zap.Int("votos", vote.Votes), zap.Int("necesarios", needed))
+	if vote.Votes >= needed {
+		p.SkipSong()
+		vote.Skipped = true
+	}
+	return vote, nil
+}
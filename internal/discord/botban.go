@@ -0,0 +1,190 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BanMiddleware descarta las interacciones de los usuarios que no pueden usar el bot en el servidor, avisándoles con
+// un mensaje que solo ven ellos. Los administradores no quedan bloqueados nunca.
+func (handler *InteractionHandler) BanMiddleware(next InteractionHandlerFunc) InteractionHandlerFunc {
+	return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		if handler.respondBanned(ic) {
+			return
+		}
+		next(s, ic)
+	}
+}
+
+// respondBanned le avisa al usuario si no puede usar el bot en el servidor. Devuelve true si está bloqueado.
+func (handler *InteractionHandler) respondBanned(ic *discordgo.InteractionCreate) bool {
+	if handler.settings == nil || ic.GuildID == "" || isGuildAdmin(ic.Member) {
+		return false
+	}
+	user := interactionUser(ic.Interaction)
+	if user == nil {
+		return false
+	}
+	ban, ok := handler.guildSettings(ic.GuildID).Banned(user.ID)
+	if !ok {
+		return false
+	}
+	// A las sugerencias de autocompletado no se les puede responder con un mensaje.
+	if ic.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		return true
+	}
+	message := "🚫 Un administrador te bloqueó el uso del bot en este servidor"
+	if ban.Reason != "" {
+		message += fmt.Sprintf(". Motivo: %s", ban.Reason)
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el aviso de usuario bloqueado", zap.String("guildID", ic.GuildID), zap.Error(err))
+	}
+	return true
+}
+
+// BotBanAdd bloquea a un usuario para que no pueda usar el bot en el servidor. Solo disponible para administradores,
+// que no se pueden bloquear entre ellos.
+func (handler *InteractionHandler) BotBanAdd(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("BotBanAdd")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+	options := commandOptions(opt)
+	userID := options["user"].Value.(string)
+	var reason string
+	if option, ok := options["reason"]; ok {
+		reason = strings.TrimSpace(option.StringValue())
+	}
+
+	if message := banTargetError(ic, userID); message != "" {
+		handler.respondBotBan(ic, message, nil)
+		return
+	}
+	message := fmt.Sprintf("🚫 <@%s> ya no puede usar el bot en este servidor", userID)
+	_, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		_, banned := g.Bans[userID]
+		if !banned && len(g.Bans) >= settings.MaxBans {
+			message = fmt.Sprintf("🙅 Ya hay %d usuarios bloqueados en el servidor", settings.MaxBans)
+			return
+		}
+		if g.Bans == nil {
+			g.Bans = make(map[string]settings.Ban)
+		}
+		g.Bans[userID] = settings.Ban{Reason: reason, BannedBy: interactionUser(ic.Interaction).ID, At: time.Now()}
+	})
+	handler.respondBotBan(ic, message, err)
+}
+
+// banTargetError devuelve por qué no se puede bloquear al usuario, o "" si se puede.
+func banTargetError(ic *discordgo.InteractionCreate, userID string) string {
+	if user := interactionUser(ic.Interaction); user != nil && user.ID == userID {
+		return "🤷🏽 No te podés bloquear a vos mismo"
+	}
+	resolved := ic.ApplicationCommandData().Resolved
+	if resolved == nil {
+		return ""
+	}
+	if user, ok := resolved.Users[userID]; ok && user.Bot {
+		return "🤷🏽 Los bots no usan mis comandos"
+	}
+	if member, ok := resolved.Members[userID]; ok && isGuildAdmin(member) {
+		return "🙅 No se puede bloquear a un administrador"
+	}
+	return ""
+}
+
+// BotBanRemove vuelve a dejar que un usuario bloqueado use el bot en el servidor. Solo disponible para
+// administradores.
+func (handler *InteractionHandler) BotBanRemove(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("BotBanRemove")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+	userID := commandOptions(opt)["user"].Value.(string)
+	message := fmt.Sprintf("✅ <@%s> puede volver a usar el bot", userID)
+	_, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		if _, ok := g.Bans[userID]; !ok {
+			message = fmt.Sprintf("🤷🏽 <@%s> no estaba bloqueado", userID)
+			return
+		}
+		delete(g.Bans, userID)
+	})
+	handler.respondBotBan(ic, message, err)
+}
+
+// BotBanList muestra los usuarios bloqueados del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) BotBanList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("BotBanList")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:          []*discordgo.MessageEmbed{GenerateBotBansEmbed(handler.guildSettings(ic.GuildID).Bans)},
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	}); err != nil {
+		logger.Error("falló al responder con los usuarios bloqueados", zap.Error(err))
+	}
+}
+
+// respondBotBan responde a los comandos /botban con message, o con el error si no se pudo guardar. No menciona a
+// los usuarios, para no notificarlos.
+func (handler *InteractionHandler) respondBotBan(ic *discordgo.InteractionCreate, message string, err error) {
+	ctx, logger := handler.requestContext(ic)
+	if err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         message,
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	}); err != nil {
+		logger.Error("falló al responder con los usuarios bloqueados", zap.Error(err))
+	}
+}
+
+// GenerateBotBansEmbed genera un embed con los usuarios que no pueden usar el bot, del bloqueo más reciente al más
+// viejo.
+func GenerateBotBansEmbed(bans map[string]settings.Ban) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "🚫 Usuarios bloqueados"}
+	if len(bans) == 0 {
+		embed.Description = "No hay usuarios bloqueados en este servidor"
+		return embed
+	}
+	userIDs := make([]string, 0, len(bans))
+	for userID := range bans {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		return bans[userIDs[i]].At.After(bans[userIDs[j]].At)
+	})
+	builder := strings.Builder{}
+	for _, userID := range userIDs {
+		ban := bans[userID]
+		line := fmt.Sprintf("<@%s> · <t:%d:R>", userID, ban.At.Unix())
+		if ban.Reason != "" {
+			line += " · " + ban.Reason
+		}
+		if builder.Len()+len(line)+1 > maxEmbedDescriptionLength {
+			builder.WriteString("…")
+			break
+		}
+		builder.WriteString(line + "\n")
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
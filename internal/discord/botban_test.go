@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newBanTestInteraction(userID string, permissions int64) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:      "interaccion",
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "1",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: userID}, Permissions: permissions},
+	}}
+}
+
+func TestBanMiddleware(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	session := new(MockSessionService)
+	handler := (&InteractionHandler{ctx: context.Background(), logger: logger, session: session, responseHandler: NewDiscordResponseHandler(logger)}).
+		WithSettings(settings.NewInMemoryStore())
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) {
+		g.Bans = map[string]settings.Ban{"spammer": {Reason: "llenar la cola", At: time.Now()}}
+	})
+	require.NoError(t, err)
+
+	var handled []string
+	next := handler.BanMiddleware(func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		handled = append(handled, interactionUser(ic.Interaction).ID)
+	})
+	session.On("InteractionRespond", mock.Anything, mock.MatchedBy(func(r *discordgo.InteractionResponse) bool {
+		return r.Data.Flags == discordgo.MessageFlagsEphemeral && strings.Contains(r.Data.Content, "llenar la cola")
+	})).Return(nil).Once()
+
+	next(nil, newBanTestInteraction("spammer", 0))
+	next(nil, newBanTestInteraction("otro", 0))
+	// Los administradores no quedan bloqueados aunque estén en la lista.
+	_, err = settings.Update(handler.settings, "1", func(g *settings.Guild) { g.Bans["admin"] = settings.Ban{} })
+	require.NoError(t, err)
+	next(nil, newBanTestInteraction("admin", discordgo.PermissionAdministrator))
+
+	assert.Equal(t, []string{"otro", "admin"}, handled)
+	session.AssertExpectations(t)
+}
+
+func TestBanTargetError(t *testing.T) {
+	ic := newBanTestInteraction("admin", discordgo.PermissionAdministrator)
+	ic.Data = discordgo.ApplicationCommandInteractionData{Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+		Users: map[string]*discordgo.User{"bot": {ID: "bot", Bot: true}, "otro-admin": {ID: "otro-admin"}, "usuario": {ID: "usuario"}},
+		Members: map[string]*discordgo.Member{
+			"otro-admin": {Permissions: discordgo.PermissionManageServer},
+			"usuario":    {},
+		},
+	}}
+
+	assert.NotEmpty(t, banTargetError(ic, "admin"))
+	assert.NotEmpty(t, banTargetError(ic, "bot"))
+	assert.NotEmpty(t, banTargetError(ic, "otro-admin"))
+	assert.Empty(t, banTargetError(ic, "usuario"))
+}
+
+func TestGenerateBotBansEmbed(t *testing.T) {
+	assert.Equal(t, "No hay usuarios bloqueados en este servidor", GenerateBotBansEmbed(nil).Description)
+
+	at := time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC)
+	embed := GenerateBotBansEmbed(map[string]settings.Ban{
+		"viejo": {At: at},
+		"nuevo": {Reason: "spam", At: at.Add(time.Hour)},
+	})
+	assert.Equal(t, "<@nuevo> · <t:1709935200:R> · spam\n<@viejo> · <t:1709931600:R>", embed.Description)
+}
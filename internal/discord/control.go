@@ -0,0 +1,219 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"go.uber.org/zap"
+	"runtime"
+	"time"
+)
+
+// InteractionHandler implementa control.Controller para que las APIs externas manejen los reproductores.
+var _ control.Controller = (*InteractionHandler)(nil)
+
+// playerFor devuelve el reproductor del servidor, si existe.
+func (handler *InteractionHandler) playerFor(guildID string) (*bot.GuildPlayer, error) {
+	handler.playersMu.RLock()
+	defer handler.playersMu.RUnlock()
+	player, ok := handler.guildsPlayers[GuildID(guildID)]
+	if !ok {
+		return nil, control.ErrPlayerNotFound
+	}
+	return player, nil
+}
+
+// ListPlayers devuelve el estado de los reproductores de todos los servidores.
+func (handler *InteractionHandler) ListPlayers() []control.PlayerStatus {
+	handler.playersMu.RLock()
+	players := make(map[GuildID]*bot.GuildPlayer, len(handler.guildsPlayers))
+	for guildID, player := range handler.guildsPlayers {
+		players[guildID] = player
+	}
+	handler.playersMu.RUnlock()
+
+	statuses := make([]control.PlayerStatus, 0, len(players))
+	for guildID, player := range players {
+		status := control.PlayerStatus{GuildID: string(guildID), Playing: player.IsPlaying()}
+		if songs, err := player.GetSongs(); err == nil {
+			status.QueueLength = len(songs)
+		}
+		if status.Playing {
+			status.CurrentSong, _ = player.GetPlayedSong()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// GetQueue devuelve la lista de reproducción del servidor y la canción que está sonando.
+func (handler *InteractionHandler) GetQueue(guildID string) (*control.Queue, error) {
+	player, err := handler.playerFor(guildID)
+	if err != nil {
+		return nil, err
+	}
+	songs, err := player.GetSongs()
+	if err != nil {
+		return nil, err
+	}
+	currentSong, err := player.GetPlayedSong()
+	if err != nil {
+		return nil, err
+	}
+	return &control.Queue{CurrentSong: currentSong, Songs: songs}, nil
+}
+
+// Enqueue busca la canción y la agrega a la lista de reproducción del servidor. Si la búsqueda devuelve una
+// lista de reproducción, se agregan todas sus canciones.
+func (handler *InteractionHandler) Enqueue(ctx context.Context, req control.EnqueueRequest) ([]*voice.Song, error) {
+	player, err := handler.playerFor(req.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	var voiceChannelID, textChannelID *string
+	if req.VoiceChannelID != "" {
+		voiceChannelID = &req.VoiceChannelID
+	} else if stored, err := player.GetVoiceChannel(); err != nil || stored == "" {
+		return nil, control.ErrNoVoiceChannel
+	}
+	if req.TextChannelID != "" {
+		textChannelID = &req.TextChannelID
+	}
+
+	videoID, err := handler.songLookup.SearchYouTubeVideoID(ctx, req.Input)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar la canción: %w", err)
+	}
+	songs, err := handler.songLookup.LookupSongs(ctx, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la metadata de la canción: %w", err)
+	}
+	if len(songs) == 0 {
+		return nil, control.ErrNoSongsFound
+	}
+	if req.RequestedBy != "" {
+		for _, song := range songs {
+			song.RequestedBy = &req.RequestedBy
+		}
+	}
+
+	if err := player.AddSong(ctx, textChannelID, voiceChannelID, songs...); err != nil {
+		var rejected *bot.RejectedSongsError
+		if !errors.As(err, &rejected) {
+			return nil, err
+		}
+		if rejected.Added == 0 {
+			return nil, fmt.Errorf("%w: %s", control.ErrSongsRejected, rejected.Rejections[0].Reason)
+		}
+		return addedSongs(songs, rejected), nil
+	}
+	return songs, nil
+}
+
+// addedSongs devuelve las canciones que no rechazó ningún filtro.
+func addedSongs(songs []*voice.Song, rejected *bot.RejectedSongsError) []*voice.Song {
+	added := make([]*voice.Song, 0, rejected.Added)
+	for _, song := range songs {
+		if !rejected.Rejected(song) {
+			added = append(added, song)
+		}
+	}
+	return added
+}
+
+// Skip salta la canción actual del servidor.
+func (handler *InteractionHandler) Skip(guildID string) error {
+	player, err := handler.playerFor(guildID)
+	if err != nil {
+		return err
+	}
+	player.SkipSong()
+	return nil
+}
+
+// Stop detiene la reproducción del servidor y limpia su lista de reproducción.
+func (handler *InteractionHandler) Stop(guildID string) error {
+	player, err := handler.playerFor(guildID)
+	if err != nil {
+		return err
+	}
+	return player.Stop()
+}
+
+// SetVolume cambia el volumen de la reproducción del servidor.
+func (handler *InteractionHandler) SetVolume(ctx context.Context, guildID string, volume int) error {
+	if volume < control.MinVolume || volume > control.MaxVolume {
+		return control.ErrInvalidVolume
+	}
+	player, err := handler.playerFor(guildID)
+	if err != nil {
+		return err
+	}
+	if err := player.SetVolume(ctx, volume); err != nil {
+		if errors.Is(err, bot.ErrVolumeNotSupported) {
+			return control.ErrVolumeNotSupported
+		}
+		return err
+	}
+	return nil
+}
+
+// Stats devuelve las estadísticas generales del bot.
+func (handler *InteractionHandler) Stats() control.Stats {
+	stats := control.Stats{
+		Uptime:     time.Since(handler.startedAt),
+		Goroutines: runtime.NumGoroutine(),
+	}
+	for _, status := range handler.ListPlayers() {
+		stats.Guilds++
+		stats.QueuedSongs += status.QueueLength
+		if status.Playing {
+			stats.ActivePlayers++
+		}
+	}
+	return stats
+}
+
+// GuildSettings devuelve la configuración del servidor.
+func (handler *InteractionHandler) GuildSettings(guildID string) (settings.Guild, error) {
+	if handler.settings == nil {
+		return settings.Guild{GuildID: guildID}, nil
+	}
+	return handler.settings.Get(guildID)
+}
+
+// UpdateGuildSettings le aplica update a la configuración del servidor, la guarda y aplica el volumen máximo a la
+// reproducción en curso. Como /settings maxvolume, no se puede poner un volumen máximo si el backend de audio no
+// permite cambiar el volumen: devuelve control.ErrVolumeNotSupported.
+func (handler *InteractionHandler) UpdateGuildSettings(ctx context.Context, guildID string, update func(*settings.Guild)) (settings.Guild, error) {
+	if handler.settings == nil {
+		return settings.Guild{}, errors.New("la configuración por servidor no está habilitada")
+	}
+	current, err := handler.settings.Get(guildID)
+	if err != nil {
+		return settings.Guild{}, err
+	}
+	updated := current
+	update(&updated)
+	if updated.MaxVolume != current.MaxVolume && updated.MaxVolume > 0 && !handler.volumeSupported() {
+		return settings.Guild{}, control.ErrVolumeNotSupported
+	}
+
+	guild, err := settings.Update(handler.settings, guildID, update)
+	if err != nil {
+		return settings.Guild{}, err
+	}
+	if guild.MaxVolume != current.MaxVolume {
+		if player, err := handler.playerFor(guildID); err == nil {
+			if err := player.ApplyVolumeLimit(ctx); err != nil {
+				handler.logger.Warn("No se pudo aplicar el volumen máximo a la reproducción en curso", zap.Error(err))
+			}
+		}
+	}
+	return guild, nil
+}
@@ -0,0 +1,52 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+)
+
+// CorrelationMiddleware genera un ID de correlación para cada interacción. Los manejadores lo obtienen con
+// requestContext y lo propagan a la búsqueda y la reproducción, de modo que los reportes de los usuarios
+// ("código de error ab12cd") se puedan encontrar en los logs.
+func (handler *InteractionHandler) CorrelationMiddleware(next InteractionHandlerFunc) InteractionHandlerFunc {
+	return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		handler.correlationIDs.Store(ic.ID, logging.NewCorrelationID())
+		defer handler.correlationIDs.Delete(ic.ID)
+		next(s, ic)
+	}
+}
+
+// requestContext devuelve el contexto y el logger de la interacción, con su ID de correlación.
+// Debe llamarse de forma sincrónica dentro del manejador, antes de lanzar cualquier goroutine.
+func (handler *InteractionHandler) requestContext(ic *discordgo.InteractionCreate) (context.Context, logging.Logger) {
+	ctx := handler.ctx
+	if id, ok := handler.correlationIDs.Load(ic.ID); ok {
+		ctx = logging.ContextWithCorrelationID(ctx, id.(string))
+	}
+	return ctx, logging.FromContext(ctx, handler.logger)
+}
+
+// withErrorCode agrega el código de error (ID de correlación) a un mensaje de error para el usuario.
+func withErrorCode(ctx context.Context, message string) string {
+	id := logging.CorrelationIDFromContext(ctx)
+	if id == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (código de error `%s`)", message, id)
+}
+
+// withErrorCodeEmbed agrega el código de error (ID de correlación) al pie de un embed de error.
+func withErrorCodeEmbed(ctx context.Context, embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	id := logging.CorrelationIDFromContext(ctx)
+	if id == "" {
+		return embed
+	}
+	code := fmt.Sprintf("Código de error: %s", id)
+	if embed.Footer != nil && embed.Footer.Text != "" {
+		code = fmt.Sprintf("%s · %s", embed.Footer.Text, code)
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: code}
+	return embed
+}
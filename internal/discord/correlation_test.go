@@ -0,0 +1,35 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCorrelationMiddleware(t *testing.T) {
+	handler := &InteractionHandler{ctx: context.Background(), logger: new(MockLogger)}
+	ic := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{ID: "interaction-1"}}
+
+	var id string
+	handler.CorrelationMiddleware(func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		ctx, _ := handler.requestContext(ic)
+		id = logging.CorrelationIDFromContext(ctx)
+	})(nil, ic)
+
+	assert.Len(t, id, 6)
+	ctx, logger := handler.requestContext(ic)
+	assert.Empty(t, logging.CorrelationIDFromContext(ctx), "el ID se descarta al terminar la interacción")
+	assert.Same(t, handler.logger, logger)
+}
+
+func TestWithErrorCode(t *testing.T) {
+	ctx := logging.ContextWithCorrelationID(context.Background(), "ab12cd")
+
+	assert.Equal(t, "Ocurrió un error (código de error `ab12cd`)", withErrorCode(ctx, "Ocurrió un error"))
+	assert.Equal(t, "Ocurrió un error", withErrorCode(context.Background(), "Ocurrió un error"))
+
+	embed := withErrorCodeEmbed(ctx, GenerateFailedToAddSongEmbed("tema", &discordgo.Member{User: &discordgo.User{Username: "tomas"}}))
+	assert.Equal(t, "Pedido por: tomas · Código de error: ab12cd", embed.Footer.Text)
+}
@@ -0,0 +1,203 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRecentErrors es la cantidad de errores recientes de cada servidor que muestra /debug.
+	maxRecentErrors = 5
+	// maxRecentErrorLength es el largo máximo de cada error en /debug, para que entren todos en el campo del embed.
+	maxRecentErrorLength = 180
+)
+
+// recentError es un error que logueó el reproductor de un servidor.
+type recentError struct {
+	At      time.Time
+	Message string
+}
+
+// recentErrors guarda los últimos errores del reproductor de un servidor.
+type recentErrors struct {
+	mu      sync.Mutex
+	entries []recentError
+}
+
+func (r *recentErrors) add(entry recentError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	if len(r.entries) > maxRecentErrors {
+		r.entries = r.entries[len(r.entries)-maxRecentErrors:]
+	}
+}
+
+// list devuelve los errores guardados, del más reciente al más viejo.
+func (r *recentErrors) list() []recentError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := slices.Clone(r.entries)
+	slices.Reverse(entries)
+	return entries
+}
+
+// errorLogger es el logger del reproductor de un servidor: además de loguear, guarda los errores para /debug.
+type errorLogger struct {
+	logging.Logger
+	errors *recentErrors
+}
+
+func (l errorLogger) Error(msg string, fields ...zap.Field) {
+	l.Logger.Error(msg, fields...)
+	for _, field := range fields {
+		if err, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType {
+			msg = fmt.Sprintf("%s: %v", msg, err)
+			break
+		}
+	}
+	l.errors.add(recentError{At: time.Now(), Message: msg})
+}
+
+// guildErrors devuelve los errores recientes del reproductor del servidor.
+func (handler *InteractionHandler) guildErrors(guildID string) *recentErrors {
+	errors, _ := handler.recentErrors.LoadOrStore(guildID, &recentErrors{})
+	return errors.(*recentErrors)
+}
+
+// isBotOwner indica si el usuario es uno de los dueños del bot configurados en OWNERS.
+func (handler *InteractionHandler) isBotOwner(user *discordgo.User) bool {
+	return user != nil && slices.Contains(handler.cfg.Owners, user.ID)
+}
+
+// debugReport es el estado interno del reproductor de un servidor que muestra /debug.
+type debugReport struct {
+	State             bot.PlayerState // State es "" si el servidor no tiene reproductor.
+	QueueLength       int
+	CurrentSong       *voice.PlayedSong
+	Volume            int
+	VoiceConnection   string
+	GatewayLatency    time.Duration
+	MetadataCacheSize int
+	AudioCacheSize    int
+	Errors            []recentError
+}
+
+// Debug muestra el estado interno del reproductor del servidor, para diagnosticar problemas en vivo. Solo
+// disponible para los dueños del bot.
+func (handler *InteractionHandler) Debug(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Debug")
+	if !handler.isBotOwner(interactionUser(ic.Interaction)) {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, ErrorMessageOwnerOnly); err != nil {
+			logger.Error("falló al responder con el error de permisos", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{generateDebugEmbed(handler.debugReport(s, ic.GuildID))},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con el estado del reproductor", zap.Error(err))
+	}
+}
+
+// debugReport junta el estado del reproductor, la conexión de voz, las cachés y los errores recientes del servidor.
+func (handler *InteractionHandler) debugReport(s *discordgo.Session, guildID string) debugReport {
+	report := debugReport{
+		VoiceConnection: handler.voiceConnectionStatus(s, guildID),
+		GatewayLatency:  s.HeartbeatLatency(),
+		Errors:          handler.guildErrors(guildID).list(),
+	}
+	if handler.caching != nil {
+		report.MetadataCacheSize = handler.caching.Size()
+	}
+	if handler.audioCaching != nil {
+		report.AudioCacheSize = handler.audioCaching.Size()
+	}
+	player, err := handler.playerFor(guildID)
+	if err != nil {
+		return report
+	}
+	report.State = player.State()
+	report.Volume = player.Volume()
+	if songs, err := player.GetSongs(); err == nil {
+		report.QueueLength = len(songs)
+	}
+	if report.State == bot.StatePlaying || report.State == bot.StatePaused {
+		report.CurrentSong, _ = player.GetPlayedSong()
+	}
+	return report
+}
+
+// voiceConnectionStatus describe la conexión de voz del bot en el servidor.
+func (handler *InteractionHandler) voiceConnectionStatus(s *discordgo.Session, guildID string) string {
+	if handler.lavalink != nil {
+		return "Gestionada por Lavalink"
+	}
+	s.RLock()
+	vc, ok := s.VoiceConnections[guildID]
+	s.RUnlock()
+	if !ok || vc == nil {
+		return "Sin conexión"
+	}
+	vc.RLock()
+	defer vc.RUnlock()
+	if !vc.Ready {
+		return fmt.Sprintf("Conectando a <#%s>", vc.ChannelID)
+	}
+	return fmt.Sprintf("Lista en <#%s>", vc.ChannelID)
+}
+
+// generateDebugEmbed arma el embed de /debug.
+func generateDebugEmbed(report debugReport) *discordgo.MessageEmbed {
+	state := "Sin reproductor"
+	if report.State != "" {
+		state = string(report.State)
+	}
+	current := "Nada"
+	if report.CurrentSong != nil {
+		current = fmt.Sprintf("%s (%s / %s)", report.CurrentSong.GetHumanName(), report.CurrentSong.Position.Truncate(time.Second), report.CurrentSong.Duration)
+	}
+	errors := "Ninguno"
+	if len(report.Errors) > 0 {
+		errors = ""
+		for _, entry := range report.Errors {
+			message := []rune(entry.Message)
+			if len(message) > maxRecentErrorLength {
+				message = append(message[:maxRecentErrorLength-1], '…')
+			}
+			line := fmt.Sprintf("<t:%d:R> %s\n", entry.At.Unix(), string(message))
+			if len(errors)+len(line) > maxEmbedFieldLength {
+				break
+			}
+			errors += line
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "🛠️ Estado del reproductor",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Estado", Value: state, Inline: true},
+			{Name: "Canciones en la lista", Value: fmt.Sprint(report.QueueLength), Inline: true},
+			{Name: "Volumen", Value: fmt.Sprintf("%d%%", report.Volume), Inline: true},
+			{Name: "Canción actual", Value: current},
+			{Name: "Conexión de voz", Value: report.VoiceConnection, Inline: true},
+			{Name: "Latencia del gateway", Value: report.GatewayLatency.Truncate(time.Millisecond).String(), Inline: true},
+			{Name: "Cachés", Value: fmt.Sprintf("Metadata: %d · Audio: %d", report.MetadataCacheSize, report.AudioCacheSize), Inline: true},
+			{Name: "Errores recientes", Value: errors},
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorLogger(t *testing.T) {
+	base := new(MockLogger)
+	base.On("Error", mock.Anything, mock.Anything).Return()
+	errs := &recentErrors{}
+	logger := errorLogger{Logger: base, errors: errs}
+
+	logger.Error("Error al unirse al canal de voz", zap.String("channelID", "voz"), zap.Error(errors.New("timeout")))
+	for i := 0; i < maxRecentErrors; i++ {
+		logger.Error(fmt.Sprintf("error %d", i))
+	}
+
+	entries := errs.list()
+	require.Len(t, entries, maxRecentErrors)
+	assert.Equal(t, "error 4", entries[0].Message, "el más reciente va primero")
+	assert.Equal(t, "error 0", entries[maxRecentErrors-1].Message, "se descarta el más viejo")
+	base.AssertNumberOfCalls(t, "Error", maxRecentErrors+1)
+
+	errs = &recentErrors{}
+	errorLogger{Logger: base, errors: errs}.Error("Error al unirse al canal de voz", zap.Error(errors.New("timeout")))
+	assert.Equal(t, "Error al unirse al canal de voz: timeout", errs.list()[0].Message)
+}
+
+func TestIsBotOwner(t *testing.T) {
+	handler := &InteractionHandler{cfg: &config.Config{Owners: []string{"dueño"}}}
+
+	assert.True(t, handler.isBotOwner(&discordgo.User{ID: "dueño"}))
+	assert.False(t, handler.isBotOwner(&discordgo.User{ID: "otro"}))
+	assert.False(t, handler.isBotOwner(nil))
+}
+
+func TestDebugReport(t *testing.T) {
+	handler, player, _, _ := newPresenceTestHandler()
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	handler.guildErrors("1").add(recentError{At: time.Now(), Message: "Error al obtener datos DCA"})
+
+	report := handler.debugReport(&discordgo.Session{}, "1")
+
+	assert.Equal(t, bot.StateInterrupted, report.State)
+	assert.Equal(t, 1, report.QueueLength)
+	assert.Equal(t, 100, report.Volume)
+	assert.Nil(t, report.CurrentSong, "la canción guardada no está sonando")
+	assert.Equal(t, "Sin conexión", report.VoiceConnection)
+	require.Len(t, report.Errors, 1)
+
+	report = handler.debugReport(&discordgo.Session{}, "2")
+	assert.Equal(t, bot.PlayerState(""), report.State)
+}
+
+func TestGenerateDebugEmbed(t *testing.T) {
+	embed := generateDebugEmbed(debugReport{
+		State:           bot.StatePlaying,
+		QueueLength:     3,
+		CurrentSong:     &voice.PlayedSong{Song: voice.Song{Title: "La Bamba", Duration: 3 * time.Minute}, Position: 42*time.Second + 300*time.Millisecond},
+		Volume:          80,
+		VoiceConnection: "Lista en <#voz>",
+		Errors: []recentError{
+			{At: time.Unix(1700000000, 0), Message: strings.Repeat("x", 500)},
+			{At: time.Unix(1600000000, 0), Message: "Error al unirse al canal de voz: timeout"},
+		},
+	})
+
+	fields := map[string]string{}
+	for _, field := range embed.Fields {
+		fields[field.Name] = field.Value
+	}
+	assert.Equal(t, "playing", fields["Estado"])
+	assert.Equal(t, "3", fields["Canciones en la lista"])
+	assert.Equal(t, "80%", fields["Volumen"])
+	assert.Equal(t, "La Bamba (42s / 3m0s)", fields["Canción actual"])
+	assert.Contains(t, fields["Errores recientes"], "<t:1600000000:R> Error al unirse al canal de voz: timeout")
+	assert.Contains(t, fields["Errores recientes"], strings.Repeat("x", maxRecentErrorLength-1)+"…")
+	assert.LessOrEqual(t, len(fields["Errores recientes"]), maxEmbedFieldLength)
+
+	embed = generateDebugEmbed(debugReport{VoiceConnection: "Sin conexión"})
+	assert.Equal(t, "Sin reproductor", embed.Fields[0].Value)
+	assert.Equal(t, "Ninguno", embed.Fields[len(embed.Fields)-1].Value)
+}
@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// Dedupe quita de la lista de reproducción del servidor las canciones repetidas, dejando la primera de cada una, y
+// responde cuántas quitó. Las canciones se comparan por CanonicalID, así que se reconocen aunque tengan URLs distintas.
+func (handler *InteractionHandler) Dedupe(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("Dedupe")
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	message := handler.dedupeQueue(ctx, logger, player)
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a /dedupe", zap.Error(err))
+	}
+}
+
+// errNoDuplicates indica que la lista de reproducción no tiene canciones repetidas.
+var errNoDuplicates = errors.New("no hay canciones repetidas")
+
+// dedupeQueue quita las canciones repetidas de la lista de reproducción y devuelve el mensaje para quien usó el
+// comando.
+func (handler *InteractionHandler) dedupeQueue(ctx context.Context, logger logging.Logger, player *bot.GuildPlayer) string {
+	var removed int
+	err := player.UpdateSongs(ctx, func(songs []*voice.Song) ([]*voice.Song, error) {
+		unique := dedupeSongs(songs)
+		removed = len(songs) - len(unique)
+		if removed == 0 {
+			return nil, errNoDuplicates
+		}
+		return unique, nil
+	})
+	switch {
+	case errors.Is(err, errNoDuplicates):
+		return "👌 No hay canciones repetidas en la lista de reproducción"
+	case err != nil:
+		logger.Error("falló al quitar las canciones repetidas", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al quitar las canciones repetidas")
+	case removed == 1:
+		return "🧹 Quité 1 canción repetida de la lista de reproducción"
+	default:
+		return fmt.Sprintf("🧹 Quité %d canciones repetidas de la lista de reproducción", removed)
+	}
+}
+
+// dedupeSongs devuelve las canciones sin las repetidas, conservando la primera aparición de cada una y el orden.
+func dedupeSongs(songs []*voice.Song) []*voice.Song {
+	seen := make(map[string]bool, len(songs))
+	unique := make([]*voice.Song, 0, len(songs))
+	for _, song := range songs {
+		id := song.CanonicalID()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, song)
+	}
+	return unique
+}
@@ -0,0 +1,22 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDedupeSongs(t *testing.T) {
+	songs := []*voice.Song{
+		{Title: "A", URL: "https://www.youtube.com/watch?v=a"},
+		{Title: "B", URL: "https://www.youtube.com/watch?v=b"},
+		{Title: "A (corto)", URL: "https://youtu.be/a"},
+		{Title: "C", URL: "https://example.com/c"},
+		{Title: "B", URL: "https://www.youtube.com/watch?v=b"},
+	}
+
+	unique := dedupeSongs(songs)
+
+	assert.Equal(t, []*voice.Song{songs[0], songs[1], songs[3]}, unique)
+	assert.Len(t, songs, 5)
+}
@@ -1,6 +1,7 @@
 package discordmessenger
 
 import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/rest"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/bwmarrin/discordgo"
@@ -49,8 +50,11 @@ func NewMessageSenderImpl(discordSession MessageSenderWrapper, logger logging.Lo
 // SendMessage envía un mensaje de texto a un canal específico en Discord.
 func (session *MessageSenderImpl) SendMessage(channelID, message string) error {
 	session.logger.Info("Enviando mensaje al canal", zap.String("mensaje", message), zap.String("channel", channelID))
-	_, err := session.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-		Content: message,
+	err := rest.Retry(rest.DefaultRetryPolicy, session.logger, func() error {
+		_, err := session.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content: message,
+		})
+		return err
 	})
 	if err != nil {
 		session.logger.Error("Error al enviar el mensaje: ", zap.Error(err))
@@ -63,8 +67,14 @@ func (session *MessageSenderImpl) SendMessage(channelID, message string) error {
 func (session *MessageSenderImpl) SendPlayMessage(channelID string, message *voice.PlayMessage) (string, error) {
 	session.logger.Info("Enviando mensaje de reproducción...")
 	// Enviar el mensaje de reproducción al canal especificado.
-	msg, err := session.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-		Embed: voice.GeneratePlayingSongEmbed(message),
+	var msg *discordgo.Message
+	err := rest.Retry(rest.DefaultRetryPolicy, session.logger, func() error {
+		var err error
+		msg, err = session.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Embed:      voice.GeneratePlayingSongEmbed(message),
+			Components: voice.GeneratePlayingSongControls(message.Song),
+		})
+		return err
 	})
 	if err != nil {
 		session.logger.Error("Error al enviar mensaje de reproducción: ", zap.Error(err))
@@ -0,0 +1,78 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// DJOnly envuelve el manejador de un comando que cambia la lista de reproducción o el reproductor (detener, eliminar,
+// saltar, mezclar, pausar, cambiar de cola, mover el bot y demás) para que, si el servidor configuró un rol de DJ, solo
+// lo puedan usar quienes tienen ese rol o permisos de administración. Sin rol de DJ cualquiera puede usarlo.
+func (handler *InteractionHandler) DJOnly(next func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption) {
+	return func(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+		if handler.djAllowed(ic) {
+			next(s, ic, opt)
+		}
+	}
+}
+
+// DJOnlyComponent es DJOnly para los botones que cambian el reproductor, como los de adelantar o elegir un capítulo.
+func (handler *InteractionHandler) DJOnlyComponent(next func(*discordgo.Session, *discordgo.InteractionCreate)) func(*discordgo.Session, *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		if handler.djAllowed(ic) {
+			next(s, ic)
+		}
+	}
+}
+
+// djAllowed indica si quien hizo la interacción puede usar los comandos de DJ. Si no, se lo avisa.
+func (handler *InteractionHandler) djAllowed(ic *discordgo.InteractionCreate) bool {
+	guild := handler.guildSettings(ic.GuildID)
+	if guild.DJRole != "" && !isDJ(ic.Member, guild) {
+		handler.respondDJOnly(ic, guild)
+		return false
+	}
+	return true
+}
+
+// isDJ indica si el miembro tiene el rol de DJ del servidor o permisos de administración.
+func isDJ(member *discordgo.Member, guild settings.Guild) bool {
+	return isGuildAdmin(member) || member != nil && guild.HasDJRole(member.Roles)
+}
+
+// respondDJOnly le avisa al miembro que el comando es solo para quienes tienen el rol de DJ.
+func (handler *InteractionHandler) respondDJOnly(ic *discordgo.InteractionCreate, guild settings.Guild) {
+	message := fmt.Sprintf("🎧 Solo quienes tienen el rol <@&%s> pueden usar este comando", guild.DJRole)
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el aviso del rol de DJ", zap.String("guildID", ic.GuildID), zap.Error(err))
+	}
+}
+
+// SetDJRole configura el rol de DJ del servidor: solo quienes lo tienen, o tienen permisos de administración, pueden
+// cambiar la lista de reproducción o el reproductor, más allá de agregar canciones, o saltar canciones sin votar. Sin
+// rol, cualquiera puede. Solo disponible para administradores.
+func (handler *InteractionHandler) SetDJRole(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetDJRole")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	var roleID string
+	if option, ok := commandOptions(opt)["role"]; ok {
+		roleID = option.Value.(string)
+	}
+	message := "⚙️ Sin rol de DJ: cualquiera puede manejar la lista de reproducción y el reproductor"
+	if roleID != "" {
+		message = fmt.Sprintf("⚙️ Rol de DJ: <@&%s>. Solo ese rol y los administradores pueden manejar la lista de reproducción y el reproductor, más allá de agregar canciones, y saltar canciones sin votar", roleID)
+	}
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.DJRole = roleID }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
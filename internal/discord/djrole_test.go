@@ -0,0 +1,42 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDJOnly(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	session := new(MockSessionService)
+	handler := (&InteractionHandler{ctx: context.Background(), logger: logger, session: session, responseHandler: NewDiscordResponseHandler(logger)}).
+		WithSettings(settings.NewInMemoryStore())
+
+	var handled []string
+	next := handler.DJOnly(func(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+		handled = append(handled, interactionUser(ic.Interaction).ID)
+	})
+	dj := newBanTestInteraction("dj", 0)
+	dj.Member.Roles = []string{"rol-dj"}
+
+	// Sin rol de DJ cualquiera puede usar el comando.
+	next(nil, newBanTestInteraction("sin-rol", 0), nil)
+
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.DJRole = "rol-dj" })
+	require.NoError(t, err)
+	session.On("InteractionRespond", mock.Anything, mock.MatchedBy(func(r *discordgo.InteractionResponse) bool {
+		return r.Data.Flags == discordgo.MessageFlagsEphemeral && strings.Contains(r.Data.Content, "<@&rol-dj>")
+	})).Return(nil).Once()
+	next(nil, newBanTestInteraction("sin-rol", 0), nil)
+	next(nil, dj, nil)
+	next(nil, newBanTestInteraction("admin", discordgo.PermissionManageServer), nil)
+
+	assert.Equal(t, []string{"sin-rol", "dj", "admin"}, handled)
+	session.AssertExpectations(t)
+}
@@ -0,0 +1,201 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// DuplicateAddCustomID y DuplicateSkipCustomID son los botones con los que quien pidió una canción repetida
+	// confirma si la agrega igual.
+	DuplicateAddCustomID  = "duplicate_song_add"
+	DuplicateSkipCustomID = "duplicate_song_skip"
+	// duplicateConfirmTimeout es el tiempo que se espera la confirmación antes de descartar la canción.
+	duplicateConfirmTimeout = 5 * time.Minute
+)
+
+// pendingDuplicate es una canción repetida que espera la confirmación de quien la pidió.
+type pendingDuplicate struct {
+	player         *bot.GuildPlayer
+	song           *voice.Song
+	textChannelID  string
+	voiceChannelID string
+}
+
+// WithSettings establece la configuración por servidor, por ejemplo qué hacer con las canciones repetidas.
+func (handler *InteractionHandler) WithSettings(store settings.Store) *InteractionHandler {
+	handler.settings = store
+	return handler
+}
+
+// guildSettings devuelve la configuración del servidor. Sin store, o si falla, devuelve la configuración por defecto.
+func (handler *InteractionHandler) guildSettings(guildID string) settings.Guild {
+	if handler.settings == nil {
+		return settings.Guild{GuildID: guildID}
+	}
+	guild, err := handler.settings.Get(guildID)
+	if err != nil {
+		handler.logger.Error("falló al obtener la configuración del servidor", zap.String("guildID", guildID), zap.Error(err))
+		return settings.Guild{GuildID: guildID}
+	}
+	return guild
+}
+
+// isQueued indica si la canción ya está sonando o en la lista de reproducción, comparando sus IDs canónicos.
+func isQueued(player *bot.GuildPlayer, song *voice.Song) bool {
+	id := song.CanonicalID()
+	if played, err := player.GetPlayedSong(); err == nil && played != nil && played.CanonicalID() == id {
+		return true
+	}
+	songs, err := player.GetSongs()
+	if err != nil {
+		return false
+	}
+	for _, queued := range songs {
+		if queued.CanonicalID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// handleDuplicate aplica la política del servidor si la canción ya está en la lista de reproducción. Devuelve true si
+// se encargó de responder y la canción no se tiene que agregar todavía.
+func (handler *InteractionHandler) handleDuplicate(ctx context.Context, player *bot.GuildPlayer, interaction *discordgo.Interaction, voiceChannelID string, song *voice.Song) bool {
+	policy := handler.guildSettings(interaction.GuildID).DuplicatePolicy()
+	if policy == settings.DuplicatesAllow || !isQueued(player, song) {
+		return false
+	}
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", interaction.GuildID))
+	logger.Info("Canción repetida", zap.String("URL", song.URL), zap.String("policy", string(policy)))
+
+	if policy == settings.DuplicatesSkip {
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{GenerateDuplicateSongEmbed(song, interaction.Member, "🔁 Ya está en la cola, no se agregó de nuevo.")},
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de canción repetida", zap.Error(err))
+		}
+		return true
+	}
+
+	key := pendingDuplicateKey(interaction.ChannelID, interactionUser(interaction).ID)
+	pending := &pendingDuplicate{player: player, song: song, textChannelID: interaction.ChannelID, voiceChannelID: voiceChannelID}
+	handler.pendingDuplicates.Store(key, pending)
+	time.AfterFunc(duplicateConfirmTimeout, func() {
+		handler.pendingDuplicates.CompareAndDelete(key, pending)
+	})
+
+	if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{GenerateDuplicateSongEmbed(song, interaction.Member, "🔁 Ya está en la cola. ¿La agrego igual?")},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "Agregar igual", Style: discordgo.PrimaryButton, CustomID: DuplicateAddCustomID},
+					discordgo.Button{Label: "Cancelar", Style: discordgo.SecondaryButton, CustomID: DuplicateSkipCustomID},
+				},
+			},
+		},
+	}); err != nil {
+		logger.Error("falló al enviar la confirmación de canción repetida", zap.Error(err))
+	}
+	return true
+}
+
+// ConfirmDuplicateSong maneja los botones de la confirmación de una canción repetida. Solo responde a quien la pidió.
+func (handler *InteractionHandler) ConfirmDuplicateSong(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	value, ok := handler.pendingDuplicates.LoadAndDelete(pendingDuplicateKey(ic.ChannelID, interactionUser(ic.Interaction).ID))
+	if !ok {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Solo quien pidió la canción puede confirmarla, o ya pasó demasiado tiempo"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	pending := value.(*pendingDuplicate)
+
+	embed := GenerateDuplicateSongEmbed(pending.song, ic.Member, "🙅 No se agregó.")
+	if ic.MessageComponentData().CustomID == DuplicateAddCustomID {
+		handler.commandUsageCounter.Inc("ConfirmDuplicateSong")
+		if err := pending.player.AddSong(ctx, &pending.textChannelID, &pending.voiceChannelID, pending.song); err != nil {
+			logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", pending.song.URL))
+			embed = withErrorCodeEmbed(ctx, GenerateFailedToAddSongEmbed(pending.song.GetHumanName(), ic.Member))
+			var rejected *bot.RejectedSongsError
+			if errors.As(err, &rejected) {
+				embed = GenerateRejectedSongsEmbed(rejected, ic.Member)
+			}
+		} else {
+			handler.recordAddedSongs(ic.GuildID, interactionUser(ic.Interaction).ID, []*voice.Song{pending.song}, nil)
+			embed = handler.tagExplicit(ic.GuildID, pending.song, GenerateAddedSongEmbed(pending.song, ic.Member))
+		}
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	}); err != nil {
+		logger.Error("falló al responder la confirmación de canción repetida", zap.Error(err))
+	}
+}
+
+// SetDuplicatePolicy cambia qué hace el servidor con las canciones repetidas. Solo disponible para administradores.
+func (handler *InteractionHandler) SetDuplicatePolicy(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetDuplicatePolicy")
+	if !isGuildAdmin(ic.Member) {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, ErrorMessageAdminOnly); err != nil {
+			logger.Error("falló al responder con el error de permisos", zap.Error(err))
+		}
+		return
+	}
+	if handler.settings == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "La configuración por servidor no está habilitada"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	policy, err := settings.ParseDuplicatePolicy(commandOptions(opt)["policy"].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Opción no válida"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.Duplicates = policy }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al guardar la configuración")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("⚙️ Canciones repetidas: %s", describeDuplicatePolicy(policy))); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+func describeDuplicatePolicy(policy settings.DuplicatePolicy) string {
+	switch policy {
+	case settings.DuplicatesSkip:
+		return "no se agregan"
+	case settings.DuplicatesAllow:
+		return "se agregan sin preguntar"
+	default:
+		return "se pregunta a quien la pidió"
+	}
+}
+
+// pendingDuplicateKey identifica la confirmación pendiente de un usuario en un canal.
+func pendingDuplicateKey(channelID, userID string) string {
+	return channelID + "/" + userID
+}
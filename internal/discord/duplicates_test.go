@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/inmemory_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func newDuplicateTestHandler(policy settings.DuplicatePolicy) (*InteractionHandler, *MockSessionService, *bot.GuildPlayer) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	session := new(MockSessionService)
+	store := settings.NewInMemoryStore()
+	_ = store.Put(settings.Guild{GuildID: "1", Duplicates: policy})
+	handler := (&InteractionHandler{ctx: context.Background(), logger: logger, session: session, responseHandler: NewDiscordResponseHandler(logger)}).
+		WithSettings(store)
+
+	songStorage := inmemory_storage.NewInmemorySongStorage(logger)
+	_ = songStorage.AppendSong(&voice.Song{Title: "La Bamba", URL: "https://www.youtube.com/watch?v=abc"})
+	player := bot.NewGuildPlayer(context.Background(), "1", nil, songStorage, inmemory_storage.NewInmemoryStateStorage(logger), nil, nil, logger)
+	return handler, session, player
+}
+
+func newDuplicateTestInteraction() *discordgo.Interaction {
+	return &discordgo.Interaction{GuildID: "1", ChannelID: "texto", Member: &discordgo.Member{User: &discordgo.User{ID: "usuario"}}}
+}
+
+func TestHandleDuplicate_NotQueued(t *testing.T) {
+	handler, session, player := newDuplicateTestHandler(settings.DuplicatesConfirm)
+
+	handled := handler.handleDuplicate(context.Background(), player, newDuplicateTestInteraction(), "voz", &voice.Song{URL: "https://youtu.be/otra"})
+
+	assert.False(t, handled)
+	session.AssertNotCalled(t, "FollowupMessageCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleDuplicate_Allow(t *testing.T) {
+	handler, _, player := newDuplicateTestHandler(settings.DuplicatesAllow)
+
+	assert.False(t, handler.handleDuplicate(context.Background(), player, newDuplicateTestInteraction(), "voz", &voice.Song{URL: "https://youtu.be/abc"}))
+}
+
+func TestHandleDuplicate_Skip(t *testing.T) {
+	handler, session, player := newDuplicateTestHandler(settings.DuplicatesSkip)
+	interaction := newDuplicateTestInteraction()
+	session.On("FollowupMessageCreate", interaction, true, mock.MatchedBy(func(params *discordgo.WebhookParams) bool {
+		return len(params.Components) == 0
+	})).Return(&discordgo.Message{}, nil)
+
+	assert.True(t, handler.handleDuplicate(context.Background(), player, interaction, "voz", &voice.Song{URL: "https://youtu.be/abc"}))
+	session.AssertExpectations(t)
+	_, pending := handler.pendingDuplicates.Load(pendingDuplicateKey("texto", "usuario"))
+	assert.False(t, pending)
+}
+
+func TestHandleDuplicate_Confirm(t *testing.T) {
+	handler, session, player := newDuplicateTestHandler(settings.DuplicatesConfirm)
+	interaction := newDuplicateTestInteraction()
+	session.On("FollowupMessageCreate", interaction, true, mock.MatchedBy(func(params *discordgo.WebhookParams) bool {
+		return len(params.Components) == 1
+	})).Return(&discordgo.Message{}, nil)
+
+	assert.True(t, handler.handleDuplicate(context.Background(), player, interaction, "voz", &voice.Song{URL: "https://youtu.be/abc"}))
+	session.AssertExpectations(t)
+
+	// Otro usuario no puede confirmar la canción pedida por el primero.
+	other := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "click",
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   "1",
+		ChannelID: "texto",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "otro"}},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: DuplicateSkipCustomID},
+	}}
+	session.On("InteractionRespond", other.Interaction, mock.Anything).Return(nil)
+	handler.ConfirmDuplicateSong(nil, other)
+	_, pending := handler.pendingDuplicates.Load(pendingDuplicateKey("texto", "usuario"))
+	assert.True(t, pending)
+}
@@ -0,0 +1,65 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// explicitTag es la marca que se agrega al título de las canciones explícitas cuando el servidor las marca.
+const explicitTag = "🔞 "
+
+// taggedExplicit indica si la canción se marca como explícita en los mensajes del servidor.
+func (handler *InteractionHandler) taggedExplicit(guildID string, song *voice.Song) bool {
+	return handler.settings != nil && handler.guildSettings(guildID).TagExplicit(song)
+}
+
+// tagExplicit marca el embed de la canción como explícito si el servidor marca las canciones explícitas.
+func (handler *InteractionHandler) tagExplicit(guildID string, song *voice.Song, embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	if !handler.taggedExplicit(guildID, song) {
+		return embed
+	}
+	embed.Title = explicitTag + embed.Title
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Contenido", Value: "Explícito", Inline: true})
+	return embed
+}
+
+// SetExplicitPolicy cambia qué hace el servidor con las canciones explícitas. Solo disponible para administradores.
+func (handler *InteractionHandler) SetExplicitPolicy(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetExplicitPolicy")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	policy, err := settings.ParseExplicitPolicy(commandOptions(opt)["policy"].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Opción no válida"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.Explicit = policy }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al guardar la configuración")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("⚙️ Canciones explícitas: %s", describeExplicitPolicy(policy))); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+func describeExplicitPolicy(policy settings.ExplicitPolicy) string {
+	switch policy {
+	case settings.ExplicitTag:
+		return "se agregan marcadas con " + explicitTag
+	case settings.ExplicitReject:
+		return "no se agregan"
+	default:
+		return "se agregan sin marcar"
+	}
+}
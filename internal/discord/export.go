@@ -0,0 +1,149 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos en los que se puede exportar la lista de reproducción.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatM3U  = "m3u"
+)
+
+// exportedSong es una canción de la lista de reproducción exportada.
+type exportedSong struct {
+	Position    int    `json:"position"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	RequestedBy string `json:"requested_by,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+}
+
+// EncodeQueue codifica las canciones de la lista de reproducción en el formato indicado y devuelve el contenido y
+// su tipo MIME.
+func EncodeQueue(songs []*voice.Song, format string) ([]byte, string, error) {
+	exported := make([]exportedSong, 0, len(songs))
+	for idx, song := range songs {
+		entry := exportedSong{
+			Position:   idx + 1,
+			Title:      song.Title,
+			URL:        song.URL,
+			DurationMs: song.Duration.Milliseconds(),
+		}
+		if song.RequestedBy != nil {
+			entry.RequestedBy = *song.RequestedBy
+		}
+		exported = append(exported, entry)
+	}
+
+	switch format {
+	case ExportFormatJSON:
+		data, err := json.MarshalIndent(exported, "", "  ")
+		if err != nil {
+			return nil, "", err
+		}
+		return data, "application/json", nil
+	case ExportFormatCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"position", "title", "url", "requested_by", "duration"})
+		for _, song := range exported {
+			_ = w.Write([]string{
+				strconv.Itoa(song.Position),
+				song.Title,
+				song.URL,
+				song.RequestedBy,
+				utils.FmtDuration(time.Duration(song.DurationMs) * time.Millisecond),
+			})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "text/csv", nil
+	case ExportFormatM3U:
+		// Las duraciones van en segundos; -1 indica que no se conoce, como en las transmisiones en vivo.
+		var buf bytes.Buffer
+		buf.WriteString("#EXTM3U\n")
+		for _, song := range exported {
+			seconds := int64(-1)
+			if song.DurationMs > 0 {
+				seconds = song.DurationMs / 1000
+			}
+			fmt.Fprintf(&buf, "#EXTINF:%d,%s\n%s\n", seconds, strings.ReplaceAll(song.Title, "\n", " "), song.URL)
+		}
+		return buf.Bytes(), "audio/x-mpegurl", nil
+	default:
+		return nil, "", fmt.Errorf("formato de exportación desconocido: %s", format)
+	}
+}
+
+// ExportQueue envía la lista de reproducción como un archivo adjunto JSON, CSV o M3U, para guardarla o compartirla.
+func (handler *InteractionHandler) ExportQueue(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	handler.commandUsageCounter.Inc("ExportQueue")
+	songs, err := player.GetSongs()
+	if err != nil {
+		logger.Error("falló al obtener la lista de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la lista de reproducción")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if len(songs) == 0 {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🫙 La lista de reproducción está vacía"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	format := ExportFormatJSON
+	for _, option := range opt.Options {
+		if option.Name == "format" {
+			format = option.StringValue()
+		}
+	}
+	data, contentType, err := EncodeQueue(songs, format)
+	if err != nil {
+		logger.Error("falló al exportar la lista de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al exportar la lista de reproducción")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("📤 Lista de reproducción exportada (%d canciones)", len(songs)),
+			Files: []*discordgo.File{{
+				Name:        fmt.Sprintf("cola-%s.%s", time.Now().Format("20060102-150405"), format),
+				ContentType: contentType,
+				Reader:      bytes.NewReader(data),
+			}},
+		},
+	}); err != nil {
+		logger.Error("falló al responder con la lista de reproducción exportada", zap.Error(err))
+	}
+}
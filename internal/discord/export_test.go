@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"encoding/json"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func exportTestSongs() []*voice.Song {
+	requester := "tomas"
+	return []*voice.Song{
+		{Title: "La Bamba", URL: "https://youtube.com/watch?v=1", Duration: 3*time.Minute + 5*time.Second, RequestedBy: &requester},
+		{Title: "Uno, dos", URL: "https://youtube.com/watch?v=2", Duration: time.Hour + 2*time.Second},
+	}
+}
+
+func TestEncodeQueue_JSON(t *testing.T) {
+	data, contentType, err := EncodeQueue(exportTestSongs(), ExportFormatJSON)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	var songs []exportedSong
+	assert.NoError(t, json.Unmarshal(data, &songs))
+	assert.Equal(t, []exportedSong{
+		{Position: 1, Title: "La Bamba", URL: "https://youtube.com/watch?v=1", RequestedBy: "tomas", DurationMs: 185000},
+		{Position: 2, Title: "Uno, dos", URL: "https://youtube.com/watch?v=2", DurationMs: 3602000},
+	}, songs)
+}
+
+func TestEncodeQueue_CSV(t *testing.T) {
+	data, contentType, err := EncodeQueue(exportTestSongs(), ExportFormatCSV)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", contentType)
+	assert.Equal(t, "position,title,url,requested_by,duration\n"+
+		"1,La Bamba,https://youtube.com/watch?v=1,tomas,03:05\n"+
+		"2,\"Uno, dos\",https://youtube.com/watch?v=2,,01:00:02\n", string(data))
+}
+
+func TestEncodeQueue_M3U(t *testing.T) {
+	songs := append(exportTestSongs(), &voice.Song{Title: "En vivo", URL: "https://youtube.com/watch?v=3"})
+	data, contentType, err := EncodeQueue(songs, ExportFormatM3U)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "audio/x-mpegurl", contentType)
+	assert.Equal(t, "#EXTM3U\n"+
+		"#EXTINF:185,La Bamba\nhttps://youtube.com/watch?v=1\n"+
+		"#EXTINF:3602,Uno, dos\nhttps://youtube.com/watch?v=2\n"+
+		"#EXTINF:-1,En vivo\nhttps://youtube.com/watch?v=3\n", string(data))
+}
+
+func TestEncodeQueue_UnknownFormat(t *testing.T) {
+	_, _, err := EncodeQueue(exportTestSongs(), "xml")
+
+	assert.Error(t, err)
+}
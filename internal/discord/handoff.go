@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+)
+
+// ActiveGuilds devuelve los servidores con una reproducción en curso.
+func (handler *InteractionHandler) ActiveGuilds() []string {
+	handler.playersMu.RLock()
+	defer handler.playersMu.RUnlock()
+
+	var guilds []string
+	for guildID, player := range handler.guildsPlayers {
+		if player.IsPlaying() {
+			guilds = append(guilds, string(guildID))
+		}
+	}
+	return guilds
+}
+
+// SuspendGuild detiene la reproducción del servidor sin perder la lista ni la posición, para traspasarla a otra instancia.
+func (handler *InteractionHandler) SuspendGuild(ctx context.Context, guildID string) error {
+	handler.playersMu.RLock()
+	player, ok := handler.guildsPlayers[GuildID(guildID)]
+	handler.playersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return player.Drain(ctx)
+}
+
+// ResumeGuild retoma la reproducción que dejó otra instancia en el servidor. El reproductor ya existe porque
+// se crea al recibir el evento GuildCreate.
+func (handler *InteractionHandler) ResumeGuild(_ context.Context, guildID string) error {
+	handler.playersMu.RLock()
+	player, ok := handler.guildsPlayers[GuildID(guildID)]
+	handler.playersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no hay un reproductor para el servidor %s", guildID)
+	}
+	return player.Resume()
+}
+
+// InterruptedGuilds devuelve los servidores con una reproducción a medias que no está sonando en esta instancia.
+func (handler *InteractionHandler) InterruptedGuilds() []string {
+	handler.playersMu.RLock()
+	players := make(map[GuildID]*bot.GuildPlayer, len(handler.guildsPlayers))
+	for guildID, player := range handler.guildsPlayers {
+		players[guildID] = player
+	}
+	handler.playersMu.RUnlock()
+
+	var guilds []string
+	for guildID, player := range players {
+		if player.Interrupted() {
+			guilds = append(guilds, string(guildID))
+		}
+	}
+	return guilds
+}
+
+// YieldGuild sale del canal de voz del servidor porque otra instancia tomó su conexión de voz.
+func (handler *InteractionHandler) YieldGuild(ctx context.Context, guildID string) error {
+	handler.playersMu.RLock()
+	player, ok := handler.guildsPlayers[GuildID(guildID)]
+	handler.playersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return player.Yield(ctx)
+}
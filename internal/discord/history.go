@@ -0,0 +1,110 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+const (
+	// HistoryPlayCustomID es el menú de /history que vuelve a agregar a la cola una canción del historial.
+	HistoryPlayCustomID = "history_play"
+	// historyLimit es la cantidad de canciones que muestra /history; el menú de Discord acepta hasta 25 opciones.
+	historyLimit = 15
+	// historyLabelMaxLength es el largo máximo del texto de una opción del menú que acepta Discord.
+	historyLabelMaxLength = 100
+)
+
+// History muestra las últimas canciones que sonaron en el servidor, con quién las pidió y cuándo, y un menú para
+// volver a agregar una a la cola.
+func (handler *InteractionHandler) History(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("History")
+	if handler.history == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "El historial de reproducción no está habilitado"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	entries, err := handler.history.Query(history.Filter{GuildID: ic.GuildID, Limit: historyLimit})
+	if err != nil {
+		logger.Error("falló al consultar el historial de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al consultar el historial")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if len(entries) == 0 {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🫙 Todavía no sonó ninguna canción en este servidor"); err != nil {
+			logger.Error("falló al responder con el historial vacío", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{GenerateHistoryEmbed(entries)},
+			Components: historyComponents(entries),
+		},
+	}); err != nil {
+		logger.Error("falló al responder con el historial", zap.Error(err))
+	}
+}
+
+// HistoryPlay maneja el menú de /history: agrega a la cola del canal de voz de quien lo usa la canción elegida.
+func (handler *InteractionHandler) HistoryPlay(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("HistoryPlay")
+	values := ic.MessageComponentData().Values
+	if len(values) == 0 {
+		logger.Error("el menú del historial no tiene una canción elegida")
+		return
+	}
+	handler.searchAndAdd(ctx, s, ic, values[0])
+}
+
+// GenerateHistoryEmbed genera el embed de /history: una línea por canción, de la más reciente a la más antigua, con
+// quién la pidió y cuándo sonó en el formato de tiempo de Discord.
+func GenerateHistoryEmbed(entries []history.Entry) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		line := fmt.Sprintf("`%d.` %s", i+1, entry.Title)
+		if entry.UserID != "" {
+			line += fmt.Sprintf(" · <@%s>", entry.UserID)
+		}
+		lines = append(lines, fmt.Sprintf("%s · <t:%d:R>", line, entry.PlayedAt.Unix()))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "🕘 Últimas canciones",
+		Description: strings.Join(lines, "\n"),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Elegí una en el menú para volver a agregarla a la cola"},
+	}
+}
+
+// historyComponents devuelve el menú para volver a agregar una canción del historial, sin las que no tienen URL ni
+// las repetidas.
+func historyComponents(entries []history.Entry) []discordgo.MessageComponent {
+	var options []discordgo.SelectMenuOption
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		if entry.URL == "" || seen[entry.URL] {
+			continue
+		}
+		seen[entry.URL] = true
+		label := fmt.Sprintf("%d. %s", i+1, entry.Title)
+		if runes := []rune(label); len(runes) > historyLabelMaxLength {
+			label = string(runes[:historyLabelMaxLength-1]) + "…"
+		}
+		options = append(options, discordgo.SelectMenuOption{Label: label, Value: entry.URL})
+	}
+	if len(options) == 0 {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.SelectMenu{CustomID: HistoryPlayCustomID, Placeholder: "Volver a poner una canción", Options: options},
+	}}}
+}
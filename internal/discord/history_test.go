@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateHistoryEmbed(t *testing.T) {
+	playedAt := time.Unix(1700000000, 0)
+	entries := []history.Entry{
+		{Title: "De música ligera", UserID: "123", URL: "https://youtu.be/a", PlayedAt: playedAt},
+		{Title: "Sin URL", PlayedAt: playedAt},
+		{Title: "De música ligera", UserID: "456", URL: "https://youtu.be/a", PlayedAt: playedAt},
+		{Title: strings.Repeat("x", 120), URL: "https://youtu.be/b", PlayedAt: playedAt},
+	}
+
+	embed := GenerateHistoryEmbed(entries)
+	lines := strings.Split(embed.Description, "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "`1.` De música ligera · <@123> · <t:1700000000:R>", lines[0])
+	assert.Equal(t, "`2.` Sin URL · <t:1700000000:R>", lines[1])
+
+	rows := historyComponents(entries)
+	require.Len(t, rows, 1)
+	menu := rows[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	assert.Equal(t, HistoryPlayCustomID, menu.CustomID)
+	// Sin las canciones sin URL ni las repetidas.
+	require.Len(t, menu.Options, 2)
+	assert.Equal(t, "https://youtu.be/a", menu.Options[0].Value)
+	assert.Len(t, []rune(menu.Options[1].Label), historyLabelMaxLength)
+
+	assert.Empty(t, historyComponents([]history.Entry{{Title: "Sin URL"}}))
+}
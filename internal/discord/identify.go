@@ -0,0 +1,251 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/identify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// IdentifyQueueCustomID es el botón que agrega a la cola la canción que reconoció /identify.
+	IdentifyQueueCustomID = "identify_queue"
+	// identifyMaxClipSize es el tamaño máximo del audio que se descarga para reconocerlo, el límite de los adjuntos
+	// de Discord sin Nitro.
+	identifyMaxClipSize = 25 << 20
+	// identifyTimeout es el tiempo máximo para descargar y reconocer el audio.
+	identifyTimeout = time.Minute
+	// identifyTitleField e identifyArtistField son los campos del embed de la canción reconocida, de donde el botón
+	// toma la búsqueda para agregarla a la cola.
+	identifyTitleField  = "Título"
+	identifyArtistField = "Artista"
+)
+
+var (
+	// messageLink es el enlace a un mensaje de Discord, como https://discord.com/channels/<servidor>/<canal>/<mensaje>.
+	messageLink = regexp.MustCompile(`^https://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/\d+/(\d+)/(\d+)$`)
+	// snowflake es un ID de Discord.
+	snowflake = regexp.MustCompile(`^\d+$`)
+
+	errNoClip          = errors.New("no hay un audio para reconocer")
+	errClipNotAudio    = errors.New("el adjunto no es un audio ni un video")
+	errClipTooLarge    = errors.New("el adjunto es demasiado grande")
+	errMessageNotFound = errors.New("no se encontró el mensaje")
+)
+
+// WithIdentifier establece el reconocedor de canciones, que habilita /identify.
+func (handler *InteractionHandler) WithIdentifier(identifier identify.Identifier) *InteractionHandler {
+	handler.identifier = identifier
+	return handler
+}
+
+// Identify reconoce la canción de un audio adjunto al comando, o del primer audio de un mensaje del canal, y ofrece
+// un botón para agregarla a la cola.
+func (handler *InteractionHandler) Identify(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Identify")
+	if handler.identifier == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "El reconocimiento de canciones no está habilitado"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	options := commandOptions(opt)
+	var attachment *discordgo.MessageAttachment
+	var reference string
+	if option, ok := options["clip"]; ok {
+		if resolved := ic.ApplicationCommandData().Resolved; resolved != nil {
+			attachment = resolved.Attachments[option.Value.(string)]
+		}
+	}
+	if option, ok := options["message"]; ok {
+		reference = strings.TrimSpace(option.StringValue())
+	}
+	if attachment == nil && reference == "" {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Adjuntá un audio o pasame el enlace de un mensaje que tenga uno"); err != nil {
+			logger.Error("falló al responder con el error de /identify", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		params := discordgo.WebhookParams{}
+		match, err := handler.identifyClip(ctx, s, ic.ChannelID, attachment, reference)
+		if err != nil {
+			logger.Info("falló al reconocer la canción", zap.Error(err))
+			params.Content = identifyErrorMessage(ctx, err)
+		} else {
+			params.Embeds = []*discordgo.MessageEmbed{GenerateIdentifiedSongEmbed(match, ic.Member)}
+			params.Components = []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{Label: "Agregar a la cola", Style: discordgo.PrimaryButton, CustomID: IdentifyQueueCustomID, Emoji: &discordgo.ComponentEmoji{Name: "➕"}},
+					},
+				},
+			}
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, params); err != nil {
+			logger.Error("falló al enviar la canción reconocida", zap.Error(err))
+		}
+	}()
+}
+
+// IdentifyQueue maneja el botón de la canción reconocida: la busca y la agrega a la cola del canal de voz de quien
+// lo aprieta.
+func (handler *InteractionHandler) IdentifyQueue(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("IdentifyQueue")
+	match, ok := identifiedSong(ic.Message)
+	if !ok {
+		logger.Error("el mensaje no tiene una canción reconocida")
+		return
+	}
+	handler.searchAndAdd(ctx, s, ic, match.Query())
+}
+
+// identifyClip descarga el audio, del adjunto o del mensaje de reference, y lo reconoce.
+func (handler *InteractionHandler) identifyClip(ctx context.Context, s *discordgo.Session, channelID string, attachment *discordgo.MessageAttachment, reference string) (*identify.Match, error) {
+	ctx, cancel := context.WithTimeout(ctx, identifyTimeout)
+	defer cancel()
+	if attachment == nil {
+		refChannelID, messageID, ok := parseMessageReference(reference, channelID)
+		if !ok {
+			return nil, errMessageNotFound
+		}
+		msg, err := s.ChannelMessage(refChannelID, messageID)
+		if err != nil {
+			logging.FromContext(ctx, handler.logger).Info("falló al obtener el mensaje", zap.String("messageID", messageID), zap.Error(err))
+			return nil, errMessageNotFound
+		}
+		if attachment = clipAttachment(msg.Attachments); attachment == nil {
+			return nil, errNoClip
+		}
+	}
+
+	path, err := downloadClip(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(path)
+	return handler.identifier.Identify(ctx, path)
+}
+
+// clipAttachment devuelve el primer adjunto de audio o video, o nil si no hay ninguno.
+func clipAttachment(attachments []*discordgo.MessageAttachment) *discordgo.MessageAttachment {
+	for _, attachment := range attachments {
+		if isClip(attachment) {
+			return attachment
+		}
+	}
+	return nil
+}
+
+func isClip(attachment *discordgo.MessageAttachment) bool {
+	return strings.HasPrefix(attachment.ContentType, "audio/") || strings.HasPrefix(attachment.ContentType, "video/")
+}
+
+// downloadClip descarga el adjunto en un archivo temporal y devuelve su ruta. Quien la llama tiene que borrarlo.
+func downloadClip(ctx context.Context, attachment *discordgo.MessageAttachment) (string, error) {
+	if !isClip(attachment) {
+		return "", errClipNotAudio
+	}
+	if attachment.Size > identifyMaxClipSize {
+		return "", errClipTooLarge
+	}
+	body, err := openAttachment(ctx, attachment)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	file, err := os.CreateTemp("", "identify-*"+filepath.Ext(attachment.Filename))
+	if err != nil {
+		return "", fmt.Errorf("error al crear el archivo temporal: %w", err)
+	}
+	defer file.Close()
+	// Se lee un byte más que el máximo para detectar los adjuntos que informaron mal su tamaño.
+	n, err := io.Copy(file, io.LimitReader(body, identifyMaxClipSize+1))
+	if err == nil && n > identifyMaxClipSize {
+		err = errClipTooLarge
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// parseMessageReference devuelve el canal y el ID del mensaje de un enlace a un mensaje, o de un ID de un mensaje
+// de channelID.
+func parseMessageReference(reference, channelID string) (string, string, bool) {
+	if match := messageLink.FindStringSubmatch(reference); match != nil {
+		return match[1], match[2], true
+	}
+	if snowflake.MatchString(reference) {
+		return channelID, reference, true
+	}
+	return "", "", false
+}
+
+// GenerateIdentifiedSongEmbed genera el embed de una canción reconocida.
+func GenerateIdentifiedSongEmbed(match *identify.Match, member *discordgo.Member) *discordgo.MessageEmbed {
+	fields := []*discordgo.MessageEmbedField{{Name: identifyTitleField, Value: match.Title, Inline: true}}
+	if match.Artist != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: identifyArtistField, Value: match.Artist, Inline: true})
+	}
+	return &discordgo.MessageEmbed{
+		Title:  "🔎 Canción reconocida",
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Coincidencia: %.0f%% · Pedido por %s", match.Score*100, getMemberName(member))},
+	}
+}
+
+// identifiedSong lee la canción reconocida del embed que generó GenerateIdentifiedSongEmbed.
+func identifiedSong(msg *discordgo.Message) (identify.Match, bool) {
+	var match identify.Match
+	if msg == nil || len(msg.Embeds) == 0 {
+		return match, false
+	}
+	for _, field := range msg.Embeds[0].Fields {
+		switch field.Name {
+		case identifyTitleField:
+			match.Title = field.Value
+		case identifyArtistField:
+			match.Artist = field.Value
+		}
+	}
+	return match, match.Title != ""
+}
+
+func identifyErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, identify.ErrNoMatch):
+		return "🤷🏽 No reconocí la canción. Probá con un fragmento más largo o con menos ruido"
+	case errors.Is(err, errNoClip):
+		return "🤷🏽 Ese mensaje no tiene ningún audio"
+	case errors.Is(err, errClipNotAudio):
+		return "🤷🏽 El adjunto tiene que ser un audio o un video"
+	case errors.Is(err, errClipTooLarge):
+		return "🙅 El audio es demasiado grande, mandá un fragmento más corto"
+	case errors.Is(err, errMessageNotFound):
+		return "🤷🏽 No encontré ese mensaje. Pasame el enlace o el ID de un mensaje de este canal"
+	default:
+		return withErrorCode(ctx, "Ocurrió un error al reconocer la canción")
+	}
+}
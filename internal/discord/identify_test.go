@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/identify"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseMessageReference(t *testing.T) {
+	channelID, messageID, ok := parseMessageReference("https://discord.com/channels/1/22/333", "9")
+	assert.True(t, ok)
+	assert.Equal(t, "22", channelID)
+	assert.Equal(t, "333", messageID)
+
+	channelID, messageID, ok = parseMessageReference("333", "9")
+	assert.True(t, ok)
+	assert.Equal(t, "9", channelID)
+	assert.Equal(t, "333", messageID)
+
+	for _, reference := range []string{"", "hola", "https://example.com/channels/1/22/333"} {
+		_, _, ok := parseMessageReference(reference, "9")
+		assert.False(t, ok, reference)
+	}
+}
+
+func TestIdentifiedSong(t *testing.T) {
+	member := &discordgo.Member{User: &discordgo.User{Username: "tomas"}}
+	embed := GenerateIdentifiedSongEmbed(&identify.Match{Title: "Lamento boliviano", Artist: "Enanitos Verdes", Score: 0.87}, member)
+	assert.Equal(t, "Coincidencia: 87% · Pedido por tomas", embed.Footer.Text)
+
+	match, ok := identifiedSong(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.True(t, ok)
+	assert.Equal(t, "Enanitos Verdes - Lamento boliviano", match.Query())
+
+	_, ok = identifiedSong(&discordgo.Message{})
+	assert.False(t, ok)
+}
+
+func TestClipAttachment(t *testing.T) {
+	clip := &discordgo.MessageAttachment{Filename: "clip.ogg", ContentType: "audio/ogg"}
+	assert.Equal(t, clip, clipAttachment([]*discordgo.MessageAttachment{{Filename: "foto.png", ContentType: "image/png"}, clip}))
+	assert.Nil(t, clipAttachment([]*discordgo.MessageAttachment{{Filename: "foto.png", ContentType: "image/png"}}))
+}
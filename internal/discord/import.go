@@ -0,0 +1,393 @@
+package discord
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// importMaxFileSize es el tamaño máximo del archivo que acepta /queue import.
+	importMaxFileSize = 1 << 20
+	// importMaxEntries es la cantidad máxima de entradas que se buscan de un archivo.
+	importMaxEntries = 200
+	// importConcurrency es la cantidad de búsquedas simultáneas al importar un archivo.
+	importConcurrency = 4
+	// importMaxFailedShown es la cantidad de entradas no encontradas que se muestran en el resultado.
+	importMaxFailedShown = 5
+)
+
+var (
+	errImportFormat   = errors.New("formato de archivo no soportado")
+	errImportEmpty    = errors.New("el archivo no tiene canciones")
+	errImportTooLarge = errors.New("el archivo es demasiado grande")
+)
+
+// importProgress es el avance de la búsqueda de las entradas de un archivo importado.
+type importProgress struct {
+	Total    int
+	Resolved int
+	Failed   int
+}
+
+func (p importProgress) done() int {
+	return p.Resolved + p.Failed
+}
+
+// ImportQueue agrega a la cola las canciones de un archivo .txt, .csv, .json o .m3u con una URL o búsqueda por
+// canción, por ejemplo exportado con /queue export o de otro bot. Busca varias entradas a la vez e informa el avance
+// con mensajes de seguimiento.
+func (handler *InteractionHandler) ImportQueue(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ImportQueue")
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+		return
+	}
+	var attachment *discordgo.MessageAttachment
+	if resolved := ic.ApplicationCommandData().Resolved; resolved != nil {
+		attachment = resolved.Attachments[commandOptions(opt)["file"].Value.(string)]
+	}
+	if attachment == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Adjuntá un archivo .txt, .csv, .json o .m3u con las canciones"); err != nil {
+			logger.Error("falló al responder con el error de la importación", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
+	go func() {
+		message := handler.importFile(ctx, ic, player, vs.ChannelID, attachment)
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: message,
+		}); err != nil {
+			logger.Error("falló al enviar el resultado de la importación", zap.Error(err))
+		}
+	}()
+}
+
+// importFile descarga el archivo, busca sus entradas y agrega las canciones encontradas a la cola, en el orden del
+// archivo. Devuelve el mensaje con el resultado.
+func (handler *InteractionHandler) importFile(ctx context.Context, ic *discordgo.InteractionCreate, player *bot.GuildPlayer, voiceChannelID string, attachment *discordgo.MessageAttachment) string {
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", ic.GuildID), zap.String("archivo", attachment.Filename))
+	entries, err := readImportFile(ctx, attachment)
+	if err != nil {
+		logger.Info("falló al leer el archivo importado", zap.Error(err))
+		return importErrorMessage(ctx, err)
+	}
+	truncated := len(entries) > importMaxEntries
+	if truncated {
+		entries = entries[:importMaxEntries]
+	}
+
+	results := handler.resolveImportEntries(ctx, entries, func(p importProgress) {
+		if !reportQuarter(p.done(), p.Total) {
+			return
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: fmt.Sprintf("⏳ Buscando las canciones de **%s**: %d de %d (%d%%)", attachment.Filename, p.done(), p.Total, p.done()*100/p.Total),
+		}); err != nil {
+			logger.Error("falló al enviar el avance de la importación", zap.Error(err))
+		}
+	})
+
+	var songs []*voice.Song
+	var failed []string
+	for i, found := range results {
+		if len(found) == 0 {
+			failed = append(failed, entries[i])
+			continue
+		}
+		songs = append(songs, found...)
+	}
+	if len(songs) == 0 {
+		return fmt.Sprintf("🤷🏽 No encontré ninguna de las %d canciones de **%s**", len(entries), attachment.Filename)
+	}
+
+	setRequester(songs, ic.Member)
+	var rejected *bot.RejectedSongsError
+	added := len(songs)
+	if err := player.AddSong(ctx, &ic.ChannelID, &voiceChannelID, songs...); errors.As(err, &rejected) {
+		added = rejected.Added
+	} else if err != nil {
+		logger.Error("falló al agregar las canciones importadas", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al agregar las canciones a la cola")
+	}
+	handler.recordAddedSongs(ic.GuildID, ic.Member.User.ID, songs, rejected)
+	logger.Info("Archivo importado", zap.Int("agregadas", added), zap.Int("no_encontradas", len(failed)))
+	return importResultMessage(attachment.Filename, added, rejected, failed, truncated)
+}
+
+// resolveImportEntries busca las canciones de cada entrada, importConcurrency a la vez. Devuelve las canciones de cada
+// entrada en el mismo orden, vacías si no se encontraron, y llama a progress cada vez que termina una búsqueda.
+func (handler *InteractionHandler) resolveImportEntries(ctx context.Context, entries []string, progress func(importProgress)) [][]*voice.Song {
+	logger := logging.FromContext(ctx, handler.logger)
+	results := make([][]*voice.Song, len(entries))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	p := importProgress{Total: len(entries)}
+	for w := 0; w < importConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				songs, err := handler.lookupImportEntry(ctx, entries[i])
+				if err != nil {
+					logger.Info("falló al buscar una entrada del archivo importado", zap.String("input", entries[i]), zap.Error(err))
+				}
+				mu.Lock()
+				results[i] = songs
+				if len(songs) > 0 {
+					p.Resolved++
+				} else {
+					p.Failed++
+				}
+				progress(p)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// lookupImportEntry busca las canciones de una entrada, como /play.
+func (handler *InteractionHandler) lookupImportEntry(ctx context.Context, entry string) ([]*voice.Song, error) {
+	videoID, err := handler.songLookup.SearchYouTubeVideoID(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return handler.songLookup.LookupSongs(ctx, videoID)
+}
+
+// readImportFile descarga el archivo adjunto y devuelve sus entradas.
+func readImportFile(ctx context.Context, attachment *discordgo.MessageAttachment) ([]string, error) {
+	if attachment.Size > importMaxFileSize {
+		return nil, errImportTooLarge
+	}
+	body, err := openAttachment(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, importMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error al descargar el archivo: %w", err)
+	}
+	if len(data) > importMaxFileSize {
+		return nil, errImportTooLarge
+	}
+	return ParseImportEntries(attachment.Filename, data)
+}
+
+// ParseImportEntries devuelve las URLs o búsquedas de un archivo, según su extensión. Un .txt tiene una por línea;
+// se ignoran las líneas vacías y las que empiezan con #. De un .csv se usa la columna url, o la primera columna si
+// no tiene encabezado; si la url está vacía se usa la columna title, como en los archivos de /queue export. Un .json
+// es una lista de canciones con url y title, como las de /queue export, o de textos. De un .m3u se usan las URLs y,
+// para los archivos locales, el título de su línea #EXTINF.
+func ParseImportEntries(filename string, data []byte) ([]string, error) {
+	var entries []string
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt":
+		entries = parseTextEntries(data)
+	case ".csv":
+		entries, err = parseCSVEntries(data)
+	case ".json":
+		entries, err = parseJSONEntries(data)
+	case ".m3u", ".m3u8":
+		entries = parseM3UEntries(data)
+	default:
+		return nil, errImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errImportEmpty
+	}
+	return entries, nil
+}
+
+func parseTextEntries(data []byte) []string {
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries
+}
+
+func parseCSVEntries(data []byte) ([]string, error) {
+	// Algunas planillas guardan el CSV con BOM.
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errImportFormat, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	urlColumn, titleColumn := 0, -1
+	header := false
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "url":
+			urlColumn, header = i, true
+		case "title":
+			titleColumn, header = i, true
+		}
+	}
+	if header {
+		records = records[1:]
+	}
+
+	var entries []string
+	for _, record := range records {
+		entry := ""
+		if urlColumn < len(record) {
+			entry = strings.TrimSpace(record[urlColumn])
+		}
+		if entry == "" && titleColumn >= 0 && titleColumn < len(record) {
+			entry = strings.TrimSpace(record[titleColumn])
+		}
+		if entry != "" && !strings.HasPrefix(entry, "#") {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func parseJSONEntries(data []byte) ([]string, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: %v", errImportFormat, err)
+	}
+	var entries []string
+	for _, item := range items {
+		var song exportedSong
+		if err := json.Unmarshal(item, &song); err != nil {
+			var text string
+			if err := json.Unmarshal(item, &text); err != nil {
+				return nil, fmt.Errorf("%w: %v", errImportFormat, err)
+			}
+			song.URL = text
+		}
+		entry := strings.TrimSpace(song.URL)
+		if entry == "" {
+			entry = strings.TrimSpace(song.Title)
+		}
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func parseM3UEntries(data []byte) []string {
+	var entries []string
+	var title string
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if info, ok := strings.CutPrefix(line, "#EXTINF:"); ok {
+			if _, name, found := strings.Cut(info, ","); found {
+				title = strings.TrimSpace(name)
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Las rutas a archivos locales no se pueden buscar, pero sí el título con el que las anotó el reproductor.
+		if !strings.HasPrefix(line, "http://") && !strings.HasPrefix(line, "https://") && title != "" {
+			line = title
+		}
+		entries = append(entries, line)
+		title = ""
+	}
+	return entries
+}
+
+// importResultMessage describe el resultado de la importación.
+func importResultMessage(filename string, added int, rejected *bot.RejectedSongsError, failed []string, truncated bool) string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("📥 Se añadieron %d canciones de **%s** a la cola", added, filename))
+	if rejected != nil {
+		builder.WriteString(fmt.Sprintf(" (🚫 %d bloqueadas en este servidor)", len(rejected.Rejections)))
+	}
+	if truncated {
+		builder.WriteString(fmt.Sprintf("\nSolo se importan las primeras %d entradas", importMaxEntries))
+	}
+	if len(failed) > 0 {
+		builder.WriteString(fmt.Sprintf("\nNo encontré %d:", len(failed)))
+		for i, entry := range failed {
+			if i == importMaxFailedShown {
+				builder.WriteString(fmt.Sprintf("\n… y %d más", len(failed)-importMaxFailedShown))
+				break
+			}
+			builder.WriteString(fmt.Sprintf("\n- `%s`", entry))
+		}
+	}
+	return builder.String()
+}
+
+func importErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, errImportFormat):
+		return "🤷🏽 El archivo tiene que ser un .txt, .csv, .json o .m3u con las canciones"
+	case errors.Is(err, errImportEmpty):
+		return "🫙 El archivo no tiene canciones"
+	case errors.Is(err, errImportTooLarge):
+		return fmt.Sprintf("🙅 El archivo es demasiado grande, el máximo es %d KB", importMaxFileSize>>10)
+	default:
+		return withErrorCode(ctx, "Ocurrió un error al descargar el archivo")
+	}
+}
@@ -0,0 +1,88 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseImportEntries_Text(t *testing.T) {
+	data := []byte("https://youtube.com/watch?v=1\r\n\n# comentario\n  soda stereo - persiana americana  \n")
+
+	entries, err := ParseImportEntries("lista.TXT", data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://youtube.com/watch?v=1", "soda stereo - persiana americana"}, entries)
+}
+
+func TestParseImportEntries_ExportedCSV(t *testing.T) {
+	data, _, err := EncodeQueue(exportTestSongs(), ExportFormatCSV)
+	assert.NoError(t, err)
+	data = append(data, []byte("3,Sin URL,,,00:00\n")...)
+
+	entries, err := ParseImportEntries("cola.csv", append([]byte("\ufeff"), data...))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://youtube.com/watch?v=1", "https://youtube.com/watch?v=2", "Sin URL"}, entries)
+}
+
+func TestParseImportEntries_CSVWithoutHeader(t *testing.T) {
+	entries, err := ParseImportEntries("cola.csv", []byte("https://youtube.com/watch?v=1,tomas\nla bamba\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://youtube.com/watch?v=1", "la bamba"}, entries)
+}
+
+func TestParseImportEntries_ExportedJSON(t *testing.T) {
+	data, _, err := EncodeQueue(exportTestSongs(), ExportFormatJSON)
+	assert.NoError(t, err)
+
+	entries, err := ParseImportEntries("cola.json", data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://youtube.com/watch?v=1", "https://youtube.com/watch?v=2"}, entries)
+
+	entries, err = ParseImportEntries("cola.json", []byte(`["la bamba", {"title": "Sin URL"}, {"url": ""}]`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"la bamba", "Sin URL"}, entries)
+}
+
+func TestParseImportEntries_M3U(t *testing.T) {
+	data, _, err := EncodeQueue(exportTestSongs(), ExportFormatM3U)
+	assert.NoError(t, err)
+	data = append(data, []byte("#EXTINF:200,Soda Stereo - Persiana americana\r\nC:\\Música\\persiana.mp3\r\nsin_titulo.mp3\n")...)
+
+	entries, err := ParseImportEntries("cola.m3u8", data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://youtube.com/watch?v=1", "https://youtube.com/watch?v=2", "Soda Stereo - Persiana americana", "sin_titulo.mp3"}, entries)
+}
+
+func TestParseImportEntries_Errors(t *testing.T) {
+	_, err := ParseImportEntries("cola.xml", []byte("<cola/>"))
+	assert.ErrorIs(t, err, errImportFormat)
+
+	_, err = ParseImportEntries("cola.txt", []byte("\n# nada\n"))
+	assert.ErrorIs(t, err, errImportEmpty)
+
+	_, err = ParseImportEntries("cola.csv", []byte("\"sin cerrar\n"))
+	assert.ErrorIs(t, err, errImportFormat)
+
+	_, err = ParseImportEntries("cola.json", []byte("{}"))
+	assert.ErrorIs(t, err, errImportFormat)
+
+	_, err = ParseImportEntries("cola.json", []byte("[]"))
+	assert.ErrorIs(t, err, errImportEmpty)
+}
+
+func TestImportResultMessage(t *testing.T) {
+	failed := []string{"a", "b", "c", "d", "e", "f", "g"}
+	rejected := &bot.RejectedSongsError{Rejections: []bot.Rejection{{}}, Added: 2}
+
+	message := importResultMessage("cola.txt", 2, rejected, failed, true)
+
+	assert.Equal(t, "📥 Se añadieron 2 canciones de **cola.txt** a la cola (🚫 1 bloqueadas en este servidor)\n"+
+		"Solo se importan las primeras 200 entradas\n"+
+		"No encontré 7:\n- `a`\n- `b`\n- `c`\n- `d`\n- `e`\n… y 2 más", message)
+}
@@ -4,21 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/alerting"
 	"github.com/Tomas-vilte/GoMusicBot/internal/cache"
+	"github.com/Tomas-vilte/GoMusicBot/internal/charts"
 	"github.com/Tomas-vilte/GoMusicBot/internal/config"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/file_storage"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/identify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/lavalink"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/internal/metrics"
 	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/radio"
+	"github.com/Tomas-vilte/GoMusicBot/internal/recommend"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
 	"github.com/Tomas-vilte/GoMusicBot/internal/services/providers"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotifysync"
 	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
+	"github.com/Tomas-vilte/GoMusicBot/internal/ytwatch"
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
-	"strings"
+	"io"
+	"sync"
 	"time"
 )
 
@@ -27,20 +42,77 @@ type GuildID string
 
 // InteractionHandler maneja las interacciones de Discord.
 type InteractionHandler struct {
-	ctx                 context.Context
-	discordToken        string
-	guildsPlayers       map[GuildID]*bot.GuildPlayer
-	songLookup          fetcher.SongLooker
-	storage             InteractionStorage
-	cfg                 *config.Config
-	logger              logging.Logger
-	responseHandler     ResponseHandler
-	session             SessionService
-	commandUsageCounter metrics.CustomMetric
-	realYoutubeClient   providers.YouTubeService
-	caching             cache.Manager
-	audioCaching        cache.AudioCaching
-	executorCommand     fetcher.CommandExecutor
+	ctx                  context.Context
+	discordToken         string
+	guildsPlayers        map[GuildID]*bot.GuildPlayer
+	playersMu            sync.RWMutex // playersMu protege guildsPlayers, ya que cada shard entrega sus eventos en paralelo.
+	songLookup           fetcher.SongLooker
+	storage              InteractionStorage
+	cfg                  *config.Config
+	logger               logging.Logger
+	responseHandler      ResponseHandler
+	session              SessionService
+	commandUsageCounter  metrics.CustomMetric
+	realYoutubeClient    providers.YouTubeService
+	caching              cache.Manager
+	audioCaching         cache.AudioCaching
+	executorCommand      fetcher.CommandExecutor
+	auditLog             AuditLog
+	audioMetrics         metrics.AudioMetrics
+	voiceSend            codec.SendConfig // voiceSend configura la temporización del envío de audio y el buffer adaptativo de frames.
+	voiceFailures        alerting.Tracker
+	fetcherMetrics       metrics.FetcherMetrics
+	processGauge         metrics.GaugeMetric
+	circuitBreaker       *fetcher.CircuitBreaker
+	processLimiter       *fetcher.ProcessLimiter
+	clientRotator        *fetcher.ClientRotator
+	addressRotator       *fetcher.AddressRotator
+	lavalink             *lavalink.Client                   // lavalink es opcional; si está configurado, los reproductores delegan el audio al nodo.
+	startedAt            time.Time                          // startedAt es el momento en que arrancó el bot, para calcular el uptime.
+	events               events.Publisher                   // events es opcional; recibe los eventos de reproducción de todos los reproductores.
+	deferResume          bool                               // deferResume indica que los reproductores no retoman la reproducción al iniciar, porque la retoma el traspaso entre instancias.
+	correlationIDs       sync.Map                           // correlationIDs contiene el ID de correlación de cada interacción en curso, por ID de interacción.
+	jobs                 jobs.Queue                         // jobs es opcional; si está configurada, las búsquedas y la transcodificación se procesan como trabajos.
+	prefetchAhead        int                                // prefetchAhead es la cantidad de canciones de la lista que se transcodifican por adelantado.
+	prefetching          sync.Map                           // prefetching contiene las URLs que se están transcodificando por adelantado.
+	transcodeJobs        jobs.Queue                         // transcodeJobs es opcional; si está configurada, la transcodificación por adelantado la hace la lambda.
+	transcodeKey         func(string) string                // transcodeKey devuelve la clave de S3 en la que la lambda deja el audio de una URL.
+	voiceGate            bot.VoiceGate                      // voiceGate es opcional; en modo cluster decide qué instancia se conecta a cada canal de voz.
+	assistants           []*assistant                       // assistants son los bots asistentes; sus reproductores también los protege playersMu.
+	savedPlaylists       *playlists.Service                 // savedPlaylists es opcional; habilita los comandos /playlist.
+	settings             settings.Store                     // settings es opcional; sin configuración por servidor se usan los valores por defecto.
+	pendingDuplicates    sync.Map                           // pendingDuplicates contiene las canciones repetidas que esperan confirmación, por canal y usuario.
+	triviaGames          sync.Map                           // triviaGames contiene la partida de trivia en curso de cada servidor.
+	playlistPrefetches   sync.Map                           // playlistPrefetches contiene los servidores que están preparando una lista guardada.
+	schedules            scheduled.Store                    // schedules es opcional; habilita los comandos /schedule.
+	scheduleLocation     *time.Location                     // scheduleLocation es la zona horaria de las reproducciones programadas.
+	history              history.Store                      // history es opcional; habilita /mystats y /history.
+	recommender          recommend.Provider                 // recommender es opcional; junto con history habilita /recommend.
+	aliasCommands        AliasCommands                      // aliasCommands es opcional; junto con settings habilita /alias.
+	identifier           identify.Identifier                // identifier es opcional; habilita /identify.
+	queueActions         sync.Map                           // queueActions contiene los últimos cambios de la lista de reproducción de cada servidor, para /undo.
+	presencePolicy       string                             // presencePolicy indica qué hacer cuando el canal de voz queda vacío: PresenceStop o PresencePause.
+	presenceGrace        time.Duration                      // presenceGrace es cuánto se espera a que alguien vuelva antes de detener una reproducción pausada.
+	autoPaused           sync.Map                           // autoPaused contiene las reproducciones pausadas por falta de presencia, por reproductor.
+	spam                 spamTracker                        // spam lleva los pedidos recientes de cada usuario para el anti-spam.
+	refresher            fetcher.SongRefresher              // refresher es opcional; revalida la metadata de las canciones antes de reproducirlas.
+	metadataTTL          time.Duration                      // metadataTTL es la antigüedad a partir de la cual se revalida la metadata de una canción.
+	parkedQueues         sync.Map                           // parkedQueues contiene las canciones de las colas con nombre inactivas, por servidor y nombre.
+	queuesMu             sync.Mutex                         // queuesMu serializa los cambios de las colas con nombre.
+	partyTimers          sync.Map                           // partyTimers contiene las escuchas grupales cuyo inicio está programado, por servidor y evento.
+	autoFills            sync.Map                           // autoFills contiene el relleno automático activo de cada servidor: la radio o el party shuffle.
+	allowedGuilds        map[string]struct{}                // allowedGuilds son los únicos servidores en los que se queda el bot; vacío permite todos.
+	recentErrors         sync.Map                           // recentErrors contiene los últimos errores del reproductor de cada servidor, para /debug.
+	spotifyPlaylists     SpotifyPlaylists                   // spotifyPlaylists es opcional; junto con spotifyLinks habilita /spotify.
+	spotifyLinks         spotifysync.Store                  // spotifyLinks son las listas de Spotify vinculadas a cada servidor.
+	spotifyAnnouncer     discordmessenger.ChatMessageSender // spotifyAnnouncer anuncia las canciones que se suman de las listas de Spotify.
+	youtubeFeeds         ytwatch.Feeds                      // youtubeFeeds es opcional; junto con youtubeSubscriptions habilita /youtube.
+	youtubeSubscriptions ytwatch.Store                      // youtubeSubscriptions son los canales de YouTube a los que está suscripto cada servidor.
+	youtubeAnnouncer     discordmessenger.ChatMessageSender // youtubeAnnouncer anuncia los videos nuevos de los canales suscriptos.
+	charts               charts.Provider                    // charts es opcional; habilita /trending.
+	chartsRegion         string                             // chartsRegion es la región del ranking si no se indica otra.
+	chartsLimit          int                                // chartsLimit es la cantidad de canciones del ranking que se muestran.
+	radioSource          radio.Source                       // radioSource es opcional; habilita /radio.
 }
 
 // NewInteractionHandler crea una nueva instancia de InteractionHandler.
@@ -68,6 +140,8 @@ func NewInteractionHandler(ctx context.Context, discordToken string, responseHan
 		audioCaching:        audioCaching,
 		realYoutubeClient:   youtubeClient,
 		executorCommand:     executorCommand,
+		startedAt:           time.Now(),
+		presencePolicy:      PresenceStop,
 	}
 	return handler
 }
@@ -78,23 +152,120 @@ func (handler *InteractionHandler) WithLogger(l logging.Logger) *InteractionHand
 	return handler
 }
 
+// WithAuditLog establece la auditoría usada por el /audit y por los reproductores de cada servidor.
+func (handler *InteractionHandler) WithAuditLog(a AuditLog) *InteractionHandler {
+	handler.auditLog = a
+	return handler
+}
+
+// WithAudioMetrics configura las métricas de temporización de frames de audio de los reproductores.
+func (handler *InteractionHandler) WithAudioMetrics(m metrics.AudioMetrics) *InteractionHandler {
+	handler.audioMetrics = m
+	return handler
+}
+
+// WithVoiceSendConfig configura el envío de audio de los reproductores que no usan Lavalink.
+func (handler *InteractionHandler) WithVoiceSendConfig(cfg codec.SendConfig) *InteractionHandler {
+	handler.voiceSend = cfg
+	return handler
+}
+
+// WithVoiceFailureTracker configura el registro de fallas de conexión a voz usado para alertar a los operadores.
+func (handler *InteractionHandler) WithVoiceFailureTracker(t alerting.Tracker) *InteractionHandler {
+	handler.voiceFailures = t
+	return handler
+}
+
+// WithFetcherMetrics configura las métricas del fetcher usado por los reproductores para descargar el audio.
+func (handler *InteractionHandler) WithFetcherMetrics(m metrics.FetcherMetrics) *InteractionHandler {
+	handler.fetcherMetrics = m
+	return handler
+}
+
+// WithProcessGauge configura la métrica de procesos externos lanzados por los reproductores.
+func (handler *InteractionHandler) WithProcessGauge(g metrics.GaugeMetric) *InteractionHandler {
+	handler.processGauge = g
+	return handler
+}
+
+// WithCircuitBreaker configura el circuit breaker compartido por los fetchers de los reproductores.
+func (handler *InteractionHandler) WithCircuitBreaker(cb *fetcher.CircuitBreaker) *InteractionHandler {
+	handler.circuitBreaker = cb
+	return handler
+}
+
+// WithProcessLimiter configura el límite de descargas simultáneas compartido por los fetchers de los reproductores.
+func (handler *InteractionHandler) WithProcessLimiter(l *fetcher.ProcessLimiter) *InteractionHandler {
+	handler.processLimiter = l
+	return handler
+}
+
+// WithClientRotation configura la rotación de clientes de YouTube compartida por los fetchers de los reproductores.
+func (handler *InteractionHandler) WithClientRotation(r *fetcher.ClientRotator) *InteractionHandler {
+	handler.clientRotator = r
+	return handler
+}
+
+// WithAddressRotation configura el bloque IPv6 desde el que salen las descargas de los reproductores.
+func (handler *InteractionHandler) WithAddressRotation(r *fetcher.AddressRotator) *InteractionHandler {
+	handler.addressRotator = r
+	return handler
+}
+
+// WithLavalink configura los reproductores para que deleguen la reproducción a un nodo de Lavalink.
+func (handler *InteractionHandler) WithLavalink(c *lavalink.Client) *InteractionHandler {
+	handler.lavalink = c
+	return handler
+}
+
+// WithEventPublisher establece el publicador de los eventos de reproducción de los reproductores.
+func (handler *InteractionHandler) WithEventPublisher(pub events.Publisher) *InteractionHandler {
+	handler.events = pub
+	return handler
+}
+
+// WithDeferredResume hace que los reproductores no retomen la reproducción guardada al iniciar; en modo cluster
+// la retoma la instancia que recibe el traspaso del servidor.
+func (handler *InteractionHandler) WithDeferredResume() *InteractionHandler {
+	handler.deferResume = true
+	return handler
+}
+
+// WithVoiceGate hace que los reproductores solo se conecten al canal de voz si esta instancia tiene el servidor.
+func (handler *InteractionHandler) WithVoiceGate(g bot.VoiceGate) *InteractionHandler {
+	handler.voiceGate = g
+	return handler
+}
+
 // Ready se llama cuando el bot está listo para recibir interacciones.
 func (handler *InteractionHandler) Ready(s *discordgo.Session, event *discordgo.Ready) {
-	if err := s.UpdateGameStatus(0, fmt.Sprintf("con tu vieja /%s", handler.cfg.CommandPrefix)); err != nil {
+	if err := s.UpdateGameStatus(0, "con tu vieja "+handler.commandName("play")); err != nil {
 		handler.logger.Error("falló al actualizar el estado del juego", zap.Error(err))
 	}
 }
 
+// commandName devuelve cómo se escribe el subcomando con el prefijo o el namespace configurado, como "/seso play".
+func (handler *InteractionHandler) commandName(subcommand string) string {
+	return CommandName(handler.cfg.CommandPrefix, handler.cfg.CommandNamespace, subcommand)
+}
+
 // GuildCreate se llama cuando el bot se une a un nuevo servidor.
 func (handler *InteractionHandler) GuildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
 	if event.Guild.Unavailable {
 		return
 	}
+	if !handler.guildAllowed(event.Guild.ID) {
+		handler.leaveGuild(s, event.Guild)
+		return
+	}
 
 	player := handler.setupGuildPlayer(GuildID(event.Guild.ID), s)
+	handler.playersMu.Lock()
 	handler.guildsPlayers[GuildID(event.Guild.ID)] = player
-	handler.logger.Info("conectado al servidor", zap.String("guildID", event.Guild.ID))
+	handler.playersMu.Unlock()
+	handler.logger.Info("conectado al servidor", zap.String("guildID", event.Guild.ID), zap.Int("shardID", s.ShardID))
 	player.StartListeningEvents(s)
+	handler.armParties(s, event.Guild.ID)
 	go func() {
 		if err := player.Run(handler.ctx); err != nil {
 			handler.logger.Error("ocurrió un error al ejecutar el reproductor", zap.Error(err))
@@ -110,22 +281,24 @@ func (handler *InteractionHandler) GuildDelete(s *discordgo.Session, event *disc
 	if err := player.Close(); err != nil {
 		handler.logger.Error("Hubo un error al cerrar el reproductor", zap.Error(err))
 	}
+	handler.playersMu.Lock()
 	delete(handler.guildsPlayers, guildID)
+	handler.playersMu.Unlock()
 }
 
 // PlaySong maneja el comando de reproducción de una canción.
 func (handler *InteractionHandler) PlaySong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
-	handler.logger.With(zap.String("guildID", ic.GuildID))
+	ctx, logger := handler.requestContext(ic)
+	logger.With(zap.String("guildID", ic.GuildID))
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 	handler.commandUsageCounter.Inc("PlaySong")
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opt.Options))
 	for _, opt := range opt.Options {
 		optionMap[opt.Name] = opt
@@ -138,113 +311,191 @@ func (handler *InteractionHandler) PlaySong(s *discordgo.Session, ic *discordgo.
 	vs := getUsersVoiceState(g, ic.Member.User)
 	if vs == nil {
 		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
-			handler.logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
 		}
 		return
 	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) || handler.respondSpam(ic, input) {
+		return
+	}
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
 	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{GenerateAddingSongEmbed(input, ic.Member)},
 		},
 	}); err != nil {
-		handler.logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
 	}
 
-	go func(ic *discordgo.InteractionCreate, vs *discordgo.VoiceState) {
-		videoID, err := handler.songLookup.SearchYouTubeVideoID(handler.ctx, input)
-		if err != nil {
-			handler.logger.Error("Error al buscar el ID del video en YouTube", zap.Error(err), zap.String("input", input))
-			if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-				Embeds: []*discordgo.MessageEmbed{GenerateFailedToAddSongEmbed(input, ic.Member)},
-			}); err != nil {
-				handler.logger.Error("falló al enviar el mensaje de seguimiento de error al buscar el ID del video", zap.Error(err))
-			}
+	// Con una cola de trabajos, la búsqueda la procesa un worker; si no se puede encolar, se procesa acá.
+	if handler.jobs != nil {
+		err := handler.publishLookup(ctx, ic.Interaction, vs.ChannelID, input)
+		if err == nil {
 			return
 		}
+		logger.Error("Error al encolar la búsqueda; se procesa en el bot", zap.Error(err))
+	}
+	go handler.lookupAndAdd(ctx, player, ic.Interaction, vs.ChannelID, input)
+}
 
-		songs, err := handler.songLookup.LookupSongs(handler.ctx, videoID)
-		if err != nil {
-			handler.logger.Info("falló al buscar la metadata de la canción", zap.Error(err), zap.String("input", input))
-			if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-				Embeds: []*discordgo.MessageEmbed{GenerateFailedToAddSongEmbed(input, ic.Member)},
-			}); err != nil {
-				handler.logger.Error("falló al enviar el mensaje de seguimiento de error al reproducir la cancion", zap.Error(err))
-			}
-			return
+// searchAndAdd busca input y lo agrega a la cola del canal de voz de quien hizo la interacción, como /play, para los
+// comandos y botones que arman la búsqueda por su cuenta.
+func (handler *InteractionHandler) searchAndAdd(ctx context.Context, s *discordgo.Session, ic *discordgo.InteractionCreate, input string) {
+	handler.searchAndAddWith(ctx, s, ic, input, handler.lookupAndAdd)
+}
+
+// searchAndAddWith es como searchAndAdd, pero la búsqueda y el agregado los hace add.
+func (handler *InteractionHandler) searchAndAddWith(ctx context.Context, s *discordgo.Session, ic *discordgo.InteractionCreate, input string, add func(context.Context, *bot.GuildPlayer, *discordgo.Interaction, string, string)) {
+	logger := logging.FromContext(ctx, handler.logger)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
+		return
+	}
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) || handler.respondSpam(ic, input) {
+		return
+	}
+
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateAddingSongEmbed(input, ic.Member)},
+		},
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+	go add(ctx, player, ic.Interaction, vs.ChannelID, input)
+}
 
-		memberName := getMemberName(ic.Member)
-		for i := range songs {
-			songs[i].RequestedBy = &memberName
+// lookupAndAdd busca la canción y la agrega a la lista de reproducción, respondiendo con mensajes de seguimiento
+// a la interacción. Si la búsqueda devuelve una lista de reproducción, pregunta si agregarla completa.
+func (handler *InteractionHandler) lookupAndAdd(ctx context.Context, player *bot.GuildPlayer, interaction *discordgo.Interaction, voiceChannelID, input string) {
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", interaction.GuildID))
+	lookupStart := time.Now()
+	videoID, err := handler.songLookup.SearchYouTubeVideoID(ctx, input)
+	if err != nil {
+		logger.Error("Error al buscar el ID del video en YouTube", zap.Error(err), zap.String("input", input))
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds:     []*discordgo.MessageEmbed{failedLookupEmbed(ctx, err, input, interaction.Member)},
+			Components: failedSongComponents(),
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de error al buscar el ID del video", zap.Error(err))
 		}
+		return
+	}
 
-		if len(songs) == 0 {
-			if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-				Embeds: []*discordgo.MessageEmbed{GenerateFailedToAddSongEmbed(input, ic.Member)},
-			}); err != nil {
-				handler.logger.Error("falló al enviar el mensaje de seguimiento de error al agregar la canción", zap.Error(err))
-			}
-			return
+	songs, err := handler.songLookup.LookupSongs(ctx, videoID)
+	logging.WarnIfSlow(logger, "lookup", time.Since(lookupStart), handler.cfg.SlowOps.Lookup,
+		zap.String("guildID", interaction.GuildID), zap.String("input", input))
+	if err != nil {
+		logger.Info("falló al buscar la metadata de la canción", zap.Error(err), zap.String("input", input))
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds:     []*discordgo.MessageEmbed{failedLookupEmbed(ctx, err, input, interaction.Member)},
+			Components: failedSongComponents(),
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de error al reproducir la cancion", zap.Error(err))
+		}
+		return
+	}
+	handler.addLookedUp(ctx, player, interaction, voiceChannelID, input, songs)
+}
+
+// addLookedUp agrega a la lista de reproducción las canciones encontradas para input, respondiendo con mensajes de
+// seguimiento a la interacción. Si son varias, pregunta si agregar la lista completa.
+func (handler *InteractionHandler) addLookedUp(ctx context.Context, player *bot.GuildPlayer, interaction *discordgo.Interaction, voiceChannelID, input string, songs []*voice.Song) {
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", interaction.GuildID))
+	setRequester(songs, interaction.Member)
+
+	if len(songs) == 0 {
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds:     []*discordgo.MessageEmbed{withErrorCodeEmbed(ctx, GenerateFailedToAddSongEmbed(input, interaction.Member))},
+			Components: failedSongComponents(),
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de error al agregar la canción", zap.Error(err))
 		}
+		return
+	}
 
-		if len(songs) == 1 {
-			song := songs[0]
-			if err := player.AddSong(&ic.ChannelID, &vs.ChannelID, song); err != nil {
-				handler.logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", input))
-				if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-					Embeds: []*discordgo.MessageEmbed{GenerateFailedToAddSongEmbed(input, ic.Member)},
-				}); err != nil {
-					handler.logger.Error("falló al enviar el mensaje de seguimiento de error al agregar la canción", zap.Error(err))
-				}
-				return
+	if len(songs) == 1 {
+		song := songs[0]
+		if handler.handleDuplicate(ctx, player, interaction, voiceChannelID, song) {
+			return
+		}
+		if err := player.AddSong(ctx, &interaction.ChannelID, &voiceChannelID, song); err != nil {
+			logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", input))
+			params := discordgo.WebhookParams{
+				Embeds:     []*discordgo.MessageEmbed{withErrorCodeEmbed(ctx, GenerateFailedToAddSongEmbed(input, interaction.Member))},
+				Components: failedSongComponents(),
 			}
-			if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-				Embeds: []*discordgo.MessageEmbed{GenerateAddedSongEmbed(song, ic.Member)},
-			}); err != nil {
-				handler.logger.Error("falló al enviar el mensaje de seguimiento de canción agregada", zap.Error(err))
+			var rejected *bot.RejectedSongsError
+			if errors.As(err, &rejected) {
+				// Reintentar no sirve: los filtros la van a volver a rechazar.
+				params = discordgo.WebhookParams{Embeds: []*discordgo.MessageEmbed{GenerateRejectedSongsEmbed(rejected, interaction.Member)}}
+			}
+			if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, params); err != nil {
+				logger.Error("falló al enviar el mensaje de seguimiento de error al agregar la canción", zap.Error(err))
 			}
 			return
 		}
+		handler.recordAddedSongs(interaction.GuildID, interactionUser(interaction).ID, songs, nil)
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{handler.tagExplicit(interaction.GuildID, song, GenerateAddedSongEmbed(song, interaction.Member))},
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de canción agregada", zap.Error(err))
+		}
+		return
+	}
 
-		handler.storage.SaveSongList(ic.ChannelID, songs)
+	handler.storage.SaveSongList(interaction.ChannelID, songs)
 
-		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
-			Embeds: []*discordgo.MessageEmbed{GenerateAskAddPlaylistEmbed(songs, ic.Member)},
-			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{
-						discordgo.SelectMenu{
-							CustomID: "add_song_playlist",
-							Options: []discordgo.SelectMenuOption{
-								{Label: "Agregar canción", Value: "song", Emoji: &discordgo.ComponentEmoji{Name: "🎵"}},
-								{Label: "Agregar lista de reproducción completa", Value: "playlist", Emoji: &discordgo.ComponentEmoji{Name: "🎶"}},
-							},
+	if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{GenerateAskAddPlaylistEmbed(songs, interaction.Member)},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.SelectMenu{
+						CustomID: "add_song_playlist",
+						Options: []discordgo.SelectMenuOption{
+							{Label: "Agregar canción", Value: "song", Emoji: &discordgo.ComponentEmoji{Name: "🎵"}},
+							{Label: "Agregar lista de reproducción completa", Value: "playlist", Emoji: &discordgo.ComponentEmoji{Name: "🎶"}},
 						},
 					},
 				},
 			},
-		}); err != nil {
-			handler.logger.Error("falló al enviar el mensaje de seguimiento de selección de agregar canción o lista de reproducción", zap.Error(err))
-		}
-	}(ic, vs)
+		},
+	}); err != nil {
+		logger.Error("falló al enviar el mensaje de seguimiento de selección de agregar canción o lista de reproducción", zap.Error(err))
+	}
 }
 
 // AddSongOrPlaylist maneja la adición de una canción o lista de reproducción.
 func (handler *InteractionHandler) AddSongOrPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
 	values := ic.MessageComponentData().Values
 	if len(values) == 0 {
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
@@ -253,7 +504,7 @@ func (handler *InteractionHandler) AddSongOrPlaylist(s *discordgo.Session, ic *d
 	songs := handler.storage.GetSongList(ic.ChannelID)
 	if len(songs) == 0 {
 		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "La interacción ya fue seleccionada"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
@@ -271,29 +522,56 @@ func (handler *InteractionHandler) AddSongOrPlaylist(s *discordgo.Session, ic *d
 
 	if voiceChannelID == nil {
 		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
+	if handler.respondVoiceChannelDenied(ic, *voiceChannelID) {
+		return
+	}
+	player = handler.playerForVoiceChannel(player, g.ID, *voiceChannelID)
 
 	switch value {
 	case "playlist":
+		added, blocked := make([]*voice.Song, 0, len(songs)), 0
 		for _, song := range songs {
-			if err := player.AddSong(&ic.Message.ChannelID, voiceChannelID, song); err != nil {
-				handler.logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", song.URL))
+			if err := player.AddSong(ctx, &ic.Message.ChannelID, voiceChannelID, song); err != nil {
+				logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", song.URL))
+				var rejected *bot.RejectedSongsError
+				if errors.As(err, &rejected) {
+					blocked++
+				}
+				continue
 			}
+			added = append(added, song)
+		}
+		handler.recordAddedSongs(g.ID, ic.Member.User.ID, added, nil)
+		message := fmt.Sprintf("➕ Se añadieron %d canciones a la lista de reproducción", len(added))
+		if blocked > 0 {
+			message += fmt.Sprintf(" (🚫 %d bloqueadas en este servidor)", blocked)
 		}
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, fmt.Sprintf("➕ Se añadieron %d canciones a la lista de reproducción", len(songs))); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 	default:
 		song := songs[0]
-		if err := player.AddSong(&ic.Message.ChannelID, voiceChannelID, song); err != nil {
-			handler.logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", song.URL))
-			if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageFailedToAddSong); err != nil {
-				handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		if err := player.AddSong(ctx, &ic.Message.ChannelID, voiceChannelID, song); err != nil {
+			logger.Info("falló al agregar la canción", zap.Error(err), zap.String("input", song.URL))
+			var rejected *bot.RejectedSongsError
+			if errors.As(err, &rejected) {
+				if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{
+						Embeds: []*discordgo.MessageEmbed{GenerateRejectedSongsEmbed(rejected, ic.Member)},
+					},
+				}); err != nil {
+					logger.Error("falló al responder con la canción bloqueada", zap.Error(err))
+				}
+			} else if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, ErrorMessageFailedToAddSong)); err != nil {
+				logger.Error("falló al responder con el error del servidor", zap.Error(err))
 			}
 		} else {
+			handler.recordAddedSongs(g.ID, ic.Member.User.ID, []*voice.Song{song}, nil)
 			embed := &discordgo.MessageEmbed{
 				Author: &discordgo.MessageEmbedAuthor{
 					Name: "Añadido a la cola",
@@ -320,10 +598,10 @@ func (handler *InteractionHandler) AddSongOrPlaylist(s *discordgo.Session, ic *d
 			if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
 				Type: discordgo.InteractionResponseChannelMessageWithSource,
 				Data: &discordgo.InteractionResponseData{
-					Embeds: []*discordgo.MessageEmbed{embed},
+					Embeds: []*discordgo.MessageEmbed{handler.tagExplicit(g.ID, song, embed)},
 				},
 			}); err != nil {
-				handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+				logger.Error("falló al responder con el error del servidor", zap.Error(err))
 			}
 		}
 	}
@@ -332,112 +610,116 @@ func (handler *InteractionHandler) AddSongOrPlaylist(s *discordgo.Session, ic *d
 
 // StopPlaying detiene la reproducción de música.
 func (handler *InteractionHandler) StopPlaying(s *discordgo.Session, ic *discordgo.InteractionCreate, acido *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
 	handler.commandUsageCounter.Inc("StopPlaying")
+	cleared := clearedSongs(player)
 	if err := player.Stop(); err != nil {
-		handler.logger.Info("falló al detener la reproducción", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al detener la reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
+	handler.recordQueueAction(g.ID, queueAction{kind: queueActionClear, userID: ic.Member.User.ID, songs: cleared})
 	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "⏹️  Reproducción detenida"); err != nil {
-		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Error("falló al responder con el error del servidor", zap.Error(err))
 	}
 }
 
 // SkipSong salta la canción actualmente en reproducción.
 func (handler *InteractionHandler) SkipSong(s *discordgo.Session, ic *discordgo.InteractionCreate, acido *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
-	player.SkipSong()
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
 	handler.commandUsageCounter.Inc("SkipSong")
-	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "⏭️ Canción omitida"); err != nil {
-		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	message := "⏭️ Canción omitida"
+	// Con votación, los administradores y el rol de DJ siguen saltando la canción directamente.
+	guild := handler.guildSettings(g.ID)
+	switch {
+	case isDJ(ic.Member, guild):
+		player.SkipSong()
+	case guild.VoteSkip > 0:
+		message = handler.voteSkip(ctx, logger, s, ic, g, player, guild)
+	case guild.DJRole != "":
+		handler.respondDJOnly(ic, guild)
+		return
+	default:
+		player.SkipSong()
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el error del servidor", zap.Error(err))
 	}
 }
 
 // ListPlaylist lista las canciones en la lista de reproducción actual.
 func (handler *InteractionHandler) ListPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, acido *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
 	handler.commandUsageCounter.Inc("ListPlaylist")
-	playlist, err := player.GetPlaylist()
+	songs, err := player.GetSongs()
 	if err != nil {
-		handler.logger.Error("falló al obtener la lista de reproducción", zap.Error(err))
+		logger.Error("falló al obtener la lista de reproducción", zap.Error(err))
 		return
 	}
 
-	if len(playlist) == 0 {
+	if len(songs) == 0 {
 		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🫙 La lista de reproducción está vacía"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
-	} else {
-		builder := strings.Builder{}
-
-		for idx, song := range playlist {
-			line := fmt.Sprintf("%d. %s\n", idx+1, song)
-
-			if len(line)+builder.Len() > 4000 {
-				builder.WriteString("...")
-				break
-			}
-
-			builder.WriteString(fmt.Sprintf("%d. %s\n", idx+1, song))
-		}
-
-		message := strings.TrimSpace(builder.String())
+		return
+	}
 
-		if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Embeds: []*discordgo.MessageEmbed{
-					{Title: "Lista de reproducción:", Description: message},
-				},
-			},
-		}); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
-		}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{GenerateQueueEmbed(songs, 1)},
+			Components: queuePageComponents(songs, 1),
+		},
+	}); err != nil {
+		logger.Error("falló al responder con la lista de reproducción", zap.Error(err))
 	}
 }
 
 // RemoveSong elimina una canción de la lista de reproducción.
 func (handler *InteractionHandler) RemoveSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
 	handler.commandUsageCounter.Inc("RemoveSong")
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opt.Options))
 	for _, opt := range opt.Options {
@@ -450,71 +732,162 @@ func (handler *InteractionHandler) RemoveSong(s *discordgo.Session, ic *discordg
 	if err != nil {
 		if errors.Is(err, bot.ErrRemoveInvalidPosition) {
 			if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🤷🏽 Posición no válida"); err != nil {
-				handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+				logger.Error("falló al responder con el error del servidor", zap.Error(err))
 			}
 			return
 		}
 
-		handler.logger.Error("falló al eliminar la canción", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al eliminar la cancion"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Error("falló al eliminar la canción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al eliminar la cancion")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
+	handler.recordQueueAction(g.ID, queueAction{kind: queueActionRemove, userID: ic.Member.User.ID, songs: []*voice.Song{song}, position: int(position)})
 	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, fmt.Sprintf("🗑️ Canción **%v** eliminada de la lista de reproducción", song.GetHumanName())); err != nil {
-		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Error("falló al responder con el error del servidor", zap.Error(err))
 	}
 }
 
 // GetPlayingSong obtiene la canción que se está reproduciendo actualmente.
 func (handler *InteractionHandler) GetPlayingSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
 	g, err := s.State.Guild(ic.GuildID)
 	if err != nil {
-		handler.logger.Info("falló al obtener el servidor", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la información del servidor"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	player := handler.getGuildPlayer(GuildID(g.ID), s)
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
 	handler.commandUsageCounter.Inc("GetPlayingSong")
 	song, err := player.GetPlayedSong()
 	if err != nil {
-		handler.logger.Info("falló al obtener la canción en reproducción", zap.Error(err))
-		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "Ocurrió un error al obtener la canción en reproducción"); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		logger.Info("falló al obtener la canción en reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la canción en reproducción")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
 	if song == nil {
 		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🔇 No se está reproduciendo ninguna canción en este momento..."); err != nil {
-			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
 		}
 		return
 	}
 
-	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, fmt.Sprintf("🎶 %s", song.GetHumanName())); err != nil {
-		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	message := fmt.Sprintf("🎶 %s", song.GetHumanName())
+	if handler.taggedExplicit(g.ID, &song.Song) {
+		message = fmt.Sprintf("🎶 %s%s", explicitTag, song.GetHumanName())
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el error del servidor", zap.Error(err))
 	}
 }
 
+// failedLookupEmbed genera el embed de error de una búsqueda fallida. Si YouTube está caído (circuit breaker
+// abierto) se avisa eso en lugar del error genérico.
+func failedLookupEmbed(ctx context.Context, err error, input string, member *discordgo.Member) *discordgo.MessageEmbed {
+	if errors.Is(err, fetcher.ErrCircuitOpen) {
+		return GenerateUpstreamUnavailableEmbed(input, member)
+	}
+	return withErrorCodeEmbed(ctx, GenerateFailedToAddSongEmbed(input, member))
+}
+
 // setupGuildPlayer configura un reproductor para un servidor dado.
 func (handler *InteractionHandler) setupGuildPlayer(guildID GuildID, dg *discordgo.Session) *bot.GuildPlayer {
-	dca := codec.NewDCAStreamerImpl(handler.logger)
-	voiceChat := voice.NewChatSessionImpl(dg, string(guildID), dca, handler.logger)
+	return handler.newGuildPlayer(guildID, dg, string(guildID), handler.lavalink)
+}
+
+// guildProcessLimiter aplica a las etapas de ganancia de un servidor el límite de procesos de las descargas, con el
+// lugar por servidor que les corresponde.
+type guildProcessLimiter struct {
+	limiter *fetcher.ProcessLimiter
+	guildID string
+}
+
+func (l guildProcessLimiter) Acquire(ctx context.Context) (func(), error) {
+	return l.limiter.Acquire(fetcher.WithDownloadGuild(ctx, l.guildID))
+}
+
+// newGuildPlayer crea el reproductor de un servidor con la sesión de Discord indicada. storeKey identifica la
+// lista de reproducción en el store; lavalinkClient es opcional.
+func (handler *InteractionHandler) newGuildPlayer(guildID GuildID, dg *discordgo.Session, storeKey string, lavalinkClient *lavalink.Client) *bot.GuildPlayer {
+	logger := errorLogger{Logger: handler.logger, errors: handler.guildErrors(string(guildID))}
+	pause := codec.NewPause()
+	dca := codec.NewDCAStreamerImpl(logger).WithSendConfig(handler.voiceSend).WithPause(pause)
+	if handler.audioMetrics != nil {
+		dca.WithMetrics(handler.audioMetrics, string(guildID))
+	}
+	var voiceChat voice.VoiceChatSession = voice.NewChatSessionImpl(dg, string(guildID), dca, logger)
+	var gain *codec.Gain
+	if lavalinkClient == nil && handler.cfg.Voice.Gain {
+		gain = codec.NewGain(handler.cfg.Voice.Fade)
+		dca.WithGain(gain).WithProcessGauge(handler.processGauge)
+		if handler.processLimiter != nil {
+			dca.WithProcessLimiter(guildProcessLimiter{limiter: handler.processLimiter, guildID: string(guildID)})
+		}
+	}
+	if lavalinkClient != nil {
+		voiceChat = lavalink.NewSession(lavalinkClient, dg, string(guildID), logger).WithFade(handler.cfg.Lavalink.Fade)
+	}
 	messageSender := discordmessenger.NewMessageSenderImpl(dg, handler.logger)
-	fetcherGetDCA := fetcher.NewYoutubeFetcher(handler.logger, handler.caching, handler.realYoutubeClient, handler.audioCaching, handler.executorCommand)
+	fetcherGetDCA := handler.newAudioFetcher()
 	persistent := file_storage.NewJSONStatePersistent()
-	songStorage, stateStorage := config.GetPlaylistStore(handler.cfg, string(guildID), handler.logger, persistent)
-	player := bot.NewGuildPlayer(handler.ctx, voiceChat, songStorage, stateStorage, fetcherGetDCA.GetDCAData, messageSender, handler.logger).WithLogger(handler.logger)
+	songStorage, stateStorage := config.GetPlaylistStore(handler.cfg, storeKey, handler.logger, persistent)
+	getDCAData := func(ctx context.Context, song *voice.Song) (io.Reader, error) {
+		return fetcherGetDCA.GetDCAData(fetcher.WithDownloadGuild(ctx, string(guildID)), song)
+	}
+	getDCADataFrom := func(ctx context.Context, song *voice.Song, offset time.Duration) (io.Reader, error) {
+		return fetcherGetDCA.GetDCADataFrom(fetcher.WithDownloadGuild(ctx, string(guildID)), song, offset)
+	}
+	player := bot.NewGuildPlayer(handler.ctx, string(guildID), voiceChat, songStorage, stateStorage, getDCAData, messageSender, logger).WithLogger(logger).WithSeekableAudio(getDCADataFrom)
+	if handler.auditLog != nil {
+		player.WithAuditRecorder(handler.auditLog)
+	}
+	if handler.voiceFailures != nil {
+		player.WithVoiceFailureTracker(handler.voiceFailures)
+	}
+	player.WithSlowOpThresholds(handler.cfg.SlowOps).WithPause(pause)
+	if gain != nil {
+		player.WithGain(gain)
+	}
+	if handler.deferResume {
+		player.WithDeferredResume()
+	}
+	if handler.voiceGate != nil {
+		player.WithVoiceGate(handler.voiceGate)
+	}
+	if handler.settings != nil {
+		player.WithSongFilter(bot.SongFilterFunc(handler.rejectSong))
+		player.WithVoiceChannelFilter(handler.voiceChannelAllowed)
+		player.WithVolumeLimit(handler.limitVolume)
+	}
+	if handler.refresher != nil && lavalinkClient == nil {
+		player.WithMetadataRefresh(metadataRefresher{refresher: handler.refresher, looker: handler.songLookup}, handler.metadataTTL)
+	}
+	publisher := handler.events
+	if (handler.jobs != nil || handler.transcodeJobs != nil) && lavalinkClient == nil && handler.prefetchAhead > 0 {
+		// Con Lavalink el audio lo transcodifica el nodo, así que no hay nada que preparar por adelantado.
+		publisher = &prefetchPublisher{handler: handler, next: publisher}
+	}
+	if handler.history != nil || handler.radioSource != nil {
+		publisher = &autoFillPublisher{handler: handler, next: publisher}
+	}
+	if publisher != nil {
+		player.WithEventPublisher(publisher)
+	}
 	return player
 }
 
 // getGuildPlayer obtiene un reproductor para un servidor dado.
 func (handler *InteractionHandler) getGuildPlayer(guildID GuildID, dg *discordgo.Session) *bot.GuildPlayer {
+	handler.playersMu.Lock()
+	defer handler.playersMu.Unlock()
 	player, ok := handler.guildsPlayers[guildID]
 	if !ok {
 		player = handler.setupGuildPlayer(guildID, dg)
@@ -535,7 +908,14 @@ func getUsersVoiceState(guild *discordgo.Guild, user *discordgo.User) *discordgo
 	return nil
 }
 
-// CheckVoiceChannelsPresence verifica la presencia de usuarios en los canales de voz y desconecta al bot si no hay usuarios presentes.
+// guildPlayer es un reproductor con el servidor al que pertenece.
+type guildPlayer struct {
+	guildID GuildID
+	player  *bot.GuildPlayer
+}
+
+// CheckVoiceChannelsPresence verifica la presencia de usuarios en los canales de voz y, si no hay usuarios presentes,
+// detiene o pausa la reproducción según la política de presencia. Corre hasta que se cancela el contexto del handler.
 func (handler *InteractionHandler) CheckVoiceChannelsPresence() {
 	// Definir el intervalo de verificación
 	ticker := time.NewTicker(1 * time.Minute)
@@ -543,9 +923,22 @@ func (handler *InteractionHandler) CheckVoiceChannelsPresence() {
 
 	for {
 		select {
-		case <-ticker.C:
+		case now := <-ticker.C:
+			handler.expireAutoPauses(now)
 			// Iterar sobre los servidores y verificar la presencia en los canales de voz
+			handler.playersMu.RLock()
+			players := make([]guildPlayer, 0, len(handler.guildsPlayers))
 			for guildID, player := range handler.guildsPlayers {
+				players = append(players, guildPlayer{guildID: guildID, player: player})
+			}
+			for _, a := range handler.assistants {
+				for guildID, player := range a.players {
+					players = append(players, guildPlayer{guildID: guildID, player: player})
+				}
+			}
+			handler.playersMu.RUnlock()
+			for _, gp := range players {
+				guildID, player := gp.guildID, gp.player
 				// Obtener el canal de voz asociado con el servidor actual
 				voiceChannelInfo, ok := player.GetVoiceChannelInfo()[string(guildID)]
 				if !ok {
@@ -553,11 +946,8 @@ func (handler *InteractionHandler) CheckVoiceChannelsPresence() {
 				}
 
 				// Verificar si hay usuarios presentes solo en el canal de voz asociado al server
-				if len(voiceChannelInfo.Members) == 1 || voiceChannelInfo.BotID == voiceChannelInfo.Members[0].User.ID {
-					handler.logger.Info("Desconectando bot debido a la falta de presencia en el canal de voz", zap.String("guildID", string(guildID)))
-					if err := player.Stop(); err != nil {
-						handler.logger.Error("falló al detener la reproducción", zap.Error(err))
-					}
+				if len(voiceChannelInfo.Members) <= 1 || voiceChannelInfo.BotID == voiceChannelInfo.Members[0].User.ID {
+					handler.leaveEmptyChannel(player, voiceChannelInfo)
 				}
 			}
 		case <-handler.ctx.Done():
@@ -601,4 +991,17 @@ func (handler *InteractionHandler) RegisterEventHandlers(s *discordgo.Session) {
 
 	// Registrar el manejador de eventos GuildDelete
 	s.AddHandler(handler.GuildDelete)
+
+	// Registrar el manejador de mensajes, que recibe las respuestas de la trivia
+	s.AddHandler(handler.TriviaGuess)
+
+	// Registrar el manejador de estados de voz, que entra solo al canal de entrada automática
+	s.AddHandler(handler.AutoJoin)
+
+	// Registrar el manejador de estados de voz, que retoma la reproducción pausada cuando alguien vuelve al canal
+	s.AddHandler(handler.AutoResume)
+
+	// Registrar los manejadores de eventos programados, que empiezan y olvidan las escuchas grupales
+	s.AddHandler(handler.PartyEventUpdate)
+	s.AddHandler(handler.PartyEventDelete)
 }
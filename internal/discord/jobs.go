@@ -0,0 +1,237 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+// remoteTranscodeTimeout es el tiempo durante el que no se vuelve a pedir a la lambda la transcodificación de una
+// canción que ya se pidió y todavía no está en S3.
+const remoteTranscodeTimeout = 10 * time.Minute
+
+const (
+	// JobTypeLookup es el trabajo que busca la canción pedida con /play y la agrega a la lista de reproducción.
+	JobTypeLookup = "lookup"
+	// JobTypePrefetch es el trabajo que descarga y transcodifica una canción de la lista para guardarla en la caché
+	// de audio antes de que empiece a sonar.
+	JobTypePrefetch = "prefetch"
+)
+
+type (
+	// lookupJob es el payload de JobTypeLookup. Incluye el token de la interacción para responderla con
+	// mensajes de seguimiento, que vale por 15 minutos.
+	lookupJob struct {
+		AppID          string            `json:"app_id"`
+		Token          string            `json:"token"`
+		GuildID        string            `json:"guild_id"`
+		ChannelID      string            `json:"channel_id"`
+		VoiceChannelID string            `json:"voice_channel_id"`
+		Input          string            `json:"input"`
+		Member         *discordgo.Member `json:"member"`
+	}
+
+	// prefetchJob es el payload de JobTypePrefetch.
+	prefetchJob struct {
+		Song *voice.Song `json:"song"`
+		Key  string      `json:"key,omitempty"` // Key es la clave de S3 en la que la lambda deja el audio; solo si la transcodifica la lambda.
+	}
+)
+
+// WithJobQueue hace que las búsquedas de /play y la transcodificación de las próximas canciones se procesen
+// con la cola de trabajos, fuera del manejo de la interacción. prefetchAhead es la cantidad de canciones de
+// la lista que se transcodifican por adelantado; con 0 no se transcodifica ninguna.
+func (handler *InteractionHandler) WithJobQueue(queue jobs.Queue, prefetchAhead int) *InteractionHandler {
+	handler.jobs = queue
+	handler.prefetchAhead = prefetchAhead
+	return handler
+}
+
+// WithRemoteTranscoding hace que la transcodificación por adelantado de las próximas canciones la haga la lambda
+// download_extract_upload_audio en lugar del bot: los trabajos se publican en queue y la lambda deja el audio en
+// S3, en la clave que devuelve key, de donde lo lee la caché de audio. prefetchAhead es la cantidad de canciones
+// de la lista que se transcodifican por adelantado.
+func (handler *InteractionHandler) WithRemoteTranscoding(queue jobs.Queue, key func(url string) string, prefetchAhead int) *InteractionHandler {
+	handler.transcodeJobs = queue
+	handler.transcodeKey = key
+	handler.prefetchAhead = prefetchAhead
+	return handler
+}
+
+// RegisterJobHandlers registra los handlers de los trabajos que procesa el bot.
+func (handler *InteractionHandler) RegisterJobHandlers(dispatcher *jobs.Dispatcher) {
+	dispatcher.
+		Handle(JobTypeLookup, handler.handleLookupJob).
+		Handle(JobTypePrefetch, handler.handlePrefetchJob)
+}
+
+// publishLookup encola la búsqueda de la canción pedida en la interacción.
+func (handler *InteractionHandler) publishLookup(ctx context.Context, interaction *discordgo.Interaction, voiceChannelID, input string) error {
+	job, err := jobs.NewJob(ctx, JobTypeLookup, interaction.GuildID, lookupJob{
+		AppID:          interaction.AppID,
+		Token:          interaction.Token,
+		GuildID:        interaction.GuildID,
+		ChannelID:      interaction.ChannelID,
+		VoiceChannelID: voiceChannelID,
+		Input:          input,
+		Member:         interaction.Member,
+	})
+	if err != nil {
+		return err
+	}
+	return handler.jobs.Publish(ctx, job)
+}
+
+// handleLookupJob procesa la búsqueda encolada por /play. Los errores de la búsqueda se responden al usuario
+// en lugar de devolverse, para que el backend no reentregue el trabajo y responda dos veces.
+func (handler *InteractionHandler) handleLookupJob(ctx context.Context, job *jobs.Job) error {
+	var payload lookupJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	player, err := handler.playerFor(payload.GuildID)
+	if err != nil {
+		return err
+	}
+	interaction := &discordgo.Interaction{
+		AppID:     payload.AppID,
+		Token:     payload.Token,
+		GuildID:   payload.GuildID,
+		ChannelID: payload.ChannelID,
+		Member:    payload.Member,
+	}
+	player = handler.playerForVoiceChannel(player, payload.GuildID, payload.VoiceChannelID)
+	handler.lookupAndAdd(ctx, player, interaction, payload.VoiceChannelID, payload.Input)
+	return nil
+}
+
+// handlePrefetchJob descarga y transcodifica la canción para dejarla en la caché de audio.
+func (handler *InteractionHandler) handlePrefetchJob(ctx context.Context, job *jobs.Job) error {
+	var payload prefetchJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	if payload.Song == nil {
+		return fmt.Errorf("el trabajo %s no tiene canción", job.Type)
+	}
+	return handler.prefetchSong(ctx, job.GuildID, payload.Song)
+}
+
+// prefetchSong descarga y transcodifica la canción del servidor para dejarla en la caché de audio, salvo que ya esté
+// en la caché o que se esté transcodificando.
+func (handler *InteractionHandler) prefetchSong(ctx context.Context, guildID string, song *voice.Song) error {
+	if _, ok := handler.audioCaching.Get(song.URL); ok {
+		return nil
+	}
+	if _, loaded := handler.prefetching.LoadOrStore(song.URL, struct{}{}); loaded {
+		return nil
+	}
+	defer handler.prefetching.Delete(song.URL)
+
+	reader, err := handler.newAudioFetcher().GetDCAData(fetcher.WithDownloadGuild(ctx, guildID), song)
+	if err != nil {
+		return err
+	}
+	// El fetcher guarda el audio en la caché cuando termina de leerse.
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return fmt.Errorf("error al transcodificar %s: %w", song.URL, err)
+	}
+	return nil
+}
+
+// prefetchUpcoming encola la transcodificación de las próximas canciones del servidor. Solo se hace mientras
+// el reproductor está sonando: si no, la primera canción la descarga el propio reproductor al empezar.
+func (handler *InteractionHandler) prefetchUpcoming(guildID string) {
+	player, err := handler.playerFor(guildID)
+	if err != nil || !player.IsPlaying() {
+		return
+	}
+	songs, err := player.GetSongs()
+	if err != nil {
+		return
+	}
+	if len(songs) > handler.prefetchAhead {
+		songs = songs[:handler.prefetchAhead]
+	}
+
+	logger := logging.WithFields(handler.logger, zap.String("guildID", guildID))
+	for _, song := range songs {
+		if _, ok := handler.audioCaching.Get(song.URL); ok {
+			continue
+		}
+		if _, ok := handler.prefetching.Load(song.URL); ok {
+			continue
+		}
+		if handler.transcodeJobs != nil {
+			handler.publishRemoteTranscode(logger, guildID, song)
+			continue
+		}
+		job, err := jobs.NewJob(handler.ctx, JobTypePrefetch, guildID, prefetchJob{Song: song})
+		if err == nil {
+			err = handler.jobs.Publish(handler.ctx, job)
+		}
+		if err != nil {
+			logger.Error("Error al encolar la transcodificación de la canción", zap.String("URL", song.URL), zap.Error(err))
+		}
+	}
+}
+
+// publishRemoteTranscode pide a la lambda la transcodificación de la canción. La URL queda en prefetching hasta
+// remoteTranscodeTimeout para no pedirla de nuevo con cada evento mientras la lambda la procesa.
+func (handler *InteractionHandler) publishRemoteTranscode(logger logging.Logger, guildID string, song *voice.Song) {
+	if _, loaded := handler.prefetching.LoadOrStore(song.URL, struct{}{}); loaded {
+		return
+	}
+	job, err := jobs.NewJob(handler.ctx, JobTypePrefetch, guildID, prefetchJob{Song: song, Key: handler.transcodeKey(song.URL)})
+	if err == nil {
+		err = handler.transcodeJobs.Publish(handler.ctx, job)
+	}
+	if err != nil {
+		handler.prefetching.Delete(song.URL)
+		logger.Error("Error al pedir la transcodificación de la canción a la lambda", zap.String("URL", song.URL), zap.Error(err))
+		return
+	}
+	time.AfterFunc(remoteTranscodeTimeout, func() { handler.prefetching.Delete(song.URL) })
+}
+
+// newAudioFetcher crea el fetcher que descarga y transcodifica el audio de las canciones.
+func (handler *InteractionHandler) newAudioFetcher() *fetcher.YoutubeFetcher {
+	audioFetcher := fetcher.NewYoutubeFetcher(handler.logger, handler.caching, handler.realYoutubeClient, handler.audioCaching, handler.executorCommand).WithMetrics(handler.fetcherMetrics).WithProcessGauge(handler.processGauge)
+	if handler.circuitBreaker != nil {
+		audioFetcher.WithCircuitBreaker(handler.circuitBreaker)
+	}
+	if handler.processLimiter != nil {
+		audioFetcher.WithProcessLimiter(handler.processLimiter)
+	}
+	if handler.clientRotator != nil {
+		audioFetcher.WithClientRotation(handler.clientRotator)
+	}
+	if handler.addressRotator != nil {
+		audioFetcher.WithAddressRotation(handler.addressRotator)
+	}
+	return audioFetcher
+}
+
+// prefetchPublisher reenvía los eventos de un reproductor y, cuando empieza una canción o cambia la lista,
+// encola en otra goroutine la transcodificación de las próximas canciones.
+type prefetchPublisher struct {
+	handler *InteractionHandler
+	next    events.Publisher // next es opcional; es el publicador configurado con WithEventPublisher.
+}
+
+func (p *prefetchPublisher) Publish(event events.Event) {
+	if p.next != nil {
+		p.next.Publish(event)
+	}
+	if event.Type == events.TypeTrackStarted || event.Type == events.TypeQueueChanged {
+		go p.handler.prefetchUpcoming(event.GuildID)
+	}
+}
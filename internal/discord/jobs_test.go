@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPublishLookup(t *testing.T) {
+	ctx := logging.ContextWithCorrelationID(context.Background(), "ab12cd")
+	queue := jobs.NewInMemoryQueue(1)
+	handler := (&InteractionHandler{ctx: context.Background(), logger: new(MockLogger)}).WithJobQueue(queue, 2)
+	interaction := &discordgo.Interaction{
+		AppID:     "app",
+		Token:     "token",
+		GuildID:   "1",
+		ChannelID: "texto",
+		Member:    &discordgo.Member{Nick: "tomas"},
+	}
+
+	assert.NoError(t, handler.publishLookup(ctx, interaction, "voz", "cancion"))
+
+	received := make(chan *jobs.Job, 1)
+	consumeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = queue.Consume(consumeCtx, func(_ context.Context, job *jobs.Job) error {
+			received <- job
+			return nil
+		})
+	}()
+	job := <-received
+	assert.Equal(t, JobTypeLookup, job.Type)
+	assert.Equal(t, "ab12cd", job.CorrelationID)
+
+	var payload lookupJob
+	assert.NoError(t, job.Decode(&payload))
+	assert.Equal(t, lookupJob{
+		AppID:          "app",
+		Token:          "token",
+		GuildID:        "1",
+		ChannelID:      "texto",
+		VoiceChannelID: "voz",
+		Input:          "cancion",
+		Member:         &discordgo.Member{Nick: "tomas"},
+	}, payload)
+}
+
+func TestHandleLookupJob_PlayerNotFound(t *testing.T) {
+	handler := &InteractionHandler{ctx: context.Background(), logger: new(MockLogger), guildsPlayers: make(map[GuildID]*bot.GuildPlayer)}
+	job, err := jobs.NewJob(context.Background(), JobTypeLookup, "1", lookupJob{GuildID: "1", Input: "cancion"})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, handler.handleLookupJob(context.Background(), job), control.ErrPlayerNotFound)
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestPrefetchPublisher_ForwardsEvents(t *testing.T) {
+	next := &recordingPublisher{}
+	handler := &InteractionHandler{ctx: context.Background(), logger: new(MockLogger), guildsPlayers: make(map[GuildID]*bot.GuildPlayer)}
+	publisher := &prefetchPublisher{handler: handler, next: next}
+
+	event := events.Event{Type: events.TypeTrackStarted, GuildID: "1"}
+	publisher.Publish(event)
+
+	assert.Equal(t, []events.Event{event}, next.events)
+}
+
+type recordingQueue struct {
+	jobs []*jobs.Job
+}
+
+func (q *recordingQueue) Publish(_ context.Context, job *jobs.Job) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *recordingQueue) Consume(context.Context, jobs.HandlerFunc) error { return nil }
+
+func (q *recordingQueue) Close() error { return nil }
+
+func TestPublishRemoteTranscode(t *testing.T) {
+	queue := &recordingQueue{}
+	key := func(url string) string { return "audio/" + url + ".dca" }
+	handler := (&InteractionHandler{ctx: context.Background(), logger: new(MockLogger)}).WithRemoteTranscoding(queue, key, 2)
+	song := &voice.Song{Title: "cancion", URL: "abc"}
+
+	handler.publishRemoteTranscode(handler.logger, "1", song)
+	// Mientras la lambda la procesa, la canción no se vuelve a pedir.
+	handler.publishRemoteTranscode(handler.logger, "1", song)
+
+	assert.Len(t, queue.jobs, 1)
+	job := queue.jobs[0]
+	assert.Equal(t, JobTypePrefetch, job.Type)
+	assert.Equal(t, "1", job.GuildID)
+
+	var payload prefetchJob
+	assert.NoError(t, job.Decode(&payload))
+	assert.Equal(t, prefetchJob{Song: song, Key: "audio/abc.dca"}, payload)
+}
@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// JumpToSong salta a la canción en la posición de la opción position de la lista de reproducción. Las canciones
+// anteriores se descartan, salvo que la opción keep sea true.
+func (handler *InteractionHandler) JumpToSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("JumpToSong")
+
+	options := commandOptions(opt)
+	position := int(options["position"].IntValue())
+	keep := false
+	if option, ok := options["keep"]; ok {
+		keep = option.BoolValue()
+	}
+
+	player, message := handler.seekPlayer(s, g, ic.Member)
+	if player != nil {
+		song, err := player.JumpTo(ctx, position, keep)
+		switch {
+		case errors.Is(err, bot.ErrRemoveInvalidPosition):
+			message = "🤷🏽 Posición no válida"
+		case err != nil:
+			logger.Error("falló al saltar a la canción", zap.Int("posición", position), zap.Error(err))
+			message = withErrorCode(ctx, "Ocurrió un error al saltar a la canción")
+		case keep || position == 1:
+			message = fmt.Sprintf("⏭️ %s saltó a **%s**", getMemberName(ic.Member), song.GetHumanName())
+		default:
+			message = fmt.Sprintf("⏭️ %s saltó a **%s** y descartó las %d canciones anteriores", getMemberName(ic.Member), song.GetHumanName(), position-1)
+		}
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a /jump", zap.Error(err))
+	}
+}
@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// Loop cambia el modo de repetición del reproductor del servidor según la opción mode: off, track o queue.
+func (handler *InteractionHandler) Loop(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Loop")
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	mode := bot.LoopMode(commandOptions(opt)["mode"].StringValue())
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	message := loopMessage(mode)
+	if err := player.SetLoopMode(mode); err != nil {
+		logger.Error("falló al cambiar el modo de repetición", zap.String("modo", string(mode)), zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al cambiar el modo de repetición")
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el modo de repetición", zap.Error(err))
+	}
+}
+
+// loopMessage es la respuesta al elegir el modo de repetición.
+func loopMessage(mode bot.LoopMode) string {
+	switch mode {
+	case bot.LoopTrack:
+		return "🔂 Repetición de la canción activada: la canción actual vuelve a sonar hasta saltarla"
+	case bot.LoopQueue:
+		return "🔁 Repetición de la lista activada: cada canción que termina vuelve al final de la lista"
+	default:
+		return "➡️ Repetición desactivada"
+	}
+}
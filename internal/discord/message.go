@@ -2,14 +2,18 @@ package discord
 
 import (
 	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
 	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
 	"github.com/bwmarrin/discordgo"
+	"strings"
 )
 
 const (
 	ErrorMessageNotInVoiceChannel = "No estas en un canal de voz down. Tenes que unirte a uno para reproducir musica loco"
 	ErrorMessageFailedToAddSong   = "No se pudo agregar la cancion kkkk"
+	ErrorMessageAdminOnly         = "🚫 Este comando es solo para administradores del servidor"
+	ErrorMessageOwnerOnly         = "🚫 Este comando es solo para los dueños del bot"
 )
 
 func GenerateAddingSongEmbed(input string, member *discordgo.Member) *discordgo.MessageEmbed {
@@ -24,6 +28,10 @@ func GenerateFailedToFindSong(input string, member *discordgo.Member) *discordgo
 	return generateAddingSongEmbed(input, "😨 No se pudo encontrar ninguna canción reproducible.", member)
 }
 
+func GenerateUpstreamUnavailableEmbed(input string, member *discordgo.Member) *discordgo.MessageEmbed {
+	return generateAddingSongEmbed(input, "🔌 YouTube está teniendo problemas. Probá de nuevo en unos minutos.", member)
+}
+
 func GenerateAskAddPlaylistEmbed(songs []*voice.Song, requestor *discordgo.Member) *discordgo.MessageEmbed {
 	title := fmt.Sprintf("👀  La canción es parte de una lista de reproducción que contiene %d canciones. Que mierda hago?", len(songs))
 	return generateAddingSongEmbed(title, "", requestor)
@@ -47,6 +55,39 @@ func GenerateAddedSongEmbed(song *voice.Song, member *discordgo.Member) *discord
 	return embed
 }
 
+// GenerateDuplicateSongEmbed genera el aviso de que la canción ya está en la lista de reproducción.
+func GenerateDuplicateSongEmbed(song *voice.Song, member *discordgo.Member, message string) *discordgo.MessageEmbed {
+	embed := generateAddingSongEmbed(song.GetHumanName(), message, member)
+	embed.URL = song.URL
+	return embed
+}
+
+// maxRejectedSongsListed es la cantidad de canciones rechazadas que se detallan en el embed.
+const maxRejectedSongsListed = 10
+
+// GenerateRejectedSongsEmbed explica por qué no se agregaron las canciones que rechazaron los filtros del servidor.
+func GenerateRejectedSongsEmbed(rejected *bot.RejectedSongsError, member *discordgo.Member) *discordgo.MessageEmbed {
+	if len(rejected.Rejections) == 1 && rejected.Added == 0 {
+		song := rejected.Rejections[0].Song
+		embed := generateAddingSongEmbed(song.GetHumanName(), fmt.Sprintf("🚫 No se agregó: %s.", rejected.Rejections[0].Reason), member)
+		embed.URL = song.URL
+		return embed
+	}
+
+	builder := strings.Builder{}
+	for idx, rejection := range rejected.Rejections {
+		if idx == maxRejectedSongsListed {
+			builder.WriteString(fmt.Sprintf("... y %d más\n", len(rejected.Rejections)-idx))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("• %s: %s\n", rejection.Song.GetHumanName(), rejection.Reason))
+	}
+	if rejected.Added > 0 {
+		builder.WriteString(fmt.Sprintf("\n➕ Se agregaron las otras %d canciones.", rejected.Added))
+	}
+	return generateAddingSongEmbed(fmt.Sprintf("🚫 No se agregaron %d canciones", len(rejected.Rejections)), strings.TrimSpace(builder.String()), member)
+}
+
 func generateAddingSongEmbed(title, description string, requestor *discordgo.Member) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Title:       title,
@@ -1,6 +1,9 @@
 package discord
 
 import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/radio"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 )
@@ -17,6 +20,62 @@ func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) With(fields ...zap.Field) {
 	m.Called(fields)
 }
+
+type MockSongLooker struct {
+	mock.Mock
+}
+
+func (m *MockSongLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	ret := m.Called(input)
+	songs, _ := ret.Get(0).([]*voice.Song)
+	return songs, ret.Error(1)
+}
+
+func (m *MockSongLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	ret := m.Called(searchTerm)
+	return ret.String(0), ret.Error(1)
+}
+
+type MockSongRefresher struct {
+	mock.Mock
+}
+
+func (m *MockSongRefresher) RefreshSong(ctx context.Context, song *voice.Song) (*voice.Song, error) {
+	ret := m.Called(song)
+	refreshed, _ := ret.Get(0).(*voice.Song)
+	return refreshed, ret.Error(1)
+}
+
+type MockChatMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockChatMessageSender) SendMessage(channelID, message string) error {
+	return m.Called(channelID, message).Error(0)
+}
+
+func (m *MockChatMessageSender) SendPlayMessage(channelID string, message *voice.PlayMessage) (string, error) {
+	ret := m.Called(channelID, message)
+	return ret.String(0), ret.Error(1)
+}
+
+func (m *MockChatMessageSender) EditPlayMessage(channelID, messageID string, message *voice.PlayMessage) error {
+	return m.Called(channelID, messageID, message).Error(0)
+}
+
+type MockRadioSource struct {
+	mock.Mock
+}
+
+func (m *MockRadioSource) Tracks(ctx context.Context, genre radio.Genre, limit int, exclude map[string]bool) ([]*voice.Song, error) {
+	ret := m.Called(genre.ID, limit, exclude)
+	songs, _ := ret.Get(0).([]*voice.Song)
+	return songs, ret.Error(1)
+}
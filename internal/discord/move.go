@@ -0,0 +1,42 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// MoveSong mueve una canción de la posición de la opción from a la de la opción to en la lista de reproducción.
+func (handler *InteractionHandler) MoveSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	handler.commandUsageCounter.Inc("MoveSong")
+	options := commandOptions(opt)
+	from, to := int(options["from"].IntValue()), int(options["to"].IntValue())
+
+	var message string
+	song, err := player.MoveSong(from, to)
+	switch {
+	case errors.Is(err, bot.ErrRemoveInvalidPosition):
+		message = "🤷🏽 Posición no válida"
+	case err != nil:
+		logger.Error("falló al mover la canción", zap.Int("desde", from), zap.Int("hasta", to), zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al mover la canción")
+	default:
+		message = fmt.Sprintf("↕️ Canción **%s** movida a la posición %d", song.GetHumanName(), to)
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a /move", zap.Error(err))
+	}
+}
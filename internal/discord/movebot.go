@@ -0,0 +1,101 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"time"
+)
+
+// moveBotTimeout es cuánto se espera a que el bot salga del canal de voz al moverlo a otro.
+const moveBotTimeout = 10 * time.Second
+
+// MoveBot mueve al bot, con la canción que está sonando y la lista, a otro canal de voz del servidor: el de la opción
+// channel o, si no se indica, el de quien usa el comando. Lo pueden mover quienes están escuchando en su canal, los DJ
+// y los administradores.
+func (handler *InteractionHandler) MoveBot(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("MoveBot")
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	target, message := handler.moveBotTarget(s, g, ic.Member, player, commandOptions(opt))
+	if target == "" {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con el error de /movebot", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		moveCtx, cancel := context.WithTimeout(ctx, moveBotTimeout)
+		defer cancel()
+		content := fmt.Sprintf("🚚 Me mudé a <#%s>, sigo desde donde iba", target)
+		if err := player.MoveTo(moveCtx, target); err != nil {
+			logger.Error("falló al mover el bot de canal de voz", zap.String("canal", target), zap.Error(err))
+			content = withErrorCode(ctx, "Ocurrió un error al cambiar de canal de voz")
+		} else {
+			logger.Info("Bot movido a otro canal de voz", zap.String("canal", target))
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: content,
+		}); err != nil {
+			logger.Error("falló al enviar el resultado de /movebot", zap.Error(err))
+		}
+	}()
+}
+
+// moveBotTarget devuelve el canal de voz al que se mueve el bot, o "" y el mensaje para responder si no se puede
+// mover.
+func (handler *InteractionHandler) moveBotTarget(s *discordgo.Session, g *discordgo.Guild, member *discordgo.Member, player *bot.GuildPlayer, options map[string]*discordgo.ApplicationCommandInteractionDataOption) (string, string) {
+	if !player.IsPlaying() {
+		return "", "🤷🏽 No estoy sonando en ningún canal de voz"
+	}
+	current, err := player.GetVoiceChannel()
+	if err != nil {
+		return "", "🤷🏽 No encontré el canal de voz en el que estoy"
+	}
+
+	vs := getUsersVoiceState(g, member.User)
+	if !isDJ(member, handler.guildSettings(g.ID)) && (vs == nil || vs.ChannelID != current) {
+		return "", fmt.Sprintf("🙅 Solo quienes están escuchando en <#%s>, un DJ o un administrador pueden moverme", current)
+	}
+
+	var target string
+	if option, ok := options["channel"]; ok {
+		target = option.Value.(string)
+	} else if vs != nil {
+		target = vs.ChannelID
+	}
+	switch {
+	case target == "":
+		return "", "🤷🏽 Decime a qué canal de voz me muevo"
+	case target == current:
+		return "", fmt.Sprintf("🤷🏽 Ya estoy en <#%s>", target)
+	case !handler.voiceChannelAllowed(g.ID, target):
+		return "", voiceChannelDeniedMessage(target)
+	}
+
+	if s.State.User != nil {
+		permissions, err := s.State.UserChannelPermissions(s.State.User.ID, target)
+		if err != nil || permissions&discordgo.PermissionVoiceConnect == 0 {
+			return "", fmt.Sprintf("🔒 No tengo permiso para entrar a <#%s>", target)
+		}
+	}
+	return target, ""
+}
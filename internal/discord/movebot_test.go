@@ -0,0 +1,36 @@
+package discord
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMoveBotTarget_NotPlaying(t *testing.T) {
+	handler, player, _, _ := newPresenceTestHandler()
+	s := &discordgo.Session{State: discordgo.NewState()}
+	member := &discordgo.Member{User: &discordgo.User{ID: "u1"}}
+
+	target, message := handler.moveBotTarget(s, &discordgo.Guild{ID: "1"}, member, player, nil)
+
+	assert.Empty(t, target)
+	assert.Equal(t, "🤷🏽 No estoy sonando en ningún canal de voz", message)
+}
+
+func TestGuildPlayer_MoveTo(t *testing.T) {
+	_, player, songStorage, stateStorage := newPresenceTestHandler()
+	require.NoError(t, stateStorage.SetVoiceChannel("viejo"))
+
+	require.NoError(t, player.MoveTo(context.Background(), "nuevo"))
+
+	channel, _ := stateStorage.GetVoiceChannel()
+	assert.Equal(t, "nuevo", channel)
+	// La canción actual vuelve a la lista para retomarla desde donde iba.
+	songs, _ := songStorage.GetSongs()
+	require.Len(t, songs, 2)
+	assert.Equal(t, "La Bamba", songs[0].Title)
+	assert.Equal(t, 42*time.Second, songs[0].StartPosition)
+}
@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// myStatsTop es la cantidad de artistas y horarios que muestra /mystats.
+const myStatsTop = 5
+
+// WithHistory establece el historial de reproducción, que habilita /mystats y /history.
+func (handler *InteractionHandler) WithHistory(store history.Store) *InteractionHandler {
+	handler.history = store
+	return handler
+}
+
+// MyStats muestra al usuario sus estadísticas de escucha en el servidor, calculadas con el historial de reproducción.
+func (handler *InteractionHandler) MyStats(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("MyStats")
+	if handler.history == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "El historial de reproducción no está habilitado"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	user := interactionUser(ic.Interaction)
+	entries, err := handler.history.Query(history.Filter{GuildID: ic.GuildID, UserID: user.ID})
+	if err != nil {
+		logger.Error("falló al consultar el historial de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al consultar tu historial")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateUserStatsEmbed(getMemberName(ic.Member), history.Summarize(entries, myStatsTop), time.Now())},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las estadísticas del usuario", zap.Error(err))
+	}
+}
+
+// GenerateUserStatsEmbed genera un embed con las estadísticas de escucha de un usuario. Los horarios se muestran con
+// el formato de tiempo de Discord, para que cada uno los vea en su zona horaria.
+func GenerateUserStatsEmbed(name string, stats history.UserStats, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("📊 Estadísticas de %s", name)}
+	if stats.Requests == 0 {
+		embed.Description = "Todavía no pediste canciones en este servidor"
+		return embed
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Canciones pedidas", Value: fmt.Sprintf("%d", stats.Requests), Inline: true},
+		{Name: "Tiempo de escucha", Value: fmtListeningTime(stats.ListeningTime), Inline: true},
+	}
+	if len(stats.TopArtists) > 0 {
+		artists := make([]string, 0, len(stats.TopArtists))
+		for i, artist := range stats.TopArtists {
+			artists = append(artists, fmt.Sprintf("%d. %s (%d)", i+1, artist.Name, artist.Count))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Artistas más pedidos", Value: strings.Join(artists, "\n")})
+	}
+	hours := make([]string, 0, len(stats.FavoriteHours))
+	for _, hour := range stats.FavoriteHours {
+		at := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+		hours = append(hours, fmt.Sprintf("<t:%d:t>", at.Unix()))
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Horarios favoritos", Value: strings.Join(hours, ", ")})
+	return embed
+}
+
+// fmtListeningTime formatea el tiempo de escucha en horas y minutos, por ejemplo "12 h 5 min".
+func fmtListeningTime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d h %d min", int(d.Hours()), int((d % time.Hour).Minutes()))
+}
@@ -0,0 +1,32 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGenerateUserStatsEmbed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)
+	embed := GenerateUserStatsEmbed("tomas", history.UserStats{
+		Requests:      12,
+		ListeningTime: 2*time.Hour + 5*time.Minute,
+		TopArtists:    []history.Count{{Name: "Soda Stereo", Count: 7}, {Name: "Charly García", Count: 3}},
+		FavoriteHours: []int{22, 9},
+	}, now)
+
+	assert.Equal(t, "📊 Estadísticas de tomas", embed.Title)
+	assert.Len(t, embed.Fields, 4)
+	assert.Equal(t, "12", embed.Fields[0].Value)
+	assert.Equal(t, "2 h 5 min", embed.Fields[1].Value)
+	assert.Equal(t, "1. Soda Stereo (7)\n2. Charly García (3)", embed.Fields[2].Value)
+	assert.Equal(t, "<t:1714600800:t>, <t:1714554000:t>", embed.Fields[3].Value)
+}
+
+func TestGenerateUserStatsEmbed_NoRequests(t *testing.T) {
+	embed := GenerateUserStatsEmbed("tomas", history.UserStats{}, time.Now())
+
+	assert.Empty(t, embed.Fields)
+	assert.Equal(t, "Todavía no pediste canciones en este servidor", embed.Description)
+}
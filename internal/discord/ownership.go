@@ -0,0 +1,24 @@
+package discord
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildOwner decide si esta instancia del bot atiende un servidor cuando corren varias instancias a la vez.
+type GuildOwner interface {
+	Owns(ctx context.Context, guildID string) bool
+}
+
+// OwnershipMiddleware descarta las interacciones de los servidores que atiende otra instancia del bot,
+// para que cada interacción se responda una sola vez. Las interacciones fuera de un servidor se atienden siempre.
+func OwnershipMiddleware(ctx context.Context, owner GuildOwner) func(InteractionHandlerFunc) InteractionHandlerFunc {
+	return func(next InteractionHandlerFunc) InteractionHandlerFunc {
+		return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+			if ic.GuildID != "" && !owner.Owns(ctx, ic.GuildID) {
+				return
+			}
+			next(s, ic)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package discord
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeGuildOwner map[string]bool
+
+func (f fakeGuildOwner) Owns(_ context.Context, guildID string) bool {
+	return f[guildID]
+}
+
+func TestOwnershipMiddleware(t *testing.T) {
+	owner := fakeGuildOwner{"propio": true}
+	var handled []string
+	handler := OwnershipMiddleware(context.Background(), owner)(func(_ *discordgo.Session, ic *discordgo.InteractionCreate) {
+		handled = append(handled, ic.GuildID)
+	})
+
+	for _, guildID := range []string{"propio", "ajeno", ""} {
+		handler(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: guildID}})
+	}
+
+	assert.Equal(t, []string{"propio", ""}, handled)
+}
@@ -0,0 +1,204 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// partyRequester es el nombre con el que se agregan las canciones de las escuchas grupales.
+const partyRequester = "Escucha grupal"
+
+// PartySchedule crea un evento programado de Discord en un canal de voz para escuchar juntos una lista guardada.
+// Cuando el evento empieza, el bot entra al canal y reproduce la lista. Si nadie lo inicia antes, lo inicia el bot a
+// la hora programada. Solo disponible para administradores.
+func (handler *InteractionHandler) PartySchedule(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("PartySchedule")
+	if !handler.settingsAdmin(ic) || !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	playlist, err := handler.savedPlaylists.Get(ic.GuildID, strings.TrimSpace(options["playlist"].StringValue()))
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	channelID := options["channel"].Value.(string)
+	if handler.respondVoiceChannelDenied(ic, channelID) {
+		return
+	}
+	if len(handler.guildSettings(ic.GuildID).Parties) >= settings.MaxParties {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("🙅 El servidor ya tiene %d escuchas grupales programadas", settings.MaxParties)); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	start, err := scheduled.ParseStart(options["when"].StringValue(), time.Now().In(handler.GuildLocation(ic.GuildID)))
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 No entendí cuándo. Usá una hora como `21:30` o una fecha y hora futura como `2024-12-24 22:00`"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	name := fmt.Sprintf("Escucha grupal: %s", playlist.Name)
+	if option, ok := options["name"]; ok {
+		name = strings.TrimSpace(option.StringValue())
+	}
+
+	event, err := s.GuildScheduledEventCreate(ic.GuildID, &discordgo.GuildScheduledEventParams{
+		ChannelID:          channelID,
+		Name:               name,
+		Description:        fmt.Sprintf("🎶 Vamos a escuchar juntos la lista **%s**", playlist.Name),
+		ScheduledStartTime: &start,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeVoice,
+	})
+	party := settings.Party{
+		Playlist:       playlist.Name,
+		VoiceChannelID: channelID,
+		TextChannelID:  ic.ChannelID,
+		Start:          start,
+		CreatedBy:      interactionUser(ic.Interaction).ID,
+	}
+	if err == nil {
+		_, err = settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+			if g.Parties == nil {
+				g.Parties = make(map[string]settings.Party)
+			}
+			g.Parties[event.ID] = party
+		})
+	}
+
+	var message string
+	var restErr *discordgo.RESTError
+	switch {
+	case errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusForbidden:
+		message = "🔒 No tengo permiso para crear eventos en el servidor"
+	case err != nil:
+		logger.Error("falló al programar la escucha grupal", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al programar la escucha grupal")
+	default:
+		logger.Info("Escucha grupal programada", zap.String("eventID", event.ID), zap.String("playlist", playlist.Name), zap.Time("inicio", start))
+		handler.armParty(s, ic.GuildID, event.ID, party)
+		message = fmt.Sprintf("🎉 Escucha grupal de **%s** en <#%s> <t:%d:R>: https://discord.com/events/%s/%s", playlist.Name, channelID, start.Unix(), ic.GuildID, event.ID)
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la escucha grupal", zap.Error(err))
+	}
+}
+
+// armParty programa que el bot inicie el evento de la escucha grupal a su hora, si nadie lo inició antes. Si ya
+// estaba programado, no hace nada.
+func (handler *InteractionHandler) armParty(s *discordgo.Session, guildID, eventID string, party settings.Party) {
+	key := guildID + ":" + eventID
+	if _, loaded := handler.partyTimers.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+	time.AfterFunc(time.Until(party.Start), func() {
+		handler.partyTimers.Delete(key)
+		if _, ok := handler.guildSettings(guildID).Parties[eventID]; !ok {
+			return
+		}
+		logger := logging.WithFields(handler.logger, zap.String("guildID", guildID), zap.String("eventID", eventID))
+		if handler.voiceGate != nil && !handler.voiceGate.Owns(handler.ctx, guildID) {
+			return
+		}
+		if _, err := s.GuildScheduledEventEdit(guildID, eventID, &discordgo.GuildScheduledEventParams{
+			Status: discordgo.GuildScheduledEventStatusActive,
+		}); err != nil {
+			logger.Error("falló al iniciar el evento de la escucha grupal", zap.Error(err))
+		}
+	})
+}
+
+// armParties programa el inicio de las escuchas grupales del servidor, para que sigan en pie después de un reinicio.
+func (handler *InteractionHandler) armParties(s *discordgo.Session, guildID string) {
+	if handler.settings == nil {
+		return
+	}
+	for eventID, party := range handler.guildSettings(guildID).Parties {
+		handler.armParty(s, guildID, eventID, party)
+	}
+}
+
+// PartyEventUpdate se llama cuando cambia un evento programado del servidor. Si es una escucha grupal que empezó, el
+// bot entra al canal y reproduce la lista; si terminó o se canceló, se olvida.
+func (handler *InteractionHandler) PartyEventUpdate(s *discordgo.Session, event *discordgo.GuildScheduledEventUpdate) {
+	if handler.settings == nil || event.GuildScheduledEvent == nil {
+		return
+	}
+	guildID, eventID := event.GuildID, event.ID
+	party, ok := handler.guildSettings(guildID).Parties[eventID]
+	if !ok {
+		return
+	}
+	switch event.Status {
+	case discordgo.GuildScheduledEventStatusActive:
+		handler.startParty(guildID, eventID, party)
+	case discordgo.GuildScheduledEventStatusCompleted, discordgo.GuildScheduledEventStatusCanceled:
+	default:
+		return
+	}
+	handler.forgetParty(guildID, eventID)
+}
+
+// PartyEventDelete se llama cuando se borra un evento programado del servidor; si era una escucha grupal, se olvida.
+func (handler *InteractionHandler) PartyEventDelete(s *discordgo.Session, event *discordgo.GuildScheduledEventDelete) {
+	if handler.settings == nil || event.GuildScheduledEvent == nil {
+		return
+	}
+	if _, ok := handler.guildSettings(event.GuildID).Parties[event.ID]; ok {
+		handler.forgetParty(event.GuildID, event.ID)
+	}
+}
+
+// startParty agrega la lista guardada de la escucha grupal a la lista de reproducción de su canal de voz. En modo
+// cluster solo la agrega la instancia que tiene el servidor.
+func (handler *InteractionHandler) startParty(guildID, eventID string, party settings.Party) {
+	logger := logging.WithFields(handler.logger, zap.String("guildID", guildID), zap.String("eventID", eventID), zap.String("playlist", party.Playlist))
+	switch {
+	case handler.voiceGate != nil && !handler.voiceGate.Owns(handler.ctx, guildID):
+		return
+	case handler.savedPlaylists == nil:
+		logger.Error("no se puede empezar la escucha grupal: las listas guardadas no están habilitadas")
+		return
+	case !handler.voiceChannelAllowed(guildID, party.VoiceChannelID):
+		logger.Info("el canal de la escucha grupal ya no está permitido", zap.String("canal", party.VoiceChannelID))
+		return
+	}
+	main, err := handler.playerFor(guildID)
+	if err != nil {
+		logger.Error("falló al obtener el reproductor de la escucha grupal", zap.Error(err))
+		return
+	}
+	playlist, err := handler.savedPlaylists.Get(guildID, party.Playlist)
+	if err != nil {
+		logger.Error("falló al obtener la lista de la escucha grupal", zap.Error(err))
+		return
+	}
+	logger.Info("empieza la escucha grupal", zap.String("canal", party.VoiceChannelID))
+	player := handler.playerForVoiceChannel(main, guildID, party.VoiceChannelID)
+	var rejected *bot.RejectedSongsError
+	if err := player.AddSong(handler.ctx, &party.TextChannelID, &party.VoiceChannelID, playlist.VoiceSongs(partyRequester)...); errors.As(err, &rejected) {
+		logger.Info("canciones de la escucha grupal rechazadas", zap.Int("cantidad", len(rejected.Rejections)))
+	} else if err != nil {
+		logger.Error("falló al agregar la lista de la escucha grupal", zap.Error(err))
+	}
+}
+
+// forgetParty borra la escucha grupal de la configuración del servidor.
+func (handler *InteractionHandler) forgetParty(guildID, eventID string) {
+	if _, err := settings.Update(handler.settings, guildID, func(g *settings.Guild) { delete(g.Parties, eventID) }); err != nil {
+		handler.logger.Error("falló al borrar la escucha grupal", zap.String("guildID", guildID), zap.String("eventID", eventID), zap.Error(err))
+	}
+}
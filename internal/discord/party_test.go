@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newPartyTestHandler(t *testing.T) (*InteractionHandler, *bot.GuildPlayer) {
+	handler, player, _, _ := newPresenceTestHandler()
+	handler.settings = settings.NewInMemoryStore()
+	handler.savedPlaylists = playlists.NewService(playlists.NewInMemoryStore())
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	_, err := handler.savedPlaylists.Save("1", "ana", "previa", []playlists.Song{{Title: "Muchachos", URL: "https://youtu.be/1"}}, "")
+	require.NoError(t, err)
+	_, err = settings.Update(handler.settings, "1", func(g *settings.Guild) {
+		g.Parties = map[string]settings.Party{
+			"evento": {Playlist: "previa", VoiceChannelID: "voz", TextChannelID: "texto"},
+			"otro":   {Playlist: "previa", VoiceChannelID: "voz", TextChannelID: "texto"},
+		}
+	})
+	require.NoError(t, err)
+	return handler, player
+}
+
+func partyEventUpdate(id string, status discordgo.GuildScheduledEventStatus) *discordgo.GuildScheduledEventUpdate {
+	return &discordgo.GuildScheduledEventUpdate{GuildScheduledEvent: &discordgo.GuildScheduledEvent{ID: id, GuildID: "1", Status: status}}
+}
+
+func TestPartyEventUpdate_StartsPlaylist(t *testing.T) {
+	handler, player := newPartyTestHandler(t)
+
+	handler.PartyEventUpdate(nil, partyEventUpdate("evento", discordgo.GuildScheduledEventStatusScheduled))
+	assert.Contains(t, handler.guildSettings("1").Parties, "evento", "el evento todavía no empezó")
+
+	handler.PartyEventUpdate(nil, partyEventUpdate("evento", discordgo.GuildScheduledEventStatusActive))
+	songs, err := player.GetSongs()
+	require.NoError(t, err)
+	require.Len(t, songs, 2)
+	assert.Equal(t, "Muchachos", songs[1].Title)
+	require.NotNil(t, songs[1].RequestedBy)
+	assert.Equal(t, partyRequester, *songs[1].RequestedBy)
+	assert.NotContains(t, handler.guildSettings("1").Parties, "evento")
+	assert.Contains(t, handler.guildSettings("1").Parties, "otro")
+}
+
+func TestPartyEventUpdate_ForgetsFinishedEvents(t *testing.T) {
+	handler, player := newPartyTestHandler(t)
+
+	handler.PartyEventUpdate(nil, partyEventUpdate("evento", discordgo.GuildScheduledEventStatusCanceled))
+	handler.PartyEventDelete(nil, &discordgo.GuildScheduledEventDelete{GuildScheduledEvent: &discordgo.GuildScheduledEvent{ID: "otro", GuildID: "1"}})
+	assert.Empty(t, handler.guildSettings("1").Parties)
+	songs, _ := player.GetSongs()
+	assert.Len(t, songs, 1, "no se agregó la lista")
+
+	// Los eventos que no son escuchas grupales se ignoran.
+	handler.PartyEventUpdate(nil, partyEventUpdate("ajeno", discordgo.GuildScheduledEventStatusActive))
+	songs, _ = player.GetSongs()
+	assert.Len(t, songs, 1)
+}
+
+func TestPartyEventUpdate_DeniedChannel(t *testing.T) {
+	handler, player := newPartyTestHandler(t)
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.VoiceChannels.Denied = []string{"voz"} })
+	require.NoError(t, err)
+
+	handler.PartyEventUpdate(nil, partyEventUpdate("evento", discordgo.GuildScheduledEventStatusActive))
+	songs, _ := player.GetSongs()
+	assert.Len(t, songs, 1)
+	assert.NotContains(t, handler.guildSettings("1").Parties, "evento")
+}
@@ -0,0 +1,183 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"math/rand"
+	"time"
+)
+
+const (
+	// partyShuffleAhead es la cantidad de canciones pendientes que el party shuffle mantiene en la lista.
+	partyShuffleAhead = 3
+	// partyShuffleHistoryWindow es cuánto historial del servidor se usa para elegir las canciones.
+	partyShuffleHistoryWindow = 90 * 24 * time.Hour
+	// partyShuffleHistoryLimit es la cantidad máxima de canciones del historial que se analizan.
+	partyShuffleHistoryLimit = 1000
+	// partyShuffleRecent es la cantidad de canciones que sonaron últimas y no se vuelven a elegir, si hay otras.
+	partyShuffleRecent = 20
+	// partyShuffleRequester es el nombre con el que se agregan las canciones del party shuffle.
+	partyShuffleRequester = "Party shuffle"
+)
+
+// errPartyShuffleNoHistory indica que el servidor no tiene historial del que elegir canciones.
+var errPartyShuffleNoHistory = errors.New("no hay canciones en el historial del servidor")
+
+// partyShuffle es la fuente del relleno automático del party shuffle: elige canciones del historial del servidor.
+type partyShuffle struct {
+	handler *InteractionHandler
+}
+
+// isPartyShuffle indica si la fuente de un relleno automático es el party shuffle.
+func isPartyShuffle(source autoFillSource) bool {
+	_, ok := source.(*partyShuffle)
+	return ok
+}
+
+// PartyShuffle activa o desactiva el party shuffle del servidor: mientras está activo, el bot mantiene la lista de
+// reproducción con canciones del historial del servidor, elegidas al azar con más chances para las que más sonaron y
+// más usuarios pidieron. Se desactiva solo al detener la reproducción.
+func (handler *InteractionHandler) PartyShuffle(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("PartyShuffle")
+	if handler.history == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "El party shuffle no está habilitado"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	if !commandOptions(opt)["enabled"].BoolValue() {
+		message := "⏹️ Party shuffle desactivado: ya no agrego canciones por mi cuenta"
+		if !handler.stopAutoFill(g.ID, isPartyShuffle) {
+			message = "🤷🏽 El party shuffle no estaba activado"
+		}
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con el party shuffle", zap.Error(err))
+		}
+		return
+	}
+
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+		return
+	}
+	handler.getGuildPlayer(GuildID(g.ID), s)
+	handler.startAutoFill(g.ID, &autoFill{source: &partyShuffle{handler: handler}, ahead: partyShuffleAhead, voiceChannelID: vs.ChannelID, textChannelID: ic.ChannelID})
+
+	message := "🎉 Party shuffle activado: mientras esté prendido agrego canciones del historial del servidor, con más chances para las más escuchadas"
+	if err := handler.refillAutoFill(ctx, g.ID); err != nil {
+		handler.stopAutoFill(g.ID, isPartyShuffle)
+		if errors.Is(err, errPartyShuffleNoHistory) {
+			message = "🤷🏽 Todavía no escucharon suficiente música en este servidor para el party shuffle"
+		} else {
+			logger.Error("falló al llenar la lista con el party shuffle", zap.Error(err))
+			message = withErrorCode(ctx, "Ocurrió un error al activar el party shuffle")
+		}
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el party shuffle", zap.Error(err))
+	}
+}
+
+// Songs elige hasta count canciones del historial del servidor, al azar y con más chances para las más
+// escuchadas. Evita las que sonaron últimas mientras haya otras.
+func (p *partyShuffle) Songs(_ context.Context, guildID string, count int, exclude map[string]bool) ([]*voice.Song, error) {
+	handler := p.handler
+	entries, err := handler.history.Query(history.Filter{GuildID: guildID, Since: time.Now().Add(-partyShuffleHistoryWindow), Limit: partyShuffleHistoryLimit})
+	if err != nil {
+		return nil, err
+	}
+	candidates := handler.partyShuffleCandidates(guildID, history.Popularity(entries), exclude)
+	if len(candidates) == 0 {
+		return nil, errPartyShuffleNoHistory
+	}
+	// Las que sonaron últimas se evitan mientras haya otras.
+	recent := make(map[string]bool, len(exclude)+partyShuffleRecent)
+	for url := range exclude {
+		recent[url] = true
+	}
+	for i := 0; i < len(entries) && i < partyShuffleRecent; i++ {
+		recent[entries[i].URL] = true
+	}
+	if fresh := handler.partyShuffleCandidates(guildID, candidates, recent); len(fresh) > 0 {
+		candidates = fresh
+	}
+	return pickPartyShuffle(candidates, count, rand.Intn), nil
+}
+
+// partyShuffleCandidates devuelve las canciones que se pueden elegir: las que no están en exclude y el servidor
+// permite.
+func (handler *InteractionHandler) partyShuffleCandidates(guildID string, songs []history.Popular, exclude map[string]bool) []history.Popular {
+	candidates := make([]history.Popular, 0, len(songs))
+	for _, song := range songs {
+		if exclude[song.URL] {
+			continue
+		}
+		if handler.settings != nil && handler.rejectSong(guildID, partyShuffleSong(song)) != "" {
+			continue
+		}
+		candidates = append(candidates, song)
+	}
+	return candidates
+}
+
+// pickPartyShuffle elige hasta count canciones distintas al azar, con más chances para las que más sonaron y más
+// usuarios pidieron. intn devuelve un número al azar en [0, n).
+func pickPartyShuffle(candidates []history.Popular, count int, intn func(n int) int) []*voice.Song {
+	candidates = append([]history.Popular(nil), candidates...)
+	songs := make([]*voice.Song, 0, count)
+	for len(songs) < count && len(candidates) > 0 {
+		total := 0
+		for _, candidate := range candidates {
+			total += partyShuffleWeight(candidate)
+		}
+		pick := intn(total)
+		for i, candidate := range candidates {
+			if pick < partyShuffleWeight(candidate) {
+				songs = append(songs, partyShuffleSong(candidate))
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				break
+			}
+			pick -= partyShuffleWeight(candidate)
+		}
+	}
+	return songs
+}
+
+// partyShuffleWeight es la popularidad de la canción en el servidor: cuántas veces sonó más cuántos usuarios
+// distintos la pidieron.
+func partyShuffleWeight(song history.Popular) int {
+	return song.Plays + song.Listeners
+}
+
+// partyShuffleSong convierte la canción del historial en una canción para la lista de reproducción.
+func partyShuffleSong(song history.Popular) *voice.Song {
+	requester := partyShuffleRequester
+	return &voice.Song{
+		Title:       song.Title,
+		URL:         song.URL,
+		Playable:    true,
+		Duration:    time.Duration(song.DurationMs) * time.Millisecond,
+		RequestedBy: &requester,
+		Uploader:    song.Uploader,
+	}
+}
@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestPickPartyShuffle(t *testing.T) {
+	candidates := []history.Popular{
+		{Entry: history.Entry{Title: "Mil horas", URL: "mil"}, Plays: 20, Listeners: 5},
+		{Entry: history.Entry{Title: "Persiana americana", URL: "persiana"}, Plays: 1, Listeners: 1},
+		{Entry: history.Entry{Title: "Lamento boliviano", URL: "lamento"}, Plays: 1, Listeners: 1},
+	}
+
+	firsts := make(map[string]int)
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < 300; run++ {
+		songs := pickPartyShuffle(candidates, 2, rnd.Intn)
+		require.Len(t, songs, 2)
+		assert.NotEqual(t, songs[0].URL, songs[1].URL)
+		assert.Equal(t, partyShuffleRequester, *songs[0].RequestedBy)
+		firsts[songs[0].URL]++
+	}
+	// La más escuchada sale primera mucho más seguido.
+	assert.Greater(t, firsts["mil"], firsts["persiana"]+firsts["lamento"])
+
+	assert.Len(t, pickPartyShuffle(candidates, 5, rnd.Intn), 3)
+}
+
+func TestRefillPartyShuffle(t *testing.T) {
+	handler, player, songStorage, _ := newPresenceTestHandler()
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	store := history.NewInMemoryStore()
+	handler.WithHistory(store)
+	now := time.Now()
+	for i, url := range []string{"mil", "persiana", "mil", "lamento", "musica"} {
+		require.NoError(t, store.Append(history.Entry{PlayedAt: now.Add(-time.Duration(i) * time.Hour), GuildID: "1", UserID: "ana", Title: url, URL: url}))
+	}
+
+	// Sin party shuffle activo no agrega nada.
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	songs, _ := songStorage.GetSongs()
+	assert.Len(t, songs, 1)
+
+	handler.startAutoFill("1", &autoFill{source: &partyShuffle{handler: handler}, ahead: partyShuffleAhead, voiceChannelID: "voz", textChannelID: "texto"})
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	songs, _ = songStorage.GetSongs()
+	require.Len(t, songs, partyShuffleAhead)
+	assert.NotEqual(t, songs[1].URL, songs[2].URL)
+
+	// Con la lista llena no agrega más.
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	songs, _ = songStorage.GetSongs()
+	assert.Len(t, songs, partyShuffleAhead)
+
+	// Apagar la radio no lo desactiva.
+	assert.False(t, handler.stopAutoFill("1", isRadioStation))
+	assert.True(t, handler.stopAutoFill("1", isPartyShuffle))
+}
+
+func TestRefillPartyShuffle_NoHistory(t *testing.T) {
+	handler, player, _, _ := newPresenceTestHandler()
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	handler.WithHistory(history.NewInMemoryStore())
+	handler.startAutoFill("1", &autoFill{source: &partyShuffle{handler: handler}, ahead: partyShuffleAhead, voiceChannelID: "voz", textChannelID: "texto"})
+
+	assert.ErrorIs(t, handler.refillAutoFill(context.Background(), "1"), errPartyShuffleNoHistory)
+}
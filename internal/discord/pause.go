@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"time"
+)
+
+// pauseTimeout es cuánto se espera a que el backend de audio pause o retome la canción.
+const pauseTimeout = 5 * time.Second
+
+// PauseSong pausa la canción que está sonando sin salir del canal de voz.
+func (handler *InteractionHandler) PauseSong(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("PauseSong")
+	handler.setPaused(s, ic, true)
+}
+
+// ResumeSong retoma la canción pausada desde donde quedó.
+func (handler *InteractionHandler) ResumeSong(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("ResumeSong")
+	handler.setPaused(s, ic, false)
+}
+
+func (handler *InteractionHandler) setPaused(s *discordgo.Session, ic *discordgo.InteractionCreate, paused bool) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	pauseCtx, cancel := context.WithTimeout(ctx, pauseTimeout)
+	defer cancel()
+	var changed bool
+	if paused {
+		changed, err = player.Pause(pauseCtx)
+	} else {
+		changed, err = player.Unpause(pauseCtx)
+	}
+	message := handler.pauseMessage(ctx, paused, changed, err)
+	if err != nil && !errors.Is(err, bot.ErrNotPlaying) && !errors.Is(err, bot.ErrPauseNotSupported) {
+		logger.Error("falló al pausar o retomar la reproducción", zap.Bool("pausada", paused), zap.Error(err))
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a la pausa", zap.Error(err))
+	}
+}
+
+// pauseMessage es la respuesta a /pause o /resume según cómo terminó.
+func (handler *InteractionHandler) pauseMessage(ctx context.Context, paused, changed bool, err error) string {
+	switch {
+	case errors.Is(err, bot.ErrNotPlaying):
+		return "🤷🏽 No hay ninguna canción sonando"
+	case errors.Is(err, bot.ErrPauseNotSupported):
+		return "🚫 El backend de audio no permite pausar la reproducción"
+	case err != nil:
+		return withErrorCode(ctx, "Ocurrió un error al pausar o retomar la reproducción")
+	case paused && changed:
+		return fmt.Sprintf("⏸️ Reproducción pausada. Usá %s para seguir desde donde quedó", handler.commandName("resume"))
+	case paused:
+		return "⏸️ La reproducción ya está pausada"
+	case changed:
+		return "▶️ Reproducción retomada"
+	default:
+		return "▶️ La reproducción no está pausada"
+	}
+}
@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// spotifyActivity es el nombre de la actividad que muestra Discord cuando alguien escucha Spotify.
+const spotifyActivity = "Spotify"
+
+// PlayFrom agrega a la cola la canción que otro miembro está escuchando en Spotify, según su presencia en Discord.
+// Necesita el intent de presencias y que la sesión guarde las presencias en su estado.
+func (handler *InteractionHandler) PlayFrom(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("PlayFrom")
+	user := commandOptions(opt)["user"].UserValue(nil)
+
+	var query string
+	presence, err := s.State.Presence(ic.GuildID, user.ID)
+	if err == nil {
+		query = spotifyTrack(presence)
+	}
+	if query == "" {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("🤷🏽 <@%s> no está escuchando nada en Spotify, o no muestra su actividad", user.ID)); err != nil {
+			logger.Error("falló al responder con el error de /playfrom", zap.Error(err))
+		}
+		return
+	}
+	logger.Info("Canción tomada de la presencia de Spotify", zap.String("userID", user.ID), zap.String("input", query))
+	handler.searchAndAdd(ctx, s, ic, query)
+}
+
+// spotifyTrack devuelve la búsqueda de la canción que la presencia muestra sonando en Spotify, como "Artista -
+// Título", o "" si no está escuchando Spotify.
+func spotifyTrack(presence *discordgo.Presence) string {
+	for _, activity := range presence.Activities {
+		if activity == nil || activity.Type != discordgo.ActivityTypeListening || activity.Name != spotifyActivity || activity.Details == "" {
+			continue
+		}
+		// Spotify separa los artistas con punto y coma.
+		artists := strings.ReplaceAll(activity.State, "; ", ", ")
+		if artists == "" {
+			return activity.Details
+		}
+		return artists + " - " + activity.Details
+	}
+	return ""
+}
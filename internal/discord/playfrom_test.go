@@ -0,0 +1,24 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSpotifyTrack(t *testing.T) {
+	presence := &discordgo.Presence{Activities: []*discordgo.Activity{
+		{Name: "Custom Status", Type: discordgo.ActivityTypeCustom, State: "escuchando"},
+		{Name: "Spotify", Type: discordgo.ActivityTypeListening, Details: "Fuego", State: "Bomba Estéreo; Will Smith"},
+	}}
+	assert.Equal(t, "Bomba Estéreo, Will Smith - Fuego", spotifyTrack(presence))
+
+	assert.Equal(t, "Fuego", spotifyTrack(&discordgo.Presence{Activities: []*discordgo.Activity{
+		{Name: "Spotify", Type: discordgo.ActivityTypeListening, Details: "Fuego"},
+	}}))
+	assert.Empty(t, spotifyTrack(&discordgo.Presence{Activities: []*discordgo.Activity{
+		{Name: "Minecraft", Type: discordgo.ActivityTypeGame},
+		{Name: "Apple Music", Type: discordgo.ActivityTypeListening, Details: "Fuego"},
+	}}))
+	assert.Empty(t, spotifyTrack(&discordgo.Presence{}))
+}
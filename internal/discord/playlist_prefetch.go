@@ -0,0 +1,137 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// prefetchProgress es el avance de la preparación de una lista guardada.
+type prefetchProgress struct {
+	Total      int
+	Cached     int // Cached son las canciones que ya estaban en la caché de audio.
+	Downloaded int
+	Failed     int
+}
+
+// done devuelve la cantidad de canciones ya procesadas.
+func (p prefetchProgress) done() int {
+	return p.Cached + p.Downloaded + p.Failed
+}
+
+// PrefetchPlaylist descarga y transcodifica todas las canciones de una lista guardada para dejarlas en la caché de
+// audio antes de un evento. Informa el avance con mensajes de seguimiento, que Discord acepta durante 15 minutos
+// desde el comando; la preparación sigue aunque ya no se puedan enviar.
+func (handler *InteractionHandler) PrefetchPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("PrefetchPlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+	if handler.lavalink != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Con Lavalink el audio lo descarga el nodo, no hace falta prepararlo"); err != nil {
+			logger.Error("falló al responder con el error de la preparación", zap.Error(err))
+		}
+		return
+	}
+
+	playlist, err := handler.savedPlaylists.Get(ic.GuildID, commandOptions(opt)["name"].StringValue())
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	if _, running := handler.playlistPrefetches.LoadOrStore(ic.GuildID, struct{}{}); running {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "⏳ Ya se está preparando una lista en este servidor"); err != nil {
+			logger.Error("falló al responder con el error de la preparación", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		defer handler.playlistPrefetches.Delete(ic.GuildID)
+		message := handler.prefetchSongs(ctx, ic, playlist.Name, playlist.VoiceSongs(""))
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: message,
+		}); err != nil {
+			logger.Error("falló al enviar el resultado de la preparación", zap.Error(err))
+		}
+	}()
+}
+
+// prefetchSongs deja las canciones en la caché de audio de a una, para no competir con el reproductor por la CPU, y
+// envía un mensaje de seguimiento con el avance cada cuarto de la lista. Si la transcodificación la hace la lambda,
+// solo se le piden las canciones. Devuelve el mensaje con el resultado.
+func (handler *InteractionHandler) prefetchSongs(ctx context.Context, ic *discordgo.InteractionCreate, name string, songs []*voice.Song) string {
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", ic.GuildID), zap.String("playlist", name))
+	if handler.transcodeJobs != nil {
+		for _, song := range songs {
+			handler.publishRemoteTranscode(logger, ic.GuildID, song)
+		}
+		return fmt.Sprintf("📦 Pedí la transcodificación de las %d canciones de **%s**", len(songs), name)
+	}
+
+	progress := prefetchProgress{Total: len(songs)}
+	for _, song := range songs {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, ok := handler.audioCaching.Get(song.URL); ok {
+			progress.Cached++
+		} else if err := handler.prefetchSong(ctx, ic.GuildID, song); err != nil {
+			logger.Error("falló al preparar la canción", zap.String("URL", song.URL), zap.Error(err))
+			progress.Failed++
+		} else {
+			progress.Downloaded++
+		}
+		if reportPrefetchProgress(progress) {
+			if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+				Content: prefetchProgressMessage(name, progress),
+			}); err != nil {
+				logger.Error("falló al enviar el avance de la preparación", zap.Error(err))
+			}
+		}
+	}
+	return prefetchResultMessage(name, progress)
+}
+
+// reportPrefetchProgress indica si con la última canción procesada se completó otro cuarto de la lista. Al terminar
+// no se informa el avance, porque se envía el resultado.
+func reportPrefetchProgress(p prefetchProgress) bool {
+	return reportQuarter(p.done(), p.Total)
+}
+
+// reportQuarter indica si con done de total elementos procesados se completó otro cuarto. Al terminar devuelve false,
+// porque se informa el resultado.
+func reportQuarter(done, total int) bool {
+	return done < total && done*4/total > (done-1)*4/total
+}
+
+// prefetchProgressMessage describe el avance de la preparación de la lista.
+func prefetchProgressMessage(name string, p prefetchProgress) string {
+	return fmt.Sprintf("⏳ Preparando **%s**: %d de %d canciones (%d%%)", name, p.done(), p.Total, p.done()*100/p.Total)
+}
+
+// prefetchResultMessage describe el resultado de la preparación de la lista.
+func prefetchResultMessage(name string, p prefetchProgress) string {
+	if p.done() < p.Total {
+		return fmt.Sprintf("🛑 Se interrumpió la preparación de **%s** en %d de %d canciones", name, p.done(), p.Total)
+	}
+	details := []string{fmt.Sprintf("%d descargadas", p.Downloaded)}
+	if p.Cached > 0 {
+		details = append(details, fmt.Sprintf("%d ya estaban listas", p.Cached))
+	}
+	if p.Failed > 0 {
+		details = append(details, fmt.Sprintf("%d fallaron", p.Failed))
+	}
+	return fmt.Sprintf("✅ **%s** está lista para sonar: %s", name, strings.Join(details, ", "))
+}
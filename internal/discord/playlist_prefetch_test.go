@@ -0,0 +1,33 @@
+package discord
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReportPrefetchProgress(t *testing.T) {
+	var reported []int
+	progress := prefetchProgress{Total: 10}
+	for i := 0; i < progress.Total; i++ {
+		progress.Downloaded++
+		if reportPrefetchProgress(progress) {
+			reported = append(reported, progress.done())
+		}
+	}
+
+	assert.Equal(t, []int{3, 5, 8}, reported)
+}
+
+func TestPrefetchResultMessage(t *testing.T) {
+	t.Run("completa", func(t *testing.T) {
+		message := prefetchResultMessage("fiesta", prefetchProgress{Total: 6, Cached: 2, Downloaded: 3, Failed: 1})
+
+		assert.Equal(t, "✅ **fiesta** está lista para sonar: 3 descargadas, 2 ya estaban listas, 1 fallaron", message)
+	})
+
+	t.Run("interrumpida", func(t *testing.T) {
+		message := prefetchResultMessage("fiesta", prefetchProgress{Total: 6, Downloaded: 2})
+
+		assert.Equal(t, "🛑 Se interrumpió la preparación de **fiesta** en 2 de 6 canciones", message)
+	})
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// PresenceStop detiene la reproducción y limpia la lista cuando el canal de voz queda vacío.
+	PresenceStop = "stop"
+	// PresencePause pausa la reproducción cuando el canal de voz queda vacío y la retoma si alguien vuelve.
+	PresencePause = "pause"
+
+	// presenceYieldTimeout es cuánto se espera a que el bot salga del canal al pausar la reproducción.
+	presenceYieldTimeout = 10 * time.Second
+)
+
+// autoPause es una reproducción pausada porque el canal de voz quedó vacío.
+type autoPause struct {
+	guildID   string
+	channelID string
+	since     time.Time
+}
+
+// WithPresencePolicy configura qué hace el bot cuando se queda solo en el canal de voz: PresenceStop, el valor por
+// defecto, o PresencePause, que retoma la reproducción si alguien vuelve al canal antes de grace.
+func (handler *InteractionHandler) WithPresencePolicy(policy string, grace time.Duration) *InteractionHandler {
+	switch policy {
+	case PresenceStop, PresencePause:
+	default:
+		handler.logger.Warn("política de presencia desconocida, se usa stop", zap.String("policy", policy))
+		policy = PresenceStop
+	}
+	handler.presencePolicy = policy
+	handler.presenceGrace = grace
+	return handler
+}
+
+// leaveEmptyChannel aplica la política de presencia a un reproductor cuyo canal de voz quedó vacío.
+func (handler *InteractionHandler) leaveEmptyChannel(player *bot.GuildPlayer, info bot.VoiceChannelInfo) {
+	logger := logging.WithFields(handler.logger, zap.String("guildID", info.GuildID), zap.String("canal", info.VoiceChannelID))
+	if handler.presencePolicy != PresencePause {
+		logger.Info("Desconectando bot debido a la falta de presencia en el canal de voz")
+		if err := player.Stop(); err != nil {
+			logger.Error("falló al detener la reproducción", zap.Error(err))
+		}
+		return
+	}
+	if !player.IsPlaying() {
+		return
+	}
+
+	logger.Info("Pausando la reproducción debido a la falta de presencia en el canal de voz")
+	ctx, cancel := context.WithTimeout(handler.ctx, presenceYieldTimeout)
+	defer cancel()
+	if err := player.Yield(ctx); err != nil {
+		logger.Error("falló al pausar la reproducción", zap.Error(err))
+		return
+	}
+	handler.autoPaused.Store(player, autoPause{guildID: info.GuildID, channelID: info.VoiceChannelID, since: time.Now()})
+}
+
+// expireAutoPauses detiene las reproducciones pausadas hace más de presenceGrace, limpiando su lista.
+func (handler *InteractionHandler) expireAutoPauses(now time.Time) {
+	handler.autoPaused.Range(func(key, value any) bool {
+		player, pause := key.(*bot.GuildPlayer), value.(autoPause)
+		if now.Sub(pause.since) < handler.presenceGrace {
+			return true
+		}
+		handler.autoPaused.Delete(key)
+		if player.IsPlaying() {
+			// Alguien volvió a reproducir antes de que venciera la pausa.
+			return true
+		}
+		handler.logger.Info("Nadie volvió al canal de voz, se detiene la reproducción pausada", zap.String("guildID", pause.guildID))
+		if err := player.Stop(); err != nil {
+			handler.logger.Error("falló al detener la reproducción", zap.Error(err))
+		}
+		return true
+	})
+}
+
+// AutoResume retoma la reproducción pausada por la política de presencia cuando alguien vuelve al canal de voz.
+func (handler *InteractionHandler) AutoResume(s *discordgo.Session, vs *discordgo.VoiceStateUpdate) {
+	if s.State.User == nil || vs.UserID == s.State.User.ID || vs.ChannelID == "" || (vs.BeforeUpdate != nil && vs.BeforeUpdate.ChannelID == vs.ChannelID) {
+		return
+	}
+	if vs.Member != nil && vs.Member.User != nil && vs.Member.User.Bot {
+		return
+	}
+	handler.autoPaused.Range(func(key, value any) bool {
+		player, pause := key.(*bot.GuildPlayer), value.(autoPause)
+		if pause.guildID != vs.GuildID || pause.channelID != vs.ChannelID {
+			return true
+		}
+		handler.autoPaused.Delete(key)
+		handler.logger.Info("Alguien volvió al canal de voz, se retoma la reproducción", zap.String("guildID", pause.guildID))
+		if err := player.Resume(); err != nil {
+			handler.logger.Error("falló al retomar la reproducción", zap.Error(err))
+		}
+		return false
+	})
+}
@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/inmemory_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newPresenceTestHandler() (*InteractionHandler, *bot.GuildPlayer, *inmemory_storage.InmemorySongStorage, *inmemory_storage.InmemoryStateStorage) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	handler := (&InteractionHandler{ctx: context.Background(), logger: logger}).WithPresencePolicy(PresencePause, time.Minute)
+
+	songStorage := inmemory_storage.NewInmemorySongStorage(logger)
+	_ = songStorage.AppendSong(&voice.Song{Title: "Siguiente"})
+	stateStorage := inmemory_storage.NewInmemoryStateStorage(logger)
+	_ = stateStorage.SetCurrentSong(&voice.PlayedSong{Song: voice.Song{Title: "La Bamba"}, Position: 42 * time.Second})
+	player := bot.NewGuildPlayer(context.Background(), "1", nil, songStorage, stateStorage, nil, nil, logger)
+	return handler, player, songStorage, stateStorage
+}
+
+func TestExpireAutoPauses(t *testing.T) {
+	handler, player, songStorage, stateStorage := newPresenceTestHandler()
+	now := time.Now()
+	handler.autoPaused.Store(player, autoPause{guildID: "1", channelID: "voz", since: now})
+
+	handler.expireAutoPauses(now.Add(30 * time.Second))
+	songs, _ := songStorage.GetSongs()
+	assert.Len(t, songs, 1, "la pausa todavía no venció")
+
+	handler.expireAutoPauses(now.Add(time.Minute))
+	songs, _ = songStorage.GetSongs()
+	assert.Empty(t, songs)
+	current, _ := stateStorage.GetCurrentSong()
+	assert.Nil(t, current)
+	_, paused := handler.autoPaused.Load(player)
+	assert.False(t, paused)
+}
+
+func TestAutoResume(t *testing.T) {
+	handler, player, songStorage, stateStorage := newPresenceTestHandler()
+	handler.autoPaused.Store(player, autoPause{guildID: "1", channelID: "voz", since: time.Now()})
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot"}
+	join := func(userID, channelID string, isBot bool) *discordgo.VoiceStateUpdate {
+		return &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+			GuildID:   "1",
+			ChannelID: channelID,
+			UserID:    userID,
+			Member:    &discordgo.Member{User: &discordgo.User{ID: userID, Bot: isBot}},
+		}}
+	}
+
+	handler.AutoResume(s, join("otro-bot", "voz", true))
+	handler.AutoResume(s, join("usuario", "otro", false))
+	_, paused := handler.autoPaused.Load(player)
+	require.True(t, paused)
+
+	handler.AutoResume(s, join("usuario", "voz", false))
+	_, paused = handler.autoPaused.Load(player)
+	assert.False(t, paused)
+	songs, _ := songStorage.GetSongs()
+	require.Len(t, songs, 2)
+	assert.Equal(t, "La Bamba", songs[0].Title)
+	assert.Equal(t, 42*time.Second, songs[0].StartPosition)
+	current, _ := stateStorage.GetCurrentSong()
+	assert.Nil(t, current)
+}
@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// QueuePreviousPageCustomID y QueueNextPageCustomID son los botones de /list que pasan a la página anterior o a
+	// la siguiente de la lista de reproducción.
+	QueuePreviousPageCustomID = "queue_page_previous"
+	QueueNextPageCustomID     = "queue_page_next"
+	// queuePageSize es la cantidad de canciones que se muestran por página.
+	queuePageSize = 10
+)
+
+// queuePageFooter es el pie del embed de /list, del que los botones leen la página que se está mostrando.
+var queuePageFooter = regexp.MustCompile(`^Página (\d+) de \d+`)
+
+// GenerateQueueEmbed devuelve el embed con la página page (empezando por 1) de la lista de reproducción, con la
+// duración de cada canción y quién la pidió. Si la página no existe, muestra la más cercana.
+func GenerateQueueEmbed(songs []*voice.Song, page int) *discordgo.MessageEmbed {
+	page = min(max(page, 1), queuePages(songs))
+	start := (page - 1) * queuePageSize
+	end := min(start+queuePageSize, len(songs))
+
+	lines := make([]string, 0, end-start)
+	var total time.Duration
+	for _, song := range songs {
+		total += song.Duration
+	}
+	for i := start; i < end; i++ {
+		lines = append(lines, queueLine(i+1, songs[i]))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Lista de reproducción:",
+		Description: strings.Join(lines, "\n"),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Página %d de %d · %d canciones · %s en total", page, queuePages(songs), len(songs), utils.FmtDuration(total)),
+		},
+	}
+}
+
+// queueLine es la línea de una canción en el embed de /list: "`1.` Título `03:45` · @miembro", con quien la pidió.
+func queueLine(position int, song *voice.Song) string {
+	line := fmt.Sprintf("`%d.` %s", position, song.GetHumanName())
+	if song.Duration > 0 {
+		line += fmt.Sprintf(" `%s`", utils.FmtDuration(song.Duration))
+	}
+	switch {
+	case song.RequestedByID != "":
+		line += fmt.Sprintf(" · <@%s>", song.RequestedByID)
+	case song.RequestedBy != nil:
+		line += " · " + *song.RequestedBy
+	}
+	return line
+}
+
+// queuePages devuelve la cantidad de páginas de la lista de reproducción; una lista vacía tiene una.
+func queuePages(songs []*voice.Song) int {
+	return max((len(songs)+queuePageSize-1)/queuePageSize, 1)
+}
+
+// queuePageComponents devuelve los botones para pasar de página, deshabilitados en la primera y la última.
+func queuePageComponents(songs []*voice.Song, page int) []discordgo.MessageComponent {
+	pages := queuePages(songs)
+	if pages == 1 {
+		return []discordgo.MessageComponent{}
+	}
+	page = min(max(page, 1), pages)
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "◀", Style: discordgo.SecondaryButton, CustomID: QueuePreviousPageCustomID, Disabled: page == 1},
+		discordgo.Button{Label: "▶", Style: discordgo.SecondaryButton, CustomID: QueueNextPageCustomID, Disabled: page == pages},
+	}}}
+}
+
+// queueEmbedPage devuelve la página que muestra el embed de /list del mensaje, o 1 si no la encuentra.
+func queueEmbedPage(message *discordgo.Message) int {
+	if message == nil || len(message.Embeds) == 0 || message.Embeds[0].Footer == nil {
+		return 1
+	}
+	match := queuePageFooter.FindStringSubmatch(message.Embeds[0].Footer.Text)
+	if match == nil {
+		return 1
+	}
+	page, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 1
+	}
+	return page
+}
+
+// QueuePage maneja los botones de /list: muestra la página anterior o la siguiente de la lista de reproducción, con
+// las canciones que tiene ahora.
+func (handler *InteractionHandler) QueuePage(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("QueuePage")
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	songs, err := player.GetSongs()
+	if err != nil {
+		logger.Error("falló al obtener la lista de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la lista de reproducción")); err != nil {
+			logger.Error("falló al responder con el error de la lista de reproducción", zap.Error(err))
+		}
+		return
+	}
+
+	page := queueEmbedPage(ic.Message) + 1
+	if ic.MessageComponentData().CustomID == QueuePreviousPageCustomID {
+		page -= 2
+	}
+	page = min(max(page, 1), queuePages(songs))
+	data := &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{GenerateQueueEmbed(songs, page)},
+		Components: queuePageComponents(songs, page),
+	}
+	if len(songs) == 0 {
+		data.Embeds = []*discordgo.MessageEmbed{}
+		data.Content = "🫙 La lista de reproducción está vacía"
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	}); err != nil {
+		logger.Error("falló al cambiar de página la lista de reproducción", zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testQueueSongs(n int) []*voice.Song {
+	songs := make([]*voice.Song, n)
+	for i := range songs {
+		songs[i] = &voice.Song{Title: "Tema " + strconv.Itoa(i+1), Duration: time.Minute}
+	}
+	return songs
+}
+
+func TestGenerateQueueEmbed(t *testing.T) {
+	songs := testQueueSongs(23)
+	requester := "Gustavo"
+	songs[10].RequestedBy = &requester
+	songs[11].RequestedBy = &requester
+	songs[11].RequestedByID = "123"
+
+	embed := GenerateQueueEmbed(songs, 2)
+
+	lines := strings.Split(embed.Description, "\n")
+	require.Len(t, lines, queuePageSize)
+	assert.Equal(t, "`11.` Tema 11 `01:00` · Gustavo", lines[0])
+	assert.Equal(t, "`12.` Tema 12 `01:00` · <@123>", lines[1])
+	assert.Equal(t, "Página 2 de 3 · 23 canciones · 23:00 en total", embed.Footer.Text)
+
+	// Las páginas fuera de rango muestran la más cercana.
+	last := GenerateQueueEmbed(songs, 9)
+	assert.Len(t, strings.Split(last.Description, "\n"), 3)
+	assert.Equal(t, 3, queueEmbedPage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{last}}))
+	assert.Equal(t, 1, queueEmbedPage(&discordgo.Message{}))
+}
+
+func TestQueuePageComponents(t *testing.T) {
+	assert.Empty(t, queuePageComponents(testQueueSongs(queuePageSize), 1))
+
+	songs := testQueueSongs(25)
+	buttons := func(page int) []discordgo.MessageComponent {
+		rows := queuePageComponents(songs, page)
+		require.Len(t, rows, 1)
+		return rows[0].(discordgo.ActionsRow).Components
+	}
+
+	assert.True(t, buttons(1)[0].(discordgo.Button).Disabled)
+	assert.False(t, buttons(1)[1].(discordgo.Button).Disabled)
+	assert.False(t, buttons(2)[0].(discordgo.Button).Disabled)
+	assert.True(t, buttons(3)[1].(discordgo.Button).Disabled)
+	assert.Equal(t, QueueNextPageCustomID, buttons(2)[1].(discordgo.Button).CustomID)
+}
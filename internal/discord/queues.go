@@ -0,0 +1,292 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot/store/file_storage"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+)
+
+var (
+	// errQueueNotFound indica que la cola pedida no existe en el servidor.
+	errQueueNotFound = errors.New("la cola no existe")
+	// errQueueActive indica que la cola pedida es la activa, y la operación es para las demás.
+	errQueueActive = errors.New("la cola es la activa")
+)
+
+// parkedQueue devuelve dónde se guardan las canciones de una cola del servidor mientras no está activa. Se guardan
+// en el mismo store que la lista de reproducción, así que sobreviven a un reinicio si el store es persistente.
+func (handler *InteractionHandler) parkedQueue(guildID, name string) store.SongStorage {
+	key := guildID + "-queue-" + url.PathEscape(name)
+	if songs, ok := handler.parkedQueues.Load(key); ok {
+		return songs.(store.SongStorage)
+	}
+	songs, _ := config.GetPlaylistStore(handler.cfg, key, handler.logger, file_storage.NewJSONStatePersistent())
+	actual, _ := handler.parkedQueues.LoadOrStore(key, songs)
+	return actual.(store.SongStorage)
+}
+
+// replaceParked reemplaza las canciones guardadas de una cola inactiva por songs.
+func replaceParked(parked store.SongStorage, songs []*voice.Song) error {
+	return parked.UpdateSongs(func([]*voice.Song) ([]*voice.Song, error) {
+		return songs, nil
+	})
+}
+
+// queuesEnabled responde al usuario si las colas con nombre no están disponibles, porque dependen de la
+// configuración por servidor. Devuelve true si están disponibles.
+func (handler *InteractionHandler) queuesEnabled(ic *discordgo.InteractionCreate) bool {
+	if handler.settings != nil {
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "La configuración por servidor no está habilitada"); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// queueNameOption valida el nombre de cola de la opción name. Si no es válido, responde al usuario y devuelve "".
+func (handler *InteractionHandler) queueNameOption(ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption, option string) string {
+	name, err := settings.ParseQueueName(commandOptions(opt)[option].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 El nombre de la cola puede tener hasta 32 letras, números, espacios, - y _"); err != nil {
+			handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return ""
+	}
+	return name
+}
+
+// respondQueue responde al comando de colas con message, o con el error si falló.
+func (handler *InteractionHandler) respondQueue(ic *discordgo.InteractionCreate, message string, err error) {
+	ctx, logger := handler.requestContext(ic)
+	switch {
+	case errors.Is(err, errQueueNotFound):
+		message = "🤷🏽 No existe esa cola. Mirá las que hay con `/queue queues`"
+	case errors.Is(err, errQueueActive):
+		message = "🤷🏽 Esa es la cola activa"
+	case errors.Is(err, bot.ErrRemoveInvalidPosition):
+		message = "🤷🏽 Posición no válida"
+	case err != nil:
+		logger.Error("falló al actualizar las colas del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al actualizar las colas")
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con las colas", zap.Error(err))
+	}
+}
+
+// CreateQueue crea una cola con nombre vacía en el servidor.
+func (handler *InteractionHandler) CreateQueue(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("CreateQueue")
+	if !handler.queuesEnabled(ic) {
+		return
+	}
+	name := handler.queueNameOption(ic, opt, "name")
+	if name == "" {
+		return
+	}
+
+	var created bool
+	guild, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		created = g.Queues.Add(name)
+	})
+	message := fmt.Sprintf("📂 Cola **%s** creada. Activala con `/queue switch`", name)
+	switch {
+	case err != nil || created:
+	case guild.Queues.Has(name):
+		message = fmt.Sprintf("🤷🏽 Ya existe la cola **%s**", name)
+	default:
+		message = fmt.Sprintf("🤷🏽 Ya hay %d colas en el servidor; borrá alguna con `/queue delete`", len(guild.Queues.Names))
+	}
+	handler.respondQueue(ic, message, err)
+}
+
+// SwitchQueue activa otra cola del servidor: la lista de reproducción actual se guarda en la cola que estaba activa
+// y se reemplaza por las canciones de la nueva. La canción que está sonando sigue hasta terminar.
+func (handler *InteractionHandler) SwitchQueue(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, _ := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SwitchQueue")
+	if !handler.queuesEnabled(ic) {
+		return
+	}
+	name := handler.queueNameOption(ic, opt, "name")
+	if name == "" {
+		return
+	}
+
+	count, err := handler.switchQueue(ctx, handler.getGuildPlayer(GuildID(ic.GuildID), s), ic.GuildID, name)
+	handler.respondQueue(ic, fmt.Sprintf("📂 Cola activa: **%s** (%s)", name, songCount(count)), err)
+}
+
+// switchQueue guarda la lista de reproducción del reproductor en la cola activa y la reemplaza por la de name.
+// Devuelve la cantidad de canciones de la nueva cola activa.
+func (handler *InteractionHandler) switchQueue(ctx context.Context, player *bot.GuildPlayer, guildID, name string) (int, error) {
+	handler.queuesMu.Lock()
+	defer handler.queuesMu.Unlock()
+
+	queues := handler.guildSettings(guildID).Queues
+	switch {
+	case !queues.Has(name):
+		return 0, errQueueNotFound
+	case name == queues.ActiveName():
+		return 0, errQueueActive
+	}
+
+	target := handler.parkedQueue(guildID, name)
+	songs, err := target.GetSongs()
+	if err != nil {
+		return 0, err
+	}
+	// Se toma la lista del reproductor en el mismo cambio que la reemplaza, para no perder lo que se agrega mientras.
+	var current []*voice.Song
+	if err := player.UpdateSongs(ctx, func(playing []*voice.Song) ([]*voice.Song, error) {
+		current = playing
+		return songs, nil
+	}); err != nil {
+		return 0, err
+	}
+	if err := replaceParked(handler.parkedQueue(guildID, queues.ActiveName()), current); err != nil {
+		return 0, err
+	}
+	if err := target.ClearPlaylist(); err != nil {
+		return 0, err
+	}
+	if _, err := settings.Update(handler.settings, guildID, func(g *settings.Guild) { g.Queues.Active = name }); err != nil {
+		return 0, err
+	}
+	return len(songs), nil
+}
+
+// ListQueues muestra las colas del servidor, con cuántas canciones tiene cada una y cuál está activa.
+func (handler *InteractionHandler) ListQueues(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("ListQueues")
+	if !handler.queuesEnabled(ic) {
+		return
+	}
+	message, err := handler.describeQueues(handler.getGuildPlayer(GuildID(ic.GuildID), s), ic.GuildID)
+	handler.respondQueue(ic, message, err)
+}
+
+// describeQueues describe las colas del servidor.
+func (handler *InteractionHandler) describeQueues(player *bot.GuildPlayer, guildID string) (string, error) {
+	queues := handler.guildSettings(guildID).Queues
+	lines := []string{"📂 Colas del servidor:"}
+	for _, name := range queues.All() {
+		var songs []*voice.Song
+		var err error
+		if name == queues.ActiveName() {
+			songs, err = player.GetSongs()
+		} else {
+			songs, err = handler.parkedQueue(guildID, name).GetSongs()
+		}
+		if err != nil {
+			return "", err
+		}
+		line := fmt.Sprintf("• **%s**: %s", name, songCount(len(songs)))
+		if name == queues.ActiveName() {
+			line = fmt.Sprintf("▶️ **%s** (activa): %s", name, songCount(len(songs)))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// DeleteQueue borra una cola del servidor con sus canciones. No se pueden borrar la principal ni la activa.
+func (handler *InteractionHandler) DeleteQueue(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("DeleteQueue")
+	if !handler.queuesEnabled(ic) {
+		return
+	}
+	name := handler.queueNameOption(ic, opt, "name")
+	if name == "" {
+		return
+	}
+
+	if name == settings.DefaultQueue {
+		handler.respondQueue(ic, "🤷🏽 La cola principal no se puede borrar", nil)
+		return
+	}
+	handler.respondQueue(ic, fmt.Sprintf("🗑️ Cola **%s** borrada", name), handler.deleteQueue(ic.GuildID, name))
+}
+
+// deleteQueue borra la cola name del servidor y sus canciones guardadas. La principal no se borra.
+func (handler *InteractionHandler) deleteQueue(guildID, name string) error {
+	handler.queuesMu.Lock()
+	defer handler.queuesMu.Unlock()
+
+	queues := handler.guildSettings(guildID).Queues
+	switch {
+	case !queues.Has(name):
+		return errQueueNotFound
+	case name == queues.ActiveName():
+		return errQueueActive
+	case name == settings.DefaultQueue:
+		return nil
+	}
+
+	if err := handler.parkedQueue(guildID, name).ClearPlaylist(); err != nil {
+		return err
+	}
+	_, err := settings.Update(handler.settings, guildID, func(g *settings.Guild) { g.Queues.Remove(name) })
+	return err
+}
+
+// TransferSong pasa una canción de la cola activa, por su posición, al final de otra cola del servidor.
+func (handler *InteractionHandler) TransferSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	handler.commandUsageCounter.Inc("TransferSong")
+	if !handler.queuesEnabled(ic) {
+		return
+	}
+	name := handler.queueNameOption(ic, opt, "to")
+	if name == "" {
+		return
+	}
+
+	position := int(commandOptions(opt)["position"].IntValue())
+	song, err := handler.transferSong(handler.getGuildPlayer(GuildID(ic.GuildID), s), ic.GuildID, position, name)
+	var message string
+	if err == nil {
+		message = fmt.Sprintf("📂 **%s** pasó a la cola **%s**", song.GetHumanName(), name)
+	}
+	handler.respondQueue(ic, message, err)
+}
+
+// transferSong saca la canción de la posición indicada de la cola activa y la agrega al final de la cola name.
+func (handler *InteractionHandler) transferSong(player *bot.GuildPlayer, guildID string, position int, name string) (*voice.Song, error) {
+	handler.queuesMu.Lock()
+	defer handler.queuesMu.Unlock()
+
+	queues := handler.guildSettings(guildID).Queues
+	switch {
+	case !queues.Has(name):
+		return nil, errQueueNotFound
+	case name == queues.ActiveName():
+		return nil, errQueueActive
+	}
+	song, err := player.RemoveSong(position)
+	if err != nil {
+		return nil, err
+	}
+	if err := handler.parkedQueue(guildID, name).AppendSong(song); err != nil {
+		return nil, err
+	}
+	return song, nil
+}
+
+// songCount describe una cantidad de canciones.
+func songCount(n int) string {
+	if n == 1 {
+		return "1 canción"
+	}
+	return fmt.Sprintf("%d canciones", n)
+}
@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newQueuesTestHandler(t *testing.T) *InteractionHandler {
+	handler, _, _, _ := newPresenceTestHandler()
+	handler.cfg = &config.Config{Store: config.StoreConfig{Type: "memory"}}
+	handler.settings = settings.NewInMemoryStore()
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.Queues.Add("noche de rock") })
+	require.NoError(t, err)
+	return handler
+}
+
+func TestSwitchQueue(t *testing.T) {
+	handler := newQueuesTestHandler(t)
+	_, player, songStorage, _ := newPresenceTestHandler()
+	require.NoError(t, handler.parkedQueue("1", "noche de rock").AppendSong(&voice.Song{Title: "Highway to Hell"}))
+
+	count, err := handler.switchQueue(context.Background(), player, "1", "noche de rock")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	songs, _ := songStorage.GetSongs()
+	require.Len(t, songs, 1)
+	assert.Equal(t, "Highway to Hell", songs[0].Title)
+	assert.Equal(t, "noche de rock", handler.guildSettings("1").Queues.ActiveName())
+
+	// La lista que estaba activa queda guardada en la principal.
+	parked, _ := handler.parkedQueue("1", settings.DefaultQueue).GetSongs()
+	require.Len(t, parked, 1)
+	assert.Equal(t, "Siguiente", parked[0].Title)
+
+	message, err := handler.describeQueues(player, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "📂 Colas del servidor:\n• **principal**: 1 canción\n▶️ **noche de rock** (activa): 1 canción", message)
+
+	_, err = handler.switchQueue(context.Background(), player, "1", "noche de rock")
+	assert.ErrorIs(t, err, errQueueActive)
+	_, err = handler.switchQueue(context.Background(), player, "1", "tranqui")
+	assert.ErrorIs(t, err, errQueueNotFound)
+}
+
+func TestTransferSongAndDeleteQueue(t *testing.T) {
+	handler := newQueuesTestHandler(t)
+	_, player, songStorage, _ := newPresenceTestHandler()
+
+	song, err := handler.transferSong(player, "1", 1, "noche de rock")
+	require.NoError(t, err)
+	assert.Equal(t, "Siguiente", song.Title)
+	songs, _ := songStorage.GetSongs()
+	assert.Empty(t, songs)
+	parked, _ := handler.parkedQueue("1", "noche de rock").GetSongs()
+	require.Len(t, parked, 1)
+
+	_, err = handler.transferSong(player, "1", 1, settings.DefaultQueue)
+	assert.ErrorIs(t, err, errQueueActive)
+
+	require.NoError(t, handler.deleteQueue("1", "noche de rock"))
+	assert.False(t, handler.guildSettings("1").Queues.Has("noche de rock"))
+	parked, _ = handler.parkedQueue("1", "noche de rock").GetSongs()
+	assert.Empty(t, parked)
+	assert.ErrorIs(t, handler.deleteQueue("1", "noche de rock"), errQueueNotFound)
+}
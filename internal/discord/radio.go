@@ -0,0 +1,174 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/Tomas-vilte/GoMusicBot/internal/radio"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// radioOff es el valor de la opción genre de /radio que apaga la radio.
+	radioOff = "off"
+	// radioAhead es la cantidad de canciones pendientes que la radio mantiene en la lista.
+	radioAhead = 2
+	// radioMaxPlayed es la cantidad de canciones que recuerda una estación para no repetirlas.
+	radioMaxPlayed = 500
+)
+
+// radioStation es la fuente del relleno automático de la radio: elige canciones de un género.
+type radioStation struct {
+	handler *InteractionHandler
+	genre   radio.Genre
+	played  map[string]bool // played son las URLs que ya agregó la estación; se usa con el mu del autoFill tomado.
+}
+
+// isRadioStation indica si la fuente de un relleno automático es la radio.
+func isRadioStation(source autoFillSource) bool {
+	_, ok := source.(*radioStation)
+	return ok
+}
+
+// WithRadio establece la fuente de canciones de la radio, que habilita /radio.
+func (handler *InteractionHandler) WithRadio(source radio.Source) *InteractionHandler {
+	handler.radioSource = source
+	return handler
+}
+
+// Radio prende la radio de un género en el servidor: mientras está prendida, el bot mantiene la lista de
+// reproducción con canciones del género. Reemplaza al party shuffle y se apaga sola al detener la reproducción.
+func (handler *InteractionHandler) Radio(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Radio")
+	if handler.radioSource == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "La radio no está habilitada"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	genreID := commandOptions(opt)["genre"].StringValue()
+	if genreID == radioOff {
+		message := "📻 Radio apagada: ya no agrego canciones por mi cuenta"
+		if !handler.stopAutoFill(g.ID, isRadioStation) {
+			message = "🤷🏽 La radio no estaba prendida"
+		}
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con la radio", zap.Error(err))
+		}
+		return
+	}
+	genre, ok := radio.FindGenre(genreID)
+	if !ok {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 No conozco ese género"); err != nil {
+			logger.Error("falló al responder con el error del género", zap.Error(err))
+		}
+		return
+	}
+
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	handler.getGuildPlayer(GuildID(g.ID), s)
+	station := &radioStation{handler: handler, genre: genre, played: make(map[string]bool)}
+	handler.startAutoFill(g.ID, &autoFill{source: station, ahead: radioAhead, voiceChannelID: vs.ChannelID, textChannelID: ic.ChannelID})
+
+	go func() {
+		message := fmt.Sprintf("📻 Radio de **%s** prendida: mientras esté sonando agrego canciones del género. Apagala con %s genre:Apagar", genre.Name, handler.commandName("radio"))
+		if err := handler.refillAutoFill(ctx, g.ID); err != nil {
+			handler.stopAutoFill(g.ID, isRadioStation)
+			message = radioErrorMessage(ctx, err)
+			if message == "" {
+				logger.Error("falló al llenar la lista con la radio", zap.String("genero", genre.ID), zap.Error(err))
+				message = withErrorCode(ctx, "Ocurrió un error al prender la radio")
+			}
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{Content: message}); err != nil {
+			logger.Error("falló al responder con la radio", zap.Error(err))
+		}
+	}()
+}
+
+// Songs pide al buscador de la radio hasta count canciones del género que la estación todavía no agregó.
+func (station *radioStation) Songs(ctx context.Context, guildID string, count int, exclude map[string]bool) ([]*voice.Song, error) {
+	handler := station.handler
+	skip := make(map[string]bool, len(station.played)+len(exclude))
+	for url := range station.played {
+		skip[url] = true
+	}
+	for url := range exclude {
+		skip[url] = true
+	}
+	tracks, err := handler.radioSource.Tracks(ctx, station.genre, count, skip)
+	if err != nil {
+		return nil, err
+	}
+	songs := tracks[:0]
+	for _, song := range tracks {
+		if handler.settings != nil && handler.rejectSong(guildID, song) != "" {
+			continue
+		}
+		requester := "Radio " + station.genre.Name
+		song.RequestedBy = &requester
+		songs = append(songs, song)
+	}
+	if len(songs) == 0 {
+		return nil, radio.ErrNoTracks
+	}
+
+	if len(station.played)+len(songs) > radioMaxPlayed {
+		station.played = make(map[string]bool)
+	}
+	for _, song := range songs {
+		station.played[song.URL] = true
+	}
+	return songs, nil
+}
+
+// radioErrorMessage devuelve el mensaje para los errores esperables de la radio, o "" si el error es inesperado.
+func radioErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, radio.ErrNoTracks):
+		return "🤷🏽 No encontré canciones de ese género"
+	case errors.Is(err, fetcher.ErrSearchNotSupported):
+		return "🤷🏽 La radio no está disponible con este buscador"
+	case errors.Is(err, fetcher.ErrCircuitOpen):
+		return "⚠️ " + fetcher.ErrCircuitOpen.Error()
+	default:
+		return ""
+	}
+}
+
+// radioGenreChoices devuelve las opciones de género de /radio, más la de apagarla.
+func radioGenreChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(radio.Genres)+1)
+	for _, genre := range radio.Genres {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: genre.Name, Value: genre.ID})
+	}
+	return append(choices, &discordgo.ApplicationCommandOptionChoice{Name: "Apagar", Value: radioOff})
+}
@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/radio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRefillRadio(t *testing.T) {
+	handler, player, songStorage, _ := newPresenceTestHandler()
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	source := new(MockRadioSource)
+	handler.WithRadio(source)
+	tango, _ := radio.FindGenre("tango")
+
+	// Sin radio prendida no agrega nada.
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	source.AssertNotCalled(t, "Tracks", mock.Anything, mock.Anything, mock.Anything)
+
+	// La cola ya tiene "Siguiente", así que falta una; no se piden las que ya están en la cola.
+	source.On("Tracks", "tango", 1, mock.MatchedBy(func(exclude map[string]bool) bool { return len(exclude) == 1 })).
+		Return([]*voice.Song{{Title: "Volver", URL: "volver", Playable: true}}, nil).Once()
+	station := &radioStation{handler: handler, genre: tango, played: make(map[string]bool)}
+	handler.startAutoFill("1", &autoFill{source: station, ahead: radioAhead, voiceChannelID: "voz", textChannelID: "texto"})
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	songs, _ := songStorage.GetSongs()
+	require.Len(t, songs, radioAhead)
+	assert.Equal(t, "Radio Tango", *songs[1].RequestedBy)
+
+	// Con la lista llena no pide más.
+	require.NoError(t, handler.refillAutoFill(context.Background(), "1"))
+	source.AssertNumberOfCalls(t, "Tracks", 1)
+
+	// Las que ya agregó no se vuelven a pedir aunque salgan de la cola.
+	_, err := songStorage.RemoveSong(1)
+	require.NoError(t, err)
+	source.On("Tracks", "tango", 1, mock.MatchedBy(func(exclude map[string]bool) bool { return exclude["volver"] })).
+		Return(nil, radio.ErrNoTracks).Once()
+	assert.ErrorIs(t, handler.refillAutoFill(context.Background(), "1"), radio.ErrNoTracks)
+
+	// Al detener la reproducción se apaga.
+	(&autoFillPublisher{handler: handler}).Publish(events.Event{Type: events.TypePlaybackStopped, GuildID: "1"})
+	_, ok := handler.autoFills.Load("1")
+	assert.False(t, ok)
+}
+
+func TestRadioGenreChoices(t *testing.T) {
+	choices := radioGenreChoices()
+
+	require.Len(t, choices, len(radio.Genres)+1)
+	assert.Equal(t, radioOff, choices[len(choices)-1].Value)
+}
@@ -0,0 +1,169 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/Tomas-vilte/GoMusicBot/internal/recommend"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	// recommendDefaultCount es la cantidad de canciones que recomienda /recommend si no se indica otra.
+	recommendDefaultCount = 5
+	// recommendMaxCount es la cantidad máxima de canciones que recomienda /recommend.
+	recommendMaxCount = 10
+	// recommendHistoryWindow es cuánto historial reciente del servidor se usa para recomendar.
+	recommendHistoryWindow = 30 * 24 * time.Hour
+	// recommendHistoryLimit es la cantidad máxima de canciones del historial que se analizan.
+	recommendHistoryLimit = 200
+)
+
+// WithRecommender establece el proveedor de recomendaciones, que habilita /recommend.
+func (handler *InteractionHandler) WithRecommender(provider recommend.Provider) *InteractionHandler {
+	handler.recommender = provider
+	return handler
+}
+
+// Recommend recomienda canciones a partir del historial reciente del servidor. Con la opción queue las agrega a la
+// lista de reproducción; si no, solo las muestra.
+func (handler *InteractionHandler) Recommend(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Recommend")
+	if handler.recommender == nil || handler.history == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Las recomendaciones no están habilitadas"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	options := commandOptions(opt)
+	count := recommendDefaultCount
+	if option, ok := options["count"]; ok {
+		count = int(option.IntValue())
+	}
+	queue := false
+	if option, ok := options["queue"]; ok {
+		queue = option.BoolValue()
+	}
+	var vs *discordgo.VoiceState
+	if queue {
+		if vs = getUsersVoiceState(g, ic.Member.User); vs == nil {
+			if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+				logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+			}
+			return
+		}
+		if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+			return
+		}
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		embed := handler.recommendSongs(ctx, s, g, ic, vs, count)
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		}); err != nil {
+			logger.Error("falló al enviar las recomendaciones", zap.Error(err))
+		}
+	}()
+}
+
+// recommendSongs busca las recomendaciones y, si vs no es nil, las agrega a la lista de reproducción del canal de
+// voz. Devuelve el embed con el resultado.
+func (handler *InteractionHandler) recommendSongs(ctx context.Context, s *discordgo.Session, g *discordgo.Guild, ic *discordgo.InteractionCreate, vs *discordgo.VoiceState, count int) *discordgo.MessageEmbed {
+	logger := logging.FromContext(ctx, handler.logger)
+	entries, err := handler.history.Query(history.Filter{GuildID: g.ID, Since: time.Now().Add(-recommendHistoryWindow), Limit: recommendHistoryLimit})
+	if err != nil {
+		logger.Error("falló al consultar el historial de reproducción", zap.Error(err))
+		return &discordgo.MessageEmbed{Title: withErrorCode(ctx, "Ocurrió un error al consultar el historial del servidor")}
+	}
+	songs, err := handler.recommender.Recommend(ctx, entries, count)
+	if err != nil {
+		logger.Info("falló al buscar recomendaciones", zap.Error(err))
+		return &discordgo.MessageEmbed{Title: recommendErrorMessage(ctx, err)}
+	}
+	// Las canciones que el servidor no permite no se recomiendan.
+	allowed := songs[:0]
+	for _, song := range songs {
+		if handler.settings == nil || handler.rejectSong(g.ID, song) == "" {
+			allowed = append(allowed, song)
+		}
+	}
+	if len(allowed) == 0 {
+		return &discordgo.MessageEmbed{Title: "🤷🏽 No encontré canciones para recomendar"}
+	}
+
+	embed := GenerateRecommendationsEmbed(allowed)
+	if vs == nil {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Usá %s queue:true para agregarlas a la cola", handler.commandName("recommend"))}
+		return embed
+	}
+
+	setRequester(allowed, ic.Member)
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
+	var rejected *bot.RejectedSongsError
+	switch err := player.AddSong(ctx, &ic.ChannelID, &vs.ChannelID, allowed...); {
+	case errors.As(err, &rejected):
+		handler.recordAddedSongs(g.ID, ic.Member.User.ID, allowed, rejected)
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("➕ Se añadieron %d canciones a la cola", rejected.Added)}
+	case err != nil:
+		logger.Error("falló al agregar las canciones recomendadas", zap.Error(err))
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "No se pudieron agregar las canciones a la cola"}
+	default:
+		handler.recordAddedSongs(g.ID, ic.Member.User.ID, allowed, nil)
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("➕ Se añadieron %d canciones a la cola", len(allowed))}
+	}
+	return embed
+}
+
+// GenerateRecommendationsEmbed genera un embed con las canciones recomendadas.
+func GenerateRecommendationsEmbed(songs []*voice.Song) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(songs))
+	for i, song := range songs {
+		line := fmt.Sprintf("%d. [%s](%s)", i+1, song.GetHumanName(), song.URL)
+		if song.Uploader != "" {
+			line += " — " + song.Uploader
+		}
+		lines = append(lines, line)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "✨ Recomendaciones para el servidor",
+		Description: strings.Join(lines, "\n"),
+	}
+}
+
+func recommendErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, recommend.ErrNoHistory):
+		return "🤷🏽 Todavía no escucharon suficiente música en este servidor para recomendarles algo"
+	case errors.Is(err, fetcher.ErrSearchNotSupported):
+		return "🤷🏽 Las recomendaciones no están disponibles con este buscador"
+	case errors.Is(err, fetcher.ErrCircuitOpen):
+		return "⚠️ " + fetcher.ErrCircuitOpen.Error()
+	default:
+		return withErrorCode(ctx, "Ocurrió un error al buscar recomendaciones")
+	}
+}
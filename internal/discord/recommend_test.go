@@ -0,0 +1,16 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateRecommendationsEmbed(t *testing.T) {
+	embed := GenerateRecommendationsEmbed([]*voice.Song{
+		{Title: "Persiana americana", URL: "https://youtu.be/a", Uploader: "Soda Stereo"},
+		{URL: "https://youtu.be/b"},
+	})
+
+	assert.Equal(t, "1. [Persiana americana](https://youtu.be/a) — Soda Stereo\n2. [https://youtu.be/b](https://youtu.be/b)", embed.Description)
+}
@@ -0,0 +1,41 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"runtime/debug"
+)
+
+// InteractionHandlerFunc es la firma de los manejadores de interacciones de Discord.
+type InteractionHandlerFunc func(*discordgo.Session, *discordgo.InteractionCreate)
+
+// RecoverMiddleware envuelve un manejador de interacciones para recuperar los panics y registrarlos con
+// el contexto del servidor y el comando (si el logger reporta a Sentry, el panic llega como evento de error).
+// Así un panic en un comando no tira abajo todo el bot.
+func RecoverMiddleware(logger logging.Logger) func(InteractionHandlerFunc) InteractionHandlerFunc {
+	return func(next InteractionHandlerFunc) InteractionHandlerFunc {
+		return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				command, args := describeInteraction(ic.Interaction)
+				fields := []zap.Field{
+					zap.String("guildID", ic.GuildID),
+					zap.String("command", command),
+					zap.String("args", args),
+					zap.String("panic", fmt.Sprint(recovered)),
+					zap.ByteString("stack", debug.Stack()),
+				}
+				if user := interactionUser(ic.Interaction); user != nil {
+					fields = append(fields, zap.String("userID", user.ID))
+				}
+				logger.Error("panic recuperado al manejar la interacción", fields...)
+			}()
+			next(s, ic)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Run("recupera el panic y lo registra con el contexto", func(t *testing.T) {
+		logger := new(MockLogger)
+		logger.On("Error", "panic recuperado al manejar la interacción", mock.MatchedBy(func(fields []zap.Field) bool {
+			return fields[0].String == "guild-1" && fields[1].String == "play" && fields[3].String == "boom"
+		})).Return()
+
+		ic := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild-1",
+			Member:  &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:    "air",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "play"}},
+			},
+		}}
+
+		handler := RecoverMiddleware(logger)(func(*discordgo.Session, *discordgo.InteractionCreate) {
+			panic("boom")
+		})
+
+		assert.NotPanics(t, func() { handler(nil, ic) })
+		logger.AssertExpectations(t)
+	})
+
+	t.Run("no registra nada si el manejador no falla", func(t *testing.T) {
+		logger := new(MockLogger)
+		called := false
+		handler := RecoverMiddleware(logger)(func(*discordgo.Session, *discordgo.InteractionCreate) {
+			called = true
+		})
+
+		handler(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{}})
+
+		assert.True(t, called)
+		logger.AssertNotCalled(t, "Error", mock.Anything, mock.Anything)
+	})
+}
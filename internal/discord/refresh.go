@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"time"
+)
+
+// WithMetadataRefresh hace que los reproductores revaliden con refresher la metadata de las canciones más viejas
+// que ttl, y la de las transmisiones en vivo, justo antes de reproducirlas.
+func (handler *InteractionHandler) WithMetadataRefresh(refresher fetcher.SongRefresher, ttl time.Duration) *InteractionHandler {
+	handler.refresher = refresher
+	handler.metadataTTL = ttl
+	return handler
+}
+
+// metadataRefresher revalida la metadata de las canciones y, si una ya no está disponible, busca otra con el mismo
+// título para reemplazarla.
+type metadataRefresher struct {
+	refresher fetcher.SongRefresher
+	looker    fetcher.SongLooker
+}
+
+func (r metadataRefresher) RefreshSong(ctx context.Context, song *voice.Song) (*voice.Song, error) {
+	refreshed, err := r.refresher.RefreshSong(ctx, song)
+	if !errors.Is(err, fetcher.ErrSongUnavailable) || song.Title == "" {
+		return refreshed, err
+	}
+
+	videoID, searchErr := r.looker.SearchYouTubeVideoID(ctx, song.Title)
+	if searchErr != nil {
+		return nil, err
+	}
+	found, lookupErr := r.looker.LookupSongs(ctx, videoID)
+	if lookupErr != nil || len(found) != 1 || !found[0].Playable || found[0].CanonicalID() == song.CanonicalID() {
+		return nil, err
+	}
+	replacement := *found[0]
+	replacement.RequestedBy = song.RequestedBy
+	replacement.RequestedByID = song.RequestedByID
+	return &replacement, nil
+}
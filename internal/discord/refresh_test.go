@@ -0,0 +1,64 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMetadataRefresher_RefreshSong(t *testing.T) {
+	requester := "Tomas"
+	song := &voice.Song{Title: "De música ligera", URL: "https://youtu.be/viejo", RequestedBy: &requester, RequestedByID: "u1"}
+
+	t.Run("devuelve la metadata actualizada", func(t *testing.T) {
+		refresher, looker := new(MockSongRefresher), new(MockSongLooker)
+		fresh := &voice.Song{Title: "De música ligera (remasterizado)", URL: song.URL, Playable: true}
+		refresher.On("RefreshSong", song).Return(fresh, nil)
+
+		refreshed, err := metadataRefresher{refresher: refresher, looker: looker}.RefreshSong(context.Background(), song)
+
+		require.NoError(t, err)
+		assert.Same(t, fresh, refreshed)
+		looker.AssertNotCalled(t, "SearchYouTubeVideoID", mock.Anything)
+	})
+
+	t.Run("reemplaza la canción que ya no está disponible", func(t *testing.T) {
+		refresher, looker := new(MockSongRefresher), new(MockSongLooker)
+		refresher.On("RefreshSong", song).Return(nil, fetcher.ErrSongUnavailable)
+		looker.On("SearchYouTubeVideoID", "De música ligera").Return("nuevo", nil)
+		looker.On("LookupSongs", "nuevo").Return([]*voice.Song{{Title: "De música ligera", URL: "https://www.youtube.com/watch?v=nuevo", Playable: true}}, nil)
+
+		refreshed, err := metadataRefresher{refresher: refresher, looker: looker}.RefreshSong(context.Background(), song)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://www.youtube.com/watch?v=nuevo", refreshed.URL)
+		assert.Equal(t, &requester, refreshed.RequestedBy)
+		assert.Equal(t, "u1", refreshed.RequestedByID)
+	})
+
+	t.Run("sin reemplazo devuelve el error", func(t *testing.T) {
+		refresher, looker := new(MockSongRefresher), new(MockSongLooker)
+		refresher.On("RefreshSong", song).Return(nil, fetcher.ErrSongUnavailable)
+		looker.On("SearchYouTubeVideoID", "De música ligera").Return("viejo", nil)
+		looker.On("LookupSongs", "viejo").Return([]*voice.Song{{URL: "https://www.youtube.com/watch?v=viejo", Playable: true}}, nil)
+
+		_, err := metadataRefresher{refresher: refresher, looker: looker}.RefreshSong(context.Background(), song)
+
+		assert.ErrorIs(t, err, fetcher.ErrSongUnavailable)
+	})
+
+	t.Run("otros errores no buscan reemplazo", func(t *testing.T) {
+		refresher, looker := new(MockSongRefresher), new(MockSongLooker)
+		refresher.On("RefreshSong", song).Return(nil, errors.New("cuota agotada"))
+
+		_, err := metadataRefresher{refresher: refresher, looker: looker}.RefreshSong(context.Background(), song)
+
+		assert.EqualError(t, err, "cuota agotada")
+		looker.AssertNotCalled(t, "SearchYouTubeVideoID", mock.Anything)
+	})
+}
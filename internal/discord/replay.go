@@ -0,0 +1,40 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// Replay vuelve a reproducir la canción actual desde el principio.
+func (handler *InteractionHandler) Replay(s *discordgo.Session, ic *discordgo.InteractionCreate, _ *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("Replay")
+
+	player, message := handler.seekPlayer(s, g, ic.Member)
+	if player != nil {
+		song, err := player.Replay()
+		switch {
+		case errors.Is(err, bot.ErrNotPlaying):
+			message = "🤷🏽 No hay ninguna canción sonando"
+		case err != nil:
+			logger.Error("falló al reiniciar la canción", zap.Error(err))
+			message = withErrorCode(ctx, "Ocurrió un error al reiniciar la canción")
+		default:
+			message = fmt.Sprintf("⏮️ %s volvió a poner **%s** desde el principio", getMemberName(ic.Member), song.GetHumanName())
+		}
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a /replay", zap.Error(err))
+	}
+}
@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/rest"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
@@ -31,10 +32,44 @@ func (s *DiscordSessionService) FollowupMessageCreate(i *discordgo.Interaction,
 	return s.session.FollowupMessageCreate(i, wait, params)
 }
 
+// RetrySessionService es un SessionService que reintenta las respuestas a interacciones que fallan por límites
+// de tasa o errores transitorios de Discord, para que no se pierdan durante las ráfagas.
+type RetrySessionService struct {
+	session SessionService
+	policy  rest.RetryPolicy
+	logger  logging.Logger
+}
+
+// NewRetrySessionService crea una nueva instancia de RetrySessionService.
+func NewRetrySessionService(session SessionService, policy rest.RetryPolicy, logger logging.Logger) *RetrySessionService {
+	return &RetrySessionService{
+		session: session,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+func (s *RetrySessionService) InteractionRespond(i *discordgo.Interaction, r *discordgo.InteractionResponse) error {
+	return rest.Retry(s.policy, s.logger, func() error {
+		return s.session.InteractionRespond(i, r)
+	})
+}
+
+func (s *RetrySessionService) FollowupMessageCreate(i *discordgo.Interaction, wait bool, params *discordgo.WebhookParams) (*discordgo.Message, error) {
+	var msg *discordgo.Message
+	err := rest.Retry(s.policy, s.logger, func() error {
+		var err error
+		msg, err = s.session.FollowupMessageCreate(i, wait, params)
+		return err
+	})
+	return msg, err
+}
+
 // ResponseHandler define la interfaz para manejar respuestas a interacciones de Discord.
 type ResponseHandler interface {
 	Respond(session SessionService, interaction *discordgo.Interaction, response discordgo.InteractionResponse) error
 	RespondWithMessage(session SessionService, interaction *discordgo.Interaction, message string) error
+	RespondWithEphemeralMessage(session SessionService, interaction *discordgo.Interaction, message string) error
 	CreateFollowupMessage(session SessionService, interaction *discordgo.Interaction, params discordgo.WebhookParams) error
 }
 
@@ -68,6 +103,18 @@ func (h *DiscordResponseHandler) RespondWithMessage(session SessionService, inte
 	return h.Respond(session, interaction, response)
 }
 
+// RespondWithEphemeralMessage responde a una interacción de Discord con un mensaje de texto visible solo para quien la invocó.
+func (h *DiscordResponseHandler) RespondWithEphemeralMessage(session SessionService, interaction *discordgo.Interaction, message string) error {
+	response := discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+	return h.Respond(session, interaction, response)
+}
+
 // CreateFollowupMessage crea un mensaje de seguimiento para una interacción de Discord.
 func (h *DiscordResponseHandler) CreateFollowupMessage(session SessionService, interaction *discordgo.Interaction, params discordgo.WebhookParams) error {
 	if _, err := session.FollowupMessageCreate(interaction, true, &params); err != nil {
@@ -2,9 +2,12 @@ package discord
 
 import (
 	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/rest"
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/mock"
+	"net/http"
 	"testing"
+	"time"
 )
 
 // MockSessionService es una implementación de SessionService para usar en pruebas.
@@ -141,3 +144,29 @@ func TestDiscordResponseHandler_CreateFollowupMessage_Error(t *testing.T) {
 
 	mockSession.AssertExpectations(t)
 }
+
+func TestRetrySessionService_FollowupMessageCreate(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockSession := new(MockSessionService)
+	service := NewRetrySessionService(mockSession, rest.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}, mockLogger)
+
+	interaction := &discordgo.Interaction{}
+	params := &discordgo.WebhookParams{}
+	expected := &discordgo.Message{ID: "123"}
+	unavailable := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}}
+
+	mockSession.On("FollowupMessageCreate", interaction, true, params).Return((*discordgo.Message)(nil), unavailable).Once()
+	mockSession.On("FollowupMessageCreate", interaction, true, params).Return(expected, nil).Once()
+	mockLogger.On("Warn", "Reintentando llamada a Discord", mock.AnythingOfType("[]zapcore.Field")).Return()
+
+	msg, err := service.FollowupMessageCreate(interaction, true, params)
+	if err != nil {
+		t.Fatalf("No se esperaba un error, pero se obtuvo: %v", err)
+	}
+	if msg != expected {
+		t.Errorf("Se esperaba el mensaje %v, pero se obtuvo: %v", expected, msg)
+	}
+
+	mockSession.AssertNumberOfCalls(t, "FollowupMessageCreate", 2)
+	mockLogger.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configura los reintentos de las llamadas a la API REST de Discord.
+type RetryPolicy struct {
+	MaxAttempts int           // MaxAttempts es la cantidad máxima de intentos, contando el primero.
+	BaseDelay   time.Duration // BaseDelay es la espera antes del primer reintento; se duplica en cada intento.
+	MaxDelay    time.Duration // MaxDelay es la espera máxima entre intentos.
+}
+
+// DefaultRetryPolicy es la política usada para responder interacciones: las esperas son cortas porque
+// Discord invalida la interacción si no se responde en 3 segundos.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// sleep se puede reemplazar en las pruebas para no esperar.
+var sleep = time.Sleep
+
+// Retry ejecuta op y la reintenta con backoff exponencial y jitter mientras Discord responda con un límite de tasa
+// (429), un error 5xx o falle la red. En los 429 se respeta la espera que indica Discord.
+func Retry(policy RetryPolicy, logger logging.Logger, op func() error) error {
+	var err error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		delay, retryable := retryDelay(err, policy, attempt)
+		// Si Discord pide esperar más que MaxDelay no se reintenta: la interacción vencería antes.
+		if !retryable || delay > policy.MaxDelay || attempt == policy.MaxAttempts-1 {
+			return err
+		}
+		logger.Warn("Reintentando llamada a Discord", zap.Int("intento", attempt+1), zap.Duration("espera", delay), zap.Error(err))
+		sleep(delay)
+	}
+	return err
+}
+
+// retryDelay indica si el error amerita un reintento y cuánto esperar antes de hacerlo.
+func retryDelay(err error, policy RetryPolicy, attempt int) (time.Duration, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RateLimit != nil && rateLimitErr.TooManyRequests != nil {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch status := restErr.Response.StatusCode; {
+		case status == http.StatusTooManyRequests:
+			if delay, ok := retryAfter(restErr.Response.Header); ok {
+				return delay, true
+			}
+			return backoff(policy, attempt), true
+		case status >= http.StatusInternalServerError:
+			return backoff(policy, attempt), true
+		default:
+			return 0, false
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return backoff(policy, attempt), true
+	}
+	return 0, false
+}
+
+// retryAfter lee la espera que pide Discord en los headers de la respuesta (en segundos, con decimales).
+func retryAfter(header http.Header) (time.Duration, bool) {
+	for _, name := range []string{"Retry-After", "X-RateLimit-Reset-After"} {
+		if value := header.Get(name); value != "" {
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+				return time.Duration(seconds * float64(time.Second)), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// backoff calcula la espera exponencial con jitter: un valor al azar entre la mitad y el total de la espera.
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay << attempt
+	if delay <= 0 || delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
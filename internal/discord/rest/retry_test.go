@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func restError(status int, header http.Header) error {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &discordgo.RESTError{Response: &http.Response{StatusCode: status, Header: header}}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	defer func() { sleep = time.Sleep }()
+
+	t.Run("respeta la espera que pide Discord en un 429", func(t *testing.T) {
+		waits = nil
+		logger := new(MockLogger)
+		logger.On("Warn", "Reintentando llamada a Discord", mock.Anything).Return()
+
+		calls := 0
+		err := Retry(policy, logger, func() error {
+			calls++
+			if calls == 1 {
+				return restError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1.5"}})
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []time.Duration{1500 * time.Millisecond}, waits)
+	})
+
+	t.Run("reintenta los 5xx con backoff hasta agotar los intentos", func(t *testing.T) {
+		waits = nil
+		logger := new(MockLogger)
+		logger.On("Warn", "Reintentando llamada a Discord", mock.Anything).Return()
+
+		calls := 0
+		err := Retry(policy, logger, func() error {
+			calls++
+			return restError(http.StatusBadGateway, nil)
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+		if assert.Len(t, waits, 2) {
+			assert.GreaterOrEqual(t, waits[0], 50*time.Millisecond)
+			assert.LessOrEqual(t, waits[0], 100*time.Millisecond)
+			assert.GreaterOrEqual(t, waits[1], 100*time.Millisecond)
+			assert.LessOrEqual(t, waits[1], 200*time.Millisecond)
+		}
+	})
+
+	t.Run("no reintenta los errores del cliente", func(t *testing.T) {
+		waits = nil
+		logger := new(MockLogger)
+
+		calls := 0
+		err := Retry(policy, logger, func() error {
+			calls++
+			return restError(http.StatusBadRequest, nil)
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, waits)
+	})
+
+	t.Run("no reintenta si Discord pide esperar más que MaxDelay", func(t *testing.T) {
+		waits = nil
+		logger := new(MockLogger)
+
+		calls := 0
+		err := Retry(policy, logger, func() error {
+			calls++
+			return restError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}})
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("no reintenta errores desconocidos", func(t *testing.T) {
+		calls := 0
+		err := Retry(policy, new(MockLogger), func() error {
+			calls++
+			return errors.New("boom")
+		})
+
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, 1, calls)
+	})
+}
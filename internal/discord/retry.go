@@ -0,0 +1,97 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// RetrySongCustomID y SearchSongCustomID son los botones del aviso de que no se pudo agregar una canción: volver
+	// a intentar con lo mismo que se pidió, o buscarlo y agregar el primer resultado que se pueda reproducir.
+	RetrySongCustomID  = "retry_song"
+	SearchSongCustomID = "retry_song_search"
+	// retrySearchResults es la cantidad de resultados entre los que se busca uno reproducible.
+	retrySearchResults = 5
+)
+
+// failedSongComponents devuelve los botones del aviso de que no se pudo agregar una canción.
+func failedSongComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Reintentar", Style: discordgo.PrimaryButton, CustomID: RetrySongCustomID, Emoji: &discordgo.ComponentEmoji{Name: "🔄"}},
+				discordgo.Button{Label: "Buscar en su lugar", Style: discordgo.SecondaryButton, CustomID: SearchSongCustomID, Emoji: &discordgo.ComponentEmoji{Name: "🔎"}},
+			},
+		},
+	}
+}
+
+// failedSongInput devuelve lo que se había pedido en el aviso de que no se pudo agregar una canción, que es el título
+// del embed.
+func failedSongInput(message *discordgo.Message) (string, bool) {
+	if message == nil || len(message.Embeds) == 0 || message.Embeds[0].Title == "" {
+		return "", false
+	}
+	return message.Embeds[0].Title, true
+}
+
+// RetrySong maneja los botones del aviso de que no se pudo agregar una canción: la vuelve a buscar, como /play, y la
+// agrega a la cola del canal de voz de quien lo aprieta.
+func (handler *InteractionHandler) RetrySong(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	input, ok := failedSongInput(ic.Message)
+	if !ok {
+		logger.Error("el mensaje no tiene la canción que no se pudo agregar")
+		return
+	}
+	if ic.MessageComponentData().CustomID == SearchSongCustomID {
+		handler.commandUsageCounter.Inc("SearchSong")
+		handler.searchAndAddWith(ctx, s, ic, input, handler.searchAlternativeAndAdd)
+		return
+	}
+	handler.commandUsageCounter.Inc("RetrySong")
+	handler.searchAndAdd(ctx, s, ic, input)
+}
+
+// searchAlternativeAndAdd busca input y agrega el primero de los resultados que se pueda reproducir, para cuando el
+// primer resultado de /play no sirve. Si la búsqueda no devuelve varios resultados, hace lo mismo que lookupAndAdd.
+func (handler *InteractionHandler) searchAlternativeAndAdd(ctx context.Context, player *bot.GuildPlayer, interaction *discordgo.Interaction, voiceChannelID, input string) {
+	searcher, ok := handler.songLookup.(fetcher.SongSearcher)
+	if !ok {
+		handler.lookupAndAdd(ctx, player, interaction, voiceChannelID, input)
+		return
+	}
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", interaction.GuildID), zap.String("input", input))
+	videoIDs, err := searcher.SearchYouTubeVideoIDs(ctx, input, retrySearchResults)
+	if err != nil {
+		logger.Error("Error al buscar los videos en YouTube", zap.Error(err))
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds:     []*discordgo.MessageEmbed{failedLookupEmbed(ctx, err, input, interaction.Member)},
+			Components: failedSongComponents(),
+		}); err != nil {
+			logger.Error("falló al enviar el mensaje de seguimiento de error al buscar los videos", zap.Error(err))
+		}
+		return
+	}
+	handler.addLookedUp(ctx, player, interaction, voiceChannelID, input, handler.firstPlayable(ctx, logger, videoIDs))
+}
+
+// firstPlayable devuelve la primera canción reproducible de los videos, o ninguna si no hay.
+func (handler *InteractionHandler) firstPlayable(ctx context.Context, logger logging.Logger, videoIDs []string) []*voice.Song {
+	for _, videoID := range videoIDs {
+		songs, err := handler.songLookup.LookupSongs(ctx, videoID)
+		if err != nil {
+			logger.Info("falló al buscar la metadata de un resultado", zap.String("videoID", videoID), zap.Error(err))
+			continue
+		}
+		if len(songs) > 0 && songs[0].Playable {
+			return songs[:1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFailedSongInput(t *testing.T) {
+	embed := withErrorCodeEmbed(context.Background(), GenerateFailedToAddSongEmbed("la bamba", &discordgo.Member{User: &discordgo.User{Username: "tomas"}}))
+	input, ok := failedSongInput(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.True(t, ok)
+	assert.Equal(t, "la bamba", input)
+
+	_, ok = failedSongInput(&discordgo.Message{})
+	assert.False(t, ok)
+	_, ok = failedSongInput(nil)
+	assert.False(t, ok)
+}
+
+func TestFailedSongComponents(t *testing.T) {
+	row := failedSongComponents()[0].(discordgo.ActionsRow)
+	require.Len(t, row.Components, 2)
+	assert.Equal(t, RetrySongCustomID, row.Components[0].(discordgo.Button).CustomID)
+	assert.Equal(t, SearchSongCustomID, row.Components[1].(discordgo.Button).CustomID)
+}
+
+func TestFirstPlayable(t *testing.T) {
+	handler, _, _, _ := newPresenceTestHandler()
+	looker := new(MockSongLooker)
+	looker.On("LookupSongs", "caido").Return(nil, errors.New("video no disponible"))
+	looker.On("LookupSongs", "privado").Return([]*voice.Song{{Title: "Privado", Playable: false}}, nil)
+	looker.On("LookupSongs", "bueno").Return([]*voice.Song{{Title: "La Bamba", Playable: true}}, nil)
+	handler.songLookup = looker
+
+	songs := handler.firstPlayable(context.Background(), handler.logger, []string{"caido", "privado", "bueno", "otro"})
+	require.Len(t, songs, 1)
+	assert.Equal(t, "La Bamba", songs[0].Title)
+	looker.AssertNotCalled(t, "LookupSongs", "otro")
+
+	assert.Empty(t, handler.firstPlayable(context.Background(), handler.logger, []string{"caido", "privado"}))
+}
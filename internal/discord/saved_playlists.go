@@ -0,0 +1,349 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// maxBrowsedPlaylists es la cantidad de listas públicas que muestra /playlist browse.
+const maxBrowsedPlaylists = 15
+
+// WithSavedPlaylists habilita los comandos /playlist para guardar la lista de reproducción y compartirla con otros
+// servidores.
+func (handler *InteractionHandler) WithSavedPlaylists(service *playlists.Service) *InteractionHandler {
+	handler.savedPlaylists = service
+	return handler
+}
+
+// SavePlaylist guarda la canción actual y la lista de reproducción con un nombre.
+func (handler *InteractionHandler) SavePlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SavePlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	options := commandOptions(opt)
+	name := strings.TrimSpace(options["name"].StringValue())
+	var visibility playlists.Visibility
+	if option, ok := options["visibility"]; ok {
+		visibility = playlists.Visibility(option.StringValue())
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	songs, err := player.GetSongs()
+	if err != nil {
+		logger.Error("falló al obtener la lista de reproducción", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la lista de reproducción")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if played, err := player.GetPlayedSong(); err == nil && played != nil {
+		songs = append([]*voice.Song{&played.Song}, songs...)
+	}
+
+	saved, err := handler.savedPlaylists.Save(ic.GuildID, interactionUser(ic.Interaction).ID, name, playlists.FromVoiceSongs(songs), visibility)
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	message := fmt.Sprintf("💾 Lista **%s** guardada con %d canciones. Código para compartirla: `%s` (%s)", saved.Name, len(saved.Songs), saved.Code, describeVisibility(saved.Visibility))
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista guardada", zap.Error(err))
+	}
+}
+
+// SharePlaylist cambia quién puede importar una lista guardada con su código y muestra el código.
+func (handler *InteractionHandler) SharePlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SharePlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	name := strings.TrimSpace(options["name"].StringValue())
+	visibility, err := playlists.ParseVisibility(options["visibility"].StringValue())
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+
+	shared, err := handler.savedPlaylists.Share(ic.GuildID, interactionUser(ic.Interaction).ID, name, visibility)
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	message := fmt.Sprintf("🔗 Lista **%s**: %s. Para usarla en otro servidor: `%s code:%s`", shared.Name, describeVisibility(shared.Visibility), handler.commandName("playlist import"), shared.Code)
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista compartida", zap.Error(err))
+	}
+}
+
+// ImportPlaylist copia en el servidor una lista compartida con un código y, si el usuario está en un canal de voz,
+// la agrega a la lista de reproducción.
+func (handler *InteractionHandler) ImportPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ImportPlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	options := commandOptions(opt)
+	var name string
+	if option, ok := options["name"]; ok {
+		name = strings.TrimSpace(option.StringValue())
+	}
+	imported, err := handler.savedPlaylists.Import(options["code"].StringValue(), ic.GuildID, interactionUser(ic.Interaction).ID, name)
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+
+	message := fmt.Sprintf("📥 Lista **%s** importada con %d canciones", imported.Name, len(imported.Songs))
+	if vs := getUsersVoiceState(g, ic.Member.User); vs != nil {
+		message += handler.queueSavedPlaylist(s, ic, g, vs, imported)
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista importada", zap.Error(err))
+	}
+}
+
+// LoadPlaylist agrega a la lista de reproducción las canciones de una lista guardada en el servidor.
+func (handler *InteractionHandler) LoadPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("LoadPlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error de no estar en un canal de voz", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+		return
+	}
+
+	playlist, err := handler.savedPlaylists.Get(ic.GuildID, strings.TrimSpace(commandOptions(opt)["name"].StringValue()))
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	message := fmt.Sprintf("📂 Lista **%s** cargada con %d canciones", playlist.Name, len(playlist.Songs))
+	message += handler.queueSavedPlaylist(s, ic, g, vs, playlist)
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista cargada", zap.Error(err))
+	}
+}
+
+// ListPlaylists muestra las listas guardadas en el servidor.
+func (handler *InteractionHandler) ListPlaylists(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ListPlaylists")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	saved, err := handler.savedPlaylists.List(ic.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener las listas del servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las listas guardadas")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateGuildPlaylistsEmbed(saved)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las listas del servidor", zap.Error(err))
+	}
+}
+
+// DeletePlaylist borra una lista guardada en el servidor. Solo pueden hacerlo quien la creó y los administradores.
+func (handler *InteractionHandler) DeletePlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("DeletePlaylist")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	name := strings.TrimSpace(commandOptions(opt)["name"].StringValue())
+	if err := handler.savedPlaylists.Delete(ic.GuildID, interactionUser(ic.Interaction).ID, name, isGuildAdmin(ic.Member)); err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("🗑️ Lista **%s** borrada", name)); err != nil {
+		logger.Error("falló al responder con la lista borrada", zap.Error(err))
+	}
+}
+
+// queueSavedPlaylist agrega las canciones de una lista guardada a la cola del canal de voz del usuario y devuelve el
+// final del mensaje con el resultado.
+func (handler *InteractionHandler) queueSavedPlaylist(s *discordgo.Session, ic *discordgo.InteractionCreate, g *discordgo.Guild, vs *discordgo.VoiceState, playlist playlists.Playlist) string {
+	ctx, logger := handler.requestContext(ic)
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
+	songs := playlist.VoiceSongs(getMemberName(ic.Member))
+	setRequester(songs, ic.Member)
+	var rejected *bot.RejectedSongsError
+	if err := player.AddSong(ctx, &ic.ChannelID, &vs.ChannelID, songs...); errors.As(err, &rejected) {
+		if rejected.Added == 0 {
+			return ", pero todas sus canciones están bloqueadas en este servidor"
+		}
+		return fmt.Sprintf(" y agregada a la cola (🚫 %d canciones bloqueadas en este servidor)", len(rejected.Rejections))
+	} else if err != nil {
+		logger.Error("falló al agregar la lista guardada", zap.Error(err))
+		return ", pero no se pudo agregar a la cola"
+	}
+	return " y agregada a la cola"
+}
+
+// BrowsePlaylists muestra las listas públicas de todos los servidores con sus códigos.
+func (handler *InteractionHandler) BrowsePlaylists(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("BrowsePlaylists")
+	if !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	public, err := handler.savedPlaylists.Public(maxBrowsedPlaylists)
+	if err != nil {
+		logger.Error("falló al obtener las listas públicas", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las listas públicas")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GeneratePublicPlaylistsEmbed(public)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las listas públicas", zap.Error(err))
+	}
+}
+
+// GeneratePublicPlaylistsEmbed genera un embed con las listas públicas y sus códigos.
+func GeneratePublicPlaylistsEmbed(public []playlists.Playlist) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "🌎 Listas públicas"}
+	if len(public) == 0 {
+		embed.Description = "Todavía no hay listas públicas"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, playlist := range public {
+		builder.WriteString(fmt.Sprintf("**%s** (%d canciones) `%s`\n", playlist.Name, len(playlist.Songs), playlist.Code))
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
+
+// GenerateGuildPlaylistsEmbed genera un embed con las listas guardadas en el servidor.
+func GenerateGuildPlaylistsEmbed(saved []playlists.Playlist) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "📂 Listas guardadas"}
+	if len(saved) == 0 {
+		embed.Description = "Todavía no hay listas guardadas en este servidor"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, playlist := range saved {
+		builder.WriteString(fmt.Sprintf("**%s** (%d canciones) de <@%s>\n", playlist.Name, len(playlist.Songs), playlist.OwnerID))
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
+
+// savedPlaylistsEnabled responde al usuario si las listas guardadas no están configuradas.
+func (handler *InteractionHandler) savedPlaylistsEnabled(ic *discordgo.InteractionCreate) bool {
+	if handler.savedPlaylists != nil {
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Las listas guardadas no están habilitadas"); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// respondSavedPlaylistError responde con el motivo por el que falló una operación sobre una lista guardada.
+func (handler *InteractionHandler) respondSavedPlaylistError(ic *discordgo.InteractionCreate, err error) {
+	ctx, logger := handler.requestContext(ic)
+	var message string
+	switch {
+	case errors.Is(err, playlists.ErrNotFound):
+		message = "🤷🏽 No encontré esa lista"
+	case errors.Is(err, playlists.ErrForbidden):
+		message = "🔒 Esa lista no es tuya o es privada"
+	case errors.Is(err, playlists.ErrNameTaken):
+		message = "🙅 Ya hay una lista con ese nombre creada por otra persona"
+	case errors.Is(err, playlists.ErrEmpty):
+		message = "🫙 La lista de reproducción está vacía"
+	case errors.Is(err, playlists.ErrInvalidVisibility):
+		message = "🤷🏽 Visibilidad no válida"
+	default:
+		logger.Error("falló la operación sobre la lista guardada", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error con la lista guardada")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el error de la lista guardada", zap.Error(err))
+	}
+}
+
+// describeVisibility describe quién puede importar una lista con su código.
+func describeVisibility(visibility playlists.Visibility) string {
+	switch visibility {
+	case playlists.VisibilityPublic:
+		return "pública, aparece en el listado de listas públicas"
+	case playlists.VisibilityUnlisted:
+		return "cualquiera con el código puede importarla"
+	default:
+		return "privada, solo vos podés importarla"
+	}
+}
+
+// commandOptions indexa las opciones de un subcomando por nombre.
+func commandOptions(opt *discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opt.Options))
+	for _, option := range opt.Options {
+		options[option.Name] = option
+	}
+	return options
+}
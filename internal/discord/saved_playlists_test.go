@@ -0,0 +1,18 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateGuildPlaylistsEmbed(t *testing.T) {
+	embed := GenerateGuildPlaylistsEmbed(nil)
+	assert.Equal(t, "Todavía no hay listas guardadas en este servidor", embed.Description)
+
+	embed = GenerateGuildPlaylistsEmbed([]playlists.Playlist{
+		{Name: "Asado", OwnerID: "user-1", Songs: []playlists.Song{{Title: "La Bamba"}}},
+		{Name: "Fiesta", OwnerID: "user-2", Songs: []playlists.Song{{Title: "La Bamba"}, {Title: "Cielito lindo"}}},
+	})
+	assert.Equal(t, "**Asado** (1 canciones) de <@user-1>\n**Fiesta** (2 canciones) de <@user-2>", embed.Description)
+}
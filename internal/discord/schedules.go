@@ -0,0 +1,181 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/scheduled"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// scheduleRequester es el nombre con el que se agregan las canciones de las reproducciones programadas.
+const scheduleRequester = "Programación"
+
+// WithSchedules habilita los comandos /schedule para programar reproducciones de listas guardadas. Las expresiones
+// se interpretan en la zona horaria location.
+func (handler *InteractionHandler) WithSchedules(store scheduled.Store, location *time.Location) *InteractionHandler {
+	handler.schedules = store
+	handler.scheduleLocation = location
+	return handler
+}
+
+// ScheduleAdd programa la reproducción de una lista guardada en un canal de voz. Solo disponible para
+// administradores.
+func (handler *InteractionHandler) ScheduleAdd(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ScheduleAdd")
+	if !handler.schedulesAdmin(ic) || !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	playlist, err := handler.savedPlaylists.Get(ic.GuildID, strings.TrimSpace(options["playlist"].StringValue()))
+	if err != nil {
+		handler.respondSavedPlaylistError(ic, err)
+		return
+	}
+	channelID := options["channel"].Value.(string)
+	schedule, err := scheduled.NewSchedule(ic.GuildID, channelID, ic.ChannelID, playlist.Name, options["when"].StringValue(), interactionUser(ic.Interaction).ID)
+	if err == nil {
+		err = scheduled.Add(handler.schedules, schedule)
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, scheduled.ErrInvalidSpec):
+		message = "🤷🏽 No entendí cuándo. Usá `weekdays 09:00`, `daily 21:30`, `mon,wed,fri 18:00` o una expresión cron como `0 9 * * 1-5`"
+	case errors.Is(err, scheduled.ErrTooMany):
+		message = fmt.Sprintf("🙅 El servidor ya tiene %d reproducciones programadas", scheduled.MaxSchedulesPerGuild)
+	case err != nil:
+		logger.Error("falló al guardar la reproducción programada", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la programación")
+	default:
+		message = fmt.Sprintf("⏰ Programado `%s`: **%s** en <#%s> (`%s`)", schedule.ID, playlist.Name, channelID, schedule.Spec)
+		if next, err := schedule.Next(handler.GuildLocation(ic.GuildID)); err == nil && !next.IsZero() {
+			message += fmt.Sprintf(", la próxima vez <t:%d:R>", next.Unix())
+		}
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la reproducción programada", zap.Error(err))
+	}
+}
+
+// ScheduleList muestra las reproducciones programadas del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) ScheduleList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ScheduleList")
+	if !handler.schedulesAdmin(ic) {
+		return
+	}
+
+	schedules, err := handler.schedules.List(ic.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener las reproducciones programadas", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las programaciones")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateSchedulesEmbed(scheduled.Filter(schedules, false), handler.GuildLocation(ic.GuildID))},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las reproducciones programadas", zap.Error(err))
+	}
+}
+
+// ScheduleRemove elimina una reproducción programada del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) ScheduleRemove(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("ScheduleRemove")
+	if !handler.schedulesAdmin(ic) {
+		return
+	}
+
+	id := strings.ToLower(strings.TrimSpace(commandOptions(opt)["id"].StringValue()))
+	message := fmt.Sprintf("🗑️ Programación `%s` eliminada", id)
+	if err := handler.schedules.Delete(ic.GuildID, id); errors.Is(err, scheduled.ErrNotFound) {
+		message = "🤷🏽 No encontré esa programación"
+	} else if err != nil {
+		logger.Error("falló al eliminar la reproducción programada", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al eliminar la programación")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la reproducción programada", zap.Error(err))
+	}
+}
+
+// RunSchedule agrega la lista guardada de la programación, o la canción de la alarma, a la lista de reproducción de
+// su canal de voz. Es la scheduled.RunFunc del scheduler. En modo cluster solo la reproduce la instancia que tiene el
+// servidor, para que no se agregue una vez por instancia.
+func (handler *InteractionHandler) RunSchedule(ctx context.Context, schedule scheduled.Schedule) error {
+	if handler.voiceGate != nil && !handler.voiceGate.Owns(ctx, schedule.GuildID) {
+		return nil
+	}
+	if schedule.IsAlarm() {
+		return handler.runAlarm(ctx, schedule)
+	}
+	if handler.savedPlaylists == nil {
+		return errors.New("las listas guardadas no están habilitadas")
+	}
+	main, err := handler.playerFor(schedule.GuildID)
+	if err != nil {
+		return err
+	}
+	playlist, err := handler.savedPlaylists.Get(schedule.GuildID, schedule.Playlist)
+	if err != nil {
+		return fmt.Errorf("error al obtener la lista %s: %w", schedule.Playlist, err)
+	}
+	player := handler.playerForVoiceChannel(main, schedule.GuildID, schedule.VoiceChannelID)
+	err = player.AddSong(ctx, &schedule.TextChannelID, &schedule.VoiceChannelID, playlist.VoiceSongs(scheduleRequester)...)
+	var rejected *bot.RejectedSongsError
+	if err != nil && !(errors.As(err, &rejected) && rejected.Added > 0) {
+		return fmt.Errorf("error al agregar la lista %s: %w", schedule.Playlist, err)
+	}
+	return nil
+}
+
+// schedulesAdmin responde al usuario si no es administrador o si las reproducciones programadas no están
+// habilitadas.
+func (handler *InteractionHandler) schedulesAdmin(ic *discordgo.InteractionCreate) bool {
+	message := ""
+	switch {
+	case !isGuildAdmin(ic.Member):
+		message = ErrorMessageAdminOnly
+	case handler.schedules == nil:
+		message = "Las reproducciones programadas no están habilitadas"
+	default:
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// GenerateSchedulesEmbed genera un embed con las reproducciones programadas y cuándo les toca, en la zona horaria loc.
+func GenerateSchedulesEmbed(schedules []scheduled.Schedule, loc *time.Location) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "⏰ Reproducciones programadas"}
+	if len(schedules) == 0 {
+		embed.Description = "No hay reproducciones programadas en este servidor"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, schedule := range schedules {
+		builder.WriteString(fmt.Sprintf("`%s` **%s** en <#%s> · `%s`", schedule.ID, schedule.Playlist, schedule.VoiceChannelID, schedule.Spec))
+		if next, err := schedule.Next(loc); err == nil && !next.IsZero() {
+			builder.WriteString(fmt.Sprintf(" · <t:%d:R>", next.Unix()))
+		}
+		builder.WriteString("\n")
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: "Zona horaria: " + loc.String()}
+	return embed
+}
@@ -0,0 +1,178 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SeekSong mueve la canción actual a la posición de la opción position, como "1:23".
+func (handler *InteractionHandler) SeekSong(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("SeekSong")
+
+	input := commandOptions(opt)["position"].StringValue()
+	position, ok := voice.ParsePosition(input)
+	if !ok {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, fmt.Sprintf("🤔 No entendí la posición %q. Escribila como 1:23, 1:02:03 o en segundos", input)); err != nil {
+			logger.Error("falló al responder con el error de /seek", zap.Error(err))
+		}
+		return
+	}
+
+	player, message := handler.seekPlayer(s, g, ic.Member)
+	if player != nil {
+		message = handler.seekTo(ctx, logger, player, getMemberName(ic.Member), position)
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder a /seek", zap.Error(err))
+	}
+}
+
+// seekTo mueve la canción actual a position y devuelve el mensaje para quien usó el comando.
+func (handler *InteractionHandler) seekTo(ctx context.Context, logger logging.Logger, player *bot.GuildPlayer, member string, position time.Duration) string {
+	played, err := player.GetPlayedSong()
+	if err != nil {
+		logger.Error("falló al obtener la canción actual", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al mover la canción")
+	}
+	if played == nil || !player.IsPlaying() {
+		return "🤷🏽 No hay ninguna canción sonando"
+	}
+	if played.Song.Duration > 0 && position >= played.Song.Duration {
+		return fmt.Sprintf("🤷🏽 **%s** dura %s", played.Song.GetHumanName(), utils.FmtDuration(played.Song.Duration))
+	}
+	if _, err := player.SeekTo(position); errors.Is(err, bot.ErrNotPlaying) {
+		return "🤷🏽 No hay ninguna canción sonando"
+	} else if err != nil {
+		logger.Error("falló al mover la canción", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al mover la canción")
+	}
+	icon := "⏩"
+	if position < played.Position {
+		icon = "⏪"
+	}
+	return fmt.Sprintf("%s %s movió **%s** a %s", icon, member, played.Song.GetHumanName(), utils.FmtDuration(position))
+}
+
+// SeekButton maneja los botones del mensaje de reproducción que retroceden o adelantan la canción actual.
+func (handler *InteractionHandler) SeekButton(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	step, ok := voice.ParseSeekCustomID(ic.MessageComponentData().CustomID)
+	if !ok {
+		logger.Error("botón para mover la canción desconocido", zap.String("customID", ic.MessageComponentData().CustomID))
+		return
+	}
+	handler.commandUsageCounter.Inc("SeekButton")
+
+	player, message := handler.seekPlayer(s, g, ic.Member)
+	if player != nil {
+		position, err := player.Seek(step)
+		switch {
+		case errors.Is(err, bot.ErrNotPlaying):
+			message = "🤷🏽 No hay ninguna canción sonando"
+		case err != nil:
+			logger.Error("falló al mover la canción", zap.Error(err))
+			message = withErrorCode(ctx, "Ocurrió un error al mover la canción")
+		case step < 0:
+			message = fmt.Sprintf("⏪ %s retrocedió la canción a %s", getMemberName(ic.Member), utils.FmtDuration(position))
+		default:
+			message = fmt.Sprintf("⏩ %s adelantó la canción a %s", getMemberName(ic.Member), utils.FmtDuration(position))
+		}
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder al mover la canción", zap.Error(err))
+	}
+}
+
+// ChapterButton maneja los botones del mensaje de reproducción que pasan al capítulo anterior o al siguiente de la
+// canción actual.
+func (handler *InteractionHandler) ChapterButton(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	forward := ic.MessageComponentData().CustomID == voice.NextChapterCustomID
+	handler.commandUsageCounter.Inc("ChapterButton")
+
+	player, message := handler.seekPlayer(s, g, ic.Member)
+	if player != nil {
+		message = handler.seekChapter(ctx, logger, player, getMemberName(ic.Member), forward)
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder al cambiar de capítulo", zap.Error(err))
+	}
+}
+
+// seekChapter mueve la canción actual al comienzo del capítulo siguiente, o del anterior, y devuelve el mensaje para
+// quien apretó el botón.
+func (handler *InteractionHandler) seekChapter(ctx context.Context, logger logging.Logger, player *bot.GuildPlayer, member string, forward bool) string {
+	played, err := player.GetPlayedSong()
+	if err != nil {
+		logger.Error("falló al obtener la canción actual", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al cambiar de capítulo")
+	}
+	if played == nil || !player.IsPlaying() {
+		return "🤷🏽 No hay ninguna canción sonando"
+	}
+	chapter, ok := played.Song.AdjacentChapter(played.Position, forward)
+	switch {
+	case len(played.Song.Chapters) == 0:
+		return "🤷🏽 La canción no tiene capítulos"
+	case !ok && forward:
+		return "🤷🏽 Ya es el último capítulo"
+	case !ok:
+		return "🤷🏽 Ya es el primer capítulo"
+	}
+	if _, err := player.SeekTo(chapter.Start); errors.Is(err, bot.ErrNotPlaying) {
+		return "🤷🏽 No hay ninguna canción sonando"
+	} else if err != nil {
+		logger.Error("falló al cambiar de capítulo", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al cambiar de capítulo")
+	}
+	icon := "⏮️"
+	if forward {
+		icon = "⏭️"
+	}
+	return fmt.Sprintf("%s %s pasó al capítulo **%s** (%s)", icon, member, chapter.Title, utils.FmtDuration(chapter.Start))
+}
+
+// seekPlayer devuelve el reproductor del canal de voz del miembro si puede mover la canción, o el mensaje con el
+// motivo por el que no puede.
+func (handler *InteractionHandler) seekPlayer(s *discordgo.Session, g *discordgo.Guild, member *discordgo.Member) (*bot.GuildPlayer, string) {
+	if _, running := handler.triviaGames.Load(g.ID); running {
+		return nil, "🎲 No se puede mover la canción durante la trivia"
+	}
+	if getUsersVoiceState(g, member.User) == nil {
+		return nil, ErrorMessageNotInVoiceChannel
+	}
+	return handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, member), ""
+}
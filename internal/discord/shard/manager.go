@@ -0,0 +1,137 @@
+package shard
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+// identifyInterval es el tiempo que Discord exige entre identificaciones de un mismo bucket de shards.
+const identifyInterval = 5 * time.Second
+
+// Manager administra una sesión del gateway de Discord por shard. Discord limita cada sesión a 2500
+// servidores, así que a partir de ahí el bot tiene que repartir los servidores entre varias sesiones.
+// Cada sesión recibe solo los eventos (GuildCreate, interacciones, estados de voz) de sus servidores,
+// por lo que los manejadores registrados reciben siempre la sesión del shard que corresponde.
+type Manager struct {
+	sessions       []*discordgo.Session
+	maxConcurrency int
+	logger         logging.Logger
+}
+
+// NewManager crea las sesiones de todos los shards sin conectarlas. Si count es 0, la cantidad de shards
+// se obtiene de la recomendada por Discord para el bot.
+func NewManager(token string, count int, logger logging.Logger) (*Manager, error) {
+	first, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear la sesión de Discord: %w", err)
+	}
+
+	maxConcurrency := 1
+	if count <= 0 {
+		gateway, err := first.GatewayBot()
+		if err != nil {
+			return nil, fmt.Errorf("error al obtener la cantidad de shards recomendada: %w", err)
+		}
+		count = gateway.Shards
+		if gateway.SessionStartLimit.MaxConcurrency > 0 {
+			maxConcurrency = gateway.SessionStartLimit.MaxConcurrency
+		}
+	}
+	if count <= 0 {
+		count = 1
+	}
+
+	sessions := []*discordgo.Session{first}
+	for i := 1; i < count; i++ {
+		session, err := discordgo.New("Bot " + token)
+		if err != nil {
+			return nil, fmt.Errorf("error al crear la sesión del shard %d: %w", i, err)
+		}
+		sessions = append(sessions, session)
+	}
+	for i, session := range sessions {
+		session.ShardID = i
+		session.ShardCount = count
+	}
+
+	logger.Info("Shards configurados", zap.Int("shardCount", count), zap.Int("maxConcurrency", maxConcurrency))
+	return &Manager{sessions: sessions, maxConcurrency: maxConcurrency, logger: logger}, nil
+}
+
+// WithIntents establece los intents con los que se identifica cada shard.
+func (m *Manager) WithIntents(intents discordgo.Intent) *Manager {
+	for _, session := range m.sessions {
+		session.Identify.Intents = intents
+	}
+	return m
+}
+
+// AddHandler registra el manejador de eventos en las sesiones de todos los shards.
+func (m *Manager) AddHandler(handler interface{}) {
+	for _, session := range m.sessions {
+		session.AddHandler(handler)
+	}
+}
+
+// Open conecta todos los shards al gateway. Discord solo permite identificar maxConcurrency shards
+// cada 5 segundos, así que se conectan por tandas.
+func (m *Manager) Open() error {
+	for i, session := range m.sessions {
+		if i > 0 && i%m.maxConcurrency == 0 {
+			time.Sleep(identifyInterval)
+		}
+		if err := session.Open(); err != nil {
+			return fmt.Errorf("error al conectar el shard %d: %w", session.ShardID, err)
+		}
+		m.logger.Info("Shard conectado", zap.Int("shardID", session.ShardID))
+	}
+	return nil
+}
+
+// Close desconecta todos los shards.
+func (m *Manager) Close() error {
+	var errs []error
+	for _, session := range m.sessions {
+		if err := session.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("shard %d: %w", session.ShardID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ShardCount devuelve la cantidad de shards.
+func (m *Manager) ShardCount() int {
+	return len(m.sessions)
+}
+
+// Sessions devuelve las sesiones de todos los shards, ordenadas por ID de shard.
+func (m *Manager) Sessions() []*discordgo.Session {
+	return m.sessions
+}
+
+// Session devuelve la sesión del primer shard. Sirve para las llamadas a la API REST, que no dependen del shard.
+func (m *Manager) Session() *discordgo.Session {
+	return m.sessions[0]
+}
+
+// SessionForGuild devuelve la sesión del shard que recibe los eventos del servidor.
+func (m *Manager) SessionForGuild(guildID string) *discordgo.Session {
+	return m.sessions[ForGuild(guildID, len(m.sessions))]
+}
+
+// ForGuild calcula el shard al que Discord asigna un servidor: (guild_id >> 22) % cantidad de shards.
+func ForGuild(guildID string, shardCount int) int {
+	if shardCount <= 1 {
+		return 0
+	}
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return int((id >> 22) % uint64(shardCount))
+}
@@ -0,0 +1,35 @@
+package shard
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestForGuild(t *testing.T) {
+	// 41771983423143937 >> 22 = 9959216934
+	assert.Equal(t, 0, ForGuild("41771983423143937", 1))
+	assert.Equal(t, 0, ForGuild("41771983423143937", 2))
+	assert.Equal(t, 6, ForGuild("41771983423143937", 16))
+	assert.Equal(t, 0, ForGuild("no-es-un-id", 4))
+}
+
+func TestNewManager_FixedShardCount(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Info", "Shards configurados", mock.Anything).Return()
+
+	manager, err := NewManager("token", 3, logger)
+	assert.NoError(t, err)
+	manager.WithIntents(discordgo.IntentsGuilds)
+
+	assert.Equal(t, 3, manager.ShardCount())
+	for i, session := range manager.Sessions() {
+		assert.Equal(t, i, session.ShardID)
+		assert.Equal(t, 3, session.ShardCount)
+		assert.Equal(t, discordgo.IntentsGuilds, session.Identify.Intents)
+	}
+	logger.AssertExpectations(t)
+	assert.Same(t, manager.Sessions()[0], manager.Session())
+	assert.Same(t, manager.Sessions()[ForGuild("41771983423143937", 3)], manager.SessionForGuild("41771983423143937"))
+}
@@ -0,0 +1,146 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"math/rand"
+	"strings"
+)
+
+const (
+	// shuffleRandom es el modo de /shuffle que mezcla las canciones completamente al azar.
+	shuffleRandom = "random"
+	// shuffleSmart es el modo de /shuffle que separa las canciones del mismo artista.
+	shuffleSmart = "smart"
+)
+
+// errNotEnoughSongs indica que la lista de reproducción tiene menos de dos canciones y no hay nada que mezclar.
+var errNotEnoughSongs = errors.New("no hay suficientes canciones para mezclar")
+
+// Shuffle mezcla la lista de reproducción del servidor sin tocar la canción que está sonando. En modo random las
+// mezcla al azar y en modo smart reparte las canciones del mismo artista para que no suenen seguidas.
+func (handler *InteractionHandler) Shuffle(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	handler.commandUsageCounter.Inc("Shuffle")
+
+	random := commandOptions(opt)["mode"].StringValue() == shuffleRandom
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	var count int
+	// La mezcla se hace sobre la lista guardada, para no perder las canciones que se agregan mientras tanto.
+	err = player.UpdateSongs(ctx, func(songs []*voice.Song) ([]*voice.Song, error) {
+		count = len(songs)
+		if count < 2 {
+			return nil, errNotEnoughSongs
+		}
+		if random {
+			return randomShuffle(songs, rand.Intn), nil
+		}
+		return smartShuffle(songs, rand.Intn), nil
+	})
+	var message string
+	switch {
+	case errors.Is(err, errNotEnoughSongs):
+		message = "🤷🏽 No hay suficientes canciones en la lista para mezclar"
+	case err != nil:
+		logger.Error("falló al mezclar la lista de reproducción", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al mezclar la lista de reproducción")
+	case random:
+		message = fmt.Sprintf("🔀 Mezclé %d canciones", count)
+	default:
+		message = fmt.Sprintf("🔀 Mezclé %d canciones separando las del mismo artista", count)
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista mezclada", zap.Error(err))
+	}
+}
+
+// randomShuffle devuelve las canciones en un orden al azar, sin modificar songs. intn devuelve un número al azar en
+// [0, n).
+func randomShuffle(songs []*voice.Song, intn func(n int) int) []*voice.Song {
+	shuffled := append([]*voice.Song(nil), songs...)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
+
+// smartShuffle mezcla las canciones de forma que las del mismo artista no queden seguidas, salvo que sean tantas
+// que no haya forma de separarlas. En cada paso elige al azar un artista distinto del anterior, con más chances para
+// los que tienen más canciones pendientes, y toma una de sus canciones al azar. intn devuelve un número al azar en
+// [0, n).
+func smartShuffle(songs []*voice.Song, intn func(n int) int) []*voice.Song {
+	groups := make(map[string][]*voice.Song)
+	var artists []string
+	for _, song := range songs {
+		artist := songArtist(song)
+		if _, ok := groups[artist]; !ok {
+			artists = append(artists, artist)
+		}
+		groups[artist] = append(groups[artist], song)
+	}
+
+	shuffled := make([]*voice.Song, 0, len(songs))
+	last := ""
+	for remaining := len(songs); remaining > 0; remaining-- {
+		artist := nextShuffleArtist(groups, artists, last, remaining, intn)
+		group := groups[artist]
+		i := intn(len(group))
+		shuffled = append(shuffled, group[i])
+		groups[artist] = append(group[:i], group[i+1:]...)
+		last = artist
+	}
+	return shuffled
+}
+
+// nextShuffleArtist elige el artista de la próxima canción entre los que tienen canciones pendientes. Si un artista
+// tiene más de la mitad de las que quedan, lo elige a él: si no, después no se podrían separar sus canciones.
+func nextShuffleArtist(groups map[string][]*voice.Song, artists []string, last string, remaining int, intn func(n int) int) string {
+	var candidates []string
+	total := 0
+	for _, artist := range artists {
+		count := len(groups[artist])
+		if count == 0 || artist == last {
+			continue
+		}
+		if 2*count > remaining {
+			return artist
+		}
+		candidates = append(candidates, artist)
+		total += count
+	}
+	if len(candidates) == 0 {
+		// Solo quedan canciones del último artista.
+		return last
+	}
+	pick := intn(total)
+	for _, artist := range candidates {
+		if pick < len(groups[artist]) {
+			return artist
+		}
+		pick -= len(groups[artist])
+	}
+	return candidates[len(candidates)-1]
+}
+
+// songArtist devuelve el artista de la canción, en minúsculas para comparar. Si no se sabe, devuelve su URL, así las
+// canciones sin artista no se agrupan entre sí.
+func songArtist(song *voice.Song) string {
+	artist := history.Artist(history.Entry{Uploader: song.Uploader, Title: song.Title})
+	if artist == "" {
+		return song.URL
+	}
+	return strings.ToLower(artist)
+}
@@ -0,0 +1,61 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestSmartShuffle(t *testing.T) {
+	var songs []*voice.Song
+	for i, artist := range []string{"Soda Stereo", "Soda Stereo", "Soda Stereo", "Charly García", "Charly García", "Spinetta", "Fito Páez"} {
+		songs = append(songs, &voice.Song{Title: artist + " - Tema", URL: string(rune('a' + i))})
+	}
+	// Las del mismo canal se agrupan aunque el título no diga el artista.
+	songs = append(songs, &voice.Song{Title: "Tema", Uploader: "Spinetta - Topic", URL: "h"})
+
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < 100; run++ {
+		shuffled := smartShuffle(songs, rnd.Intn)
+
+		assert.ElementsMatch(t, songs, shuffled)
+		for i := 1; i < len(shuffled); i++ {
+			assert.NotEqual(t, songArtist(shuffled[i-1]), songArtist(shuffled[i]), "canciones seguidas del mismo artista en %d", i)
+		}
+	}
+}
+
+func TestRandomShuffle(t *testing.T) {
+	songs := []*voice.Song{{URL: "a"}, {URL: "b"}, {URL: "c"}, {URL: "d"}}
+	original := append([]*voice.Song(nil), songs...)
+
+	shuffled := randomShuffle(songs, func(n int) int { return 0 })
+
+	assert.ElementsMatch(t, songs, shuffled)
+	assert.Equal(t, []*voice.Song{songs[1], songs[2], songs[3], songs[0]}, shuffled)
+	// No modifica la lista recibida.
+	assert.Equal(t, original, songs)
+}
+
+func TestSmartShuffle_Unavoidable(t *testing.T) {
+	songs := []*voice.Song{
+		{Title: "Soda Stereo - A", URL: "a"},
+		{Title: "Soda Stereo - B", URL: "b"},
+		{Title: "Soda Stereo - C", URL: "c"},
+		{Title: "Spinetta - D", URL: "d"},
+	}
+
+	shuffled := smartShuffle(songs, rand.New(rand.NewSource(1)).Intn)
+
+	assert.ElementsMatch(t, songs, shuffled)
+	// No se pueden separar todas: se empieza por el artista con más canciones y el otro queda en el medio.
+	assert.Equal(t, "soda stereo", songArtist(shuffled[0]))
+	assert.Equal(t, "spinetta", songArtist(shuffled[1]))
+}
+
+func TestSongArtist(t *testing.T) {
+	assert.Equal(t, "soda stereo", songArtist(&voice.Song{Title: "Tema", Uploader: "Soda Stereo - Topic"}))
+	assert.Equal(t, "soda stereo", songArtist(&voice.Song{Title: "Soda Stereo - De música ligera"}))
+	assert.Equal(t, "https://youtu.be/abc", songArtist(&voice.Song{Title: "Tema", URL: "https://youtu.be/abc"}))
+}
@@ -1,19 +1,120 @@
 package discord
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/plugin"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/Tomas-vilte/GoMusicBot/internal/trivia"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+)
 
 // SlashCommandRouter enruta los comandos de barra oblicua en Discord.
 type SlashCommandRouter struct {
-	commandPrefix            string
-	playHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	stopHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	listHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	skipHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	removeHandler            func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	playingNowHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
-	addSongOrPlaylistHandler func(*discordgo.Session, *discordgo.InteractionCreate)
+	commandPrefix             string
+	namespace                 string // namespace es opcional; con él cada subcomando se registra como un comando propio.
+	playHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playFromHandler           func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	stopHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	listHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	skipHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	seekCommandHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	replayHandler             func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	jumpHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	loopHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	pauseHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	resumeHandler             func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	removeHandler             func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	moveHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	undoHandler               func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	shuffleHandler            func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	dedupeHandler             func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	partyShuffleHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	radioHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	moveBotHandler            func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playingNowHandler         func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	auditHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	debugHandler              func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueExportHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueImportHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueCreateHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueSwitchHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueListHandler          func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueDeleteHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	queueTransferHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistSaveHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistLoadHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistListHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistDeleteHandler     func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistShareHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistImportHandler     func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistBrowseHandler     func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	playlistPrefetchHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsDuplicatesHandler func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsExplicitHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsAutoJoinHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsTimezoneHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsVoiceHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsAntiSpamHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsMaxVolumeHandler  func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsVoteSkipHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	settingsDJRoleHandler     func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	blocklistAddHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	blocklistRemoveHandler    func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	blocklistListHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	triviaStartHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	triviaStopHandler         func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	myStatsHandler            func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	historyHandler            func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	recommendHandler          func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	trendingHandler           func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	identifyHandler           func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	scheduleAddHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	scheduleListHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	scheduleRemoveHandler     func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	spotifyLinkHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	spotifyUnlinkHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	spotifyListHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	youtubeSubscribeHandler   func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	youtubeUnsubscribeHandler func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	youtubeListHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	partyScheduleHandler      func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	alarmSetHandler           func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	alarmListHandler          func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	alarmCancelHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	aliasAddHandler           func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	aliasRemoveHandler        func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	aliasListHandler          func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	addSongOrPlaylistHandler  func(*discordgo.Session, *discordgo.InteractionCreate)
+	duplicateSongHandler      func(*discordgo.Session, *discordgo.InteractionCreate)
+	seekHandler               func(*discordgo.Session, *discordgo.InteractionCreate)
+	chapterHandler            func(*discordgo.Session, *discordgo.InteractionCreate)
+	identifyQueueHandler      func(*discordgo.Session, *discordgo.InteractionCreate)
+	trendingQueueHandler      func(*discordgo.Session, *discordgo.InteractionCreate)
+	queuePageHandler          func(*discordgo.Session, *discordgo.InteractionCreate)
+	historyPlayHandler        func(*discordgo.Session, *discordgo.InteractionCreate)
+	retrySongHandler          func(*discordgo.Session, *discordgo.InteractionCreate)
+	botBanAddHandler          func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	botBanRemoveHandler       func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	botBanListHandler         func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)
+	// pluginCommands y pluginComponents son los subcomandos y componentes que registran los plugins.
+	pluginCommands   []plugin.Command
+	pluginComponents map[string]plugin.ComponentHandler
+	// aliases es opcional; devuelve los alias de comandos de cada servidor.
+	aliases func(guildID string) map[string]string
 }
 
+var (
+	// ErrAliasConflict indica que el nombre del alias es el de un comando o subcomando existente.
+	ErrAliasConflict = errors.New("el alias coincide con un comando existente")
+	// ErrUnknownCommand indica que el subcomando al que apunta el alias no existe.
+	ErrUnknownCommand = errors.New("el comando no existe")
+)
+
 // NewSlashCommandRouter crea una nueva instancia de SlashCommandRouter con el prefijo de comando especificado.
 func NewSlashCommandRouter(commandPrefix string) *SlashCommandRouter {
 	return &SlashCommandRouter{
@@ -27,6 +128,12 @@ func (ch *SlashCommandRouter) PlayHandler(h func(*discordgo.Session, *discordgo.
 	return ch
 }
 
+// PlayFromHandler establece el manejador para el comando "playfrom".
+func (ch *SlashCommandRouter) PlayFromHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playFromHandler = h
+	return ch
+}
+
 // StopHandler establece el manejador para el comando "stop".
 func (ch *SlashCommandRouter) StopHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
 	ch.stopHandler = h
@@ -39,6 +146,42 @@ func (ch *SlashCommandRouter) SkipHandler(h func(*discordgo.Session, *discordgo.
 	return ch
 }
 
+// SeekCommandHandler establece el manejador para el comando "seek".
+func (ch *SlashCommandRouter) SeekCommandHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.seekCommandHandler = h
+	return ch
+}
+
+// ReplayHandler establece el manejador para el comando "replay".
+func (ch *SlashCommandRouter) ReplayHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.replayHandler = h
+	return ch
+}
+
+// JumpHandler establece el manejador para el comando "jump".
+func (ch *SlashCommandRouter) JumpHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.jumpHandler = h
+	return ch
+}
+
+// LoopHandler establece el manejador para el comando "loop".
+func (ch *SlashCommandRouter) LoopHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.loopHandler = h
+	return ch
+}
+
+// PauseHandler establece el manejador para el comando "pause".
+func (ch *SlashCommandRouter) PauseHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.pauseHandler = h
+	return ch
+}
+
+// ResumeHandler establece el manejador para el comando "resume".
+func (ch *SlashCommandRouter) ResumeHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.resumeHandler = h
+	return ch
+}
+
 // ListHandler establece el manejador para el comando "list".
 func (ch *SlashCommandRouter) ListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
 	ch.listHandler = h
@@ -51,60 +194,890 @@ func (ch *SlashCommandRouter) RemoveHandler(h func(*discordgo.Session, *discordg
 	return ch
 }
 
+// MoveHandler establece el manejador para el comando "move".
+func (ch *SlashCommandRouter) MoveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.moveHandler = h
+	return ch
+}
+
+// UndoHandler establece el manejador para el comando "undo".
+func (ch *SlashCommandRouter) UndoHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.undoHandler = h
+	return ch
+}
+
+// ShuffleHandler establece el manejador para el comando "shuffle".
+func (ch *SlashCommandRouter) ShuffleHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.shuffleHandler = h
+	return ch
+}
+
+// DedupeHandler establece el manejador para el comando "dedupe".
+func (ch *SlashCommandRouter) DedupeHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.dedupeHandler = h
+	return ch
+}
+
+// PartyShuffleHandler establece el manejador para el comando "partyshuffle".
+func (ch *SlashCommandRouter) PartyShuffleHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.partyShuffleHandler = h
+	return ch
+}
+
+// RadioHandler establece el manejador para el comando "radio".
+func (ch *SlashCommandRouter) RadioHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.radioHandler = h
+	return ch
+}
+
+// MoveBotHandler establece el manejador para el comando "movebot".
+func (ch *SlashCommandRouter) MoveBotHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.moveBotHandler = h
+	return ch
+}
+
 // PlayingNowHandler establece el manejador para el comando "playing".
 func (ch *SlashCommandRouter) PlayingNowHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
 	ch.playingNowHandler = h
 	return ch
 }
 
+// AuditHandler establece el manejador para el comando "audit".
+func (ch *SlashCommandRouter) AuditHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.auditHandler = h
+	return ch
+}
+
+// DebugHandler establece el manejador para el comando "debug".
+func (ch *SlashCommandRouter) DebugHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.debugHandler = h
+	return ch
+}
+
+// QueueExportHandler establece el manejador para el comando "queue export".
+func (ch *SlashCommandRouter) QueueExportHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueExportHandler = h
+	return ch
+}
+
+// QueueImportHandler establece el manejador para el comando "queue import".
+func (ch *SlashCommandRouter) QueueImportHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueImportHandler = h
+	return ch
+}
+
+// QueueCreateHandler establece el manejador para el comando "queue create".
+func (ch *SlashCommandRouter) QueueCreateHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueCreateHandler = h
+	return ch
+}
+
+// QueueSwitchHandler establece el manejador para el comando "queue switch".
+func (ch *SlashCommandRouter) QueueSwitchHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueSwitchHandler = h
+	return ch
+}
+
+// QueueListHandler establece el manejador para el comando "queue queues".
+func (ch *SlashCommandRouter) QueueListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueListHandler = h
+	return ch
+}
+
+// QueueDeleteHandler establece el manejador para el comando "queue delete".
+func (ch *SlashCommandRouter) QueueDeleteHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueDeleteHandler = h
+	return ch
+}
+
+// QueueTransferHandler establece el manejador para el comando "queue transfer".
+func (ch *SlashCommandRouter) QueueTransferHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.queueTransferHandler = h
+	return ch
+}
+
+// PlaylistSaveHandler establece el manejador para el comando "playlist save".
+func (ch *SlashCommandRouter) PlaylistSaveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistSaveHandler = h
+	return ch
+}
+
+// PlaylistLoadHandler establece el manejador para el comando "playlist load".
+func (ch *SlashCommandRouter) PlaylistLoadHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistLoadHandler = h
+	return ch
+}
+
+// PlaylistListHandler establece el manejador para el comando "playlist list".
+func (ch *SlashCommandRouter) PlaylistListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistListHandler = h
+	return ch
+}
+
+// PlaylistDeleteHandler establece el manejador para el comando "playlist delete".
+func (ch *SlashCommandRouter) PlaylistDeleteHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistDeleteHandler = h
+	return ch
+}
+
+// PlaylistShareHandler establece el manejador para el comando "playlist share".
+func (ch *SlashCommandRouter) PlaylistShareHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistShareHandler = h
+	return ch
+}
+
+// PlaylistImportHandler establece el manejador para el comando "playlist import".
+func (ch *SlashCommandRouter) PlaylistImportHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistImportHandler = h
+	return ch
+}
+
+// PlaylistBrowseHandler establece el manejador para el comando "playlist browse".
+func (ch *SlashCommandRouter) PlaylistBrowseHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistBrowseHandler = h
+	return ch
+}
+
+// PlaylistPrefetchHandler establece el manejador para el comando "playlist prefetch".
+func (ch *SlashCommandRouter) PlaylistPrefetchHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.playlistPrefetchHandler = h
+	return ch
+}
+
+// SettingsDuplicatesHandler establece el manejador para el comando "settings duplicates".
+func (ch *SlashCommandRouter) SettingsDuplicatesHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsDuplicatesHandler = h
+	return ch
+}
+
+// SettingsExplicitHandler establece el manejador para el comando "settings explicit".
+func (ch *SlashCommandRouter) SettingsExplicitHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsExplicitHandler = h
+	return ch
+}
+
+// SettingsAutoJoinHandler establece el manejador para el comando "settings autojoin".
+func (ch *SlashCommandRouter) SettingsAutoJoinHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsAutoJoinHandler = h
+	return ch
+}
+
+// SettingsTimezoneHandler establece el manejador para el comando "settings timezone".
+func (ch *SlashCommandRouter) SettingsTimezoneHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsTimezoneHandler = h
+	return ch
+}
+
+// SettingsVoiceChannelsHandler establece el manejador para el comando "settings voicechannels".
+func (ch *SlashCommandRouter) SettingsVoiceChannelsHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsVoiceHandler = h
+	return ch
+}
+
+// SettingsAntiSpamHandler establece el manejador para el comando "settings antispam".
+func (ch *SlashCommandRouter) SettingsAntiSpamHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsAntiSpamHandler = h
+	return ch
+}
+
+// SettingsMaxVolumeHandler establece el manejador para el comando "settings maxvolume".
+func (ch *SlashCommandRouter) SettingsMaxVolumeHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsMaxVolumeHandler = h
+	return ch
+}
+
+// SettingsVoteSkipHandler establece el manejador para el comando "settings voteskip".
+func (ch *SlashCommandRouter) SettingsVoteSkipHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsVoteSkipHandler = h
+	return ch
+}
+
+// SettingsDJRoleHandler establece el manejador para el comando "settings djrole".
+func (ch *SlashCommandRouter) SettingsDJRoleHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.settingsDJRoleHandler = h
+	return ch
+}
+
+// BlocklistAddHandler establece el manejador para el comando "blocklist add".
+func (ch *SlashCommandRouter) BlocklistAddHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.blocklistAddHandler = h
+	return ch
+}
+
+// BlocklistRemoveHandler establece el manejador para el comando "blocklist remove".
+func (ch *SlashCommandRouter) BlocklistRemoveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.blocklistRemoveHandler = h
+	return ch
+}
+
+// BlocklistListHandler establece el manejador para el comando "blocklist list".
+func (ch *SlashCommandRouter) BlocklistListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.blocklistListHandler = h
+	return ch
+}
+
+// BotBanAddHandler establece el manejador para el comando "botban add".
+func (ch *SlashCommandRouter) BotBanAddHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.botBanAddHandler = h
+	return ch
+}
+
+// BotBanRemoveHandler establece el manejador para el comando "botban remove".
+func (ch *SlashCommandRouter) BotBanRemoveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.botBanRemoveHandler = h
+	return ch
+}
+
+// BotBanListHandler establece el manejador para el comando "botban list".
+func (ch *SlashCommandRouter) BotBanListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.botBanListHandler = h
+	return ch
+}
+
+// TriviaStartHandler establece el manejador para el comando "trivia start".
+func (ch *SlashCommandRouter) TriviaStartHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.triviaStartHandler = h
+	return ch
+}
+
+// TriviaStopHandler establece el manejador para el comando "trivia stop".
+func (ch *SlashCommandRouter) TriviaStopHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.triviaStopHandler = h
+	return ch
+}
+
+// HistoryHandler establece el manejador para el comando "history".
+func (ch *SlashCommandRouter) HistoryHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.historyHandler = h
+	return ch
+}
+
+// HistoryPlayHandler establece el manejador del menú de /history que vuelve a agregar una canción a la cola.
+func (ch *SlashCommandRouter) HistoryPlayHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.historyPlayHandler = h
+	return ch
+}
+
+// MyStatsHandler establece el manejador para el comando "mystats".
+func (ch *SlashCommandRouter) MyStatsHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.myStatsHandler = h
+	return ch
+}
+
+// RecommendHandler establece el manejador para el comando "recommend".
+func (ch *SlashCommandRouter) RecommendHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.recommendHandler = h
+	return ch
+}
+
+// IdentifyHandler establece el manejador para el comando "identify".
+func (ch *SlashCommandRouter) IdentifyHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.identifyHandler = h
+	return ch
+}
+
+// ScheduleAddHandler establece el manejador para el comando "schedule add".
+func (ch *SlashCommandRouter) ScheduleAddHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.scheduleAddHandler = h
+	return ch
+}
+
+// ScheduleListHandler establece el manejador para el comando "schedule list".
+func (ch *SlashCommandRouter) ScheduleListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.scheduleListHandler = h
+	return ch
+}
+
+// ScheduleRemoveHandler establece el manejador para el comando "schedule remove".
+func (ch *SlashCommandRouter) ScheduleRemoveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.scheduleRemoveHandler = h
+	return ch
+}
+
+// SpotifyLinkHandler establece el manejador para el comando "spotify link".
+func (ch *SlashCommandRouter) SpotifyLinkHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.spotifyLinkHandler = h
+	return ch
+}
+
+// SpotifyUnlinkHandler establece el manejador para el comando "spotify unlink".
+func (ch *SlashCommandRouter) SpotifyUnlinkHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.spotifyUnlinkHandler = h
+	return ch
+}
+
+// SpotifyListHandler establece el manejador para el comando "spotify list".
+func (ch *SlashCommandRouter) SpotifyListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.spotifyListHandler = h
+	return ch
+}
+
+// YouTubeSubscribeHandler establece el manejador para el comando "youtube subscribe".
+func (ch *SlashCommandRouter) YouTubeSubscribeHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.youtubeSubscribeHandler = h
+	return ch
+}
+
+// YouTubeUnsubscribeHandler establece el manejador para el comando "youtube unsubscribe".
+func (ch *SlashCommandRouter) YouTubeUnsubscribeHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.youtubeUnsubscribeHandler = h
+	return ch
+}
+
+// YouTubeListHandler establece el manejador para el comando "youtube list".
+func (ch *SlashCommandRouter) YouTubeListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.youtubeListHandler = h
+	return ch
+}
+
+// PartyScheduleHandler establece el manejador para el comando "party schedule".
+func (ch *SlashCommandRouter) PartyScheduleHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.partyScheduleHandler = h
+	return ch
+}
+
+// AlarmSetHandler establece el manejador para el comando "alarm set".
+func (ch *SlashCommandRouter) AlarmSetHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.alarmSetHandler = h
+	return ch
+}
+
+// AlarmListHandler establece el manejador para el comando "alarm list".
+func (ch *SlashCommandRouter) AlarmListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.alarmListHandler = h
+	return ch
+}
+
+// AlarmCancelHandler establece el manejador para el comando "alarm cancel".
+func (ch *SlashCommandRouter) AlarmCancelHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.alarmCancelHandler = h
+	return ch
+}
+
+// AliasAddHandler establece el manejador para el comando "alias add".
+func (ch *SlashCommandRouter) AliasAddHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.aliasAddHandler = h
+	return ch
+}
+
+// AliasRemoveHandler establece el manejador para el comando "alias remove".
+func (ch *SlashCommandRouter) AliasRemoveHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.aliasRemoveHandler = h
+	return ch
+}
+
+// AliasListHandler establece el manejador para el comando "alias list".
+func (ch *SlashCommandRouter) AliasListHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.aliasListHandler = h
+	return ch
+}
+
+// Namespace hace que cada subcomando se registre como un comando propio con el nombre "<namespace>-<subcomando>",
+// como /musica-play, en lugar de como /<prefijo> play. Sirve para tener varias instancias del bot en un servidor.
+func (ch *SlashCommandRouter) Namespace(namespace string) *SlashCommandRouter {
+	ch.namespace = namespace
+	return ch
+}
+
+// AliasResolver establece la función que devuelve los alias de comandos de cada servidor, que usa HandleAlias.
+func (ch *SlashCommandRouter) AliasResolver(aliases func(guildID string) map[string]string) *SlashCommandRouter {
+	ch.aliases = aliases
+	return ch
+}
+
 // AddSongOrPlaylistHandler establece el manejador para el comando "add_song_playlist".
 func (ch *SlashCommandRouter) AddSongOrPlaylistHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
 	ch.addSongOrPlaylistHandler = h
 	return ch
 }
 
+// DuplicateSongHandler establece el manejador de los botones de confirmación de una canción repetida.
+func (ch *SlashCommandRouter) DuplicateSongHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.duplicateSongHandler = h
+	return ch
+}
+
+// SeekHandler establece el manejador de los botones del mensaje de reproducción que mueven la canción.
+func (ch *SlashCommandRouter) SeekHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.seekHandler = h
+	return ch
+}
+
+// ChapterHandler establece el manejador de los botones del mensaje de reproducción que pasan de capítulo.
+func (ch *SlashCommandRouter) ChapterHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.chapterHandler = h
+	return ch
+}
+
+// TrendingHandler establece el manejador para el comando "trending".
+func (ch *SlashCommandRouter) TrendingHandler(h func(*discordgo.Session, *discordgo.InteractionCreate, *discordgo.ApplicationCommandInteractionDataOption)) *SlashCommandRouter {
+	ch.trendingHandler = h
+	return ch
+}
+
+// TrendingQueueHandler establece el manejador de los botones que agregan a la cola canciones del ranking de
+// /trending.
+func (ch *SlashCommandRouter) TrendingQueueHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.trendingQueueHandler = h
+	return ch
+}
+
+// QueuePageHandler establece el manejador de los botones de /list que pasan de página la lista de reproducción.
+func (ch *SlashCommandRouter) QueuePageHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.queuePageHandler = h
+	return ch
+}
+
+// IdentifyQueueHandler establece el manejador del botón que agrega a la cola la canción que reconoció /identify.
+func (ch *SlashCommandRouter) IdentifyQueueHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.identifyQueueHandler = h
+	return ch
+}
+
+// RetrySongHandler establece el manejador de los botones para reintentar o buscar una canción que no se pudo agregar.
+func (ch *SlashCommandRouter) RetrySongHandler(h func(*discordgo.Session, *discordgo.InteractionCreate)) *SlashCommandRouter {
+	ch.retrySongHandler = h
+	return ch
+}
+
+// AddCommand agrega el subcomando de un plugin. Implementa plugin.CommandRegistry; los subcomandos se agregan antes
+// de registrar los comandos en Discord.
+func (ch *SlashCommandRouter) AddCommand(cmd plugin.Command) {
+	ch.pluginCommands = append(ch.pluginCommands, cmd)
+}
+
+// AddComponent agrega el manejador de un componente de un plugin. Implementa plugin.CommandRegistry.
+func (ch *SlashCommandRouter) AddComponent(customID string, handler plugin.ComponentHandler) {
+	if ch.pluginComponents == nil {
+		ch.pluginComponents = make(map[string]plugin.ComponentHandler)
+	}
+	ch.pluginComponents[customID] = handler
+}
+
 // GetCommandHandlers devuelve los manejadores de los comandos de barra oblicua.
 func (ch *SlashCommandRouter) GetCommandHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return map[string]func(*discordgo.Session, *discordgo.InteractionCreate){
-		ch.commandPrefix: func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
-			options := ic.ApplicationCommandData().Options
-			option := options[0]
-
-			switch option.Name {
-			case "play":
-				ch.playHandler(s, ic, option)
-			case "stop":
-				ch.stopHandler(s, ic, option)
-			case "list":
-				ch.listHandler(s, ic, option)
-			case "skip":
-				ch.skipHandler(s, ic, option)
-			case "remove":
-				ch.removeHandler(s, ic, option)
-			case "playing":
-				ch.playingNowHandler(s, ic, option)
+	if ch.namespace == "" {
+		// Los subcomandos se llaman distinto en todos los comandos, así que cada comando despacha el subcomando elegido.
+		handlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate), len(commandSections))
+		for _, section := range commandSections {
+			handlers[section.commandName(ch.commandPrefix)] = func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+				ch.dispatch(s, ic, ic.ApplicationCommandData().Options[0])
 			}
-		},
+		}
+		return handlers
 	}
+	// Con namespace, las opciones del comando son las del subcomando, o el subcomando elegido si es un grupo.
+	subcommands := ch.groupedCommands()[0].Options
+	handlers := make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate), len(subcommands))
+	for _, sub := range subcommands {
+		name, kind := sub.Name, sub.Type
+		handlers[ch.namespace+"-"+name] = func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+			ch.dispatch(s, ic, &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: kind, Options: ic.ApplicationCommandData().Options})
+		}
+	}
+	return handlers
 }
 
-// GetComponentHandlers devuelve los manejadores de los componentes.
-func (ch *SlashCommandRouter) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
-	return map[string]func(*discordgo.Session, *discordgo.InteractionCreate){
-		"add_song_playlist": ch.addSongOrPlaylistHandler,
+// HandleAlias ejecuta el subcomando al que apunta el alias del servidor. Los alias se registran en Discord como
+// comandos del servidor con las mismas opciones que el subcomando, así que llegan como un comando aparte.
+func (ch *SlashCommandRouter) HandleAlias(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	if ch.aliases == nil || ic.GuildID == "" {
+		return
+	}
+	data := ic.ApplicationCommandData()
+	if target, ok := ch.aliases(ic.GuildID)[data.Name]; ok {
+		ch.dispatch(s, ic, aliasOption(target, data.Options))
 	}
 }
 
-// GetSlashCommands devuelve los comandos de barra oblicua.
-func (ch *SlashCommandRouter) GetSlashCommands() []*discordgo.ApplicationCommand {
-	return []*discordgo.ApplicationCommand{
-		{
-			Name:        ch.commandPrefix,
-			Description: "Comando de butakero",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Name:        "play",
+// AliasCommand arma el comando de Discord del alias, con las opciones del subcomando al que apunta, y devuelve
+// también el subcomando normalizado (por ejemplo, "playlist save" para "/seso playlist save"). Devuelve
+// ErrAliasConflict si el alias se llama como un comando o subcomando existente y ErrUnknownCommand si el subcomando
+// no existe.
+func (ch *SlashCommandRouter) AliasCommand(alias, target string) (*discordgo.ApplicationCommand, string, error) {
+	subcommands := ch.groupedCommands()[0].Options
+	if isSectionCommand(ch.commandPrefix, alias) || findSubcommand(subcommands, alias) != nil {
+		return nil, "", ErrAliasConflict
+	}
+	for _, cmd := range ch.GetSlashCommands() {
+		if cmd.Name == alias {
+			return nil, "", ErrAliasConflict
+		}
+	}
+
+	names := strings.Fields(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(target), "/")))
+	if len(names) > 0 && isSectionCommand(ch.commandPrefix, names[0]) {
+		names = names[1:]
+	}
+	var definition *discordgo.ApplicationCommandOption
+	if len(names) == 1 || len(names) == 2 {
+		definition = findSubcommand(subcommands, names[0])
+	}
+	if definition != nil && len(names) == 2 {
+		definition = findSubcommand(definition.Options, names[1])
+	}
+	if definition == nil || definition.Type != discordgo.ApplicationCommandOptionSubCommand {
+		return nil, "", ErrUnknownCommand
+	}
+	target = strings.Join(names, " ")
+	return &discordgo.ApplicationCommand{
+		Name:        alias,
+		Description: "Alias de " + CommandName(ch.commandPrefix, ch.namespace, target),
+		Options:     definition.Options,
+	}, target, nil
+}
+
+// dispatch ejecuta el manejador del subcomando elegido.
+func (ch *SlashCommandRouter) dispatch(s *discordgo.Session, ic *discordgo.InteractionCreate, option *discordgo.ApplicationCommandInteractionDataOption) {
+	switch option.Name {
+	case "play":
+		ch.playHandler(s, ic, option)
+	case "playfrom":
+		ch.playFromHandler(s, ic, option)
+	case "stop":
+		ch.stopHandler(s, ic, option)
+	case "list":
+		ch.listHandler(s, ic, option)
+	case "skip":
+		ch.skipHandler(s, ic, option)
+	case "seek":
+		ch.seekCommandHandler(s, ic, option)
+	case "replay":
+		ch.replayHandler(s, ic, option)
+	case "jump":
+		ch.jumpHandler(s, ic, option)
+	case "loop":
+		ch.loopHandler(s, ic, option)
+	case "pause":
+		ch.pauseHandler(s, ic, option)
+	case "resume":
+		ch.resumeHandler(s, ic, option)
+	case "remove":
+		ch.removeHandler(s, ic, option)
+	case "move":
+		ch.moveHandler(s, ic, option)
+	case "undo":
+		ch.undoHandler(s, ic, option)
+	case "shuffle":
+		ch.shuffleHandler(s, ic, option)
+	case "dedupe":
+		ch.dedupeHandler(s, ic, option)
+	case "partyshuffle":
+		ch.partyShuffleHandler(s, ic, option)
+	case "radio":
+		ch.radioHandler(s, ic, option)
+	case "movebot":
+		ch.moveBotHandler(s, ic, option)
+	case "playing":
+		ch.playingNowHandler(s, ic, option)
+	case "audit":
+		ch.auditHandler(s, ic, option)
+	case "debug":
+		ch.debugHandler(s, ic, option)
+	case "queue":
+		// queue es un grupo de subcomandos: el subcomando elegido viene como su única opción.
+		sub := option.Options[0]
+		switch sub.Name {
+		case "export":
+			ch.queueExportHandler(s, ic, sub)
+		case "import":
+			ch.queueImportHandler(s, ic, sub)
+		case "create":
+			ch.queueCreateHandler(s, ic, sub)
+		case "switch":
+			ch.queueSwitchHandler(s, ic, sub)
+		case "queues":
+			ch.queueListHandler(s, ic, sub)
+		case "delete":
+			ch.queueDeleteHandler(s, ic, sub)
+		case "transfer":
+			ch.queueTransferHandler(s, ic, sub)
+		}
+	case "playlist":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "save":
+			ch.playlistSaveHandler(s, ic, sub)
+		case "load":
+			ch.playlistLoadHandler(s, ic, sub)
+		case "list":
+			ch.playlistListHandler(s, ic, sub)
+		case "delete":
+			ch.playlistDeleteHandler(s, ic, sub)
+		case "share":
+			ch.playlistShareHandler(s, ic, sub)
+		case "import":
+			ch.playlistImportHandler(s, ic, sub)
+		case "browse":
+			ch.playlistBrowseHandler(s, ic, sub)
+		case "prefetch":
+			ch.playlistPrefetchHandler(s, ic, sub)
+		}
+	case "settings":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "duplicates":
+			ch.settingsDuplicatesHandler(s, ic, sub)
+		case "explicit":
+			ch.settingsExplicitHandler(s, ic, sub)
+		case "autojoin":
+			ch.settingsAutoJoinHandler(s, ic, sub)
+		case "timezone":
+			ch.settingsTimezoneHandler(s, ic, sub)
+		case "voicechannels":
+			ch.settingsVoiceHandler(s, ic, sub)
+		case "antispam":
+			ch.settingsAntiSpamHandler(s, ic, sub)
+		case "maxvolume":
+			ch.settingsMaxVolumeHandler(s, ic, sub)
+		case "voteskip":
+			ch.settingsVoteSkipHandler(s, ic, sub)
+		case "djrole":
+			ch.settingsDJRoleHandler(s, ic, sub)
+		}
+	case "blocklist":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "add":
+			ch.blocklistAddHandler(s, ic, sub)
+		case "remove":
+			ch.blocklistRemoveHandler(s, ic, sub)
+		case "list":
+			ch.blocklistListHandler(s, ic, sub)
+		}
+	case "botban":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "add":
+			ch.botBanAddHandler(s, ic, sub)
+		case "remove":
+			ch.botBanRemoveHandler(s, ic, sub)
+		case "list":
+			ch.botBanListHandler(s, ic, sub)
+		}
+	case "trivia":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "start":
+			ch.triviaStartHandler(s, ic, sub)
+		case "stop":
+			ch.triviaStopHandler(s, ic, sub)
+		}
+	case "history":
+		ch.historyHandler(s, ic, option)
+	case "mystats":
+		ch.myStatsHandler(s, ic, option)
+	case "recommend":
+		ch.recommendHandler(s, ic, option)
+	case "trending":
+		ch.trendingHandler(s, ic, option)
+	case "identify":
+		ch.identifyHandler(s, ic, option)
+	case "schedule":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "add":
+			ch.scheduleAddHandler(s, ic, sub)
+		case "list":
+			ch.scheduleListHandler(s, ic, sub)
+		case "remove":
+			ch.scheduleRemoveHandler(s, ic, sub)
+		}
+	case "spotify":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "link":
+			ch.spotifyLinkHandler(s, ic, sub)
+		case "unlink":
+			ch.spotifyUnlinkHandler(s, ic, sub)
+		case "list":
+			ch.spotifyListHandler(s, ic, sub)
+		}
+	case "youtube":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "subscribe":
+			ch.youtubeSubscribeHandler(s, ic, sub)
+		case "unsubscribe":
+			ch.youtubeUnsubscribeHandler(s, ic, sub)
+		case "list":
+			ch.youtubeListHandler(s, ic, sub)
+		}
+	case "party":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "schedule":
+			ch.partyScheduleHandler(s, ic, sub)
+		}
+	case "alarm":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "set":
+			ch.alarmSetHandler(s, ic, sub)
+		case "list":
+			ch.alarmListHandler(s, ic, sub)
+		case "cancel":
+			ch.alarmCancelHandler(s, ic, sub)
+		}
+	case "alias":
+		sub := option.Options[0]
+		switch sub.Name {
+		case "add":
+			ch.aliasAddHandler(s, ic, sub)
+		case "remove":
+			ch.aliasRemoveHandler(s, ic, sub)
+		case "list":
+			ch.aliasListHandler(s, ic, sub)
+		}
+	default:
+		for _, cmd := range ch.pluginCommands {
+			if cmd.Name == option.Name {
+				cmd.Handler(s, ic, option)
+				return
+			}
+		}
+	}
+}
+
+// GetComponentHandlers devuelve los manejadores de los componentes.
+func (ch *SlashCommandRouter) GetComponentHandlers() map[string]func(*discordgo.Session, *discordgo.InteractionCreate) {
+	handlers := map[string]func(*discordgo.Session, *discordgo.InteractionCreate){
+		"add_song_playlist":   ch.addSongOrPlaylistHandler,
+		DuplicateAddCustomID:  ch.duplicateSongHandler,
+		DuplicateSkipCustomID: ch.duplicateSongHandler,
+	}
+	for _, step := range voice.SeekSteps {
+		handlers[voice.SeekCustomID(step)] = ch.seekHandler
+	}
+	handlers[voice.PreviousChapterCustomID] = ch.chapterHandler
+	handlers[voice.NextChapterCustomID] = ch.chapterHandler
+	handlers[IdentifyQueueCustomID] = ch.identifyQueueHandler
+	for _, customID := range TrendingQueueCustomIDs() {
+		handlers[customID] = ch.trendingQueueHandler
+	}
+	handlers[QueuePreviousPageCustomID] = ch.queuePageHandler
+	handlers[QueueNextPageCustomID] = ch.queuePageHandler
+	handlers[HistoryPlayCustomID] = ch.historyPlayHandler
+	handlers[RetrySongCustomID] = ch.retrySongHandler
+	handlers[SearchSongCustomID] = ch.retrySongHandler
+	for customID, handler := range ch.pluginComponents {
+		handlers[customID] = handler
+	}
+	return handlers
+}
+
+// GetSlashCommands devuelve los comandos de barra oblicua que se registran en Discord. Sin namespace, los subcomandos
+// se reparten entre el comando del prefijo y los de sus secciones; con namespace, cada subcomando o grupo de
+// subcomandos es un comando propio.
+func (ch *SlashCommandRouter) GetSlashCommands() []*discordgo.ApplicationCommand {
+	commands := ch.groupedCommands()
+	if ch.namespace == "" {
+		return ch.sectionCommands(commands[0].Options)
+	}
+	flat := make([]*discordgo.ApplicationCommand, 0, len(commands[0].Options))
+	for _, sub := range commands[0].Options {
+		flat = append(flat, &discordgo.ApplicationCommand{
+			Name:        ch.namespace + "-" + sub.Name,
+			Description: sub.Description,
+			Options:     sub.Options,
+		})
+	}
+	return flat
+}
+
+// CommandName devuelve cómo se escribe un subcomando, como "play" o "playlist save", según el prefijo y el namespace
+// con los que se registraron los comandos: "/seso play", "/seso-admin settings djrole" o "/musica-play".
+func CommandName(prefix, namespace, subcommand string) string {
+	if namespace != "" {
+		return "/" + namespace + "-" + subcommand
+	}
+	group, _, _ := strings.Cut(subcommand, " ")
+	return "/" + sectionOf(group).commandName(prefix) + " " + subcommand
+}
+
+// maxCommandOptions es la cantidad máxima de opciones que Discord acepta en un comando o en un grupo de subcomandos.
+const maxCommandOptions = 25
+
+// commandSection es uno de los comandos en los que se reparten los subcomandos cuando no hay namespace, porque en un
+// solo comando no entran todos. Se llama como el prefijo o, si tiene sufijo, "<prefijo>-<sufijo>".
+type commandSection struct {
+	suffix      string
+	description string
+}
+
+var (
+	mainSection  = commandSection{description: "Comando de butakero"}
+	extraSection = commandSection{suffix: "extra", description: "Más comandos de butakero"}
+	adminSection = commandSection{suffix: "admin", description: "Configuración y administración de butakero"}
+
+	commandSections = []commandSection{mainSection, extraSection, adminSection}
+
+	// subcommandSections indica en qué comando va cada subcomando. Los que no están, como los de los plugins, van en
+	// extraSection.
+	subcommandSections = map[string]commandSection{
+		"play": mainSection, "playfrom": mainSection, "remove": mainSection, "move": mainSection, "undo": mainSection,
+		"shuffle": mainSection, "dedupe": mainSection, "partyshuffle": mainSection, "radio": mainSection,
+		"movebot": mainSection, "skip": mainSection, "seek": mainSection, "loop": mainSection, "replay": mainSection,
+		"jump": mainSection, "pause": mainSection, "resume": mainSection, "stop": mainSection, "list": mainSection,
+		"playing": mainSection, "queue": mainSection, "playlist": mainSection, "history": mainSection,
+		"debug": adminSection, "audit": adminSection, "settings": adminSection, "blocklist": adminSection,
+		"botban": adminSection, "alias": adminSection,
+	}
+)
+
+// commandName devuelve el nombre del comando de Discord de la sección.
+func (cs commandSection) commandName(prefix string) string {
+	if cs.suffix == "" {
+		return prefix
+	}
+	return prefix + "-" + cs.suffix
+}
+
+// sectionOf devuelve la sección en la que va el subcomando o grupo de subcomandos.
+func sectionOf(subcommand string) commandSection {
+	if section, ok := subcommandSections[subcommand]; ok {
+		return section
+	}
+	return extraSection
+}
+
+// isSectionCommand indica si name es el nombre de alguno de los comandos en los que se reparten los subcomandos.
+func isSectionCommand(prefix, name string) bool {
+	for _, section := range commandSections {
+		if section.commandName(prefix) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionCommands reparte los subcomandos en los comandos de cada sección, sin los que quedan vacíos.
+func (ch *SlashCommandRouter) sectionCommands(subcommands []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommand {
+	commands := make([]*discordgo.ApplicationCommand, 0, len(commandSections))
+	for _, section := range commandSections {
+		cmd := &discordgo.ApplicationCommand{Name: section.commandName(ch.commandPrefix), Description: section.description}
+		for _, sub := range subcommands {
+			if sectionOf(sub.Name) == section {
+				cmd.Options = append(cmd.Options, sub)
+			}
+		}
+		if len(cmd.Options) > 0 {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+// groupedCommands devuelve el comando con el prefijo y todos los subcomandos como opciones.
+func (ch *SlashCommandRouter) groupedCommands() []*discordgo.ApplicationCommand {
+	commands := []*discordgo.ApplicationCommand{
+		{
+			Name:        ch.commandPrefix,
+			Description: "Comando de butakero",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "play",
 					Description: "Agregar una canción a la lista de reproducción",
 					Options: []*discordgo.ApplicationCommandOption{
 						{
@@ -115,6 +1088,19 @@ func (ch *SlashCommandRouter) GetSlashCommands() []*discordgo.ApplicationCommand
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "playfrom",
+					Description: "Agregar la canción que otro miembro está escuchando en Spotify",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "Miembro que está escuchando Spotify",
+							Required:    true,
+						},
+					},
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "remove",
@@ -128,11 +1114,161 @@ func (ch *SlashCommandRouter) GetSlashCommands() []*discordgo.ApplicationCommand
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "move",
+					Description: "Mover una canción a otra posición de la lista de reproducción",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "from",
+							Description: "Posición actual de la canción en la lista de reproducción",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "to",
+							Description: "Posición a la que se mueve la canción",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "undo",
+					Description: "Deshacer el último cambio de la lista de reproducción",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "shuffle",
+					Description: "Mezclar la lista de reproducción",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "mode",
+							Description: "Cómo se mezcla",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Al azar", Value: shuffleRandom},
+								{Name: "Inteligente (separa las canciones del mismo artista)", Value: shuffleSmart},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "dedupe",
+					Description: "Quitar las canciones repetidas de la lista de reproducción",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "partyshuffle",
+					Description: "Llenar la cola sola con las canciones más escuchadas del servidor",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Activar o desactivar el party shuffle",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "radio",
+					Description: "Llenar la cola sola con canciones de un género",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "genre",
+							Description: "El género de la radio, o Apagar",
+							Required:    true,
+							Choices:     radioGenreChoices(),
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "movebot",
+					Description: "Mover el bot, sin cortar la canción, a otro canal de voz",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "channel",
+							Description:  "Canal de voz (por defecto, el tuyo)",
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+						},
+					},
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "skip",
 					Description: "Saltar la canción actual",
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "seek",
+					Description: "Mover la canción actual a una posición",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "position",
+							Description: "Posición, como 1:23, 1:02:03 o en segundos",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "loop",
+					Description: "Repetir la canción actual o la lista de reproducción",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "mode",
+							Description: "Qué se repite",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Nada", Value: string(bot.LoopOff)},
+								{Name: "La canción actual", Value: string(bot.LoopTrack)},
+								{Name: "La lista de reproducción", Value: string(bot.LoopQueue)},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "replay",
+					Description: "Volver a poner la canción actual desde el principio",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "jump",
+					Description: "Saltar a una canción de la lista de reproducción",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "position",
+							Description: "Posición de la canción en la lista de reproducción",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "keep",
+							Description: "Dejar en la lista las canciones anteriores en vez de descartarlas",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "pause",
+					Description: "Pausar la canción actual sin salir del canal de voz",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "resume",
+					Description: "Retomar la canción pausada desde donde quedó",
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "stop",
@@ -148,7 +1284,959 @@ func (ch *SlashCommandRouter) GetSlashCommands() []*discordgo.ApplicationCommand
 					Name:        "playing",
 					Description: "Obtener la canción que se está reproduciendo actualmente",
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "debug",
+					Description: "Ver el estado interno del reproductor (solo dueños del bot)",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "audit",
+					Description: "Ver el registro de auditoría del servidor (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "Filtrar por usuario",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "command",
+							Description: "Filtrar por comando",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "limit",
+							Description: "Cantidad máxima de entradas a mostrar",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "queue",
+					Description: "Administrar la lista de reproducción",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "export",
+							Description: "Descargar la lista de reproducción como archivo",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "format",
+									Description: "Formato del archivo (por defecto JSON)",
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "JSON", Value: ExportFormatJSON},
+										{Name: "CSV", Value: ExportFormatCSV},
+										{Name: "M3U", Value: ExportFormatM3U},
+									},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "import",
+							Description: "Agregar a la cola las canciones de un archivo .txt, .csv, .json o .m3u",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionAttachment,
+									Name:        "file",
+									Description: "Archivo con una URL o búsqueda por canción",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "create",
+							Description: "Crear una cola con nombre, como \"noche de rock\"",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la cola",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "switch",
+							Description: "Cambiar la cola activa; la actual queda guardada con sus canciones",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la cola (la principal se llama \"principal\")",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "queues",
+							Description: "Ver las colas del servidor y cuál está activa",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "delete",
+							Description: "Borrar una cola con sus canciones",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la cola",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "transfer",
+							Description: "Pasar una canción de la cola activa a otra cola",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "position",
+									Description: "Posición de la canción en la cola activa",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "to",
+									Description: "Nombre de la cola a la que pasa",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "playlist",
+					Description: "Guardar listas de reproducción y compartirlas con otros servidores",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "save",
+							Description: "Guardar la lista de reproducción actual con un nombre",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la lista",
+									Required:    true,
+								},
+								playlistVisibilityOption(false),
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "load",
+							Description: "Agregar a la cola una lista guardada en el servidor",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la lista",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver las listas guardadas en el servidor",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "delete",
+							Description: "Borrar una lista guardada (solo quien la creó o un administrador)",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la lista",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "share",
+							Description: "Cambiar quién puede importar una lista guardada y ver su código",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la lista",
+									Required:    true,
+								},
+								playlistVisibilityOption(true),
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "import",
+							Description: "Importar una lista compartida con su código",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "code",
+									Description: "Código de la lista",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre con el que se guarda en este servidor (por defecto el original)",
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "browse",
+							Description: "Ver las listas públicas de todos los servidores",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "prefetch",
+							Description: "Descargar por adelantado las canciones de una lista guardada para que suenen sin demoras",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre de la lista",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "settings",
+					Description: "Configurar el bot en este servidor (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "duplicates",
+							Description: "Qué hacer cuando se pide una canción que ya está en la cola",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "policy",
+									Description: "Política para las canciones repetidas",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Preguntar a quien la pidió", Value: string(settings.DuplicatesConfirm)},
+										{Name: "No agregarla", Value: string(settings.DuplicatesSkip)},
+										{Name: "Agregarla igual", Value: string(settings.DuplicatesAllow)},
+									},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "explicit",
+							Description: "Qué hacer con las canciones con contenido explícito",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "policy",
+									Description: "Política para las canciones explícitas",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Agregarlas sin marcar", Value: string(settings.ExplicitAllow)},
+										{Name: "Agregarlas marcadas", Value: string(settings.ExplicitTag)},
+										{Name: "No agregarlas", Value: string(settings.ExplicitReject)},
+									},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "autojoin",
+							Description: "Entrar solo a un canal de voz cuando alguien se conecta y poner una lista guardada",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal de voz (sin canal se deshabilita)",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Nombre de la lista guardada que se reproduce",
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "timezone",
+							Description: "Zona horaria de las alarmas y las reproducciones programadas",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "timezone",
+									Description: "Nombre de la zona horaria, como America/Argentina/Buenos_Aires",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "voicechannels",
+							Description: "A qué canales de voz puede entrar el bot",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "action",
+									Description: "Qué hacer con el canal",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Permitir (solo entra a los permitidos)", Value: voiceChannelsAllow},
+										{Name: "Prohibir", Value: voiceChannelsDeny},
+										{Name: "Quitar las reglas del canal", Value: voiceChannelsReset},
+										{Name: "Ver las reglas", Value: voiceChannelsList},
+									},
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal de voz",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "antispam",
+							Description: "Límites a quienes piden la misma canción muchas veces o llenan la cola",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "enabled",
+									Description: "Activar o desactivar el anti-spam",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "window",
+									Description: "Segundos en los que se cuentan los pedidos",
+									MinValue:    &minSpamSeconds,
+									MaxValue:    3600,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "repeats",
+									Description: "Veces que se puede pedir la misma canción en ese tiempo",
+									MinValue:    &minSpamCount,
+									MaxValue:    50,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "requests",
+									Description: "Canciones que se pueden pedir en total en ese tiempo",
+									MinValue:    &minSpamCount,
+									MaxValue:    100,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "cooldown",
+									Description: "Segundos que espera quien se pasa (se duplican si reincide)",
+									MinValue:    &minSpamSeconds,
+									MaxValue:    3600,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "maxvolume",
+							Description: "Volumen máximo de la reproducción, para cuidar los oídos",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "volume",
+									Description: "Porcentaje del volumen original (sin volumen se quita el tope)",
+									MinValue:    &maxVolumeMin,
+									MaxValue:    control.MaxVolume,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "voteskip",
+							Description: "Porcentaje del canal de voz que tiene que votar con /skip para saltar la canción",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "percent",
+									Description: "Porcentaje de las personas del canal de voz (sin porcentaje cualquiera salta la canción)",
+									MinValue:    &voteSkipMin,
+									MaxValue:    100,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "djrole",
+							Description: "Rol que puede detener, eliminar, quitar duplicados, borrar colas y saltar canciones sin votar",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Rol de DJ (sin rol cualquiera puede)",
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "blocklist",
+					Description: "Bloquear canciones, canales o palabras en este servidor (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "add",
+							Description: "Bloquear una canción, un canal o una palabra",
+							Options:     blocklistEntryOptions(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove",
+							Description: "Desbloquear una canción, un canal o una palabra",
+							Options:     blocklistEntryOptions(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver lo que está bloqueado en este servidor",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "botban",
+					Description: "Bloquear usuarios para que no usen el bot en este servidor (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "add",
+							Description: "Bloquear a un usuario",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionUser,
+									Name:        "user",
+									Description: "Usuario a bloquear",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "reason",
+									Description: "Motivo, que se le muestra al usuario",
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove",
+							Description: "Desbloquear a un usuario",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionUser,
+									Name:        "user",
+									Description: "Usuario a desbloquear",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver los usuarios bloqueados",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "trivia",
+					Description: "Jugar a adivinar canciones",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "start",
+							Description: "Arrancar una trivia con una lista guardada o un género",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Lista guardada de este servidor de la que salen las canciones",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "genre",
+									Description: "Género del que salen las canciones, por ejemplo rock nacional",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "rounds",
+									Description: "Cantidad de rondas (por defecto 5)",
+									MinValue:    &triviaMinRounds,
+									MaxValue:    trivia.MaxRounds,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "stop",
+							Description: "Terminar la trivia en curso",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "history",
+					Description: "Ver las últimas canciones que sonaron y volver a poner una",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "mystats",
+					Description: "Ver cuánto escuchaste, tus artistas más pedidos y tus horarios favoritos",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "recommend",
+					Description: "Recomendar canciones según lo que se escucha en el servidor",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "count",
+							Description: "Cantidad de canciones a recomendar",
+							MinValue:    &recommendMinCount,
+							MaxValue:    recommendMaxCount,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "queue",
+							Description: "Agregarlas directamente a la cola",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "trending",
+					Description: "Ver las canciones más escuchadas de un país",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "region",
+							Description: "Código del país, como AR o US",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "identify",
+					Description: "Reconocer la canción de un audio",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionAttachment,
+							Name:        "clip",
+							Description: "Un fragmento de audio o video con la canción",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "message",
+							Description: "Enlace o ID de un mensaje de este canal que tenga el audio",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "schedule",
+					Description: "Programar reproducciones de listas guardadas (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "add",
+							Description: "Programar una lista guardada en un canal de voz",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "when",
+									Description: "Cuándo: \"weekdays 09:00\", \"daily 21:30\", \"mon,fri 18:00\" o cron (\"0 9 * * 1-5\")",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Nombre de la lista guardada",
+									Required:    true,
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal de voz",
+									Required:     true,
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver las reproducciones programadas",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove",
+							Description: "Eliminar una reproducción programada",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "id",
+									Description: "ID de la programación (se ve con /schedule list)",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "spotify",
+					Description: "Sumar solas las canciones nuevas de listas de Spotify (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "link",
+							Description: "Vincular una lista de Spotify para sumar sus canciones nuevas",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Enlace de la lista de Spotify",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "saved",
+									Description: "Lista guardada a la que se suman las canciones (si no, van a la cola)",
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal en el que se anuncian las canciones nuevas (por defecto, este)",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "unlink",
+							Description: "Desvincular una lista de Spotify",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Enlace de la lista de Spotify",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver las listas de Spotify vinculadas",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "youtube",
+					Description: "Anunciar los videos nuevos de canales de YouTube (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "subscribe",
+							Description: "Suscribir el servidor a un canal de YouTube",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "channel",
+									Description: "Enlace del canal (https://www.youtube.com/channel/UC...)",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "queue",
+									Description: "Sumar los videos nuevos a la cola si el bot está en un canal de voz",
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "announce",
+									Description:  "Canal en el que se anuncian los videos nuevos (por defecto, este)",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "unsubscribe",
+							Description: "Eliminar la suscripción a un canal de YouTube",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "channel",
+									Description: "Enlace del canal",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver los canales de YouTube suscriptos",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "party",
+					Description: "Escuchas grupales con eventos del servidor (solo administradores)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "schedule",
+							Description: "Crear un evento para escuchar juntos una lista guardada",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "playlist",
+									Description: "Nombre de la lista guardada",
+									Required:    true,
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal de voz del evento",
+									Required:     true,
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "when",
+									Description: "Cuándo empieza: \"21:30\" o \"2024-12-24 22:00\", en la zona horaria del servidor",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Nombre del evento",
+									MaxLength:   100,
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "alarm",
+					Description: "Alarmas que ponen una canción a la hora pedida",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "set",
+							Description: "Poner una alarma para hoy o mañana a la hora pedida",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "time",
+									Description: "Hora en formato HH:MM, en la zona horaria del servidor",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "song",
+									Description: "Canción: nombre o URL",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "volume",
+									Description: "Volumen de la alarma",
+									MinValue:    &alarmMinVolume,
+									MaxValue:    control.MaxVolume,
+								},
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Canal de voz (por defecto en el que estás)",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver las alarmas del servidor",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "cancel",
+							Description: "Cancelar una alarma",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "id",
+									Description: "ID de la alarma (se ve con /alarm list)",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "alias",
+					Description: "Atajos del servidor para los comandos, como /p para play",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "add",
+							Description: "Crear o cambiar un alias (solo administradores)",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "alias",
+									Description: "Nombre del alias, como p o np",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "command",
+									Description: "Subcomando al que apunta, como play o playlist save",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove",
+							Description: "Eliminar un alias (solo administradores)",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "alias",
+									Description: "Nombre del alias",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "list",
+							Description: "Ver los alias del servidor",
+						},
+					},
+				},
 			},
 		},
 	}
+	for _, cmd := range ch.pluginCommands {
+		commands[0].Options = append(commands[0].Options, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			Options:     cmd.Options,
+		})
+	}
+	return commands
+}
+
+// findSubcommand busca un subcomando o un grupo de subcomandos por nombre.
+func findSubcommand(options []*discordgo.ApplicationCommandOption, name string) *discordgo.ApplicationCommandOption {
+	for _, option := range options {
+		if option.Name == name && (option.Type == discordgo.ApplicationCommandOptionSubCommand || option.Type == discordgo.ApplicationCommandOptionSubCommandGroup) {
+			return option
+		}
+	}
+	return nil
+}
+
+// aliasOption arma la opción que llegaría con el subcomando al que apunta un alias, como "play" o "playlist save",
+// a partir de las opciones con las que se usó el alias.
+func aliasOption(target string, options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	group, name, isGroup := strings.Cut(target, " ")
+	if !isGroup {
+		return &discordgo.ApplicationCommandInteractionDataOption{Name: target, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options}
+	}
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name: group,
+		Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: name, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options},
+		},
+	}
+}
+
+// playlistVisibilityOption es la opción con la visibilidad de una lista guardada.
+func playlistVisibilityOption(required bool) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "visibility",
+		Description: "Quién puede importarla con el código",
+		Required:    required,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "Privada (solo vos)", Value: string(playlists.VisibilityPrivate)},
+			{Name: "No listada (cualquiera con el código)", Value: string(playlists.VisibilityUnlisted)},
+			{Name: "Pública", Value: string(playlists.VisibilityPublic)},
+		},
+	}
+}
+
+// triviaMinRounds es la cantidad mínima de rondas de una trivia; MinValue necesita un puntero.
+var triviaMinRounds = 1.0
+
+// recommendMinCount es la cantidad mínima de canciones que recomienda /recommend; MinValue necesita un puntero.
+var recommendMinCount = 1.0
+
+// alarmMinVolume es el volumen mínimo de una alarma; MinValue necesita un puntero.
+var alarmMinVolume = 1.0
+
+// maxVolumeMin es el mínimo del volumen máximo de /settings maxvolume; MinValue necesita un puntero.
+var maxVolumeMin = 1.0
+
+// voteSkipMin es el mínimo del porcentaje de /settings voteskip; MinValue necesita un puntero.
+var voteSkipMin = 1.0
+
+// minSpamSeconds y minSpamCount son los mínimos de las opciones de /settings antispam; MinValue necesita un puntero.
+var (
+	minSpamSeconds = 1.0
+	minSpamCount   = 1.0
+)
+
+// blocklistEntryOptions son las opciones de los subcomandos que agregan o quitan una entrada de la lista de bloqueo.
+func blocklistEntryOptions() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "type",
+			Description: "Qué se bloquea",
+			Required:    true,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Canción (URL o ID del video)", Value: string(settings.BlockVideo)},
+				{Name: "Canal (URL, ID o nombre)", Value: string(settings.BlockChannel)},
+				{Name: "Palabra en el título", Value: string(settings.BlockKeyword)},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "value",
+			Description: "La canción, el canal o la palabra",
+			Required:    true,
+		},
+	}
 }
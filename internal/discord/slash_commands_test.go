@@ -0,0 +1,130 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSlashCommandRouter_Namespace(t *testing.T) {
+	var played, saved *discordgo.ApplicationCommandInteractionDataOption
+	router := NewSlashCommandRouter("seso").
+		Namespace("musica").
+		PlayHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			played = opt
+		}).
+		PlaylistSaveHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			saved = opt
+		})
+
+	names := make(map[string]*discordgo.ApplicationCommand)
+	for _, cmd := range router.GetSlashCommands() {
+		names[cmd.Name] = cmd
+	}
+	assert.NotContains(t, names, "seso")
+	require.Contains(t, names, "musica-play")
+	assert.Equal(t, "input", names["musica-play"].Options[0].Name)
+	require.Contains(t, names, "musica-playlist")
+	assert.Equal(t, discordgo.ApplicationCommandOptionSubCommand, names["musica-playlist"].Options[0].Type)
+
+	handlers := router.GetCommandHandlers()
+	require.Contains(t, handlers, "musica-play")
+	handlers["musica-play"](nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:    "musica-play",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "input", Type: discordgo.ApplicationCommandOptionString, Value: "lofi"}},
+		},
+	}})
+	require.NotNil(t, played)
+	assert.Equal(t, "lofi", commandOptions(played)["input"].StringValue())
+
+	handlers["musica-playlist"](nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:    "musica-playlist",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "save", Type: discordgo.ApplicationCommandOptionSubCommand}},
+		},
+	}})
+	require.NotNil(t, saved)
+	assert.Equal(t, "save", saved.Name)
+
+	_, _, err := router.AliasCommand("musica-play", "skip")
+	assert.ErrorIs(t, err, ErrAliasConflict)
+}
+
+func TestSlashCommandRouter_Sections(t *testing.T) {
+	var played, configured *discordgo.ApplicationCommandInteractionDataOption
+	router := NewSlashCommandRouter("seso").
+		PlayHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			played = opt
+		}).
+		SettingsDJRoleHandler(func(_ *discordgo.Session, _ *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+			configured = opt
+		})
+
+	names := make(map[string]*discordgo.ApplicationCommand)
+	for _, cmd := range router.GetSlashCommands() {
+		names[cmd.Name] = cmd
+	}
+	require.Contains(t, names, "seso")
+	require.Contains(t, names, "seso-admin")
+	require.Contains(t, names, "seso-extra")
+	assert.NotNil(t, findSubcommand(names["seso"].Options, "play"))
+	assert.NotNil(t, findSubcommand(names["seso-admin"].Options, "settings"))
+	assert.NotNil(t, findSubcommand(names["seso-extra"].Options, "trivia"))
+
+	handlers := router.GetCommandHandlers()
+	require.Contains(t, handlers, "seso-admin")
+	handlers["seso"](nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:    "seso",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "play", Type: discordgo.ApplicationCommandOptionSubCommand}},
+		},
+	}})
+	require.NotNil(t, played)
+	handlers["seso-admin"](nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "seso-admin",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+				Name:    "settings",
+				Type:    discordgo.ApplicationCommandOptionSubCommandGroup,
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "djrole", Type: discordgo.ApplicationCommandOptionSubCommand}},
+			}},
+		},
+	}})
+	require.NotNil(t, configured)
+
+	_, target, err := router.AliasCommand("cfg", "/seso-admin settings djrole")
+	require.NoError(t, err)
+	assert.Equal(t, "settings djrole", target)
+	_, _, err = router.AliasCommand("seso-extra", "skip")
+	assert.ErrorIs(t, err, ErrAliasConflict)
+}
+
+// checkOptionLimit falla si algún comando, grupo o subcomando tiene más opciones de las que acepta Discord.
+func checkOptionLimit(t *testing.T, name string, options []*discordgo.ApplicationCommandOption) {
+	t.Helper()
+	assert.LessOrEqual(t, len(options), maxCommandOptions, "%s tiene demasiadas opciones", name)
+	for _, option := range options {
+		checkOptionLimit(t, name+" "+option.Name, option.Options)
+	}
+}
+
+func TestSlashCommandRouter_OptionLimit(t *testing.T) {
+	for _, router := range []*SlashCommandRouter{NewSlashCommandRouter("seso"), NewSlashCommandRouter("seso").Namespace("musica")} {
+		for _, cmd := range router.GetSlashCommands() {
+			checkOptionLimit(t, cmd.Name, cmd.Options)
+		}
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	assert.Equal(t, "/seso playlist save", CommandName("seso", "", "playlist save"))
+	assert.Equal(t, "/seso-admin settings djrole", CommandName("seso", "", "settings djrole"))
+	assert.Equal(t, "/seso-extra trivia start", CommandName("seso", "", "trivia start"))
+	assert.Equal(t, "/musica-playlist save", CommandName("seso", "musica", "playlist save"))
+}
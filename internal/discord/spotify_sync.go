@@ -0,0 +1,316 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotifysync"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	// spotifyRequester es el nombre con el que se agregan las canciones de las listas de Spotify vinculadas.
+	spotifyRequester = "Spotify"
+	// spotifyMaxTitlesShown es la cantidad de canciones agregadas, y de no encontradas, que se nombran en el anuncio
+	// de una sincronización.
+	spotifyMaxTitlesShown = 5
+)
+
+// SpotifyPlaylists son las listas de Spotify; lo implementa spotify.Client.
+type SpotifyPlaylists interface {
+	Playlist(ctx context.Context, id string) (spotify.Playlist, error)
+	PlaylistTracks(ctx context.Context, id string) ([]spotify.Track, error)
+}
+
+// WithSpotifySync habilita los comandos /spotify para vincular listas de Spotify, cuyas canciones nuevas se suman a
+// una lista guardada o a la cola y se anuncian con announcer.
+func (handler *InteractionHandler) WithSpotifySync(spotifyPlaylists SpotifyPlaylists, store spotifysync.Store, announcer discordmessenger.ChatMessageSender) *InteractionHandler {
+	handler.spotifyPlaylists = spotifyPlaylists
+	handler.spotifyLinks = store
+	handler.spotifyAnnouncer = announcer
+	return handler
+}
+
+// SpotifyLink vincula una lista de Spotify al servidor. Desde ese momento, las canciones que se agreguen a la lista
+// se suman a la lista guardada indicada, o a la cola si no se indica ninguna. Solo disponible para administradores.
+func (handler *InteractionHandler) SpotifyLink(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SpotifyLink")
+	if !handler.spotifyAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	link := spotifysync.Link{
+		GuildID:   ic.GuildID,
+		ChannelID: ic.ChannelID,
+		CreatedBy: interactionUser(ic.Interaction).ID,
+		CreatedAt: time.Now(),
+	}
+	if option, ok := options["saved"]; ok {
+		link.Target = strings.TrimSpace(option.StringValue())
+		if link.Target != "" && !handler.savedPlaylistsEnabled(ic) {
+			return
+		}
+	}
+	if option, ok := options["channel"]; ok {
+		link.ChannelID = option.Value.(string)
+	}
+	playlistID, err := spotify.ParsePlaylistID(options["playlist"].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Pasame el enlace de una lista de Spotify, como `https://open.spotify.com/playlist/...`"); err != nil {
+			logger.Error("falló al responder con el error de la lista de Spotify", zap.Error(err))
+		}
+		return
+	}
+	link.PlaylistID = playlistID
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		message := handler.linkSpotifyPlaylist(ctx, logger, link)
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}); err != nil {
+			logger.Error("falló al responder con la lista de Spotify vinculada", zap.Error(err))
+		}
+	}()
+}
+
+// linkSpotifyPlaylist busca la lista en Spotify y la guarda marcando sus canciones actuales como ya sincronizadas,
+// para que solo se sumen las que se agreguen después. Devuelve el mensaje para el usuario.
+func (handler *InteractionHandler) linkSpotifyPlaylist(ctx context.Context, logger logging.Logger, link spotifysync.Link) string {
+	playlist, err := handler.spotifyPlaylists.Playlist(ctx, link.PlaylistID)
+	var tracks []spotify.Track
+	if err == nil {
+		tracks, err = handler.spotifyPlaylists.PlaylistTracks(ctx, link.PlaylistID)
+	}
+	if err == nil {
+		link.Name = playlist.Name
+		link.LastAddedAt = spotifysync.LatestAddedAt(tracks)
+		err = spotifysync.Add(handler.spotifyLinks, link)
+	}
+
+	switch {
+	case errors.Is(err, spotify.ErrNotFound):
+		return "🤷🏽 No encontré esa lista en Spotify. Tiene que ser pública"
+	case errors.Is(err, spotifysync.ErrTooMany):
+		return fmt.Sprintf("🙅 El servidor ya tiene %d listas de Spotify vinculadas", spotifysync.MaxLinksPerGuild)
+	case err != nil:
+		logger.Error("falló al vincular la lista de Spotify", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al vincular la lista de Spotify")
+	}
+	return fmt.Sprintf("🔗 Vinculada **%s**: las canciones que se agreguen se van a sumar a %s y se anuncian en <#%s>", link.Name, describeSpotifyTarget(link), link.ChannelID)
+}
+
+// SpotifyUnlink desvincula una lista de Spotify del servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) SpotifyUnlink(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SpotifyUnlink")
+	if !handler.spotifyAdmin(ic) {
+		return
+	}
+
+	input := commandOptions(opt)["playlist"].StringValue()
+	playlistID, err := spotify.ParsePlaylistID(input)
+	if err == nil {
+		err = handler.spotifyLinks.Delete(ic.GuildID, playlistID)
+	}
+	message := "🗑️ Lista de Spotify desvinculada"
+	switch {
+	case errors.Is(err, spotify.ErrInvalidPlaylist), errors.Is(err, spotifysync.ErrNotFound):
+		message = "🤷🏽 Esa lista de Spotify no está vinculada"
+	case err != nil:
+		logger.Error("falló al desvincular la lista de Spotify", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al desvincular la lista de Spotify")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la lista de Spotify desvinculada", zap.Error(err))
+	}
+}
+
+// SpotifyList muestra las listas de Spotify vinculadas al servidor. Solo disponible para administradores.
+func (handler *InteractionHandler) SpotifyList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SpotifyList")
+	if !handler.spotifyAdmin(ic) {
+		return
+	}
+
+	links, err := handler.spotifyLinks.List(ic.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener las listas de Spotify vinculadas", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las listas de Spotify")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateSpotifyLinksEmbed(links)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las listas de Spotify vinculadas", zap.Error(err))
+	}
+}
+
+// SyncSpotify busca en YouTube las canciones nuevas de una lista de Spotify vinculada, las suma a su lista guardada
+// o a la cola y las anuncia en el canal de la lista. Es la spotifysync.SyncFunc del syncer. En modo cluster solo la
+// sincroniza la instancia que tiene el servidor.
+func (handler *InteractionHandler) SyncSpotify(ctx context.Context, link spotifysync.Link, tracks []spotify.Track) error {
+	if handler.voiceGate != nil && !handler.voiceGate.Owns(ctx, link.GuildID) {
+		return spotifysync.ErrNotReady
+	}
+	var player *bot.GuildPlayer
+	if link.Target == "" {
+		var err error
+		if player, err = handler.playerFor(link.GuildID); err != nil {
+			return fmt.Errorf("%w: %v", spotifysync.ErrNotReady, err)
+		}
+		if voiceChannelID, err := player.GetVoiceChannel(); err != nil || voiceChannelID == "" {
+			return fmt.Errorf("%w: el bot no está en un canal de voz", spotifysync.ErrNotReady)
+		}
+	} else if handler.savedPlaylists == nil {
+		return errors.New("las listas guardadas no están habilitadas")
+	}
+
+	logger := logging.WithFields(handler.logger, zap.String("guildID", link.GuildID), zap.String("playlistID", link.PlaylistID))
+	songs := make([]*voice.Song, 0, len(tracks))
+	var missing []string
+	for _, track := range tracks {
+		song, err := handler.lookupSpotifyTrack(ctx, track)
+		if err != nil {
+			logger.Info("falló al buscar una canción de la lista de Spotify", zap.String("query", track.Query()), zap.Error(err))
+			missing = append(missing, track.Query())
+			continue
+		}
+		requester := spotifyRequester
+		song.RequestedBy = &requester
+		songs = append(songs, song)
+	}
+	if len(songs) > 0 {
+		var err error
+		if player != nil {
+			err = player.AddSong(ctx, &link.ChannelID, nil, songs...)
+			var rejected *bot.RejectedSongsError
+			if errors.As(err, &rejected) && rejected.Added > 0 {
+				songs, err = addedSongs(songs, rejected), nil
+			}
+		} else {
+			_, err = handler.savedPlaylists.Append(link.GuildID, link.CreatedBy, link.Target, playlists.FromVoiceSongs(songs))
+		}
+		if err != nil {
+			return fmt.Errorf("error al agregar las canciones de la lista de Spotify: %w", err)
+		}
+	}
+
+	if handler.spotifyAnnouncer != nil {
+		if err := handler.spotifyAnnouncer.SendMessage(link.ChannelID, spotifySyncMessage(link, songs, missing)); err != nil {
+			logger.Error("falló al anunciar las canciones de la lista de Spotify", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// lookupSpotifyTrack busca en YouTube la canción de Spotify y devuelve el primer resultado.
+func (handler *InteractionHandler) lookupSpotifyTrack(ctx context.Context, track spotify.Track) (*voice.Song, error) {
+	videoID, err := handler.songLookup.SearchYouTubeVideoID(ctx, track.Query())
+	if err != nil {
+		return nil, err
+	}
+	songs, err := handler.songLookup.LookupSongs(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(songs) == 0 {
+		return nil, errors.New("la búsqueda no devolvió canciones")
+	}
+	return songs[0], nil
+}
+
+// spotifyAdmin responde al usuario si no es administrador o si las listas de Spotify no están habilitadas.
+func (handler *InteractionHandler) spotifyAdmin(ic *discordgo.InteractionCreate) bool {
+	message := ""
+	switch {
+	case !isGuildAdmin(ic.Member):
+		message = ErrorMessageAdminOnly
+	case handler.spotifyLinks == nil:
+		message = "Las listas de Spotify no están habilitadas"
+	default:
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// describeSpotifyTarget describe a dónde van las canciones nuevas de la lista vinculada.
+func describeSpotifyTarget(link spotifysync.Link) string {
+	if link.Target == "" {
+		return "la cola"
+	}
+	return fmt.Sprintf("la lista guardada **%s**", link.Target)
+}
+
+// spotifySyncMessage genera el anuncio de las canciones que se sumaron de una lista de Spotify.
+func spotifySyncMessage(link spotifysync.Link, songs []*voice.Song, missing []string) string {
+	builder := strings.Builder{}
+	if len(songs) > 0 {
+		builder.WriteString(fmt.Sprintf("🟢 **%s** tiene %d canciones nuevas en Spotify, las sumé a %s:", link.Name, len(songs), describeSpotifyTarget(link)))
+		for i, song := range songs {
+			if i == spotifyMaxTitlesShown {
+				builder.WriteString(fmt.Sprintf("\n… y %d más", len(songs)-i))
+				break
+			}
+			builder.WriteString("\n- " + song.Title)
+		}
+	}
+	if len(missing) > 0 {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(fmt.Sprintf("🤷🏽 No encontré en YouTube %d canciones de **%s**: ", len(missing), link.Name))
+		if len(missing) > spotifyMaxTitlesShown {
+			missing = append(missing[:spotifyMaxTitlesShown:spotifyMaxTitlesShown], "…")
+		}
+		builder.WriteString(strings.Join(missing, ", "))
+	}
+	return builder.String()
+}
+
+// GenerateSpotifyLinksEmbed genera un embed con las listas de Spotify vinculadas y a dónde van sus canciones.
+func GenerateSpotifyLinksEmbed(links []spotifysync.Link) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "🟢 Listas de Spotify vinculadas"}
+	if len(links) == 0 {
+		embed.Description = "No hay listas de Spotify vinculadas en este servidor"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, link := range links {
+		builder.WriteString(fmt.Sprintf("[%s](https://open.spotify.com/playlist/%s) → %s en <#%s>", link.Name, link.PlaylistID, describeSpotifyTarget(link), link.ChannelID))
+		if !link.LastSync.IsZero() {
+			builder.WriteString(fmt.Sprintf(" · revisada <t:%d:R>", link.LastSync.Unix()))
+		}
+		builder.WriteString("\n")
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
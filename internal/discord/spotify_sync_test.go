@@ -0,0 +1,72 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotifysync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSyncSpotify_SavedPlaylist(t *testing.T) {
+	handler, _, _, _ := newPresenceTestHandler()
+	looker := new(MockSongLooker)
+	looker.On("SearchYouTubeVideoID", "Ritchie Valens - La Bamba").Return("1", nil)
+	looker.On("LookupSongs", "1").Return([]*voice.Song{{Title: "La Bamba", URL: "https://youtube.com/watch?v=1"}}, nil)
+	looker.On("SearchYouTubeVideoID", "Nadie - Inexistente").Return("", errors.New("sin resultados"))
+	announcer := new(MockChatMessageSender)
+	announcer.On("SendMessage", "texto", mock.Anything).Return(nil)
+	handler.songLookup = looker
+	handler.WithSavedPlaylists(playlists.NewService(playlists.NewInMemoryStore())).WithSpotifySync(nil, spotifysync.NewInMemoryStore(), announcer)
+
+	link := spotifysync.Link{GuildID: "1", PlaylistID: "p1", Name: "Novedades", Target: "Fiesta", ChannelID: "texto", CreatedBy: "u1"}
+	err := handler.SyncSpotify(context.Background(), link, []spotify.Track{
+		{Name: "La Bamba", Artists: []string{"Ritchie Valens"}},
+		{Name: "Inexistente", Artists: []string{"Nadie"}},
+	})
+
+	require.NoError(t, err)
+	playlist, err := handler.savedPlaylists.Get("1", "Fiesta")
+	require.NoError(t, err)
+	require.Len(t, playlist.Songs, 1)
+	assert.Equal(t, "La Bamba", playlist.Songs[0].Title)
+	assert.Equal(t, "u1", playlist.OwnerID)
+	message := announcer.Calls[0].Arguments.String(1)
+	assert.Contains(t, message, "La Bamba")
+	assert.Contains(t, message, "Nadie - Inexistente")
+}
+
+func TestSyncSpotify_QueueNotReady(t *testing.T) {
+	handler, player, _, _ := newPresenceTestHandler()
+	handler.WithSpotifySync(nil, spotifysync.NewInMemoryStore(), nil)
+	link := spotifysync.Link{GuildID: "1", PlaylistID: "p1", ChannelID: "texto"}
+
+	err := handler.SyncSpotify(context.Background(), link, []spotify.Track{{Name: "La Bamba"}})
+	assert.ErrorIs(t, err, spotifysync.ErrNotReady, "sin reproductor las canciones esperan")
+
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	err = handler.SyncSpotify(context.Background(), link, []spotify.Track{{Name: "La Bamba"}})
+	assert.ErrorIs(t, err, spotifysync.ErrNotReady, "sin canal de voz las canciones esperan")
+}
+
+func TestSpotifySyncMessage(t *testing.T) {
+	link := spotifysync.Link{Name: "Novedades"}
+	songs := make([]*voice.Song, spotifyMaxTitlesShown+2)
+	for i := range songs {
+		songs[i] = &voice.Song{Title: "Canción"}
+	}
+
+	message := spotifySyncMessage(link, songs, nil)
+
+	assert.Contains(t, message, "7 canciones nuevas")
+	assert.Contains(t, message, "la cola")
+	assert.Equal(t, spotifyMaxTitlesShown, strings.Count(message, "- Canción"))
+	assert.Contains(t, message, "… y 2 más")
+}
@@ -0,0 +1,249 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/charts"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// TrendingQueueAllCustomID es el botón del ranking de /trending que agrega todas las canciones a la cola.
+	TrendingQueueAllCustomID = "trending_queue_all"
+	// trendingQueueCustomIDPrefix es el prefijo de los botones que agregan una canción del ranking, seguido del puesto.
+	trendingQueueCustomIDPrefix = "trending_queue_"
+	// trendingMaxEntries es la cantidad máxima de canciones del ranking que se muestran, una por botón.
+	trendingMaxEntries = 10
+	// trendingButtonsPerRow es la cantidad máxima de botones por fila que acepta Discord.
+	trendingButtonsPerRow = 5
+	// trendingLookupConcurrency es la cantidad de canciones del ranking que se buscan a la vez al agregarlas todas.
+	trendingLookupConcurrency = 4
+)
+
+// trendingLine es una línea del ranking en el embed de /trending: "`1.` Artista - Título".
+var trendingLine = regexp.MustCompile("^`(\\d+)\\.` (.+)$")
+
+// WithCharts habilita /trending con el ranking de provider. region es la región que se muestra si no se indica
+// otra, y limit la cantidad de canciones.
+func (handler *InteractionHandler) WithCharts(provider charts.Provider, region string, limit int) *InteractionHandler {
+	handler.charts = provider
+	handler.chartsRegion = region
+	handler.chartsLimit = min(max(limit, 1), trendingMaxEntries)
+	return handler
+}
+
+// TrendingQueueCustomID devuelve el ID del botón que agrega a la cola la canción del puesto rank del ranking.
+func TrendingQueueCustomID(rank int) string {
+	return trendingQueueCustomIDPrefix + strconv.Itoa(rank)
+}
+
+// TrendingQueueCustomIDs devuelve los IDs de todos los botones del ranking de /trending.
+func TrendingQueueCustomIDs() []string {
+	ids := []string{TrendingQueueAllCustomID}
+	for rank := 1; rank <= trendingMaxEntries; rank++ {
+		ids = append(ids, TrendingQueueCustomID(rank))
+	}
+	return ids
+}
+
+// Trending muestra las canciones más escuchadas de una región, con botones para agregarlas a la cola.
+func (handler *InteractionHandler) Trending(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Trending")
+	if handler.charts == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "Los rankings no están habilitados"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	region := handler.chartsRegion
+	if option, ok := commandOptions(opt)["region"]; ok {
+		region = option.StringValue()
+	}
+	region, err := charts.ParseRegion(region)
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 La región tiene que ser un código de país de dos letras, como `AR` o `US`"); err != nil {
+			logger.Error("falló al responder con el error de la región", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		params := discordgo.WebhookParams{}
+		entries, err := handler.charts.Top(ctx, region, handler.chartsLimit)
+		switch {
+		case errors.Is(err, charts.ErrRegionNotFound):
+			params.Content = fmt.Sprintf("🤷🏽 No hay ranking para `%s`", strings.ToUpper(region))
+		case err != nil:
+			logger.Error("falló al obtener el ranking", zap.String("region", region), zap.Error(err))
+			params.Content = withErrorCode(ctx, "Ocurrió un error al obtener el ranking")
+		case len(entries) == 0:
+			params.Content = fmt.Sprintf("🤷🏽 El ranking de `%s` está vacío", strings.ToUpper(region))
+		default:
+			entries = entries[:min(len(entries), trendingMaxEntries)]
+			params.Embeds = []*discordgo.MessageEmbed{GenerateTrendingEmbed(entries, region)}
+			params.Components = trendingComponents(entries)
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, params); err != nil {
+			logger.Error("falló al enviar el ranking", zap.Error(err))
+		}
+	}()
+}
+
+// TrendingQueue maneja los botones del ranking de /trending: agrega a la cola del canal de voz de quien lo aprieta
+// la canción del puesto elegido, o todas.
+func (handler *InteractionHandler) TrendingQueue(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("TrendingQueue")
+	entries := trendingEntries(ic.Message)
+	if len(entries) == 0 {
+		logger.Error("el mensaje no tiene el ranking")
+		return
+	}
+	customID := ic.MessageComponentData().CustomID
+	if customID == TrendingQueueAllCustomID {
+		handler.searchAndAddWith(ctx, s, ic, ic.Message.Embeds[0].Title, handler.addTrendingEntries(entries))
+		return
+	}
+	rank, err := strconv.Atoi(strings.TrimPrefix(customID, trendingQueueCustomIDPrefix))
+	if err != nil || rank < 1 || rank > len(entries) {
+		logger.Error("botón del ranking desconocido", zap.String("customID", customID))
+		return
+	}
+	handler.searchAndAdd(ctx, s, ic, entries[rank-1].Query())
+}
+
+// addTrendingEntries devuelve la función que busca todas las canciones del ranking y las agrega a la lista de
+// reproducción, respondiendo con un mensaje de seguimiento con cuántas se agregaron.
+func (handler *InteractionHandler) addTrendingEntries(entries []charts.Entry) func(context.Context, *bot.GuildPlayer, *discordgo.Interaction, string, string) {
+	return func(ctx context.Context, player *bot.GuildPlayer, interaction *discordgo.Interaction, voiceChannelID, input string) {
+		logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", interaction.GuildID))
+		songs := handler.lookupTrendingEntries(ctx, logger, entries)
+		embed := &discordgo.MessageEmbed{Title: input}
+		if len(songs) == 0 {
+			embed.Description = withErrorCode(ctx, "😨 No encontré ninguna canción del ranking")
+		} else {
+			setRequester(songs, interaction.Member)
+			var rejected *bot.RejectedSongsError
+			switch err := player.AddSong(ctx, &interaction.ChannelID, &voiceChannelID, songs...); {
+			case errors.As(err, &rejected):
+				handler.recordAddedSongs(interaction.GuildID, interactionUser(interaction).ID, songs, rejected)
+				embed.Description = fmt.Sprintf("➕ Se añadieron %d canciones a la cola", rejected.Added)
+			case err != nil:
+				logger.Error("falló al agregar las canciones del ranking", zap.Error(err))
+				embed.Description = withErrorCode(ctx, "No se pudieron agregar las canciones a la cola")
+			default:
+				handler.recordAddedSongs(interaction.GuildID, interactionUser(interaction).ID, songs, nil)
+				embed.Description = fmt.Sprintf("➕ Se añadieron %d canciones a la cola", len(songs))
+			}
+			if missing := len(entries) - len(songs); missing > 0 {
+				embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("No encontré %d canciones en YouTube", missing)}
+			}
+		}
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, interaction, discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		}); err != nil {
+			logger.Error("falló al enviar las canciones del ranking agregadas", zap.Error(err))
+		}
+	}
+}
+
+// lookupTrendingEntries busca en YouTube las canciones del ranking, varias a la vez, y devuelve las que encontró en
+// el orden del ranking.
+func (handler *InteractionHandler) lookupTrendingEntries(ctx context.Context, logger logging.Logger, entries []charts.Entry) []*voice.Song {
+	results := make([]*voice.Song, len(entries))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < trendingLookupConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				songs, err := handler.lookupImportEntry(ctx, entries[i].Query())
+				if err != nil || len(songs) == 0 {
+					logger.Info("falló al buscar una canción del ranking", zap.String("query", entries[i].Query()), zap.Error(err))
+					continue
+				}
+				results[i] = songs[0]
+			}
+		}()
+	}
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	songs := make([]*voice.Song, 0, len(results))
+	for _, song := range results {
+		if song != nil {
+			songs = append(songs, song)
+		}
+	}
+	return songs
+}
+
+// GenerateTrendingEmbed genera un embed con las canciones del ranking de la región. trendingEntries lee las
+// canciones de vuelta para los botones.
+func GenerateTrendingEmbed(entries []charts.Entry, region string) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("`%d.` %s", entry.Rank, entry.Query()))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🔥 Lo más escuchado en %s", strings.ToUpper(region)),
+		Description: strings.Join(lines, "\n"),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Fuente: Apple Music · Tocá un número para agregar esa canción"},
+	}
+}
+
+// trendingComponents devuelve un botón por canción del ranking, de a cinco por fila, y uno para agregarlas todas.
+func trendingComponents(entries []charts.Entry) []discordgo.MessageComponent {
+	var rows []discordgo.MessageComponent
+	var buttons []discordgo.MessageComponent
+	for _, entry := range entries {
+		buttons = append(buttons, discordgo.Button{Label: strconv.Itoa(entry.Rank), Style: discordgo.SecondaryButton, CustomID: TrendingQueueCustomID(entry.Rank)})
+		if len(buttons) == trendingButtonsPerRow {
+			rows = append(rows, discordgo.ActionsRow{Components: buttons})
+			buttons = nil
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+	return append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Agregar todas", Style: discordgo.PrimaryButton, CustomID: TrendingQueueAllCustomID, Emoji: &discordgo.ComponentEmoji{Name: "➕"}},
+	}})
+}
+
+// trendingEntries lee las canciones del ranking del embed que generó GenerateTrendingEmbed.
+func trendingEntries(msg *discordgo.Message) []charts.Entry {
+	if msg == nil || len(msg.Embeds) == 0 {
+		return nil
+	}
+	var entries []charts.Entry
+	for _, line := range strings.Split(msg.Embeds[0].Description, "\n") {
+		match := trendingLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		rank, _ := strconv.Atoi(match[1])
+		entries = append(entries, charts.Entry{Rank: rank, Title: match[2]})
+	}
+	return entries
+}
@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/charts"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testTrendingEntries = []charts.Entry{
+	{Rank: 1, Title: "De música ligera", Artist: "Soda Stereo"},
+	{Rank: 2, Title: "Demoliendo hoteles", Artist: "Charly García"},
+	{Rank: 3, Title: "Sin artista"},
+}
+
+func TestTrendingEmbed_RoundTrip(t *testing.T) {
+	embed := GenerateTrendingEmbed(testTrendingEntries, "ar")
+
+	assert.Equal(t, "🔥 Lo más escuchado en AR", embed.Title)
+	entries := trendingEntries(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	require.Len(t, entries, 3)
+	assert.Equal(t, 2, entries[1].Rank)
+	assert.Equal(t, "Charly García - Demoliendo hoteles", entries[1].Query())
+	assert.Equal(t, "Sin artista", entries[2].Query())
+	assert.Empty(t, trendingEntries(&discordgo.Message{}))
+}
+
+func TestTrendingComponents(t *testing.T) {
+	entries := make([]charts.Entry, 7)
+	for i := range entries {
+		entries[i] = charts.Entry{Rank: i + 1, Title: "Tema"}
+	}
+
+	rows := trendingComponents(entries)
+
+	require.Len(t, rows, 3)
+	assert.Len(t, rows[0].(discordgo.ActionsRow).Components, trendingButtonsPerRow)
+	assert.Len(t, rows[1].(discordgo.ActionsRow).Components, 2)
+	assert.Equal(t, TrendingQueueCustomID(7), rows[1].(discordgo.ActionsRow).Components[1].(discordgo.Button).CustomID)
+	assert.Equal(t, TrendingQueueAllCustomID, rows[2].(discordgo.ActionsRow).Components[0].(discordgo.Button).CustomID)
+	assert.Contains(t, TrendingQueueCustomIDs(), TrendingQueueCustomID(trendingMaxEntries))
+}
+
+func TestLookupTrendingEntries(t *testing.T) {
+	handler, _, _, _ := newPresenceTestHandler()
+	looker := new(MockSongLooker)
+	looker.On("SearchYouTubeVideoID", "Soda Stereo - De música ligera").Return("1", nil)
+	looker.On("SearchYouTubeVideoID", "Charly García - Demoliendo hoteles").Return("", errors.New("sin resultados"))
+	looker.On("SearchYouTubeVideoID", "Sin artista").Return("3", nil)
+	looker.On("LookupSongs", "1").Return([]*voice.Song{{Title: "De música ligera"}}, nil)
+	looker.On("LookupSongs", "3").Return([]*voice.Song{{Title: "Sin artista"}}, nil)
+	handler.songLookup = looker
+
+	songs := handler.lookupTrendingEntries(context.Background(), handler.logger, testTrendingEntries)
+
+	require.Len(t, songs, 2)
+	assert.Equal(t, "De música ligera", songs[0].Title, "se respeta el orden del ranking")
+	assert.Equal(t, "Sin artista", songs[1].Title)
+}
@@ -0,0 +1,317 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/Tomas-vilte/GoMusicBot/internal/playlists"
+	"github.com/Tomas-vilte/GoMusicBot/internal/trivia"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// triviaClipLength es el tiempo que suena cada canción antes de revelar la respuesta.
+	triviaClipLength = 30 * time.Second
+	// triviaDefaultRounds es la cantidad de rondas si no se indica otra.
+	triviaDefaultRounds = 5
+	// triviaSearchResults es la cantidad de resultados entre los que se eligen las canciones de un género.
+	triviaSearchResults = 25
+	// triviaLeaderboardSize es la cantidad de jugadores que se muestran en la tabla final.
+	triviaLeaderboardSize = 10
+)
+
+// triviaSession es una partida de trivia en curso en un servidor.
+type triviaSession struct {
+	game          atomic.Pointer[trivia.Game] // game es nil mientras se buscan las canciones.
+	startedBy     string
+	textChannelID string
+	guessed       chan string // guessed recibe el nombre de quien adivinó la canción de la ronda.
+	cancel        context.CancelFunc
+}
+
+// StartTrivia arranca una partida de trivia: suenan fragmentos de canciones de una lista guardada o de un género y
+// gana un punto el primero que escribe el nombre en el chat.
+func (handler *InteractionHandler) StartTrivia(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("StartTrivia")
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	vs := getUsersVoiceState(g, ic.Member.User)
+	if vs == nil {
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, ErrorMessageNotInVoiceChannel); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if handler.respondVoiceChannelDenied(ic, vs.ChannelID) {
+		return
+	}
+
+	options := commandOptions(opt)
+	var playlistName, genre string
+	if option, ok := options["playlist"]; ok {
+		playlistName = strings.TrimSpace(option.StringValue())
+	}
+	if option, ok := options["genre"]; ok {
+		genre = strings.TrimSpace(option.StringValue())
+	}
+	rounds := triviaDefaultRounds
+	if option, ok := options["rounds"]; ok {
+		rounds = int(option.IntValue())
+	}
+	if (playlistName == "") == (genre == "") {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Elegí una lista guardada o un género, no los dos"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if playlistName != "" && !handler.savedPlaylistsEnabled(ic) {
+		return
+	}
+
+	player := handler.playerForVoiceChannel(handler.getGuildPlayer(GuildID(g.ID), s), g.ID, vs.ChannelID)
+	if player.IsPlaying() {
+		message := fmt.Sprintf("🎶 Hay música sonando. Pará la reproducción con `%s` antes de arrancar la trivia", handler.commandName("stop"))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	gameCtx, cancel := context.WithCancel(logging.ContextWithCorrelationID(handler.ctx, logging.CorrelationIDFromContext(ctx)))
+	session := &triviaSession{
+		startedBy:     interactionUser(ic.Interaction).ID,
+		textChannelID: ic.ChannelID,
+		guessed:       make(chan string, 1),
+		cancel:        cancel,
+	}
+	if _, running := handler.triviaGames.LoadOrStore(g.ID, session); running {
+		cancel()
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🎲 Ya hay una trivia en curso en este servidor"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, "🎲 Preparando la trivia..."); err != nil {
+		logger.Error("falló al responder con el inicio de la trivia", zap.Error(err))
+	}
+	go handler.runTrivia(gameCtx, discordmessenger.NewMessageSenderImpl(s, handler.logger), player, g.ID, vs.ChannelID, session, playlistName, genre, rounds)
+}
+
+// StopTrivia termina la partida en curso. Puede hacerlo quien la arrancó o un administrador.
+func (handler *InteractionHandler) StopTrivia(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	_, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("StopTrivia")
+	message := "🤷🏽 No hay ninguna trivia en curso"
+	if value, ok := handler.triviaGames.Load(ic.GuildID); ok {
+		session := value.(*triviaSession)
+		if session.startedBy == interactionUser(ic.Interaction).ID || isGuildAdmin(ic.Member) {
+			session.cancel()
+			message = "🛑 Trivia terminada"
+		} else {
+			message = "🚫 Solo quien arrancó la trivia o un administrador puede terminarla"
+		}
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el fin de la trivia", zap.Error(err))
+	}
+}
+
+// TriviaGuess recibe los mensajes del chat y, si hay una trivia en curso en el canal, los toma como respuestas.
+func (handler *InteractionHandler) TriviaGuess(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author == nil || m.Author.Bot || m.GuildID == "" {
+		return
+	}
+	value, ok := handler.triviaGames.Load(m.GuildID)
+	if !ok {
+		return
+	}
+	session := value.(*triviaSession)
+	game := session.game.Load()
+	if game == nil || session.textChannelID != m.ChannelID {
+		return
+	}
+	name := m.Author.Username
+	if m.Member != nil && m.Member.Nick != "" {
+		name = m.Member.Nick
+	}
+	if game.Guess(m.Author.ID, name, m.Content) {
+		select {
+		case session.guessed <- name:
+		default:
+		}
+	}
+}
+
+// runTrivia juega las rondas de la partida y, al terminar o si se cancela, detiene la reproducción y muestra la
+// tabla de posiciones.
+func (handler *InteractionHandler) runTrivia(ctx context.Context, messenger discordmessenger.ChatMessageSender, player *bot.GuildPlayer, guildID, voiceChannelID string, session *triviaSession, playlistName, genre string, rounds int) {
+	logger := logging.WithFields(logging.FromContext(ctx, handler.logger), zap.String("guildID", guildID))
+	defer handler.triviaGames.Delete(guildID)
+	defer session.cancel()
+	send := func(message string) {
+		if err := messenger.SendMessage(session.textChannelID, message); err != nil {
+			logger.Error("falló al enviar el mensaje de la trivia", zap.Error(err))
+		}
+	}
+
+	songs, err := handler.triviaSongs(ctx, guildID, playlistName, genre, rounds)
+	if err != nil {
+		logger.Error("falló al buscar las canciones de la trivia", zap.Error(err))
+		send(triviaSongsErrorMessage(ctx, err))
+		return
+	}
+	if len(songs) == 0 {
+		send("🫙 No encontré canciones para la trivia")
+		return
+	}
+	game := trivia.NewGame(songs, rounds)
+	session.game.Store(game)
+	defer func() {
+		// La trivia deja el reproductor como lo encontró: sin canciones y sin sonar.
+		if err := player.Stop(); err != nil {
+			logger.Error("falló al detener la reproducción de la trivia", zap.Error(err))
+		}
+		send(triviaLeaderboardMessage(game.Leaderboard()))
+	}()
+	send(fmt.Sprintf("🎲 ¡Arranca la trivia! %d rondas de %d segundos. Escribí en este canal el nombre de la canción que suena.", game.Rounds(), int(triviaClipLength.Seconds())))
+
+	round, song, ok := handler.queueTriviaRound(ctx, game, player, session.textChannelID, voiceChannelID)
+	for ok {
+		send(fmt.Sprintf("🎧 Ronda %d/%d: ¿qué canción es?", round, game.Rounds()))
+		timer := time.NewTimer(triviaClipLength)
+		var winner string
+		select {
+		case winner = <-session.guessed:
+		case <-timer.C:
+			game.CloseRound()
+			// Alguien pudo haber adivinado justo antes de cerrar la ronda.
+			select {
+			case winner = <-session.guessed:
+			default:
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+		if winner != "" {
+			send(fmt.Sprintf("✅ ¡%s la adivinó! Era **%s**", winner, song.Title))
+		} else {
+			send(fmt.Sprintf("⌛ Nadie la adivinó. Era **%s**", song.Title))
+		}
+
+		// La próxima ronda se encola antes de saltar el fragmento actual, para que el bot no salga del canal de voz.
+		round, song, ok = handler.queueTriviaRound(ctx, game, player, session.textChannelID, voiceChannelID)
+		player.SkipSong()
+	}
+}
+
+// queueTriviaRound pasa a la siguiente ronda y encola su fragmento. Saltea las rondas cuya canción no se puede
+// encolar, por ejemplo porque está bloqueada en el servidor.
+func (handler *InteractionHandler) queueTriviaRound(ctx context.Context, game *trivia.Game, player *bot.GuildPlayer, textChannelID, voiceChannelID string) (int, *voice.Song, bool) {
+	for {
+		round, song, ok := game.NextRound()
+		if !ok {
+			return 0, nil, false
+		}
+		if err := player.AddSong(ctx, &textChannelID, &voiceChannelID, trivia.Clip(song, round, triviaClipLength)); err != nil {
+			handler.logger.Info("falló al encolar la ronda de la trivia", zap.Error(err), zap.String("URL", song.URL))
+			game.CloseRound()
+			continue
+		}
+		return round, song, true
+	}
+}
+
+// triviaSongs devuelve las canciones de la lista guardada o, si no se indicó una, hasta rounds canciones del género.
+func (handler *InteractionHandler) triviaSongs(ctx context.Context, guildID, playlistName, genre string, rounds int) ([]*voice.Song, error) {
+	if playlistName != "" {
+		playlist, err := handler.savedPlaylists.Get(guildID, playlistName)
+		if err != nil {
+			return nil, err
+		}
+		return playlist.VoiceSongs("Trivia"), nil
+	}
+
+	searcher, ok := handler.songLookup.(fetcher.SongSearcher)
+	if !ok {
+		return nil, fetcher.ErrSearchNotSupported
+	}
+	videoIDs, err := searcher.SearchYouTubeVideoIDs(ctx, genre+" music", triviaSearchResults)
+	if err != nil {
+		return nil, err
+	}
+	rand.Shuffle(len(videoIDs), func(i, j int) { videoIDs[i], videoIDs[j] = videoIDs[j], videoIDs[i] })
+	songs := make([]*voice.Song, 0, rounds)
+	for _, videoID := range videoIDs {
+		if len(songs) == rounds {
+			break
+		}
+		found, err := handler.songLookup.LookupSongs(ctx, videoID)
+		if err != nil {
+			handler.logger.Info("falló al buscar una canción de la trivia", zap.Error(err), zap.String("videoID", videoID))
+			continue
+		}
+		// Los videos largos suelen ser recopilados o mixes, que no sirven para adivinar una canción.
+		if len(found) == 1 && found[0].Playable && found[0].Duration > triviaClipLength && found[0].Duration < 10*time.Minute {
+			songs = append(songs, found[0])
+		}
+	}
+	return songs, nil
+}
+
+func triviaSongsErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, playlists.ErrNotFound):
+		return "🤷🏽 No encontré esa lista guardada"
+	case errors.Is(err, fetcher.ErrSearchNotSupported):
+		return "🤷🏽 La búsqueda por género no está disponible, probá con una lista guardada"
+	case errors.Is(err, fetcher.ErrCircuitOpen):
+		return "⚠️ " + fetcher.ErrCircuitOpen.Error()
+	default:
+		return withErrorCode(ctx, "Ocurrió un error al buscar las canciones de la trivia")
+	}
+}
+
+// triviaLeaderboardMessage arma la tabla de posiciones final.
+func triviaLeaderboardMessage(leaderboard []trivia.Score) string {
+	if len(leaderboard) == 0 {
+		return "🏁 Terminó la trivia. Nadie sumó puntos esta vez."
+	}
+	medals := []string{"🥇", "🥈", "🥉"}
+	builder := strings.Builder{}
+	builder.WriteString("🏁 Terminó la trivia. Tabla de posiciones:\n")
+	for idx, score := range leaderboard {
+		if idx == triviaLeaderboardSize {
+			break
+		}
+		position := fmt.Sprintf("%d.", idx+1)
+		if idx < len(medals) {
+			position = medals[idx]
+		}
+		points := "puntos"
+		if score.Points == 1 {
+			points = "punto"
+		}
+		builder.WriteString(fmt.Sprintf("%s %s: %d %s\n", position, score.Name, score.Points, points))
+	}
+	return strings.TrimSpace(builder.String())
+}
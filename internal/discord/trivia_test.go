@@ -0,0 +1,45 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/trivia"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTriviaGuess(t *testing.T) {
+	handler := &InteractionHandler{}
+	session := &triviaSession{textChannelID: "texto", guessed: make(chan string, 1)}
+	handler.triviaGames.Store("1", session)
+	message := func(channelID, content string, bot bool) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{Message: &discordgo.Message{
+			GuildID:   "1",
+			ChannelID: channelID,
+			Content:   content,
+			Author:    &discordgo.User{ID: "usuario", Username: "ana", Bot: bot},
+			Member:    &discordgo.Member{Nick: "Anita"},
+		}}
+	}
+
+	// Mientras se buscan las canciones no hay partida y los mensajes se ignoran.
+	handler.TriviaGuess(nil, message("texto", "la bamba", false))
+	assert.Empty(t, session.guessed)
+
+	game := trivia.NewGame([]*voice.Song{{Title: "Ritchie Valens - La Bamba"}}, 1)
+	session.game.Store(game)
+	game.NextRound()
+	handler.TriviaGuess(nil, message("otro", "la bamba", false))
+	handler.TriviaGuess(nil, message("texto", "la bamba", true))
+	assert.Empty(t, session.guessed)
+
+	handler.TriviaGuess(nil, message("texto", "La Bamba!", false))
+	assert.Equal(t, "Anita", <-session.guessed)
+	assert.Equal(t, []trivia.Score{{UserID: "usuario", Name: "Anita", Points: 1}}, game.Leaderboard())
+}
+
+func TestTriviaLeaderboardMessage(t *testing.T) {
+	assert.Contains(t, triviaLeaderboardMessage(nil), "Nadie sumó puntos")
+	assert.Equal(t, "🏁 Terminó la trivia. Tabla de posiciones:\n🥇 Ana: 3 puntos\n🥈 Beto: 1 punto",
+		triviaLeaderboardMessage([]trivia.Score{{Name: "Ana", Points: 3}, {Name: "Beto", Points: 1}}))
+}
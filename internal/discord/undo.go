@@ -0,0 +1,181 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"sync"
+)
+
+const (
+	// undoHistorySize es la cantidad de cambios de la lista de reproducción que se recuerdan por servidor.
+	undoHistorySize = 10
+
+	queueActionAdd    = "add"
+	queueActionRemove = "remove"
+	queueActionClear  = "clear"
+)
+
+// queueAction es un cambio de la lista de reproducción que se puede deshacer con /undo.
+type queueAction struct {
+	kind   string
+	userID string
+	songs  []*voice.Song
+	// position es la posición, desde 1, de la canción eliminada.
+	position int
+}
+
+// queueActionLog son los últimos cambios de la lista de reproducción de un servidor, del más viejo al más nuevo.
+type queueActionLog struct {
+	mu      sync.Mutex
+	actions []queueAction
+}
+
+// recordQueueAction guarda un cambio de la lista de reproducción del servidor para poder deshacerlo.
+func (handler *InteractionHandler) recordQueueAction(guildID string, action queueAction) {
+	if len(action.songs) == 0 {
+		return
+	}
+	value, _ := handler.queueActions.LoadOrStore(guildID, &queueActionLog{})
+	log := value.(*queueActionLog)
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.actions = append(log.actions, action)
+	if len(log.actions) > undoHistorySize {
+		log.actions = log.actions[len(log.actions)-undoHistorySize:]
+	}
+}
+
+// recordAddedSongs guarda las canciones que agregó el usuario, sin las que rechazó algún filtro del reproductor.
+func (handler *InteractionHandler) recordAddedSongs(guildID, userID string, songs []*voice.Song, rejected *bot.RejectedSongsError) {
+	if rejected != nil {
+		songs = addedSongs(songs, rejected)
+	}
+	handler.recordQueueAction(guildID, queueAction{kind: queueActionAdd, userID: userID, songs: songs})
+}
+
+// clearedSongs devuelve lo que se pierde al detener el reproductor: la canción actual, para retomarla desde donde
+// iba, y la lista de reproducción.
+func clearedSongs(player *bot.GuildPlayer) []*voice.Song {
+	var songs []*voice.Song
+	if played, err := player.GetPlayedSong(); err == nil && played != nil {
+		song := played.Song
+		song.StartPosition = played.Position
+		songs = append(songs, &song)
+	}
+	if queue, err := player.GetSongs(); err == nil {
+		songs = append(songs, queue...)
+	}
+	return songs
+}
+
+// Undo deshace el último cambio de la lista de reproducción del servidor. Solo puede hacerlo quien hizo el cambio,
+// un DJ o un administrador.
+func (handler *InteractionHandler) Undo(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("Undo")
+	g, err := s.State.Guild(ic.GuildID)
+	if err != nil {
+		logger.Info("falló al obtener el servidor", zap.Error(err))
+		if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener la información del servidor")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	action, message := handler.takeQueueAction(ic)
+	if action == nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+			logger.Error("falló al responder con el error de /undo", zap.Error(err))
+		}
+		return
+	}
+
+	player := handler.memberPlayer(handler.getGuildPlayer(GuildID(g.ID), s), g, ic.Member)
+	err = player.UpdateSongs(ctx, func(songs []*voice.Song) ([]*voice.Song, error) {
+		return undoQueue(songs, *action), nil
+	})
+	if err != nil {
+		logger.Error("falló al deshacer el cambio de la lista de reproducción", zap.String("cambio", action.kind), zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al deshacer el cambio")
+	} else {
+		message = describeUndo(*action)
+	}
+	if err := handler.responseHandler.RespondWithMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con el cambio deshecho", zap.Error(err))
+	}
+}
+
+// takeQueueAction saca el último cambio de la lista de reproducción del servidor si quien hizo la interacción puede
+// deshacerlo. Si no, devuelve nil y el mensaje para responderle.
+func (handler *InteractionHandler) takeQueueAction(ic *discordgo.InteractionCreate) (*queueAction, string) {
+	value, ok := handler.queueActions.Load(ic.GuildID)
+	if !ok {
+		return nil, "🤷🏽 No hay nada para deshacer"
+	}
+	log := value.(*queueActionLog)
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if len(log.actions) == 0 {
+		return nil, "🤷🏽 No hay nada para deshacer"
+	}
+	action := log.actions[len(log.actions)-1]
+	if action.userID != interactionUser(ic.Interaction).ID && !isDJ(ic.Member, handler.guildSettings(ic.GuildID)) {
+		return nil, fmt.Sprintf("🙅 Solo <@%s>, un DJ o un administrador pueden deshacer el último cambio", action.userID)
+	}
+	log.actions = log.actions[:len(log.actions)-1]
+	return &action, ""
+}
+
+// undoQueue devuelve cómo queda la lista de reproducción queue al deshacer action: saca las canciones agregadas que
+// todavía no sonaron, o vuelve a poner las que se eliminaron.
+func undoQueue(queue []*voice.Song, action queueAction) []*voice.Song {
+	switch action.kind {
+	case queueActionAdd:
+		result := append([]*voice.Song(nil), queue...)
+		// Se sacan las últimas apariciones, por si la canción ya estaba en la lista antes de agregarla.
+		for i := len(action.songs) - 1; i >= 0; i-- {
+			id := action.songs[i].CanonicalID()
+			for j := len(result) - 1; j >= 0; j-- {
+				if result[j].CanonicalID() == id {
+					result = append(result[:j], result[j+1:]...)
+					break
+				}
+			}
+		}
+		return result
+	case queueActionRemove:
+		index := action.position - 1
+		if index > len(queue) {
+			index = len(queue)
+		}
+		if index < 0 {
+			index = 0
+		}
+		result := make([]*voice.Song, 0, len(queue)+len(action.songs))
+		result = append(result, queue[:index]...)
+		result = append(result, action.songs...)
+		return append(result, queue[index:]...)
+	case queueActionClear:
+		return append(append([]*voice.Song(nil), action.songs...), queue...)
+	default:
+		return queue
+	}
+}
+
+// describeUndo describe el cambio que se deshizo.
+func describeUndo(action queueAction) string {
+	switch action.kind {
+	case queueActionAdd:
+		if len(action.songs) == 1 {
+			return fmt.Sprintf("↩️ Saqué **%s** de la cola", action.songs[0].GetHumanName())
+		}
+		return fmt.Sprintf("↩️ Saqué de la cola las %d canciones agregadas", len(action.songs))
+	case queueActionRemove:
+		return fmt.Sprintf("↩️ Volví a poner **%s** en la posición %d", action.songs[0].GetHumanName(), action.position)
+	default:
+		return fmt.Sprintf("↩️ Volví a poner las %d canciones que se habían quitado", len(action.songs))
+	}
+}
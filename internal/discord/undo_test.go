@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func undoTestSongs(ids ...string) []*voice.Song {
+	songs := make([]*voice.Song, 0, len(ids))
+	for _, id := range ids {
+		songs = append(songs, &voice.Song{Title: id, URL: "https://www.youtube.com/watch?v=" + id})
+	}
+	return songs
+}
+
+func songTitles(songs []*voice.Song) []string {
+	titles := make([]string, 0, len(songs))
+	for _, song := range songs {
+		titles = append(titles, song.Title)
+	}
+	return titles
+}
+
+func TestUndoQueue(t *testing.T) {
+	queue := undoTestSongs("a", "b", "c", "b")
+
+	added := undoQueue(queue, queueAction{kind: queueActionAdd, songs: undoTestSongs("b", "c")})
+	assert.Equal(t, []string{"a", "b"}, songTitles(added), "saca las últimas apariciones")
+
+	removed := undoQueue(queue, queueAction{kind: queueActionRemove, songs: undoTestSongs("x"), position: 2})
+	assert.Equal(t, []string{"a", "x", "b", "c", "b"}, songTitles(removed))
+	removed = undoQueue(queue, queueAction{kind: queueActionRemove, songs: undoTestSongs("x"), position: 9})
+	assert.Equal(t, []string{"a", "b", "c", "b", "x"}, songTitles(removed), "si la lista se achicó, va al final")
+
+	cleared := undoQueue(undoTestSongs("nueva"), queueAction{kind: queueActionClear, songs: undoTestSongs("a", "b")})
+	assert.Equal(t, []string{"a", "b", "nueva"}, songTitles(cleared))
+
+	assert.Equal(t, []string{"a", "b", "c", "b"}, songTitles(queue), "no modifica la lista original")
+}
+
+func TestTakeQueueAction(t *testing.T) {
+	handler := (&InteractionHandler{}).WithSettings(settings.NewInMemoryStore())
+	interaction := func(userID string, permissions int64) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			GuildID: "1",
+			Member:  &discordgo.Member{User: &discordgo.User{ID: userID}, Permissions: permissions},
+		}}
+	}
+
+	_, message := handler.takeQueueAction(interaction("ana", 0))
+	assert.Equal(t, "🤷🏽 No hay nada para deshacer", message)
+
+	for i := 0; i < undoHistorySize+2; i++ {
+		handler.recordQueueAction("1", queueAction{kind: queueActionAdd, userID: "ana", songs: undoTestSongs("a")})
+	}
+	handler.recordQueueAction("1", queueAction{kind: queueActionRemove, userID: "ana", songs: undoTestSongs("b"), position: 1})
+	handler.recordQueueAction("1", queueAction{kind: queueActionAdd, userID: "ana"})
+
+	action, message := handler.takeQueueAction(interaction("beto", 0))
+	assert.Nil(t, action)
+	assert.Contains(t, message, "<@ana>")
+
+	action, _ = handler.takeQueueAction(interaction("ana", 0))
+	require.NotNil(t, action)
+	assert.Equal(t, queueActionRemove, action.kind, "los cambios sin canciones no se guardan")
+
+	action, _ = handler.takeQueueAction(interaction("beto", discordgo.PermissionAdministrator))
+	require.NotNil(t, action, "un administrador puede deshacer cambios de otros")
+
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.DJRole = "rol-dj" })
+	require.NoError(t, err)
+	dj := interaction("dj", 0)
+	dj.Member.Roles = []string{"rol-dj"}
+	action, _ = handler.takeQueueAction(dj)
+	require.NotNil(t, action, "un DJ puede deshacer cambios de otros")
+
+	value, _ := handler.queueActions.Load("1")
+	assert.Len(t, value.(*queueActionLog).actions, undoHistorySize-3)
+}
@@ -1,7 +1,12 @@
 package discord
 
 import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
 	"github.com/bwmarrin/discordgo"
+	"io"
+	"net/http"
 )
 
 func getMemberName(member *discordgo.Member) string {
@@ -10,3 +15,41 @@ func getMemberName(member *discordgo.Member) string {
 	}
 	return member.User.Username
 }
+
+// setRequester marca las canciones como pedidas por el miembro, con su nombre y su ID.
+func setRequester(songs []*voice.Song, member *discordgo.Member) {
+	memberName := getMemberName(member)
+	memberID := ""
+	if member.User != nil {
+		memberID = member.User.ID
+	}
+	for _, song := range songs {
+		song.RequestedBy = &memberName
+		song.RequestedByID = memberID
+	}
+}
+
+// openAttachment empieza a descargar un adjunto de Discord. Quien la llama tiene que cerrar el contenido.
+func openAttachment(ctx context.Context, attachment *discordgo.MessageAttachment) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear la descarga del adjunto: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al descargar el adjunto: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error al descargar el adjunto: status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// isGuildAdmin indica si el miembro tiene permisos para administrar el servidor.
+func isGuildAdmin(member *discordgo.Member) bool {
+	if member == nil {
+		return false
+	}
+	return member.Permissions&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) != 0
+}
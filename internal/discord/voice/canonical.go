@@ -0,0 +1,40 @@
+package voice
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CanonicalID devuelve un identificador de la canción que no depende de cómo se escribió la URL: para YouTube es el
+// ID del video (youtube.com/watch?v=, youtu.be/, /shorts/, music.youtube.com), y para el resto la URL sin esquema,
+// "www." ni fragmento. Sirve para reconocer la misma canción pedida con URLs distintas.
+func (s *Song) CanonicalID() string {
+	u, err := url.Parse(strings.TrimSpace(s.URL))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(s.URL)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	switch host {
+	case "youtu.be":
+		if id := strings.Trim(u.Path, "/"); id != "" {
+			return "youtube:" + id
+		}
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		if id := u.Query().Get("v"); id != "" {
+			return "youtube:" + id
+		}
+		if id, ok := strings.CutPrefix(u.Path, "/shorts/"); ok && id != "" {
+			return "youtube:" + strings.Trim(id, "/")
+		}
+	}
+	u.Fragment = ""
+	return host + strings.TrimSuffix(u.EscapedPath(), "/") + querySuffix(u.RawQuery)
+}
+
+func querySuffix(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	return "?" + rawQuery
+}
@@ -0,0 +1,27 @@
+package voice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSong_CanonicalID(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", want: "youtube:dQw4w9WgXcQ"},
+		{url: "https://youtube.com/watch?v=dQw4w9WgXcQ&t=42s&list=PL1", want: "youtube:dQw4w9WgXcQ"},
+		{url: "https://youtu.be/dQw4w9WgXcQ?si=abc", want: "youtube:dQw4w9WgXcQ"},
+		{url: "https://music.youtube.com/watch?v=dQw4w9WgXcQ", want: "youtube:dQw4w9WgXcQ"},
+		{url: "https://www.youtube.com/shorts/dQw4w9WgXcQ", want: "youtube:dQw4w9WgXcQ"},
+		{url: "https://soundcloud.com/artista/tema/#comentarios", want: "soundcloud.com/artista/tema"},
+		{url: "http://www.soundcloud.com/artista/tema", want: "soundcloud.com/artista/tema"},
+		{url: "no es una url", want: "no es una url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, (&Song{URL: tt.url}).CanonicalID())
+		})
+	}
+}
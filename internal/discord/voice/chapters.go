@@ -0,0 +1,114 @@
+package voice
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minChapters y minChapterLength son los requisitos de YouTube para mostrar los capítulos de un video.
+	minChapters      = 3
+	minChapterLength = 10 * time.Second
+	// chapterRestartWindow es cuánto tiene que haber avanzado el capítulo actual para que el botón de capítulo
+	// anterior vuelva a su comienzo en lugar de ir al anterior, como el botón de canción anterior de un reproductor.
+	chapterRestartWindow = 3 * time.Second
+)
+
+// chapterLine es una línea de la descripción que empieza con una marca de tiempo, como "1:02:03 - Título".
+var chapterLine = regexp.MustCompile(`^[\[(]?((?:\d{1,2}:)?\d{1,2}:\d{2})[\])]?\s*[-–—:|.]*\s*(.+)$`)
+
+// Chapter es un capítulo de una canción, que va desde Start hasta el comienzo del siguiente.
+type Chapter struct {
+	Start time.Duration
+	Title string
+}
+
+// ParseChapters busca los capítulos en la descripción de un video, con las mismas reglas que YouTube: una línea por
+// capítulo que empieza con su marca de tiempo, el primero en 0:00, al menos tres y de al menos diez segundos cada
+// uno. Si no cumplen las reglas, devuelve nil.
+func ParseChapters(description string, duration time.Duration) []Chapter {
+	var chapters []Chapter
+	for _, line := range strings.Split(description, "\n") {
+		match := chapterLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		chapters = append(chapters, Chapter{Start: parseTimestamp(match[1]), Title: strings.TrimSpace(match[2])})
+	}
+	if len(chapters) < minChapters || chapters[0].Start != 0 {
+		return nil
+	}
+	for i := 1; i < len(chapters); i++ {
+		if chapters[i].Start-chapters[i-1].Start < minChapterLength {
+			return nil
+		}
+	}
+	if duration > 0 && duration-chapters[len(chapters)-1].Start < minChapterLength {
+		return nil
+	}
+	return chapters
+}
+
+// ChapterAt devuelve el índice del capítulo que suena en position, o -1 si la canción no tiene capítulos.
+func (s *Song) ChapterAt(position time.Duration) int {
+	current := -1
+	for i, chapter := range s.Chapters {
+		if chapter.Start > position {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// AdjacentChapter devuelve el capítulo al que se pasa desde position: el siguiente, o el anterior si forward es
+// false. Hacia atrás, si el capítulo actual ya avanzó unos segundos, vuelve a su comienzo.
+func (s *Song) AdjacentChapter(position time.Duration, forward bool) (Chapter, bool) {
+	current := s.ChapterAt(position)
+	switch {
+	case current < 0:
+		return Chapter{}, false
+	case forward:
+		if current+1 >= len(s.Chapters) {
+			return Chapter{}, false
+		}
+		return s.Chapters[current+1], true
+	case position-s.Chapters[current].Start > chapterRestartWindow:
+		return s.Chapters[current], true
+	case current > 0:
+		return s.Chapters[current-1], true
+	default:
+		return Chapter{}, false
+	}
+}
+
+// ParsePosition convierte una posición escrita por un usuario, en segundos ("83"), "MM:SS" o "HH:MM:SS", en una
+// duración. Devuelve false si no tiene ninguno de esos formatos.
+func ParsePosition(input string) (time.Duration, bool) {
+	parts := strings.Split(strings.TrimSpace(input), ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	var total time.Duration
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && (len(part) != 2 || n >= 60)) {
+			return 0, false
+		}
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second, true
+}
+
+// parseTimestamp convierte una marca de tiempo "MM:SS" o "HH:MM:SS" en una duración. La expresión de chapterLine ya
+// garantiza que son números.
+func parseTimestamp(timestamp string) time.Duration {
+	var total time.Duration
+	for _, part := range strings.Split(timestamp, ":") {
+		n, _ := strconv.Atoi(part)
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second
+}
@@ -0,0 +1,78 @@
+package voice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseChapters(t *testing.T) {
+	description := `Mix de la semana
+
+0:00 Intro
+(1:30) - Primer tema
+03:05 | Segundo tema
+1:00:00 Cierre
+
+Seguinos en las redes`
+	chapters := ParseChapters(description, 70*time.Minute)
+	assert.Equal(t, []Chapter{
+		{Start: 0, Title: "Intro"},
+		{Start: 90 * time.Second, Title: "Primer tema"},
+		{Start: 185 * time.Second, Title: "Segundo tema"},
+		{Start: time.Hour, Title: "Cierre"},
+	}, chapters)
+
+	tests := map[string]string{
+		"no empieza en 0:00":    "0:05 Intro\n1:00 Tema\n2:00 Cierre",
+		"menos de tres":         "0:00 Intro\n1:00 Tema",
+		"capítulo muy corto":    "0:00 Intro\n0:05 Tema\n2:00 Cierre",
+		"último después de fin": "0:00 Intro\n1:00 Tema\n4:55 Cierre",
+		"sin marcas":            "Tema nuevo, escuchalo a las 20:00",
+	}
+	for name, description := range tests {
+		assert.Nil(t, ParseChapters(description, 5*time.Minute), name)
+	}
+}
+
+func TestSong_AdjacentChapter(t *testing.T) {
+	song := &Song{Chapters: []Chapter{{Title: "Intro"}, {Start: time.Minute, Title: "Tema"}, {Start: 2 * time.Minute, Title: "Cierre"}}}
+
+	next, ok := song.AdjacentChapter(30*time.Second, true)
+	assert.True(t, ok)
+	assert.Equal(t, "Tema", next.Title)
+
+	previous, ok := song.AdjacentChapter(61*time.Second, false)
+	assert.True(t, ok)
+	assert.Equal(t, "Intro", previous.Title, "al comienzo del capítulo va al anterior")
+
+	previous, ok = song.AdjacentChapter(90*time.Second, false)
+	assert.True(t, ok)
+	assert.Equal(t, "Tema", previous.Title, "con el capítulo avanzado vuelve a su comienzo")
+
+	_, ok = song.AdjacentChapter(150*time.Second, true)
+	assert.False(t, ok)
+	_, ok = song.AdjacentChapter(time.Second, false)
+	assert.False(t, ok)
+	_, ok = (&Song{}).AdjacentChapter(time.Second, true)
+	assert.False(t, ok)
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := map[string]time.Duration{
+		"83":      83 * time.Second,
+		"1:23":    83 * time.Second,
+		" 01:23 ": 83 * time.Second,
+		"1:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		"0":       0,
+	}
+	for input, want := range tests {
+		got, ok := ParsePosition(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "abc", "1:2", "1:60", "-5", "1:02:03:04", "1m23s"} {
+		_, ok := ParsePosition(input)
+		assert.False(t, ok, input)
+	}
+}
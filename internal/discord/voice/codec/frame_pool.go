@@ -0,0 +1,75 @@
+package codec
+
+import "sync"
+
+// defaultFrameCapacity es la capacidad inicial de los buffers de frames. Un frame Opus de 20ms suele ocupar unos
+// cientos de bytes; los más grandes agrandan el buffer, que se reutiliza con ese tamaño.
+const defaultFrameCapacity = 1024
+
+// framePool reutiliza los buffers de los frames entre canciones y entre servidores, para que el envío de audio no
+// genere basura por cada frame.
+var framePool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, defaultFrameCapacity)
+		return &buf
+	},
+}
+
+// getFrame devuelve un buffer del pool de size bytes.
+func getFrame(size int) *[]byte {
+	buf := framePool.Get().(*[]byte)
+	if cap(*buf) < size {
+		*buf = make([]byte, size)
+	}
+	*buf = (*buf)[:size]
+	return buf
+}
+
+// putFrame devuelve un buffer al pool.
+func putFrame(buf *[]byte) {
+	framePool.Put(buf)
+}
+
+// inFlightFrame es un buffer enviado a la conexión de voz y el número del último envío que lo usó.
+type inFlightFrame struct {
+	buf      *[]byte
+	lastSend int
+}
+
+// inFlightFrames lleva los buffers enviados a la conexión de voz hasta que es seguro devolverlos al pool. El que
+// recibe los frames termina de usar uno antes de recibir el siguiente, así que, con un canal de capacidad c, después
+// del envío número n ya terminó con todos los envíos hasta n-c-1.
+type inFlightFrames struct {
+	capacity int
+	sends    int
+	frames   []inFlightFrame
+}
+
+func newInFlightFrames(capacity int) *inFlightFrames {
+	return &inFlightFrames{capacity: capacity, frames: make([]inFlightFrame, 0, capacity+2)}
+}
+
+// sent registra un envío al canal.
+func (f *inFlightFrames) sent() {
+	f.sends++
+}
+
+// add registra un buffer cuyo último envío fue el más reciente y devuelve al pool los que ya no se usan. Si no se
+// envió ninguna parte del buffer, se devuelve al pool directamente.
+func (f *inFlightFrames) add(buf *[]byte, sentAny bool) {
+	if sentAny {
+		f.frames = append(f.frames, inFlightFrame{buf: buf, lastSend: f.sends})
+	} else {
+		putFrame(buf)
+	}
+	released := 0
+	for released < len(f.frames) && f.frames[released].lastSend < f.sends-f.capacity {
+		putFrame(f.frames[released].buf)
+		released++
+	}
+	if released > 0 {
+		n := copy(f.frames, f.frames[released:])
+		clear(f.frames[n:])
+		f.frames = f.frames[:n]
+	}
+}
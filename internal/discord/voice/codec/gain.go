@@ -0,0 +1,185 @@
+package codec
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// pcmFrameSize es el tamaño de un frame de 20ms de audio PCM s16le estéreo a 48kHz.
+	pcmFrameSize = opusFrameSamples * 2 * 2
+	// gainLatencyFrames son los frames que la etapa de ganancia puede adelantarse al envío, además de los que el
+	// DCAStreamer lee por adelantado. Cubren lo que demora ffmpeg en volver a codificarlos.
+	gainLatencyFrames = 10
+	// gainPacingTimeout es cuánto espera la etapa de ganancia a que se envíe un frame antes de dejar de acompasarse
+	// al envío, para no trabar la reproducción si la codificación retiene más frames de lo esperado.
+	gainPacingTimeout = time.Second
+)
+
+// Gain es la ganancia con la que suena el audio que envía el bot: el volumen y los fundidos al cortar o retomar una
+// canción. La cambian el reproductor y el DCAStreamer, y la aplica la etapa de ganancia del DCAStreamer sobre el
+// audio PCM, antes de volver a codificarlo en Opus.
+type Gain struct {
+	fade time.Duration // fade es la duración de los fundidos; cero los desactiva.
+
+	mu     sync.Mutex
+	volume float64 // volume es el volumen, como fracción del original.
+	level  float64 // level es el nivel del fundido, de 0 (silencio) a 1.
+	target float64 // target es el nivel al que va el fundido.
+
+	ahead    int           // ahead son los frames que la etapa puede adelantarse al envío.
+	produced int           // produced son los frames del flujo actual por los que pasó la ganancia.
+	sent     int           // sent son los frames del flujo actual que se enviaron.
+	unpaced  bool          // unpaced indica que la etapa dejó de acompasarse al envío en el flujo actual.
+	progress chan struct{} // progress avisa a la etapa que se envió un frame.
+}
+
+// NewGain crea una ganancia con el volumen original y fundidos de la duración indicada.
+func NewGain(fade time.Duration) *Gain {
+	return &Gain{fade: fade, volume: 1, level: 1, target: 1, progress: make(chan struct{}, 1)}
+}
+
+// SetVolume cambia el volumen. volume es un porcentaje: 100 es el volumen original.
+func (g *Gain) SetVolume(volume int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.volume = float64(max(volume, 0)) / 100
+}
+
+// Start prepara la ganancia para una canción nueva. Con fadeIn, la canción arranca en silencio y sube durante el
+// fundido, como al retomarla desde una posición; si no, arranca con el volumen completo.
+func (g *Gain) Start(fadeIn bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.level, g.target = 1, 1
+	if fadeIn && g.fade > 0 {
+		g.level = 0
+	}
+}
+
+// fadeOut empieza a bajar el volumen hasta el silencio y devuelve cuánto falta para que el fundido termine de
+// enviarse, contando los frames que la etapa tiene adelantados. Sin fundido devuelve cero.
+func (g *Gain) fadeOut() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fade <= 0 {
+		return 0
+	}
+	g.target = 0
+	return g.fade + time.Duration(g.ahead)*frameLength
+}
+
+// fadeIn empieza a subir el volumen hasta el nivel completo.
+func (g *Gain) fadeIn() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.target = 1
+	if g.fade <= 0 {
+		g.level = 1
+	}
+}
+
+// begin reinicia el acompasamiento con el envío para un flujo nuevo, en el que la etapa puede adelantarse ahead frames.
+func (g *Gain) begin(ahead int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ahead = ahead
+	g.produced, g.sent = 0, 0
+	g.unpaced = false
+}
+
+// frameSent registra que se envió un frame del flujo actual.
+func (g *Gain) frameSent() {
+	g.mu.Lock()
+	g.sent++
+	g.mu.Unlock()
+	select {
+	case g.progress <- struct{}{}:
+	default:
+	}
+}
+
+// waitAhead espera a que la etapa pueda procesar un frame más sin adelantarse al envío más de ahead frames. Así los
+// cambios de ganancia se escuchan enseguida, en lugar de después de todo lo que se codificó por adelantado.
+func (g *Gain) waitAhead(ctx context.Context) error {
+	timeout := time.NewTimer(gainPacingTimeout)
+	defer timeout.Stop()
+	for {
+		g.mu.Lock()
+		if g.unpaced || g.produced-g.sent < g.ahead {
+			g.produced++
+			g.mu.Unlock()
+			return nil
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-g.progress:
+		case <-timeout.C:
+			g.mu.Lock()
+			g.unpaced = true
+			g.mu.Unlock()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// apply multiplica las muestras del audio PCM s16le estéreo por el volumen y por el nivel del fundido, que avanza
+// muestra a muestra hacia su objetivo.
+func (g *Gain) apply(pcm []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.volume == 1 && g.level == 1 && g.target == 1 {
+		return
+	}
+	step := 1.0
+	if g.fade > 0 {
+		step = 1 / (g.fade.Seconds() * opusSampleRate)
+	}
+	for i := 0; i+4 <= len(pcm); i += 4 {
+		factor := g.volume * g.level
+		scaleSample(pcm[i:i+2], factor)
+		scaleSample(pcm[i+2:i+4], factor)
+		switch {
+		case g.level < g.target:
+			g.level = math.Min(g.level+step, g.target)
+		case g.level > g.target:
+			g.level = math.Max(g.level-step, g.target)
+		}
+	}
+}
+
+// scaleSample multiplica una muestra s16le por factor, recortándola al rango de 16 bits.
+func scaleSample(sample []byte, factor float64) {
+	value := float64(int16(binary.LittleEndian.Uint16(sample))) * factor
+	value = math.Max(math.Min(math.Round(value), math.MaxInt16), math.MinInt16)
+	binary.LittleEndian.PutUint16(sample, uint16(int16(value)))
+}
+
+// process lee el audio PCM de pcm frame por frame, le aplica la ganancia y lo escribe en w, sin adelantarse al envío.
+func (g *Gain) process(ctx context.Context, pcm io.Reader, w io.Writer) error {
+	frame := make([]byte, pcmFrameSize)
+	for {
+		n, err := io.ReadFull(pcm, frame)
+		if n > 0 {
+			if err := g.waitAhead(ctx); err != nil {
+				return err
+			}
+			g.apply(frame[:n])
+			if _, err := w.Write(frame[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
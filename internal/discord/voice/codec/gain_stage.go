@@ -0,0 +1,229 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// gainDecodeArgs son los argumentos de ffmpeg que decodifican el audio Ogg Opus a PCM s16le estéreo a 48kHz.
+	gainDecodeArgs = []string{"-loglevel", "error", "-f", "ogg", "-i", "pipe:0", "-f", "s16le", "-ar", "48000", "-ac", "2", "pipe:1"}
+	// gainEncodeArgs son los argumentos de ffmpeg que vuelven a codificar el PCM en frames Opus de 20ms. Cada frame
+	// va en su propia página Ogg y se escribe apenas se codifica, para que la etapa no retenga audio.
+	gainEncodeArgs = []string{"-loglevel", "error", "-f", "s16le", "-ar", "48000", "-ac", "2", "-i", "pipe:0",
+		"-c:a", "libopus", "-b:a", "128k", "-frame_duration", "20", "-f", "ogg", "-page_duration", "20000", "-flush_packets", "1", "pipe:1"}
+)
+
+// gainSlotTimeout es el tiempo máximo que la etapa de ganancia espera un lugar en el ProcessLimiter. La descarga de
+// la misma canción puede estar ocupando el único lugar del servidor hasta que la canción termine de sonar, así que
+// esperar sin límite podría trabar la reproducción.
+const gainSlotTimeout = 30 * time.Second
+
+// gainStage pasa el audio de un flujo DCA por una Gain. El audio Opus no se puede escalar sin decodificarlo, así que
+// un ffmpeg lo decodifica a PCM, se multiplica cada muestra por la ganancia y otro ffmpeg lo vuelve a codificar en
+// Opus. La ganancia no se adelanta al envío más que unos frames, así que sus cambios se escuchan enseguida.
+type gainStage struct {
+	cancel context.CancelFunc
+	output *io.PipeReader
+	wg     sync.WaitGroup
+}
+
+// startGainStage arranca la etapa de ganancia sobre el flujo DCA y devuelve el flujo DCA con la ganancia aplicada.
+// La etapa termina cuando se cancela ctx o con close, que espera a que terminen los procesos. Mientras corre ocupa un
+// lugar del ProcessLimiter, si hay uno configurado.
+func (d *DCAStreamerImpl) startGainStage(ctx context.Context, dca io.Reader) (io.Reader, func(), error) {
+	release, err := d.acquireGainSlot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// ctx se cancela al cortar la etapa desde afuera; kill, además, cuando falla una de sus partes, y en ese caso el
+	// error se informa.
+	ctx, cancel := context.WithCancel(ctx)
+	processCtx, kill := context.WithCancel(ctx)
+	decoderStderr, encoderStderr := &bytes.Buffer{}, &bytes.Buffer{}
+	decoder := d.command(processCtx, "ffmpeg", gainDecodeArgs...)
+	decoder.Stderr = decoderStderr
+	encoder := d.command(processCtx, "ffmpeg", gainEncodeArgs...)
+	encoder.Stderr = encoderStderr
+
+	decoderIn, decoderErr := decoder.StdinPipe()
+	pcm, pcmErr := decoder.StdoutPipe()
+	encoderIn, encoderErr := encoder.StdinPipe()
+	encoded, encodedErr := encoder.StdoutPipe()
+	if err := errors.Join(decoderErr, pcmErr, encoderErr, encodedErr); err != nil {
+		kill()
+		cancel()
+		release()
+		return nil, nil, fmt.Errorf("error al preparar la etapa de ganancia: %w", err)
+	}
+	if err := decoder.Start(); err != nil {
+		kill()
+		cancel()
+		release()
+		return nil, nil, fmt.Errorf("error al iniciar el decodificador de la etapa de ganancia: %w", err)
+	}
+	d.trackProcess(true)
+	if err := encoder.Start(); err != nil {
+		kill()
+		cancel()
+		_ = decoder.Wait()
+		d.trackProcess(false)
+		release()
+		return nil, nil, fmt.Errorf("error al iniciar el codificador de la etapa de ganancia: %w", err)
+	}
+	d.trackProcess(true)
+
+	output, writer := io.Pipe()
+	stage := &gainStage{cancel: cancel, output: output}
+	decoded := make(chan error, 1)
+	// La escritura del flujo DCA no se espera al cerrar la etapa: puede estar bloqueada leyéndolo, y termina sola
+	// en la siguiente escritura, porque el decodificador ya no la acepta.
+	go func() {
+		defer decoderIn.Close()
+		_ = writeOggOpus(decoderIn, dca)
+	}()
+	stage.wg.Add(2)
+	go func() {
+		defer stage.wg.Done()
+		err := d.gain.process(processCtx, pcm, encoderIn)
+		_ = encoderIn.Close()
+		if err != nil {
+			// El decodificador se detiene para poder esperarlo.
+			kill()
+		}
+		waitErr := decoder.Wait()
+		d.trackProcess(false)
+		if waitErr != nil && err == nil {
+			err = commandError("decodificador", waitErr, decoderStderr)
+		}
+		decoded <- err
+	}()
+	go func() {
+		defer stage.wg.Done()
+		err := writeDCA(writer, NewOggOpusReader(encoded))
+		if err != nil {
+			kill()
+		}
+		waitErr := encoder.Wait()
+		d.trackProcess(false)
+		if waitErr != nil {
+			// Si el codificador falló, su error explica mejor que el del decodificador por qué se cortó la etapa.
+			err = commandError("codificador", waitErr, encoderStderr)
+		}
+		if decodeErr := <-decoded; err == nil {
+			err = decodeErr
+		}
+		release()
+		if ctx.Err() != nil {
+			err = nil
+		}
+		_ = writer.CloseWithError(err)
+	}()
+	return output, stage.close, nil
+}
+
+// acquireGainSlot espera un lugar del ProcessLimiter para la etapa de ganancia, como mucho gainSlotTimeout, y
+// devuelve la función que lo libera.
+func (d *DCAStreamerImpl) acquireGainSlot(ctx context.Context) (func(), error) {
+	if d.limiter == nil {
+		return func() {}, nil
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, gainSlotTimeout)
+	defer cancel()
+	release, err := d.limiter.Acquire(waitCtx)
+	if err != nil {
+		return nil, fmt.Errorf("error al esperar un lugar para la etapa de ganancia: %w", err)
+	}
+	return release, nil
+}
+
+// trackProcess incrementa (o decrementa, si running es false) el gauge de procesos con un ffmpeg de la etapa.
+func (d *DCAStreamerImpl) trackProcess(running bool) {
+	if d.processGauge == nil {
+		return
+	}
+	if running {
+		d.processGauge.Inc("ffmpeg")
+	} else {
+		d.processGauge.Dec("ffmpeg")
+	}
+}
+
+// close termina los procesos de la etapa y espera a que se detengan.
+func (s *gainStage) close() {
+	s.cancel()
+	_ = s.output.Close()
+	s.wg.Wait()
+}
+
+// writeOggOpus escribe los frames del flujo DCA como un stream Ogg Opus, que ffmpeg puede decodificar.
+func writeOggOpus(w io.Writer, dca io.Reader) error {
+	ogg, err := NewOggOpusWriter(w, rand.Uint32())
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(dca, dcaReadBufferSize)
+	var opuslen int16
+	for {
+		if err := binary.Read(reader, binary.LittleEndian, &opuslen); err != nil {
+			if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+				return ogg.Close()
+			}
+			return err
+		}
+		frame := make([]byte, max(int(opuslen), 0))
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return ogg.Close()
+			}
+			return err
+		}
+		if err := ogg.WritePacket(frame); err != nil {
+			return err
+		}
+	}
+}
+
+// writeDCA escribe los frames del stream Ogg Opus en w con el formato DCA: la longitud de cada frame y el frame.
+func writeDCA(w io.Writer, ogg *OggOpusReader) error {
+	header := make([]byte, 2)
+	for {
+		packet, err := ogg.ReadPacket()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint16(header, uint16(len(packet)))
+		if _, err := w.Write(header); err != nil {
+			return err
+		}
+		if _, err := w.Write(packet); err != nil {
+			return err
+		}
+	}
+}
+
+// commandError describe la falla de un proceso de la etapa de ganancia con lo último que escribió en stderr.
+func commandError(name string, err error, stderr *bytes.Buffer) error {
+	if output := strings.TrimSpace(stderr.String()); output != "" {
+		return fmt.Errorf("falló el %s de la etapa de ganancia: %w: %s", name, err, output)
+	}
+	return fmt.Errorf("falló el %s de la etapa de ganancia: %w", name, err)
+}
+
+// defaultCommand crea los procesos de la etapa de ganancia.
+func defaultCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
@@ -0,0 +1,232 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pcmSamples devuelve n pares de muestras estéreo s16le con el valor indicado.
+func pcmSamples(n int, value int16) []byte {
+	pcm := make([]byte, n*4)
+	for i := 0; i < n*2; i++ {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(value))
+	}
+	return pcm
+}
+
+func sampleAt(pcm []byte, pair int) int16 {
+	return int16(binary.LittleEndian.Uint16(pcm[pair*4:]))
+}
+
+func TestGain_AppliesVolume(t *testing.T) {
+	gain := NewGain(0)
+	pcm := pcmSamples(4, 1000)
+	gain.apply(pcm)
+	assert.Equal(t, pcmSamples(4, 1000), pcm, "con el volumen original el audio no cambia")
+
+	gain.SetVolume(50)
+	gain.apply(pcm)
+	assert.Equal(t, pcmSamples(4, 500), pcm)
+
+	gain.SetVolume(200)
+	pcm = pcmSamples(1, math.MaxInt16-10)
+	gain.apply(pcm)
+	assert.Equal(t, pcmSamples(1, math.MaxInt16), pcm, "las muestras se recortan al rango de 16 bits")
+}
+
+func TestGain_StartFadesIn(t *testing.T) {
+	gain := NewGain(time.Second)
+	gain.Start(true)
+	pcm := pcmSamples(opusSampleRate, 10000)
+	gain.apply(pcm)
+
+	assert.Equal(t, int16(0), sampleAt(pcm, 0), "arranca en silencio")
+	assert.InDelta(t, 5000, sampleAt(pcm, opusSampleRate/2), 1, "a la mitad del fundido suena a la mitad")
+	pcm = pcmSamples(1, 10000)
+	gain.apply(pcm)
+	assert.Equal(t, int16(10000), sampleAt(pcm, 0), "al terminar el fundido suena completo")
+
+	gain.Start(false)
+	pcm = pcmSamples(1, 10000)
+	gain.apply(pcm)
+	assert.Equal(t, int16(10000), sampleAt(pcm, 0))
+}
+
+func TestGain_FadeOut(t *testing.T) {
+	gain := NewGain(time.Second)
+	gain.begin(5)
+	assert.Equal(t, time.Second+5*frameLength, gain.fadeOut(), "el fundido termina de enviarse después de los frames adelantados")
+
+	pcm := pcmSamples(opusSampleRate+1, 10000)
+	gain.apply(pcm)
+	assert.Equal(t, int16(10000), sampleAt(pcm, 0))
+	assert.InDelta(t, 5000, sampleAt(pcm, opusSampleRate/2), 1)
+	assert.Equal(t, int16(0), sampleAt(pcm, opusSampleRate))
+
+	gain.fadeIn()
+	pcm = pcmSamples(opusSampleRate+1, 10000)
+	gain.apply(pcm)
+	assert.Equal(t, int16(0), sampleAt(pcm, 0))
+	assert.Equal(t, int16(10000), sampleAt(pcm, opusSampleRate))
+}
+
+func TestGain_FadeOutWithoutFade(t *testing.T) {
+	gain := NewGain(0)
+	assert.Zero(t, gain.fadeOut())
+	pcm := pcmSamples(1, 10000)
+	gain.apply(pcm)
+	assert.Equal(t, int16(10000), sampleAt(pcm, 0), "sin fundido el audio sigue hasta cortarse")
+}
+
+func TestGain_ProcessWaitsForSentFrames(t *testing.T) {
+	gain := NewGain(0)
+	gain.begin(2)
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- gain.process(context.Background(), bytes.NewReader(pcmSamples(opusFrameSamples*3, 1)), &out)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("la etapa se adelantó al envío más frames de los permitidos")
+	case <-time.After(50 * time.Millisecond):
+	}
+	gain.frameSent()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("la etapa no siguió después de enviarse un frame")
+	}
+	assert.Equal(t, pcmSamples(opusFrameSamples*3, 1), out.Bytes())
+}
+
+func TestGain_ProcessStopsPacingWhenFramesAreNotSent(t *testing.T) {
+	gain := NewGain(0)
+	gain.begin(1)
+	var out bytes.Buffer
+	start := time.Now()
+	require.NoError(t, gain.process(context.Background(), bytes.NewReader(pcmSamples(opusFrameSamples*3, 1)), &out))
+	assert.Less(t, time.Since(start), 2*gainPacingTimeout, "deja de acompasarse después del primer timeout")
+	assert.Len(t, out.Bytes(), 3*pcmFrameSize)
+}
+
+func TestGainStage_RoundTrip(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeOggOpus(&out, bytes.NewReader(dcaFrames(3, 10))))
+	var dca bytes.Buffer
+	require.NoError(t, writeDCA(&dca, NewOggOpusReader(&out)))
+	assert.Equal(t, dcaFrames(3, 10), dca.Bytes())
+}
+
+func TestStreamDCAData_PassesThroughGainStage(t *testing.T) {
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithGain(NewGain(0))
+	var commands []string
+	clientDCA.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		commands = append(commands, name)
+		// Con el volumen original la ganancia no toca el audio, así que sin ffmpeg el flujo Ogg vuelve igual.
+		return exec.CommandContext(ctx, "cat")
+	}
+	opusChan := make(chan []byte, 3)
+
+	require.NoError(t, clientDCA.StreamDCAData(context.Background(), bytes.NewReader(dcaFrames(3, 10)), opusChan, nil))
+	assert.Equal(t, []string{"ffmpeg", "ffmpeg"}, commands)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 10), <-opusChan)
+	}
+}
+
+// slotLimiter es un ProcessLimiter con un solo lugar que cuenta cuántas veces se ocupó.
+type slotLimiter struct {
+	slot     chan struct{}
+	acquired int
+}
+
+func (l *slotLimiter) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case l.slot <- struct{}{}:
+		l.acquired++
+		return func() { <-l.slot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// processCounter es un gauge de procesos que recuerda cuántos corrieron a la vez.
+type processCounter struct {
+	mu            sync.Mutex
+	running, peak int
+}
+
+func (c *processCounter) Describe(chan<- *prometheus.Desc) {}
+func (c *processCounter) Collect(chan<- prometheus.Metric) {}
+
+func (c *processCounter) Inc(labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+}
+
+func (c *processCounter) Dec(labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running--
+}
+
+func TestStreamDCAData_GainStageUsesProcessLimiterAndGauge(t *testing.T) {
+	limiter := &slotLimiter{slot: make(chan struct{}, 1)}
+	gauge := &processCounter{}
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithGain(NewGain(0)).WithProcessLimiter(limiter).WithProcessGauge(gauge)
+	clientDCA.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "cat")
+	}
+
+	require.NoError(t, clientDCA.StreamDCAData(context.Background(), bytes.NewReader(dcaFrames(3, 10)), make(chan []byte, 3), nil))
+	assert.Equal(t, 1, limiter.acquired)
+	assert.Empty(t, limiter.slot, "al terminar la etapa se libera el lugar")
+	assert.Equal(t, 2, gauge.peak, "se cuentan los dos ffmpeg")
+	assert.Zero(t, gauge.running)
+
+	// Sin lugar libre, la etapa no arranca y el envío termina con el error.
+	limiter.slot <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := clientDCA.StreamDCAData(ctx, bytes.NewReader(dcaFrames(3, 10)), make(chan []byte, 3), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "esperar un lugar")
+	assert.Zero(t, gauge.running)
+}
+
+func TestStreamDCAData_ReturnsGainStageErrors(t *testing.T) {
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithGain(NewGain(0))
+	clientDCA.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", "-c", "cat >/dev/null; echo 'Unknown encoder libopus' >&2; exit 1")
+	}
+
+	err := clientDCA.StreamDCAData(context.Background(), bytes.NewReader(dcaFrames(3, 10)), make(chan []byte, 3), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown encoder libopus")
+}
+
+func TestStreamDCAData_FadesOutBeforeStopping(t *testing.T) {
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithGain(NewGain(100 * time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	sendCtx, stop := clientDCA.fadeOutOnCancel(ctx)
+	defer stop()
+
+	start := time.Now()
+	cancel()
+	<-sendCtx.Done()
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "el envío sigue mientras baja el volumen")
+}
@@ -0,0 +1,155 @@
+package codec
+
+import (
+	"context"
+	"io"
+)
+
+// jitterShrinkAfter es la cantidad de frames seguidos sin quedarse sin audio después de la cual el buffer adaptativo
+// achica su objetivo en un frame (30 segundos de audio).
+const jitterShrinkAfter = 1500
+
+// JitterBuffer decide cuántos frames se acumulan antes de empezar a enviar, o de retomar el envío después de
+// quedarse sin audio. Cada vez que el buffer se vacía mientras la canción sigue, el objetivo se duplica hasta max;
+// después de jitterShrinkAfter frames sin vaciarse, baja de a un frame hasta min. Así un host con una lectura
+// irregular termina con el colchón justo para no cortar el audio, sin agregar latencia de más en uno estable.
+type JitterBuffer struct {
+	min       int
+	max       int
+	target    int
+	stable    int
+	underruns int
+}
+
+// NewJitterBuffer crea un buffer adaptativo cuyo objetivo arranca en min y nunca pasa de max.
+func NewJitterBuffer(min, max int) *JitterBuffer {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &JitterBuffer{min: min, max: max, target: min}
+}
+
+// Target devuelve la cantidad de frames que se acumulan antes de enviar.
+func (j *JitterBuffer) Target() int {
+	return j.target
+}
+
+// Underruns devuelve cuántas veces se vació el buffer durante la canción.
+func (j *JitterBuffer) Underruns() int {
+	return j.underruns
+}
+
+// Underrun registra que el buffer se vació antes de terminar la canción y agranda el objetivo.
+func (j *JitterBuffer) Underrun() {
+	j.underruns++
+	j.stable = 0
+	j.target = min(j.target*2, j.max)
+}
+
+// FrameSent registra un frame enviado sin vaciar el buffer y, si el envío viene estable, achica el objetivo.
+func (j *JitterBuffer) FrameSent() {
+	j.stable++
+	if j.stable >= jitterShrinkAfter && j.target > j.min {
+		j.target--
+		j.stable = 0
+	}
+}
+
+// frameResult es un frame leído del flujo DCA, o el error que cortó la lectura.
+type frameResult struct {
+	data *[]byte
+	err  error
+}
+
+// frameBuffer lee los frames del flujo DCA por adelantado en otra goroutine y los entrega respetando el objetivo
+// del JitterBuffer.
+type frameBuffer struct {
+	frames  chan frameResult
+	pending []*[]byte
+	err     error
+	started bool
+	jitter  *JitterBuffer
+}
+
+// newFrameBuffer empieza a leer frames con read hasta depth frames por delante del envío. La lectura termina con
+// el primer error, incluido io.EOF, o cuando se cancela ctx.
+func newFrameBuffer(ctx context.Context, depth int, jitter *JitterBuffer, read func() (*[]byte, error)) *frameBuffer {
+	b := &frameBuffer{
+		frames: make(chan frameResult, depth),
+		jitter: jitter,
+	}
+	go func() {
+		defer close(b.frames)
+		for {
+			data, err := read()
+			select {
+			case b.frames <- frameResult{data: data, err: err}:
+			case <-ctx.Done():
+				if data != nil {
+					putFrame(data)
+				}
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// next devuelve el próximo frame. Si no hay ninguno listo, espera a acumular el objetivo del buffer adaptativo
+// antes de devolverlo.
+func (b *frameBuffer) next(ctx context.Context) (*[]byte, error) {
+	if len(b.pending) == 0 && b.err == nil {
+		select {
+		case res, ok := <-b.frames:
+			b.receive(res, ok)
+		default:
+			if b.started {
+				b.jitter.Underrun()
+			}
+			if err := b.fill(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	b.started = true
+	if len(b.pending) == 0 {
+		return nil, b.err
+	}
+	frame := b.pending[0]
+	// Se corren los frames pendientes al principio del arreglo para no agrandarlo con cada frame.
+	n := copy(b.pending, b.pending[1:])
+	b.pending[n] = nil
+	b.pending = b.pending[:n]
+	b.jitter.FrameSent()
+	return frame, nil
+}
+
+// fill acumula frames hasta llegar al objetivo del buffer adaptativo o hasta que termine la lectura.
+func (b *frameBuffer) fill(ctx context.Context) error {
+	for len(b.pending) < b.jitter.Target() && b.err == nil {
+		select {
+		case res, ok := <-b.frames:
+			b.receive(res, ok)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (b *frameBuffer) receive(res frameResult, ok bool) {
+	switch {
+	case !ok:
+		b.err = io.EOF
+	case res.err != nil:
+		b.err = res.err
+	default:
+		b.pending = append(b.pending, res.data)
+	}
+}
@@ -0,0 +1,61 @@
+package codec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJitterBuffer_GrowsOnUnderrunAndShrinksWhenStable(t *testing.T) {
+	jitter := NewJitterBuffer(2, 10)
+	assert.Equal(t, 2, jitter.Target())
+
+	jitter.Underrun()
+	jitter.Underrun()
+	assert.Equal(t, 8, jitter.Target())
+	jitter.Underrun()
+	assert.Equal(t, 10, jitter.Target())
+	assert.Equal(t, 3, jitter.Underruns())
+
+	for i := 0; i < jitterShrinkAfter; i++ {
+		jitter.FrameSent()
+	}
+	assert.Equal(t, 9, jitter.Target())
+}
+
+func TestJitterBuffer_NormalizesBounds(t *testing.T) {
+	jitter := NewJitterBuffer(0, 0)
+
+	jitter.Underrun()
+
+	assert.Equal(t, 1, jitter.Target())
+}
+
+func TestFrameBuffer_FillsTargetBeforeDelivering(t *testing.T) {
+	frames := make(chan []byte, 3)
+	frames <- []byte{1}
+	frames <- []byte{2}
+	frames <- []byte{3}
+	close(frames)
+	read := func() (*[]byte, error) {
+		frame, ok := <-frames
+		if !ok {
+			return nil, errEndOfStream
+		}
+		return &frame, nil
+	}
+	buffer := newFrameBuffer(context.Background(), 4, NewJitterBuffer(2, 4), read)
+
+	var got [][]byte
+	for {
+		frame, err := buffer.next(context.Background())
+		if err != nil {
+			assert.ErrorIs(t, err, errEndOfStream)
+			break
+		}
+		got = append(got, *frame)
+	}
+
+	assert.Equal(t, [][]byte{{1}, {2}, {3}}, got)
+}
@@ -0,0 +1,203 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// opusSampleRate es la frecuencia con la que Opus cuenta las muestras, sin importar la del audio original.
+	opusSampleRate = 48000
+	// opusFrameSamples son las muestras de cada frame DCA: Discord usa frames de 20ms.
+	opusFrameSamples = opusSampleRate / 50
+	// opusPreSkip son las muestras que el decodificador descarta al principio, el valor que usa libopus.
+	opusPreSkip = 312
+
+	oggHeaderBOS = 0x02
+	oggHeaderEOS = 0x04
+	// oggOpusHeaders son los paquetes de encabezado de un stream Ogg Opus: OpusHead y OpusTags.
+	oggOpusHeaders = 2
+)
+
+// errInvalidOggPage indica que el stream no tiene una página Ogg donde se esperaba.
+var errInvalidOggPage = errors.New("página Ogg inválida")
+
+// oggCRCTable es la tabla del CRC-32 de Ogg (polinomio 0x04c11db7, sin reflejar), que no es el de hash/crc32.
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// OggOpusWriter escribe los frames Opus de una canción como un stream Ogg Opus, que se puede abrir con cualquier
+// reproductor o con ffmpeg. Cada página lleva un frame; el último se guarda hasta Close para marcarlo como el
+// final del stream.
+type OggOpusWriter struct {
+	w       io.Writer
+	serial  uint32
+	page    uint32
+	granule uint64
+	pending []byte
+}
+
+// NewOggOpusWriter escribe los encabezados de un stream Ogg Opus estéreo con el número de serie indicado.
+func NewOggOpusWriter(w io.Writer, serial uint32) (*OggOpusWriter, error) {
+	o := &OggOpusWriter{w: w, serial: serial}
+
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1 // versión
+	head[9] = 2 // canales
+	binary.LittleEndian.PutUint16(head[10:], opusPreSkip)
+	binary.LittleEndian.PutUint32(head[12:], opusSampleRate)
+	if err := o.writePage(head, oggHeaderBOS, 0); err != nil {
+		return nil, err
+	}
+
+	vendor := "GoMusicBot"
+	tags := make([]byte, 8+4+len(vendor)+4)
+	copy(tags, "OpusTags")
+	binary.LittleEndian.PutUint32(tags[8:], uint32(len(vendor)))
+	copy(tags[12:], vendor)
+	if err := o.writePage(tags, 0, 0); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WritePacket agrega un frame Opus de 20ms al stream.
+func (o *OggOpusWriter) WritePacket(packet []byte) error {
+	if o.pending != nil {
+		if err := o.flush(0); err != nil {
+			return err
+		}
+	}
+	o.pending = append([]byte(nil), packet...)
+	return nil
+}
+
+// Close escribe el último frame con la marca de fin de stream. No cierra el io.Writer.
+func (o *OggOpusWriter) Close() error {
+	if o.pending == nil {
+		return o.writePage(nil, oggHeaderEOS, o.granule)
+	}
+	return o.flush(oggHeaderEOS)
+}
+
+func (o *OggOpusWriter) flush(headerType byte) error {
+	o.granule += opusFrameSamples
+	err := o.writePage(o.pending, headerType, o.granule)
+	o.pending = nil
+	return err
+}
+
+func (o *OggOpusWriter) writePage(packet []byte, headerType byte, granule uint64) error {
+	segments := len(packet)/255 + 1
+	page := make([]byte, 27+segments, 27+segments+len(packet))
+	copy(page, "OggS")
+	page[5] = headerType
+	binary.LittleEndian.PutUint64(page[6:], granule)
+	binary.LittleEndian.PutUint32(page[14:], o.serial)
+	binary.LittleEndian.PutUint32(page[18:], o.page)
+	page[26] = byte(segments)
+	for i := 0; i < segments-1; i++ {
+		page[27+i] = 255
+	}
+	page[27+segments-1] = byte(len(packet) % 255)
+	page = append(page, packet...)
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+
+	o.page++
+	_, err := o.w.Write(page)
+	return err
+}
+
+// OggOpusReader lee los frames Opus de un stream Ogg Opus, como el que escribe ffmpeg. Descarta los encabezados,
+// también los de los streams encadenados, y no verifica el checksum de las páginas.
+type OggOpusReader struct {
+	r       io.Reader
+	header  []byte
+	packets [][]byte // packets son los paquetes completos de la última página que todavía no se leyeron.
+	partial []byte   // partial es el paquete que sigue en la página siguiente.
+	skip    int      // skip son los paquetes de encabezado que faltan descartar.
+}
+
+// NewOggOpusReader crea un lector de los frames Opus del stream Ogg Opus de r.
+func NewOggOpusReader(r io.Reader) *OggOpusReader {
+	return &OggOpusReader{r: r, header: make([]byte, 27), skip: oggOpusHeaders}
+}
+
+// ReadPacket devuelve el próximo frame Opus. Devuelve io.EOF cuando el stream termina entre páginas.
+func (o *OggOpusReader) ReadPacket() ([]byte, error) {
+	for {
+		for len(o.packets) > 0 {
+			packet := o.packets[0]
+			o.packets = o.packets[1:]
+			if o.skip > 0 {
+				o.skip--
+				continue
+			}
+			return packet, nil
+		}
+		if err := o.readPage(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readPage lee la próxima página y separa sus paquetes. Un segmento de 255 bytes indica que el paquete sigue en el
+// segmento siguiente, que puede estar en la página siguiente.
+func (o *OggOpusReader) readPage() error {
+	if _, err := io.ReadFull(o.r, o.header); err != nil {
+		return err
+	}
+	if string(o.header[:4]) != "OggS" {
+		return errInvalidOggPage
+	}
+	if o.header[5]&oggHeaderBOS != 0 {
+		o.skip = oggOpusHeaders
+	}
+	segments := make([]byte, o.header[26])
+	if _, err := io.ReadFull(o.r, segments); err != nil {
+		return unexpectedEOF(err)
+	}
+	for _, size := range segments {
+		data := make([]byte, size)
+		if _, err := io.ReadFull(o.r, data); err != nil {
+			return unexpectedEOF(err)
+		}
+		o.partial = append(o.partial, data...)
+		if size < 255 {
+			o.packets = append(o.packets, o.partial)
+			o.partial = nil
+		}
+	}
+	return nil
+}
+
+// unexpectedEOF convierte io.EOF en io.ErrUnexpectedEOF, para los streams que terminan en medio de una página.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
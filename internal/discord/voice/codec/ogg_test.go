@@ -0,0 +1,123 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+type oggPage struct {
+	headerType byte
+	granule    uint64
+	sequence   uint32
+	packet     []byte
+}
+
+// readOggPages separa las páginas de un stream Ogg y verifica el checksum de cada una.
+func readOggPages(t *testing.T, data []byte) []oggPage {
+	var pages []oggPage
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 27)
+		require.Equal(t, "OggS", string(data[:4]))
+		segments := int(data[26])
+		size := 27 + segments
+		for _, segment := range data[27 : 27+segments] {
+			size += int(segment)
+		}
+		page := append([]byte(nil), data[:size]...)
+		checksum := binary.LittleEndian.Uint32(page[22:])
+		binary.LittleEndian.PutUint32(page[22:], 0)
+		assert.Equal(t, oggCRC(page), checksum)
+
+		pages = append(pages, oggPage{
+			headerType: data[5],
+			granule:    binary.LittleEndian.Uint64(data[6:]),
+			sequence:   binary.LittleEndian.Uint32(data[18:]),
+			packet:     data[27+segments : size],
+		})
+		data = data[size:]
+	}
+	return pages
+}
+
+func TestOggCRC(t *testing.T) {
+	// Valor de referencia del CRC de Ogg (polinomio 0x04c11db7, valor inicial 0, sin complemento final).
+	assert.Equal(t, uint32(0x89a1897f), oggCRC([]byte("123456789")))
+}
+
+func TestOggOpusWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ogg, err := NewOggOpusWriter(buf, 42)
+	require.NoError(t, err)
+	require.NoError(t, ogg.WritePacket([]byte{1, 2, 3}))
+	require.NoError(t, ogg.WritePacket(bytes.Repeat([]byte{4}, 300)))
+	require.NoError(t, ogg.Close())
+
+	pages := readOggPages(t, buf.Bytes())
+	require.Len(t, pages, 4)
+	assert.Equal(t, byte(oggHeaderBOS), pages[0].headerType)
+	assert.Equal(t, "OpusHead", string(pages[0].packet[:8]))
+	assert.Equal(t, "OpusTags", string(pages[1].packet[:8]))
+	assert.Equal(t, []byte{1, 2, 3}, pages[2].packet)
+	assert.Equal(t, uint64(opusFrameSamples), pages[2].granule)
+	assert.Len(t, pages[3].packet, 300)
+	assert.Equal(t, byte(oggHeaderEOS), pages[3].headerType)
+	assert.Equal(t, uint64(2*opusFrameSamples), pages[3].granule)
+	for i, page := range pages {
+		assert.Equal(t, uint32(i), page.sequence)
+	}
+}
+
+func TestOggOpusReader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ogg, err := NewOggOpusWriter(buf, 42)
+	require.NoError(t, err)
+	packets := [][]byte{{1, 2, 3}, bytes.Repeat([]byte{4}, 255), bytes.Repeat([]byte{5}, 600)}
+	for _, packet := range packets {
+		require.NoError(t, ogg.WritePacket(packet))
+	}
+	require.NoError(t, ogg.Close())
+
+	reader := NewOggOpusReader(buf)
+	for _, want := range packets {
+		packet, err := reader.ReadPacket()
+		require.NoError(t, err)
+		assert.Equal(t, want, packet)
+	}
+	_, err = reader.ReadPacket()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestOggOpusReader_ChainedStreams(t *testing.T) {
+	buf := &bytes.Buffer{}
+	for _, packet := range [][]byte{{1}, {2}} {
+		ogg, err := NewOggOpusWriter(buf, uint32(packet[0]))
+		require.NoError(t, err)
+		require.NoError(t, ogg.WritePacket(packet))
+		require.NoError(t, ogg.Close())
+	}
+
+	reader := NewOggOpusReader(buf)
+	for _, want := range [][]byte{{1}, {2}} {
+		packet, err := reader.ReadPacket()
+		require.NoError(t, err)
+		assert.Equal(t, want, packet)
+	}
+}
+
+func TestOggOpusReader_Truncated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ogg, err := NewOggOpusWriter(buf, 42)
+	require.NoError(t, err)
+	require.NoError(t, ogg.WritePacket([]byte{1, 2, 3}))
+	require.NoError(t, ogg.Close())
+
+	_, err = NewOggOpusReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1])).ReadPacket()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = NewOggOpusReader(bytes.NewReader([]byte("no es un stream Ogg que se pueda leer"))).ReadPacket()
+	assert.ErrorIs(t, err, errInvalidOggPage)
+}
@@ -1,83 +1,267 @@
 package codec
 
 import (
+	"bufio"
 	"context"
 	"encoding/binary"
 	"errors"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/metrics"
 	"go.uber.org/zap"
 	"io"
+	"os/exec"
 	"time"
 )
 
+// DCAStreamer envía los frames Opus de un flujo DCA a opusChan. Los frames se reutilizan: el que los recibe tiene
+// que terminar de usar cada uno antes de recibir el siguiente, y copiarlo si lo necesita por más tiempo.
 type DCAStreamer interface {
 	StreamDCAData(ctx context.Context, dca io.Reader, opusChan chan<- []byte, positionCallback func(position time.Duration)) error
 }
 
 type DCAStreamerImpl struct {
-	logger logging.Logger
+	logger  logging.Logger
+	metrics metrics.AudioMetrics // metrics es opcional; si es nil no se registran métricas de temporización.
+	guildID string
+	// sendTimeout es el tiempo máximo que se espera a que la conexión de voz acepte un frame antes de descartarlo.
+	sendTimeout time.Duration
+	sendConfig  SendConfig
+	pause       *Pause // pause es opcional; mientras está pausada se suspende el envío.
+	gain        *Gain  // gain es opcional; si es nil el audio se envía sin pasar por la etapa de ganancia.
+	// command crea los procesos de ffmpeg de la etapa de ganancia.
+	command      func(ctx context.Context, name string, args ...string) *exec.Cmd
+	limiter      ProcessLimiter      // limiter es opcional; limita las etapas de ganancia que corren a la vez.
+	processGauge metrics.GaugeMetric // processGauge es opcional; cuenta los procesos de la etapa de ganancia.
+}
+
+// ProcessLimiter limita la cantidad de procesos externos que corren a la vez, como fetcher.ProcessLimiter. Acquire
+// espera un lugar y devuelve la función que lo libera.
+type ProcessLimiter interface {
+	Acquire(ctx context.Context) (func(), error)
+}
+
+// SendConfig configura el ciclo de envío de frames a la conexión de voz. El valor cero envía cada frame apenas se
+// lee, con el ritmo que marca la conexión.
+type SendConfig struct {
+	// Timer es la fuente de temporización: TimerConnection deja el ritmo a la conexión de voz y TimerTicker espera
+	// un tick propio antes de cada tanda de frames.
+	Timer string
+	// FrameBatch es la cantidad de frames que se envían por tick con TimerTicker; el tick dura FrameBatch frames.
+	FrameBatch int
+	// SendAhead es la cantidad máxima de frames que se leen por adelantado del flujo DCA. Con 0 no se lee por
+	// adelantado y no se usa el buffer adaptativo.
+	SendAhead int
+	// JitterMin y JitterMax acotan los frames que acumula el buffer adaptativo antes de enviar. Un JitterMax de 0
+	// usa SendAhead.
+	JitterMin int
+	JitterMax int
 }
 
 const (
 	frameLength      = time.Duration(20) * time.Millisecond
-	maxOpusBlockSize = 8192 // Tamaño máximo del bloque de datos Opus
 	maxOpusChunkSize = 4096 // Tamaño máximo de cada chunk de datos Opus
+	// dcaReadBufferSize es el tamaño del buffer de lectura del flujo DCA cuando no viene ya con buffer.
+	dcaReadBufferSize = 16 * 1024
+	// lateFrameThreshold es el retraso entre frames a partir del cual un frame se considera tarde.
+	// Discord consume un frame cada 20ms; si tardamos más, el oyente escucha cortes ("audio robótico").
+	lateFrameThreshold      = 2 * frameLength
+	defaultFrameSendTimeout = time.Second
+
+	// TimerConnection deja el ritmo del envío a la conexión de voz, que acepta un frame cada 20ms.
+	TimerConnection = "connection"
+	// TimerTicker envía los frames con un ticker propio, para hosts en los que el ritmo de la conexión es irregular.
+	TimerTicker = "ticker"
 )
 
+// errEndOfStream indica que el flujo DCA terminó entre dos frames.
+var errEndOfStream = errors.New("fin del flujo DCA")
+
 func NewDCAStreamerImpl(logger logging.Logger) *DCAStreamerImpl {
 	return &DCAStreamerImpl{
-		logger: logger,
+		logger:      logger,
+		sendTimeout: defaultFrameSendTimeout,
+		command:     defaultCommand,
 	}
 }
 
+// WithMetrics configura las métricas de temporización de frames para el servidor indicado.
+func (d *DCAStreamerImpl) WithMetrics(audioMetrics metrics.AudioMetrics, guildID string) *DCAStreamerImpl {
+	d.metrics = audioMetrics
+	d.guildID = guildID
+	return d
+}
+
+// WithSendConfig configura la temporización del envío y el buffer adaptativo de frames.
+func (d *DCAStreamerImpl) WithSendConfig(cfg SendConfig) *DCAStreamerImpl {
+	d.sendConfig = cfg
+	return d
+}
+
+// WithPause configura la pausa con la que se suspende el envío. Mientras está pausada el flujo queda abierto y sin
+// leer, así que al retomarla sigue desde el mismo frame. Con WithGain, el audio baja antes de suspenderse y sube al
+// retomarse.
+func (d *DCAStreamerImpl) WithPause(pause *Pause) *DCAStreamerImpl {
+	d.pause = pause
+	return d
+}
+
+// WithGain hace pasar el audio por una etapa de ganancia que le aplica el volumen y los fundidos de gain. La etapa
+// usa ffmpeg con libopus para decodificar y volver a codificar el audio. Al cancelarse el envío, el audio baja
+// durante el fundido antes de cortarse.
+func (d *DCAStreamerImpl) WithGain(gain *Gain) *DCAStreamerImpl {
+	d.gain = gain
+	return d
+}
+
+// WithProcessLimiter hace que cada etapa de ganancia espere un lugar en limiter antes de lanzar sus procesos de ffmpeg
+// y lo ocupe mientras suena la canción, como una descarga.
+func (d *DCAStreamerImpl) WithProcessLimiter(limiter ProcessLimiter) *DCAStreamerImpl {
+	d.limiter = limiter
+	return d
+}
+
+// WithProcessGauge establece la métrica de procesos externos en ejecución, en la que se cuentan los ffmpeg de la
+// etapa de ganancia.
+func (d *DCAStreamerImpl) WithProcessGauge(g metrics.GaugeMetric) *DCAStreamerImpl {
+	d.processGauge = g
+	return d
+}
+
 func (d *DCAStreamerImpl) StreamDCAData(ctx context.Context, dca io.Reader, opusChan chan<- []byte, positionCallback func(position time.Duration)) error {
-	var opuslen int16
+	if d.gain != nil {
+		sendCtx, stop := d.fadeOutOnCancel(ctx)
+		defer stop()
+		d.gain.begin(d.sendConfig.SendAhead + cap(opusChan) + gainLatencyFrames)
+		staged, closeStage, err := d.startGainStage(sendCtx, dca)
+		if err != nil {
+			d.logger.Error("Error al iniciar la etapa de ganancia", zap.Error(err))
+			return err
+		}
+		defer closeStage()
+		ctx, dca = sendCtx, staged
+	}
+
 	framesSent := 0
-	positionChan := make(chan int)
-	opusBuf := make([]byte, maxOpusBlockSize)
+	sendTimer := time.NewTimer(d.sendTimeout)
+	defer sendTimer.Stop()
+	var lastFrameSent time.Time
 
-	go func() {
+	var positionChan chan int
+	if positionCallback != nil {
+		positionChan = make(chan int)
 		defer close(positionChan)
-		for framesSent := range positionChan {
-			positionCallback(time.Duration(framesSent) * frameLength)
+		go func() {
+			for framesSent := range positionChan {
+				positionCallback(time.Duration(framesSent) * frameLength)
+			}
+		}()
+	}
+
+	// Las lecturas van a través de un bufio.Reader para no hacer una lectura del pipe por cada encabezado y frame.
+	reader, ok := dca.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReaderSize(dca, dcaReadBufferSize)
+	}
+	header := make([]byte, 2)
+	nextFrame := func() (*[]byte, error) {
+		return d.readFrame(reader, header)
+	}
+	if d.sendConfig.SendAhead > 0 {
+		// Con lectura por adelantado, los frames se leen en otra goroutine y el JitterBuffer decide cuántos
+		// acumular antes de enviar.
+		readCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		jitterMax := d.sendConfig.JitterMax
+		if jitterMax <= 0 {
+			jitterMax = d.sendConfig.SendAhead
 		}
-	}()
+		buffer := newFrameBuffer(readCtx, d.sendConfig.SendAhead, NewJitterBuffer(d.sendConfig.JitterMin, jitterMax), nextFrame)
+		nextFrame = func() (*[]byte, error) {
+			return buffer.next(ctx)
+		}
+	}
 
-	for {
-		err := binary.Read(dca, binary.LittleEndian, &opuslen)
+	// Con el timer propio se espera un tick antes de cada tanda de FrameBatch frames; si no, el ritmo lo marca la
+	// conexión de voz al aceptar cada frame.
+	batch := max(d.sendConfig.FrameBatch, 1)
+	var tick <-chan time.Time
+	if d.sendConfig.Timer == TimerTicker {
+		ticker := time.NewTicker(time.Duration(batch) * frameLength)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	batchLeft := 0
 
-		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
-			d.logger.Error("Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", zap.Error(err))
+	inFlight := newInFlightFrames(cap(opusChan))
+	// Con ganancia, al pausar se sigue enviando hasta pauseAt, mientras baja el volumen.
+	fadingOut := false
+	var pauseAt time.Time
+	for {
+		if d.pause != nil && d.pause.Paused() {
+			if d.gain != nil && !fadingOut {
+				fadingOut = true
+				pauseAt = time.Now().Add(d.gain.fadeOut())
+			}
+			if !time.Now().Before(pauseAt) {
+				if err := d.pause.Wait(ctx); err != nil {
+					return nil
+				}
+				// El silencio de la pausa no cuenta como un frame tarde.
+				lastFrameSent = time.Time{}
+			}
+		}
+		if fadingOut && !d.pause.Paused() {
+			fadingOut = false
+			d.gain.fadeIn()
+		}
+		frame, err := nextFrame()
+		if errors.Is(err, errEndOfStream) {
 			return nil
 		}
 		if err != nil {
-			d.logger.Error("Error mientras se leia la longitud de DCA", zap.Error(err))
+			if ctx.Err() != nil {
+				return nil
+			}
 			return err
 		}
 
-		var bytesRead int
-		var opusData []byte
-		for bytesRead < int(opuslen) {
-			n, err := dca.Read(opusBuf[:min(int(opuslen)-bytesRead, maxOpusBlockSize)])
-			if err != nil {
-				d.logger.Error("Error mientras se leia PCM de DCA:", zap.Error(err))
-				return err
+		if tick != nil && batchLeft == 0 {
+			select {
+			case <-tick:
+				batchLeft = batch
+			case <-ctx.Done():
+				return nil
 			}
-			opusData = append(opusData, opusBuf[:n]...)
-			bytesRead += n
 		}
+		batchLeft--
 
-		for len(opusData) > maxOpusChunkSize {
-			opusChan <- opusData[:maxOpusChunkSize]
-			opusData = opusData[maxOpusChunkSize:]
+		// Si la conexión de voz no acepta el frame a tiempo, se descarta en lugar de bloquear la transmisión.
+		resetTimer(sendTimer, d.sendTimeout)
+		sent := true
+		sentAny := false
+		opusData := *frame
+		for len(opusData) > 0 && sent {
+			chunk := opusData[:min(len(opusData), maxOpusChunkSize)]
+			opusData = opusData[len(chunk):]
+			select {
+			case opusChan <- chunk:
+				inFlight.sent()
+				sentAny = true
+			case <-sendTimer.C:
+				sent = false
+			case <-ctx.Done():
+				return nil
+			}
 		}
-		if len(opusData) > 0 {
-			opusChan <- opusData
+		inFlight.add(frame, sentAny)
+		d.observeFrame(sent, &lastFrameSent)
+		if d.gain != nil {
+			d.gain.frameSent()
 		}
 
 		framesSent++
 
-		if positionCallback != nil && framesSent%50 == 0 {
+		if positionChan != nil && framesSent%50 == 0 {
 			positionChan <- framesSent
 		}
 
@@ -88,3 +272,98 @@ func (d *DCAStreamerImpl) StreamDCAData(ctx context.Context, dca io.Reader, opus
 		}
 	}
 }
+
+// fadeOutOnCancel devuelve un contexto para el envío que, al cancelarse ctx, se cancela recién cuando terminó de
+// enviarse el fundido de salida de la ganancia, o enseguida si el envío está pausado. stop lo cancela enseguida.
+func (d *DCAStreamerImpl) fadeOutOnCancel(ctx context.Context) (sendCtx context.Context, stop context.CancelFunc) {
+	sendCtx, stop = context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sendCtx.Done():
+			return
+		}
+		if d.pause != nil && d.pause.Paused() {
+			// Pausado no se envía audio, así que no hay nada que bajar.
+			stop()
+			return
+		}
+		tail := time.NewTimer(d.gain.fadeOut())
+		defer tail.Stop()
+		select {
+		case <-tail.C:
+		case <-sendCtx.Done():
+		}
+		stop()
+	}()
+	return sendCtx, stop
+}
+
+// readFrame lee el próximo frame del flujo DCA en un buffer del pool, usando header para el encabezado. Devuelve
+// errEndOfStream cuando el flujo termina entre frames.
+func (d *DCAStreamerImpl) readFrame(dca io.Reader, header []byte) (*[]byte, error) {
+	_, err := io.ReadFull(dca, header)
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		d.logger.Error("Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", zap.Error(err))
+		return nil, errEndOfStream
+	}
+	if err != nil {
+		d.logger.Error("Error mientras se leia la longitud de DCA", zap.Error(err))
+		return nil, err
+	}
+
+	opuslen := max(int(int16(binary.LittleEndian.Uint16(header))), 0)
+	frame := getFrame(opuslen)
+	if _, err := io.ReadFull(dca, *frame); err != nil {
+		putFrame(frame)
+		d.logger.Error("Error mientras se leia PCM de DCA:", zap.Error(err))
+		return nil, err
+	}
+	return frame, nil
+}
+
+// SkipFrames descarta del flujo DCA los frames de los primeros d de audio, para que la reproducción empiece en esa
+// posición.
+func SkipFrames(dca io.Reader, d time.Duration) error {
+	var opuslen int16
+	for skipped := time.Duration(0); skipped < d; skipped += frameLength {
+		if err := binary.Read(dca, binary.LittleEndian, &opuslen); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(io.Discard, dca, int64(opuslen)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetTimer reinicia el timer descartando un vencimiento pendiente.
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
+
+// observeFrame registra las métricas de temporización del frame recién procesado.
+func (d *DCAStreamerImpl) observeFrame(sent bool, lastFrameSent *time.Time) {
+	if d.metrics == nil {
+		return
+	}
+	if !sent {
+		d.metrics.IncDroppedFrames(d.guildID)
+		return
+	}
+	now := time.Now()
+	if !lastFrameSent.IsZero() {
+		delay := now.Sub(*lastFrameSent)
+		d.metrics.ObserveFrameDelay(d.guildID, delay)
+		if delay > lateFrameThreshold {
+			d.metrics.IncLateFrames(d.guildID)
+		}
+	}
+	*lastFrameSent = now
+}
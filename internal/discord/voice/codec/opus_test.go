@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"io"
 	"testing"
 	"time"
 )
@@ -30,6 +33,10 @@ func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) With(fields ...zap.Field) {
 	m.Called(fields)
 }
@@ -162,3 +169,180 @@ func TestStreamDCAData_ReturnsNilOnContextCancellation(t *testing.T) {
 		t.Errorf("StreamDCAData returned an unexpected error: %v", err)
 	}
 }
+
+type MockAudioMetrics struct {
+	mock.Mock
+}
+
+func (m *MockAudioMetrics) Describe(chan<- *prometheus.Desc) {}
+
+func (m *MockAudioMetrics) Collect(chan<- prometheus.Metric) {}
+
+func (m *MockAudioMetrics) ObserveFrameDelay(guildID string, delay time.Duration) {
+	m.Called(guildID, delay)
+}
+
+func (m *MockAudioMetrics) IncDroppedFrames(guildID string) {
+	m.Called(guildID)
+}
+
+func (m *MockAudioMetrics) IncLateFrames(guildID string) {
+	m.Called(guildID)
+}
+
+func TestStreamDCAData_RecordsFrameMetrics(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockMetrics := new(MockAudioMetrics)
+	clientDCA := NewDCAStreamerImpl(mockLogger).WithMetrics(mockMetrics, "guild-1")
+	frame := []byte{0x02, 0x00, 0x01, 0x02}
+	dca := bytes.NewReader(append(append([]byte{}, frame...), frame...))
+	opusChan := make(chan []byte, 2)
+	mockLogger.On("Error", "Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", mock.AnythingOfType("[]zapcore.Field")).Return()
+	mockMetrics.On("ObserveFrameDelay", "guild-1", mock.AnythingOfType("time.Duration")).Return().Once()
+
+	err := clientDCA.StreamDCAData(context.Background(), dca, opusChan, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, opusChan, 2)
+	mockMetrics.AssertExpectations(t)
+	mockMetrics.AssertNotCalled(t, "IncDroppedFrames", mock.Anything)
+}
+
+func TestStreamDCAData_DropsFramesWhenConnectionIsBlocked(t *testing.T) {
+	mockLogger := new(MockLogger)
+	mockMetrics := new(MockAudioMetrics)
+	clientDCA := NewDCAStreamerImpl(mockLogger).WithMetrics(mockMetrics, "guild-1")
+	clientDCA.sendTimeout = 10 * time.Millisecond
+	dca := bytes.NewReader([]byte{0x02, 0x00, 0x01, 0x02})
+	opusChan := make(chan []byte)
+	mockLogger.On("Error", "Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", mock.AnythingOfType("[]zapcore.Field")).Return()
+	mockMetrics.On("IncDroppedFrames", "guild-1").Return().Once()
+
+	err := clientDCA.StreamDCAData(context.Background(), dca, opusChan, nil)
+
+	assert.NoError(t, err)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestSkipFrames(t *testing.T) {
+	// Tres frames de 20ms con un byte cada uno.
+	dca := bytes.NewReader([]byte{0x01, 0x00, 0xaa, 0x01, 0x00, 0xbb, 0x01, 0x00, 0xcc})
+
+	assert.NoError(t, SkipFrames(dca, 40*time.Millisecond))
+	rest := make([]byte, 3)
+	_, err := dca.Read(rest)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x00, 0xcc}, rest)
+
+	assert.ErrorIs(t, SkipFrames(dca, time.Second), io.EOF)
+}
+
+func TestStreamDCAData_SendsAheadWithTicker(t *testing.T) {
+	mockLogger := new(MockLogger)
+	clientDCA := NewDCAStreamerImpl(mockLogger).WithSendConfig(SendConfig{Timer: TimerTicker, FrameBatch: 2, SendAhead: 4, JitterMin: 2})
+	frame := []byte{0x02, 0x00, 0x01, 0x02}
+	var data []byte
+	for i := 0; i < 5; i++ {
+		data = append(data, frame...)
+	}
+	opusChan := make(chan []byte, 5)
+	mockLogger.On("Error", "Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", mock.AnythingOfType("[]zapcore.Field")).Return()
+
+	err := clientDCA.StreamDCAData(context.Background(), bytes.NewReader(data), opusChan, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, opusChan, 5)
+	assert.Equal(t, []byte{0x01, 0x02}, <-opusChan)
+}
+
+func TestStreamDCAData_SendAheadReturnsReadErrors(t *testing.T) {
+	mockLogger := new(MockLogger)
+	clientDCA := NewDCAStreamerImpl(mockLogger).WithSendConfig(SendConfig{SendAhead: 2})
+	mockLogger.On("Error", "Error mientras se leia la longitud de DCA", mock.AnythingOfType("[]zapcore.Field")).Return()
+
+	err := clientDCA.StreamDCAData(context.Background(), &errorReader{errors.New("test error")}, make(chan []byte, 1), nil)
+
+	assert.EqualError(t, err, "test error")
+}
+
+// dcaFrames arma un flujo DCA de n frames de size bytes, en el que cada frame está lleno con su número.
+func dcaFrames(n, size int) []byte {
+	var data []byte
+	for i := 0; i < n; i++ {
+		data = append(data, byte(size), byte(size>>8))
+		data = append(data, bytes.Repeat([]byte{byte(i)}, size)...)
+	}
+	return data
+}
+
+func TestStreamDCAData_ReusedFramesAreNotOverwrittenWhileInUse(t *testing.T) {
+	mockLogger := new(MockLogger)
+	clientDCA := NewDCAStreamerImpl(mockLogger)
+	mockLogger.On("Error", "Error EOF o EOF inesperado encontrado durante la transmisión de datos DCA:", mock.AnythingOfType("[]zapcore.Field")).Return()
+	opusChan := make(chan []byte, 2)
+	received := make(chan []byte)
+	go func() {
+		defer close(received)
+		for frame := range opusChan {
+			// Mientras el consumidor tarda, el canal se llena con frames que no se pueden sobrescribir.
+			time.Sleep(100 * time.Microsecond)
+			received <- append([]byte(nil), frame...)
+		}
+	}()
+
+	go func() {
+		assert.NoError(t, clientDCA.StreamDCAData(context.Background(), bytes.NewReader(dcaFrames(200, 40)), opusChan, nil))
+		close(opusChan)
+	}()
+
+	i := 0
+	for frame := range received {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 40), frame)
+		i++
+	}
+	assert.Equal(t, 200, i)
+}
+
+// nopLogger descarta los logs, para medir las asignaciones del envío sin las del mock.
+type nopLogger struct{}
+
+func (nopLogger) Error(string, ...zap.Field) {}
+func (nopLogger) Info(string, ...zap.Field)  {}
+func (nopLogger) Warn(string, ...zap.Field)  {}
+func (nopLogger) With(...zap.Field)          {}
+
+func TestStreamDCAData_ReusesFrameBuffers(t *testing.T) {
+	clientDCA := NewDCAStreamerImpl(nopLogger{})
+	data := dcaFrames(1000, 120)
+	opusChan := make(chan []byte)
+	go func() {
+		for range opusChan {
+		}
+	}()
+	defer close(opusChan)
+
+	allocs := testing.AllocsPerRun(5, func() {
+		_ = clientDCA.StreamDCAData(context.Background(), bytes.NewReader(data), opusChan, nil)
+	})
+
+	// Las asignaciones son las de cada canción (timer, buffer de lectura), no las de cada frame. El margen cubre al
+	// detector de carreras, que descarta al azar parte de lo que se devuelve al pool.
+	assert.Less(t, allocs, 1000.0)
+}
+
+func BenchmarkStreamDCAData(b *testing.B) {
+	clientDCA := NewDCAStreamerImpl(nopLogger{})
+	data := dcaFrames(500, 160)
+	opusChan := make(chan []byte, 2)
+	go func() {
+		for range opusChan {
+		}
+	}()
+	defer close(opusChan)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = clientDCA.StreamDCAData(context.Background(), bytes.NewReader(data), opusChan, nil)
+	}
+}
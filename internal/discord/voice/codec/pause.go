@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"context"
+	"sync"
+)
+
+// Pause suspende el envío de frames de los DCAStreamer que la reciben (ver DCAStreamerImpl.WithPause). Mientras está
+// pausado el flujo queda abierto y sin leer, así que al retomarlo sigue desde el mismo frame.
+type Pause struct {
+	mu      sync.Mutex
+	resumed chan struct{} // resumed es nil si no está pausado; se cierra al retomar.
+}
+
+// NewPause crea una pausa sin pausar.
+func NewPause() *Pause {
+	return &Pause{}
+}
+
+// Set pausa o retoma el envío y devuelve si cambió el estado.
+func (p *Pause) Set(paused bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if paused == (p.resumed != nil) {
+		return false
+	}
+	if paused {
+		p.resumed = make(chan struct{})
+	} else {
+		close(p.resumed)
+		p.resumed = nil
+	}
+	return true
+}
+
+// Paused indica si el envío está pausado.
+func (p *Pause) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resumed != nil
+}
+
+// Wait espera a que se retome el envío, si está pausado. Devuelve el error del contexto si se cancela antes.
+func (p *Pause) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	resumed := p.resumed
+	p.mu.Unlock()
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
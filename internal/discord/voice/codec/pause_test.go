@@ -0,0 +1,112 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPause_Set(t *testing.T) {
+	pause := NewPause()
+	assert.False(t, pause.Paused())
+	assert.True(t, pause.Set(true))
+	assert.False(t, pause.Set(true))
+	assert.True(t, pause.Paused())
+	assert.True(t, pause.Set(false))
+	assert.False(t, pause.Set(false))
+	assert.NoError(t, pause.Wait(context.Background()))
+}
+
+func TestPause_WaitReturnsOnCancel(t *testing.T) {
+	pause := NewPause()
+	pause.Set(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, pause.Wait(ctx), context.Canceled)
+}
+
+func TestStreamDCAData_WaitsWhilePaused(t *testing.T) {
+	pause := NewPause()
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithPause(pause)
+	pause.Set(true)
+	opusChan := make(chan []byte, 1)
+	done := make(chan error)
+	go func() {
+		done <- clientDCA.StreamDCAData(context.Background(), bytes.NewReader(dcaFrames(3, 10)), opusChan, nil)
+	}()
+
+	select {
+	case <-opusChan:
+		t.Fatal("se envió un frame con la reproducción pausada")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pause.Set(false)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i)}, 10), <-opusChan)
+	}
+	assert.NoError(t, <-done)
+}
+
+func TestStreamDCAData_CancelWhilePaused(t *testing.T) {
+	pause := NewPause()
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithPause(pause)
+	pause.Set(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- clientDCA.StreamDCAData(ctx, bytes.NewReader(dcaFrames(3, 10)), make(chan []byte, 1), nil)
+	}()
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestStreamDCAData_FadesAroundPause(t *testing.T) {
+	pause := NewPause()
+	gain := NewGain(time.Second)
+	clientDCA := NewDCAStreamerImpl(nopLogger{}).WithPause(pause).WithGain(gain)
+	// Sin ffmpeg, el codificador falso descarta el audio con la ganancia aplicada y devuelve siempre el mismo flujo.
+	encoded := filepath.Join(t.TempDir(), "audio.ogg")
+	var ogg bytes.Buffer
+	assert.NoError(t, writeOggOpus(&ogg, bytes.NewReader(dcaFrames(1000, 10))))
+	assert.NoError(t, os.WriteFile(encoded, ogg.Bytes(), 0o600))
+	clientDCA.command = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		if slices.Equal(args, gainEncodeArgs) {
+			return exec.CommandContext(ctx, "sh", "-c", `cat >/dev/null & exec cat "$1"`, "sh", encoded)
+		}
+		return exec.CommandContext(ctx, "cat")
+	}
+	opusChan := make(chan []byte)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- clientDCA.StreamDCAData(ctx, bytes.NewReader(dcaFrames(1000, 10)), opusChan, nil)
+	}()
+	go func() {
+		for {
+			select {
+			case <-opusChan:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	target := func() float64 {
+		gain.mu.Lock()
+		defer gain.mu.Unlock()
+		return gain.target
+	}
+	pause.Set(true)
+	assert.Eventually(t, func() bool { return target() == 0 }, time.Second, 5*time.Millisecond, "al pausar el audio baja")
+	pause.Set(false)
+	assert.Eventually(t, func() bool { return target() == 1 }, time.Second, 5*time.Millisecond, "al retomar el audio vuelve a subir")
+	cancel()
+	assert.NoError(t, <-done)
+}
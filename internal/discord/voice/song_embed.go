@@ -4,8 +4,61 @@ import (
 	"fmt"
 	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
 	"github.com/bwmarrin/discordgo"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	// seekCustomIDPrefix es el prefijo del CustomID de los botones para mover la canción; le sigue el salto en segundos.
+	seekCustomIDPrefix = "seek:"
+	// PreviousChapterCustomID y NextChapterCustomID son los CustomID de los botones para pasar al capítulo anterior
+	// y al siguiente de la canción.
+	PreviousChapterCustomID = "chapter:previous"
+	NextChapterCustomID     = "chapter:next"
+)
+
+// SeekSteps son los saltos de los botones para mover la canción del mensaje de reproducción.
+var SeekSteps = []time.Duration{-60 * time.Second, -15 * time.Second, 15 * time.Second, 60 * time.Second}
+
+// SeekCustomID devuelve el CustomID del botón que mueve la canción step.
+func SeekCustomID(step time.Duration) string {
+	return seekCustomIDPrefix + strconv.Itoa(int(step.Seconds()))
+}
+
+// ParseSeekCustomID devuelve el salto del botón para mover la canción con ese CustomID.
+func ParseSeekCustomID(customID string) (time.Duration, bool) {
+	if !strings.HasPrefix(customID, seekCustomIDPrefix) {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(strings.TrimPrefix(customID, seekCustomIDPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// GeneratePlayingSongControls genera los botones del mensaje de reproducción para retroceder y adelantar la canción y,
+// si la canción tiene capítulos, para pasar al capítulo anterior o al siguiente.
+func GeneratePlayingSongControls(song *Song) []discordgo.MessageComponent {
+	buttons := make([]discordgo.MessageComponent, 0, len(SeekSteps))
+	for _, step := range SeekSteps {
+		label := fmt.Sprintf("⏪ %ds", int(-step.Seconds()))
+		if step > 0 {
+			label = fmt.Sprintf("%ds ⏩", int(step.Seconds()))
+		}
+		buttons = append(buttons, discordgo.Button{Label: label, Style: discordgo.SecondaryButton, CustomID: SeekCustomID(step)})
+	}
+	controls := []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+	if song != nil && len(song.Chapters) > 0 {
+		controls = append(controls, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "⏮️ Capítulo anterior", Style: discordgo.SecondaryButton, CustomID: PreviousChapterCustomID},
+			discordgo.Button{Label: "Capítulo siguiente ⏭️", Style: discordgo.SecondaryButton, CustomID: NextChapterCustomID},
+		}})
+	}
+	return controls
+}
+
 // GeneratePlayingSongEmbed un mensaje embed para mostrar que se está agregando una canción a la cola de reproducción.
 func GeneratePlayingSongEmbed(message *PlayMessage) *discordgo.MessageEmbed {
 	if message == nil || message.Song == nil {
@@ -18,6 +71,9 @@ func GeneratePlayingSongEmbed(message *PlayMessage) *discordgo.MessageEmbed {
 		Title:       message.Song.GetHumanName(),
 		Description: fmt.Sprintf("%s\n%s / %s", progressBar, utils.FmtDuration(message.Position), utils.FmtDuration(message.Song.Duration)),
 	}
+	if chapter := message.Song.ChapterAt(message.Position); chapter >= 0 {
+		embed.Description += fmt.Sprintf("\n📖 %d/%d: %s", chapter+1, len(message.Song.Chapters), message.Song.Chapters[chapter].Title)
+	}
 	if message.Song.ThumbnailURL != nil {
 		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
 			URL: *message.Song.ThumbnailURL,
@@ -2,6 +2,7 @@ package voice
 
 import (
 	"github.com/Tomas-vilte/GoMusicBot/internal/utils"
+	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -92,3 +93,35 @@ func TestGeneratePlayingSongEmbed_NilRequestedBy(t *testing.T) {
 	assert.NotNil(t, embed)
 	assert.Nil(t, embed.Footer)
 }
+
+func TestGeneratePlayingSongControls(t *testing.T) {
+	controls := GeneratePlayingSongControls(&Song{Title: "Sin capítulos"})
+	assert.Len(t, controls, 1)
+	row := controls[0].(discordgo.ActionsRow)
+
+	assert.Len(t, row.Components, len(SeekSteps))
+	first := row.Components[0].(discordgo.Button)
+	last := row.Components[len(row.Components)-1].(discordgo.Button)
+	assert.Equal(t, "⏪ 60s", first.Label)
+	assert.Equal(t, "60s ⏩", last.Label)
+
+	step, ok := ParseSeekCustomID(first.CustomID)
+	assert.True(t, ok)
+	assert.Equal(t, -time.Minute, step)
+	_, ok = ParseSeekCustomID("add_song_playlist")
+	assert.False(t, ok)
+
+	controls = GeneratePlayingSongControls(&Song{Chapters: []Chapter{{Title: "Intro"}, {Start: time.Minute, Title: "Tema"}}})
+	assert.Len(t, controls, 2)
+	chapters := controls[1].(discordgo.ActionsRow)
+	assert.Equal(t, PreviousChapterCustomID, chapters.Components[0].(discordgo.Button).CustomID)
+	assert.Equal(t, NextChapterCustomID, chapters.Components[1].(discordgo.Button).CustomID)
+}
+
+func TestGeneratePlayingSongEmbed_Chapter(t *testing.T) {
+	embed := GeneratePlayingSongEmbed(&PlayMessage{
+		Song:     &Song{Title: "Mix", Duration: 3 * time.Minute, Chapters: []Chapter{{Title: "Intro"}, {Start: time.Minute, Title: "Tema"}}},
+		Position: 90 * time.Second,
+	})
+	assert.Contains(t, embed.Description, "📖 2/2: Tema")
+}
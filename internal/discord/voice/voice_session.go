@@ -15,6 +15,24 @@ type (
 		SendAudio(ctx context.Context, reader io.Reader, positionCallback func(time.Duration)) error
 	}
 
+	// TrackPlayer lo implementan las sesiones de voz que reproducen la canción por su cuenta (por ejemplo,
+	// delegándola a Lavalink) en lugar de recibir el audio codificado desde el bot.
+	TrackPlayer interface {
+		PlayTrack(ctx context.Context, song *Song, positionCallback func(time.Duration)) error
+	}
+
+	// VolumeController lo implementan las sesiones de voz que pueden cambiar el volumen durante la reproducción.
+	// El volumen es un porcentaje: 100 es el volumen original.
+	VolumeController interface {
+		SetVolume(ctx context.Context, volume int) error
+	}
+
+	// PauseController lo implementan las sesiones de voz que reproducen la canción por su cuenta y pueden pausarla
+	// sin salir del canal. Las que reciben el audio del bot se pausan dejando de enviarlo.
+	PauseController interface {
+		SetPaused(ctx context.Context, paused bool) error
+	}
+
 	// PlayMessage es el mensaje que se enviará al canal de texto para mostrar la canción que se está reproduciendo actualmente.
 	PlayMessage struct {
 		Song     *Song
@@ -31,6 +49,13 @@ type (
 		Duration      time.Duration
 		StartPosition time.Duration
 		RequestedBy   *string
+		RequestedByID string     `json:",omitempty"` // RequestedByID es el ID de Discord de quien pidió la canción, si la pidió un usuario.
+		Uploader      string     `json:",omitempty"` // Uploader es el nombre del canal que subió la canción, si la fuente lo informa.
+		UploaderID    string     `json:",omitempty"` // UploaderID es el ID del canal que subió la canción (en YouTube, UC...), si la fuente lo informa.
+		Explicit      bool       `json:",omitempty"` // Explicit indica que la fuente marcó la canción como contenido explícito o para mayores.
+		Chapters      []Chapter  `json:",omitempty"` // Chapters son los capítulos de la canción, si la fuente los informa.
+		LookedUpAt    *time.Time `json:",omitempty"` // LookedUpAt es cuándo se obtuvo la metadata de la fuente, si se sabe.
+		Unresolved    bool       `json:",omitempty"` // Unresolved indica que de la canción solo se sabe lo que informa su playlist; el resto de la metadata se busca antes de reproducirla.
 	}
 
 	// PlayedSong representa una canción que ha sido reproducida.
@@ -0,0 +1,122 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// Acciones de /settings voicechannels.
+const (
+	voiceChannelsAllow = "allow"
+	voiceChannelsDeny  = "deny"
+	voiceChannelsReset = "reset"
+	voiceChannelsList  = "list"
+)
+
+// voiceChannelAllowed indica si la configuración del servidor deja que el bot entre al canal de voz.
+func (handler *InteractionHandler) voiceChannelAllowed(guildID, voiceChannelID string) bool {
+	if handler.settings == nil {
+		return true
+	}
+	return handler.guildSettings(guildID).VoiceChannels.Allows(voiceChannelID)
+}
+
+// respondVoiceChannelDenied responde al usuario si el servidor no deja que el bot entre al canal de voz. Devuelve
+// true si respondió.
+func (handler *InteractionHandler) respondVoiceChannelDenied(ic *discordgo.InteractionCreate, voiceChannelID string) bool {
+	if handler.voiceChannelAllowed(ic.GuildID, voiceChannelID) {
+		return false
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, voiceChannelDeniedMessage(voiceChannelID)); err != nil {
+		handler.logger.Error("falló al responder con el error del canal de voz", zap.Error(err))
+	}
+	return true
+}
+
+func voiceChannelDeniedMessage(voiceChannelID string) string {
+	return fmt.Sprintf("🔒 En este servidor no puedo entrar a <#%s>", voiceChannelID)
+}
+
+// SetVoiceChannels configura los canales de voz a los que el bot puede entrar: permite o prohíbe uno, lo saca de
+// las reglas o lista las reglas. Solo disponible para administradores.
+func (handler *InteractionHandler) SetVoiceChannels(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetVoiceChannels")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	action := options["action"].StringValue()
+	var channelID string
+	if option, ok := options["channel"]; ok {
+		channelID = option.Value.(string)
+	}
+	if action != voiceChannelsList && channelID == "" {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Elegí el canal de voz"); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+
+	var message string
+	guild, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) {
+		message = updateVoiceChannels(&g.VoiceChannels, action, channelID)
+	})
+	if err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	} else {
+		message += "\n" + describeVoiceChannels(guild.VoiceChannels)
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
+
+// updateVoiceChannels aplica la acción a las reglas de canales de voz y devuelve el mensaje con el resultado.
+func updateVoiceChannels(channels *settings.VoiceChannels, action, channelID string) string {
+	switch action {
+	case voiceChannelsAllow:
+		if !channels.Allow(channelID) {
+			return fmt.Sprintf("🤷🏽 <#%s> ya estaba permitido", channelID)
+		}
+		return fmt.Sprintf("⚙️ Permitido: <#%s>", channelID)
+	case voiceChannelsDeny:
+		if !channels.Deny(channelID) {
+			return fmt.Sprintf("🤷🏽 <#%s> ya estaba prohibido", channelID)
+		}
+		return fmt.Sprintf("⚙️ Prohibido: <#%s>", channelID)
+	case voiceChannelsReset:
+		if !channels.Reset(channelID) {
+			return fmt.Sprintf("🤷🏽 <#%s> no tenía reglas", channelID)
+		}
+		return fmt.Sprintf("⚙️ <#%s> ya no tiene reglas", channelID)
+	default:
+		return "⚙️ Canales de voz"
+	}
+}
+
+// describeVoiceChannels describe a qué canales de voz puede entrar el bot.
+func describeVoiceChannels(channels settings.VoiceChannels) string {
+	mentions := func(ids []string) string {
+		formatted := make([]string, len(ids))
+		for i, id := range ids {
+			formatted[i] = fmt.Sprintf("<#%s>", id)
+		}
+		return strings.Join(formatted, ", ")
+	}
+	var lines []string
+	if len(channels.Allowed) > 0 {
+		lines = append(lines, "Solo entro a: "+mentions(channels.Allowed))
+	} else {
+		lines = append(lines, "Entro a cualquier canal de voz")
+	}
+	if len(channels.Denied) > 0 {
+		lines = append(lines, "Nunca entro a: "+mentions(channels.Denied))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package discord
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUpdateVoiceChannels(t *testing.T) {
+	var channels settings.VoiceChannels
+
+	assert.Equal(t, "⚙️ Prohibido: <#afk>", updateVoiceChannels(&channels, voiceChannelsDeny, "afk"))
+	assert.Equal(t, "🤷🏽 <#afk> ya estaba prohibido", updateVoiceChannels(&channels, voiceChannelsDeny, "afk"))
+	assert.Equal(t, "⚙️ Permitido: <#musica>", updateVoiceChannels(&channels, voiceChannelsAllow, "musica"))
+	assert.Equal(t, "Solo entro a: <#musica>\nNunca entro a: <#afk>", describeVoiceChannels(channels))
+
+	assert.Equal(t, "⚙️ <#musica> ya no tiene reglas", updateVoiceChannels(&channels, voiceChannelsReset, "musica"))
+	assert.Equal(t, "🤷🏽 <#musica> no tenía reglas", updateVoiceChannels(&channels, voiceChannelsReset, "musica"))
+	assert.Equal(t, "Entro a cualquier canal de voz\nNunca entro a: <#afk>", describeVoiceChannels(channels))
+}
+
+func TestVoiceChannelAllowed(t *testing.T) {
+	handler := &InteractionHandler{}
+	assert.True(t, handler.voiceChannelAllowed("1", "afk"), "sin configuración por servidor puede entrar a cualquier canal")
+
+	handler.settings = settings.NewInMemoryStore()
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.VoiceChannels.Deny("afk") })
+	assert.NoError(t, err)
+	assert.False(t, handler.voiceChannelAllowed("1", "afk"))
+	assert.True(t, handler.voiceChannelAllowed("1", "general"))
+	assert.True(t, handler.voiceChannelAllowed("2", "afk"))
+}
@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// limitVolume limita el volumen pedido al volumen máximo configurado en el servidor.
+func (handler *InteractionHandler) limitVolume(guildID string, volume int) int {
+	return handler.guildSettings(guildID).CapVolume(volume)
+}
+
+// volumeSupported indica si el backend de audio permite cambiar el volumen: Lavalink o, sin Lavalink, la etapa de
+// ganancia del envío de audio.
+func (handler *InteractionHandler) volumeSupported() bool {
+	return handler.lavalink != nil || handler.cfg.Voice.Gain
+}
+
+// SetMaxVolume configura el volumen máximo de la reproducción del servidor, que se aplica a cualquier cambio de
+// volumen, y a la reproducción en curso. Sin volumen se quita el tope. Solo disponible para administradores.
+func (handler *InteractionHandler) SetMaxVolume(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetMaxVolume")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+	if !handler.volumeSupported() {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🚫 El backend de audio no permite cambiar el volumen, así que no hay tope que poner"); err != nil {
+			logger.Error("falló al responder con la configuración", zap.Error(err))
+		}
+		return
+	}
+
+	var maxVolume int
+	if option, ok := commandOptions(opt)["volume"]; ok {
+		maxVolume = int(option.IntValue())
+	}
+	message := "⚙️ Sin volumen máximo"
+	if maxVolume > 0 {
+		message = fmt.Sprintf("⚙️ Volumen máximo: %d%%", maxVolume)
+	}
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.MaxVolume = maxVolume }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	} else if player, err := handler.playerFor(ic.GuildID); err == nil {
+		if err := player.ApplyVolumeLimit(ctx); err != nil {
+			logger.Warn("No se pudo aplicar el volumen máximo a la reproducción en curso", zap.Error(err))
+		}
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLimitVolume(t *testing.T) {
+	handler, _, _ := newDuplicateTestHandler(settings.DuplicatesConfirm)
+	assert.Equal(t, 150, handler.limitVolume("1", 150))
+
+	_, err := settings.Update(handler.settings, "1", func(g *settings.Guild) { g.MaxVolume = 80 })
+	require.NoError(t, err)
+	assert.Equal(t, 80, handler.limitVolume("1", 150))
+	assert.Equal(t, 50, handler.limitVolume("1", 50))
+	assert.Equal(t, 150, handler.limitVolume("2", 150))
+}
+
+func TestVolumeSupported(t *testing.T) {
+	handler := &InteractionHandler{cfg: &config.Config{}}
+	assert.False(t, handler.volumeSupported(), "sin Lavalink ni etapa de ganancia no hay volumen")
+
+	handler.cfg.Voice.Gain = true
+	assert.True(t, handler.volumeSupported())
+}
+
+func TestSetVolume_WithGain(t *testing.T) {
+	_, _, player := newDuplicateTestHandler(settings.DuplicatesConfirm)
+	assert.ErrorIs(t, player.SetVolume(context.Background(), 50), bot.ErrVolumeNotSupported)
+
+	// Sin un backend que controle el volumen, se aplica en la ganancia del envío de audio.
+	player.WithGain(codec.NewGain(0))
+	assert.NoError(t, player.SetVolume(context.Background(), 50))
+	assert.Equal(t, 50, player.Volume())
+}
+
+func TestUpdateGuildSettings(t *testing.T) {
+	handler, _, _ := newDuplicateTestHandler(settings.DuplicatesConfirm)
+	handler.cfg = &config.Config{}
+
+	guild, err := handler.UpdateGuildSettings(context.Background(), "1", func(g *settings.Guild) { g.VoteSkip = 50 })
+	require.NoError(t, err)
+	assert.Equal(t, 50, guild.VoteSkip)
+	assert.Equal(t, settings.DuplicatesConfirm, guild.Duplicates, "el resto de la configuración no cambia")
+
+	_, err = handler.UpdateGuildSettings(context.Background(), "1", func(g *settings.Guild) { g.MaxVolume = 80 })
+	assert.ErrorIs(t, err, control.ErrVolumeNotSupported, "sin volumen no hay tope que poner")
+	guild, err = handler.GuildSettings("1")
+	require.NoError(t, err)
+	assert.Zero(t, guild.MaxVolume)
+	assert.Equal(t, 50, guild.VoteSkip)
+
+	handler.cfg.Voice.Gain = true
+	guild, err = handler.UpdateGuildSettings(context.Background(), "1", func(g *settings.Guild) { g.MaxVolume = 80 })
+	require.NoError(t, err)
+	assert.Equal(t, 80, guild.MaxVolume)
+}
@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/settings"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// voteSkip registra el voto de quien usó /skip para saltar la canción actual y devuelve el mensaje de respuesta. La
+// canción se salta cuando votó el porcentaje configurado de las personas del canal de voz del bot.
+func (handler *InteractionHandler) voteSkip(ctx context.Context, logger logging.Logger, s *discordgo.Session, ic *discordgo.InteractionCreate, g *discordgo.Guild, player *bot.GuildPlayer, guild settings.Guild) string {
+	channelID, err := player.GetVoiceChannel()
+	if err != nil {
+		logger.Error("falló al obtener el canal de voz", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al votar para saltar la canción")
+	}
+	if vs := getUsersVoiceState(g, ic.Member.User); vs == nil || vs.ChannelID != channelID {
+		return "🎧 Tenés que estar en el canal de voz del bot para votar"
+	}
+
+	listeners := voiceChannelMembers(g, channelID, s.State.User.ID)
+	vote, err := player.VoteSkip(ic.Member.User.ID, listeners, guild.SkipVotesNeeded(len(listeners)))
+	switch {
+	case errors.Is(err, bot.ErrNotPlaying):
+		return "🤷🏽 No hay ninguna canción sonando"
+	case err != nil:
+		logger.Error("falló al votar para saltar la canción", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al votar para saltar la canción")
+	case vote.Skipped:
+		return fmt.Sprintf("⏭️ Canción omitida por votación (%d/%d)", vote.Votes, vote.Needed)
+	case vote.Already:
+		return fmt.Sprintf("🗳️ Ya votaste para saltar esta canción (%d/%d)", vote.Votes, vote.Needed)
+	default:
+		return fmt.Sprintf("🗳️ %s votó para saltar la canción (%d/%d)", getMemberName(ic.Member), vote.Votes, vote.Needed)
+	}
+}
+
+// SetVoteSkip configura el porcentaje de las personas del canal de voz que tienen que votar con /skip para saltar la
+// canción. Sin porcentaje, cualquiera la salta. Solo disponible para administradores.
+func (handler *InteractionHandler) SetVoteSkip(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("SetVoteSkip")
+	if !handler.settingsAdmin(ic) {
+		return
+	}
+
+	var percent int
+	if option, ok := commandOptions(opt)["percent"]; ok {
+		percent = int(option.IntValue())
+	}
+	message := "⚙️ Sin votación: cualquiera puede saltar la canción"
+	if percent > 0 {
+		message = fmt.Sprintf("⚙️ Para saltar la canción tiene que votar el %d%% del canal de voz", percent)
+	}
+	if _, err := settings.Update(handler.settings, ic.GuildID, func(g *settings.Guild) { g.VoteSkip = percent }); err != nil {
+		logger.Error("falló al guardar la configuración del servidor", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al guardar la configuración")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la configuración", zap.Error(err))
+	}
+}
@@ -0,0 +1,259 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/discordmessenger"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/ytwatch"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// youtubeRequester es el nombre con el que se agregan a la cola los videos nuevos de los canales suscriptos.
+const youtubeRequester = "YouTube"
+
+// WithYouTubeWatch habilita los comandos /youtube para suscribir el servidor a canales de YouTube, cuyos videos
+// nuevos se anuncian con announcer.
+func (handler *InteractionHandler) WithYouTubeWatch(feeds ytwatch.Feeds, store ytwatch.Store, announcer discordmessenger.ChatMessageSender) *InteractionHandler {
+	handler.youtubeFeeds = feeds
+	handler.youtubeSubscriptions = store
+	handler.youtubeAnnouncer = announcer
+	return handler
+}
+
+// YouTubeSubscribe suscribe el servidor a un canal de YouTube. Desde ese momento, los videos que suba el canal se
+// anuncian y, si se pide, se suman a la cola. Solo disponible para administradores.
+func (handler *InteractionHandler) YouTubeSubscribe(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("YouTubeSubscribe")
+	if !handler.youtubeAdmin(ic) {
+		return
+	}
+
+	options := commandOptions(opt)
+	subscription := ytwatch.Subscription{
+		GuildID:       ic.GuildID,
+		TextChannelID: ic.ChannelID,
+		CreatedBy:     interactionUser(ic.Interaction).ID,
+		CreatedAt:     time.Now(),
+	}
+	if option, ok := options["queue"]; ok {
+		subscription.Queue = option.BoolValue()
+	}
+	if option, ok := options["announce"]; ok {
+		subscription.TextChannelID = option.Value.(string)
+	}
+	channelID, err := ytwatch.ParseChannelID(options["channel"].StringValue())
+	if err != nil {
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, "🤷🏽 Pasame el enlace del canal con su ID, como `https://www.youtube.com/channel/UC...`"); err != nil {
+			logger.Error("falló al responder con el error del canal de YouTube", zap.Error(err))
+		}
+		return
+	}
+	subscription.ChannelID = channelID
+
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	}); err != nil {
+		logger.Error("fallo al enviar la respuesta diferida", zap.Error(err))
+	}
+
+	go func() {
+		message := handler.subscribeYouTubeChannel(ctx, logger, subscription)
+		if err := handler.responseHandler.CreateFollowupMessage(handler.session, ic.Interaction, discordgo.WebhookParams{
+			Content: message,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		}); err != nil {
+			logger.Error("falló al responder con la suscripción al canal de YouTube", zap.Error(err))
+		}
+	}()
+}
+
+// subscribeYouTubeChannel lee el feed del canal y guarda la suscripción marcando sus videos actuales como ya
+// anunciados. Devuelve el mensaje para el usuario.
+func (handler *InteractionHandler) subscribeYouTubeChannel(ctx context.Context, logger logging.Logger, subscription ytwatch.Subscription) string {
+	feed, err := handler.youtubeFeeds.Uploads(ctx, subscription.ChannelID)
+	if err == nil {
+		subscription.Name = feed.Title
+		subscription.LastPublished = ytwatch.LatestPublished(feed.Uploads)
+		err = ytwatch.Add(handler.youtubeSubscriptions, subscription)
+	}
+
+	switch {
+	case errors.Is(err, ytwatch.ErrChannelNotFound):
+		return "🤷🏽 No encontré ese canal de YouTube"
+	case errors.Is(err, ytwatch.ErrTooMany):
+		return fmt.Sprintf("🙅 El servidor ya está suscripto a %d canales", ytwatch.MaxSubscriptionsPerGuild)
+	case err != nil:
+		logger.Error("falló al suscribirse al canal de YouTube", zap.Error(err))
+		return withErrorCode(ctx, "Ocurrió un error al suscribirse al canal")
+	}
+	message := fmt.Sprintf("📺 Suscripto a **%s**: los videos nuevos se anuncian en <#%s>", subscription.Name, subscription.TextChannelID)
+	if subscription.Queue {
+		message += " y se suman a la cola si estoy en un canal de voz"
+	}
+	return message
+}
+
+// YouTubeUnsubscribe elimina la suscripción del servidor a un canal de YouTube. Solo disponible para
+// administradores.
+func (handler *InteractionHandler) YouTubeUnsubscribe(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("YouTubeUnsubscribe")
+	if !handler.youtubeAdmin(ic) {
+		return
+	}
+
+	channelID, err := ytwatch.ParseChannelID(commandOptions(opt)["channel"].StringValue())
+	if err == nil {
+		err = handler.youtubeSubscriptions.Delete(ic.GuildID, channelID)
+	}
+	message := "🗑️ Suscripción eliminada"
+	switch {
+	case errors.Is(err, ytwatch.ErrInvalidChannel), errors.Is(err, ytwatch.ErrNotFound):
+		message = "🤷🏽 El servidor no está suscripto a ese canal"
+	case err != nil:
+		logger.Error("falló al eliminar la suscripción al canal de YouTube", zap.Error(err))
+		message = withErrorCode(ctx, "Ocurrió un error al eliminar la suscripción")
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		logger.Error("falló al responder con la suscripción eliminada", zap.Error(err))
+	}
+}
+
+// YouTubeList muestra los canales de YouTube a los que está suscripto el servidor. Solo disponible para
+// administradores.
+func (handler *InteractionHandler) YouTubeList(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) {
+	ctx, logger := handler.requestContext(ic)
+	handler.commandUsageCounter.Inc("YouTubeList")
+	if !handler.youtubeAdmin(ic) {
+		return
+	}
+
+	subscriptions, err := handler.youtubeSubscriptions.List(ic.GuildID)
+	if err != nil {
+		logger.Error("falló al obtener las suscripciones a canales de YouTube", zap.Error(err))
+		if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, withErrorCode(ctx, "Ocurrió un error al obtener las suscripciones")); err != nil {
+			logger.Error("falló al responder con el error del servidor", zap.Error(err))
+		}
+		return
+	}
+	if err := handler.responseHandler.Respond(handler.session, ic.Interaction, discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{GenerateYouTubeSubscriptionsEmbed(subscriptions)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		logger.Error("falló al responder con las suscripciones a canales de YouTube", zap.Error(err))
+	}
+}
+
+// NotifyYouTubeUploads anuncia los videos nuevos de un canal suscripto y, si la suscripción lo pide y el bot está en
+// un canal de voz, los suma a la cola. Es la ytwatch.NotifyFunc del watcher. En modo cluster solo los anuncia la
+// instancia que tiene el servidor, para que no se anuncien una vez por instancia.
+func (handler *InteractionHandler) NotifyYouTubeUploads(ctx context.Context, subscription ytwatch.Subscription, uploads []ytwatch.Upload) error {
+	if handler.voiceGate != nil && !handler.voiceGate.Owns(ctx, subscription.GuildID) {
+		return nil
+	}
+	queued := false
+	if subscription.Queue {
+		queued = handler.queueYouTubeUploads(ctx, subscription, uploads)
+	}
+	if handler.youtubeAnnouncer != nil {
+		if err := handler.youtubeAnnouncer.SendMessage(subscription.TextChannelID, youtubeUploadsMessage(subscription, uploads, queued)); err != nil {
+			handler.logger.Error("falló al anunciar los videos nuevos del canal de YouTube", zap.String("guildID", subscription.GuildID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// queueYouTubeUploads suma los videos a la cola del servidor si el bot está en un canal de voz, e indica si se
+// agregó alguno.
+func (handler *InteractionHandler) queueYouTubeUploads(ctx context.Context, subscription ytwatch.Subscription, uploads []ytwatch.Upload) bool {
+	logger := logging.WithFields(handler.logger, zap.String("guildID", subscription.GuildID), zap.String("channelID", subscription.ChannelID))
+	player, err := handler.playerFor(subscription.GuildID)
+	if err != nil {
+		return false
+	}
+	if voiceChannelID, err := player.GetVoiceChannel(); err != nil || voiceChannelID == "" {
+		return false
+	}
+	added := false
+	for _, upload := range uploads {
+		songs, err := handler.songLookup.LookupSongs(ctx, upload.URL())
+		if err != nil || len(songs) == 0 {
+			logger.Info("falló al buscar el video nuevo del canal", zap.String("videoID", upload.VideoID), zap.Error(err))
+			continue
+		}
+		requester := youtubeRequester
+		songs[0].RequestedBy = &requester
+		err = player.AddSong(ctx, &subscription.TextChannelID, nil, songs[0])
+		var rejected *bot.RejectedSongsError
+		if err != nil && !(errors.As(err, &rejected) && rejected.Added > 0) {
+			logger.Info("falló al agregar el video nuevo del canal a la cola", zap.String("videoID", upload.VideoID), zap.Error(err))
+			continue
+		}
+		added = true
+	}
+	return added
+}
+
+// youtubeAdmin responde al usuario si no es administrador o si las suscripciones a canales no están habilitadas.
+func (handler *InteractionHandler) youtubeAdmin(ic *discordgo.InteractionCreate) bool {
+	message := ""
+	switch {
+	case !isGuildAdmin(ic.Member):
+		message = ErrorMessageAdminOnly
+	case handler.youtubeSubscriptions == nil:
+		message = "Las suscripciones a canales de YouTube no están habilitadas"
+	default:
+		return true
+	}
+	if err := handler.responseHandler.RespondWithEphemeralMessage(handler.session, ic.Interaction, message); err != nil {
+		handler.logger.Error("falló al responder con el error del servidor", zap.Error(err))
+	}
+	return false
+}
+
+// youtubeUploadsMessage genera el anuncio de los videos nuevos de un canal.
+func youtubeUploadsMessage(subscription ytwatch.Subscription, uploads []ytwatch.Upload, queued bool) string {
+	builder := strings.Builder{}
+	if len(uploads) == 1 {
+		builder.WriteString(fmt.Sprintf("📺 **%s** subió un video nuevo:", subscription.Name))
+	} else {
+		builder.WriteString(fmt.Sprintf("📺 **%s** subió %d videos nuevos:", subscription.Name, len(uploads)))
+	}
+	for _, upload := range uploads {
+		builder.WriteString(fmt.Sprintf("\n- [%s](%s)", upload.Title, upload.URL()))
+	}
+	if queued {
+		builder.WriteString("\n➕ Agregado a la cola")
+	}
+	return builder.String()
+}
+
+// GenerateYouTubeSubscriptionsEmbed genera un embed con los canales suscriptos y dónde se anuncian.
+func GenerateYouTubeSubscriptionsEmbed(subscriptions []ytwatch.Subscription) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "📺 Canales de YouTube suscriptos"}
+	if len(subscriptions) == 0 {
+		embed.Description = "El servidor no está suscripto a ningún canal"
+		return embed
+	}
+	builder := strings.Builder{}
+	for _, subscription := range subscriptions {
+		builder.WriteString(fmt.Sprintf("[%s](https://www.youtube.com/channel/%s) → <#%s>", subscription.Name, subscription.ChannelID, subscription.TextChannelID))
+		if subscription.Queue {
+			builder.WriteString(" · a la cola")
+		}
+		builder.WriteString("\n")
+	}
+	embed.Description = strings.TrimSpace(builder.String())
+	return embed
+}
@@ -0,0 +1,51 @@
+package discord
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/bot"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/ytwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNotifyYouTubeUploads(t *testing.T) {
+	handler, player, songStorage, stateStorage := newPresenceTestHandler()
+	handler.guildsPlayers = map[GuildID]*bot.GuildPlayer{"1": player}
+	require.NoError(t, stateStorage.SetVoiceChannel("voz"))
+	looker := new(MockSongLooker)
+	looker.On("LookupSongs", "https://www.youtube.com/watch?v=nuevo").Return([]*voice.Song{{Title: "Tema nuevo"}}, nil)
+	handler.songLookup = looker
+	announcer := new(MockChatMessageSender)
+	announcer.On("SendMessage", "texto", mock.Anything).Return(nil)
+	handler.WithYouTubeWatch(nil, ytwatch.NewInMemoryStore(), announcer)
+
+	subscription := ytwatch.Subscription{GuildID: "1", ChannelID: "c1", Name: "Canal", TextChannelID: "texto", Queue: true}
+	err := handler.NotifyYouTubeUploads(context.Background(), subscription, []ytwatch.Upload{{VideoID: "nuevo", Title: "Tema nuevo"}})
+
+	require.NoError(t, err)
+	songs, _ := songStorage.GetSongs()
+	require.Len(t, songs, 2)
+	assert.Equal(t, "Tema nuevo", songs[1].Title)
+	assert.Equal(t, youtubeRequester, *songs[1].RequestedBy)
+	message := announcer.Calls[0].Arguments.String(1)
+	assert.Contains(t, message, "[Tema nuevo](https://www.youtube.com/watch?v=nuevo)")
+	assert.Contains(t, message, "Agregado a la cola")
+}
+
+func TestNotifyYouTubeUploads_NoSession(t *testing.T) {
+	handler, _, _, _ := newPresenceTestHandler()
+	announcer := new(MockChatMessageSender)
+	announcer.On("SendMessage", "texto", mock.Anything).Return(nil)
+	handler.WithYouTubeWatch(nil, ytwatch.NewInMemoryStore(), announcer)
+
+	subscription := ytwatch.Subscription{GuildID: "1", ChannelID: "c1", Name: "Canal", TextChannelID: "texto", Queue: true}
+	err := handler.NotifyYouTubeUploads(context.Background(), subscription, []ytwatch.Upload{{VideoID: "a", Title: "Uno"}, {VideoID: "b", Title: "Dos"}})
+
+	require.NoError(t, err)
+	message := announcer.Calls[0].Arguments.String(1)
+	assert.Contains(t, message, "subió 2 videos nuevos")
+	assert.NotContains(t, message, "cola", "sin reproductor solo se anuncian")
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"sync"
+	"time"
+)
+
+// Type es el tipo de un evento de reproducción.
+type Type string
+
+const (
+	// TypeQueueChanged se publica cuando cambia la lista de reproducción de un servidor.
+	TypeQueueChanged Type = "queue_changed"
+	// TypeTrackStarted se publica cuando empieza a sonar una canción.
+	TypeTrackStarted Type = "track_started"
+	// TypeTrackFinished se publica cuando termina (o se salta) la canción que estaba sonando.
+	TypeTrackFinished Type = "track_finished"
+	// TypePosition se publica periódicamente con la posición de la canción que está sonando.
+	TypePosition Type = "position"
+	// TypePlaybackStopped se publica cuando se detiene la reproducción y se limpia la lista.
+	TypePlaybackStopped Type = "playback_stopped"
+)
+
+// Event es un evento de reproducción de un servidor. RequestedByID es el ID de Discord de quien pidió la canción, vacío
+// si la pidió otro sistema. VoiceChannelID es el canal de voz del reproductor; solo lo llevan los eventos de canción
+// que empieza o termina y de reproducción detenida.
+type Event struct {
+	Type           Type      `json:"type"`
+	GuildID        string    `json:"guild_id"`
+	Title          string    `json:"title,omitempty"`
+	URL            string    `json:"url,omitempty"`
+	DurationMs     int64     `json:"duration_ms,omitempty"`
+	PositionMs     int64     `json:"position_ms,omitempty"`
+	QueueLength    *int      `json:"queue_length,omitempty"`
+	RequestedBy    string    `json:"requested_by,omitempty"`
+	RequestedByID  string    `json:"requested_by_id,omitempty"`
+	Uploader       string    `json:"uploader,omitempty"`
+	VoiceChannelID string    `json:"voice_channel_id,omitempty"`
+	Time           time.Time `json:"time"`
+}
+
+// NewSongEvent crea un evento con los datos de la canción.
+func NewSongEvent(eventType Type, guildID string, song *voice.Song, position time.Duration) Event {
+	event := Event{Type: eventType, GuildID: guildID, PositionMs: position.Milliseconds(), Time: time.Now()}
+	if song != nil {
+		event.Title = song.GetHumanName()
+		event.URL = song.URL
+		event.DurationMs = song.Duration.Milliseconds()
+		if song.RequestedBy != nil {
+			event.RequestedBy = *song.RequestedBy
+		}
+		event.RequestedByID = song.RequestedByID
+		event.Uploader = song.Uploader
+	}
+	return event
+}
+
+// NewQueueEvent crea un evento con la cantidad de canciones de la lista de reproducción.
+func NewQueueEvent(eventType Type, guildID string, queueLength int) Event {
+	return Event{Type: eventType, GuildID: guildID, QueueLength: &queueLength, Time: time.Now()}
+}
+
+// Publisher publica eventos de reproducción.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus reparte los eventos publicados entre todos los suscriptores. Si un suscriptor no consume a tiempo,
+// sus eventos se descartan para no frenar la reproducción.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewBus crea un Bus sin suscriptores.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish envía el evento a todos los suscriptores sin bloquear.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registra un suscriptor con un búfer del tamaño indicado. La función devuelta cancela la suscripción
+// y cierra el canal.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
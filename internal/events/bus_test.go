@@ -0,0 +1,35 @@
+package events
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	first, cancelFirst := bus.Subscribe(1)
+	second, cancelSecond := bus.Subscribe(1)
+	defer cancelSecond()
+
+	bus.Publish(NewSongEvent(TypeTrackStarted, "1", &voice.Song{Title: "Canción", Duration: time.Minute}, 0))
+	// El búfer del segundo suscriptor está lleno: el evento se descarta sin bloquear.
+	bus.Publish(NewQueueEvent(TypeQueueChanged, "1", 3))
+
+	event := <-first
+	assert.Equal(t, TypeTrackStarted, event.Type)
+	assert.Equal(t, "Canción", event.Title)
+	assert.Equal(t, int64(60000), event.DurationMs)
+	assert.Equal(t, TypeTrackStarted, (<-second).Type)
+
+	cancelFirst()
+	cancelFirst()
+	_, open := <-first
+	assert.False(t, open, "el canal se cierra al cancelar la suscripción")
+
+	bus.Publish(NewQueueEvent(TypeQueueChanged, "1", 2))
+	event = <-second
+	assert.Equal(t, TypeQueueChanged, event.Type)
+	assert.Equal(t, 2, *event.QueueLength)
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"os"
+	"path/filepath"
+)
+
+// DiscordGatewayCheck verifica que las sesiones (una por shard) estén conectadas al gateway de Discord.
+func DiscordGatewayCheck(sessions ...*discordgo.Session) Check {
+	return func(_ context.Context) error {
+		if len(sessions) == 0 {
+			return errors.New("no conectado al gateway de Discord")
+		}
+		for _, session := range sessions {
+			if session == nil || !session.DataReady {
+				if len(sessions) > 1 {
+					return fmt.Errorf("shard %d no conectado al gateway de Discord", session.ShardID)
+				}
+				return errors.New("no conectado al gateway de Discord")
+			}
+		}
+		return nil
+	}
+}
+
+// DirCheck verifica que se pueda escribir en el directorio, creándolo si todavía no existe
+// (igual que lo hacen los stores en archivo). Se usa para los stores en archivo.
+func DirCheck(dir string) Check {
+	return func(_ context.Context) error {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("directorio inaccesible: %w", err)
+		}
+		file, err := os.CreateTemp(dir, ".readyz-*")
+		if err != nil {
+			return fmt.Errorf("directorio sin permisos de escritura: %w", err)
+		}
+		_ = file.Close()
+		return os.Remove(file.Name())
+	}
+}
+
+// FileDirCheck verifica que el directorio que contiene el archivo indicado sea accesible.
+func FileDirCheck(path string) Check {
+	return DirCheck(filepath.Dir(path))
+}
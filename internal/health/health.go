@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkTimeout es el tiempo máximo que puede tardar cada verificación de disponibilidad.
+const checkTimeout = 3 * time.Second
+
+// Check es una verificación de disponibilidad. Devuelve un error si el componente no está listo.
+type Check func(ctx context.Context) error
+
+// Status es la respuesta de los endpoints de salud.
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Server expone los endpoints /healthz (liveness) y /readyz (readiness) para los health probes
+// de Docker o Kubernetes.
+type Server struct {
+	mu     sync.RWMutex
+	checks map[string]Check
+	server *http.Server
+}
+
+// NewServer crea un nuevo Server que escucha en la dirección indicada.
+func NewServer(address string) *Server {
+	s := &Server{checks: make(map[string]Check)}
+	s.server = &http.Server{
+		Addr:              address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// AddReadinessCheck registra una verificación que debe pasar para que el bot se considere listo.
+func (s *Server) AddReadinessCheck(name string, check Check) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+	return s
+}
+
+// Handler devuelve el http.Handler con los endpoints de salud.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.liveness)
+	mux.HandleFunc("/readyz", s.readiness)
+	return mux
+}
+
+// Start inicia el servidor HTTP. Bloquea hasta que el servidor se cierra.
+func (s *Server) Start() error {
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown detiene el servidor HTTP.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// liveness responde OK mientras el proceso esté vivo.
+func (s *Server) liveness(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, http.StatusOK, Status{Status: "ok"})
+}
+
+// readiness ejecuta todas las verificaciones registradas y responde 503 si alguna falla.
+func (s *Server) readiness(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.checks))
+	for name := range s.checks {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	sort.Strings(names)
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	status := Status{Status: "ok", Checks: make(map[string]string, len(names))}
+	code := http.StatusOK
+	for _, name := range names {
+		s.mu.RLock()
+		check := s.checks[name]
+		s.mu.RUnlock()
+		if err := check(ctx); err != nil {
+			status.Status = "unavailable"
+			status.Checks[name] = err.Error()
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		status.Checks[name] = "ok"
+	}
+	writeStatus(w, code, status)
+}
+
+func writeStatus(w http.ResponseWriter, code int, status Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
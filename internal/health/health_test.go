@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServer_Liveness(t *testing.T) {
+	server := NewServer(":0").AddReadinessCheck("falla", func(context.Context) error {
+		return errors.New("no listo")
+	})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_Readiness(t *testing.T) {
+	t.Run("todas las verificaciones pasan", func(t *testing.T) {
+		server := NewServer(":0").AddReadinessCheck("store", func(context.Context) error { return nil })
+
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var status Status
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "ok", status.Status)
+		assert.Equal(t, "ok", status.Checks["store"])
+	})
+
+	t.Run("una verificación falla", func(t *testing.T) {
+		server := NewServer(":0").
+			AddReadinessCheck("store", func(context.Context) error { return nil }).
+			AddReadinessCheck("discord", DiscordGatewayCheck(&discordgo.Session{}))
+
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var status Status
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "unavailable", status.Status)
+		assert.Equal(t, "no conectado al gateway de Discord", status.Checks["discord"])
+	})
+}
+
+func TestDirCheck(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, DirCheck(dir)(context.Background()))
+	assert.NoError(t, FileDirCheck(filepath.Join(dir, "audit.log"))(context.Background()))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.NoError(t, DirCheck(filepath.Join(dir, "nuevo"))(context.Background()))
+	assert.DirExists(t, filepath.Join(dir, "nuevo"))
+
+	file := filepath.Join(dir, "archivo")
+	assert.NoError(t, os.WriteFile(file, nil, 0644))
+	assert.Error(t, DirCheck(file)(context.Background()))
+}
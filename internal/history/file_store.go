@@ -0,0 +1,118 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileStore implementa Store guardando las entradas en un archivo JSON Lines (una entrada por línea).
+type FileStore struct {
+	mu       sync.Mutex
+	filepath string
+}
+
+// NewFileStore crea una nueva instancia de FileStore. Si el archivo no existe, se creará uno nuevo.
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio del historial: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el archivo del historial: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return &FileStore{filepath: path}, nil
+}
+
+// Append agrega una entrada al final del archivo.
+func (s *FileStore) Append(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la entrada del historial: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// Query devuelve las entradas que cumplen con el filtro, de la más reciente a la más antigua.
+func (s *FileStore) Query(filter Filter) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readEntries()
+	if err != nil {
+		return nil, err
+	}
+	return queryEntries(entries, filter), nil
+}
+
+// Purge reescribe el archivo conservando solo las entradas posteriores a la fecha indicada.
+func (s *FileStore) Purge(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readEntries()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	err = jsonfile.WriteFile(s.filepath, func(w io.Writer) error {
+		writer := bufio.NewWriter(w)
+		encoder := json.NewEncoder(writer)
+		for _, entry := range entries {
+			if entry.PlayedAt.Before(before) {
+				purged++
+				continue
+			}
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// readEntries lee todas las entradas del archivo, ignorando las líneas corruptas.
+func (s *FileStore) readEntries() ([]Entry, error) {
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
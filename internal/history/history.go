@@ -0,0 +1,242 @@
+// Package history guarda el historial de reproducción de cada servidor: qué canciones sonaron, cuándo y quién las
+// pidió. Lo usan los comandos que muestran estadísticas de escucha.
+package history
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Entry es una canción que empezó a sonar en un servidor.
+	Entry struct {
+		PlayedAt   time.Time `json:"played_at"`
+		GuildID    string    `json:"guild_id"`
+		UserID     string    `json:"user_id,omitempty"`
+		UserName   string    `json:"user_name,omitempty"`
+		Title      string    `json:"title"`
+		URL        string    `json:"url,omitempty"`
+		Uploader   string    `json:"uploader,omitempty"`
+		DurationMs int64     `json:"duration_ms,omitempty"`
+	}
+
+	// Filter define los criterios para consultar el historial. Los campos vacíos no se tienen en cuenta.
+	Filter struct {
+		GuildID string
+		UserID  string
+		Since   time.Time
+		Limit   int
+	}
+
+	// Store guarda el historial de reproducción de todos los servidores.
+	Store interface {
+		// Append agrega una entrada al historial.
+		Append(entry Entry) error
+		// Query devuelve las entradas que cumplen con el filtro, de la más reciente a la más antigua.
+		Query(filter Filter) ([]Entry, error)
+		// Purge elimina las entradas anteriores a la fecha indicada y devuelve cuántas se eliminaron.
+		Purge(before time.Time) (int, error)
+	}
+)
+
+// Matches indica si la entrada cumple con el filtro.
+func (f Filter) Matches(entry Entry) bool {
+	if f.GuildID != "" && entry.GuildID != f.GuildID {
+		return false
+	}
+	if f.UserID != "" && entry.UserID != f.UserID {
+		return false
+	}
+	if !f.Since.IsZero() && entry.PlayedAt.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// Recorder guarda en el Store las canciones que empiezan a sonar y borra las entradas que superan la retención.
+type Recorder struct {
+	store     Store
+	retention time.Duration
+	logger    logging.Logger
+}
+
+// NewRecorder crea un Recorder. Con retention cero el historial se guarda para siempre.
+func NewRecorder(store Store, retention time.Duration, logger logging.Logger) *Recorder {
+	return &Recorder{store: store, retention: retention, logger: logger}
+}
+
+// Run registra los eventos recibidos hasta que se cierre el canal o se cancele el contexto. Cada interval borra las
+// entradas vencidas.
+func (r *Recorder) Run(ctx context.Context, ch <-chan events.Event, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			// Solo se guardan las canciones que pidió un usuario: las de la trivia o de otros sistemas no
+			// dicen nada de lo que escucha el servidor.
+			if event.Type != events.TypeTrackStarted || event.Title == "" || event.RequestedByID == "" {
+				continue
+			}
+			entry := Entry{
+				PlayedAt:   event.Time,
+				GuildID:    event.GuildID,
+				UserID:     event.RequestedByID,
+				UserName:   event.RequestedBy,
+				Title:      event.Title,
+				URL:        event.URL,
+				Uploader:   event.Uploader,
+				DurationMs: event.DurationMs,
+			}
+			if err := r.store.Append(entry); err != nil {
+				r.logger.Error("Error al guardar la canción en el historial", zap.String("guildID", event.GuildID), zap.Error(err))
+			}
+		case <-ticker.C:
+			r.purge()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Recorder) purge() {
+	if r.retention <= 0 {
+		return
+	}
+	purged, err := r.store.Purge(time.Now().Add(-r.retention))
+	if err != nil {
+		r.logger.Error("Error al purgar el historial de reproducción", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		r.logger.Info("Historial de reproducción purgado", zap.Int("entradas", purged))
+	}
+}
+
+type (
+	// Count es la cantidad de canciones pedidas de un artista.
+	Count struct {
+		Name  string
+		Count int
+	}
+
+	// UserStats son las estadísticas de escucha de un usuario.
+	UserStats struct {
+		Requests      int
+		ListeningTime time.Duration
+		// TopArtists son los artistas más pedidos, de mayor a menor.
+		TopArtists []Count
+		// FavoriteHours son las horas del día (en UTC) en las que el usuario pide más canciones, de mayor a menor.
+		FavoriteHours []int
+	}
+
+	// Popular es una canción del historial con cuánto se escuchó en el servidor.
+	Popular struct {
+		// Entry es la última vez que sonó la canción.
+		Entry
+		Plays int
+		// Listeners es la cantidad de usuarios distintos que la pidieron.
+		Listeners int
+	}
+)
+
+// Summarize calcula las estadísticas de las entradas, con hasta top artistas y horas. Los artistas son el canal que
+// subió la canción o, si la fuente no lo informa, la parte del título antes de " - ".
+func Summarize(entries []Entry, top int) UserStats {
+	stats := UserStats{Requests: len(entries)}
+	artists := make(map[string]int)
+	hours := make(map[int]int)
+	for _, entry := range entries {
+		stats.ListeningTime += time.Duration(entry.DurationMs) * time.Millisecond
+		if artist := Artist(entry); artist != "" {
+			artists[artist]++
+		}
+		hours[entry.PlayedAt.UTC().Hour()]++
+	}
+
+	for name, count := range artists {
+		stats.TopArtists = append(stats.TopArtists, Count{Name: name, Count: count})
+	}
+	sort.Slice(stats.TopArtists, func(i, j int) bool {
+		if stats.TopArtists[i].Count != stats.TopArtists[j].Count {
+			return stats.TopArtists[i].Count > stats.TopArtists[j].Count
+		}
+		return stats.TopArtists[i].Name < stats.TopArtists[j].Name
+	})
+	if len(stats.TopArtists) > top {
+		stats.TopArtists = stats.TopArtists[:top]
+	}
+
+	for hour := range hours {
+		stats.FavoriteHours = append(stats.FavoriteHours, hour)
+	}
+	sort.Slice(stats.FavoriteHours, func(i, j int) bool {
+		a, b := stats.FavoriteHours[i], stats.FavoriteHours[j]
+		if hours[a] != hours[b] {
+			return hours[a] > hours[b]
+		}
+		return a < b
+	})
+	if len(stats.FavoriteHours) > top {
+		stats.FavoriteHours = stats.FavoriteHours[:top]
+	}
+	return stats
+}
+
+// Popularity agrupa las entradas por canción y cuenta cuántas veces sonó cada una y cuántos usuarios distintos la
+// pidieron. Devuelve las canciones de la más escuchada a la menos escuchada; las entradas sin URL no se cuentan.
+func Popularity(entries []Entry) []Popular {
+	byURL := make(map[string]*Popular)
+	listeners := make(map[string]map[string]struct{})
+	for _, entry := range entries {
+		if entry.URL == "" {
+			continue
+		}
+		popular, ok := byURL[entry.URL]
+		if !ok {
+			popular = &Popular{Entry: entry}
+			byURL[entry.URL] = popular
+			listeners[entry.URL] = make(map[string]struct{})
+		}
+		if entry.PlayedAt.After(popular.PlayedAt) {
+			popular.Entry = entry
+		}
+		popular.Plays++
+		if entry.UserID != "" {
+			listeners[entry.URL][entry.UserID] = struct{}{}
+		}
+	}
+
+	songs := make([]Popular, 0, len(byURL))
+	for url, popular := range byURL {
+		popular.Listeners = len(listeners[url])
+		songs = append(songs, *popular)
+	}
+	sort.Slice(songs, func(i, j int) bool {
+		if songs[i].Plays != songs[j].Plays {
+			return songs[i].Plays > songs[j].Plays
+		}
+		return songs[i].URL < songs[j].URL
+	})
+	return songs
+}
+
+// Artist devuelve el artista de la canción: el canal que la subió, sin el sufijo " - Topic" de los canales
+// automáticos de YouTube, o la parte del título antes de " - ".
+func Artist(entry Entry) string {
+	if entry.Uploader != "" {
+		return strings.TrimSuffix(entry.Uploader, " - Topic")
+	}
+	if artist, _, ok := strings.Cut(entry.Title, " - "); ok {
+		return strings.TrimSpace(artist)
+	}
+	return ""
+}
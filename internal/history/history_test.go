@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_AppendQueryAndPurge(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "history", "history.log"))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, store.Append(Entry{PlayedAt: now.Add(-48 * time.Hour), GuildID: "g1", UserID: "u1", Title: "Vieja"}))
+	assert.NoError(t, store.Append(Entry{PlayedAt: now.Add(-time.Minute), GuildID: "g1", UserID: "u2", Title: "Otra"}))
+	assert.NoError(t, store.Append(Entry{PlayedAt: now, GuildID: "g1", UserID: "u1", Title: "Nueva"}))
+
+	entries, err := store.Query(Filter{GuildID: "g1", UserID: "u1"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Nueva", entries[0].Title)
+
+	purged, err := store.Purge(now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	entries, err = store.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestRecorder_Run(t *testing.T) {
+	store := new(MockStore)
+	logger := new(MockLogger)
+	logger.On("Error", "Error al guardar la canción en el historial", mock.Anything).Return()
+	at := time.Now()
+	store.On("Append", Entry{PlayedAt: at, GuildID: "1", UserID: "10", UserName: "tomas", Title: "Canción", Uploader: "Banda", DurationMs: 1000}).Return(nil).Once()
+	store.On("Append", mock.MatchedBy(func(e Entry) bool { return e.GuildID == "2" })).Return(errors.New("falla")).Once()
+
+	ch := make(chan events.Event, 4)
+	ch <- events.Event{Type: events.TypeTrackStarted, GuildID: "1", Title: "Canción", RequestedBy: "tomas", RequestedByID: "10", Uploader: "Banda", DurationMs: 1000, Time: at}
+	ch <- events.Event{Type: events.TypeTrackStarted, GuildID: "1", Title: "🎲 Trivia: ronda 1", RequestedBy: "Trivia", Time: at}
+	ch <- events.Event{Type: events.TypePosition, GuildID: "1", Title: "Canción", RequestedByID: "10", Time: at}
+	ch <- events.Event{Type: events.TypeTrackStarted, GuildID: "2", Title: "Otra", RequestedByID: "20", Time: at}
+	close(ch)
+	NewRecorder(store, time.Hour, logger).Run(context.Background(), ch, time.Hour)
+
+	store.AssertExpectations(t)
+	logger.AssertNumberOfCalls(t, "Error", 1)
+}
+
+func TestSummarize(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 5, 1, hour, 0, 0, 0, time.UTC) }
+	stats := Summarize([]Entry{
+		{PlayedAt: at(22), Title: "Soda Stereo - De música ligera", DurationMs: 210000},
+		{PlayedAt: at(22), Title: "Persiana americana", Uploader: "Soda Stereo - Topic", DurationMs: 290000},
+		{PlayedAt: at(9), Title: "Mil horas", Uploader: "Los Abuelos de la Nada", DurationMs: 100000},
+		{PlayedAt: at(23), Title: "Sin artista"},
+	}, 2)
+
+	assert.Equal(t, 4, stats.Requests)
+	assert.Equal(t, 10*time.Minute, stats.ListeningTime)
+	assert.Equal(t, []Count{{Name: "Soda Stereo", Count: 2}, {Name: "Los Abuelos de la Nada", Count: 1}}, stats.TopArtists)
+	assert.Equal(t, []int{22, 9}, stats.FavoriteHours)
+}
+
+func TestPopularity(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 5, 1, hour, 0, 0, 0, time.UTC) }
+	songs := Popularity([]Entry{
+		{PlayedAt: at(23), UserID: "ana", Title: "Mil horas", URL: "https://youtu.be/mil"},
+		{PlayedAt: at(22), UserID: "ana", Title: "Persiana americana (viejo)", URL: "https://youtu.be/persiana"},
+		{PlayedAt: at(21), UserID: "beto", Title: "Mil horas", URL: "https://youtu.be/mil"},
+		{PlayedAt: at(23), UserID: "ana", Title: "Persiana americana", URL: "https://youtu.be/persiana"},
+		{PlayedAt: at(20), UserID: "ana", Title: "Mil horas", URL: "https://youtu.be/mil"},
+		{PlayedAt: at(20), UserID: "ana", Title: "Sin URL"},
+	})
+
+	assert.Len(t, songs, 2)
+	assert.Equal(t, "Mil horas", songs[0].Title)
+	assert.Equal(t, 3, songs[0].Plays)
+	assert.Equal(t, 2, songs[0].Listeners)
+	// Se queda con la última vez que sonó.
+	assert.Equal(t, "Persiana americana", songs[1].Title)
+	assert.Equal(t, 2, songs[1].Plays)
+	assert.Equal(t, 1, songs[1].Listeners)
+}
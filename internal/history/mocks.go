@@ -0,0 +1,46 @@
+package history
+
+import (
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"time"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Append(entry Entry) error {
+	return m.Called(entry).Error(0)
+}
+
+func (m *MockStore) Query(filter Filter) ([]Entry, error) {
+	ret := m.Called(filter)
+	entries, _ := ret.Get(0).([]Entry)
+	return entries, ret.Error(1)
+}
+
+func (m *MockStore) Purge(before time.Time) (int, error) {
+	ret := m.Called(before)
+	return ret.Int(0), ret.Error(1)
+}
@@ -0,0 +1,169 @@
+// Package identify reconoce canciones a partir de un fragmento de audio, con huellas acústicas de Chromaprint y la
+// base de datos de AcoustID.
+package identify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLookupURL es la API de búsqueda de AcoustID.
+	DefaultLookupURL = "https://api.acoustid.org/v2/lookup"
+	// minScore es la coincidencia mínima para aceptar un resultado de AcoustID.
+	minScore = 0.5
+	// lookupTimeout es el tiempo máximo de una búsqueda en AcoustID.
+	lookupTimeout = 10 * time.Second
+)
+
+// ErrNoMatch indica que no se reconoció la canción del fragmento.
+var ErrNoMatch = errors.New("no se reconoció la canción")
+
+// Match es una canción reconocida.
+type Match struct {
+	Title  string
+	Artist string
+	// Score es qué tan segura es la coincidencia, de 0 a 1.
+	Score float64
+}
+
+// Query devuelve la búsqueda con la que se encuentra la canción en YouTube.
+func (m Match) Query() string {
+	if m.Artist == "" {
+		return m.Title
+	}
+	return m.Artist + " - " + m.Title
+}
+
+// Identifier reconoce la canción de un archivo de audio.
+type Identifier interface {
+	// Identify devuelve la canción que suena en el archivo de path, o ErrNoMatch si no la reconoce.
+	Identify(ctx context.Context, path string) (*Match, error)
+}
+
+// AcoustID reconoce canciones calculando la huella del audio con fpcalc, de Chromaprint, y buscándola en AcoustID.
+type AcoustID struct {
+	apiKey    string
+	fpcalc    string
+	executor  fetcher.CommandExecutor
+	client    *http.Client
+	lookupURL string
+}
+
+// NewAcoustID crea un AcoustID con la clave de aplicación de AcoustID y la ruta del binario fpcalc.
+func NewAcoustID(apiKey, fpcalc string, executor fetcher.CommandExecutor) *AcoustID {
+	return &AcoustID{
+		apiKey:    apiKey,
+		fpcalc:    fpcalc,
+		executor:  executor,
+		client:    &http.Client{Timeout: lookupTimeout},
+		lookupURL: DefaultLookupURL,
+	}
+}
+
+// WithLookupURL cambia la URL de la API de búsqueda, por ejemplo para usar un servidor propio.
+func (a *AcoustID) WithLookupURL(lookupURL string) *AcoustID {
+	a.lookupURL = lookupURL
+	return a
+}
+
+func (a *AcoustID) Identify(ctx context.Context, path string) (*Match, error) {
+	fp, err := a.fingerprint(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return a.lookup(ctx, fp)
+}
+
+// fingerprint es la huella acústica de un archivo, como la devuelve fpcalc -json.
+type fingerprint struct {
+	Duration    float64 `json:"duration"`
+	Fingerprint string  `json:"fingerprint"`
+}
+
+// fingerprint calcula la huella acústica del archivo con fpcalc.
+func (a *AcoustID) fingerprint(ctx context.Context, path string) (fingerprint, error) {
+	var fp fingerprint
+	output, err := a.executor.ExecuteCommand(ctx, a.fpcalc, "-json", path).Output()
+	if err != nil {
+		return fp, fmt.Errorf("error al calcular la huella del audio: %w", err)
+	}
+	if err := json.Unmarshal(output, &fp); err != nil {
+		return fp, fmt.Errorf("error al leer la huella del audio: %w", err)
+	}
+	if fp.Fingerprint == "" {
+		return fp, ErrNoMatch
+	}
+	return fp, nil
+}
+
+// lookupResponse es la respuesta de la API de búsqueda de AcoustID.
+type lookupResponse struct {
+	Status string `json:"status"`
+	Error  struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			Title   string `json:"title"`
+			Artists []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+		} `json:"recordings"`
+	} `json:"results"`
+}
+
+// lookup busca la huella en AcoustID y devuelve la grabación con título del resultado más seguro.
+func (a *AcoustID) lookup(ctx context.Context, fp fingerprint) (*Match, error) {
+	form := url.Values{
+		"client":      {a.apiKey},
+		"meta":        {"recordings"},
+		"duration":    {strconv.Itoa(int(fp.Duration))},
+		"fingerprint": {fp.Fingerprint},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.lookupURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error al crear la búsqueda en AcoustID: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar en AcoustID: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body lookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error al leer la respuesta de AcoustID (status %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "ok" {
+		return nil, fmt.Errorf("AcoustID respondió con un error: %s", body.Error.Message)
+	}
+
+	// Los resultados vienen ordenados por coincidencia.
+	for _, result := range body.Results {
+		if result.Score < minScore {
+			break
+		}
+		for _, recording := range result.Recordings {
+			if recording.Title == "" {
+				continue
+			}
+			artists := make([]string, 0, len(recording.Artists))
+			for _, artist := range recording.Artists {
+				artists = append(artists, artist.Name)
+			}
+			return &Match{Title: recording.Title, Artist: strings.Join(artists, ", "), Score: result.Score}, nil
+		}
+	}
+	return nil, ErrNoMatch
+}
@@ -0,0 +1,63 @@
+package identify
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+)
+
+func newTestAcoustID(t *testing.T, fpcalcOutput, response string) *AcoustID {
+	executor := new(fetcher.MockCommandExecutor)
+	executor.On("ExecuteCommand", mock.Anything, "fpcalc", []string{"-json", "clip.ogg"}).Return(exec.Command("echo", fpcalcOutput))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "clave", r.PostForm.Get("client"))
+		assert.Equal(t, "AQAAdW", r.PostForm.Get("fingerprint"))
+		assert.Equal(t, "12", r.PostForm.Get("duration"))
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return NewAcoustID("clave", "fpcalc", executor).WithLookupURL(server.URL)
+}
+
+func TestAcoustID_Identify(t *testing.T) {
+	acoustID := newTestAcoustID(t, `{"duration": 12.7, "fingerprint": "AQAAdW"}`, `{"status": "ok", "results": [
+		{"score": 0.93, "recordings": [
+			{"id": "sin-titulo"},
+			{"title": "Ella usó mi cabeza como un revólver", "artists": [{"name": "Soda Stereo"}]}
+		]},
+		{"score": 0.6, "recordings": [{"title": "Otra"}]}
+	]}`)
+
+	match, err := acoustID.Identify(context.Background(), "clip.ogg")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Ella usó mi cabeza como un revólver", match.Title)
+	assert.Equal(t, "Soda Stereo", match.Artist)
+	assert.Equal(t, 0.93, match.Score)
+	assert.Equal(t, "Soda Stereo - Ella usó mi cabeza como un revólver", match.Query())
+}
+
+func TestAcoustID_IdentifyNoMatch(t *testing.T) {
+	acoustID := newTestAcoustID(t, `{"duration": 12.2, "fingerprint": "AQAAdW"}`, `{"status": "ok", "results": [
+		{"score": 0.2, "recordings": [{"title": "Dudosa"}]}
+	]}`)
+
+	_, err := acoustID.Identify(context.Background(), "clip.ogg")
+
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestAcoustID_IdentifyError(t *testing.T) {
+	acoustID := newTestAcoustID(t, `{"duration": 12, "fingerprint": "AQAAdW"}`, `{"status": "error", "error": {"code": 4, "message": "invalid API key"}}`)
+
+	_, err := acoustID.Identify(context.Background(), "clip.ogg")
+
+	assert.ErrorContains(t, err, "invalid API key")
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// Dispatcher consume los trabajos de una cola con varios workers y los entrega al handler registrado para su tipo.
+type Dispatcher struct {
+	queue    Queue
+	workers  int
+	logger   logging.Logger
+	handlers map[string]HandlerFunc
+}
+
+// NewDispatcher crea un Dispatcher que consume la cola con la cantidad de workers indicada.
+func NewDispatcher(queue Queue, workers int, logger logging.Logger) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Dispatcher{
+		queue:    queue,
+		workers:  workers,
+		logger:   logger,
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Handle registra el handler de los trabajos del tipo indicado.
+func (d *Dispatcher) Handle(jobType string, handler HandlerFunc) *Dispatcher {
+	d.handlers[jobType] = handler
+	return d
+}
+
+// Run consume la cola hasta que se cancele el contexto. Los handlers se registran antes de llamarlo.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.queue.Consume(ctx, d.dispatch); err != nil {
+				d.logger.Error("Error al consumir la cola de trabajos", zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// dispatch ejecuta el handler del trabajo, con el ID de correlación del trabajo en el contexto y en los logs.
+func (d *Dispatcher) dispatch(ctx context.Context, job *Job) (err error) {
+	ctx = job.Context(ctx)
+	logger := logging.FromContext(ctx, d.logger)
+	handler, ok := d.handlers[job.Type]
+	if !ok {
+		logger.Warn("Se descartó un trabajo sin handler", zap.String("type", job.Type))
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic al procesar el trabajo %s: %v", job.Type, r)
+		}
+		if err != nil {
+			logger.Error("Error al procesar el trabajo", zap.String("type", job.Type), zap.String("guildID", job.GuildID), zap.Error(err))
+		}
+	}()
+	start := time.Now()
+	err = handler(ctx, job)
+	logger.Info("Trabajo procesado", zap.String("type", job.Type), zap.String("guildID", job.GuildID),
+		zap.Duration("wait", start.Sub(job.EnqueuedAt)), zap.Duration("duration", time.Since(start)))
+	return err
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := new(MockLogger)
+	logger.On("Info", "Trabajo procesado", mock.Anything).Return()
+	logger.On("Warn", "Se descartó un trabajo sin handler", mock.Anything).Return()
+	logger.On("Error", "Error al procesar el trabajo", mock.Anything).Return()
+	queue := NewInMemoryQueue(10)
+
+	processed := make(chan string, 3)
+	dispatcher := NewDispatcher(queue, 2, logger).
+		Handle("lookup", func(_ context.Context, job *Job) error {
+			processed <- job.GuildID
+			return nil
+		}).
+		Handle("prefetch", func(_ context.Context, job *Job) error {
+			defer func() { processed <- job.GuildID }()
+			panic("falla")
+		})
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	for _, job := range []*Job{{Type: "desconocido", GuildID: "0"}, {Type: "prefetch", GuildID: "1"}, {Type: "lookup", GuildID: "2"}} {
+		assert.NoError(t, queue.Publish(ctx, job))
+	}
+	assert.ElementsMatch(t, []string{"1", "2"}, []string{<-processed, <-processed})
+
+	cancel()
+	<-done
+	logger.AssertCalled(t, "Warn", "Se descartó un trabajo sin handler", mock.Anything)
+	logger.AssertCalled(t, "Error", "Error al procesar el trabajo", mock.Anything)
+}
+
+func TestDispatcherReturnsHandlerError(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	wantErr := errors.New("falla")
+	dispatcher := NewDispatcher(NewInMemoryQueue(1), 1, logger).Handle("lookup", func(context.Context, *Job) error {
+		return wantErr
+	})
+
+	assert.ErrorIs(t, dispatcher.dispatch(context.Background(), &Job{Type: "lookup"}), wantErr)
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// eventBridgeEvent es el formato con el que EventBridge entrega los eventos a sus destinos, por ejemplo a una
+// cola de SQS. El detail-type es el tipo del trabajo y el detail, su payload.
+type eventBridgeEvent struct {
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Time       time.Time       `json:"time"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// fromEventBridge convierte un evento de EventBridge en un trabajo. El ID del evento se usa como ID de
+// correlación, para poder seguirlo en los logs.
+func fromEventBridge(data []byte) (*Job, error) {
+	var event eventBridgeEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error al leer el evento de EventBridge: %w", err)
+	}
+	if event.DetailType == "" {
+		return nil, errors.New("el trabajo no tiene tipo")
+	}
+	var detail struct {
+		GuildID string `json:"guild_id"`
+	}
+	if len(event.Detail) > 0 {
+		if err := json.Unmarshal(event.Detail, &detail); err != nil {
+			return nil, fmt.Errorf("error al leer el detalle del evento %s: %w", event.ID, err)
+		}
+	}
+	return &Job{
+		Type:          event.DetailType,
+		GuildID:       detail.GuildID,
+		CorrelationID: event.ID,
+		Payload:       event.Detail,
+		EnqueuedAt:    event.Time,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"time"
+)
+
+// ErrQueueClosed se devuelve al publicar o consumir de una cola cerrada.
+var ErrQueueClosed = errors.New("la cola de trabajos está cerrada")
+
+type (
+	// Job es un trabajo pesado (búsqueda, transcodificación) que se procesa fuera del manejo de la interacción.
+	Job struct {
+		Type          string          `json:"type"`
+		GuildID       string          `json:"guild_id,omitempty"`
+		CorrelationID string          `json:"correlation_id,omitempty"`
+		Payload       json.RawMessage `json:"payload,omitempty"`
+		EnqueuedAt    time.Time       `json:"enqueued_at"`
+	}
+
+	// Queue define una cola de trabajos. Las implementaciones tienen que permitir que varios consumidores
+	// (goroutines del bot o workers en otros procesos) llamen a Consume al mismo tiempo.
+	Queue interface {
+		// Publish encola el trabajo.
+		Publish(ctx context.Context, job *Job) error
+		// Consume procesa los trabajos de a uno con handler hasta que se cancele el contexto. Si handler
+		// devuelve un error, el backend puede volver a entregar el trabajo.
+		Consume(ctx context.Context, handler HandlerFunc) error
+		// Close libera la conexión con el backend.
+		Close() error
+	}
+
+	// HandlerFunc procesa un trabajo.
+	HandlerFunc func(ctx context.Context, job *Job) error
+)
+
+// NewJob crea un trabajo del tipo indicado con el payload serializado en JSON. El ID de correlación se toma del
+// contexto, para poder seguir el trabajo en los logs desde la interacción que lo originó.
+func NewJob(ctx context.Context, jobType, guildID string, payload interface{}) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar el trabajo %s: %w", jobType, err)
+	}
+	return &Job{
+		Type:          jobType,
+		GuildID:       guildID,
+		CorrelationID: logging.CorrelationIDFromContext(ctx),
+		Payload:       data,
+		EnqueuedAt:    time.Now(),
+	}, nil
+}
+
+// Decode deserializa el payload del trabajo.
+func (j *Job) Decode(payload interface{}) error {
+	if err := json.Unmarshal(j.Payload, payload); err != nil {
+		return fmt.Errorf("error al leer el trabajo %s: %w", j.Type, err)
+	}
+	return nil
+}
+
+// Context devuelve el contexto con el ID de correlación del trabajo.
+func (j *Job) Context(ctx context.Context) context.Context {
+	if j.CorrelationID == "" {
+		return ctx
+	}
+	return logging.ContextWithCorrelationID(ctx, j.CorrelationID)
+}
+
+func marshal(job *Job) ([]byte, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar el trabajo %s: %w", job.Type, err)
+	}
+	return data, nil
+}
+
+// unmarshal lee un trabajo publicado con Publish o un evento de EventBridge.
+func unmarshal(data []byte) (*Job, error) {
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("error al leer el trabajo: %w", err)
+	}
+	if job.Type == "" {
+		return fromEventBridge(data)
+	}
+	return &job, nil
+}
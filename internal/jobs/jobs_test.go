@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testPayload struct {
+	Input string `json:"input"`
+}
+
+func TestNewJob(t *testing.T) {
+	ctx := logging.ContextWithCorrelationID(context.Background(), "ab12cd")
+
+	job, err := NewJob(ctx, "lookup", "1", testPayload{Input: "cancion"})
+	assert.NoError(t, err)
+	assert.Equal(t, "lookup", job.Type)
+	assert.Equal(t, "1", job.GuildID)
+	assert.Equal(t, "ab12cd", job.CorrelationID)
+
+	var payload testPayload
+	assert.NoError(t, job.Decode(&payload))
+	assert.Equal(t, "cancion", payload.Input)
+	assert.Equal(t, "ab12cd", logging.CorrelationIDFromContext(job.Context(context.Background())))
+}
+
+func TestInMemoryQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := NewInMemoryQueue(1)
+	job, err := NewJob(ctx, "lookup", "1", testPayload{Input: "cancion"})
+	assert.NoError(t, err)
+	assert.NoError(t, queue.Publish(ctx, job))
+
+	received := make(chan *Job, 1)
+	go func() {
+		_ = queue.Consume(ctx, func(_ context.Context, job *Job) error {
+			received <- job
+			return nil
+		})
+	}()
+	assert.Same(t, job, <-received)
+
+	assert.NoError(t, queue.Close())
+	assert.ErrorIs(t, queue.Publish(ctx, job), ErrQueueClosed)
+}
+
+func TestUnmarshal_EventBridgeEvent(t *testing.T) {
+	data := []byte(`{
+		"version": "0",
+		"id": "6a7e8feb-b491-4cf7-a9f1-bf3703467718",
+		"detail-type": "Scheduled Play",
+		"source": "gomusicbot.scheduler",
+		"time": "2024-05-06T09:00:00Z",
+		"detail": {"guild_id": "1", "input": "lofi hip hop"}
+	}`)
+
+	job, err := unmarshal(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Scheduled Play", job.Type)
+	assert.Equal(t, "1", job.GuildID)
+	assert.Equal(t, "6a7e8feb-b491-4cf7-a9f1-bf3703467718", job.CorrelationID)
+	var payload testPayload
+	assert.NoError(t, job.Decode(&payload))
+	assert.Equal(t, "lofi hip hop", payload.Input)
+
+	_, err = unmarshal([]byte(`{"guild_id": "1"}`))
+	assert.Error(t, err)
+}
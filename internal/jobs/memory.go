@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryQueue implementa Queue con un canal. Los trabajos se procesan en el mismo proceso y se pierden si el
+// bot se reinicia; sirve para una única instancia y para pruebas.
+type InMemoryQueue struct {
+	jobs      chan *Job
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewInMemoryQueue crea una cola en memoria con capacidad para size trabajos pendientes.
+func NewInMemoryQueue(size int) *InMemoryQueue {
+	return &InMemoryQueue{
+		jobs: make(chan *Job, size),
+		done: make(chan struct{}),
+	}
+}
+
+// Publish encola el trabajo. Si la cola está llena, espera hasta que haya lugar o se cancele el contexto.
+func (q *InMemoryQueue) Publish(ctx context.Context, job *Job) error {
+	select {
+	case <-q.done:
+		return ErrQueueClosed
+	default:
+	}
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-q.done:
+		return ErrQueueClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Consume procesa los trabajos hasta que se cancele el contexto o se cierre la cola. Los trabajos que fallan
+// no se vuelven a entregar.
+func (q *InMemoryQueue) Consume(ctx context.Context, handler HandlerFunc) error {
+	for {
+		select {
+		case job := <-q.jobs:
+			_ = handler(ctx, job)
+		case <-q.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (q *InMemoryQueue) Close() error {
+	q.closeOnce.Do(func() { close(q.done) })
+	return nil
+}
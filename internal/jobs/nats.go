@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// NATSQueue implementa Queue sobre NATS. Los consumidores se suscriben en un queue group, así que cada trabajo
+// lo procesa uno solo de ellos, sin importar cuántos bots o workers estén escuchando. NATS no guarda los
+// mensajes: si no hay ningún consumidor conectado, el trabajo se pierde.
+type NATSQueue struct {
+	conn    *nats.Conn
+	subject string
+	group   string
+	logger  logging.Logger
+}
+
+// NewNATSQueue se conecta al servidor de NATS y crea una cola sobre el subject indicado.
+func NewNATSQueue(url, subject, group string, logger logging.Logger) (*NATSQueue, error) {
+	conn, err := nats.Connect(url, nats.Name("gomusicbot"))
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar con NATS: %w", err)
+	}
+	return &NATSQueue{conn: conn, subject: subject, group: group, logger: logger}, nil
+}
+
+func (q *NATSQueue) Publish(_ context.Context, job *Job) error {
+	data, err := marshal(job)
+	if err != nil {
+		return err
+	}
+	if err := q.conn.Publish(q.subject, data); err != nil {
+		if errors.Is(err, nats.ErrConnectionClosed) {
+			return ErrQueueClosed
+		}
+		return fmt.Errorf("error al publicar el trabajo en NATS: %w", err)
+	}
+	return nil
+}
+
+func (q *NATSQueue) Consume(ctx context.Context, handler HandlerFunc) error {
+	sub, err := q.conn.QueueSubscribeSync(q.subject, q.group)
+	if err != nil {
+		return fmt.Errorf("error al suscribirse a NATS: %w", err)
+	}
+	defer func() {
+		_ = sub.Unsubscribe()
+	}()
+
+	for {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, nats.ErrConnectionClosed) || errors.Is(err, nats.ErrBadSubscription) {
+				return nil
+			}
+			return fmt.Errorf("error al recibir un trabajo de NATS: %w", err)
+		}
+		job, err := unmarshal(msg.Data)
+		if err != nil {
+			q.logger.Error("Se descartó un trabajo inválido", zap.Error(err))
+			continue
+		}
+		_ = handler(ctx, job)
+	}
+}
+
+// Close espera a que se envíen los trabajos publicados y cierra la conexión.
+func (q *NATSQueue) Close() error {
+	return q.conn.Drain()
+}
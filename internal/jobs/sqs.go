@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"go.uber.org/zap"
+	"time"
+)
+
+// sqsWaitTimeSeconds es el tiempo de long polling de ReceiveMessage (el máximo permitido por SQS).
+const sqsWaitTimeSeconds = 20
+
+// sqsRetryDelay es la espera después de un error al recibir mensajes, para no saturar SQS mientras falla.
+var sqsRetryDelay = 5 * time.Second
+
+// SQSAPI son las operaciones de SQS usadas por SQSQueue.
+type SQSAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+}
+
+// SQSQueue implementa Queue sobre Amazon SQS. Los trabajos se borran de la cola solo si se procesan bien; si el
+// handler falla o el proceso se cae, SQS los vuelve a entregar cuando vence el visibility timeout de la cola.
+type SQSQueue struct {
+	client   SQSAPI
+	queueURL string
+	logger   logging.Logger
+}
+
+// NewSQSQueue crea una cola sobre la cola de SQS indicada.
+func NewSQSQueue(client SQSAPI, queueURL string, logger logging.Logger) *SQSQueue {
+	return &SQSQueue{client: client, queueURL: queueURL, logger: logger}
+}
+
+func (q *SQSQueue) Publish(ctx context.Context, job *Job) error {
+	data, err := marshal(job)
+	if err != nil {
+		return err
+	}
+	if _, err := q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(data)),
+	}); err != nil {
+		return fmt.Errorf("error al publicar el trabajo en SQS: %w", err)
+	}
+	return nil
+}
+
+func (q *SQSQueue) Consume(ctx context.Context, handler HandlerFunc) error {
+	for ctx.Err() == nil {
+		out, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(q.queueURL),
+			MaxNumberOfMessages: 1,
+			WaitTimeSeconds:     sqsWaitTimeSeconds,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			q.logger.Error("Error al recibir trabajos de SQS", zap.Error(err))
+			select {
+			case <-time.After(sqsRetryDelay):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, msg := range out.Messages {
+			job, err := unmarshal([]byte(aws.ToString(msg.Body)))
+			if err != nil {
+				// Un mensaje inválido nunca se va a poder procesar: se borra para que no se reentregue.
+				q.logger.Error("Se descartó un trabajo inválido", zap.Error(err))
+			} else if err := handler(ctx, job); err != nil {
+				continue
+			}
+			if _, err := q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(q.queueURL),
+				ReceiptHandle: msg.ReceiptHandle,
+			}); err != nil {
+				q.logger.Error("Error al borrar el trabajo de SQS", zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (q *SQSQueue) Close() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+const testQueueURL = "https://sqs.us-east-1.amazonaws.com/123/gomusicbot-jobs"
+
+func TestSQSQueue_Publish(t *testing.T) {
+	client := new(MockSQSAPI)
+	client.On("SendMessage", mock.MatchedBy(func(in *sqs.SendMessageInput) bool {
+		return aws.ToString(in.QueueUrl) == testQueueURL && aws.ToString(in.MessageBody) != ""
+	})).Return(&sqs.SendMessageOutput{}, nil)
+	queue := NewSQSQueue(client, testQueueURL, new(MockLogger))
+
+	assert.NoError(t, queue.Publish(context.Background(), &Job{Type: "lookup"}))
+	client.AssertExpectations(t)
+}
+
+func TestSQSQueue_Consume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := new(MockSQSAPI)
+	logger := new(MockLogger)
+	logger.On("Error", "Se descartó un trabajo inválido", mock.Anything).Return()
+	client.On("ReceiveMessage", mock.Anything).Return(&sqs.ReceiveMessageOutput{Messages: []types.Message{
+		{Body: aws.String(`{"type":"lookup","guild_id":"1"}`), ReceiptHandle: aws.String("ok")},
+		{Body: aws.String(`{"type":"lookup","guild_id":"2"}`), ReceiptHandle: aws.String("falla")},
+		{Body: aws.String(`no es json`), ReceiptHandle: aws.String("invalido")},
+	}}, nil).Once()
+	client.On("ReceiveMessage", mock.Anything).Run(func(mock.Arguments) { cancel() }).Return(&sqs.ReceiveMessageOutput{}, nil)
+	client.On("DeleteMessage", mock.Anything).Return(&sqs.DeleteMessageOutput{}, nil)
+	queue := NewSQSQueue(client, testQueueURL, logger)
+
+	var guilds []string
+	err := queue.Consume(ctx, func(_ context.Context, job *Job) error {
+		guilds = append(guilds, job.GuildID)
+		if job.GuildID == "2" {
+			return errors.New("falla")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, guilds)
+	// El trabajo que falló queda en la cola para que SQS lo reentregue; el inválido se borra.
+	client.AssertCalled(t, "DeleteMessage", &sqs.DeleteMessageInput{QueueUrl: aws.String(testQueueURL), ReceiptHandle: aws.String("ok")})
+	client.AssertCalled(t, "DeleteMessage", &sqs.DeleteMessageInput{QueueUrl: aws.String(testQueueURL), ReceiptHandle: aws.String("invalido")})
+	client.AssertNumberOfCalls(t, "DeleteMessage", 2)
+}
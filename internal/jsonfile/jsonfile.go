@@ -0,0 +1,78 @@
+// Package jsonfile guarda el estado de los stores en archivos JSON sin dejarlos a medio escribir.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Load crea el directorio de path y carga en v el JSON del archivo, si existe. Si no existe, v queda sin cambios.
+func Load(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error al crear el directorio: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al leer el archivo: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error al deserializar el archivo: %w", err)
+	}
+	return nil
+}
+
+// Save escribe v como JSON indentado en path con WriteFile.
+func Save(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar el archivo: %w", err)
+	}
+	return WriteFile(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// WriteFile escribe con write un archivo temporal al lado de path y lo renombra, para que path siempre tenga el
+// contenido anterior o el nuevo completo. Si write falla, path queda como estaba.
+func WriteFile(path string, write func(w io.Writer) error) error {
+	tmpPath := path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo temporal: %w", err)
+	}
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("error al escribir el archivo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("error al escribir el archivo: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Update aplica change, que solo modifica la entrada key de items, y guarda con save. Si no se puede guardar, deja
+// la entrada como estaba, para que lo que está en memoria no se aparte del archivo. Se llama con el lock del store
+// tomado.
+func Update[K comparable, V any](items map[K]V, key K, change func(), save func() error) error {
+	previous, existed := items[key]
+	change()
+	if err := save(); err != nil {
+		if existed {
+			items[key] = previous
+		} else {
+			delete(items, key)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package jsonfile
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store", "items.json")
+
+	items := map[string]int{"a": 1}
+	require.NoError(t, Load(path, &items))
+	assert.Equal(t, map[string]int{"a": 1}, items, "sin archivo no se cambia nada")
+
+	require.NoError(t, Save(path, map[string]int{"b": 2}))
+	loaded := make(map[string]int)
+	require.NoError(t, Load(path, &loaded))
+	assert.Equal(t, map[string]int{"b": 2}, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	assert.Error(t, Load(path, &loaded))
+}
+
+func TestWriteFile_KeepsPreviousContentOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.json")
+	require.NoError(t, os.WriteFile(path, []byte("anterior"), 0644))
+
+	err := WriteFile(path, func(w io.Writer) error {
+		_, _ = w.Write([]byte("a medio"))
+		return errors.New("falló")
+	})
+
+	require.Error(t, err)
+	data, readErr := os.ReadFile(path)
+	require.NoError(t, readErr)
+	assert.Equal(t, "anterior", string(data))
+	_, statErr := os.Stat(path + ".tmp")
+	assert.ErrorIs(t, statErr, os.ErrNotExist)
+}
+
+func TestUpdate(t *testing.T) {
+	failed := errors.New("disco lleno")
+	tests := []struct {
+		name   string
+		key    string
+		change func(items map[string]int)
+	}{
+		{name: "modificar", key: "a", change: func(items map[string]int) { items["a"] = 10 }},
+		{name: "agregar", key: "b", change: func(items map[string]int) { items["b"] = 2 }},
+		{name: "eliminar", key: "a", change: func(items map[string]int) { delete(items, "a") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := map[string]int{"a": 1}
+
+			err := Update(items, tt.key, func() { tt.change(items) }, func() error { return failed })
+
+			assert.ErrorIs(t, err, failed)
+			assert.Equal(t, map[string]int{"a": 1}, items, "si no se puede guardar, se deshace el cambio")
+		})
+	}
+
+	items := map[string]int{"a": 1}
+	require.NoError(t, Update(items, "a", func() { items["a"] = 2 }, func() error { return nil }))
+	assert.Equal(t, map[string]int{"a": 2}, items)
+}
@@ -0,0 +1,364 @@
+package lavalink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// clientName es el nombre con el que el bot se identifica ante el nodo de Lavalink.
+const clientName = "GoMusicBot"
+
+// ErrNotConnected se devuelve cuando se usa el cliente antes de que el nodo confirme la sesión.
+var ErrNotConnected = errors.New("no conectado al nodo de Lavalink")
+
+type (
+	// Track es una pista resuelta por Lavalink.
+	Track struct {
+		Encoded string    `json:"encoded"`
+		Info    TrackInfo `json:"info"`
+	}
+
+	// TrackInfo contiene los metadatos de una pista.
+	TrackInfo struct {
+		Identifier string  `json:"identifier"`
+		IsStream   bool    `json:"isStream"`
+		Length     int64   `json:"length"` // Length es la duración en milisegundos.
+		Title      string  `json:"title"`
+		Author     string  `json:"author"`
+		URI        string  `json:"uri"`
+		ArtworkURL *string `json:"artworkUrl"`
+	}
+
+	// PlayerEvent es un evento del reproductor de un servidor enviado por el nodo por el websocket.
+	PlayerEvent struct {
+		Op       string `json:"op"`
+		Type     string `json:"type"`
+		GuildID  string `json:"guildId"`
+		Reason   string `json:"reason"`
+		Position int64  `json:"-"` // Position es la posición de la pista en milisegundos (solo en playerUpdate).
+	}
+
+	// voiceState es la información de voz de Discord que Lavalink necesita para conectarse al canal.
+	voiceState struct {
+		Token     string `json:"token"`
+		Endpoint  string `json:"endpoint"`
+		SessionID string `json:"sessionId"`
+	}
+)
+
+// Client es un cliente de un nodo de Lavalink v4: usa la API REST para resolver pistas y controlar los
+// reproductores, y el websocket para recibir los eventos de reproducción.
+type Client struct {
+	restURL    string
+	wsURL      string
+	password   string
+	httpClient *http.Client
+	logger     logging.Logger
+
+	mu          sync.Mutex
+	userID      string
+	sessionID   string
+	voice       map[string]*voiceState      // voice contiene la información de voz de cada servidor.
+	voiceReady  map[string]chan struct{}    // voiceReady se cierra cuando la información de voz del servidor llegó a Lavalink.
+	subscribers map[string]chan PlayerEvent // subscribers recibe los eventos del reproductor de cada servidor.
+	conn        *websocket.Conn
+}
+
+// NewClient crea un cliente para el nodo de Lavalink en address (host:puerto).
+func NewClient(address, password string, secure bool, logger logging.Logger) *Client {
+	httpScheme, wsScheme := "http", "ws"
+	if secure {
+		httpScheme, wsScheme = "https", "wss"
+	}
+	return &Client{
+		restURL:     fmt.Sprintf("%s://%s/v4", httpScheme, address),
+		wsURL:       fmt.Sprintf("%s://%s/v4/websocket", wsScheme, address),
+		password:    password,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		logger:      logger,
+		voice:       make(map[string]*voiceState),
+		voiceReady:  make(map[string]chan struct{}),
+		subscribers: make(map[string]chan PlayerEvent),
+	}
+}
+
+// Connect abre el websocket con el nodo usando el ID de usuario del bot y espera a que confirme la sesión.
+// Los eventos se procesan en segundo plano hasta que se cancele el contexto.
+func (c *Client) Connect(ctx context.Context, userID string) error {
+	header := http.Header{}
+	header.Set("Authorization", c.password)
+	header.Set("User-Id", userID)
+	header.Set("Client-Name", clientName)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL, header)
+	if err != nil {
+		return fmt.Errorf("error al conectar con Lavalink: %w", err)
+	}
+
+	var ready struct {
+		Op        string `json:"op"`
+		SessionID string `json:"sessionId"`
+	}
+	if err := conn.ReadJSON(&ready); err != nil || ready.Op != "ready" {
+		_ = conn.Close()
+		return fmt.Errorf("Lavalink no confirmó la sesión: %v", err)
+	}
+
+	c.mu.Lock()
+	c.userID = userID
+	c.sessionID = ready.SessionID
+	c.conn = conn
+	c.mu.Unlock()
+	c.logger.Info("Conectado a Lavalink", zap.String("sessionID", ready.SessionID))
+
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+	go c.readEvents(conn)
+	return nil
+}
+
+// readEvents reparte los eventos recibidos por el websocket entre los reproductores de cada servidor.
+func (c *Client) readEvents(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			c.logger.Warn("Se cerró la conexión con Lavalink", zap.Error(err))
+			c.mu.Lock()
+			c.sessionID = ""
+			c.mu.Unlock()
+			return
+		}
+
+		var message struct {
+			PlayerEvent
+			State struct {
+				Position int64 `json:"position"`
+			} `json:"state"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.logger.Error("Mensaje de Lavalink inválido", zap.Error(err))
+			continue
+		}
+		if message.GuildID == "" {
+			continue
+		}
+		event := message.PlayerEvent
+		event.Position = message.State.Position
+
+		c.mu.Lock()
+		subscriber, ok := c.subscribers[event.GuildID]
+		c.mu.Unlock()
+		if !ok {
+			continue
+		}
+		select {
+		case subscriber <- event:
+		default:
+			// Los playerUpdate llegan periódicamente; si el reproductor está atrasado, se descarta el evento.
+		}
+	}
+}
+
+// Ready verifica que el nodo de Lavalink haya confirmado la sesión y que la conexión siga abierta.
+func (c *Client) Ready(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessionID == "" {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+// subscribe devuelve el canal con los eventos del reproductor del servidor.
+func (c *Client) subscribe(guildID string) chan PlayerEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan PlayerEvent, 16)
+	c.subscribers[guildID] = ch
+	return ch
+}
+
+func (c *Client) unsubscribe(guildID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subscribers, guildID)
+}
+
+// LoadTracks resuelve una URL o una búsqueda (por ejemplo "ytsearch:término") en pistas.
+func (c *Client) LoadTracks(ctx context.Context, identifier string) ([]Track, error) {
+	var result struct {
+		LoadType string          `json:"loadType"`
+		Data     json.RawMessage `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/loadtracks?identifier="+url.QueryEscape(identifier), nil, &result); err != nil {
+		return nil, err
+	}
+
+	switch result.LoadType {
+	case "track":
+		var track Track
+		if err := json.Unmarshal(result.Data, &track); err != nil {
+			return nil, fmt.Errorf("respuesta de Lavalink inválida: %w", err)
+		}
+		return []Track{track}, nil
+	case "search":
+		var tracks []Track
+		if err := json.Unmarshal(result.Data, &tracks); err != nil {
+			return nil, fmt.Errorf("respuesta de Lavalink inválida: %w", err)
+		}
+		return tracks, nil
+	case "playlist":
+		var playlist struct {
+			Tracks []Track `json:"tracks"`
+		}
+		if err := json.Unmarshal(result.Data, &playlist); err != nil {
+			return nil, fmt.Errorf("respuesta de Lavalink inválida: %w", err)
+		}
+		return playlist.Tracks, nil
+	case "empty":
+		return nil, nil
+	default:
+		var exception struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(result.Data, &exception)
+		return nil, fmt.Errorf("Lavalink no pudo cargar la pista: %s", exception.Message)
+	}
+}
+
+// updatePlayer actualiza el reproductor del servidor en el nodo.
+func (c *Client) updatePlayer(ctx context.Context, guildID string, update interface{}) error {
+	c.mu.Lock()
+	sessionID := c.sessionID
+	c.mu.Unlock()
+	if sessionID == "" {
+		return ErrNotConnected
+	}
+	return c.do(ctx, http.MethodPatch, fmt.Sprintf("/sessions/%s/players/%s", sessionID, guildID), update, nil)
+}
+
+// destroyPlayer elimina el reproductor del servidor en el nodo.
+func (c *Client) destroyPlayer(ctx context.Context, guildID string) error {
+	c.mu.Lock()
+	sessionID := c.sessionID
+	c.mu.Unlock()
+	if sessionID == "" {
+		return ErrNotConnected
+	}
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/sessions/%s/players/%s", sessionID, guildID), nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.restURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al llamar a Lavalink: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Lavalink respondió %d: %s", resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// OnVoiceStateUpdate guarda la sesión de voz del bot en cada servidor. Se registra como manejador de discordgo.
+func (c *Client) OnVoiceStateUpdate(_ *discordgo.Session, vs *discordgo.VoiceStateUpdate) {
+	c.mu.Lock()
+	if vs.UserID != c.userID {
+		c.mu.Unlock()
+		return
+	}
+	state := c.voiceStateLocked(vs.GuildID)
+	state.SessionID = vs.SessionID
+	c.mu.Unlock()
+	c.sendVoice(vs.GuildID)
+}
+
+// OnVoiceServerUpdate guarda el servidor de voz asignado a cada servidor. Se registra como manejador de discordgo.
+func (c *Client) OnVoiceServerUpdate(_ *discordgo.Session, vsu *discordgo.VoiceServerUpdate) {
+	c.mu.Lock()
+	state := c.voiceStateLocked(vsu.GuildID)
+	state.Token = vsu.Token
+	state.Endpoint = vsu.Endpoint
+	c.mu.Unlock()
+	c.sendVoice(vsu.GuildID)
+}
+
+func (c *Client) voiceStateLocked(guildID string) *voiceState {
+	state, ok := c.voice[guildID]
+	if !ok {
+		state = &voiceState{}
+		c.voice[guildID] = state
+	}
+	return state
+}
+
+// waitVoice devuelve un canal que se cierra cuando Lavalink recibió la información de voz del servidor.
+func (c *Client) waitVoice(guildID string) <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan struct{})
+	c.voiceReady[guildID] = ch
+	delete(c.voice, guildID)
+	return ch
+}
+
+// sendVoice envía a Lavalink la información de voz del servidor cuando Discord ya mandó los dos eventos.
+func (c *Client) sendVoice(guildID string) {
+	c.mu.Lock()
+	state, ok := c.voice[guildID]
+	if !ok || state.Token == "" || state.Endpoint == "" || state.SessionID == "" {
+		c.mu.Unlock()
+		return
+	}
+	voice := *state
+	ready := c.voiceReady[guildID]
+	delete(c.voiceReady, guildID)
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.updatePlayer(ctx, guildID, map[string]interface{}{"voice": voice}); err != nil {
+		c.logger.Error("Error al enviar la información de voz a Lavalink", zap.String("guildID", guildID), zap.Error(err))
+		return
+	}
+	if ready != nil {
+		close(ready)
+	}
+}
@@ -0,0 +1,237 @@
+package lavalink
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNode simula un nodo de Lavalink: registra las llamadas a la API REST y permite enviar eventos por el websocket.
+type fakeNode struct {
+	t       *testing.T
+	server  *httptest.Server
+	mu      sync.Mutex
+	patches []string
+	conn    *websocket.Conn
+	ready   chan struct{}
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	node := &fakeNode{t: t, ready: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/websocket", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secreto", r.Header.Get("Authorization"))
+		assert.Equal(t, "bot-1", r.Header.Get("User-Id"))
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		node.conn = conn
+		assert.NoError(t, conn.WriteJSON(map[string]interface{}{"op": "ready", "sessionId": "sesion-1"}))
+		close(node.ready)
+	})
+	mux.HandleFunc("/v4/loadtracks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("identifier") {
+		case "ytsearch:tema":
+			_, _ = io.WriteString(w, `{"loadType":"search","data":[{"encoded":"abc","info":{"identifier":"id-1","title":"Tema","uri":"https://www.youtube.com/watch?v=id-1","length":61000}}]}`)
+		case "https://www.youtube.com/watch?v=id-1":
+			_, _ = io.WriteString(w, `{"loadType":"track","data":{"encoded":"abc","info":{"identifier":"id-1","title":"Tema","uri":"https://www.youtube.com/watch?v=id-1","length":61000}}}`)
+		default:
+			_, _ = io.WriteString(w, `{"loadType":"empty","data":{}}`)
+		}
+	})
+	mux.HandleFunc("/v4/sessions/sesion-1/players/guild-1", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		node.mu.Lock()
+		node.patches = append(node.patches, r.Method+" "+string(body))
+		node.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{}`)
+	})
+	node.server = httptest.NewServer(mux)
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+func (n *fakeNode) send(event map[string]interface{}) {
+	<-n.ready
+	assert.NoError(n.t, n.conn.WriteJSON(event))
+}
+
+func (n *fakeNode) requests() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string{}, n.patches...)
+}
+
+func newConnectedClient(t *testing.T) (*Client, *fakeNode) {
+	node := newFakeNode(t)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	client := NewClient(strings.TrimPrefix(node.server.URL, "http://"), "secreto", false, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	assert.NoError(t, client.Connect(ctx, "bot-1"))
+	assert.NoError(t, client.Ready(ctx))
+	return client, node
+}
+
+func TestSongLooker(t *testing.T) {
+	client, _ := newConnectedClient(t)
+	looker := NewSongLooker(client)
+
+	videoID, err := looker.SearchYouTubeVideoID(context.Background(), "tema")
+	assert.NoError(t, err)
+	assert.Equal(t, "id-1", videoID)
+
+	songs, err := looker.LookupSongs(context.Background(), videoID)
+	assert.NoError(t, err)
+	assert.Len(t, songs, 1)
+	assert.Equal(t, "Tema", songs[0].Title)
+	assert.Equal(t, 61*time.Second, songs[0].Duration)
+
+	_, err = looker.SearchYouTubeVideoID(context.Background(), "nada")
+	assert.Error(t, err)
+}
+
+func TestSession_JoinAndPlayTrack(t *testing.T) {
+	client, node := newConnectedClient(t)
+	gateway := new(MockVoiceGateway)
+	gateway.On("ChannelVoiceJoinManual", "guild-1", "canal-1", false, true).Return(nil).Run(func(mock.Arguments) {
+		// Discord responde al cambio de canal con los dos eventos de voz.
+		go func() {
+			client.OnVoiceStateUpdate(nil, &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{UserID: "bot-1", GuildID: "guild-1", SessionID: "voz-1"}})
+			client.OnVoiceServerUpdate(nil, &discordgo.VoiceServerUpdate{GuildID: "guild-1", Token: "token", Endpoint: "endpoint"})
+		}()
+	})
+	session := NewSession(client, gateway, "guild-1", client.logger)
+
+	assert.NoError(t, session.JoinVoiceChannel("canal-1"))
+	var voiceUpdate struct {
+		Voice voiceState `json:"voice"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(node.requests()[0], "PATCH ")), &voiceUpdate))
+	assert.Equal(t, voiceState{Token: "token", Endpoint: "endpoint", SessionID: "voz-1"}, voiceUpdate.Voice)
+
+	var positions []time.Duration
+	done := make(chan error)
+	go func() {
+		done <- session.PlayTrack(context.Background(), &voice.Song{URL: "https://www.youtube.com/watch?v=id-1"}, func(d time.Duration) {
+			positions = append(positions, d)
+		})
+	}()
+	assert.Eventually(t, func() bool { return len(node.requests()) == 2 }, time.Second, 10*time.Millisecond)
+	node.send(map[string]interface{}{"op": "playerUpdate", "guildId": "guild-1", "state": map[string]interface{}{"position": 1500}})
+	node.send(map[string]interface{}{"op": "event", "type": "TrackEndEvent", "guildId": "guild-1", "reason": "finished"})
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("PlayTrack no terminó al recibir TrackEndEvent")
+	}
+	assert.Contains(t, node.requests()[1], `"encoded":"abc"`)
+	assert.Equal(t, []time.Duration{1500 * time.Millisecond}, positions)
+	assert.ErrorIs(t, session.SendAudio(context.Background(), nil, nil), errAudioNotSupported)
+}
+
+func TestSession_PlayTrackStopsOnCancel(t *testing.T) {
+	client, node := newConnectedClient(t)
+	session := NewSession(client, new(MockVoiceGateway), "guild-1", client.logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- session.PlayTrack(ctx, &voice.Song{URL: "https://www.youtube.com/watch?v=id-1"}, nil)
+	}()
+	assert.Eventually(t, func() bool { return len(node.requests()) == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	assert.NoError(t, <-done)
+	assert.Contains(t, node.requests()[1], `"encoded":null`)
+}
+
+func TestSession_PlayTrackFades(t *testing.T) {
+	client, node := newConnectedClient(t)
+	session := NewSession(client, new(MockVoiceGateway), "guild-1", client.logger).WithFade(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- session.PlayTrack(ctx, &voice.Song{URL: "https://www.youtube.com/watch?v=id-1", StartPosition: time.Minute}, nil)
+	}()
+	// Al retomar desde una posición arranca en silencio y sube el volumen hasta el original.
+	assert.Eventually(t, func() bool { return len(node.requests()) == 1+fadeSteps }, time.Second, 5*time.Millisecond)
+	assert.Contains(t, node.requests()[0], `"filters":{"volume":0}`)
+	assert.Contains(t, node.requests()[fadeSteps], `"filters":{"volume":1}`)
+	cancel()
+
+	// Al cancelar baja el volumen antes de detener la canción.
+	assert.NoError(t, <-done)
+	requests := node.requests()
+	assert.Len(t, requests, 2+2*fadeSteps)
+	assert.Contains(t, requests[2*fadeSteps], `"filters":{"volume":0}`)
+	assert.Contains(t, requests[len(requests)-1], `"encoded":null`)
+}
+
+func TestSession_SetPaused(t *testing.T) {
+	client, node := newConnectedClient(t)
+	session := NewSession(client, new(MockVoiceGateway), "guild-1", client.logger)
+
+	assert.NoError(t, session.SetPaused(context.Background(), true))
+	assert.Contains(t, node.requests()[0], `"paused":true`)
+
+	// La pausa sigue para las canciones que se empiecen después.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- session.PlayTrack(ctx, &voice.Song{URL: "https://www.youtube.com/watch?v=id-1"}, nil)
+	}()
+	assert.Eventually(t, func() bool { return len(node.requests()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Contains(t, node.requests()[1], `"paused":true`)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestSession_SetPausedFades(t *testing.T) {
+	client, node := newConnectedClient(t)
+	session := NewSession(client, new(MockVoiceGateway), "guild-1", client.logger).WithFade(10 * time.Millisecond)
+
+	// Baja el volumen y recién después pausa.
+	assert.NoError(t, session.SetPaused(context.Background(), true))
+	requests := node.requests()
+	assert.Len(t, requests, fadeSteps+1)
+	assert.Contains(t, requests[fadeSteps-1], `"filters":{"volume":0}`)
+	assert.Contains(t, requests[fadeSteps], `"paused":true`)
+
+	// Pausar de nuevo no repite el fundido.
+	assert.NoError(t, session.SetPaused(context.Background(), true))
+	assert.Len(t, node.requests(), fadeSteps+2)
+
+	// Retoma en silencio y sube el volumen.
+	assert.NoError(t, session.SetPaused(context.Background(), false))
+	requests = node.requests()
+	assert.Len(t, requests, 2*fadeSteps+3)
+	assert.Contains(t, requests[fadeSteps+2], `"paused":false`)
+	assert.Contains(t, requests[fadeSteps+2], `"filters":{"volume":0}`)
+	assert.Contains(t, requests[len(requests)-1], `"filters":{"volume":1}`)
+}
+
+func TestVolumeFilter(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"volume": 0.5}, volumeFilter(0.5))
+	// El filtro nunca sube el volumen por encima del elegido, para respetar el tope del servidor.
+	assert.Equal(t, map[string]interface{}{"volume": maxFilterGain}, volumeFilter(3))
+}
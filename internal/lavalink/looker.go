@@ -0,0 +1,73 @@
+package lavalink
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"strings"
+	"time"
+)
+
+// SongLooker implementa fetcher.SongLooker resolviendo las búsquedas con Lavalink en lugar de la API de YouTube.
+type SongLooker struct {
+	client *Client
+}
+
+// NewSongLooker crea un SongLooker que busca con el nodo de Lavalink.
+func NewSongLooker(client *Client) *SongLooker {
+	return &SongLooker{client: client}
+}
+
+// LookupSongs resuelve el ID de video (o la URL) en canciones.
+func (l *SongLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	identifier := input
+	if !strings.HasPrefix(input, "http") {
+		identifier = fmt.Sprintf("https://www.youtube.com/watch?v=%s", input)
+	}
+	tracks, err := l.client.LoadTracks(ctx, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener detalles del video: %w", err)
+	}
+
+	songs := make([]*voice.Song, 0, len(tracks))
+	for _, track := range tracks {
+		songs = append(songs, &voice.Song{
+			Type:         "lavalink",
+			Title:        track.Info.Title,
+			URL:          track.Info.URI,
+			Playable:     !track.Info.IsStream,
+			ThumbnailURL: track.Info.ArtworkURL,
+			Duration:     time.Duration(track.Info.Length) * time.Millisecond,
+			Uploader:     track.Info.Author,
+		})
+	}
+	return songs, nil
+}
+
+// SearchYouTubeVideoID busca el término en YouTube y devuelve el ID del primer resultado.
+func (l *SongLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	tracks, err := l.client.LoadTracks(ctx, "ytsearch:"+searchTerm)
+	if err != nil {
+		return "", fmt.Errorf("error al buscar el video en YouTube: %w", err)
+	}
+	if len(tracks) == 0 {
+		return "", fmt.Errorf("no se encontraron resultados para %q", searchTerm)
+	}
+	return tracks[0].Info.Identifier, nil
+}
+
+// SearchYouTubeVideoIDs busca el término en YouTube y devuelve los IDs de hasta limit resultados.
+func (l *SongLooker) SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	tracks, err := l.client.LoadTracks(ctx, "ytsearch:"+searchTerm)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar videos en YouTube: %w", err)
+	}
+	videoIDs := make([]string, 0, min(len(tracks), limit))
+	for _, track := range tracks {
+		if len(videoIDs) == limit {
+			break
+		}
+		videoIDs = append(videoIDs, track.Info.Identifier)
+	}
+	return videoIDs, nil
+}
@@ -0,0 +1,35 @@
+package lavalink
+
+import (
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockVoiceGateway struct {
+	mock.Mock
+}
+
+func (m *MockVoiceGateway) ChannelVoiceJoinManual(guildID, channelID string, mute, deaf bool) error {
+	args := m.Called(guildID, channelID, mute, deaf)
+	return args.Error(0)
+}
@@ -0,0 +1,226 @@
+package lavalink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// voiceConnectTimeout es el tiempo máximo que se espera a que Lavalink reciba la información de voz de Discord.
+	voiceConnectTimeout = 10 * time.Second
+	// fadeSteps es la cantidad de cambios de volumen con los que se hace un fundido.
+	fadeSteps = 10
+	// maxFilterGain es la ganancia máxima del filtro de volumen. Los filtros nunca suben el volumen por encima del
+	// elegido con SetVolume, que es el que respeta el tope del servidor.
+	maxFilterGain = 1.0
+)
+
+// errAudioNotSupported se devuelve si se intenta enviar audio desde el bot: con Lavalink el nodo lo transmite directo.
+var errAudioNotSupported = errors.New("con Lavalink el audio no se envía desde el bot")
+
+// VoiceGateway envuelve el método de discordgo.Session que envía al gateway los cambios de canal de voz.
+type VoiceGateway interface {
+	ChannelVoiceJoinManual(guildID, channelID string, mute, deaf bool) error
+}
+
+// Session implementa voice.VoiceChatSession, voice.TrackPlayer y voice.PauseController delegando la reproducción de
+// un servidor a Lavalink. El bot solo se une al canal por el gateway; la conexión de voz y la transcodificación las
+// hace el nodo.
+type Session struct {
+	client  *Client
+	gateway VoiceGateway
+	guildID string
+	logger  logging.Logger
+	fade    time.Duration // fade es la duración del fundido al detener, pausar y retomar una canción; cero lo desactiva.
+	paused  atomic.Bool   // paused indica que la reproducción está pausada; las canciones que se empiecen arrancan pausadas.
+}
+
+// NewSession crea una sesión de voz de Lavalink para el servidor indicado.
+func NewSession(client *Client, gateway VoiceGateway, guildID string, logger logging.Logger) *Session {
+	return &Session{
+		client:  client,
+		gateway: gateway,
+		guildID: guildID,
+		logger:  logger,
+	}
+}
+
+// WithFade establece la duración del fundido: al saltar, detener, mover o pausar una canción el audio baja
+// gradualmente en lugar de cortarse, y al retomarla sube de la misma forma. El fundido se aplica con el filtro de
+// volumen del nodo, sobre el audio decodificado, así que no cambia el volumen elegido con SetVolume.
+func (s *Session) WithFade(d time.Duration) *Session {
+	s.fade = d
+	return s
+}
+
+// Close abandona el canal de voz.
+func (s *Session) Close() error {
+	return s.LeaveVoiceChannel()
+}
+
+// JoinVoiceChannel se une al canal de voz y espera a que Lavalink se conecte.
+func (s *Session) JoinVoiceChannel(channelID string) error {
+	s.logger.Info("Uniéndose al canal de voz con Lavalink", zap.String("channelID", channelID))
+	ready := s.client.waitVoice(s.guildID)
+	if err := s.gateway.ChannelVoiceJoinManual(s.guildID, channelID, false, true); err != nil {
+		return fmt.Errorf("error al unirse al canal de voz: %w", err)
+	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-time.After(voiceConnectTimeout):
+		return fmt.Errorf("Lavalink no recibió la conexión de voz en %s", voiceConnectTimeout)
+	}
+}
+
+// LeaveVoiceChannel abandona el canal de voz y elimina el reproductor del nodo.
+func (s *Session) LeaveVoiceChannel() error {
+	if err := s.gateway.ChannelVoiceJoinManual(s.guildID, "", false, true); err != nil {
+		return fmt.Errorf("error al salir del canal de voz: %w", err)
+	}
+	s.paused.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.client.destroyPlayer(ctx, s.guildID); err != nil {
+		s.logger.Error("Error al eliminar el reproductor de Lavalink", zap.Error(err))
+	}
+	return nil
+}
+
+// SendAudio no está soportado: con Lavalink las canciones se reproducen con PlayTrack.
+func (s *Session) SendAudio(_ context.Context, _ io.Reader, _ func(time.Duration)) error {
+	return errAudioNotSupported
+}
+
+// SetVolume cambia el volumen del reproductor en el nodo. Lavalink acepta de 0 a 1000, donde 100 es el volumen original.
+func (s *Session) SetVolume(ctx context.Context, volume int) error {
+	if err := s.client.updatePlayer(ctx, s.guildID, map[string]interface{}{"volume": volume}); err != nil {
+		return fmt.Errorf("error al cambiar el volumen en Lavalink: %w", err)
+	}
+	return nil
+}
+
+// SetPaused pausa o retoma la canción en el nodo. La pausa sigue para las canciones siguientes hasta que se retome.
+// Con fundido, el audio baja antes de pausarse y sube al retomarse.
+func (s *Session) SetPaused(ctx context.Context, paused bool) error {
+	fade := s.fade > 0 && paused != s.paused.Load()
+	update := map[string]interface{}{"paused": paused}
+	if fade && paused {
+		if err := s.fadeVolume(ctx, 1, 0); err != nil {
+			return fmt.Errorf("error al bajar el volumen de la canción en Lavalink: %w", err)
+		}
+	} else if fade {
+		// Retoma en silencio, para que el fundido no empiece con el volumen completo.
+		update["filters"] = volumeFilter(0)
+	}
+	if err := s.client.updatePlayer(ctx, s.guildID, update); err != nil {
+		return fmt.Errorf("error al pausar la canción en Lavalink: %w", err)
+	}
+	s.paused.Store(paused)
+	if fade && !paused {
+		if err := s.fadeVolume(ctx, 0, 1); err != nil {
+			return fmt.Errorf("error al subir el volumen de la canción en Lavalink: %w", err)
+		}
+	}
+	return nil
+}
+
+// PlayTrack reproduce la canción en el nodo y espera a que termine o a que se cancele el contexto
+// (al saltar o detener la reproducción).
+func (s *Session) PlayTrack(ctx context.Context, song *voice.Song, positionCallback func(time.Duration)) error {
+	tracks, err := s.client.LoadTracks(ctx, song.URL)
+	if err != nil {
+		return err
+	}
+	if len(tracks) == 0 {
+		return fmt.Errorf("Lavalink no encontró la canción %s", song.URL)
+	}
+
+	events := s.client.subscribe(s.guildID)
+	defer s.client.unsubscribe(s.guildID)
+
+	// Al retomar una canción desde una posición (por ejemplo, después de moverla) arranca en silencio y sube.
+	fadeIn := s.fade > 0 && song.StartPosition > 0
+	gain := 1.0
+	if fadeIn {
+		gain = 0
+	}
+	update := map[string]interface{}{
+		"track":    map[string]interface{}{"encoded": tracks[0].Encoded},
+		"position": song.StartPosition.Milliseconds(),
+		"paused":   s.paused.Load(),
+		"filters":  volumeFilter(gain),
+	}
+	if err := s.client.updatePlayer(ctx, s.guildID, update); err != nil {
+		return fmt.Errorf("error al reproducir la canción en Lavalink: %w", err)
+	}
+	if fadeIn {
+		go func() {
+			if err := s.fadeVolume(ctx, 0, 1); err != nil && ctx.Err() == nil {
+				s.logger.Error("Error al subir el volumen de la canción en Lavalink", zap.Error(err))
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			// Pausada no suena, así que no hace falta bajar el volumen.
+			if s.fade > 0 && !s.paused.Load() {
+				if err := s.fadeVolume(stopCtx, 1, 0); err != nil {
+					s.logger.Error("Error al bajar el volumen de la canción en Lavalink", zap.Error(err))
+				}
+			}
+			stop := map[string]interface{}{"track": map[string]interface{}{"encoded": nil}}
+			if err := s.client.updatePlayer(stopCtx, s.guildID, stop); err != nil {
+				s.logger.Error("Error al detener la canción en Lavalink", zap.Error(err))
+			}
+			return nil
+		case event := <-events:
+			switch {
+			case event.Op == "playerUpdate":
+				if positionCallback != nil {
+					positionCallback(time.Duration(event.Position) * time.Millisecond)
+				}
+			case event.Type == "TrackEndEvent":
+				return nil
+			case event.Type == "TrackExceptionEvent", event.Type == "TrackStuckEvent":
+				return fmt.Errorf("Lavalink no pudo reproducir la canción: %s", event.Type)
+			}
+		}
+	}
+}
+
+// fadeVolume cambia el filtro de volumen de from a to en fadeSteps pasos repartidos en la duración del fundido.
+func (s *Session) fadeVolume(ctx context.Context, from, to float64) error {
+	interval := s.fade / fadeSteps
+	for step := 1; step <= fadeSteps; step++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		gain := from + (to-from)*float64(step)/fadeSteps
+		if err := s.client.updatePlayer(ctx, s.guildID, map[string]interface{}{"filters": volumeFilter(gain)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// volumeFilter es el filtro de volumen de Lavalink: multiplica el audio por gain. Lavalink acepta de 0 a 5, pero la
+// ganancia se limita a maxFilterGain.
+func volumeFilter(gain float64) map[string]interface{} {
+	return map[string]interface{}{"volume": math.Min(gain, maxFilterGain)}
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"os"
+	"strings"
+)
+
+type (
+	// Config contiene la configuración del logger del bot.
+	Config struct {
+		Level    string            `default:"info"` // Level es el nivel mínimo global (debug, info, warn, error).
+		Encoding string            `default:"json"` // Encoding es el formato de salida: json o console.
+		Stdout   bool              `default:"true"` // Stdout indica si los logs también se escriben en la salida estándar.
+		Modules  map[string]string // Modules permite definir niveles por módulo, por ej: "cache:warn,fetcher:debug".
+		File     FileConfig
+	}
+
+	// FileConfig contiene la configuración de la salida a archivo con rotación.
+	FileConfig struct {
+		Path       string `default:"../logs/myapp.log"` // Path es la ruta del archivo de logs. Vacío desactiva la salida a archivo.
+		MaxSizeMB  int    `default:"100"`               // MaxSizeMB es el tamaño máximo del archivo antes de rotarlo.
+		MaxBackups int    `default:"5"`                 // MaxBackups es la cantidad de archivos rotados que se conservan.
+		MaxAgeDays int    `default:"28"`                // MaxAgeDays es la cantidad de días que se conservan los archivos rotados.
+		Compress   bool   `default:"true"`              // Compress indica si los archivos rotados se comprimen con gzip.
+	}
+)
+
+// DefaultConfig es la configuración usada cuando no se carga ninguna desde el entorno.
+var DefaultConfig = Config{
+	Level:    "info",
+	Encoding: "json",
+	Stdout:   true,
+	File: FileConfig{
+		Path:       "../logs/myapp.log",
+		MaxSizeMB:  100,
+		MaxBackups: 5,
+		MaxAgeDays: 28,
+		Compress:   true,
+	},
+}
+
+// NewLogger crea un ZapLogger a partir de la configuración indicada.
+func NewLogger(cfg Config) (*ZapLogger, error) {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make(map[string]zapcore.Level, len(cfg.Modules))
+	minLevel := level
+	for module, levelName := range cfg.Modules {
+		moduleLevel, err := parseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("nivel inválido para el módulo %s: %w", module, err)
+		}
+		modules[module] = moduleLevel
+		if moduleLevel < minLevel {
+			minLevel = moduleLevel
+		}
+	}
+
+	encoder, err := newEncoder(cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var syncers []zapcore.WriteSyncer
+	if cfg.Stdout {
+		syncers = append(syncers, zapcore.Lock(os.Stdout))
+	}
+	if cfg.File.Path != "" {
+		syncers = append(syncers, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSizeMB,
+			MaxBackups: cfg.File.MaxBackups,
+			MaxAge:     cfg.File.MaxAgeDays,
+			Compress:   cfg.File.Compress,
+		}))
+	}
+	if len(syncers) == 0 {
+		return nil, fmt.Errorf("no hay ninguna salida de logs configurada")
+	}
+
+	// El core se construye con el nivel más bajo entre el global y los módulos; cada logger
+	// restringe después su propio nivel con zap.IncreaseLevel.
+	core := zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(syncers...), minLevel)
+	base := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+
+	return &ZapLogger{
+		logger:  base.WithOptions(zap.IncreaseLevel(level)),
+		base:    base,
+		modules: modules,
+		level:   level,
+	}, nil
+}
+
+// Named devuelve un logger para el módulo indicado, usando el nivel configurado para ese módulo
+// o el nivel global si no tiene uno propio.
+func (l *ZapLogger) Named(module string) *ZapLogger {
+	if l.base == nil {
+		return &ZapLogger{logger: l.logger.Named(module)}
+	}
+	level, ok := l.modules[module]
+	if !ok {
+		level = l.level
+	}
+	return &ZapLogger{
+		logger:  l.base.Named(module).WithOptions(zap.IncreaseLevel(level)),
+		base:    l.base,
+		modules: l.modules,
+		level:   l.level,
+	}
+}
+
+func parseLevel(name string) (zapcore.Level, error) {
+	if name == "" {
+		return zapcore.InfoLevel, nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(name))); err != nil {
+		return level, fmt.Errorf("nivel de log inválido %q: %w", name, err)
+	}
+	return level, nil
+}
+
+func newEncoder(encoding string) (zapcore.Encoder, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	switch strings.ToLower(encoding) {
+	case "", "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "console":
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("encoding de logs inválido: %s", encoding)
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_WritesJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(Config{Level: "info", Encoding: "json", File: FileConfig{Path: path, MaxSizeMB: 1}})
+	assert.NoError(t, err)
+
+	logger.Info("hola mundo")
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"hola mundo"`)
+}
+
+func TestNewLogger_ModuleLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(Config{
+		Level:    "error",
+		Encoding: "json",
+		Modules:  map[string]string{"fetcher": "info"},
+		File:     FileConfig{Path: path, MaxSizeMB: 1},
+	})
+	assert.NoError(t, err)
+
+	logger.Info("global descartado")
+	logger.Named("cache").Info("cache descartado")
+	logger.Named("fetcher").Info("fetcher registrado")
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "fetcher registrado")
+	assert.Contains(t, lines[0], `"logger":"fetcher"`)
+}
+
+func TestNewLogger_InvalidConfig(t *testing.T) {
+	_, err := NewLogger(Config{Level: "ruidoso", Stdout: true})
+	assert.Error(t, err)
+
+	_, err = NewLogger(Config{Encoding: "xml", Stdout: true})
+	assert.Error(t, err)
+
+	_, err = NewLogger(Config{Modules: map[string]string{"cache": "nope"}, Stdout: true})
+	assert.Error(t, err)
+
+	_, err = NewLogger(Config{})
+	assert.Error(t, err)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := parseLevel("WARN")
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.WarnLevel, level)
+
+	level, err = parseLevel("")
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, level)
+}
+
+func TestNewLambdaLogger(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	logger, err := NewLambdaLogger(getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.InfoLevel, logger.level)
+
+	env["LOG_LEVEL"] = "debug"
+	logger, err = NewLambdaLogger(getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, logger.level)
+
+	env["LOG_LEVEL"] = "ruidoso"
+	_, err = NewLambdaLogger(getenv)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CorrelationIDField es el nombre del campo de log que contiene el ID de correlación.
+const CorrelationIDField = "correlationID"
+
+type correlationIDKey struct{}
+
+// NewCorrelationID genera un ID de correlación corto (6 caracteres hexadecimales), pensado para
+// que los usuarios lo puedan copiar en un reporte y se pueda buscar en los logs.
+func NewCorrelationID() string {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// ContextWithCorrelationID devuelve un contexto que lleva el ID de correlación indicado.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext devuelve el ID de correlación del contexto, o "" si no tiene uno.
+func CorrelationIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// FromContext devuelve un logger que agrega el ID de correlación del contexto a cada mensaje.
+// Si el contexto no tiene un ID de correlación, devuelve el mismo logger.
+func FromContext(ctx context.Context, logger Logger) Logger {
+	id := CorrelationIDFromContext(ctx)
+	if id == "" {
+		return logger
+	}
+	return WithFields(logger, zap.String(CorrelationIDField, id))
+}
+
+// fieldLogger es un Logger que agrega campos fijos a cada mensaje del logger subyacente.
+type fieldLogger struct {
+	logger Logger
+	fields []zapcore.Field
+}
+
+// WithFields devuelve un logger que agrega los campos indicados a cada mensaje.
+func WithFields(logger Logger, fields ...zapcore.Field) Logger {
+	return &fieldLogger{logger: logger, fields: fields}
+}
+
+// Info registra un mensaje informativo con los campos fijos.
+func (l *fieldLogger) Info(msg string, fields ...zapcore.Field) {
+	l.logger.Info(msg, append(fields, l.fields...)...)
+}
+
+// Warn registra una advertencia con los campos fijos.
+func (l *fieldLogger) Warn(msg string, fields ...zapcore.Field) {
+	l.logger.Warn(msg, append(fields, l.fields...)...)
+}
+
+// Error registra un mensaje de error con los campos fijos.
+func (l *fieldLogger) Error(msg string, fields ...zapcore.Field) {
+	l.logger.Error(msg, append(fields, l.fields...)...)
+}
+
+// With agrega campos fijos al logger.
+func (l *fieldLogger) With(fields ...zapcore.Field) {
+	l.fields = append(l.fields, fields...)
+}
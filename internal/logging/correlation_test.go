@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	id := NewCorrelationID()
+	assert.Len(t, id, 6)
+	assert.NotEqual(t, id, NewCorrelationID())
+}
+
+func TestCorrelationIDFromContext(t *testing.T) {
+	assert.Empty(t, CorrelationIDFromContext(context.Background()))
+
+	ctx := ContextWithCorrelationID(context.Background(), "ab12cd")
+	assert.Equal(t, "ab12cd", CorrelationIDFromContext(ctx))
+}
+
+func TestFromContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(Config{Level: "info", Encoding: "json", File: FileConfig{Path: path, MaxSizeMB: 1}})
+	assert.NoError(t, err)
+
+	assert.Same(t, logger, FromContext(context.Background(), logger))
+
+	FromContext(ContextWithCorrelationID(context.Background(), "ab12cd"), logger).Error("falló la búsqueda")
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"correlationID":"ab12cd"`)
+}
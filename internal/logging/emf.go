@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"time"
+)
+
+// Unidades de CloudWatch de las métricas.
+const (
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+)
+
+type (
+	// emfMetadata es la clave _aws de un registro de CloudWatch Embedded Metric Format. CloudWatch Logs extrae la
+	// métrica de cualquier línea JSON que la tenga, así que los registros pueden llevar los campos del logger.
+	emfMetadata struct {
+		Timestamp         int64           `json:"Timestamp"`
+		CloudWatchMetrics []emfDirectives `json:"CloudWatchMetrics"`
+	}
+
+	emfDirectives struct {
+		Namespace  string       `json:"Namespace"`
+		Dimensions [][]string   `json:"Dimensions"`
+		Metrics    []emfMetrics `json:"Metrics"`
+	}
+
+	emfMetrics struct {
+		Name string `json:"Name"`
+		Unit string `json:"Unit"`
+	}
+)
+
+// EMFFields devuelve los campos de un registro de CloudWatch Embedded Metric Format con la métrica name y sus
+// dimensiones. Escritos por un logger JSON, CloudWatch publica la métrica sin necesidad de Prometheus.
+func EMFFields(now time.Time, namespace, name string, value float64, unit string, dimensions map[string]string) []zapcore.Field {
+	names := make([]string, 0, len(dimensions))
+	for dimension := range dimensions {
+		names = append(names, dimension)
+	}
+	sort.Strings(names)
+
+	fields := make([]zapcore.Field, 0, len(names)+2)
+	fields = append(fields, zap.Any("_aws", emfMetadata{
+		Timestamp: now.UnixMilli(),
+		CloudWatchMetrics: []emfDirectives{{
+			Namespace:  namespace,
+			Dimensions: [][]string{names},
+			Metrics:    []emfMetrics{{Name: name, Unit: unit}},
+		}},
+	}))
+	fields = append(fields, zap.Float64(name, value))
+	for _, dimension := range names {
+		fields = append(fields, zap.String(dimension, dimensions[dimension]))
+	}
+	return fields
+}
+
+// DefaultNamespace es el namespace de CloudWatch de las métricas del bot y de las lambdas.
+const DefaultNamespace = "GoMusicBot"
+
+// Count escribe un contador de CloudWatch en DefaultNamespace. Lo usan las lambdas, cuyos logs ya van a CloudWatch.
+func (l *ZapLogger) Count(name string, value float64, dimensions map[string]string) {
+	l.logger.Info("Métrica", EMFFields(time.Now(), DefaultNamespace, name, value, UnitCount, dimensions)...)
+}
@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEMFFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(Config{Level: "info", Encoding: "json", File: FileConfig{Path: path, MaxSizeMB: 1}})
+	assert.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	logger.Info("Métrica", EMFFields(now, "GoMusicBot", "CommandUsage", 1, UnitCount, map[string]string{"command": "PlaySong"})...)
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, float64(1), record["CommandUsage"])
+	assert.Equal(t, "PlaySong", record["command"])
+	assert.Equal(t, map[string]interface{}{
+		"Timestamp": float64(now.UnixMilli()),
+		"CloudWatchMetrics": []interface{}{map[string]interface{}{
+			"Namespace":  "GoMusicBot",
+			"Dimensions": []interface{}{[]interface{}{"command"}},
+			"Metrics":    []interface{}{map[string]interface{}{"Name": "CommandUsage", "Unit": "Count"}},
+		}},
+	}, record["_aws"])
+}
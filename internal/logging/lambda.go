@@ -0,0 +1,7 @@
+package logging
+
+// NewLambdaLogger crea el logger de las lambdas: JSON a la salida estándar, que Lambda envía a CloudWatch Logs,
+// con el nivel mínimo de LOG_LEVEL (info si no está definido).
+func NewLambdaLogger(getenv func(string) string) (*ZapLogger, error) {
+	return NewLogger(Config{Level: getenv("LOG_LEVEL"), Encoding: "json", Stdout: true})
+}
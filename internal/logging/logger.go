@@ -8,13 +8,17 @@ import (
 // Logger define la interfaz para los métodos de registro de información y error.
 type Logger interface {
 	Info(msg string, fields ...zapcore.Field)  // Info registra un mensaje informativo.
+	Warn(msg string, fields ...zapcore.Field)  // Warn registra una advertencia.
 	Error(msg string, fields ...zapcore.Field) // Error registra un mensaje de error.
 	With(fields ...zapcore.Field)
 }
 
 // ZapLogger es una implementación de la interfaz Logger utilizando Zap Logger.
 type ZapLogger struct {
-	logger *zap.Logger
+	logger  *zap.Logger
+	base    *zap.Logger              // base es el logger sin restricción de nivel, usado para derivar loggers por módulo.
+	modules map[string]zapcore.Level // modules contiene los niveles configurados por módulo.
+	level   zapcore.Level            // level es el nivel global configurado.
 }
 
 // NewZapLogger crea una nueva instancia de ZapLogger.
@@ -49,6 +53,11 @@ func (l *ZapLogger) Info(msg string, fields ...zapcore.Field) {
 	l.logger.Info(msg, fields...)
 }
 
+// Warn registra una advertencia.
+func (l *ZapLogger) Warn(msg string, fields ...zapcore.Field) {
+	l.logger.Warn(msg, fields...)
+}
+
 // Error registra un mensaje de error.
 func (l *ZapLogger) Error(msg string, fields ...zapcore.Field) {
 	l.logger.Error(msg, fields...)
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"fmt"
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// sentryFlushTimeout es el tiempo máximo que se espera al vaciar los eventos pendientes de Sentry.
+const sentryFlushTimeout = 2 * time.Second
+
+// sentryTagKeys son los campos del log que se envían a Sentry como tags para poder filtrar por ellos.
+var sentryTagKeys = map[string]bool{
+	"guildID":   true,
+	"command":   true,
+	"userID":    true,
+	"channelID": true,
+}
+
+// sentryCore es un zapcore.Core que envía a Sentry los logs de nivel error o superior.
+type sentryCore struct {
+	zapcore.LevelEnabler
+	hub    *sentry.Hub
+	fields []zapcore.Field
+}
+
+// NewSentryCore crea un zapcore.Core que reporta a Sentry los logs a partir del nivel indicado.
+func NewSentryCore(hub *sentry.Hub, level zapcore.Level) zapcore.Core {
+	return &sentryCore{LevelEnabler: level, hub: hub}
+}
+
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &clone
+}
+
+func (c *sentryCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *sentryCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	var errs []error
+	for _, field := range append(append([]zapcore.Field{}, c.fields...), fields...) {
+		if field.Type == zapcore.ErrorType {
+			if err, ok := field.Interface.(error); ok {
+				errs = append(errs, err)
+			}
+		}
+		field.AddTo(encoder)
+	}
+
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(entry.Level)
+	event.Message = entry.Message
+	event.Logger = entry.LoggerName
+	event.Timestamp = entry.Time
+	for key, value := range encoder.Fields {
+		if sentryTagKeys[key] {
+			event.Tags[key] = fmt.Sprint(value)
+			continue
+		}
+		event.Extra[key] = value
+	}
+	for _, err := range errs {
+		event.Exception = append(event.Exception, sentry.Exception{
+			Type:  entry.Message,
+			Value: err.Error(),
+		})
+	}
+	if entry.Caller.Defined {
+		event.Extra["caller"] = entry.Caller.TrimmedPath()
+	}
+
+	c.hub.CaptureEvent(event)
+	return nil
+}
+
+func (c *sentryCore) Sync() error {
+	c.hub.Flush(sentryFlushTimeout)
+	return nil
+}
+
+func sentryLevel(level zapcore.Level) sentry.Level {
+	switch level {
+	case zapcore.DebugLevel:
+		return sentry.LevelDebug
+	case zapcore.InfoLevel:
+		return sentry.LevelInfo
+	case zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
+
+// WithSentry devuelve una copia del logger que además reporta a Sentry los logs de nivel error.
+// Los loggers derivados con Named también reportan a Sentry.
+func (l *ZapLogger) WithSentry(hub *sentry.Hub) *ZapLogger {
+	tee := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, NewSentryCore(hub, zapcore.ErrorLevel))
+	})
+	clone := *l
+	clone.logger = l.logger.WithOptions(tee)
+	if l.base != nil {
+		clone.base = l.base.WithOptions(tee)
+	}
+	return &clone
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"errors"
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (f *fakeTransport) Flush(time.Duration) bool       { return true }
+func (f *fakeTransport) Configure(sentry.ClientOptions) {}
+func (f *fakeTransport) SendEvent(event *sentry.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+func TestZapLogger_WithSentry(t *testing.T) {
+	transport := &fakeTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: transport})
+	assert.NoError(t, err)
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	logger, err := NewLogger(Config{Level: "info", File: FileConfig{Path: filepath.Join(t.TempDir(), "bot.log")}})
+	assert.NoError(t, err)
+	logger = logger.WithSentry(hub)
+
+	logger.Info("no se reporta")
+	logger.Named("fetcher").Error("falló la descarga", zap.String("guildID", "guild-1"), zap.String("videoID", "abc"), zap.Error(errors.New("timeout")))
+
+	if assert.Len(t, transport.events, 1) {
+		event := transport.events[0]
+		assert.Equal(t, "falló la descarga", event.Message)
+		assert.Equal(t, "fetcher", event.Logger)
+		assert.Equal(t, sentry.LevelError, event.Level)
+		assert.Equal(t, "guild-1", event.Tags["guildID"])
+		assert.Equal(t, "abc", event.Extra["videoID"])
+		if assert.Len(t, event.Exception, 1) {
+			assert.Equal(t, "timeout", event.Exception[0].Value)
+		}
+	}
+}
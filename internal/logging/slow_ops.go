@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"time"
+)
+
+// SlowOpThresholds contiene los umbrales a partir de los cuales una operación se considera lenta.
+// Un umbral de 0 desactiva la advertencia para esa operación.
+type SlowOpThresholds struct {
+	Lookup       time.Duration `default:"3s"` // Lookup es el umbral para la búsqueda de una canción.
+	EncodeStart  time.Duration `default:"5s"` // EncodeStart es el umbral hasta que el audio codificado empieza a llegar.
+	VoiceConnect time.Duration `default:"5s"` // VoiceConnect es el umbral para conectarse a un canal de voz.
+}
+
+// WarnIfSlow registra una advertencia estructurada si la operación tardó más que el umbral indicado.
+func WarnIfSlow(logger Logger, operation string, elapsed, threshold time.Duration, fields ...zap.Field) {
+	if threshold <= 0 || elapsed <= threshold {
+		return
+	}
+	logger.Warn("Operación lenta", append([]zap.Field{
+		zap.String("operation", operation),
+		zap.Duration("elapsed", elapsed),
+		zap.Duration("threshold", threshold),
+	}, fields...)...)
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarnIfSlow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(Config{Level: "info", Encoding: "json", File: FileConfig{Path: path, MaxSizeMB: 1}})
+	assert.NoError(t, err)
+
+	WarnIfSlow(logger, "lookup", time.Second, 2*time.Second)
+	WarnIfSlow(logger, "lookup", 3*time.Second, 0)
+	WarnIfSlow(logger, "voice_connect", 3*time.Second, 2*time.Second, zap.String("guildID", "guild-1"))
+	assert.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if assert.Len(t, lines, 1) {
+		assert.Contains(t, lines[0], `"level":"warn"`)
+		assert.Contains(t, lines[0], `"operation":"voice_connect"`)
+		assert.Contains(t, lines[0], `"guildID":"guild-1"`)
+	}
+}
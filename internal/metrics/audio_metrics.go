@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+type (
+	// AudioPrometheusMetrics contiene las métricas de temporización de los frames de audio enviados a Discord.
+	AudioPrometheusMetrics struct {
+		frameDelay    *prometheus.HistogramVec
+		droppedFrames *prometheus.CounterVec
+		lateFrames    *prometheus.CounterVec
+	}
+)
+
+// NewAudioMetrics crea una nueva instancia de AudioMetrics.
+func NewAudioMetrics() *AudioPrometheusMetrics {
+	return &AudioPrometheusMetrics{
+		frameDelay: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "audio_frame_interval_seconds",
+			Help:    "Tiempo entre el envío de dos frames de audio consecutivos",
+			Buckets: []float64{0.005, 0.01, 0.015, 0.02, 0.025, 0.03, 0.04, 0.06, 0.1, 0.25, 0.5, 1},
+		}, []string{"guild_id"}),
+		droppedFrames: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "audio_frames_dropped_total",
+			Help: "Número total de frames de audio descartados porque la conexión de voz no los aceptó a tiempo",
+		}, []string{"guild_id"}),
+		lateFrames: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "audio_frames_late_total",
+			Help: "Número total de frames de audio enviados con más retraso que la duración de un frame",
+		}, []string{"guild_id"}),
+	}
+}
+
+// Describe implementa el método Describe de prometheus.Collector.
+func (a *AudioPrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	a.frameDelay.Describe(ch)
+	a.droppedFrames.Describe(ch)
+	a.lateFrames.Describe(ch)
+}
+
+// Collect implementa el método Collect de prometheus.Collector.
+func (a *AudioPrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	a.frameDelay.Collect(ch)
+	a.droppedFrames.Collect(ch)
+	a.lateFrames.Collect(ch)
+}
+
+func (a *AudioPrometheusMetrics) ObserveFrameDelay(guildID string, delay time.Duration) {
+	a.frameDelay.WithLabelValues(guildID).Observe(delay.Seconds())
+}
+
+func (a *AudioPrometheusMetrics) IncDroppedFrames(guildID string) {
+	a.droppedFrames.WithLabelValues(guildID).Inc()
+}
+
+func (a *AudioPrometheusMetrics) IncLateFrames(guildID string) {
+	a.lateFrames.WithLabelValues(guildID).Inc()
+}
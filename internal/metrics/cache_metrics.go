@@ -21,7 +21,7 @@ type (
 )
 
 // NewCacheMetrics crea una nueva instancia de CacheMetrics.
-func NewCacheMetrics() CacheMetrics {
+func NewCacheMetrics() *CachePrometheusMetrics {
 	return &CachePrometheusMetrics{
 		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "cache_hits_total",
@@ -64,7 +64,7 @@ func NewCacheMetrics() CacheMetrics {
 	}
 }
 
-// Describe implementa el método Describe de la interfaz CacheMetrics.
+// Describe implementa el método Describe de prometheus.Collector.
 func (c *CachePrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
 	c.cacheHits.Describe(ch)
 	c.cacheMisses.Describe(ch)
@@ -77,7 +77,7 @@ func (c *CachePrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
 	c.latencySet.Describe(ch)
 }
 
-// Collect implementa el método Collect de la interfaz CacheMetrics.
+// Collect implementa el método Collect de prometheus.Collector.
 func (c *CachePrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
 	c.cacheHits.Collect(ch)
 	c.cacheMisses.Collect(ch)
@@ -1,20 +1,15 @@
 package metrics
 
-import (
-	"github.com/prometheus/client_golang/prometheus"
-	"time"
-)
+import "time"
 
-// CustomMetric define la interfaz que deben cumplir todas las métricas personalizadas.
+// CustomMetric define la interfaz que deben cumplir todas las métricas personalizadas. Las interfaces de métricas no
+// dependen del backend: las implementaciones de Prometheus además son colectores que se registran en el
+// PrometheusRegistry, y las de Recorder envían cada valor a otro backend, como StatsD.
 type CustomMetric interface {
-	Describe(chan<- *prometheus.Desc)
-	Collect(chan<- prometheus.Metric)
 	Inc(labels ...string)
 }
 
 type CacheMetrics interface {
-	Describe(chan<- *prometheus.Desc)
-	Collect(chan<- prometheus.Metric)
 	IncHits(cacheType string)
 	IncMisses(cacheType string)
 	SetCacheSize(size float64)
@@ -25,3 +20,23 @@ type CacheMetrics interface {
 	IncLatencyGet(cacheType string, duration time.Duration)
 	IncLatencySet(cacheType string, duration time.Duration)
 }
+
+type AudioMetrics interface {
+	ObserveFrameDelay(guildID string, delay time.Duration)
+	IncDroppedFrames(guildID string)
+	IncLateFrames(guildID string)
+}
+
+// FetcherMetrics es una CustomMetric cuyo Inc cuenta los errores del fetcher (etiquetas operation y cause),
+// con métodos adicionales para la duración de las operaciones y los resultados de caché.
+type FetcherMetrics interface {
+	CustomMetric
+	ObserveDuration(operation string, duration time.Duration)
+	IncCacheResult(operation, result string)
+}
+
+// GaugeMetric es una CustomMetric que además puede decrementarse.
+type GaugeMetric interface {
+	CustomMetric
+	Dec(labels ...string)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"time"
+)
+
+// emfMessage es el mensaje de los registros de métricas de CloudWatch.
+const emfMessage = "Métrica"
+
+// EMFCounter envuelve un contador para que cada Inc también escriba un registro de CloudWatch Embedded Metric
+// Format con el logger. Así, en los despliegues en AWS las métricas llegan a CloudWatch junto con los logs, sin
+// correr Prometheus; el contador envuelto se sigue exponiendo en /metrics.
+type EMFCounter struct {
+	CustomMetric
+	logger    logging.Logger
+	namespace string
+	name      string
+	labels    []string
+	now       func() time.Time
+}
+
+// NewEMFCounter crea un EMFCounter. labels son los nombres de las etiquetas de Inc, en el mismo orden, que se
+// publican como dimensiones de la métrica name.
+func NewEMFCounter(next CustomMetric, logger logging.Logger, namespace, name string, labels ...string) *EMFCounter {
+	return &EMFCounter{CustomMetric: next, logger: logger, namespace: namespace, name: name, labels: labels, now: time.Now}
+}
+
+func (c *EMFCounter) Inc(labels ...string) {
+	c.CustomMetric.Inc(labels...)
+	dimensions := make(map[string]string, len(c.labels))
+	for i, label := range c.labels {
+		if i < len(labels) {
+			dimensions[label] = labels[i]
+		}
+	}
+	c.logger.Info(emfMessage, logging.EMFFields(c.now(), c.namespace, c.name, 1, logging.UnitCount, dimensions)...)
+}
+
+// emfFetcherMetrics publica en CloudWatch los errores del fetcher, que se cuentan con Inc.
+type emfFetcherMetrics struct {
+	FetcherMetrics
+	errors *EMFCounter
+}
+
+// NewEMFFetcherMetrics envuelve las métricas del fetcher para que los errores también se publiquen en CloudWatch
+// como la métrica FetcherErrors, con las dimensiones operation y cause.
+func NewEMFFetcherMetrics(next FetcherMetrics, logger logging.Logger, namespace string) FetcherMetrics {
+	return &emfFetcherMetrics{FetcherMetrics: next, errors: NewEMFCounter(next, logger, namespace, "FetcherErrors", "operation", "cause")}
+}
+
+func (m *emfFetcherMetrics) Inc(labels ...string) {
+	m.errors.Inc(labels...)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// FetcherPrometheusMetrics contiene las métricas de las operaciones del fetcher (búsqueda de metadatos y descarga de audio).
+// Inc cuenta los errores etiquetados por operación y causa, de modo que se registra como cualquier CustomMetric.
+type FetcherPrometheusMetrics struct {
+	errors      *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	cacheResult *prometheus.CounterVec
+}
+
+// NewFetcherMetrics crea una nueva instancia de FetcherMetrics.
+func NewFetcherMetrics() *FetcherPrometheusMetrics {
+	return &FetcherPrometheusMetrics{
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fetcher_errors_total",
+			Help: "Número total de errores del fetcher, etiquetados por operación y causa",
+		}, []string{"operation", "cause"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fetcher_operation_duration_seconds",
+			Help:    "Duración de las operaciones del fetcher",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		}, []string{"operation"}),
+		cacheResult: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fetcher_cache_results_total",
+			Help: "Número total de aciertos y fallos de caché de las operaciones del fetcher",
+		}, []string{"operation", "result"}),
+	}
+}
+
+func (f *FetcherPrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	f.errors.Describe(ch)
+	f.duration.Describe(ch)
+	f.cacheResult.Describe(ch)
+}
+
+func (f *FetcherPrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	f.errors.Collect(ch)
+	f.duration.Collect(ch)
+	f.cacheResult.Collect(ch)
+}
+
+// Inc incrementa el contador de errores. Espera las etiquetas operation y cause.
+func (f *FetcherPrometheusMetrics) Inc(labels ...string) {
+	f.errors.WithLabelValues(labels...).Inc()
+}
+
+func (f *FetcherPrometheusMetrics) ObserveDuration(operation string, duration time.Duration) {
+	f.duration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (f *FetcherPrometheusMetrics) IncCacheResult(operation, result string) {
+	f.cacheResult.WithLabelValues(operation, result).Inc()
+}
@@ -11,8 +11,7 @@ import (
 )
 
 type RegistryMetric interface {
-	Register(metric CustomMetric)
-	RegisterCacheMetrics(cacheMetrics CacheMetrics)
+	Register(collector prometheus.Collector)
 	RegisterStandardMetrics()
 	GetRegistry() *prometheus.Registry
 }
@@ -27,16 +26,14 @@ func NewPrometheusRegistry() *PrometheusRegistry {
 	}
 }
 
-func (pr *PrometheusRegistry) RegisterCacheMetrics(cacheMetrics CacheMetrics) {
-	pr.registry.MustRegister(cacheMetrics)
-}
-
-func (pr *PrometheusRegistry) Register(metric CustomMetric) {
-	pr.registry.MustRegister(metric)
+// Register registra una métrica de Prometheus, como las que crean NewCommandUsageCounter o NewCacheMetrics.
+func (pr *PrometheusRegistry) Register(collector prometheus.Collector) {
+	pr.registry.MustRegister(collector)
 }
 
 func (pr *PrometheusRegistry) RegisterStandardMetrics() {
 	pr.registry.MustRegister(collectors.NewBuildInfoCollector())
+	pr.registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 	pr.registry.MustRegister(collectors.NewGoCollector(
 		collectors.WithGoCollectorRuntimeMetrics(collectors.GoRuntimeMetricsRule{Matcher: regexp.MustCompile("/.*")}),
 	))
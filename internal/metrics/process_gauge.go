@@ -0,0 +1,36 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// ExternalProcessGauge cuenta los procesos externos (yt-dlp, ffmpeg, dca) que están corriendo,
+// para poder detectar procesos que quedan colgados en el pipeline de audio.
+type ExternalProcessGauge struct {
+	gaugeVec *prometheus.GaugeVec
+}
+
+func NewExternalProcessGauge() *ExternalProcessGauge {
+	return &ExternalProcessGauge{
+		gaugeVec: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "external_processes_running",
+			Help: "Número de procesos externos en ejecución, etiquetados por nombre del proceso",
+		},
+			[]string{"process"},
+		),
+	}
+}
+
+func (g *ExternalProcessGauge) Describe(ch chan<- *prometheus.Desc) {
+	g.gaugeVec.Describe(ch)
+}
+
+func (g *ExternalProcessGauge) Collect(ch chan<- prometheus.Metric) {
+	g.gaugeVec.Collect(ch)
+}
+
+func (g *ExternalProcessGauge) Inc(labels ...string) {
+	g.gaugeVec.WithLabelValues(labels...).Inc()
+}
+
+func (g *ExternalProcessGauge) Dec(labels ...string) {
+	g.gaugeVec.WithLabelValues(labels...).Dec()
+}
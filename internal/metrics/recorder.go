@@ -0,0 +1,168 @@
+package metrics
+
+import "time"
+
+// Recorder envía métricas a un backend que no es Prometheus, como StatsD o Datadog. Cada llamada reporta un valor
+// con las etiquetas de la métrica; el backend se encarga de agregarlos.
+type Recorder interface {
+	// Count suma value al contador name.
+	Count(name string, value float64, tags map[string]string)
+	// AddGauge suma delta, que puede ser negativo, al gauge name.
+	AddGauge(name string, delta float64, tags map[string]string)
+	// SetGauge cambia el valor del gauge name.
+	SetGauge(name string, value float64, tags map[string]string)
+	// Observe registra una duración, en segundos, en el histograma name.
+	Observe(name string, value float64, tags map[string]string)
+}
+
+// NopRecorder es un Recorder que descarta las métricas, para cuando no hay un backend de métricas configurado.
+type NopRecorder struct{}
+
+func (NopRecorder) Count(string, float64, map[string]string)    {}
+func (NopRecorder) AddGauge(string, float64, map[string]string) {}
+func (NopRecorder) SetGauge(string, float64, map[string]string) {}
+func (NopRecorder) Observe(string, float64, map[string]string)  {}
+
+// recorderTags arma las etiquetas de una métrica con los nombres de labels y los valores de values, en el mismo
+// orden.
+func recorderTags(labels []string, values ...string) map[string]string {
+	tags := make(map[string]string, len(labels))
+	for i, label := range labels {
+		if i < len(values) {
+			tags[label] = values[i]
+		}
+	}
+	return tags
+}
+
+// RecorderCounter es un contador que se envía a un Recorder.
+type RecorderCounter struct {
+	recorder Recorder
+	name     string
+	labels   []string
+}
+
+// NewRecorderCounter crea un contador llamado name. labels son los nombres de las etiquetas de Inc, en el mismo orden.
+func NewRecorderCounter(recorder Recorder, name string, labels ...string) *RecorderCounter {
+	return &RecorderCounter{recorder: recorder, name: name, labels: labels}
+}
+
+func (c *RecorderCounter) Inc(labels ...string) {
+	c.recorder.Count(c.name, 1, recorderTags(c.labels, labels...))
+}
+
+// RecorderGauge es un gauge que se envía a un Recorder.
+type RecorderGauge struct {
+	recorder Recorder
+	name     string
+	labels   []string
+}
+
+// NewRecorderGauge crea un gauge llamado name. labels son los nombres de las etiquetas de Inc y Dec, en el mismo
+// orden.
+func NewRecorderGauge(recorder Recorder, name string, labels ...string) *RecorderGauge {
+	return &RecorderGauge{recorder: recorder, name: name, labels: labels}
+}
+
+func (g *RecorderGauge) Inc(labels ...string) {
+	g.recorder.AddGauge(g.name, 1, recorderTags(g.labels, labels...))
+}
+
+func (g *RecorderGauge) Dec(labels ...string) {
+	g.recorder.AddGauge(g.name, -1, recorderTags(g.labels, labels...))
+}
+
+// recorderCacheMetrics envía las métricas de la caché a un Recorder, con los mismos nombres que en Prometheus.
+type recorderCacheMetrics struct {
+	recorder Recorder
+}
+
+// NewRecorderCacheMetrics crea las métricas de la caché que se envían a recorder.
+func NewRecorderCacheMetrics(recorder Recorder) CacheMetrics {
+	return &recorderCacheMetrics{recorder: recorder}
+}
+
+func (c *recorderCacheMetrics) count(name, cacheType string) {
+	c.recorder.Count(name, 1, map[string]string{"cache_type": cacheType})
+}
+
+func (c *recorderCacheMetrics) IncHits(cacheType string) {
+	c.count("cache_hits_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) IncMisses(cacheType string) {
+	c.count("cache_misses_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) SetCacheSize(size float64) {
+	c.recorder.SetGauge("cache_size", size, nil)
+}
+
+func (c *recorderCacheMetrics) IncEvictions(cacheType string) {
+	c.count("cache_evictions_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) IncRequests(cacheType string) {
+	c.count("cache_requests_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) IncSetOperations(cacheType string) {
+	c.count("cache_set_operations_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) IncGetOperations(cacheType string) {
+	c.count("cache_get_operations_total", cacheType)
+}
+
+func (c *recorderCacheMetrics) IncLatencyGet(cacheType string, duration time.Duration) {
+	c.recorder.Observe("custom_cache_get_latency_seconds", duration.Seconds(), map[string]string{"cache_type": cacheType})
+}
+
+func (c *recorderCacheMetrics) IncLatencySet(cacheType string, duration time.Duration) {
+	c.recorder.Observe("custom_cache_set_latency_seconds", duration.Seconds(), map[string]string{"cache_type": cacheType})
+}
+
+// recorderAudioMetrics envía las métricas del audio a un Recorder, con los mismos nombres que en Prometheus.
+type recorderAudioMetrics struct {
+	recorder Recorder
+}
+
+// NewRecorderAudioMetrics crea las métricas del audio que se envían a recorder.
+func NewRecorderAudioMetrics(recorder Recorder) AudioMetrics {
+	return &recorderAudioMetrics{recorder: recorder}
+}
+
+func (a *recorderAudioMetrics) ObserveFrameDelay(guildID string, delay time.Duration) {
+	a.recorder.Observe("audio_frame_interval_seconds", delay.Seconds(), map[string]string{"guild_id": guildID})
+}
+
+func (a *recorderAudioMetrics) IncDroppedFrames(guildID string) {
+	a.recorder.Count("audio_frames_dropped_total", 1, map[string]string{"guild_id": guildID})
+}
+
+func (a *recorderAudioMetrics) IncLateFrames(guildID string) {
+	a.recorder.Count("audio_frames_late_total", 1, map[string]string{"guild_id": guildID})
+}
+
+// recorderFetcherMetrics envía las métricas del fetcher a un Recorder, con los mismos nombres que en Prometheus.
+type recorderFetcherMetrics struct {
+	*RecorderCounter
+	recorder Recorder
+}
+
+// NewRecorderFetcherMetrics crea las métricas del fetcher que se envían a recorder. Como en Prometheus, Inc cuenta
+// los errores con las etiquetas operation y cause.
+func NewRecorderFetcherMetrics(recorder Recorder) FetcherMetrics {
+	return &recorderFetcherMetrics{
+		RecorderCounter: NewRecorderCounter(recorder, "fetcher_errors_total", "operation", "cause"),
+		recorder:        recorder,
+	}
+}
+
+func (f *recorderFetcherMetrics) ObserveDuration(operation string, duration time.Duration) {
+	f.recorder.Observe("fetcher_operation_duration_seconds", duration.Seconds(), map[string]string{"operation": operation})
+}
+
+func (f *recorderFetcherMetrics) IncCacheResult(operation, result string) {
+	f.recorder.Count("fetcher_cache_results_total", 1, map[string]string{"operation": operation, "result": result})
+}
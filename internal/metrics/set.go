@@ -0,0 +1,46 @@
+package metrics
+
+// Set agrupa las métricas que usa el bot, creadas para un mismo backend. Registry solo está cuando el backend es
+// Prometheus, y es el que hay que exponer por HTTP.
+type Set struct {
+	CommandUsage CustomMetric
+	Cache        CacheMetrics
+	Audio        AudioMetrics
+	Fetcher      FetcherMetrics
+	Processes    GaugeMetric
+	Registry     *PrometheusRegistry
+}
+
+// NewPrometheusSet crea las métricas de Prometheus y las registra en un registro nuevo.
+func NewPrometheusSet() Set {
+	registry := NewPrometheusRegistry()
+	commandUsage := NewCommandUsageCounter()
+	cache := NewCacheMetrics()
+	audio := NewAudioMetrics()
+	fetcher := NewFetcherMetrics()
+	processes := NewExternalProcessGauge()
+	registry.Register(commandUsage)
+	registry.Register(cache)
+	registry.Register(audio)
+	registry.Register(fetcher)
+	registry.Register(processes)
+	return Set{
+		CommandUsage: commandUsage,
+		Cache:        cache,
+		Audio:        audio,
+		Fetcher:      fetcher,
+		Processes:    processes,
+		Registry:     registry,
+	}
+}
+
+// NewRecorderSet crea las métricas que se envían a recorder, con los mismos nombres y etiquetas que en Prometheus.
+func NewRecorderSet(recorder Recorder) Set {
+	return Set{
+		CommandUsage: NewRecorderCounter(recorder, "command_usage_total", "command"),
+		Cache:        NewRecorderCacheMetrics(recorder),
+		Audio:        NewRecorderAudioMetrics(recorder),
+		Fetcher:      NewRecorderFetcherMetrics(recorder),
+		Processes:    NewRecorderGauge(recorder, "external_processes_running", "process"),
+	}
+}
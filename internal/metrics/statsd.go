@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// statsdUnsafe son los caracteres que no pueden ir en el nombre de una métrica de StatsD.
+var statsdUnsafe = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
+
+// StatsDRecorder envía las métricas por UDP a un servidor StatsD. Con tags, las etiquetas se envían con el formato
+// de DogStatsD, que entienden el agente de Datadog y Telegraf; sin tags, StatsD no soporta etiquetas, así que sus
+// valores se agregan al nombre de la métrica (por ejemplo, command_usage_total.PlaySong). Si el servidor no
+// responde, las métricas se pierden sin demorar al bot.
+type StatsDRecorder struct {
+	conn   net.Conn
+	prefix string
+	tags   bool
+}
+
+// NewStatsDRecorder crea un StatsDRecorder que envía las métricas a address, con prefix antes de cada nombre.
+func NewStatsDRecorder(address, prefix string, tags bool) (*StatsDRecorder, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error al conectarse a StatsD en %s: %w", address, err)
+	}
+	return &StatsDRecorder{conn: conn, prefix: prefix, tags: tags}, nil
+}
+
+func (r *StatsDRecorder) Count(name string, value float64, tags map[string]string) {
+	r.send(name, formatStatsDValue(value), "c", tags)
+}
+
+func (r *StatsDRecorder) AddGauge(name string, delta float64, tags map[string]string) {
+	value := formatStatsDValue(delta)
+	if delta >= 0 {
+		// Sin signo, StatsD interpreta el valor como el nuevo valor del gauge.
+		value = "+" + value
+	}
+	r.send(name, value, "g", tags)
+}
+
+func (r *StatsDRecorder) SetGauge(name string, value float64, tags map[string]string) {
+	r.send(name, formatStatsDValue(value), "g", tags)
+}
+
+func (r *StatsDRecorder) Observe(name string, value float64, tags map[string]string) {
+	if r.tags {
+		r.send(name, formatStatsDValue(value), "h", tags)
+		return
+	}
+	// StatsD no tiene histogramas; las duraciones se envían como tiempos, en milisegundos.
+	r.send(name, formatStatsDValue(value*1000), "ms", tags)
+}
+
+// Close cierra la conexión con StatsD.
+func (r *StatsDRecorder) Close() error {
+	return r.conn.Close()
+}
+
+func (r *StatsDRecorder) send(name, value, kind string, tags map[string]string) {
+	_, _ = r.conn.Write([]byte(r.line(name, value, kind, tags)))
+}
+
+// line arma la línea de StatsD de la métrica, con las etiquetas ordenadas por nombre.
+func (r *StatsDRecorder) line(name, value, kind string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder := strings.Builder{}
+	builder.WriteString(r.prefix + name)
+	if !r.tags {
+		for _, key := range keys {
+			builder.WriteString("." + statsdUnsafe.ReplaceAllString(tags[key], "_"))
+		}
+	}
+	builder.WriteString(":" + value + "|" + kind)
+	if r.tags && len(keys) > 0 {
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, key+":"+strings.NewReplacer(",", "_", "|", "_", "#", "_").Replace(tags[key]))
+		}
+		builder.WriteString("|#" + strings.Join(pairs, ","))
+	}
+	return builder.String()
+}
+
+func formatStatsDValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
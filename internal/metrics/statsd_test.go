@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestStatsDRecorder_Line(t *testing.T) {
+	tags := map[string]string{"operation": "search", "cause": "http 429"}
+
+	statsd := &StatsDRecorder{prefix: "gomusicbot."}
+	assert.Equal(t, "gomusicbot.fetcher_errors_total.http_429.search:1|c", statsd.line("fetcher_errors_total", "1", "c", tags))
+
+	datadog := &StatsDRecorder{prefix: "gomusicbot.", tags: true}
+	assert.Equal(t, "gomusicbot.fetcher_errors_total:1|c|#cause:http 429,operation:search", datadog.line("fetcher_errors_total", "1", "c", tags))
+	assert.Equal(t, "gomusicbot.cache_size:3|g", datadog.line("cache_size", "3", "g", nil))
+}
+
+func TestStatsDRecorder_Send(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	recorder, err := NewStatsDRecorder(listener.LocalAddr().String(), "", false)
+	require.NoError(t, err)
+	defer recorder.Close()
+
+	read := func() string {
+		buf := make([]byte, 512)
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := listener.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	recorder.AddGauge("external_processes_running", 1, map[string]string{"process": "ffmpeg"})
+	assert.Equal(t, "external_processes_running.ffmpeg:+1|g", read())
+	recorder.AddGauge("external_processes_running", -1, map[string]string{"process": "ffmpeg"})
+	assert.Equal(t, "external_processes_running.ffmpeg:-1|g", read())
+	recorder.Observe("audio_frame_interval_seconds", 0.02, nil)
+	assert.Equal(t, "audio_frame_interval_seconds:20|ms", read())
+}
@@ -0,0 +1,92 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/netip"
+	"time"
+)
+
+// AddressRotator elige una dirección distinta de un bloque IPv6, por ejemplo un /64 asignado al host, para cada
+// solicitud saliente a YouTube. Repartir las solicitudes entre muchas direcciones evita que YouTube limite al bot
+// por dirección en despliegues grandes. Las direcciones del bloque tienen que estar ruteadas al host (por ejemplo
+// con ip -6 route add local <bloque> dev lo) y net.ipv6.ip_nonlocal_bind habilitado.
+type AddressRotator struct {
+	prefix netip.Prefix
+	random func() uint64
+}
+
+// NewAddressRotator crea un rotador para el bloque IPv6 en notación CIDR, como 2001:db8:1:2::/64.
+func NewAddressRotator(block string) (*AddressRotator, error) {
+	prefix, err := netip.ParsePrefix(block)
+	if err != nil {
+		return nil, fmt.Errorf("bloque IPv6 inválido %q: %w", block, err)
+	}
+	if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+		return nil, fmt.Errorf("el bloque %q no es IPv6", block)
+	}
+	if prefix.Bits() > 120 {
+		return nil, fmt.Errorf("el bloque %q es demasiado chico para rotar direcciones", block)
+	}
+	return &AddressRotator{prefix: prefix.Masked(), random: rand.Uint64}, nil
+}
+
+// Next devuelve una dirección al azar del bloque. Nunca devuelve la dirección de la red, que en IPv6 es la
+// anycast de los routers de la subred.
+func (r *AddressRotator) Next() netip.Addr {
+	network := r.prefix.Addr().As16()
+	bits := r.prefix.Bits()
+	for {
+		random := [16]byte{}
+		high, low := r.random(), r.random()
+		for i := 0; i < 8; i++ {
+			random[i] = byte(high >> (56 - 8*i))
+			random[8+i] = byte(low >> (56 - 8*i))
+		}
+		addr := network
+		for i := range addr {
+			// mask tiene en 1 los bits de host del byte i.
+			var mask byte
+			switch {
+			case bits >= 8*(i+1):
+				mask = 0
+			case bits <= 8*i:
+				mask = 0xff
+			default:
+				mask = 0xff >> (bits - 8*i)
+			}
+			addr[i] = network[i] | random[i]&mask
+		}
+		if next := netip.AddrFrom16(addr); next != r.prefix.Addr() {
+			return next
+		}
+	}
+}
+
+// DialContext abre la conexión desde una dirección nueva del bloque. Solo se conecta por IPv6.
+func (r *AddressRotator) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp6":
+		network = "tcp6"
+	default:
+		return nil, fmt.Errorf("red no soportada para rotar direcciones: %s", network)
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		LocalAddr: &net.TCPAddr{IP: r.Next().AsSlice()},
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Transport devuelve un http.RoundTripper que abre cada conexión desde una dirección nueva del bloque. No reutiliza
+// las conexiones, para que cada solicitud salga de una dirección distinta.
+func (r *AddressRotator) Transport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = r.DialContext
+	transport.DisableKeepAlives = true
+	return transport
+}
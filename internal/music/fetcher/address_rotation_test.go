@@ -0,0 +1,47 @@
+package fetcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/netip"
+	"testing"
+)
+
+func TestNewAddressRotator_Invalid(t *testing.T) {
+	for _, block := range []string{"no es un bloque", "10.0.0.0/8", "2001:db8::1/128"} {
+		_, err := NewAddressRotator(block)
+		assert.Error(t, err, block)
+	}
+}
+
+func TestAddressRotator_Next(t *testing.T) {
+	rotator, err := NewAddressRotator("2001:db8:1:2::/64")
+	require.NoError(t, err)
+	prefix := netip.MustParsePrefix("2001:db8:1:2::/64")
+
+	seen := make(map[netip.Addr]bool)
+	for i := 0; i < 100; i++ {
+		addr := rotator.Next()
+		assert.True(t, prefix.Contains(addr), addr.String())
+		seen[addr] = true
+	}
+	assert.Greater(t, len(seen), 90)
+}
+
+func TestAddressRotator_NextUnalignedPrefix(t *testing.T) {
+	rotator, err := NewAddressRotator("2001:db8::/60")
+	require.NoError(t, err)
+	rotator.random = func() uint64 { return ^uint64(0) }
+
+	// Todos los bits de host en 1: la dirección más alta del bloque.
+	assert.Equal(t, netip.MustParseAddr("2001:db8:0:f:ffff:ffff:ffff:ffff"), rotator.Next())
+}
+
+func TestAddressRotator_DialContextUnsupportedNetwork(t *testing.T) {
+	rotator, err := NewAddressRotator("2001:db8::/64")
+	require.NoError(t, err)
+
+	_, err = rotator.DialContext(context.Background(), "udp", "[2001:db8::1]:443")
+	assert.Error(t, err)
+}
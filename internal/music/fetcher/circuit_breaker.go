@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen se devuelve sin consultar a YouTube mientras el circuit breaker está abierto.
+var ErrCircuitOpen = errors.New("YouTube está teniendo problemas, probá de nuevo en un rato")
+
+// circuitState es el estado del circuit breaker.
+type circuitState int
+
+const (
+	circuitClosed   circuitState = iota // Las solicitudes pasan normalmente.
+	circuitOpen                         // Las solicitudes fallan rápido sin llegar a YouTube.
+	circuitHalfOpen                     // Se deja pasar una solicitud de prueba para ver si YouTube se recuperó.
+)
+
+// CircuitBreaker corta las solicitudes a YouTube después de varias fallas seguidas del lado de YouTube,
+// para no acumular descargas y búsquedas que van a fallar igual. Pasado openTimeout deja pasar una solicitud
+// de prueba: si funciona vuelve a cerrarse y si falla sigue abierto.
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	onOpen           func()
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker crea un circuit breaker que se abre tras failureThreshold fallas seguidas.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// WithOnOpen establece una función que se llama cada vez que el circuit breaker se abre.
+func (cb *CircuitBreaker) WithOnOpen(fn func()) *CircuitBreaker {
+	cb.onOpen = fn
+	return cb
+}
+
+// Allow indica si la solicitud puede llegar a YouTube. Devuelve ErrCircuitOpen si el circuit breaker está abierto.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		cb.probing = true
+		return nil
+	case circuitHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record registra el resultado de una solicitud permitida por Allow. Solo cuentan como fallas las atribuibles
+// a YouTube (límites de tasa, timeouts y errores desconocidos); las causadas por el pedido cuentan como respuestas
+// de YouTube y las cancelaciones no cuentan.
+func (cb *CircuitBreaker) Record(err error) {
+	cb.mu.Lock()
+	if err != nil && classifyError(err) == ErrorCauseCanceled {
+		cb.probing = false
+		cb.mu.Unlock()
+		return
+	}
+
+	opened := false
+	if err == nil || !isUpstreamFailure(err) {
+		cb.state = circuitClosed
+		cb.failures = 0
+		cb.probing = false
+	} else {
+		cb.failures++
+		if cb.state == circuitHalfOpen || cb.failures >= cb.failureThreshold {
+			opened = cb.state != circuitOpen
+			cb.state = circuitOpen
+			cb.openedAt = cb.now()
+			cb.probing = false
+		}
+	}
+	cb.mu.Unlock()
+
+	if opened && cb.onOpen != nil {
+		cb.onOpen()
+	}
+}
+
+// isUpstreamFailure indica si el error se debe a un problema de YouTube.
+func isUpstreamFailure(err error) bool {
+	switch classifyError(err) {
+	case ErrorCauseRateLimited, ErrorCauseTimeout, ErrorCauseOther:
+		return true
+	default:
+		return false
+	}
+}
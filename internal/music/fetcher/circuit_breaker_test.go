@@ -0,0 +1,56 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	opened := 0
+	breaker := NewCircuitBreaker(2, time.Minute).WithOnOpen(func() { opened++ })
+	breaker.now = func() time.Time { return now }
+	upstreamErr := errors.New("HTTP Error 503")
+
+	t.Run("los errores del pedido no abren el circuito", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, breaker.Allow())
+			breaker.Record(errors.New("Video unavailable"))
+			assert.NoError(t, breaker.Allow())
+			breaker.Record(context.Canceled)
+		}
+		assert.Equal(t, 0, opened)
+	})
+
+	t.Run("se abre tras fallas seguidas de YouTube", func(t *testing.T) {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(upstreamErr)
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(upstreamErr)
+
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+		assert.Equal(t, 1, opened)
+	})
+
+	t.Run("deja pasar una sola prueba al vencer el timeout", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+		breaker.Record(upstreamErr)
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "si la prueba falla vuelve a abrirse")
+		assert.Equal(t, 2, opened)
+	})
+
+	t.Run("se cierra si la prueba funciona", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(nil)
+
+		assert.NoError(t, breaker.Allow())
+		assert.NoError(t, breaker.Allow())
+	})
+}
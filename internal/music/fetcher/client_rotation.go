@@ -0,0 +1,200 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// POToken es un token de prueba de origen (PO token) de YouTube, junto con el visitor data para el que se generó.
+// YouTube los pide a los clientes web para no responder 403 o "Sign in to confirm you're not a bot".
+type POToken struct {
+	Token       string `json:"poToken"`
+	VisitorData string `json:"visitorData"`
+}
+
+// TokenProvider provee los PO tokens que se pasan a yt-dlp.
+type TokenProvider interface {
+	Token(ctx context.Context) (POToken, error)
+	// Invalidate descarta el token actual porque YouTube lo rechazó.
+	Invalidate()
+}
+
+// StaticTokenProvider provee siempre el mismo PO token, por ejemplo uno configurado a mano.
+type StaticTokenProvider struct {
+	token POToken
+}
+
+// NewStaticTokenProvider crea un provider con un token fijo.
+func NewStaticTokenProvider(token POToken) *StaticTokenProvider {
+	return &StaticTokenProvider{token: token}
+}
+
+func (p *StaticTokenProvider) Token(context.Context) (POToken, error) {
+	return p.token, nil
+}
+
+func (p *StaticTokenProvider) Invalidate() {}
+
+// CommandTokenProvider genera los PO tokens con un comando externo, como youtube-po-token-generator, que imprime un
+// JSON con poToken y visitorData. Guarda el token hasta que vence ttl o YouTube lo rechaza.
+type CommandTokenProvider struct {
+	executor CommandExecutor
+	command  string
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	token   *POToken
+	expires time.Time
+}
+
+// NewCommandTokenProvider crea un provider que ejecuta command con sh para generar cada token.
+func NewCommandTokenProvider(executor CommandExecutor, command string, ttl time.Duration) *CommandTokenProvider {
+	return &CommandTokenProvider{executor: executor, command: command, ttl: ttl, now: time.Now}
+}
+
+func (p *CommandTokenProvider) Token(ctx context.Context) (POToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != nil && p.now().Before(p.expires) {
+		return *p.token, nil
+	}
+
+	output, err := p.executor.ExecuteCommand(ctx, "sh", "-c", p.command).Output()
+	if err != nil {
+		return POToken{}, fmt.Errorf("error al generar el PO token: %w", err)
+	}
+	var token POToken
+	if err := json.Unmarshal(output, &token); err != nil {
+		return POToken{}, fmt.Errorf("error al leer el PO token generado: %w", err)
+	}
+	if token.Token == "" {
+		return POToken{}, fmt.Errorf("el comando no generó un PO token")
+	}
+	p.token = &token
+	p.expires = p.now().Add(p.ttl)
+	return token, nil
+}
+
+func (p *CommandTokenProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token = nil
+}
+
+// ClientRotator elige el cliente de YouTube (web, android, ios, ...) con el que yt-dlp extrae el audio. Cuando
+// YouTube bloquea un cliente, lo deja de usar por cooldown y pasa al siguiente; el último que funcionó es el primero
+// que se prueba en la próxima descarga.
+type ClientRotator struct {
+	clients  []string
+	cooldown time.Duration
+	tokens   TokenProvider // tokens es opcional; sin él no se pasan PO tokens a los clientes web.
+	now      func() time.Time
+
+	mu           sync.Mutex
+	current      int
+	blockedUntil map[string]time.Time
+}
+
+// NewClientRotator crea un rotador que prueba los clientes en el orden indicado.
+func NewClientRotator(clients []string, cooldown time.Duration) *ClientRotator {
+	return &ClientRotator{
+		clients:      clients,
+		cooldown:     cooldown,
+		now:          time.Now,
+		blockedUntil: make(map[string]time.Time),
+	}
+}
+
+// WithTokenProvider establece el provider de los PO tokens de los clientes web.
+func (r *ClientRotator) WithTokenProvider(p TokenProvider) *ClientRotator {
+	r.tokens = p
+	return r
+}
+
+// Attempts devuelve los clientes a probar, en orden: el último que funcionó y después los demás que no están
+// bloqueados. Si están todos bloqueados se prueban igual, para no dejar de reproducir.
+func (r *ClientRotator) Attempts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	var available, blocked []string
+	for i := range r.clients {
+		client := r.clients[(r.current+i)%len(r.clients)]
+		if now.Before(r.blockedUntil[client]) {
+			blocked = append(blocked, client)
+		} else {
+			available = append(available, client)
+		}
+	}
+	if len(available) == 0 {
+		return blocked
+	}
+	return available
+}
+
+// Succeeded registra que el cliente funcionó, para probarlo primero la próxima vez.
+func (r *ClientRotator) Succeeded(client string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.clients {
+		if c == client {
+			r.current = i
+		}
+	}
+	delete(r.blockedUntil, client)
+}
+
+// Blocked registra que YouTube bloqueó el cliente. Si usaba un PO token, el token se descarta.
+func (r *ClientRotator) Blocked(client string) {
+	r.mu.Lock()
+	r.blockedUntil[client] = r.now().Add(r.cooldown)
+	r.mu.Unlock()
+	if r.tokens != nil && usesPOToken(client) {
+		r.tokens.Invalidate()
+	}
+}
+
+// ExtractorArgs devuelve el valor de --extractor-args de yt-dlp para el cliente. Si no se pudo obtener el PO token,
+// devuelve los argumentos sin el token junto con el error.
+func (r *ClientRotator) ExtractorArgs(ctx context.Context, client string) (string, error) {
+	args := []string{"player_client=" + client}
+	if r.tokens == nil || !usesPOToken(client) {
+		return "youtube:" + strings.Join(args, ";"), nil
+	}
+	token, err := r.tokens.Token(ctx)
+	if err == nil {
+		args = append(args, fmt.Sprintf("po_token=%s.gvs+%s", client, token.Token))
+		if token.VisitorData != "" {
+			args = append(args, "visitor_data="+token.VisitorData)
+		}
+	}
+	return "youtube:" + strings.Join(args, ";"), err
+}
+
+// usesPOToken indica si el cliente necesita PO token. YouTube solo se los pide a los clientes web.
+func usesPOToken(client string) bool {
+	return strings.HasPrefix(client, "web") || client == "mweb"
+}
+
+// countingWriter cuenta los bytes escritos, para saber si una descarga fallida ya envió audio.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// shellQuote escapa el valor para pasarlo como un solo argumento en un comando de sh.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,103 @@
+package fetcher
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientRotator_Attempts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rotator := NewClientRotator([]string{"web", "android", "ios"}, 10*time.Minute)
+	rotator.now = func() time.Time { return now }
+
+	assert.Equal(t, []string{"web", "android", "ios"}, rotator.Attempts())
+
+	rotator.Blocked("web")
+	rotator.Succeeded("android")
+	assert.Equal(t, []string{"android", "ios"}, rotator.Attempts())
+
+	// Si están todos bloqueados se prueban igual, empezando por el último que funcionó.
+	rotator.Blocked("android")
+	rotator.Blocked("ios")
+	assert.Equal(t, []string{"android", "ios", "web"}, rotator.Attempts())
+
+	now = now.Add(11 * time.Minute)
+	assert.Equal(t, []string{"android", "ios", "web"}, rotator.Attempts())
+}
+
+func TestClientRotator_ExtractorArgs(t *testing.T) {
+	rotator := NewClientRotator([]string{"web", "android"}, time.Minute).WithTokenProvider(NewStaticTokenProvider(POToken{Token: "tok", VisitorData: "visitor"}))
+
+	args, err := rotator.ExtractorArgs(context.Background(), "web")
+	assert.NoError(t, err)
+	assert.Equal(t, "youtube:player_client=web;po_token=web.gvs+tok;visitor_data=visitor", args)
+
+	args, err = rotator.ExtractorArgs(context.Background(), "android")
+	assert.NoError(t, err)
+	assert.Equal(t, "youtube:player_client=android", args)
+}
+
+func TestCommandTokenProvider(t *testing.T) {
+	ctx := context.Background()
+	executor := new(MockCommandExecutor)
+	executor.On("ExecuteCommand", ctx, "sh", []string{"-c", "generar-token"}).
+		Return(exec.Command("echo", `{"visitorData":"v1","poToken":"t1"}`)).Once()
+	executor.On("ExecuteCommand", ctx, "sh", []string{"-c", "generar-token"}).
+		Return(exec.Command("echo", `{"visitorData":"v2","poToken":"t2"}`)).Once()
+	provider := NewCommandTokenProvider(executor, "generar-token", time.Hour)
+
+	token, err := provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, POToken{Token: "t1", VisitorData: "v1"}, token)
+
+	// Mientras no venza se usa el mismo token; al invalidarlo se genera otro.
+	token, err = provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", token.Token)
+	provider.Invalidate()
+	token, err = provider.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "t2", token.Token)
+	executor.AssertExpectations(t)
+}
+
+func TestYoutubeFetcher_GetDCAData_ClientFallback(t *testing.T) {
+	ctx := context.Background()
+	mockLogger := new(MockLogger)
+	mockAudioCache := new(MockAudioCaching)
+	mockCommandExecutor := new(MockCommandExecutor)
+	rotator := NewClientRotator([]string{"web", "android"}, time.Minute)
+	fetcher := NewYoutubeFetcher(mockLogger, new(MockCacheManager), new(MockYouTubeService), mockAudioCache, mockCommandExecutor).WithClientRotation(rotator)
+	song := &voice.Song{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}
+
+	withClient := func(client string) interface{} {
+		return mock.MatchedBy(func(args []string) bool {
+			return strings.Contains(args[1], "--extractor-args 'youtube:player_client="+client+"'")
+		})
+	}
+	mockCommandExecutor.On("ExecuteCommand", ctx, "sh", withClient("web")).
+		Return(exec.Command("sh", "-c", `echo "ERROR: Sign in to confirm you're not a bot" >&2; exit 1`))
+	mockCommandExecutor.On("ExecuteCommand", ctx, "sh", withClient("android")).
+		Return(exec.Command("echo", "-n", "audio"))
+	mockAudioCache.On("Get", song.URL).Return(nil, false)
+	mockAudioCache.On("Set", song.URL, []byte("audio"))
+	mockLogger.On("Warn", "YouTube bloqueó el cliente de yt-dlp, se prueba con otro", mock.Anything).Once()
+
+	reader, err := fetcher.GetDCAData(ctx, song)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("audio"), data)
+	assert.Equal(t, []string{"android"}, rotator.Attempts())
+	mockCommandExecutor.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
@@ -0,0 +1,81 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/api/googleapi"
+	"net/http"
+	"strings"
+)
+
+// Causas de error usadas como etiqueta en las métricas del fetcher.
+const (
+	ErrorCauseAgeRestricted = "age_restricted"
+	ErrorCauseRateLimited   = "rate_limited"
+	ErrorCauseBlocked       = "blocked"
+	ErrorCauseNotFound      = "not_found"
+	ErrorCauseTimeout       = "timeout"
+	ErrorCauseCanceled      = "canceled"
+	ErrorCauseOther         = "other"
+)
+
+// classifyError determina la causa de un error del fetcher a partir del error de la API de YouTube
+// o de la salida de error de yt-dlp.
+func classifyError(err error) string {
+	if errors.Is(err, context.Canceled) {
+		return ErrorCauseCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCauseTimeout
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusTooManyRequests:
+			return ErrorCauseRateLimited
+		case http.StatusNotFound:
+			return ErrorCauseNotFound
+		case http.StatusForbidden:
+			for _, item := range apiErr.Errors {
+				if strings.Contains(item.Reason, "quotaExceeded") || strings.Contains(item.Reason, "rateLimitExceeded") {
+					return ErrorCauseRateLimited
+				}
+			}
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "confirm your age") || strings.Contains(msg, "age-restricted") || strings.Contains(msg, "age restricted"):
+		return ErrorCauseAgeRestricted
+	case strings.Contains(msg, "sign in to confirm") || strings.Contains(msg, "http error 403"):
+		// YouTube detectó al bot y pide iniciar sesión, o rechazó el cliente o el PO token.
+		return ErrorCauseBlocked
+	case strings.Contains(msg, "429") || strings.Contains(msg, "too many requests"):
+		return ErrorCauseRateLimited
+	case strings.Contains(msg, "video unavailable") || strings.Contains(msg, "no encontrado") || strings.Contains(msg, "no se encontró"):
+		return ErrorCauseNotFound
+	default:
+		return ErrorCauseOther
+	}
+}
+
+// tailBuffer es un io.Writer que conserva solo los últimos bytes escritos. Se usa para adjuntar
+// el final de la salida de error de yt-dlp a los errores sin guardar todo el progreso de la descarga.
+type tailBuffer struct {
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return strings.TrimSpace(string(b.data))
+}
@@ -0,0 +1,45 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"contexto cancelado", fmt.Errorf("falló: %w", context.Canceled), ErrorCauseCanceled},
+		{"timeout", context.DeadlineExceeded, ErrorCauseTimeout},
+		{"API 429", &googleapi.Error{Code: 429}, ErrorCauseRateLimited},
+		{"API cuota agotada", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, ErrorCauseRateLimited},
+		{"API 404", fmt.Errorf("error: %w", &googleapi.Error{Code: 404}), ErrorCauseNotFound},
+		{"yt-dlp restricción de edad", errors.New("exit status 1: ERROR: Sign in to confirm your age"), ErrorCauseAgeRestricted},
+		{"yt-dlp bloqueado", errors.New("exit status 1: ERROR: [youtube] abc: Sign in to confirm you're not a bot"), ErrorCauseBlocked},
+		{"yt-dlp 403", errors.New("exit status 1: ERROR: unable to download video data: HTTP Error 403: Forbidden"), ErrorCauseBlocked},
+		{"yt-dlp 429", errors.New("exit status 1: HTTP Error 429: Too Many Requests"), ErrorCauseRateLimited},
+		{"yt-dlp no disponible", errors.New("exit status 1: ERROR: Video unavailable"), ErrorCauseNotFound},
+		{"video no encontrado", errors.New("video no encontrado con el ID: abc"), ErrorCauseNotFound},
+		{"otro", errors.New("exit status 1"), ErrorCauseOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyError(tt.err))
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	buffer := &tailBuffer{limit: 5}
+	_, _ = buffer.Write([]byte("abc"))
+	_, _ = buffer.Write([]byte("defgh"))
+
+	assert.Equal(t, "defgh", buffer.String())
+}
@@ -0,0 +1,214 @@
+package fetcher
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/services/providers"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/youtube/v3"
+	"sync"
+)
+
+// bulkLookupKey es la clave del contexto que marca una búsqueda como parte de una expansión masiva.
+type bulkLookupKey struct{}
+
+// WithBulkLookup marca las búsquedas hechas con el contexto como parte de una expansión masiva (por ejemplo,
+// cargar todas las canciones de una playlist) del servidor indicado. Esas búsquedas ceden el paso a las de una
+// sola canción y se reparten por turnos entre servidores, para que uno no acapare el pool.
+func WithBulkLookup(ctx context.Context, guildID string) context.Context {
+	return context.WithValue(ctx, bulkLookupKey{}, guildID)
+}
+
+// bulkLookupGuild devuelve el servidor de la expansión masiva, si el contexto está marcado como tal.
+func bulkLookupGuild(ctx context.Context) (string, bool) {
+	guildID, ok := ctx.Value(bulkLookupKey{}).(string)
+	return guildID, ok
+}
+
+type lookupJob struct {
+	ctx  context.Context
+	fn   func(ctx context.Context) error
+	done chan error
+}
+
+// LookupPool centraliza las solicitudes salientes a YouTube en un pool de workers con concurrencia y tasa globales.
+// Las búsquedas de una sola canción tienen prioridad sobre las expansiones masivas.
+type LookupPool struct {
+	workers int
+	limiter *rate.Limiter
+	wake    chan struct{}
+
+	mu          sync.Mutex
+	interactive []*lookupJob
+	bulk        map[string][]*lookupJob
+	bulkOrder   []string // bulkOrder es el turno de los servidores con expansiones pendientes.
+}
+
+// NewLookupPool crea un pool con la cantidad de workers y la tasa (solicitudes por segundo y ráfaga) indicadas.
+// Las solicitudes no se ejecutan hasta llamar a Run.
+func NewLookupPool(workers int, ratePerSecond float64, burst int) *LookupPool {
+	if workers < 1 {
+		workers = 1
+	}
+	limit := rate.Limit(ratePerSecond)
+	if ratePerSecond <= 0 {
+		limit = rate.Inf
+	}
+	return &LookupPool{
+		workers: workers,
+		limiter: rate.NewLimiter(limit, burst),
+		wake:    make(chan struct{}, workers),
+		bulk:    make(map[string][]*lookupJob),
+	}
+}
+
+// Run ejecuta los workers hasta que se cancele el contexto.
+func (p *LookupPool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// Do encola la solicitud y espera a que un worker la ejecute. La prioridad se toma del contexto (ver WithBulkLookup).
+func (p *LookupPool) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	job := &lookupJob{ctx: ctx, fn: fn, done: make(chan error, 1)}
+	p.enqueue(job)
+
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *LookupPool) work(ctx context.Context) {
+	for {
+		job := p.dequeue()
+		if job == nil {
+			select {
+			case <-p.wake:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := job.ctx.Err(); err != nil {
+			job.done <- err
+			continue
+		}
+		if err := p.limiter.Wait(job.ctx); err != nil {
+			job.done <- err
+			continue
+		}
+		job.done <- job.fn(job.ctx)
+	}
+}
+
+func (p *LookupPool) enqueue(job *lookupJob) {
+	p.mu.Lock()
+	if guildID, ok := bulkLookupGuild(job.ctx); ok {
+		if len(p.bulk[guildID]) == 0 {
+			p.bulkOrder = append(p.bulkOrder, guildID)
+		}
+		p.bulk[guildID] = append(p.bulk[guildID], job)
+	} else {
+		p.interactive = append(p.interactive, job)
+	}
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue devuelve la próxima solicitud: primero las de una sola canción y después las expansiones masivas,
+// una por servidor a la vez.
+func (p *LookupPool) dequeue() *lookupJob {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.interactive) > 0 {
+		job := p.interactive[0]
+		p.interactive = p.interactive[1:]
+		return job
+	}
+	if len(p.bulkOrder) == 0 {
+		return nil
+	}
+
+	guildID := p.bulkOrder[0]
+	p.bulkOrder = p.bulkOrder[1:]
+	jobs := p.bulk[guildID]
+	job := jobs[0]
+	if len(jobs) > 1 {
+		p.bulk[guildID] = jobs[1:]
+		p.bulkOrder = append(p.bulkOrder, guildID)
+	} else {
+		delete(p.bulk, guildID)
+	}
+	return job
+}
+
+// RateLimitedYouTubeService envuelve un providers.YouTubeService para que todas sus solicitudes pasen por el LookupPool.
+type RateLimitedYouTubeService struct {
+	service providers.YouTubeService
+	pool    *LookupPool
+}
+
+// NewRateLimitedYouTubeService crea un YouTubeService que ejecuta las solicitudes a través del pool.
+func NewRateLimitedYouTubeService(service providers.YouTubeService, pool *LookupPool) *RateLimitedYouTubeService {
+	return &RateLimitedYouTubeService{service: service, pool: pool}
+}
+
+func (s *RateLimitedYouTubeService) SearchVideoID(ctx context.Context, searchTerm string) (string, error) {
+	var videoID string
+	err := s.pool.Do(ctx, func(ctx context.Context) error {
+		var err error
+		videoID, err = s.service.SearchVideoID(ctx, searchTerm)
+		return err
+	})
+	return videoID, err
+}
+
+func (s *RateLimitedYouTubeService) SearchVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	var videoIDs []string
+	err := s.pool.Do(ctx, func(ctx context.Context) error {
+		var err error
+		videoIDs, err = s.service.SearchVideoIDs(ctx, searchTerm, limit)
+		return err
+	})
+	return videoIDs, err
+}
+
+func (s *RateLimitedYouTubeService) GetVideoDetails(ctx context.Context, videoID string) (*youtube.Video, error) {
+	var video *youtube.Video
+	err := s.pool.Do(ctx, func(ctx context.Context) error {
+		var err error
+		video, err = s.service.GetVideoDetails(ctx, videoID)
+		return err
+	})
+	return video, err
+}
+
+// GetPlaylistItems lista los elementos de la playlist a través del pool, si el servicio envuelto lo permite.
+func (s *RateLimitedYouTubeService) GetPlaylistItems(ctx context.Context, playlistID string, limit int) ([]*youtube.PlaylistItem, error) {
+	service, ok := s.service.(providers.PlaylistService)
+	if !ok {
+		return nil, ErrPlaylistsNotSupported
+	}
+	var items []*youtube.PlaylistItem
+	err := s.pool.Do(ctx, func(ctx context.Context) error {
+		var err error
+		items, err = service.GetPlaylistItems(ctx, playlistID, limit)
+		return err
+	})
+	return items, err
+}
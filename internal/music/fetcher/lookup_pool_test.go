@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/youtube/v3"
+	"testing"
+	"time"
+)
+
+func TestLookupPool_Priorities(t *testing.T) {
+	pool := NewLookupPool(1, 0, 1)
+	ctx := context.Background()
+	newJob := func(ctx context.Context) *lookupJob {
+		return &lookupJob{ctx: ctx, done: make(chan error, 1)}
+	}
+
+	bulkA1 := newJob(WithBulkLookup(ctx, "a"))
+	bulkA2 := newJob(WithBulkLookup(ctx, "a"))
+	bulkA3 := newJob(WithBulkLookup(ctx, "a"))
+	bulkB1 := newJob(WithBulkLookup(ctx, "b"))
+	single := newJob(ctx)
+	for _, job := range []*lookupJob{bulkA1, bulkA2, bulkA3, bulkB1, single} {
+		pool.enqueue(job)
+	}
+
+	// La búsqueda de una canción pasa primero y las expansiones se turnan entre servidores.
+	for _, expected := range []*lookupJob{single, bulkA1, bulkB1, bulkA2, bulkA3} {
+		assert.Same(t, expected, pool.dequeue())
+	}
+	assert.Nil(t, pool.dequeue())
+}
+
+func TestRateLimitedYouTubeService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := NewLookupPool(2, 1000, 10)
+	go pool.Run(ctx)
+
+	mockService := new(MockYouTubeService)
+	mockService.On("SearchVideoID", mock.Anything, "tema").Return("id-1", nil)
+	mockService.On("GetVideoDetails", mock.Anything, "id-1").Return(&youtube.Video{Id: "id-1"}, nil)
+	service := NewRateLimitedYouTubeService(mockService, pool)
+
+	videoID, err := service.SearchVideoID(ctx, "tema")
+	assert.NoError(t, err)
+	assert.Equal(t, "id-1", videoID)
+
+	video, err := service.GetVideoDetails(WithBulkLookup(ctx, "guild"), "id-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "id-1", video.Id)
+}
+
+func TestLookupPool_CanceledContext(t *testing.T) {
+	pool := NewLookupPool(1, 0, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Sin workers corriendo la solicitud nunca se ejecuta, así que Do vuelve al vencer el contexto.
+	err := pool.Do(ctx, func(context.Context) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -3,10 +3,13 @@ package fetcher
 import (
 	"context"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 	"google.golang.org/api/youtube/v3"
 	"os/exec"
+	"time"
 )
 
 type MockLogger struct {
@@ -21,6 +24,10 @@ func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) With(fields ...zap.Field) {
 	m.Called(fields)
 }
@@ -80,11 +87,23 @@ func (m *MockYouTubeService) SearchVideoID(ctx context.Context, searchTerm strin
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockYouTubeService) SearchVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	args := m.Called(ctx, searchTerm, limit)
+	videoIDs, _ := args.Get(0).([]string)
+	return videoIDs, args.Error(1)
+}
+
 func (m *MockYouTubeService) GetVideoDetails(ctx context.Context, videoID string) (*youtube.Video, error) {
 	args := m.Called(ctx, videoID)
 	return args.Get(0).(*youtube.Video), args.Error(1)
 }
 
+func (m *MockYouTubeService) GetPlaylistItems(ctx context.Context, playlistID string, limit int) ([]*youtube.PlaylistItem, error) {
+	args := m.Called(ctx, playlistID, limit)
+	items, _ := args.Get(0).([]*youtube.PlaylistItem)
+	return items, args.Error(1)
+}
+
 // MockCommandExecutor es un mock de CommandExecutor usando testify
 type MockCommandExecutor struct {
 	mock.Mock
@@ -94,3 +113,83 @@ func (m *MockCommandExecutor) ExecuteCommand(ctx context.Context, name string, a
 	argsForCall := m.Called(ctx, name, args)
 	return argsForCall.Get(0).(*exec.Cmd)
 }
+
+type MockFetcherMetrics struct {
+	mock.Mock
+}
+
+func (m *MockFetcherMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.Called(ch)
+}
+
+func (m *MockFetcherMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.Called(ch)
+}
+
+func (m *MockFetcherMetrics) Inc(labels ...string) {
+	m.Called(labels)
+}
+
+func (m *MockFetcherMetrics) ObserveDuration(operation string, duration time.Duration) {
+	m.Called(operation, duration)
+}
+
+func (m *MockFetcherMetrics) IncCacheResult(operation, result string) {
+	m.Called(operation, result)
+}
+
+type MockGaugeMetric struct {
+	mock.Mock
+}
+
+func (m *MockGaugeMetric) Describe(ch chan<- *prometheus.Desc) {
+	m.Called(ch)
+}
+
+func (m *MockGaugeMetric) Collect(ch chan<- prometheus.Metric) {
+	m.Called(ch)
+}
+
+func (m *MockGaugeMetric) Inc(labels ...string) {
+	m.Called(labels)
+}
+
+func (m *MockGaugeMetric) Dec(labels ...string) {
+	m.Called(labels)
+}
+
+type MockSongLooker struct {
+	mock.Mock
+}
+
+func (m *MockSongLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	args := m.Called(ctx, input)
+	songs, _ := args.Get(0).([]*voice.Song)
+	return songs, args.Error(1)
+}
+
+func (m *MockSongLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	args := m.Called(ctx, searchTerm)
+	return args.String(0), args.Error(1)
+}
+
+type MockSpotifyCatalog struct {
+	mock.Mock
+}
+
+func (m *MockSpotifyCatalog) Track(ctx context.Context, id string) (spotify.Track, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(spotify.Track), args.Error(1)
+}
+
+func (m *MockSpotifyCatalog) AlbumTracks(ctx context.Context, id string) ([]spotify.Track, error) {
+	args := m.Called(ctx, id)
+	tracks, _ := args.Get(0).([]spotify.Track)
+	return tracks, args.Error(1)
+}
+
+func (m *MockSpotifyCatalog) PlaylistTracks(ctx context.Context, id string) ([]spotify.Track, error) {
+	args := m.Called(ctx, id)
+	tracks, _ := args.Get(0).([]spotify.Track)
+	return tracks, args.Error(1)
+}
@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/services/providers"
+	"go.uber.org/zap"
+	"google.golang.org/api/youtube/v3"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPlaylistsNotSupported indica que el servicio de YouTube configurado no lista los elementos de una playlist.
+var ErrPlaylistsNotSupported = errors.New("el servicio de YouTube no permite cargar playlists")
+
+const (
+	operationLookupPlaylist = "lookup_playlist"
+
+	// defaultPlaylistMaxSize es la cantidad máxima de canciones que se cargan de una playlist.
+	defaultPlaylistMaxSize = 500
+	// defaultPlaylistEager es la cantidad de canciones del principio de la playlist cuya metadata se busca al
+	// cargarla; las demás se completan cuando se acercan al principio de la lista de reproducción.
+	defaultPlaylistEager = 20
+	// defaultPlaylistWorkers es la cantidad de búsquedas de metadata de una playlist que corren a la vez.
+	defaultPlaylistWorkers = 8
+)
+
+// WithPlaylistResolution configura la carga de playlists: se cargan hasta maxSize canciones, de las que se busca la
+// metadata completa de las primeras eager con workers búsquedas a la vez. Las demás quedan sin resolver y el
+// reproductor completa su metadata antes de reproducirlas.
+func (s *YoutubeFetcher) WithPlaylistResolution(maxSize, eager, workers int) *YoutubeFetcher {
+	s.playlistMaxSize = maxSize
+	s.playlistEager = eager
+	s.playlistWorkers = workers
+	return s
+}
+
+// ParsePlaylistID devuelve el ID de la playlist de un link de YouTube (https://www.youtube.com/playlist?list=...).
+func ParsePlaylistID(input string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(input))
+	if err != nil || parsed.Path != "/playlist" {
+		return "", false
+	}
+	switch strings.TrimPrefix(parsed.Hostname(), "www.") {
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+	default:
+		return "", false
+	}
+	playlistID := parsed.Query().Get("list")
+	return playlistID, playlistID != ""
+}
+
+// lookupPlaylist carga las canciones de la playlist. La metadata de las primeras se busca en paralelo y el resto
+// queda sin resolver, para responder rápido aunque la playlist tenga cientos de videos.
+func (s *YoutubeFetcher) lookupPlaylist(ctx context.Context, playlistID string) ([]*voice.Song, error) {
+	logger := logging.WithFields(logging.FromContext(ctx, s.Logger), zap.String("playlistID", playlistID))
+	service, ok := s.YoutubeService.(providers.PlaylistService)
+	if !ok {
+		return nil, ErrPlaylistsNotSupported
+	}
+	// Las búsquedas de la playlist ceden el paso a las de una sola canción y se turnan con las de otras playlists.
+	if _, ok := bulkLookupGuild(ctx); !ok {
+		ctx = WithBulkLookup(ctx, playlistID)
+	}
+
+	if err := s.allow(); err != nil {
+		logger.Warn("Carga de playlist rechazada por el circuit breaker")
+		return nil, err
+	}
+	start := time.Now()
+	items, err := service.GetPlaylistItems(ctx, playlistID, s.playlistMaxSize)
+	s.record(err)
+	s.observe(operationLookupPlaylist, start, err)
+	if err != nil {
+		logger.Error("Error al obtener los elementos de la playlist", zap.Error(err), zap.String("cause", classifyError(err)))
+		return nil, fmt.Errorf("error al obtener los elementos de la playlist: %w", err)
+	}
+
+	songs := make([]*voice.Song, 0, len(items))
+	for _, item := range items {
+		if song := songFromPlaylistItem(item); song != nil {
+			songs = append(songs, song)
+		}
+	}
+	s.resolveSongs(ctx, logger, songs[:min(len(songs), s.playlistEager)])
+
+	logger.Info("Playlist cargada", zap.Int("canciones", len(songs)), zap.Duration("duración", time.Since(start)))
+	return songs, nil
+}
+
+// resolveSongs reemplaza la metadata de las canciones sin resolver por la completa, con hasta playlistWorkers
+// búsquedas a la vez. Las que fallan quedan sin resolver, para volver a intentarlo antes de reproducirlas.
+func (s *YoutubeFetcher) resolveSongs(ctx context.Context, logger logging.Logger, songs []*voice.Song) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(max(s.playlistWorkers, 1), len(songs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				videoID, _ := strings.CutPrefix(songs[i].CanonicalID(), "youtube:")
+				found, err := s.LookupSongs(ctx, videoID)
+				if err != nil || len(found) != 1 {
+					logger.Warn("No se pudo obtener la metadata de una canción de la playlist", zap.String("URL", songs[i].URL), zap.Error(err))
+					continue
+				}
+				// Se copia para no compartir con la caché la canción a la que después se le asigna quién la pidió.
+				resolved := *found[0]
+				songs[i] = &resolved
+			}
+		}()
+	}
+	for i := range songs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// songFromPlaylistItem arma una canción sin resolver con lo que informa la playlist, o nil si el video ya no está
+// disponible. Los videos borrados o privados siguen en la playlist, pero sin canal.
+func songFromPlaylistItem(item *youtube.PlaylistItem) *voice.Song {
+	if item.Snippet == nil || item.ContentDetails == nil || item.ContentDetails.VideoId == "" || item.Snippet.VideoOwnerChannelId == "" {
+		return nil
+	}
+	song := &voice.Song{
+		Type:       "youtube_provider",
+		Title:      item.Snippet.Title,
+		URL:        fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ContentDetails.VideoId),
+		Playable:   true,
+		Uploader:   item.Snippet.VideoOwnerChannelTitle,
+		UploaderID: item.Snippet.VideoOwnerChannelId,
+		Unresolved: true,
+	}
+	if item.Snippet.Thumbnails != nil && item.Snippet.Thumbnails.Default != nil {
+		thumbnailURL := item.Snippet.Thumbnails.Default.Url
+		song.ThumbnailURL = &thumbnailURL
+	}
+	return song
+}
@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/youtube/v3"
+	"testing"
+)
+
+func TestParsePlaylistID(t *testing.T) {
+	tests := []struct {
+		input string
+		id    string
+		ok    bool
+	}{
+		{input: "https://www.youtube.com/playlist?list=PL123", id: "PL123", ok: true},
+		{input: "https://music.youtube.com/playlist?list=PL123&si=abc", id: "PL123", ok: true},
+		{input: " https://youtube.com/playlist?list=PL123 ", id: "PL123", ok: true},
+		{input: "https://www.youtube.com/watch?v=abc&list=PL123"},
+		{input: "https://www.youtube.com/playlist"},
+		{input: "https://example.com/playlist?list=PL123"},
+		{input: "lofi hip hop"},
+	}
+	for _, tt := range tests {
+		id, ok := ParsePlaylistID(tt.input)
+		assert.Equal(t, tt.ok, ok, tt.input)
+		assert.Equal(t, tt.id, id, tt.input)
+	}
+}
+
+func playlistItem(videoID, title string) *youtube.PlaylistItem {
+	return &youtube.PlaylistItem{
+		Snippet: &youtube.PlaylistItemSnippet{
+			Title:                  title,
+			VideoOwnerChannelId:    "channel",
+			VideoOwnerChannelTitle: "Artista",
+		},
+		ContentDetails: &youtube.PlaylistItemContentDetails{VideoId: videoID},
+	}
+}
+
+func TestYoutubeFetcher_LookupPlaylist(t *testing.T) {
+	t.Run("Resolves only the first songs", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockCache := new(MockCacheManager)
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(mockLogger, mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor)).WithPlaylistResolution(10, 2, 2)
+
+		deleted := playlistItem("gone", "Deleted video")
+		deleted.Snippet.VideoOwnerChannelId = ""
+		items := []*youtube.PlaylistItem{playlistItem("a", "A"), playlistItem("b", "B"), deleted, playlistItem("c", "C")}
+		mockYoutubeService.On("GetPlaylistItems", mock.Anything, "PL123", 10).Return(items, nil)
+		mockCache.On("Get", mock.Anything).Return(nil)
+		mockCache.On("Set", mock.Anything, mock.Anything)
+		for _, id := range []string{"a", "b"} {
+			mockYoutubeService.On("GetVideoDetails", mock.Anything, id).Return(&youtube.Video{
+				Snippet:        &youtube.VideoSnippet{Title: "Completa " + id, Thumbnails: &youtube.ThumbnailDetails{Default: &youtube.Thumbnail{Url: "thumb"}}},
+				ContentDetails: &youtube.VideoContentDetails{Duration: "PT3M"},
+			}, nil)
+		}
+		mockLogger.On("Info", "Playlist cargada", mock.Anything)
+
+		songs, err := fetcher.LookupSongs(context.Background(), "https://www.youtube.com/playlist?list=PL123")
+
+		require.NoError(t, err)
+		require.Len(t, songs, 3)
+		assert.Equal(t, "Completa a", songs[0].Title)
+		assert.False(t, songs[0].Unresolved)
+		assert.Equal(t, "Completa b", songs[1].Title)
+		assert.Equal(t, "C", songs[2].Title)
+		assert.True(t, songs[2].Unresolved)
+		assert.Equal(t, "https://www.youtube.com/watch?v=c", songs[2].URL)
+		assert.Equal(t, "Artista", songs[2].Uploader)
+		mockYoutubeService.AssertNotCalled(t, "GetVideoDetails", mock.Anything, "c")
+	})
+	t.Run("Failed resolution keeps the song unresolved", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockCache := new(MockCacheManager)
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(mockLogger, mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+
+		mockYoutubeService.On("GetPlaylistItems", mock.Anything, "PL123", defaultPlaylistMaxSize).Return([]*youtube.PlaylistItem{playlistItem("a", "A")}, nil)
+		mockCache.On("Get", mock.Anything).Return(nil)
+		mockYoutubeService.On("GetVideoDetails", mock.Anything, "a").Return(&youtube.Video{}, errors.New("cuota"))
+		mockLogger.On("Error", mock.Anything, mock.Anything)
+		mockLogger.On("Warn", "No se pudo obtener la metadata de una canción de la playlist", mock.Anything)
+		mockLogger.On("Info", "Playlist cargada", mock.Anything)
+
+		songs, err := fetcher.LookupSongs(context.Background(), "https://www.youtube.com/playlist?list=PL123")
+
+		require.NoError(t, err)
+		require.Len(t, songs, 1)
+		assert.True(t, songs[0].Unresolved)
+		assert.Equal(t, "A", songs[0].Title)
+	})
+	t.Run("Playlist error", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(mockLogger, new(MockCacheManager), mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+
+		mockYoutubeService.On("GetPlaylistItems", mock.Anything, "PL123", defaultPlaylistMaxSize).Return(nil, fmt.Errorf("playlist no encontrada"))
+		mockLogger.On("Error", "Error al obtener los elementos de la playlist", mock.Anything)
+
+		_, err := fetcher.LookupSongs(context.Background(), "https://www.youtube.com/playlist?list=PL123")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestYoutubeFetcher_RefreshUnresolvedSong(t *testing.T) {
+	mockCache := new(MockCacheManager)
+	mockYoutubeService := new(MockYouTubeService)
+	fetcher := NewYoutubeFetcher(new(MockLogger), mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+
+	videoURL := "https://www.youtube.com/watch?v=abc"
+	mockCache.On("Get", videoURL).Return([]*voice.Song{{URL: videoURL, Title: "Completa", Playable: true}})
+	song := &voice.Song{URL: videoURL, Title: "Parcial", RequestedByID: "u1", Unresolved: true}
+
+	refreshed, err := fetcher.RefreshSong(context.Background(), song)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Completa", refreshed.Title)
+	assert.Equal(t, "u1", refreshed.RequestedByID)
+	mockYoutubeService.AssertNotCalled(t, "GetVideoDetails", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+)
+
+// downloadGuildKey es la clave del contexto con el servidor para el que se descarga el audio.
+type downloadGuildKey struct{}
+
+// WithDownloadGuild indica el servidor para el que se descarga el audio con el contexto, para aplicarle el límite
+// de procesos por servidor del ProcessLimiter.
+func WithDownloadGuild(ctx context.Context, guildID string) context.Context {
+	return context.WithValue(ctx, downloadGuildKey{}, guildID)
+}
+
+// downloadGuild devuelve el servidor de la descarga, o "" si el contexto no lo indica.
+func downloadGuild(ctx context.Context) string {
+	guildID, _ := ctx.Value(downloadGuildKey{}).(string)
+	return guildID
+}
+
+// guildSlots son los lugares de un servidor y la cantidad de descargas que los usan o esperan uno.
+type guildSlots struct {
+	slots chan struct{}
+	users int
+}
+
+// ProcessLimiter limita la cantidad de pipelines de descarga (yt-dlp, ffmpeg y dca) que corren a la vez, en total
+// y por servidor, para que un host chico no se quede sin memoria al descargar una playlist entera. Las descargas
+// que no entran esperan su turno en orden de llegada.
+type ProcessLimiter struct {
+	global   chan struct{} // global es nil si no hay límite total.
+	perGuild int           // perGuild es 0 si no hay límite por servidor.
+
+	mu     sync.Mutex
+	guilds map[string]*guildSlots
+}
+
+// NewProcessLimiter crea un limitador con la cantidad máxima de pipelines en total y por servidor. Un límite de 0 o
+// menos no limita.
+func NewProcessLimiter(global, perGuild int) *ProcessLimiter {
+	l := &ProcessLimiter{guilds: make(map[string]*guildSlots)}
+	if global > 0 {
+		l.global = make(chan struct{}, global)
+	}
+	if perGuild > 0 {
+		l.perGuild = perGuild
+	}
+	return l
+}
+
+// Acquire espera un lugar para lanzar un pipeline del servidor del contexto (ver WithDownloadGuild) y devuelve la
+// función que lo libera. Si el contexto se cancela mientras espera, devuelve su error.
+func (l *ProcessLimiter) Acquire(ctx context.Context) (func(), error) {
+	guildID := downloadGuild(ctx)
+	var guild *guildSlots
+	if l.perGuild > 0 && guildID != "" {
+		guild = l.joinGuild(guildID)
+		select {
+		case guild.slots <- struct{}{}:
+		case <-ctx.Done():
+			l.leaveGuild(guildID)
+			return nil, ctx.Err()
+		}
+	}
+	// El lugar del servidor se toma antes que el global, para que un servidor saturado no ocupe lugares globales
+	// mientras espera.
+	if l.global != nil {
+		select {
+		case l.global <- struct{}{}:
+		case <-ctx.Done():
+			if guild != nil {
+				<-guild.slots
+				l.leaveGuild(guildID)
+			}
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if l.global != nil {
+				<-l.global
+			}
+			if guild != nil {
+				<-guild.slots
+				l.leaveGuild(guildID)
+			}
+		})
+	}, nil
+}
+
+func (l *ProcessLimiter) joinGuild(guildID string) *guildSlots {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	guild, ok := l.guilds[guildID]
+	if !ok {
+		guild = &guildSlots{slots: make(chan struct{}, l.perGuild)}
+		l.guilds[guildID] = guild
+	}
+	guild.users++
+	return guild
+}
+
+// leaveGuild descarta los lugares del servidor cuando ninguna descarga los usa.
+func (l *ProcessLimiter) leaveGuild(guildID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	guild := l.guilds[guildID]
+	guild.users--
+	if guild.users == 0 {
+		delete(l.guilds, guildID)
+	}
+}
@@ -0,0 +1,66 @@
+package fetcher
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProcessLimiter_PerGuild(t *testing.T) {
+	limiter := NewProcessLimiter(3, 1)
+	guildA := WithDownloadGuild(context.Background(), "a")
+
+	releaseA, err := limiter.Acquire(guildA)
+	assert.NoError(t, err)
+
+	// El servidor a ya usa su único lugar, pero el b tiene el suyo.
+	releaseB, err := limiter.Acquire(WithDownloadGuild(context.Background(), "b"))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(guildA, 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	releaseA()
+	releaseA()
+	releaseB()
+	assert.Empty(t, limiter.guilds)
+	assert.Len(t, limiter.global, 0)
+}
+
+func TestProcessLimiter_GlobalQueue(t *testing.T) {
+	limiter := NewProcessLimiter(1, 0)
+	release, err := limiter.Acquire(context.Background())
+	assert.NoError(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		next, err := limiter.Acquire(WithDownloadGuild(context.Background(), "a"))
+		assert.NoError(t, err)
+		acquired <- next
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("la descarga no debería empezar mientras el límite está lleno")
+	case <-time.After(10 * time.Millisecond):
+	}
+	release()
+	select {
+	case next := <-acquired:
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("la descarga en espera no empezó al liberarse un lugar")
+	}
+}
+
+func TestProcessLimiter_Unlimited(t *testing.T) {
+	limiter := NewProcessLimiter(0, 0)
+	ctx := WithDownloadGuild(context.Background(), "a")
+	for i := 0; i < 10; i++ {
+		_, err := limiter.Acquire(ctx)
+		assert.NoError(t, err)
+	}
+}
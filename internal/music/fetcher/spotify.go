@@ -0,0 +1,159 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// defaultSpotifyMaxTracks es la cantidad máxima de canciones que se cargan de un álbum o una lista de Spotify. Cada
+// una es una búsqueda en YouTube, que gasta bastante más cuota que leer una playlist de YouTube.
+const defaultSpotifyMaxTracks = 100
+
+// ErrSpotifyNoMatches indica que no se encontró en YouTube ninguna de las canciones de un link de Spotify.
+var ErrSpotifyNoMatches = errors.New("no se encontraron en YouTube las canciones de Spotify")
+
+// SpotifyCatalog lee canciones de la API web de Spotify. Lo implementa spotify.Client.
+type SpotifyCatalog interface {
+	Track(ctx context.Context, id string) (spotify.Track, error)
+	AlbumTracks(ctx context.Context, id string) ([]spotify.Track, error)
+	PlaylistTracks(ctx context.Context, id string) ([]spotify.Track, error)
+}
+
+// SpotifyFetcher es un SongLooker que reconoce los links de canciones, álbumes y listas de Spotify. Spotify no
+// entrega el audio, así que cada canción se busca en YouTube como "Artista - Título" y se reproduce el primer
+// resultado. Lo demás lo resuelve el SongLooker siguiente.
+type SpotifyFetcher struct {
+	logger    logging.Logger
+	catalog   SpotifyCatalog
+	next      SongLooker
+	maxTracks int
+}
+
+// NewSpotifyFetcher crea un SpotifyFetcher que busca las canciones de Spotify con next, que también resuelve todo
+// lo que no es un link de Spotify.
+func NewSpotifyFetcher(logger logging.Logger, catalog SpotifyCatalog, next SongLooker) *SpotifyFetcher {
+	return &SpotifyFetcher{logger: logger, catalog: catalog, next: next, maxTracks: defaultSpotifyMaxTracks}
+}
+
+// WithMaxTracks configura la cantidad máxima de canciones que se cargan de un álbum o una lista.
+func (s *SpotifyFetcher) WithMaxTracks(maxTracks int) *SpotifyFetcher {
+	s.maxTracks = maxTracks
+	return s
+}
+
+// SearchYouTubeVideoID devuelve los links de Spotify sin cambios, para que LookupSongs los resuelva.
+func (s *SpotifyFetcher) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	if _, err := spotify.ParseLink(searchTerm); err == nil {
+		return searchTerm, nil
+	}
+	return s.next.SearchYouTubeVideoID(ctx, searchTerm)
+}
+
+// SearchYouTubeVideoIDs delega la búsqueda de varios resultados en next, si la soporta.
+func (s *SpotifyFetcher) SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	searcher, ok := s.next.(SongSearcher)
+	if !ok {
+		return nil, ErrSearchNotSupported
+	}
+	return searcher.SearchYouTubeVideoIDs(ctx, searchTerm, limit)
+}
+
+// LookupSongs devuelve las canciones de YouTube equivalentes a las del link de Spotify, en el mismo orden. Las que
+// no se encuentran se omiten.
+func (s *SpotifyFetcher) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	link, err := spotify.ParseLink(input)
+	if err != nil {
+		return s.next.LookupSongs(ctx, input)
+	}
+	logger := logging.WithFields(logging.FromContext(ctx, s.logger), zap.String("spotify", link.Kind+":"+link.ID))
+
+	start := time.Now()
+	tracks, err := s.tracks(ctx, link)
+	if err != nil {
+		logger.Error("Error al obtener las canciones de Spotify", zap.Error(err))
+		return nil, fmt.Errorf("error al obtener las canciones de Spotify: %w", err)
+	}
+	if len(tracks) > s.maxTracks {
+		tracks = tracks[:s.maxTracks]
+	}
+	// Como con las playlists de YouTube, las búsquedas de un álbum o una lista ceden el paso a las de una canción.
+	if _, ok := bulkLookupGuild(ctx); !ok && link.Kind != spotify.KindTrack {
+		ctx = WithBulkLookup(ctx, link.ID)
+	}
+
+	found := make([]*voice.Song, len(tracks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(defaultPlaylistWorkers, len(tracks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				song, err := s.searchTrack(ctx, tracks[i])
+				if err != nil {
+					logger.Warn("No se encontró en YouTube una canción de Spotify", zap.String("canción", tracks[i].Query()), zap.Error(err))
+					continue
+				}
+				found[i] = song
+			}
+		}()
+	}
+	for i := range tracks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	songs := make([]*voice.Song, 0, len(found))
+	for _, song := range found {
+		if song != nil {
+			songs = append(songs, song)
+		}
+	}
+	if len(songs) == 0 {
+		return nil, ErrSpotifyNoMatches
+	}
+	logger.Info("Link de Spotify cargado", zap.Int("canciones", len(songs)), zap.Int("no_encontradas", len(tracks)-len(songs)), zap.Duration("duración", time.Since(start)))
+	return songs, nil
+}
+
+// tracks devuelve las canciones de Spotify del link.
+func (s *SpotifyFetcher) tracks(ctx context.Context, link spotify.Link) ([]spotify.Track, error) {
+	switch link.Kind {
+	case spotify.KindTrack:
+		track, err := s.catalog.Track(ctx, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		return []spotify.Track{track}, nil
+	case spotify.KindAlbum:
+		return s.catalog.AlbumTracks(ctx, link.ID)
+	default:
+		return s.catalog.PlaylistTracks(ctx, link.ID)
+	}
+}
+
+// searchTrack busca la canción en YouTube y devuelve el primer resultado.
+func (s *SpotifyFetcher) searchTrack(ctx context.Context, track spotify.Track) (*voice.Song, error) {
+	videoID, err := s.next.SearchYouTubeVideoID(ctx, track.Query())
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.next.LookupSongs(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrSpotifyNoMatches
+	}
+	// Se copia para no compartir con la caché la canción a la que después se le asigna quién la pidió.
+	song := *results[0]
+	return &song, nil
+}
@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const testSpotifyID = "37i9dQZF1DXcBWIGoYBM5M"
+
+func newTestSpotifyFetcher() (*SpotifyFetcher, *MockSpotifyCatalog, *MockSongLooker) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	catalog, next := new(MockSpotifyCatalog), new(MockSongLooker)
+	return NewSpotifyFetcher(logger, catalog, next), catalog, next
+}
+
+func TestSpotifyFetcher_SearchYouTubeVideoID(t *testing.T) {
+	fetcher, _, next := newTestSpotifyFetcher()
+	next.On("SearchYouTubeVideoID", mock.Anything, "la bamba").Return("abc", nil)
+
+	link := "https://open.spotify.com/track/" + testSpotifyID
+	id, err := fetcher.SearchYouTubeVideoID(context.Background(), link)
+	require.NoError(t, err)
+	assert.Equal(t, link, id, "los links de Spotify se resuelven en LookupSongs")
+
+	id, err = fetcher.SearchYouTubeVideoID(context.Background(), "la bamba")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", id)
+}
+
+func TestSpotifyFetcher_LookupSongs(t *testing.T) {
+	t.Run("Searches each track on YouTube in order", func(t *testing.T) {
+		fetcher, catalog, next := newTestSpotifyFetcher()
+		catalog.On("AlbumTracks", mock.Anything, testSpotifyID).Return([]spotify.Track{
+			{Name: "Uno", Artists: []string{"A"}},
+			{Name: "Perdida", Artists: []string{"A"}},
+			{Name: "Dos", Artists: []string{"A", "B"}},
+		}, nil)
+		cached := &voice.Song{Title: "A - Uno", URL: "https://www.youtube.com/watch?v=1"}
+		next.On("SearchYouTubeVideoID", mock.Anything, "A - Uno").Return("1", nil)
+		next.On("SearchYouTubeVideoID", mock.Anything, "A - Perdida").Return("", errors.New("sin resultados"))
+		next.On("SearchYouTubeVideoID", mock.Anything, "A, B - Dos").Return("2", nil)
+		next.On("LookupSongs", mock.Anything, "1").Return([]*voice.Song{cached}, nil)
+		next.On("LookupSongs", mock.Anything, "2").Return([]*voice.Song{{Title: "A, B - Dos", URL: "https://www.youtube.com/watch?v=2"}}, nil)
+
+		songs, err := fetcher.LookupSongs(context.Background(), "spotify:album:"+testSpotifyID)
+
+		require.NoError(t, err)
+		require.Len(t, songs, 2)
+		assert.Equal(t, "A - Uno", songs[0].Title)
+		assert.Equal(t, "A, B - Dos", songs[1].Title)
+		assert.NotSame(t, cached, songs[0], "no comparte la canción de la caché")
+	})
+
+	t.Run("Limits the tracks of a playlist", func(t *testing.T) {
+		fetcher, catalog, next := newTestSpotifyFetcher()
+		fetcher.WithMaxTracks(1)
+		catalog.On("PlaylistTracks", mock.Anything, testSpotifyID).Return([]spotify.Track{{Name: "Uno"}, {Name: "Dos"}}, nil)
+		next.On("SearchYouTubeVideoID", mock.Anything, "Uno").Return("1", nil)
+		next.On("LookupSongs", mock.Anything, "1").Return([]*voice.Song{{Title: "Uno"}}, nil)
+
+		songs, err := fetcher.LookupSongs(context.Background(), "https://open.spotify.com/playlist/"+testSpotifyID)
+
+		require.NoError(t, err)
+		assert.Len(t, songs, 1)
+		next.AssertNotCalled(t, "SearchYouTubeVideoID", mock.Anything, "Dos")
+	})
+
+	t.Run("Fails when no track is found", func(t *testing.T) {
+		fetcher, catalog, next := newTestSpotifyFetcher()
+		catalog.On("Track", mock.Anything, testSpotifyID).Return(spotify.Track{Name: "Uno"}, nil)
+		next.On("SearchYouTubeVideoID", mock.Anything, "Uno").Return("", errors.New("sin resultados"))
+
+		_, err := fetcher.LookupSongs(context.Background(), "https://open.spotify.com/track/"+testSpotifyID)
+
+		assert.ErrorIs(t, err, ErrSpotifyNoMatches)
+	})
+
+	t.Run("Returns Spotify errors", func(t *testing.T) {
+		fetcher, catalog, _ := newTestSpotifyFetcher()
+		catalog.On("Track", mock.Anything, testSpotifyID).Return(spotify.Track{}, spotify.ErrNotFound)
+
+		_, err := fetcher.LookupSongs(context.Background(), "https://open.spotify.com/track/"+testSpotifyID)
+
+		assert.ErrorIs(t, err, spotify.ErrNotFound)
+	})
+
+	t.Run("Delegates other inputs", func(t *testing.T) {
+		fetcher, _, next := newTestSpotifyFetcher()
+		next.On("LookupSongs", mock.Anything, "abc").Return([]*voice.Song{{Title: "La Bamba"}}, nil)
+
+		songs, err := fetcher.LookupSongs(context.Background(), "abc")
+
+		require.NoError(t, err)
+		assert.Len(t, songs, 1)
+	})
+}
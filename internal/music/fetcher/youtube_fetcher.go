@@ -3,12 +3,16 @@ package fetcher
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Tomas-vilte/GoMusicBot/internal/cache"
 	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice/codec"
 	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/metrics"
 	"github.com/Tomas-vilte/GoMusicBot/internal/services/providers"
 	"go.uber.org/zap"
+	"google.golang.org/api/youtube/v3"
 	"io"
 	"os/exec"
 	"strconv"
@@ -16,6 +20,13 @@ import (
 	"time"
 )
 
+var (
+	// ErrSearchNotSupported indica que el buscador de canciones no devuelve varios resultados de una búsqueda.
+	ErrSearchNotSupported = errors.New("el buscador no permite buscar varias canciones")
+	// ErrSongUnavailable indica que la canción ya no está disponible en la fuente, por ejemplo porque se borró el video.
+	ErrSongUnavailable = errors.New("la canción ya no está disponible")
+)
+
 type (
 	// SongLooker define la interfaz para buscar canciones.
 	SongLooker interface {
@@ -23,6 +34,19 @@ type (
 		SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error)
 	}
 
+	// SongSearcher es un SongLooker que además devuelve varios resultados de una búsqueda, por ejemplo para armar
+	// una lista de canciones de un género.
+	SongSearcher interface {
+		SongLooker
+		SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error)
+	}
+
+	// SongRefresher vuelve a buscar la metadata de una canción en la fuente, sin usar la caché, para revalidarla
+	// antes de reproducirla.
+	SongRefresher interface {
+		RefreshSong(ctx context.Context, song *voice.Song) (*voice.Song, error)
+	}
+
 	// YoutubeFetcher es un tipo que interactúa con YouTube para obtener metadatos y datos de audio.
 	YoutubeFetcher struct {
 		Logger          logging.Logger
@@ -30,6 +54,15 @@ type (
 		audioCache      cache.AudioCaching
 		YoutubeService  providers.YouTubeService
 		CommandExecutor CommandExecutor
+		metrics         metrics.FetcherMetrics // metrics es opcional; si es nil no se registran métricas.
+		processGauge    metrics.GaugeMetric    // processGauge es opcional; cuenta los procesos externos en ejecución.
+		breaker         *CircuitBreaker        // breaker es opcional; corta las solicitudes mientras YouTube falla.
+		limiter         *ProcessLimiter        // limiter es opcional; limita las descargas que corren a la vez.
+		clients         *ClientRotator         // clients es opcional; rota el cliente de YouTube cuando lo bloquean.
+		addresses       *AddressRotator        // addresses es opcional; rota la dirección IPv6 de cada descarga.
+		playlistMaxSize int                    // playlistMaxSize es la cantidad máxima de canciones que se cargan de una playlist.
+		playlistEager   int                    // playlistEager es la cantidad de canciones de una playlist que se resuelven al cargarla.
+		playlistWorkers int                    // playlistWorkers es la cantidad de canciones de una playlist que se resuelven a la vez.
 	}
 
 	// CommandExecutor define una interfaz para ejecutar comandos del sistema.
@@ -56,44 +89,224 @@ func NewYoutubeFetcher(logger logging.Logger, cache cache.Manager, youtubeServic
 		YoutubeService:  youtubeService,
 		audioCache:      audioCache,
 		CommandExecutor: commandExecutor,
+		playlistMaxSize: defaultPlaylistMaxSize,
+		playlistEager:   defaultPlaylistEager,
+		playlistWorkers: defaultPlaylistWorkers,
 	}
 }
 
+// Nombres de las operaciones usados como etiqueta en las métricas del fetcher.
+const (
+	operationLookupSongs = "lookup_songs"
+	operationRefreshSong = "refresh_song"
+	operationSearch      = "search_video_id"
+	operationGetDCAData  = "get_dca_data"
+	operationDownload    = "download_audio"
+)
+
+// audioPipelineProcesses son los procesos externos que lanza cada descarga de audio.
+var audioPipelineProcesses = []string{"yt-dlp", "ffmpeg", "dca"}
+
+// stderrTailSize es la cantidad de bytes de la salida de error de yt-dlp que se adjuntan a los errores.
+const stderrTailSize = 2048
+
+// WithMetrics establece las métricas del fetcher.
+func (s *YoutubeFetcher) WithMetrics(m metrics.FetcherMetrics) *YoutubeFetcher {
+	s.metrics = m
+	return s
+}
+
+// WithProcessGauge establece la métrica de procesos externos en ejecución.
+func (s *YoutubeFetcher) WithProcessGauge(g metrics.GaugeMetric) *YoutubeFetcher {
+	s.processGauge = g
+	return s
+}
+
+// WithCircuitBreaker establece el circuit breaker que protege las solicitudes a YouTube.
+func (s *YoutubeFetcher) WithCircuitBreaker(cb *CircuitBreaker) *YoutubeFetcher {
+	s.breaker = cb
+	return s
+}
+
+// WithProcessLimiter establece el límite de descargas que corren a la vez.
+func (s *YoutubeFetcher) WithProcessLimiter(l *ProcessLimiter) *YoutubeFetcher {
+	s.limiter = l
+	return s
+}
+
+// WithClientRotation establece la rotación de clientes de YouTube de yt-dlp.
+func (s *YoutubeFetcher) WithClientRotation(r *ClientRotator) *YoutubeFetcher {
+	s.clients = r
+	return s
+}
+
+// WithAddressRotation establece el bloque IPv6 desde el que salen las descargas, una dirección por descarga.
+func (s *YoutubeFetcher) WithAddressRotation(r *AddressRotator) *YoutubeFetcher {
+	s.addresses = r
+	return s
+}
+
+// allow consulta al circuit breaker, si está configurado.
+func (s *YoutubeFetcher) allow() error {
+	if s.breaker == nil {
+		return nil
+	}
+	return s.breaker.Allow()
+}
+
+// record registra el resultado de la solicitud en el circuit breaker, si está configurado.
+func (s *YoutubeFetcher) record(err error) {
+	if s.breaker != nil {
+		s.breaker.Record(err)
+	}
+}
+
+// trackProcesses incrementa (o decrementa, si running es false) el gauge de cada proceso del pipeline de audio.
+func (s *YoutubeFetcher) trackProcesses(running bool) {
+	if s.processGauge == nil {
+		return
+	}
+	for _, process := range audioPipelineProcesses {
+		if running {
+			s.processGauge.Inc(process)
+		} else {
+			s.processGauge.Dec(process)
+		}
+	}
+}
+
+// observe registra la duración de la operación y, si falló, el error con su causa.
+func (s *YoutubeFetcher) observe(operation string, start time.Time, err error) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.ObserveDuration(operation, time.Since(start))
+	if err != nil {
+		s.metrics.Inc(operation, classifyError(err))
+	}
+}
+
+// observeCache registra si la operación se resolvió desde la caché.
+func (s *YoutubeFetcher) observeCache(operation string, hit bool) {
+	if s.metrics == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	s.metrics.IncCacheResult(operation, result)
+}
+
 // LookupSongs busca canciones en YouTube según el término de búsqueda proporcionado en input.
-// Retorna una lista de objetos bot.Song que contienen metadatos de las canciones encontradas.
+// Retorna una lista de objetos bot.Song que contienen metadatos de las canciones encontradas. Si input es el link de
+// una playlist, devuelve sus canciones; solo las primeras tienen la metadata completa (ver WithPlaylistResolution).
 func (s *YoutubeFetcher) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	if playlistID, ok := ParsePlaylistID(input); ok {
+		return s.lookupPlaylist(ctx, playlistID)
+	}
+	logger := logging.FromContext(ctx, s.Logger)
 	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", input)
 
+	start := time.Now()
+
 	cachedResult := s.Cache.Get(videoURL)
+	s.observeCache(operationLookupSongs, cachedResult != nil)
 	if cachedResult != nil {
-		s.Logger.Info("Video encontrado en cache: ", zap.String("Video", videoURL))
+		logger.Info("Video encontrado en cache: ", zap.String("Video", videoURL))
 		return cachedResult, nil
 	}
 
+	if err := s.allow(); err != nil {
+		logger.Warn("Búsqueda rechazada por el circuit breaker", zap.String("Video", videoURL))
+		return nil, err
+	}
 	video, err := s.YoutubeService.GetVideoDetails(ctx, input)
+	s.record(err)
+	s.observe(operationLookupSongs, start, err)
 	if err != nil {
-		s.Logger.Error("Error al obtener detalles del video", zap.Error(err))
+		logger.Error("Error al obtener detalles del video", zap.Error(err), zap.String("cause", classifyError(err)))
 		return nil, fmt.Errorf("error al obtener detalles del video")
 	}
 
+	song := s.songFromVideo(logger, videoURL, video)
+	songs := []*voice.Song{song}
+
+	s.Cache.Set(videoURL, songs)
+	return songs, nil
+}
+
+// RefreshSong vuelve a pedir a YouTube la metadata de la canción, sin usar la caché, y actualiza la caché con la
+// nueva. La canción que devuelve conserva quién la pidió y desde dónde arranca. Si el video ya no existe devuelve
+// ErrSongUnavailable; las canciones que no son videos de YouTube se devuelven sin cambios.
+func (s *YoutubeFetcher) RefreshSong(ctx context.Context, song *voice.Song) (*voice.Song, error) {
+	logger := logging.FromContext(ctx, s.Logger)
+	videoID, ok := strings.CutPrefix(song.CanonicalID(), "youtube:")
+	if !ok {
+		return song, nil
+	}
+	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
+
+	// Las canciones de playlists sin resolver no necesitan metadata recién pedida: alcanza con la de la caché, que
+	// puede haber cargado el reproductor por adelantado.
+	if song.Unresolved {
+		if cached := s.Cache.Get(videoURL); len(cached) == 1 {
+			return withRequest(cached[0], song), nil
+		}
+	}
+
+	start := time.Now()
+	if err := s.allow(); err != nil {
+		return nil, err
+	}
+	video, err := s.YoutubeService.GetVideoDetails(ctx, videoID)
+	s.record(err)
+	s.observe(operationRefreshSong, start, err)
+	if err != nil {
+		if classifyError(err) == ErrorCauseNotFound {
+			logger.Info("El video ya no está disponible", zap.String("Video", videoURL))
+			return nil, fmt.Errorf("%w: %s", ErrSongUnavailable, videoURL)
+		}
+		logger.Error("Error al actualizar los detalles del video", zap.Error(err), zap.String("cause", classifyError(err)))
+		return nil, fmt.Errorf("error al actualizar los detalles del video: %w", err)
+	}
+
+	fresh := s.songFromVideo(logger, videoURL, video)
+	s.Cache.Set(videoURL, []*voice.Song{fresh})
+	return withRequest(fresh, song), nil
+}
+
+// withRequest devuelve una copia de fresh que conserva quién pidió la canción original y desde dónde arranca.
+func withRequest(fresh, original *voice.Song) *voice.Song {
+	refreshed := *fresh
+	refreshed.RequestedBy = original.RequestedBy
+	refreshed.RequestedByID = original.RequestedByID
+	refreshed.StartPosition = original.StartPosition
+	return &refreshed
+}
+
+// songFromVideo arma la canción a partir de los detalles del video de YouTube.
+func (s *YoutubeFetcher) songFromVideo(logger logging.Logger, videoURL string, video *youtube.Video) *voice.Song {
 	duration, err := parseCustomDuration(video.ContentDetails.Duration)
 	if err != nil {
-		s.Logger.Error("Error al analizar la duracion: ", zap.Error(err))
+		logger.Error("Error al analizar la duracion: ", zap.Error(err))
 	}
 	thumbnailURL := video.Snippet.Thumbnails.Default.Url
+	lookedUpAt := time.Now()
 
-	song := &voice.Song{
+	return &voice.Song{
 		Type:         "youtube_provider",
 		Title:        video.Snippet.Title,
 		URL:          videoURL,
 		Playable:     video.Snippet.LiveBroadcastContent != "live",
 		ThumbnailURL: &thumbnailURL,
 		Duration:     duration,
+		Uploader:     video.Snippet.ChannelTitle,
+		UploaderID:   video.Snippet.ChannelId,
+		Explicit:     video.ContentDetails.ContentRating != nil && video.ContentDetails.ContentRating.YtRating == "ytAgeRestricted",
+		Chapters:     voice.ParseChapters(video.Snippet.Description, duration),
+		LookedUpAt:   &lookedUpAt,
 	}
-	songs := []*voice.Song{song}
-
-	s.Cache.Set(videoURL, songs)
-	return songs, nil
 }
 
 func parseCustomDuration(durationStr string) (time.Duration, error) {
@@ -147,11 +360,19 @@ func parseCustomDuration(durationStr string) (time.Duration, error) {
 // Utiliza yt-dlp y ffmpeg para descargar el audio de YouTube y convertirlo al formato DCA esperado por Discord.
 // Retorna un io.Reader que permite leer los datos de audio y un posible error.
 func (s *YoutubeFetcher) GetDCAData(ctx context.Context, song *voice.Song) (io.Reader, error) {
+	logger := logging.FromContext(ctx, s.Logger)
 	// Verificar si los datos de audio están en caché
-	if cachedData, ok := s.audioCache.Get(song.URL); ok {
+	cachedData, ok := s.audioCache.Get(song.URL)
+	s.observeCache(operationGetDCAData, ok)
+	if ok {
 		return bytes.NewReader(cachedData), nil
 	}
 
+	if err := s.allow(); err != nil {
+		logger.Warn("Descarga rechazada por el circuit breaker", zap.String("URL", song.URL))
+		return nil, err
+	}
+
 	// Crear un pipe para la transmisión progresiva de datos
 	reader, writer := io.Pipe()
 
@@ -162,8 +383,12 @@ func (s *YoutubeFetcher) GetDCAData(ctx context.Context, song *voice.Song) (io.R
 		var buffer bytes.Buffer
 		multiWriter := io.MultiWriter(writer, &buffer)
 
-		if err := s.downloadAndStreamAudio(ctx, song, multiWriter); err != nil {
-			s.Logger.Error("Error al descargar y transmitir audio", zap.Error(err))
+		start := time.Now()
+		err := s.downloadAndStreamAudio(ctx, song, multiWriter, 0)
+		s.record(err)
+		s.observe(operationDownload, start, err)
+		if err != nil {
+			logger.Error("Error al descargar y transmitir audio", zap.Error(err), zap.String("cause", classifyError(err)))
 			writer.CloseWithError(err)
 			return
 		}
@@ -174,9 +399,102 @@ func (s *YoutubeFetcher) GetDCAData(ctx context.Context, song *voice.Song) (io.R
 	return reader, nil
 }
 
-func (s *YoutubeFetcher) downloadAndStreamAudio(ctx context.Context, song *voice.Song, writer io.Writer) error {
-	ytArgs := []string{"-f", "bestaudio[ext=m4a]", "--audio-quality", "0", "-o", "-", "--force-overwrites", "--http-chunk-size", "100K", song.URL}
+// GetDCADataFrom devuelve el audio DCA de la canción a partir de offset. Si el audio completo está en caché se
+// adelanta sobre él; si no, ffmpeg descarta el principio antes de codificar, así no se codifica lo que no va a sonar.
+// El audio que no arranca del principio no se guarda en caché.
+func (s *YoutubeFetcher) GetDCADataFrom(ctx context.Context, song *voice.Song, offset time.Duration) (io.Reader, error) {
+	if offset <= 0 {
+		return s.GetDCAData(ctx, song)
+	}
+	logger := logging.FromContext(ctx, s.Logger)
+	cachedData, ok := s.audioCache.Get(song.URL)
+	s.observeCache(operationGetDCAData, ok)
+	if ok {
+		reader := bytes.NewReader(cachedData)
+		// Si la posición está después del final, el flujo queda vacío y la canción termina.
+		if err := codec.SkipFrames(reader, offset); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		return reader, nil
+	}
+
+	if err := s.allow(); err != nil {
+		logger.Warn("Descarga rechazada por el circuit breaker", zap.String("URL", song.URL))
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		defer writer.Close()
+		start := time.Now()
+		err := s.downloadAndStreamAudio(ctx, song, writer, offset)
+		s.record(err)
+		s.observe(operationDownload, start, err)
+		if err != nil {
+			logger.Error("Error al descargar y transmitir audio", zap.Error(err), zap.String("cause", classifyError(err)))
+			writer.CloseWithError(err)
+		}
+	}()
+	return reader, nil
+}
+
+// downloadAndStreamAudio descarga el audio de la canción desde offset y lo escribe codificado en writer.
+func (s *YoutubeFetcher) downloadAndStreamAudio(ctx context.Context, song *voice.Song, writer io.Writer, offset time.Duration) error {
+	if s.limiter != nil {
+		release, err := s.limiter.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("error al esperar un lugar para la descarga: %w", err)
+		}
+		defer release()
+	}
+	var attempts []string
+	if s.clients != nil {
+		attempts = s.clients.Attempts()
+	}
+	if len(attempts) == 0 {
+		return s.runAudioPipeline(ctx, song, writer, "", offset)
+	}
+
+	// Si YouTube bloquea el cliente antes de enviar audio se prueba con el siguiente. Una vez que empezó a sonar no
+	// se puede reintentar sin repetir el principio de la canción.
+	logger := logging.FromContext(ctx, s.Logger)
+	var err error
+	for _, client := range attempts {
+		extractorArgs, tokenErr := s.clients.ExtractorArgs(ctx, client)
+		if tokenErr != nil {
+			logger.Warn("No se pudo obtener el PO token, se descarga sin él", zap.String("client", client), zap.Error(tokenErr))
+		}
+		counter := &countingWriter{w: writer}
+		err = s.runAudioPipeline(ctx, song, counter, extractorArgs, offset)
+		if err == nil {
+			s.clients.Succeeded(client)
+			return nil
+		}
+		if counter.n > 0 || ctx.Err() != nil || classifyError(err) != ErrorCauseBlocked {
+			return err
+		}
+		logger.Warn("YouTube bloqueó el cliente de yt-dlp, se prueba con otro", zap.String("client", client), zap.Error(err))
+		s.clients.Blocked(client)
+	}
+	return err
+}
+
+// runAudioPipeline ejecuta yt-dlp, ffmpeg y dca para descargar el audio de la canción y escribirlo en writer.
+// extractorArgs es opcional; se pasa a yt-dlp con --extractor-args. Con un offset mayor a cero, ffmpeg descarta el
+// audio anterior a esa posición.
+func (s *YoutubeFetcher) runAudioPipeline(ctx context.Context, song *voice.Song, writer io.Writer, extractorArgs string, offset time.Duration) error {
+	ytArgs := []string{"-f", "bestaudio[ext=m4a]", "--audio-quality", "0", "-o", "-", "--force-overwrites", "--http-chunk-size", "100K"}
+	if extractorArgs != "" {
+		ytArgs = append(ytArgs, "--extractor-args", shellQuote(extractorArgs))
+	}
+	if s.addresses != nil {
+		ytArgs = append(ytArgs, "--source-address", s.addresses.Next().String())
+	}
+	ytArgs = append(ytArgs, song.URL)
 	ffmpegArgs := []string{"-i", "pipe:0", "-b:a", "192k", "-f", "s16le", "-ar", "48000", "-ac", "2", "pipe:1"}
+	if offset > 0 {
+		ffmpegArgs = append([]string{"-ss", fmt.Sprintf("%.3f", offset.Seconds())}, ffmpegArgs...)
+	}
 
 	// Ejecuta una cadena de comandos para descargar el audio de YouTube y convertirlo a formato DCA.
 	cmd := s.CommandExecutor.ExecuteCommand(ctx, "sh", "-c", fmt.Sprintf("yt-dlp %s | ffmpeg %s | dca",
@@ -185,17 +503,56 @@ func (s *YoutubeFetcher) downloadAndStreamAudio(ctx context.Context, song *voice
 
 	// Configurar la salida del comando para escribir en el pipe
 	cmd.Stdout = writer
+	stderr := &tailBuffer{limit: stderrTailSize}
+	cmd.Stderr = stderr
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("error al iniciar el comando: %w", err)
 	}
+	s.trackProcesses(true)
+	defer s.trackProcesses(false)
 
-	return cmd.Wait()
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%w: %w", ctxErr, err)
+		}
+		if output := stderr.String(); output != "" {
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+	return nil
 }
 
+// SearchYouTubeVideoID busca el término en YouTube y devuelve el ID del primer resultado. Los links de playlists se
+// devuelven sin cambios, para que LookupSongs cargue la playlist.
 func (s *YoutubeFetcher) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	if _, ok := ParsePlaylistID(searchTerm); ok {
+		return searchTerm, nil
+	}
+	if err := s.allow(); err != nil {
+		return "", err
+	}
+	start := time.Now()
 	videoID, err := s.YoutubeService.SearchVideoID(ctx, searchTerm)
+	s.record(err)
+	s.observe(operationSearch, start, err)
 	if err != nil {
 		return "", fmt.Errorf("error al buscar el video en YouTube: %w", err)
 	}
 	return videoID, nil
 }
+
+// SearchYouTubeVideoIDs busca el término en YouTube y devuelve hasta limit IDs de video.
+func (s *YoutubeFetcher) SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	if err := s.allow(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	videoIDs, err := s.YoutubeService.SearchVideoIDs(ctx, searchTerm, limit)
+	s.record(err)
+	s.observe(operationSearch, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar videos en YouTube: %w", err)
+	}
+	return videoIDs, nil
+}
@@ -8,9 +8,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/youtube/v3"
 	"io"
 	"os/exec"
+	"strings"
 	"testing"
 	"time"
 )
@@ -55,7 +57,7 @@ func TestYoutubeFetcher_LookupSongs(t *testing.T) {
 				Duration: "PT3M33S",
 			},
 		}, nil)
-		mockCache.On("Set", videoURL, []*voice.Song{expectedSong})
+		mockCache.On("Set", videoURL, mock.AnythingOfType("[]*voice.Song"))
 
 		// Act
 		songs, err := fetcher.LookupSongs(ctx, input)
@@ -68,6 +70,7 @@ func TestYoutubeFetcher_LookupSongs(t *testing.T) {
 		assert.Equal(t, expectedSong.Playable, songs[0].Playable)
 		assert.Equal(t, expectedSong.Duration, songs[0].Duration)
 		assert.Equal(t, expectedSong.ThumbnailURL, songs[0].ThumbnailURL)
+		assert.NotNil(t, songs[0].LookedUpAt)
 
 		mockCache.AssertExpectations(t)
 		mockYoutubeService.AssertExpectations(t)
@@ -150,6 +153,119 @@ func TestYoutubeFetcher_LookupSongs(t *testing.T) {
 		mockLogger.AssertExpectations(t)
 		mockYoutubeService.AssertNotCalled(t, "GetVideoDetails", mock.Anything, mock.Anything)
 	})
+	t.Run("Records metrics", func(t *testing.T) {
+		// Arrange
+		mockLogger := new(MockLogger)
+		mockCache := new(MockCacheManager)
+		mockYoutubeService := new(MockYouTubeService)
+		mockMetrics := new(MockFetcherMetrics)
+
+		fetcher := NewYoutubeFetcher(mockLogger, mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor)).WithMetrics(mockMetrics)
+
+		ctx := context.Background()
+		input := "dQw4w9WgXcQ"
+		videoURL := "https://www.youtube.com/watch?v=dQw4w9WgXcQ"
+
+		mockCache.On("Get", videoURL).Return(nil)
+		mockYoutubeService.On("GetVideoDetails", ctx, input).Return(&youtube.Video{}, &googleapi.Error{Code: 429})
+		mockLogger.On("Error", "Error al obtener detalles del video", mock.Anything)
+		mockMetrics.On("IncCacheResult", "lookup_songs", "miss").Return()
+		mockMetrics.On("ObserveDuration", "lookup_songs", mock.AnythingOfType("time.Duration")).Return()
+		mockMetrics.On("Inc", []string{"lookup_songs", ErrorCauseRateLimited}).Return()
+
+		// Act
+		_, err := fetcher.LookupSongs(ctx, input)
+
+		// Assert
+		assert.Error(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+	t.Run("Fails fast when the circuit is open", func(t *testing.T) {
+		// Arrange
+		mockLogger := new(MockLogger)
+		mockCache := new(MockCacheManager)
+		mockYoutubeService := new(MockYouTubeService)
+		breaker := NewCircuitBreaker(1, time.Minute)
+
+		fetcher := NewYoutubeFetcher(mockLogger, mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor)).WithCircuitBreaker(breaker)
+
+		ctx := context.Background()
+		input := "dQw4w9WgXcQ"
+		videoURL := "https://www.youtube.com/watch?v=dQw4w9WgXcQ"
+
+		mockCache.On("Get", videoURL).Return(nil)
+		mockYoutubeService.On("GetVideoDetails", ctx, input).Return(&youtube.Video{}, &googleapi.Error{Code: 500}).Once()
+		mockLogger.On("Error", "Error al obtener detalles del video", mock.Anything)
+		mockLogger.On("Warn", "Búsqueda rechazada por el circuit breaker", mock.Anything)
+
+		// Act
+		_, firstErr := fetcher.LookupSongs(ctx, input)
+		_, secondErr := fetcher.LookupSongs(ctx, input)
+
+		// Assert
+		assert.Error(t, firstErr)
+		assert.ErrorIs(t, secondErr, ErrCircuitOpen)
+		mockYoutubeService.AssertNumberOfCalls(t, "GetVideoDetails", 1)
+	})
+}
+
+func TestYoutubeFetcher_RefreshSong(t *testing.T) {
+	t.Run("Refreshes without the cache and keeps the requester", func(t *testing.T) {
+		mockCache := new(MockCacheManager)
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(new(MockLogger), mockCache, mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+
+		ctx := context.Background()
+		requester := "Tomas"
+		song := &voice.Song{URL: "https://youtu.be/dQw4w9WgXcQ", Title: "En vivo", RequestedBy: &requester, RequestedByID: "u1", StartPosition: time.Minute}
+		mockYoutubeService.On("GetVideoDetails", ctx, "dQw4w9WgXcQ").Return(&youtube.Video{
+			Snippet: &youtube.VideoSnippet{
+				Title:                "Ya no está en vivo",
+				LiveBroadcastContent: "none",
+				Thumbnails:           &youtube.ThumbnailDetails{Default: &youtube.Thumbnail{Url: "thumb"}},
+			},
+			ContentDetails: &youtube.VideoContentDetails{Duration: "PT1H"},
+		}, nil)
+		mockCache.On("Set", "https://www.youtube.com/watch?v=dQw4w9WgXcQ", mock.AnythingOfType("[]*voice.Song"))
+
+		refreshed, err := fetcher.RefreshSong(ctx, song)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ya no está en vivo", refreshed.Title)
+		assert.True(t, refreshed.Playable)
+		assert.Equal(t, time.Hour, refreshed.Duration)
+		assert.Equal(t, &requester, refreshed.RequestedBy)
+		assert.Equal(t, "u1", refreshed.RequestedByID)
+		assert.Equal(t, time.Minute, refreshed.StartPosition)
+		assert.NotNil(t, refreshed.LookedUpAt)
+		mockCache.AssertNotCalled(t, "Get", mock.Anything)
+		mockCache.AssertExpectations(t)
+	})
+	t.Run("Deleted video", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(mockLogger, new(MockCacheManager), mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+
+		ctx := context.Background()
+		mockYoutubeService.On("GetVideoDetails", ctx, "abc").Return(&youtube.Video{}, fmt.Errorf("video no encontrado con el ID: abc"))
+		mockLogger.On("Info", "El video ya no está disponible", mock.Anything)
+
+		_, err := fetcher.RefreshSong(ctx, &voice.Song{URL: "https://www.youtube.com/watch?v=abc"})
+
+		assert.ErrorIs(t, err, ErrSongUnavailable)
+		mockLogger.AssertExpectations(t)
+	})
+	t.Run("Not a YouTube video", func(t *testing.T) {
+		mockYoutubeService := new(MockYouTubeService)
+		fetcher := NewYoutubeFetcher(new(MockLogger), new(MockCacheManager), mockYoutubeService, new(MockAudioCaching), new(MockCommandExecutor))
+		song := &voice.Song{URL: "https://soundcloud.com/artista/tema"}
+
+		refreshed, err := fetcher.RefreshSong(context.Background(), song)
+
+		require.NoError(t, err)
+		assert.Same(t, song, refreshed)
+		mockYoutubeService.AssertNotCalled(t, "GetVideoDetails", mock.Anything, mock.Anything)
+	})
 }
 
 func TestYoutubeFetcher_GetDCAData(t *testing.T) {
@@ -193,6 +309,37 @@ func TestYoutubeFetcher_GetDCAData(t *testing.T) {
 		mockAudioCache.AssertExpectations(t)
 	})
 
+	t.Run("Tracks external processes", func(t *testing.T) {
+		// Arrange
+		mockAudioCache := new(MockAudioCaching)
+		mockCommandExecutor := new(MockCommandExecutor)
+		mockGauge := new(MockGaugeMetric)
+
+		fetcher := NewYoutubeFetcher(new(MockLogger), new(MockCacheManager), new(MockYouTubeService), mockAudioCache, mockCommandExecutor).WithProcessGauge(mockGauge)
+
+		ctx := context.Background()
+		song := &voice.Song{
+			URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		}
+
+		mockCommandExecutor.On("ExecuteCommand", ctx, "sh", mock.Anything).Return(exec.CommandContext(ctx, "echo", "fake audio data"))
+		mockAudioCache.On("Get", song.URL).Return(nil, false)
+		mockAudioCache.On("Set", song.URL, mock.Anything)
+		for _, process := range []string{"yt-dlp", "ffmpeg", "dca"} {
+			mockGauge.On("Inc", []string{process}).Return().Once()
+			mockGauge.On("Dec", []string{process}).Return().Once()
+		}
+
+		// Act
+		reader, err := fetcher.GetDCAData(ctx, song)
+		require.NoError(t, err)
+		_, err = io.Copy(io.Discard, reader)
+
+		// Assert
+		require.NoError(t, err)
+		mockGauge.AssertExpectations(t)
+	})
+
 	t.Run("Error executing command", func(t *testing.T) {
 		// Arrange
 		mockLogger := new(MockLogger)
@@ -311,6 +458,43 @@ func TestYoutubeFetcher_GetDCAData(t *testing.T) {
 	})
 }
 
+func TestYoutubeFetcher_GetDCADataFrom(t *testing.T) {
+	t.Run("Skips frames of the cached audio", func(t *testing.T) {
+		mockAudioCache := new(MockAudioCaching)
+		fetcher := NewYoutubeFetcher(new(MockLogger), new(MockCacheManager), new(MockYouTubeService), mockAudioCache, new(MockCommandExecutor))
+		song := &voice.Song{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}
+		// Tres frames de 20ms de un byte cada uno.
+		mockAudioCache.On("Get", song.URL).Return([]byte{1, 0, 'a', 1, 0, 'b', 1, 0, 'c'}, true)
+
+		reader, err := fetcher.GetDCADataFrom(context.Background(), song, 40*time.Millisecond)
+
+		require.NoError(t, err)
+		rest, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1, 0, 'c'}, rest)
+	})
+	t.Run("Encodes from the offset without caching", func(t *testing.T) {
+		mockAudioCache := new(MockAudioCaching)
+		mockCommandExecutor := new(MockCommandExecutor)
+		fetcher := NewYoutubeFetcher(new(MockLogger), new(MockCacheManager), new(MockYouTubeService), mockAudioCache, mockCommandExecutor)
+		ctx := context.Background()
+		song := &voice.Song{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"}
+		mockAudioCache.On("Get", song.URL).Return(nil, false)
+		mockCommandExecutor.On("ExecuteCommand", ctx, "sh", mock.MatchedBy(func(args []string) bool {
+			return len(args) == 2 && strings.Contains(args[1], "| ffmpeg -ss 83.000 -i pipe:0 ")
+		})).Return(exec.CommandContext(ctx, "echo", "fake audio data"))
+
+		reader, err := fetcher.GetDCADataFrom(ctx, song, 83*time.Second)
+
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "fake audio data\n", string(data))
+		mockCommandExecutor.AssertExpectations(t)
+		mockAudioCache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+	})
+}
+
 func TestYoutubeFetcher_SearchYouTubeVideoID(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		// Arrange
@@ -0,0 +1,92 @@
+package playlists
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"sync"
+)
+
+// FileStore implementa Store guardando todas las listas en un archivo JSON. Cada cambio reescribe el archivo
+// completo, lo que alcanza para la cantidad de listas que guarda un bot.
+type FileStore struct {
+	mu        sync.RWMutex
+	filepath  string
+	playlists map[string]Playlist
+}
+
+// NewFileStore crea una nueva instancia de FileStore y carga las listas del archivo, si existe.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{filepath: path, playlists: make(map[string]Playlist)}
+
+	var playlists []Playlist
+	if err := jsonfile.Load(path, &playlists); err != nil {
+		return nil, fmt.Errorf("error al cargar las listas: %w", err)
+	}
+	for _, playlist := range playlists {
+		s.playlists[playlistKey(playlist.GuildID, playlist.Name)] = playlist
+	}
+	return s, nil
+}
+
+func (s *FileStore) Put(playlist Playlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := playlistKey(playlist.GuildID, playlist.Name)
+	return jsonfile.Update(s.playlists, key, func() { s.playlists[key] = playlist }, s.persist)
+}
+
+func (s *FileStore) Get(guildID, name string) (Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	playlist, ok := s.playlists[playlistKey(guildID, name)]
+	if !ok {
+		return Playlist{}, ErrNotFound
+	}
+	return playlist, nil
+}
+
+func (s *FileStore) GetByCode(code string) (Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findByCode(s.playlists, code)
+}
+
+func (s *FileStore) List(guildID string) ([]Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listGuild(s.playlists, guildID), nil
+}
+
+func (s *FileStore) ListPublic(limit int) ([]Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listPublic(s.playlists, limit), nil
+}
+
+func (s *FileStore) Delete(guildID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := playlistKey(guildID, name)
+	if _, ok := s.playlists[key]; !ok {
+		return ErrNotFound
+	}
+	return jsonfile.Update(s.playlists, key, func() { delete(s.playlists, key) }, s.persist)
+}
+
+// persist guarda las listas en el archivo. Se llama con mu tomado.
+func (s *FileStore) persist() error {
+	playlists := make([]Playlist, 0, len(s.playlists))
+	for _, playlist := range s.playlists {
+		playlists = append(playlists, playlist)
+	}
+	if err := jsonfile.Save(s.filepath, playlists); err != nil {
+		return fmt.Errorf("error al guardar las listas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package playlists
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_PersistsPlaylists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists", "playlists.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	playlist := Playlist{
+		Name:       "Fiesta",
+		GuildID:    "guild-1",
+		OwnerID:    "user-1",
+		Code:       "ABCD2345",
+		Visibility: VisibilityPublic,
+		Songs:      testSongs,
+		CreatedAt:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.Put(playlist))
+	require.NoError(t, store.Put(Playlist{Name: "Borrar", GuildID: "guild-1", Code: "ZZZZ2345", Songs: testSongs}))
+	require.NoError(t, store.Delete("guild-1", "borrar"))
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	got, err := reopened.Get("guild-1", "FIESTA")
+	require.NoError(t, err)
+	assert.Equal(t, playlist, got)
+
+	got, err = reopened.GetByCode("ABCD2345")
+	require.NoError(t, err)
+	assert.Equal(t, "Fiesta", got.Name)
+
+	list, err := reopened.List("guild-1")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	_, err = reopened.Get("guild-1", "Borrar")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, reopened.Delete("guild-1", "Borrar"), ErrNotFound)
+}
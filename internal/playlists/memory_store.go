@@ -0,0 +1,112 @@
+package playlists
+
+import (
+	"sort"
+	"sync"
+)
+
+// InMemoryStore implementa Store guardando las listas en memoria.
+type InMemoryStore struct {
+	mu        sync.RWMutex
+	playlists map[string]Playlist
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{playlists: make(map[string]Playlist)}
+}
+
+func (s *InMemoryStore) Put(playlist Playlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.playlists[playlistKey(playlist.GuildID, playlist.Name)] = playlist
+	return nil
+}
+
+func (s *InMemoryStore) Get(guildID, name string) (Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	playlist, ok := s.playlists[playlistKey(guildID, name)]
+	if !ok {
+		return Playlist{}, ErrNotFound
+	}
+	return playlist, nil
+}
+
+func (s *InMemoryStore) GetByCode(code string) (Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findByCode(s.playlists, code)
+}
+
+func (s *InMemoryStore) List(guildID string) ([]Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listGuild(s.playlists, guildID), nil
+}
+
+func (s *InMemoryStore) ListPublic(limit int) ([]Playlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listPublic(s.playlists, limit), nil
+}
+
+func (s *InMemoryStore) Delete(guildID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := playlistKey(guildID, name)
+	if _, ok := s.playlists[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.playlists, key)
+	return nil
+}
+
+// playlistKey es la clave de una lista en los almacenamientos: el nombre es único por servidor.
+func playlistKey(guildID, name string) string {
+	return guildID + "/" + normalizeName(name)
+}
+
+func findByCode(playlists map[string]Playlist, code string) (Playlist, error) {
+	for _, playlist := range playlists {
+		if playlist.Code == code {
+			return playlist, nil
+		}
+	}
+	return Playlist{}, ErrNotFound
+}
+
+func listGuild(playlists map[string]Playlist, guildID string) []Playlist {
+	result := make([]Playlist, 0)
+	for _, playlist := range playlists {
+		if playlist.GuildID == guildID {
+			result = append(result, playlist)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return normalizeName(result[i].Name) < normalizeName(result[j].Name)
+	})
+	return result
+}
+
+func listPublic(playlists map[string]Playlist, limit int) []Playlist {
+	result := make([]Playlist, 0)
+	for _, playlist := range playlists {
+		if playlist.Visibility == VisibilityPublic {
+			result = append(result, playlist)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
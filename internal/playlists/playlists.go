@@ -0,0 +1,166 @@
+package playlists
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"strings"
+	"time"
+)
+
+// Visibility indica quién puede importar una lista guardada con su código.
+type Visibility string
+
+const (
+	// VisibilityPrivate permite importar la lista solo a quien la creó, en cualquier servidor.
+	VisibilityPrivate Visibility = "private"
+	// VisibilityUnlisted permite importar la lista a cualquiera que tenga el código.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPublic además muestra la lista en el listado de listas públicas.
+	VisibilityPublic Visibility = "public"
+)
+
+// codeAlphabet son los caracteres de los códigos para compartir. No incluye los que se confunden entre sí (0/O, 1/I).
+const (
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+)
+
+var (
+	// ErrNotFound indica que la lista no existe.
+	ErrNotFound = errors.New("la lista no existe")
+	// ErrForbidden indica que el usuario no puede modificar o importar la lista.
+	ErrForbidden = errors.New("no tenés permiso sobre la lista")
+	// ErrNameTaken indica que en el servidor ya hay una lista con ese nombre creada por otro usuario.
+	ErrNameTaken = errors.New("ya existe una lista con ese nombre")
+	// ErrInvalidVisibility indica que la visibilidad no es una de las soportadas.
+	ErrInvalidVisibility = errors.New("visibilidad inválida")
+	// ErrEmpty indica que se intentó guardar una lista sin canciones.
+	ErrEmpty = errors.New("la lista no tiene canciones")
+)
+
+type (
+	// Song es una canción de una lista guardada.
+	Song struct {
+		Type         string `json:"type,omitempty"`
+		Title        string `json:"title"`
+		URL          string `json:"url"`
+		ThumbnailURL string `json:"thumbnail_url,omitempty"`
+		DurationMs   int64  `json:"duration_ms,omitempty"`
+		Uploader     string `json:"uploader,omitempty"`
+		UploaderID   string `json:"uploader_id,omitempty"`
+		Explicit     bool   `json:"explicit,omitempty"`
+	}
+
+	// Playlist es una lista de canciones guardada en un servidor. El nombre es único por servidor sin distinguir
+	// mayúsculas; el código es único entre todos los servidores.
+	Playlist struct {
+		Name       string     `json:"name"`
+		GuildID    string     `json:"guild_id"`
+		OwnerID    string     `json:"owner_id"`
+		Code       string     `json:"code"`
+		Visibility Visibility `json:"visibility"`
+		Songs      []Song     `json:"songs"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+	}
+
+	// Store guarda las listas de todos los servidores.
+	Store interface {
+		// Put crea o reemplaza la lista con el mismo servidor y nombre.
+		Put(playlist Playlist) error
+		// Get devuelve la lista del servidor con ese nombre, o ErrNotFound.
+		Get(guildID, name string) (Playlist, error)
+		// GetByCode devuelve la lista con ese código, o ErrNotFound.
+		GetByCode(code string) (Playlist, error)
+		// List devuelve las listas del servidor ordenadas por nombre.
+		List(guildID string) ([]Playlist, error)
+		// ListPublic devuelve las listas públicas de todos los servidores, de la más reciente a la más vieja.
+		ListPublic(limit int) ([]Playlist, error)
+		// Delete elimina la lista del servidor con ese nombre, o devuelve ErrNotFound.
+		Delete(guildID, name string) error
+	}
+)
+
+// ParseVisibility valida la visibilidad pedida.
+func ParseVisibility(value string) (Visibility, error) {
+	switch v := Visibility(value); v {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+		return v, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidVisibility, value)
+	}
+}
+
+// CanImport indica si el usuario puede importar la lista con su código.
+func (p Playlist) CanImport(userID string) bool {
+	return p.Visibility != VisibilityPrivate || p.OwnerID == userID
+}
+
+// VoiceSongs devuelve las canciones de la lista listas para encolar, a nombre de requestedBy.
+func (p Playlist) VoiceSongs(requestedBy string) []*voice.Song {
+	songs := make([]*voice.Song, 0, len(p.Songs))
+	for _, song := range p.Songs {
+		voiceSong := &voice.Song{
+			Type:        song.Type,
+			Title:       song.Title,
+			URL:         song.URL,
+			Playable:    true,
+			Duration:    time.Duration(song.DurationMs) * time.Millisecond,
+			RequestedBy: &requestedBy,
+			Uploader:    song.Uploader,
+			UploaderID:  song.UploaderID,
+			Explicit:    song.Explicit,
+		}
+		if song.ThumbnailURL != "" {
+			thumbnailURL := song.ThumbnailURL
+			voiceSong.ThumbnailURL = &thumbnailURL
+		}
+		songs = append(songs, voiceSong)
+	}
+	return songs
+}
+
+// FromVoiceSongs convierte las canciones de la lista de reproducción en canciones de una lista guardada.
+func FromVoiceSongs(songs []*voice.Song) []Song {
+	saved := make([]Song, 0, len(songs))
+	for _, song := range songs {
+		entry := Song{
+			Type:       song.Type,
+			Title:      song.Title,
+			URL:        song.URL,
+			DurationMs: song.Duration.Milliseconds(),
+			Uploader:   song.Uploader,
+			UploaderID: song.UploaderID,
+			Explicit:   song.Explicit,
+		}
+		if song.ThumbnailURL != nil {
+			entry.ThumbnailURL = *song.ThumbnailURL
+		}
+		saved = append(saved, entry)
+	}
+	return saved
+}
+
+// normalizeName es la clave con la que se comparan los nombres de las listas.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeCode devuelve el código como se guarda, para aceptar lo que escriba el usuario en minúsculas o con espacios.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// newCode genera un código aleatorio para compartir una lista.
+func newCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error al generar el código de la lista: %w", err)
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b), nil
+}
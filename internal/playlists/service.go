@@ -0,0 +1,161 @@
+package playlists
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxCodeAttempts es la cantidad de veces que se genera un código nuevo si el anterior ya estaba en uso.
+const maxCodeAttempts = 5
+
+// Service administra las listas guardadas de los servidores y las comparte entre servidores con códigos.
+type Service struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewService crea un Service sobre el almacenamiento indicado.
+func NewService(store Store) *Service {
+	return &Service{store: store, now: time.Now}
+}
+
+// Save guarda las canciones con el nombre indicado en el servidor. Si la lista ya existe y es del usuario, se
+// reemplazan sus canciones y se conserva el código; si es de otro usuario, devuelve ErrNameTaken. Con visibility
+// vacía, las listas nuevas son privadas y las existentes conservan la suya.
+func (s *Service) Save(guildID, ownerID, name string, songs []Song, visibility Visibility) (Playlist, error) {
+	if len(songs) == 0 {
+		return Playlist{}, ErrEmpty
+	}
+	now := s.now()
+	playlist, err := s.store.Get(guildID, name)
+	switch {
+	case err == nil:
+		if playlist.OwnerID != ownerID {
+			return Playlist{}, ErrNameTaken
+		}
+	case errors.Is(err, ErrNotFound):
+		code, err := s.uniqueCode()
+		if err != nil {
+			return Playlist{}, err
+		}
+		playlist = Playlist{Name: name, GuildID: guildID, OwnerID: ownerID, Code: code, Visibility: VisibilityPrivate, CreatedAt: now}
+	default:
+		return Playlist{}, err
+	}
+
+	if visibility != "" {
+		playlist.Visibility = visibility
+	}
+	playlist.Songs = songs
+	playlist.UpdatedAt = now
+	if err := s.store.Put(playlist); err != nil {
+		return Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// Append agrega canciones al final de la lista del servidor con ese nombre, sin importar quién la creó. Si la lista
+// no existe, la crea como una lista privada de ownerID.
+func (s *Service) Append(guildID, ownerID, name string, songs []Song) (Playlist, error) {
+	playlist, err := s.store.Get(guildID, name)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNotFound):
+		return s.Save(guildID, ownerID, name, songs, VisibilityPrivate)
+	default:
+		return Playlist{}, err
+	}
+	if len(songs) == 0 {
+		return playlist, nil
+	}
+
+	playlist.Songs = append(playlist.Songs, songs...)
+	playlist.UpdatedAt = s.now()
+	if err := s.store.Put(playlist); err != nil {
+		return Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// Share cambia la visibilidad de una lista del servidor y la devuelve con su código. Solo puede hacerlo quien la
+// creó.
+func (s *Service) Share(guildID, userID, name string, visibility Visibility) (Playlist, error) {
+	playlist, err := s.store.Get(guildID, name)
+	if err != nil {
+		return Playlist{}, err
+	}
+	if playlist.OwnerID != userID {
+		return Playlist{}, ErrForbidden
+	}
+	playlist.Visibility = visibility
+	playlist.UpdatedAt = s.now()
+	if err := s.store.Put(playlist); err != nil {
+		return Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// Import copia en el servidor la lista con el código indicado, como una lista privada del usuario. Si name está
+// vacío, se usa el nombre de la lista original.
+func (s *Service) Import(code, guildID, userID, name string) (Playlist, error) {
+	source, err := s.store.GetByCode(NormalizeCode(code))
+	if err != nil {
+		return Playlist{}, err
+	}
+	if !source.CanImport(userID) {
+		return Playlist{}, ErrForbidden
+	}
+	if name == "" {
+		name = source.Name
+	}
+	if source.GuildID == guildID && normalizeName(source.Name) == normalizeName(name) {
+		// Importar una lista en su mismo servidor y con su mismo nombre no crea nada nuevo.
+		return source, nil
+	}
+	return s.Save(guildID, userID, name, source.Songs, VisibilityPrivate)
+}
+
+// Get devuelve la lista del servidor con ese nombre.
+func (s *Service) Get(guildID, name string) (Playlist, error) {
+	return s.store.Get(guildID, name)
+}
+
+// List devuelve las listas guardadas en el servidor, ordenadas por nombre.
+func (s *Service) List(guildID string) ([]Playlist, error) {
+	return s.store.List(guildID)
+}
+
+// Delete borra la lista del servidor con ese nombre. Solo puede hacerlo quien la creó, salvo que admin indique que
+// el usuario administra el servidor.
+func (s *Service) Delete(guildID, userID, name string, admin bool) error {
+	playlist, err := s.store.Get(guildID, name)
+	if err != nil {
+		return err
+	}
+	if playlist.OwnerID != userID && !admin {
+		return ErrForbidden
+	}
+	return s.store.Delete(guildID, playlist.Name)
+}
+
+// Public devuelve las listas públicas más recientes.
+func (s *Service) Public(limit int) ([]Playlist, error) {
+	return s.store.ListPublic(limit)
+}
+
+// uniqueCode genera un código que no use otra lista.
+func (s *Service) uniqueCode() (string, error) {
+	for i := 0; i < maxCodeAttempts; i++ {
+		code, err := newCode()
+		if err != nil {
+			return "", err
+		}
+		if _, err := s.store.GetByCode(code); errors.Is(err, ErrNotFound) {
+			return code, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no se pudo generar un código libre en %d intentos", maxCodeAttempts)
+}
@@ -0,0 +1,149 @@
+package playlists
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSongs = []Song{
+	{Title: "La Bamba", URL: "https://youtube.com/watch?v=1", DurationMs: 185000},
+	{Title: "Cielito lindo", URL: "https://youtube.com/watch?v=2"},
+}
+
+func newTestService() *Service {
+	service := NewService(NewInMemoryStore())
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return service
+}
+
+func TestService_Save(t *testing.T) {
+	service := newTestService()
+
+	created, err := service.Save("guild-1", "user-1", "Fiesta", testSongs, "")
+	require.NoError(t, err)
+	assert.Equal(t, VisibilityPrivate, created.Visibility)
+	assert.Len(t, created.Code, codeLength)
+
+	updated, err := service.Save("guild-1", "user-1", "fiesta", testSongs[:1], VisibilityUnlisted)
+	require.NoError(t, err)
+	assert.Equal(t, created.Code, updated.Code)
+	assert.Equal(t, "Fiesta", updated.Name)
+	assert.Equal(t, VisibilityUnlisted, updated.Visibility)
+	assert.Len(t, updated.Songs, 1)
+
+	_, err = service.Save("guild-1", "user-2", "Fiesta", testSongs, "")
+	assert.ErrorIs(t, err, ErrNameTaken)
+
+	_, err = service.Save("guild-1", "user-1", "Vacía", nil, "")
+	assert.ErrorIs(t, err, ErrEmpty)
+}
+
+func TestService_Append(t *testing.T) {
+	service := newTestService()
+
+	created, err := service.Append("guild-1", "user-1", "Novedades", testSongs[:1])
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", created.OwnerID)
+	assert.Equal(t, VisibilityPrivate, created.Visibility)
+
+	appended, err := service.Append("guild-1", "user-2", "novedades", testSongs[1:])
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", appended.OwnerID, "agregar canciones no cambia quién creó la lista")
+	assert.Equal(t, created.Code, appended.Code)
+	require.Len(t, appended.Songs, 2)
+	assert.Equal(t, "Cielito lindo", appended.Songs[1].Title)
+}
+
+func TestService_Share(t *testing.T) {
+	service := newTestService()
+	_, err := service.Save("guild-1", "user-1", "Fiesta", testSongs, "")
+	require.NoError(t, err)
+
+	_, err = service.Share("guild-1", "user-2", "Fiesta", VisibilityPublic)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	shared, err := service.Share("guild-1", "user-1", "Fiesta", VisibilityPublic)
+	require.NoError(t, err)
+	assert.Equal(t, VisibilityPublic, shared.Visibility)
+
+	public, err := service.Public(10)
+	require.NoError(t, err)
+	assert.Len(t, public, 1)
+
+	_, err = service.Share("guild-1", "user-1", "Otra", VisibilityPublic)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_Delete(t *testing.T) {
+	service := newTestService()
+	_, err := service.Save("guild-1", "user-1", "Fiesta", testSongs, "")
+	require.NoError(t, err)
+	_, err = service.Save("guild-1", "user-1", "Asado", testSongs, "")
+	require.NoError(t, err)
+
+	list, err := service.List("guild-1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Asado", list[0].Name)
+
+	assert.ErrorIs(t, service.Delete("guild-1", "user-2", "Fiesta", false), ErrForbidden)
+	require.NoError(t, service.Delete("guild-1", "user-1", "fiesta", false))
+	require.NoError(t, service.Delete("guild-1", "user-2", "Asado", true), "un administrador puede borrar listas ajenas")
+	assert.ErrorIs(t, service.Delete("guild-1", "user-1", "Fiesta", false), ErrNotFound)
+
+	list, err = service.List("guild-1")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestService_Import(t *testing.T) {
+	service := newTestService()
+	private, err := service.Save("guild-1", "user-1", "Fiesta", testSongs, VisibilityPrivate)
+	require.NoError(t, err)
+
+	t.Run("otro usuario no puede importar una lista privada", func(t *testing.T) {
+		_, err := service.Import(private.Code, "guild-2", "user-2", "")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("el dueño puede importar su lista privada en otro servidor", func(t *testing.T) {
+		imported, err := service.Import(private.Code, "guild-2", "user-1", "")
+		require.NoError(t, err)
+		assert.Equal(t, "guild-2", imported.GuildID)
+		assert.Equal(t, "Fiesta", imported.Name)
+		assert.NotEqual(t, private.Code, imported.Code)
+		assert.Equal(t, testSongs, imported.Songs)
+	})
+
+	t.Run("cualquiera puede importar una lista no listada con el código", func(t *testing.T) {
+		_, err := service.Share("guild-1", "user-1", "Fiesta", VisibilityUnlisted)
+		require.NoError(t, err)
+
+		imported, err := service.Import(" "+strings.ToLower(private.Code)+" ", "guild-3", "user-3", "Copia")
+		require.NoError(t, err)
+		assert.Equal(t, "user-3", imported.OwnerID)
+		assert.Equal(t, "Copia", imported.Name)
+		assert.Equal(t, VisibilityPrivate, imported.Visibility)
+	})
+
+	t.Run("código inexistente", func(t *testing.T) {
+		_, err := service.Import("NOEXISTE", "guild-2", "user-1", "")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestParseVisibility(t *testing.T) {
+	visibility, err := ParseVisibility("unlisted")
+	assert.NoError(t, err)
+	assert.Equal(t, VisibilityUnlisted, visibility)
+
+	_, err = ParseVisibility("secreta")
+	assert.ErrorIs(t, err, ErrInvalidVisibility)
+}
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// eventBuffer es el búfer de eventos de cada plugin; si un plugin no los consume a tiempo, se descartan.
+const eventBuffer = 64
+
+// EventSubscriber permite suscribirse a los eventos de reproducción.
+type EventSubscriber interface {
+	Subscribe(buffer int) (<-chan events.Event, func())
+}
+
+// Manager carga los plugins habilitados y conecta sus comandos, fuentes y suscripciones con el bot.
+type Manager struct {
+	logger logging.Logger
+
+	mu      sync.RWMutex
+	plugins map[string]Plugin
+	sources []Source
+}
+
+// NewManager crea un Manager sin plugins cargados.
+func NewManager(logger logging.Logger) *Manager {
+	return &Manager{logger: logger, plugins: make(map[string]Plugin)}
+}
+
+// Load crea los plugins indicados y registra sus comandos y fuentes. Falla si alguno no está registrado o no
+// se puede crear, para no arrancar con una configuración distinta a la pedida.
+func (m *Manager) Load(names []string, host Host, commands CommandRegistry) error {
+	for _, name := range names {
+		f, ok := factory(name)
+		if !ok {
+			return fmt.Errorf("el plugin %s no existe; los disponibles son %v", name, Available())
+		}
+		m.mu.RLock()
+		_, loaded := m.plugins[name]
+		m.mu.RUnlock()
+		if loaded {
+			continue
+		}
+
+		p, err := f(Host{Controller: host.Controller, Logger: logging.WithFields(host.Logger, zap.String("plugin", name))})
+		if err != nil {
+			return fmt.Errorf("error al crear el plugin %s: %w", name, err)
+		}
+		p.RegisterCommands(commands)
+		p.RegisterSources(m)
+
+		m.mu.Lock()
+		m.plugins[name] = p
+		m.mu.Unlock()
+		m.logger.Info("Plugin cargado", zap.String("plugin", name))
+	}
+	return nil
+}
+
+// AddSource registra una fuente de canciones. Las fuentes se consultan en el orden en que se registraron.
+func (m *Manager) AddSource(source Source) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = append(m.sources, source)
+}
+
+// Run entrega los eventos de reproducción a cada plugin hasta que se cancele el contexto.
+func (m *Manager) Run(ctx context.Context, subscriber EventSubscriber) {
+	m.mu.RLock()
+	plugins := make(map[string]Plugin, len(m.plugins))
+	for name, p := range m.plugins {
+		plugins[name] = p
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for name, p := range plugins {
+		ch, cancel := subscriber.Subscribe(eventBuffer)
+		wg.Add(1)
+		go func(name string, p Plugin) {
+			defer wg.Done()
+			defer cancel()
+			defer func() {
+				if r := recover(); r != nil {
+					m.logger.Error("Panic en la suscripción del plugin", zap.String("plugin", name), zap.Any("panic", r))
+				}
+			}()
+			p.Subscribe(ctx, ch)
+		}(name, p)
+		go func() {
+			<-ctx.Done()
+			cancel()
+		}()
+	}
+	wg.Wait()
+}
+
+// SongLooker envuelve el buscador de canciones del bot para que lo que pide el usuario lo resuelvan primero las
+// fuentes de los plugins. Si ninguna fuente lo reconoce, la búsqueda sigue en next.
+func (m *Manager) SongLooker(next fetcher.SongLooker) fetcher.SongLooker {
+	return &sourceLooker{manager: m, next: next}
+}
+
+// source devuelve la primera fuente que reconoce lo pedido.
+func (m *Manager) source(input string) (Source, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, source := range m.sources {
+		if source.Matches(input) {
+			return source, true
+		}
+	}
+	return nil, false
+}
+
+// sourceLooker implementa fetcher.SongLooker con las fuentes de los plugins.
+type sourceLooker struct {
+	manager *Manager
+	next    fetcher.SongLooker
+}
+
+// SearchYouTubeVideoID devuelve lo pedido sin cambios si lo reconoce una fuente, para que LookupSongs lo
+// resuelva con esa misma fuente.
+func (l *sourceLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	if _, ok := l.manager.source(searchTerm); ok {
+		return searchTerm, nil
+	}
+	return l.next.SearchYouTubeVideoID(ctx, searchTerm)
+}
+
+// SearchYouTubeVideoIDs delega la búsqueda de varios resultados en next, si la soporta.
+func (l *sourceLooker) SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	searcher, ok := l.next.(fetcher.SongSearcher)
+	if !ok {
+		return nil, fetcher.ErrSearchNotSupported
+	}
+	return searcher.SearchYouTubeVideoIDs(ctx, searchTerm, limit)
+}
+
+func (l *sourceLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	if source, ok := l.manager.source(input); ok {
+		return source.Lookup(ctx, input)
+	}
+	return l.next.LookupSongs(ctx, input)
+}
@@ -0,0 +1,145 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSource struct {
+	prefix string
+	songs  []*voice.Song
+}
+
+func (s *testSource) Matches(input string) bool {
+	return strings.HasPrefix(input, s.prefix)
+}
+
+func (s *testSource) Lookup(context.Context, string) ([]*voice.Song, error) {
+	return s.songs, nil
+}
+
+type testPlugin struct {
+	Base
+	source *testSource
+	events chan events.Event
+}
+
+func (p *testPlugin) RegisterCommands(commands CommandRegistry) {
+	commands.AddCommand(Command{Name: "trivia", Description: "Jugar a adivinar la canción"})
+}
+
+func (p *testPlugin) RegisterSources(sources SourceRegistry) {
+	sources.AddSource(p.source)
+}
+
+func (p *testPlugin) Subscribe(ctx context.Context, ch <-chan events.Event) {
+	for event := range ch {
+		select {
+		case p.events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// register registra el plugin solo durante el test.
+func register(t *testing.T, name string, factory Factory) {
+	Register(name, factory)
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		delete(factories, name)
+		factoriesMu.Unlock()
+	})
+}
+
+func TestManager_Load(t *testing.T) {
+	p := &testPlugin{source: &testSource{prefix: "test:"}}
+	register(t, "test-load", func(Host) (Plugin, error) { return p, nil })
+	register(t, "test-load-failing", func(Host) (Plugin, error) { return nil, errors.New("falla") })
+	logger := new(MockLogger)
+	logger.On("Info", "Plugin cargado", mock.Anything).Return()
+	commands := new(MockCommandRegistry)
+	commands.On("AddCommand", mock.MatchedBy(func(cmd Command) bool { return cmd.Name == "trivia" })).Return().Once()
+	manager := NewManager(logger)
+
+	assert.NoError(t, manager.Load([]string{"test-load", "test-load"}, Host{Logger: logger}, commands))
+	commands.AssertExpectations(t)
+	_, ok := manager.source("test:algo")
+	assert.True(t, ok)
+
+	assert.ErrorContains(t, manager.Load([]string{"no-existe"}, Host{Logger: logger}, commands), "no-existe")
+	assert.ErrorContains(t, manager.Load([]string{"test-load-failing"}, Host{Logger: logger}, commands), "falla")
+}
+
+func TestRegister_PanicsOnDuplicate(t *testing.T) {
+	register(t, "test-duplicate", func(Host) (Plugin, error) { return Base{}, nil })
+	assert.Panics(t, func() {
+		register(t, "test-duplicate", func(Host) (Plugin, error) { return Base{}, nil })
+	})
+	assert.Contains(t, Available(), "test-duplicate")
+}
+
+func TestManager_SongLooker(t *testing.T) {
+	ctx := context.Background()
+	manager := NewManager(new(MockLogger))
+	song := &voice.Song{Title: "Plugin", URL: "https://example.com/1"}
+	manager.AddSource(&testSource{prefix: "test:", songs: []*voice.Song{song}})
+	next := new(MockSongLooker)
+	next.On("SearchYouTubeVideoID", ctx, "cancion").Return("abc", nil)
+	next.On("LookupSongs", ctx, "https://youtube.com/watch?v=abc").Return([]*voice.Song{{Title: "YouTube"}}, nil)
+	looker := manager.SongLooker(next)
+
+	id, err := looker.SearchYouTubeVideoID(ctx, "test:1")
+	assert.NoError(t, err)
+	assert.Equal(t, "test:1", id)
+	songs, err := looker.LookupSongs(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []*voice.Song{song}, songs)
+
+	id, err = looker.SearchYouTubeVideoID(ctx, "cancion")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", id)
+	songs, err = looker.LookupSongs(ctx, "https://youtube.com/watch?v=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "YouTube", songs[0].Title)
+	next.AssertExpectations(t)
+}
+
+func TestManager_Run(t *testing.T) {
+	p := &testPlugin{source: &testSource{prefix: "run:"}, events: make(chan events.Event, 1)}
+	register(t, "test-run", func(Host) (Plugin, error) { return p, nil })
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	commands := new(MockCommandRegistry)
+	commands.On("AddCommand", mock.Anything).Return()
+	manager := NewManager(logger)
+	assert.NoError(t, manager.Load([]string{"test-run"}, Host{Logger: logger}, commands))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := events.NewBus()
+	done := make(chan struct{})
+	go func() {
+		manager.Run(ctx, bus)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		bus.Publish(events.Event{Type: events.TypeTrackStarted, GuildID: "1"})
+		select {
+		case event := <-p.events:
+			return event.GuildID == "1"
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
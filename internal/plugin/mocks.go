@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockSongLooker struct {
+	mock.Mock
+}
+
+func (m *MockSongLooker) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).([]*voice.Song), args.Error(1)
+}
+
+func (m *MockSongLooker) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	args := m.Called(ctx, searchTerm)
+	return args.String(0), args.Error(1)
+}
+
+type MockCommandRegistry struct {
+	mock.Mock
+}
+
+func (m *MockCommandRegistry) AddCommand(cmd Command) {
+	m.Called(cmd)
+}
+
+func (m *MockCommandRegistry) AddComponent(customID string, handler ComponentHandler) {
+	m.Called(customID, handler)
+}
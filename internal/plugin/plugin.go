@@ -0,0 +1,114 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"sort"
+	"sync"
+)
+
+type (
+	// Plugin es una funcionalidad opcional (juegos, fuentes de canciones propias, integraciones) que se carga al
+	// iniciar el bot sin modificar el manejador de interacciones.
+	Plugin interface {
+		// RegisterCommands registra los subcomandos y componentes del plugin.
+		RegisterCommands(commands CommandRegistry)
+		// RegisterSources registra las fuentes de canciones del plugin.
+		RegisterSources(sources SourceRegistry)
+		// Subscribe recibe los eventos de reproducción de todos los servidores hasta que se cierre el canal. Si el
+		// plugin no necesita eventos, puede volver enseguida.
+		Subscribe(ctx context.Context, events <-chan events.Event)
+	}
+
+	// CommandHandler maneja un subcomando del comando del bot.
+	CommandHandler func(s *discordgo.Session, ic *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption)
+
+	// ComponentHandler maneja la interacción con un componente (botón, menú) de un mensaje.
+	ComponentHandler func(s *discordgo.Session, ic *discordgo.InteractionCreate)
+
+	// Command es un subcomando del comando del bot, por ejemplo /butakero trivia.
+	Command struct {
+		Name        string
+		Description string
+		Options     []*discordgo.ApplicationCommandOption
+		Handler     CommandHandler
+	}
+
+	// CommandRegistry registra los subcomandos y componentes de los plugins.
+	CommandRegistry interface {
+		AddCommand(cmd Command)
+		AddComponent(customID string, handler ComponentHandler)
+	}
+
+	// Source es una fuente de canciones. El audio lo descarga yt-dlp, así que las URLs de las canciones tienen
+	// que ser de un sitio que soporte.
+	Source interface {
+		// Matches indica si la fuente resuelve lo que pidió el usuario, normalmente una URL de su sitio.
+		Matches(input string) bool
+		// Lookup devuelve las canciones de lo que pidió el usuario.
+		Lookup(ctx context.Context, input string) ([]*voice.Song, error)
+	}
+
+	// SourceRegistry registra las fuentes de canciones de los plugins.
+	SourceRegistry interface {
+		AddSource(source Source)
+	}
+
+	// Host son los servicios del bot disponibles para los plugins.
+	Host struct {
+		Controller control.Controller
+		Logger     logging.Logger
+	}
+
+	// Factory crea una instancia del plugin.
+	Factory func(host Host) (Plugin, error)
+)
+
+// Base implementa Plugin sin hacer nada, para que los plugins solo implementen los métodos que necesitan.
+type Base struct{}
+
+func (Base) RegisterCommands(CommandRegistry) {}
+
+func (Base) RegisterSources(SourceRegistry) {}
+
+func (Base) Subscribe(context.Context, <-chan events.Event) {}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register deja disponible un plugin con el nombre indicado. Se llama desde el init del paquete del plugin, que
+// se importa en main; el plugin se carga solo si su nombre está en PLUGINS_ENABLED.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("el plugin %s ya está registrado", name))
+	}
+	factories[name] = factory
+}
+
+// Available devuelve los nombres de los plugins registrados, ordenados.
+func Available() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func factory(name string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	f, ok := factories[name]
+	return f, ok
+}
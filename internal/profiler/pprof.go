@@ -0,0 +1,67 @@
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// PprofServer expone los endpoints de net/http/pprof para perfilar CPU y memoria en instancias en vivo.
+// Solo escucha en interfaces de loopback para no exponer los perfiles fuera del host.
+type PprofServer struct {
+	server *http.Server
+}
+
+// NewPprofServer crea un nuevo PprofServer. Devuelve un error si la dirección no es de loopback.
+func NewPprofServer(address string) (*PprofServer, error) {
+	if err := validateLoopback(address); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return &PprofServer{
+		server: &http.Server{
+			Addr:              address,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}, nil
+}
+
+// Start inicia el servidor de pprof. Bloquea hasta que el servidor se cierra.
+func (s *PprofServer) Start() error {
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close detiene el servidor de pprof.
+func (s *PprofServer) Close() error {
+	return s.server.Close()
+}
+
+// validateLoopback verifica que la dirección escuche solo en localhost.
+func validateLoopback(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("dirección de pprof inválida %q: %w", address, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("la dirección de pprof debe ser de loopback (localhost, 127.0.0.1 o ::1): %q", address)
+	}
+	return nil
+}
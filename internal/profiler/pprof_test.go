@@ -0,0 +1,18 @@
+package profiler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewPprofServer(t *testing.T) {
+	for _, address := range []string{"localhost:6060", "127.0.0.1:6060", "[::1]:6060"} {
+		_, err := NewPprofServer(address)
+		assert.NoError(t, err, address)
+	}
+
+	for _, address := range []string{":6060", "0.0.0.0:6060", "10.0.0.5:6060", "localhost"} {
+		_, err := NewPprofServer(address)
+		assert.Error(t, err, address)
+	}
+}
@@ -0,0 +1,49 @@
+package radio
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockSongSearcher struct {
+	mock.Mock
+}
+
+func (m *MockSongSearcher) LookupSongs(ctx context.Context, input string) ([]*voice.Song, error) {
+	ret := m.Called(input)
+	songs, _ := ret.Get(0).([]*voice.Song)
+	return songs, ret.Error(1)
+}
+
+func (m *MockSongSearcher) SearchYouTubeVideoID(ctx context.Context, searchTerm string) (string, error) {
+	ret := m.Called(searchTerm)
+	return ret.String(0), ret.Error(1)
+}
+
+func (m *MockSongSearcher) SearchYouTubeVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	ret := m.Called(searchTerm, limit)
+	videoIDs, _ := ret.Get(0).([]string)
+	return videoIDs, ret.Error(1)
+}
@@ -0,0 +1,129 @@
+// Package radio arma estaciones de radio por género: canciones sin fin de un género elegido, para ir llenando la
+// lista de reproducción.
+package radio
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"go.uber.org/zap"
+	"math/rand"
+	"time"
+)
+
+const (
+	// resultsPerQuery es la cantidad de resultados que se piden por cada búsqueda del género.
+	resultsPerQuery = 15
+	// maxDuration descarta los videos largos, que suelen ser recopilados o mixes.
+	maxDuration = 10 * time.Minute
+)
+
+// ErrNoTracks indica que no se encontró ninguna canción nueva del género.
+var ErrNoTracks = errors.New("no se encontraron canciones del género")
+
+// Genre es un género de la radio, con las búsquedas de las que salen sus canciones.
+type Genre struct {
+	ID      string
+	Name    string
+	Queries []string
+}
+
+// Genres son los géneros que se pueden elegir en la radio, curados a mano.
+var Genres = []Genre{
+	{ID: "rock-nacional", Name: "Rock nacional", Queries: []string{"rock nacional argentino clásicos", "Soda Stereo", "Charly García", "Los Redondos", "Spinetta", "Divididos", "Los Fabulosos Cadillacs"}},
+	{ID: "rock", Name: "Rock", Queries: []string{"classic rock hits", "Queen", "The Rolling Stones", "AC/DC", "Led Zeppelin", "Nirvana", "Foo Fighters"}},
+	{ID: "pop", Name: "Pop", Queries: []string{"pop hits", "Dua Lipa", "Taylor Swift", "The Weeknd", "Harry Styles", "Ed Sheeran"}},
+	{ID: "cumbia", Name: "Cumbia", Queries: []string{"cumbia argentina", "Los Palmeras", "Damas Gratis", "Los Ángeles Azules", "La Nueva Luna", "Gilda"}},
+	{ID: "cuarteto", Name: "Cuarteto", Queries: []string{"cuarteto cordobés", "La Mona Jiménez", "Rodrigo", "Ulises Bueno", "La K'onga"}},
+	{ID: "reggaeton", Name: "Reggaetón", Queries: []string{"reggaeton hits", "Bad Bunny", "Daddy Yankee", "J Balvin", "Karol G", "Bizarrap music sessions"}},
+	{ID: "trap", Name: "Trap y hip hop", Queries: []string{"trap argentino", "Duki", "Paulo Londra", "Trueno", "Kendrick Lamar", "Eminem"}},
+	{ID: "electronica", Name: "Electrónica", Queries: []string{"electronic dance music", "Daft Punk", "Avicii", "Calvin Harris", "deep house", "techno"}},
+	{ID: "folklore", Name: "Folklore", Queries: []string{"folklore argentino", "Mercedes Sosa", "Los Chalchaleros", "Abel Pintos", "Soledad Pastorutti", "chacarera"}},
+	{ID: "tango", Name: "Tango", Queries: []string{"tango clásico", "Carlos Gardel", "Astor Piazzolla", "Aníbal Troilo", "Osvaldo Pugliese"}},
+	{ID: "jazz", Name: "Jazz", Queries: []string{"jazz classics", "Miles Davis", "John Coltrane", "Bill Evans", "Ella Fitzgerald", "Chet Baker"}},
+	{ID: "lofi", Name: "Lo-fi", Queries: []string{"lofi hip hop", "lofi beats", "chillhop", "lofi jazz"}},
+	{ID: "clasica", Name: "Clásica", Queries: []string{"classical music", "Bach", "Mozart", "Beethoven", "Chopin", "Debussy"}},
+	{ID: "metal", Name: "Metal", Queries: []string{"heavy metal classics", "Metallica", "Iron Maiden", "Black Sabbath", "Rata Blanca", "Almafuerte"}},
+}
+
+// FindGenre devuelve el género con ese ID.
+func FindGenre(id string) (Genre, bool) {
+	for _, genre := range Genres {
+		if genre.ID == id {
+			return genre, true
+		}
+	}
+	return Genre{}, false
+}
+
+// Source devuelve canciones de un género para la radio. Cualquier fuente (un buscador, una API de recomendaciones)
+// puede implementarla.
+type Source interface {
+	// Tracks devuelve hasta limit canciones del género cuyas URLs no estén en exclude. Devuelve ErrNoTracks si no
+	// encuentra ninguna.
+	Tracks(ctx context.Context, genre Genre, limit int, exclude map[string]bool) ([]*voice.Song, error)
+}
+
+// SearchSource busca las canciones de la radio en YouTube, con las búsquedas del género en un orden al azar.
+type SearchSource struct {
+	searcher fetcher.SongSearcher
+	logger   logging.Logger
+	shuffle  func(n int, swap func(i, j int))
+}
+
+// NewSearchSource crea un SearchSource.
+func NewSearchSource(searcher fetcher.SongSearcher, logger logging.Logger) *SearchSource {
+	return &SearchSource{searcher: searcher, logger: logger, shuffle: rand.Shuffle}
+}
+
+func (s *SearchSource) Tracks(ctx context.Context, genre Genre, limit int, exclude map[string]bool) ([]*voice.Song, error) {
+	queries := append([]string(nil), genre.Queries...)
+	s.shuffle(len(queries), func(i, j int) { queries[i], queries[j] = queries[j], queries[i] })
+
+	songs := make([]*voice.Song, 0, limit)
+	seen := make(map[string]bool, len(exclude))
+	for url := range exclude {
+		seen[url] = true
+	}
+	// Se toma una canción de cada búsqueda, para que no salgan todas del mismo artista.
+	for _, query := range queries {
+		if len(songs) == limit {
+			break
+		}
+		videoIDs, err := s.searcher.SearchYouTubeVideoIDs(ctx, query, resultsPerQuery)
+		if err != nil {
+			if errors.Is(err, fetcher.ErrSearchNotSupported) || ctx.Err() != nil {
+				return nil, err
+			}
+			s.logger.Info("falló al buscar canciones del género", zap.String("genero", genre.ID), zap.String("busqueda", query), zap.Error(err))
+			continue
+		}
+		s.shuffle(len(videoIDs), func(i, j int) { videoIDs[i], videoIDs[j] = videoIDs[j], videoIDs[i] })
+		if song := s.firstNew(ctx, videoIDs, seen); song != nil {
+			seen[song.URL] = true
+			songs = append(songs, song)
+		}
+	}
+	if len(songs) == 0 {
+		return nil, ErrNoTracks
+	}
+	return songs, nil
+}
+
+// firstNew devuelve la primera canción de videoIDs que se puede reproducir, no es demasiado larga y no está en seen.
+func (s *SearchSource) firstNew(ctx context.Context, videoIDs []string, seen map[string]bool) *voice.Song {
+	for _, videoID := range videoIDs {
+		found, err := s.searcher.LookupSongs(ctx, videoID)
+		if err != nil {
+			s.logger.Info("falló al buscar una canción de la radio", zap.String("videoID", videoID), zap.Error(err))
+			continue
+		}
+		if len(found) != 1 || !found[0].Playable || found[0].Duration > maxDuration || seen[found[0].URL] {
+			continue
+		}
+		return found[0]
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package radio
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func song(id string, duration time.Duration) []*voice.Song {
+	return []*voice.Song{{Title: id, URL: "https://youtu.be/" + id, Playable: true, Duration: duration}}
+}
+
+func newTestSource(searcher *MockSongSearcher) *SearchSource {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	source := NewSearchSource(searcher, logger)
+	source.shuffle = func(int, func(i, j int)) {}
+	return source
+}
+
+func TestFindGenre(t *testing.T) {
+	genre, ok := FindGenre("tango")
+	assert.True(t, ok)
+	assert.Equal(t, "Tango", genre.Name)
+
+	_, ok = FindGenre("vaporwave")
+	assert.False(t, ok)
+
+	ids := make(map[string]bool, len(Genres))
+	for _, genre := range Genres {
+		assert.False(t, ids[genre.ID], "el ID %q está repetido", genre.ID)
+		assert.NotEmpty(t, genre.Queries, genre.ID)
+		ids[genre.ID] = true
+	}
+	assert.LessOrEqual(t, len(Genres), 24, "Discord acepta hasta 25 opciones, contando la de apagar la radio")
+}
+
+func TestSearchSource_Tracks(t *testing.T) {
+	searcher := new(MockSongSearcher)
+	genre := Genre{ID: "tango", Queries: []string{"Gardel", "Piazzolla", "Troilo"}}
+	// a1 ya sonó en la estación y a2 es un recopilado largo, así que de Gardel sale a3.
+	searcher.On("SearchYouTubeVideoIDs", "Gardel", resultsPerQuery).Return([]string{"a1", "a2", "a3"}, nil)
+	searcher.On("SearchYouTubeVideoIDs", "Piazzolla", resultsPerQuery).Return(nil, errors.New("falla"))
+	searcher.On("SearchYouTubeVideoIDs", "Troilo", resultsPerQuery).Return([]string{"a3", "b1"}, nil)
+	searcher.On("LookupSongs", "a1").Return(song("a1", 3*time.Minute), nil)
+	searcher.On("LookupSongs", "a2").Return(song("a2", time.Hour), nil)
+	searcher.On("LookupSongs", "a3").Return(song("a3", 3*time.Minute), nil)
+	searcher.On("LookupSongs", "b1").Return(song("b1", 4*time.Minute), nil)
+
+	songs, err := newTestSource(searcher).Tracks(context.Background(), genre, 5, map[string]bool{"https://youtu.be/a1": true})
+
+	require.NoError(t, err)
+	require.Len(t, songs, 2)
+	assert.Equal(t, "a3", songs[0].Title)
+	assert.Equal(t, "b1", songs[1].Title, "no repite la canción que ya eligió de otra búsqueda")
+}
+
+func TestSearchSource_TracksLimit(t *testing.T) {
+	searcher := new(MockSongSearcher)
+	genre := Genre{ID: "jazz", Queries: []string{"Miles Davis", "Coltrane"}}
+	searcher.On("SearchYouTubeVideoIDs", "Miles Davis", resultsPerQuery).Return([]string{"a1"}, nil)
+	searcher.On("LookupSongs", "a1").Return(song("a1", 3*time.Minute), nil)
+
+	songs, err := newTestSource(searcher).Tracks(context.Background(), genre, 1, nil)
+
+	require.NoError(t, err)
+	assert.Len(t, songs, 1)
+	searcher.AssertNotCalled(t, "SearchYouTubeVideoIDs", "Coltrane", resultsPerQuery)
+}
+
+func TestSearchSource_TracksErrors(t *testing.T) {
+	searcher := new(MockSongSearcher)
+	searcher.On("SearchYouTubeVideoIDs", "lofi", resultsPerQuery).Return([]string{}, nil)
+	_, err := newTestSource(searcher).Tracks(context.Background(), Genre{Queries: []string{"lofi"}}, 2, nil)
+	assert.ErrorIs(t, err, ErrNoTracks)
+
+	searcher = new(MockSongSearcher)
+	searcher.On("SearchYouTubeVideoIDs", "lofi", resultsPerQuery).Return(nil, fetcher.ErrSearchNotSupported)
+	_, err = newTestSource(searcher).Tracks(context.Background(), Genre{Queries: []string{"lofi"}}, 2, nil)
+	assert.ErrorIs(t, err, fetcher.ErrSearchNotSupported)
+}
@@ -0,0 +1,94 @@
+// Package recommend recomienda canciones a partir del historial de reproducción de un servidor.
+package recommend
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// seedArtists es la cantidad de artistas más escuchados que se usan para buscar recomendaciones.
+	seedArtists = 5
+	// resultsPerArtist es la cantidad de resultados que se piden por artista.
+	resultsPerArtist = 8
+	// maxDuration descarta los videos largos, que suelen ser recopilados o mixes.
+	maxDuration = 10 * time.Minute
+)
+
+// ErrNoHistory indica que el historial no alcanza para recomendar canciones.
+var ErrNoHistory = errors.New("no hay historial suficiente para recomendar canciones")
+
+// Provider recomienda canciones a partir del historial de reproducción de un servidor. Cualquier fuente de
+// recomendaciones (una API de terceros, un modelo propio) puede implementarla.
+type Provider interface {
+	// Recommend devuelve hasta limit canciones recomendadas para el servidor que escuchó entries. Devuelve
+	// ErrNoHistory si no puede recomendar nada con ese historial.
+	Recommend(ctx context.Context, entries []history.Entry, limit int) ([]*voice.Song, error)
+}
+
+// SearchProvider recomienda canciones de los artistas más escuchados que todavía no sonaron en el servidor,
+// buscándolas en YouTube.
+type SearchProvider struct {
+	searcher fetcher.SongSearcher
+	logger   logging.Logger
+}
+
+// NewSearchProvider crea un SearchProvider.
+func NewSearchProvider(searcher fetcher.SongSearcher, logger logging.Logger) *SearchProvider {
+	return &SearchProvider{searcher: searcher, logger: logger}
+}
+
+func (p *SearchProvider) Recommend(ctx context.Context, entries []history.Entry, limit int) ([]*voice.Song, error) {
+	stats := history.Summarize(entries, seedArtists)
+	if len(stats.TopArtists) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	played := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		played[entry.URL] = true
+	}
+
+	candidates := make([][]string, 0, len(stats.TopArtists))
+	for _, artist := range stats.TopArtists {
+		videoIDs, err := p.searcher.SearchYouTubeVideoIDs(ctx, artist.Name, resultsPerArtist)
+		if err != nil {
+			if errors.Is(err, fetcher.ErrSearchNotSupported) || ctx.Err() != nil {
+				return nil, err
+			}
+			p.logger.Info("falló al buscar canciones del artista", zap.String("artista", artist.Name), zap.Error(err))
+			continue
+		}
+		candidates = append(candidates, videoIDs)
+	}
+
+	// Se toma un resultado de cada artista por vuelta, para que las recomendaciones no sean todas del más escuchado.
+	songs := make([]*voice.Song, 0, limit)
+	for round := 0; round < resultsPerArtist && len(songs) < limit; round++ {
+		for _, videoIDs := range candidates {
+			if len(songs) == limit {
+				break
+			}
+			if round >= len(videoIDs) {
+				continue
+			}
+			found, err := p.searcher.LookupSongs(ctx, videoIDs[round])
+			if err != nil {
+				p.logger.Info("falló al buscar una canción recomendada", zap.String("videoID", videoIDs[round]), zap.Error(err))
+				continue
+			}
+			if len(found) != 1 || !found[0].Playable || found[0].Duration > maxDuration || played[found[0].URL] {
+				continue
+			}
+			played[found[0].URL] = true
+			songs = append(songs, found[0])
+		}
+	}
+	return songs, nil
+}
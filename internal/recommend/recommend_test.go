@@ -0,0 +1,57 @@
+package recommend
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/history"
+	"github.com/Tomas-vilte/GoMusicBot/internal/music/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func song(id string, duration time.Duration) []*voice.Song {
+	return []*voice.Song{{Title: id, URL: "https://youtu.be/" + id, Playable: true, Duration: duration}}
+}
+
+func TestSearchProvider_Recommend(t *testing.T) {
+	searcher := new(MockSongSearcher)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	searcher.On("SearchYouTubeVideoIDs", "Soda Stereo", resultsPerArtist).Return([]string{"a1", "a2", "a3"}, nil)
+	searcher.On("SearchYouTubeVideoIDs", "Charly García", resultsPerArtist).Return([]string{"b1", "b2"}, nil)
+	// a1 ya sonó en el servidor, b1 es un recopilado largo y a2 falla.
+	searcher.On("LookupSongs", "a1").Return(song("a1", 4*time.Minute), nil)
+	searcher.On("LookupSongs", "b1").Return(song("b1", time.Hour), nil)
+	searcher.On("LookupSongs", "a2").Return(nil, errors.New("falla"))
+	searcher.On("LookupSongs", "b2").Return(song("b2", 3*time.Minute), nil)
+	searcher.On("LookupSongs", "a3").Return(song("a3", 5*time.Minute), nil)
+
+	songs, err := NewSearchProvider(searcher, logger).Recommend(context.Background(), []history.Entry{
+		{Title: "De música ligera", Uploader: "Soda Stereo", URL: "https://youtu.be/a1"},
+		{Title: "Persiana americana", Uploader: "Soda Stereo", URL: "https://youtu.be/x"},
+		{Title: "Charly García - Demoliendo hoteles", URL: "https://youtu.be/y"},
+	}, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, songs, 2)
+	assert.Equal(t, "b2", songs[0].Title)
+	assert.Equal(t, "a3", songs[1].Title)
+}
+
+func TestSearchProvider_RecommendNoHistory(t *testing.T) {
+	_, err := NewSearchProvider(new(MockSongSearcher), new(MockLogger)).Recommend(context.Background(), []history.Entry{{Title: "Sin artista"}}, 5)
+
+	assert.ErrorIs(t, err, ErrNoHistory)
+}
+
+func TestSearchProvider_RecommendSearchNotSupported(t *testing.T) {
+	searcher := new(MockSongSearcher)
+	searcher.On("SearchYouTubeVideoIDs", "Soda Stereo", resultsPerArtist).Return(nil, fetcher.ErrSearchNotSupported)
+
+	_, err := NewSearchProvider(searcher, new(MockLogger)).Recommend(context.Background(), []history.Entry{{Uploader: "Soda Stereo"}}, 5)
+
+	assert.ErrorIs(t, err, fetcher.ErrSearchNotSupported)
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+// ErrNil se devuelve cuando Redis responde con un valor nulo (por ejemplo, GET de una clave inexistente).
+var ErrNil = errors.New("redis: valor nulo")
+
+// Error es un error devuelto por el servidor de Redis (respuesta "-ERR ...").
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Commander define la interfaz para ejecutar comandos de Redis.
+type Commander interface {
+	Do(ctx context.Context, args ...string) (interface{}, error)
+}
+
+// Options configura la conexión a Redis.
+type Options struct {
+	Address  string
+	Username string // Username es opcional; sin él se autentica solo con la contraseña.
+	Password string
+	DB       int
+	// PoolSize es la cantidad máxima de conexiones abiertas a la vez; con 0 se usan 10 por CPU.
+	PoolSize int
+	// TLS cifra las conexiones, como piden los Redis administrados. El certificado se valida contra el host de Address.
+	TLS bool
+}
+
+// Client es un cliente de Redis con un pool de conexiones, que se abren cuando hacen falta y se reabren si se cortan.
+// Las respuestas se devuelven como string, int64, nil o []interface{}; los errores del servidor como Error.
+type Client struct {
+	client *goredis.Client
+}
+
+// NewClient crea un cliente de Redis con las opciones indicadas.
+func NewClient(opts Options) *Client {
+	options := &goredis.Options{
+		Addr:        opts.Address,
+		Username:    opts.Username,
+		Password:    opts.Password,
+		DB:          opts.DB,
+		PoolSize:    opts.PoolSize,
+		DialTimeout: 5 * time.Second,
+		// Con RESP2 las respuestas tienen los tipos que esperan los stores.
+		Protocol:        2,
+		DisableIdentity: true,
+	}
+	if opts.TLS {
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &Client{client: goredis.NewClient(options)}
+}
+
+// Do ejecuta un comando y devuelve su respuesta.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	command := make([]interface{}, len(args))
+	for i, arg := range args {
+		command[i] = arg
+	}
+	reply, err := c.client.Do(ctx, command...).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrNil
+	}
+	var redisErr goredis.Error
+	if errors.As(err, &redisErr) {
+		return nil, Error(redisErr.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al ejecutar el comando en Redis: %w", err)
+	}
+	return convertReply(reply), nil
+}
+
+// Close cierra las conexiones con Redis.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// convertReply convierte los errores que vienen dentro de un array, como los de EXEC, en Error.
+func convertReply(reply interface{}) interface{} {
+	switch value := reply.(type) {
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = convertReply(item)
+		}
+		return items
+	case goredis.Error:
+		return Error(value.Error())
+	default:
+		return reply
+	}
+}
+
+// String convierte la respuesta de un comando en string.
+func String(reply interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	s, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: se esperaba un string y se recibió %T", reply)
+	}
+	return s, nil
+}
+
+// Int convierte la respuesta de un comando en entero.
+func Int(reply interface{}, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: se esperaba un entero y se recibió %T", reply)
+	}
+	return n, nil
+}
+
+// Strings convierte la respuesta de un comando en una lista de strings. Los elementos nulos quedan vacíos.
+func Strings(reply interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("redis: se esperaba un array y se recibió %T", reply)
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("redis: se esperaba un string y se recibió %T", item)
+		}
+		values[i] = s
+	}
+	return values, nil
+}
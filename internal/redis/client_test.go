@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// readCommand lee un comando RESP: un array de bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+// serveReplies levanta un servidor que responde a cada comando recibido con la respuesta indicada, en orden. HELLO
+// se rechaza, como en las versiones de Redis sin RESP3, y AUTH se acepta.
+func serveReplies(t *testing.T, replies ...string) (string, chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	commands := make(chan []string, len(replies)+1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for len(replies) > 0 {
+			command, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+			reply := "-ERR unknown command 'HELLO'\r\n"
+			if !strings.EqualFold(command[0], "HELLO") {
+				commands <- command
+				reply, replies = replies[0], replies[1:]
+			}
+			_, _ = conn.Write([]byte(reply))
+		}
+	}()
+	return listener.Addr().String(), commands
+}
+
+func TestClient_Do(t *testing.T) {
+	addr, commands := serveReplies(t,
+		"+OK\r\n",
+		"+OK\r\n",
+		"$5\r\nhola!\r\n",
+		"$-1\r\n",
+		":3\r\n",
+		"*2\r\n$1\r\na\r\n$-1\r\n",
+		"-ERR comando desconocido\r\n",
+		"*2\r\n+OK\r\n-WRONGTYPE clave de otro tipo\r\n",
+	)
+	client := NewClient(Options{Address: addr, Password: "secreto", PoolSize: 1})
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err := String(client.Do(ctx, "SET", "clave", "hola!"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth", "secreto"}, <-commands)
+	assert.Equal(t, []string{"SET", "clave", "hola!"}, <-commands)
+
+	value, err := String(client.Do(ctx, "GET", "clave"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hola!", value)
+
+	_, err = client.Do(ctx, "GET", "otra")
+	assert.True(t, errors.Is(err, ErrNil))
+
+	n, err := Int(client.Do(ctx, "RPUSH", "lista", "a", "b", "c"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	values, err := Strings(client.Do(ctx, "MGET", "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", ""}, values)
+
+	_, err = client.Do(ctx, "FOO")
+	var redisErr Error
+	assert.True(t, errors.As(err, &redisErr))
+	assert.Equal(t, "ERR comando desconocido", err.Error())
+
+	reply, err := client.Do(ctx, "EXEC")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK", Error("WRONGTYPE clave de otro tipo")}, reply)
+}
+
+func TestClient_DoConnectionError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	client := NewClient(Options{Address: addr})
+	defer client.Close()
+	_, err = client.Do(context.Background(), "PING")
+	assert.Error(t, err)
+	var redisErr Error
+	assert.False(t, errors.As(err, &redisErr), "un error de conexión no es un error del servidor")
+}
+
+func TestNewClient_Options(t *testing.T) {
+	client := NewClient(Options{Address: "redis:6380", PoolSize: 4, TLS: true})
+	defer client.Close()
+	options := client.client.Options()
+	assert.Equal(t, 4, options.PoolSize)
+	assert.NotNil(t, options.TLSConfig, "con TLS las conexiones se cifran")
+
+	plain := NewClient(Options{Address: "redis:6379"})
+	defer plain.Close()
+	assert.Nil(t, plain.client.Options().TLSConfig)
+}
@@ -0,0 +1,75 @@
+package scheduled
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"sync"
+	"time"
+)
+
+// FileStore implementa Store guardando las programaciones de todos los servidores en un archivo JSON.
+type FileStore struct {
+	mu        sync.RWMutex
+	filepath  string
+	schedules map[string]Schedule
+}
+
+// NewFileStore crea una nueva instancia de FileStore y carga las programaciones del archivo, si existe.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{filepath: path, schedules: make(map[string]Schedule)}
+	if err := jsonfile.Load(path, &s.schedules); err != nil {
+		return nil, fmt.Errorf("error al cargar las programaciones: %w", err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) Put(schedule Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return jsonfile.Update(s.schedules, schedule.ID, func() { s.schedules[schedule.ID] = schedule }, s.persist)
+}
+
+func (s *FileStore) List(guildID string) ([]Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSchedules(s.schedules, guildID), nil
+}
+
+func (s *FileStore) All() ([]Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSchedules(s.schedules, ""), nil
+}
+
+func (s *FileStore) Delete(guildID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.schedules[id]; !ok || schedule.GuildID != guildID {
+		return ErrNotFound
+	}
+	return jsonfile.Update(s.schedules, id, func() { delete(s.schedules, id) }, s.persist)
+}
+
+func (s *FileStore) MarkRun(guildID, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok || schedule.GuildID != guildID {
+		return ErrNotFound
+	}
+	schedule.LastRun = at
+	return jsonfile.Update(s.schedules, id, func() { s.schedules[id] = schedule }, s.persist)
+}
+
+// persist guarda las programaciones en el archivo. Se llama con mu tomado.
+func (s *FileStore) persist() error {
+	if err := jsonfile.Save(s.filepath, s.schedules); err != nil {
+		return fmt.Errorf("error al guardar las programaciones: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package scheduled
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryStore implementa Store guardando las programaciones en memoria.
+type InMemoryStore struct {
+	mu        sync.RWMutex
+	schedules map[string]Schedule
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{schedules: make(map[string]Schedule)}
+}
+
+func (s *InMemoryStore) Put(schedule Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (s *InMemoryStore) List(guildID string) ([]Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSchedules(s.schedules, guildID), nil
+}
+
+func (s *InMemoryStore) All() ([]Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSchedules(s.schedules, ""), nil
+}
+
+func (s *InMemoryStore) Delete(guildID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.schedules[id]; !ok || schedule.GuildID != guildID {
+		return ErrNotFound
+	}
+	delete(s.schedules, id)
+	return nil
+}
+
+func (s *InMemoryStore) MarkRun(guildID, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok || schedule.GuildID != guildID {
+		return ErrNotFound
+	}
+	schedule.LastRun = at
+	s.schedules[id] = schedule
+	return nil
+}
+
+// listSchedules devuelve las programaciones del servidor, o de todos si guildID está vacío, ordenadas por fecha de
+// creación.
+func listSchedules(schedules map[string]Schedule, guildID string) []Schedule {
+	list := make([]Schedule, 0)
+	for _, schedule := range schedules {
+		if guildID == "" || schedule.GuildID == guildID {
+			list = append(list, schedule)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
@@ -0,0 +1,240 @@
+package scheduled
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// MaxSchedulesPerGuild es la cantidad máxima de reproducciones programadas por servidor, y también la de alarmas.
+	MaxSchedulesPerGuild = 25
+	// maxScheduleDelay es el atraso máximo con el que se reproduce una programación. Si el bot estuvo apagado más
+	// tiempo, se saltea, para no reproducir todas las atrasadas juntas al volver.
+	maxScheduleDelay = 5 * time.Minute
+)
+
+var (
+	// ErrNotFound indica que la programación no existe.
+	ErrNotFound = errors.New("la programación no existe")
+	// ErrTooMany indica que el servidor llegó a MaxSchedulesPerGuild.
+	ErrTooMany = errors.New("el servidor tiene demasiadas programaciones")
+)
+
+type (
+	// Schedule es una reproducción programada de una lista guardada, que se repite según Spec, o una alarma, que
+	// reproduce Input una sola vez en At.
+	Schedule struct {
+		ID             string    `json:"id"`
+		GuildID        string    `json:"guild_id"`
+		VoiceChannelID string    `json:"voice_channel_id"`
+		TextChannelID  string    `json:"text_channel_id"`
+		Playlist       string    `json:"playlist,omitempty"`
+		Spec           string    `json:"spec,omitempty"`
+		Input          string    `json:"input,omitempty"`
+		Volume         int       `json:"volume,omitempty"` // Volume es el volumen de la alarma; con 0 no se cambia.
+		At             time.Time `json:"at"`
+		CreatedBy      string    `json:"created_by"`
+		CreatedAt      time.Time `json:"created_at"`
+		LastRun        time.Time `json:"last_run"`
+	}
+
+	// Store guarda las reproducciones programadas de todos los servidores.
+	Store interface {
+		// Put crea o reemplaza la programación con el mismo ID.
+		Put(schedule Schedule) error
+		// List devuelve las programaciones del servidor ordenadas por fecha de creación.
+		List(guildID string) ([]Schedule, error)
+		// All devuelve las programaciones de todos los servidores.
+		All() ([]Schedule, error)
+		// Delete elimina la programación del servidor con ese ID, o devuelve ErrNotFound.
+		Delete(guildID, id string) error
+		// MarkRun guarda cuándo se reprodujo por última vez la programación, o devuelve ErrNotFound si se eliminó.
+		MarkRun(guildID, id string, at time.Time) error
+	}
+
+	// RunFunc reproduce una programación cuando le toca.
+	RunFunc func(ctx context.Context, schedule Schedule) error
+)
+
+// NewSchedule valida la expresión y crea una programación con un ID nuevo.
+func NewSchedule(guildID, voiceChannelID, textChannelID, playlist, spec, createdBy string) (Schedule, error) {
+	parsed, err := ParseSpec(spec)
+	if err != nil {
+		return Schedule{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{
+		ID:             id,
+		GuildID:        guildID,
+		VoiceChannelID: voiceChannelID,
+		TextChannelID:  textChannelID,
+		Playlist:       playlist,
+		Spec:           parsed.String(),
+		CreatedBy:      createdBy,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// NewAlarm crea una alarma que reproduce input en at con el volumen indicado.
+func NewAlarm(guildID, voiceChannelID, textChannelID, input string, volume int, at time.Time, createdBy string) (Schedule, error) {
+	id, err := newID()
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{
+		ID:             id,
+		GuildID:        guildID,
+		VoiceChannelID: voiceChannelID,
+		TextChannelID:  textChannelID,
+		Input:          input,
+		Volume:         volume,
+		At:             at,
+		CreatedBy:      createdBy,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// Add guarda la programación si el servidor no llegó al máximo de las de su tipo.
+func Add(store Store, schedule Schedule) error {
+	existing, err := store.List(schedule.GuildID)
+	if err != nil {
+		return err
+	}
+	if len(Filter(existing, schedule.IsAlarm())) >= MaxSchedulesPerGuild {
+		return ErrTooMany
+	}
+	return store.Put(schedule)
+}
+
+// Filter devuelve solo las alarmas, o solo las reproducciones programadas.
+func Filter(schedules []Schedule, alarms bool) []Schedule {
+	filtered := make([]Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.IsAlarm() == alarms {
+			filtered = append(filtered, schedule)
+		}
+	}
+	return filtered
+}
+
+// IsAlarm indica si es una alarma, que se reproduce una sola vez.
+func (s Schedule) IsAlarm() bool {
+	return !s.At.IsZero()
+}
+
+// Next devuelve la próxima vez que corresponde reproducir la programación, en la zona horaria loc. Una alarma que
+// ya sonó no tiene próxima vez y devuelve el tiempo cero.
+func (s Schedule) Next(loc *time.Location) (time.Time, error) {
+	if s.IsAlarm() {
+		if !s.LastRun.IsZero() {
+			return time.Time{}, nil
+		}
+		return s.At.In(loc), nil
+	}
+	spec, err := ParseSpec(s.Spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	after := s.CreatedAt
+	if s.LastRun.After(after) {
+		after = s.LastRun
+	}
+	return spec.Next(after.In(loc)), nil
+}
+
+// newID genera un ID aleatorio para una programación.
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error al generar el ID de la programación: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Scheduler reproduce las programaciones guardadas cuando les toca.
+type Scheduler struct {
+	store     Store
+	location  *time.Location
+	locations func(guildID string) *time.Location // locations es opcional; devuelve la zona horaria de cada servidor.
+	run       RunFunc
+	logger    logging.Logger
+}
+
+// NewScheduler crea un Scheduler que interpreta las expresiones en la zona horaria location.
+func NewScheduler(store Store, location *time.Location, run RunFunc, logger logging.Logger) *Scheduler {
+	return &Scheduler{store: store, location: location, run: run, logger: logger}
+}
+
+// WithGuildLocations hace que las expresiones de cada servidor se interpreten en la zona horaria que devuelve
+// locations, en lugar de la del Scheduler.
+func (s *Scheduler) WithGuildLocations(locations func(guildID string) *time.Location) *Scheduler {
+	s.locations = locations
+	return s
+}
+
+// locationFor devuelve la zona horaria de las programaciones del servidor.
+func (s *Scheduler) locationFor(guildID string) *time.Location {
+	if s.locations != nil {
+		return s.locations(guildID)
+	}
+	return s.location
+}
+
+// Run revisa las programaciones cada interval hasta que se cancele ctx.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, now)
+		}
+	}
+}
+
+// tick reproduce las programaciones a las que les tocó desde la última revisión. Las alarmas se eliminan después de
+// sonar.
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	schedules, err := s.store.All()
+	if err != nil {
+		s.logger.Error("Error al obtener las reproducciones programadas", zap.Error(err))
+		return
+	}
+	for _, schedule := range schedules {
+		logger := logging.WithFields(s.logger, zap.String("guildID", schedule.GuildID), zap.String("scheduleID", schedule.ID))
+		next, err := schedule.Next(s.locationFor(schedule.GuildID))
+		if err != nil {
+			logger.Error("La reproducción programada tiene una expresión inválida", zap.Error(err))
+			continue
+		}
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+		if now.Sub(next) > maxScheduleDelay {
+			logger.Warn("Se salteó una reproducción programada atrasada", zap.Time("programada", next))
+		} else if err := s.run(ctx, schedule); err != nil {
+			logger.Error("Error al reproducir la programación", zap.Error(err))
+		} else {
+			logger.Info("Se reprodujo la programación", zap.String("playlist", schedule.Playlist))
+		}
+		if schedule.IsAlarm() {
+			err = s.store.Delete(schedule.GuildID, schedule.ID)
+		} else {
+			err = s.store.MarkRun(schedule.GuildID, schedule.ID, now)
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			logger.Error("Error al guardar la última reproducción de la programación", zap.Error(err))
+		}
+	}
+}
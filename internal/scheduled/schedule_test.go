@@ -0,0 +1,199 @@
+package scheduled
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseSpec_Next(t *testing.T) {
+	// 2024-03-08 es viernes.
+	friday := time.Date(2024, 3, 8, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"weekdays 09:00", friday, time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{"daily 21:30", friday, time.Date(2024, 3, 8, 21, 30, 0, 0, time.UTC)},
+		{"weekends 09:00", friday, time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"mon,wed 18:00", friday, time.Date(2024, 3, 11, 18, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", friday, time.Date(2024, 3, 8, 10, 15, 0, 0, time.UTC)},
+		{"0 0 29 2 *", friday, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * 0", friday, time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", friday, time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			spec, err := ParseSpec(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec.Next(tt.after))
+		})
+	}
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "mañana", "weekdays 25:00", "someday 09:00", "60 * * * *", "* * * *", "5-1 * * * *", "*/0 * * * *"} {
+		_, err := ParseSpec(expr)
+		assert.ErrorIs(t, err, ErrInvalidSpec, expr)
+	}
+}
+
+func TestNextClock(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 8, 10, 0, 0, 0, loc)
+
+	today, err := NextClock("21:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 21, 30, 0, 0, loc), today)
+
+	tomorrow, err := NextClock("07:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 7, 30, 0, 0, loc), tomorrow)
+
+	_, err = NextClock("7.30", now)
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+}
+
+func TestParseStart(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 8, 10, 0, 0, 0, loc)
+
+	clock, err := ParseStart("21:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 21, 30, 0, 0, loc), clock)
+
+	date, err := ParseStart(" 2024-03-15   20:00 ", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 20, 0, 0, 0, loc), date)
+
+	_, err = ParseStart("2024-03-01 20:00", now)
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+	_, err = ParseStart("15/03 20:00", now)
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	store := NewInMemoryStore()
+	created := time.Date(2024, 3, 8, 8, 0, 0, 0, time.UTC)
+	due := Schedule{ID: "a", GuildID: "1", Playlist: "lofi", Spec: "daily 09:00", CreatedAt: created}
+	late := Schedule{ID: "b", GuildID: "1", Playlist: "rock", Spec: "daily 08:30", CreatedAt: created.Add(time.Second)}
+	later := Schedule{ID: "c", GuildID: "2", Playlist: "jazz", Spec: "daily 10:00", CreatedAt: created.Add(2 * time.Second)}
+	for _, schedule := range []Schedule{due, late, later} {
+		require.NoError(t, store.Put(schedule))
+	}
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	var ran []string
+	scheduler := NewScheduler(store, time.UTC, func(_ context.Context, schedule Schedule) error {
+		ran = append(ran, schedule.ID)
+		return nil
+	}, logger)
+	now := time.Date(2024, 3, 8, 9, 0, 10, 0, time.UTC)
+	scheduler.tick(context.Background(), now)
+	scheduler.tick(context.Background(), now.Add(15*time.Second))
+
+	assert.Equal(t, []string{"a"}, ran)
+	schedules, err := store.All()
+	require.NoError(t, err)
+	assert.Equal(t, now, schedules[0].LastRun)
+	assert.Equal(t, now, schedules[1].LastRun, "la atrasada se saltea pero queda marcada")
+	assert.True(t, schedules[2].LastRun.IsZero())
+}
+
+func TestScheduler_TickAlarms(t *testing.T) {
+	store := NewInMemoryStore()
+	now := time.Date(2024, 3, 8, 7, 30, 10, 0, time.UTC)
+	due, err := NewAlarm("1", "voz", "texto", "despertador", 80, now.Add(-10*time.Second), "u1")
+	require.NoError(t, err)
+	late, err := NewAlarm("1", "voz", "texto", "tarde", 0, now.Add(-time.Hour), "u1")
+	require.NoError(t, err)
+	later, err := NewAlarm("1", "voz", "texto", "después", 0, now.Add(time.Hour), "u1")
+	require.NoError(t, err)
+	for _, alarm := range []Schedule{due, late, later} {
+		require.NoError(t, store.Put(alarm))
+	}
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+
+	var ran []string
+	scheduler := NewScheduler(store, time.UTC, func(_ context.Context, schedule Schedule) error {
+		ran = append(ran, schedule.Input)
+		return nil
+	}, logger)
+	scheduler.tick(context.Background(), now)
+	scheduler.tick(context.Background(), now.Add(15*time.Second))
+
+	assert.Equal(t, []string{"despertador"}, ran)
+	schedules, err := store.All()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1, "las alarmas que sonaron o se saltearon se eliminan")
+	assert.Equal(t, later.ID, schedules[0].ID)
+}
+
+func TestScheduler_GuildLocations(t *testing.T) {
+	store := NewInMemoryStore()
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	require.NoError(t, store.Put(Schedule{ID: "a", GuildID: "1", Playlist: "lofi", Spec: "daily 09:00", CreatedAt: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)}))
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	var ran int
+	scheduler := NewScheduler(store, time.UTC, func(context.Context, Schedule) error {
+		ran++
+		return nil
+	}, logger).WithGuildLocations(func(string) *time.Location { return loc })
+	scheduler.tick(context.Background(), time.Date(2024, 3, 8, 9, 0, 10, 0, time.UTC))
+	assert.Zero(t, ran, "a las 09:00 UTC todavía no son las 09:00 del servidor")
+	scheduler.tick(context.Background(), time.Date(2024, 3, 8, 12, 0, 10, 0, time.UTC))
+	assert.Equal(t, 1, ran)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules", "schedules.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	schedule, err := NewSchedule("1", "voz", "texto", "lofi", "weekdays 09:00", "u1")
+	require.NoError(t, err)
+	require.NoError(t, Add(store, schedule))
+	runAt := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.MarkRun("1", schedule.ID, runAt))
+	assert.ErrorIs(t, store.Delete("2", schedule.ID), ErrNotFound)
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	schedules, err := reopened.List("1")
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "lofi", schedules[0].Playlist)
+	assert.True(t, runAt.Equal(schedules[0].LastRun))
+
+	require.NoError(t, reopened.Delete("1", schedule.ID))
+	assert.ErrorIs(t, reopened.MarkRun("1", schedule.ID, runAt), ErrNotFound)
+}
+
+func TestAdd_TooMany(t *testing.T) {
+	store := NewInMemoryStore()
+	for i := 0; i < MaxSchedulesPerGuild; i++ {
+		schedule, err := NewSchedule("1", "voz", "texto", "lofi", "daily 09:00", "u1")
+		require.NoError(t, err)
+		require.NoError(t, Add(store, schedule))
+	}
+	schedule, err := NewSchedule("1", "voz", "texto", "lofi", "daily 09:00", "u1")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, Add(store, schedule), ErrTooMany)
+	alarm, err := NewAlarm("1", "voz", "texto", "despertador", 0, time.Now().Add(time.Hour), "u1")
+	require.NoError(t, err)
+	assert.NoError(t, Add(store, alarm), "las alarmas tienen su propio límite")
+}
@@ -0,0 +1,144 @@
+// Package scheduled procesa los eventos programados con reglas de EventBridge (por ejemplo, "arrancar la
+// playlist de lo-fi a las 9:00") que llegan al bot a través de una cola de SQS, y las reproducciones programadas
+// desde Discord con /schedule, que revisa el propio bot con un Scheduler.
+package scheduled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// DetailTypePlay es el detail-type de los eventos que agregan una canción o playlist a la lista de
+	// reproducción de un servidor.
+	DetailTypePlay = "Scheduled Play"
+	// DetailTypeAnnouncement es el detail-type de los eventos que publican un anuncio en un canal de texto.
+	DetailTypeAnnouncement = "Scheduled Announcement"
+)
+
+// announcementColor es el color de los embeds de los anuncios.
+const announcementColor = 0x5865F2
+
+// errInvalidEvent indica que al evento le falta un campo obligatorio.
+var errInvalidEvent = errors.New("evento programado inválido")
+
+type (
+	// Messenger envía mensajes a los canales de texto. Lo implementa *discordgo.Session.
+	Messenger interface {
+		ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	}
+
+	// PlayEvent es el detail de DetailTypePlay. Si los canales están vacíos, se usan los que el reproductor tiene
+	// guardados. Si Message no está vacío, se anuncia en el canal de texto cuando la canción se agregó.
+	PlayEvent struct {
+		GuildID        string `json:"guild_id"`
+		VoiceChannelID string `json:"voice_channel_id,omitempty"`
+		TextChannelID  string `json:"text_channel_id,omitempty"`
+		Input          string `json:"input"`
+		Message        string `json:"message,omitempty"`
+	}
+
+	// AnnouncementEvent es el detail de DetailTypeAnnouncement.
+	AnnouncementEvent struct {
+		GuildID   string `json:"guild_id"`
+		ChannelID string `json:"channel_id"`
+		Title     string `json:"title,omitempty"`
+		Message   string `json:"message"`
+	}
+)
+
+// Handler procesa los eventos programados con el controlador de los reproductores.
+type Handler struct {
+	controller control.Controller
+	messenger  Messenger
+	logger     logging.Logger
+}
+
+// NewHandler crea un Handler.
+func NewHandler(controller control.Controller, messenger Messenger, logger logging.Logger) *Handler {
+	return &Handler{controller: controller, messenger: messenger, logger: logger}
+}
+
+// Register registra los handlers de los eventos programados en el dispatcher.
+func (h *Handler) Register(dispatcher *jobs.Dispatcher) {
+	dispatcher.
+		Handle(DetailTypePlay, h.handlePlay).
+		Handle(DetailTypeAnnouncement, h.handleAnnouncement)
+}
+
+// handlePlay agrega lo pedido a la lista de reproducción del servidor. Los errores que no se resuelven
+// reintentando (evento inválido, servidor sin reproductor, búsqueda sin resultados) se registran y el evento se
+// descarta; el resto se devuelve para que SQS lo reentregue.
+func (h *Handler) handlePlay(ctx context.Context, job *jobs.Job) error {
+	var event PlayEvent
+	if err := job.Decode(&event); err != nil {
+		return h.discard(ctx, job, err)
+	}
+	if event.GuildID == "" || event.Input == "" {
+		return h.discard(ctx, job, fmt.Errorf("%w: guild_id e input son obligatorios", errInvalidEvent))
+	}
+
+	songs, err := h.controller.Enqueue(ctx, control.EnqueueRequest{
+		GuildID:        event.GuildID,
+		Input:          event.Input,
+		VoiceChannelID: event.VoiceChannelID,
+		TextChannelID:  event.TextChannelID,
+		RequestedBy:    "EventBridge",
+	})
+	switch {
+	case errors.Is(err, control.ErrPlayerNotFound), errors.Is(err, control.ErrNoVoiceChannel), errors.Is(err, control.ErrNoSongsFound),
+		errors.Is(err, control.ErrSongsRejected):
+		return h.discard(ctx, job, err)
+	case err != nil:
+		return fmt.Errorf("error al agregar la canción programada: %w", err)
+	}
+	logging.FromContext(ctx, h.logger).Info("Se agregó la reproducción programada", zap.String("guildID", event.GuildID),
+		zap.String("input", event.Input), zap.Int("songs", len(songs)))
+
+	if event.Message != "" && event.TextChannelID != "" {
+		if err := h.send(event.TextChannelID, "", event.Message); err != nil {
+			// La canción ya está en la lista: si se reentregara el evento, se agregaría dos veces.
+			logging.FromContext(ctx, h.logger).Error("Error al anunciar la reproducción programada", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// handleAnnouncement publica el anuncio en el canal de texto.
+func (h *Handler) handleAnnouncement(ctx context.Context, job *jobs.Job) error {
+	var event AnnouncementEvent
+	if err := job.Decode(&event); err != nil {
+		return h.discard(ctx, job, err)
+	}
+	if event.ChannelID == "" || event.Message == "" {
+		return h.discard(ctx, job, fmt.Errorf("%w: channel_id y message son obligatorios", errInvalidEvent))
+	}
+	if err := h.send(event.ChannelID, event.Title, event.Message); err != nil {
+		return fmt.Errorf("error al publicar el anuncio programado: %w", err)
+	}
+	logging.FromContext(ctx, h.logger).Info("Se publicó el anuncio programado", zap.String("guildID", event.GuildID),
+		zap.String("channelID", event.ChannelID))
+	return nil
+}
+
+func (h *Handler) send(channelID, title, message string) error {
+	_, err := h.messenger.ChannelMessageSendEmbed(channelID, &discordgo.MessageEmbed{
+		Title:       title,
+		Description: message,
+		Color:       announcementColor,
+	})
+	return err
+}
+
+// discard registra el motivo por el que se descarta el evento y devuelve nil para que no se reentregue.
+func (h *Handler) discard(ctx context.Context, job *jobs.Job, err error) error {
+	logging.FromContext(ctx, h.logger).Warn("Se descartó un evento programado", zap.String("type", job.Type),
+		zap.String("guildID", job.GuildID), zap.Error(err))
+	return nil
+}
@@ -0,0 +1,79 @@
+package scheduled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func newJob(t *testing.T, detailType string, detail interface{}) *jobs.Job {
+	t.Helper()
+	data, err := json.Marshal(detail)
+	assert.NoError(t, err)
+	return &jobs.Job{Type: detailType, GuildID: "1", Payload: data}
+}
+
+func TestHandler_Play(t *testing.T) {
+	controller := new(MockController)
+	messenger := new(MockMessenger)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	controller.On("Enqueue", control.EnqueueRequest{GuildID: "1", Input: "lofi", VoiceChannelID: "10", TextChannelID: "20", RequestedBy: "EventBridge"}).
+		Return([]*voice.Song{{Title: "lofi hip hop radio"}}, nil)
+	messenger.On("ChannelMessageSendEmbed", "20", &discordgo.MessageEmbed{Description: "¡Buen día! Arranca el lo-fi", Color: announcementColor}).
+		Return(&discordgo.Message{}, nil)
+
+	err := NewHandler(controller, messenger, logger).handlePlay(context.Background(), newJob(t, DetailTypePlay, PlayEvent{
+		GuildID: "1", VoiceChannelID: "10", TextChannelID: "20", Input: "lofi", Message: "¡Buen día! Arranca el lo-fi",
+	}))
+
+	assert.NoError(t, err)
+	controller.AssertExpectations(t)
+	messenger.AssertExpectations(t)
+}
+
+func TestHandler_PlayDiscardsPermanentErrors(t *testing.T) {
+	controller := new(MockController)
+	logger := new(MockLogger)
+	logger.On("Warn", "Se descartó un evento programado", mock.Anything).Return()
+	controller.On("Enqueue", mock.Anything).Return(nil, control.ErrPlayerNotFound).Once()
+	handler := NewHandler(controller, new(MockMessenger), logger)
+
+	assert.NoError(t, handler.handlePlay(context.Background(), newJob(t, DetailTypePlay, PlayEvent{GuildID: "1", Input: "lofi"})))
+	assert.NoError(t, handler.handlePlay(context.Background(), newJob(t, DetailTypePlay, PlayEvent{GuildID: "1"})))
+
+	controller.AssertNumberOfCalls(t, "Enqueue", 1)
+	logger.AssertNumberOfCalls(t, "Warn", 2)
+}
+
+func TestHandler_PlayRetriesOtherErrors(t *testing.T) {
+	controller := new(MockController)
+	controller.On("Enqueue", mock.Anything).Return(nil, errors.New("timeout de YouTube"))
+
+	err := NewHandler(controller, new(MockMessenger), new(MockLogger)).
+		handlePlay(context.Background(), newJob(t, DetailTypePlay, PlayEvent{GuildID: "1", Input: "lofi"}))
+
+	assert.ErrorContains(t, err, "timeout de YouTube")
+}
+
+func TestHandler_Announcement(t *testing.T) {
+	messenger := new(MockMessenger)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	messenger.On("ChannelMessageSendEmbed", "20", &discordgo.MessageEmbed{Title: "Noche de karaoke", Description: "Arrancamos a las 22", Color: announcementColor}).
+		Return(nil, errors.New("falta de permisos")).Once()
+	messenger.On("ChannelMessageSendEmbed", "20", mock.Anything).Return(&discordgo.Message{}, nil).Once()
+	handler := NewHandler(new(MockController), messenger, logger)
+	job := newJob(t, DetailTypeAnnouncement, AnnouncementEvent{GuildID: "1", ChannelID: "20", Title: "Noche de karaoke", Message: "Arrancamos a las 22"})
+
+	assert.ErrorContains(t, handler.handleAnnouncement(context.Background(), job), "falta de permisos")
+	assert.NoError(t, handler.handleAnnouncement(context.Background(), job))
+	messenger.AssertExpectations(t)
+}
@@ -0,0 +1,217 @@
+package scheduled
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSpecSearch es hasta cuándo se busca la próxima ejecución de una expresión; alcanza para el 29 de febrero.
+const maxSpecSearch = 5 * 366 * 24 * time.Hour
+
+// ErrInvalidSpec indica que la expresión de una programación no es válida.
+var ErrInvalidSpec = errors.New("expresión de programación inválida")
+
+var (
+	// dayNames son los nombres de los días que se aceptan en las expresiones, en inglés y en castellano.
+	dayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		"dom": 0, "lun": 1, "mar": 2, "mie": 3, "jue": 4, "vie": 5, "sab": 6,
+	}
+	// dayShortcuts son los atajos para los días de las expresiones "<días> HH:MM".
+	dayShortcuts = map[string]string{
+		"daily": "*", "diario": "*",
+		"weekdays": "1-5", "semana": "1-5",
+		"weekends": "0,6", "findes": "0,6",
+	}
+)
+
+// Spec es una expresión de programación en formato cron de cinco campos: minuto, hora, día del mes, mes y día de
+// la semana (0 es domingo). Cada campo acepta "*", valores, rangos ("1-5"), listas ("1,3,5") y pasos ("*/15").
+type Spec struct {
+	expr     string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// anyDay y anyWeekday indican si el día del mes o el de la semana es "*". Como en cron, si los dos están
+	// restringidos alcanza con que coincida uno.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseSpec interpreta una expresión cron de cinco campos o una de la forma "<días> HH:MM", donde los días son
+// "daily", "weekdays", "weekends" o una lista de días como "mon,wed,fri".
+func ParseSpec(expr string) (Spec, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	fields := strings.Fields(expr)
+	if len(fields) == 2 {
+		converted, err := shortcutToCron(fields[0], fields[1])
+		if err != nil {
+			return Spec{}, err
+		}
+		fields = strings.Fields(converted)
+	}
+	if len(fields) != 5 {
+		return Spec{}, fmt.Errorf("%w: se esperan cinco campos o \"<días> HH:MM\": %s", ErrInvalidSpec, expr)
+	}
+
+	spec := Spec{expr: expr, anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	for _, field := range []struct {
+		value    string
+		min, max int
+		bits     *uint64
+	}{
+		{fields[0], 0, 59, &spec.minutes},
+		{fields[1], 0, 23, &spec.hours},
+		{fields[2], 1, 31, &spec.days},
+		{fields[3], 1, 12, &spec.months},
+		{fields[4], 0, 7, &spec.weekdays},
+	} {
+		bits, err := parseField(field.value, field.min, field.max)
+		if err != nil {
+			return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalidSpec, expr, err)
+		}
+		*field.bits = bits
+	}
+	// El 7 también es domingo.
+	if spec.weekdays&(1<<7) != 0 {
+		spec.weekdays |= 1
+	}
+	return spec, nil
+}
+
+// NextClock devuelve la próxima vez después de now en la que el reloj marca clock ("HH:MM"), en la zona horaria de
+// now: hoy si todavía no pasó, o mañana.
+func NextClock(clock string, now time.Time) (time.Time, error) {
+	spec, err := ParseSpec("daily " + clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return spec.Next(now), nil
+}
+
+// startLayout es el formato de una fecha y hora para ParseStart.
+const startLayout = "2006-01-02 15:04"
+
+// ParseStart interpreta cuándo empieza algo que pasa una sola vez: "HH:MM" es la próxima vez que el reloj marca esa
+// hora, y "AAAA-MM-DD HH:MM" es esa fecha y hora, en la zona horaria de now. Tiene que ser posterior a now.
+func ParseStart(value string, now time.Time) (time.Time, error) {
+	value = strings.Join(strings.Fields(value), " ")
+	if !strings.Contains(value, " ") {
+		return NextClock(value, now)
+	}
+	at, err := time.ParseInLocation(startLayout, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidSpec, value)
+	}
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("%w: %s ya pasó", ErrInvalidSpec, value)
+	}
+	return at, nil
+}
+
+// String devuelve la expresión tal como se escribió.
+func (s Spec) String() string {
+	return s.expr
+}
+
+// Next devuelve el primer minuto posterior a after en el que corresponde ejecutar, en la zona horaria de after. Si
+// no hay ninguno en los próximos años (por ejemplo, "0 0 31 2 *"), devuelve el tiempo cero.
+func (s Spec) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxSpecSearch)
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Spec) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// shortcutToCron convierte una expresión "<días> HH:MM" en una expresión cron.
+func shortcutToCron(days, clock string) (string, error) {
+	hour, minute, ok := strings.Cut(clock, ":")
+	h, errHour := strconv.Atoi(hour)
+	m, errMinute := strconv.Atoi(minute)
+	if !ok || errHour != nil || errMinute != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return "", fmt.Errorf("%w: hora inválida: %s", ErrInvalidSpec, clock)
+	}
+	weekdays, ok := dayShortcuts[days]
+	if !ok {
+		weekdays = days
+	}
+	return fmt.Sprintf("%d %d * * %s", m, h, weekdays), nil
+}
+
+// parseField devuelve los valores de un campo como bits encendidos.
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("paso inválido: %s", part)
+			}
+		}
+		start, end := min, max
+		if rangeExpr != "*" {
+			from, to, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = parseValue(from); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("valor fuera de rango: %s", part)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue interpreta un número o un nombre de día.
+func parseValue(value string) (int, error) {
+	if day, ok := dayNames[value]; ok {
+		return day, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido: %s", value)
+	}
+	return n, nil
+}
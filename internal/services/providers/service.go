@@ -9,6 +9,13 @@ type (
 	// YouTubeService define una interfaz para las operaciones relacionadas con YouTube.
 	YouTubeService interface {
 		SearchVideoID(ctx context.Context, searchTerm string) (string, error)
+		SearchVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error)
 		GetVideoDetails(ctx context.Context, videoID string) (*youtube.Video, error)
 	}
+
+	// PlaylistService es un YouTubeService que además lista los elementos de una playlist.
+	PlaylistService interface {
+		YouTubeService
+		GetPlaylistItems(ctx context.Context, playlistID string, limit int) ([]*youtube.PlaylistItem, error)
+	}
 )
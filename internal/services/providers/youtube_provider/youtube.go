@@ -13,6 +13,7 @@ type (
 	YouTubeClient interface {
 		VideosListCall(ctx context.Context, part []string) VideosListCallWrapper
 		SearchListCall(ctx context.Context, part []string) SearchListCallWrapper
+		PlaylistItemsListCall(ctx context.Context, part []string) PlaylistItemsListCallWrapper
 	}
 
 	// YouTubeProvider implementa la interface providers.Service
@@ -51,6 +52,27 @@ func (p *YouTubeProvider) SearchVideoID(ctx context.Context, searchTerm string)
 	return videoID, nil
 }
 
+// SearchVideoIDs busca videos en YouTube por un término de búsqueda y devuelve hasta limit IDs, en el orden de los
+// resultados.
+func (p *YouTubeProvider) SearchVideoIDs(ctx context.Context, searchTerm string, limit int) ([]string, error) {
+	p.logger.Info("Buscando videos en YouTube", zap.String("searchTerm", searchTerm), zap.Int("limit", limit))
+	call := p.Client.SearchListCall(ctx, []string{"id"}).Q(searchTerm).MaxResults(int64(limit)).Type("video")
+
+	response, err := call.Do()
+	if err != nil {
+		p.logger.Error("Error al buscar videos en YouTube", zap.Error(err))
+		return nil, fmt.Errorf("error al buscar videos en YouTube: %w", err)
+	}
+
+	videoIDs := make([]string, 0, len(response.Items))
+	for _, item := range response.Items {
+		if item.Id != nil && item.Id.VideoId != "" {
+			videoIDs = append(videoIDs, item.Id.VideoId)
+		}
+	}
+	return videoIDs, nil
+}
+
 // GetVideoDetails obtiene los detalles de un video de YouTube por su ID.
 func (p *YouTubeProvider) GetVideoDetails(ctx context.Context, videoID string) (*youtube.Video, error) {
 	p.logger.Info("Obteniendo detalles del video desde Youtube", zap.String("videoID", videoID))
@@ -72,3 +94,33 @@ func (p *YouTubeProvider) GetVideoDetails(ctx context.Context, videoID string) (
 
 	return response.Items[0], nil
 }
+
+// playlistPageSize es la cantidad máxima de elementos que devuelve la API por página de una playlist.
+const playlistPageSize = 50
+
+// GetPlaylistItems obtiene hasta limit elementos de una playlist de YouTube, en orden, recorriendo las páginas.
+func (p *YouTubeProvider) GetPlaylistItems(ctx context.Context, playlistID string, limit int) ([]*youtube.PlaylistItem, error) {
+	p.logger.Info("Obteniendo los elementos de la playlist desde YouTube", zap.String("playlistID", playlistID), zap.Int("limit", limit))
+	var items []*youtube.PlaylistItem
+	pageToken := ""
+	for len(items) < limit {
+		call := p.Client.PlaylistItemsListCall(ctx, []string{"snippet", "contentDetails"}).PlaylistId(playlistID).MaxResults(int64(min(limit-len(items), playlistPageSize)))
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		response, err := call.Do()
+		if err != nil {
+			p.logger.Error("Error al obtener los elementos de la playlist desde YouTube", zap.Error(err))
+			return nil, fmt.Errorf("error al obtener los elementos de la playlist: %w", err)
+		}
+		items = append(items, response.Items...)
+		if response.NextPageToken == "" || len(response.Items) == 0 {
+			break
+		}
+		pageToken = response.NextPageToken
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
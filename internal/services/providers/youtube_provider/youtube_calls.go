@@ -3,8 +3,10 @@ package youtube_provider
 import (
 	"context"
 	"fmt"
+	"google.golang.org/api/googleapi/transport"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
+	"net/http"
 )
 
 type (
@@ -22,6 +24,14 @@ type (
 		Do() (*youtube.VideoListResponse, error)
 	}
 
+	// PlaylistItemsListCallWrapper es una interfaz que envuelve youtube.PlaylistItemsListCall
+	PlaylistItemsListCallWrapper interface {
+		PlaylistId(playlistID string) PlaylistItemsListCallWrapper
+		MaxResults(maxResults int64) PlaylistItemsListCallWrapper
+		PageToken(pageToken string) PlaylistItemsListCallWrapper
+		Do() (*youtube.PlaylistItemListResponse, error)
+	}
+
 	RealVideosListCallWrapper struct {
 		Call *youtube.VideosListCall
 	}
@@ -30,6 +40,10 @@ type (
 		Call *youtube.SearchListCall
 	}
 
+	RealPlaylistItemsListCallWrapper struct {
+		Call *youtube.PlaylistItemsListCall
+	}
+
 	RealYouTubeClient struct {
 		Service *youtube.Service
 	}
@@ -44,6 +58,18 @@ func NewRealYouTubeClient(apiKey string) (*RealYouTubeClient, error) {
 	return &RealYouTubeClient{service}, nil
 }
 
+// NewRealYouTubeClientWithTransport crea el cliente de la API de YouTube con un transporte HTTP propio, por ejemplo
+// uno que rota la dirección de origen de cada solicitud.
+func NewRealYouTubeClientWithTransport(apiKey string, base http.RoundTripper) (*RealYouTubeClient, error) {
+	// Con un cliente HTTP propio la biblioteca ignora WithAPIKey, así que la clave la agrega el transporte.
+	httpClient := &http.Client{Transport: &transport.APIKey{Key: apiKey, Transport: base}}
+	service, err := youtube.NewService(context.Background(), option.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("error al crear el servicio de YouTube: %w", err)
+	}
+	return &RealYouTubeClient{service}, nil
+}
+
 func (c *RealYouTubeClient) VideosListCall(ctx context.Context, part []string) VideosListCallWrapper {
 	return &RealVideosListCallWrapper{Call: c.Service.Videos.List(part)}
 }
@@ -52,6 +78,10 @@ func (c *RealYouTubeClient) SearchListCall(ctx context.Context, part []string) S
 	return &RealSearchListCallWrapper{Call: c.Service.Search.List(part)}
 }
 
+func (c *RealYouTubeClient) PlaylistItemsListCall(ctx context.Context, part []string) PlaylistItemsListCallWrapper {
+	return &RealPlaylistItemsListCallWrapper{Call: c.Service.PlaylistItems.List(part).Context(ctx)}
+}
+
 func (r *RealSearchListCallWrapper) Q(q string) SearchListCallWrapper {
 	r.Call.Q(q)
 	return r
@@ -79,3 +109,22 @@ func (r *RealVideosListCallWrapper) Id(id string) VideosListCallWrapper {
 func (r *RealVideosListCallWrapper) Do() (*youtube.VideoListResponse, error) {
 	return r.Call.Do()
 }
+
+func (r *RealPlaylistItemsListCallWrapper) PlaylistId(playlistID string) PlaylistItemsListCallWrapper {
+	r.Call.PlaylistId(playlistID)
+	return r
+}
+
+func (r *RealPlaylistItemsListCallWrapper) MaxResults(maxResults int64) PlaylistItemsListCallWrapper {
+	r.Call.MaxResults(maxResults)
+	return r
+}
+
+func (r *RealPlaylistItemsListCallWrapper) PageToken(pageToken string) PlaylistItemsListCallWrapper {
+	r.Call.PageToken(pageToken)
+	return r
+}
+
+func (r *RealPlaylistItemsListCallWrapper) Do() (*youtube.PlaylistItemListResponse, error) {
+	return r.Call.Do()
+}
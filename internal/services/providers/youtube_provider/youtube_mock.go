@@ -21,6 +21,11 @@ func (m *MockYouTubeClient) SearchListCall(ctx context.Context, part []string) S
 	return args.Get(0).(SearchListCallWrapper)
 }
 
+func (m *MockYouTubeClient) PlaylistItemsListCall(ctx context.Context, part []string) PlaylistItemsListCallWrapper {
+	args := m.Called(ctx, part)
+	return args.Get(0).(PlaylistItemsListCallWrapper)
+}
+
 type MockLogger struct {
 	mock.Mock
 }
@@ -29,6 +34,10 @@ func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
 func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
 	m.Called(msg, fields)
 }
@@ -74,3 +83,27 @@ func (m *SearchListCallWrapperMock) Do() (*youtube.SearchListResponse, error) {
 	args := m.Called()
 	return args.Get(0).(*youtube.SearchListResponse), args.Error(1)
 }
+
+type PlaylistItemsListCallWrapperMock struct {
+	mock.Mock
+}
+
+func (m *PlaylistItemsListCallWrapperMock) PlaylistId(playlistID string) PlaylistItemsListCallWrapper {
+	args := m.Called(playlistID)
+	return args.Get(0).(PlaylistItemsListCallWrapper)
+}
+
+func (m *PlaylistItemsListCallWrapperMock) MaxResults(maxResults int64) PlaylistItemsListCallWrapper {
+	args := m.Called(maxResults)
+	return args.Get(0).(PlaylistItemsListCallWrapper)
+}
+
+func (m *PlaylistItemsListCallWrapperMock) PageToken(pageToken string) PlaylistItemsListCallWrapper {
+	args := m.Called(pageToken)
+	return args.Get(0).(PlaylistItemsListCallWrapper)
+}
+
+func (m *PlaylistItemsListCallWrapperMock) Do() (*youtube.PlaylistItemListResponse, error) {
+	args := m.Called()
+	return args.Get(0).(*youtube.PlaylistItemListResponse), args.Error(1)
+}
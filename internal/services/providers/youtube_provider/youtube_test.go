@@ -96,6 +96,32 @@ func TestSearchVideoID(t *testing.T) {
 	})
 }
 
+func TestSearchVideoIDs(t *testing.T) {
+	clientMock := new(MockYouTubeClient)
+	loggerMock := new(MockLogger)
+	searchCallMock := new(SearchListCallWrapperMock)
+
+	clientMock.On("SearchListCall", mock.Anything, []string{"id"}).Return(searchCallMock)
+	searchCallMock.On("Q", "cumbia music").Return(searchCallMock)
+	searchCallMock.On("MaxResults", int64(3)).Return(searchCallMock)
+	searchCallMock.On("Type", "video").Return(searchCallMock)
+	loggerMock.On("Info", "Buscando videos en YouTube", mock.Anything).Return()
+	searchCallMock.On("Do").Return(&youtube.SearchListResponse{
+		Items: []*youtube.SearchResult{
+			{Id: &youtube.ResourceId{VideoId: "abc"}},
+			{Id: &youtube.ResourceId{}},
+			{Id: &youtube.ResourceId{VideoId: "def"}},
+		},
+	}, nil)
+
+	provider := NewYouTubeProvider("dummyApiKey", loggerMock, clientMock)
+	videoIDs, err := provider.SearchVideoIDs(context.Background(), "cumbia music", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "def"}, videoIDs)
+	searchCallMock.AssertExpectations(t)
+}
+
 func TestGetVideoDetails(t *testing.T) {
 	t.Run("successful get video details", func(t *testing.T) {
 		clientMock := new(MockYouTubeClient)
@@ -175,3 +201,37 @@ func TestGetVideoDetails(t *testing.T) {
 		loggerMock.AssertExpectations(t)
 	})
 }
+
+func TestGetPlaylistItems(t *testing.T) {
+	clientMock := new(MockYouTubeClient)
+	loggerMock := new(MockLogger)
+	firstPage := new(PlaylistItemsListCallWrapperMock)
+	secondPage := new(PlaylistItemsListCallWrapperMock)
+
+	clientMock.On("PlaylistItemsListCall", mock.Anything, []string{"snippet", "contentDetails"}).Return(firstPage).Once()
+	clientMock.On("PlaylistItemsListCall", mock.Anything, []string{"snippet", "contentDetails"}).Return(secondPage).Once()
+	firstPage.On("PlaylistId", "PL1").Return(firstPage)
+	firstPage.On("MaxResults", int64(3)).Return(firstPage)
+	firstPage.On("Do").Return(&youtube.PlaylistItemListResponse{
+		Items:         []*youtube.PlaylistItem{{Id: "a"}, {Id: "b"}},
+		NextPageToken: "page-2",
+	}, nil)
+	secondPage.On("PlaylistId", "PL1").Return(secondPage)
+	secondPage.On("MaxResults", int64(1)).Return(secondPage)
+	secondPage.On("PageToken", "page-2").Return(secondPage)
+	secondPage.On("Do").Return(&youtube.PlaylistItemListResponse{
+		Items:         []*youtube.PlaylistItem{{Id: "c"}},
+		NextPageToken: "page-3",
+	}, nil)
+	loggerMock.On("Info", "Obteniendo los elementos de la playlist desde YouTube", mock.Anything).Return()
+
+	provider := NewYouTubeProvider("dummyApiKey", loggerMock, clientMock)
+	items, err := provider.GetPlaylistItems(context.Background(), "PL1", 3)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 3)
+	assert.Equal(t, "c", items[2].Id)
+	clientMock.AssertExpectations(t)
+	firstPage.AssertExpectations(t)
+	secondPage.AssertExpectations(t)
+}
@@ -0,0 +1,39 @@
+package settings
+
+import "time"
+
+// Valores por defecto del anti-spam.
+const (
+	DefaultSpamWindow      = time.Minute
+	DefaultSpamMaxRepeats  = 3
+	DefaultSpamMaxRequests = 10
+	DefaultSpamCooldown    = 30 * time.Second
+)
+
+// AntiSpam son los límites a los pedidos de canciones de cada usuario en un servidor. Quien pide lo mismo más de
+// MaxRepeats veces, o más de MaxRequests canciones, dentro de Window queda sin poder pedir durante Cooldown; la espera
+// se duplica con cada reincidencia. Los campos en cero usan el valor por defecto.
+type AntiSpam struct {
+	Disabled    bool          `json:"disabled,omitempty"`
+	Window      time.Duration `json:"window,omitempty"`
+	MaxRepeats  int           `json:"max_repeats,omitempty"`
+	MaxRequests int           `json:"max_requests,omitempty"`
+	Cooldown    time.Duration `json:"cooldown,omitempty"`
+}
+
+// Limits devuelve los límites con los valores por defecto en los campos que no se configuraron.
+func (a AntiSpam) Limits() AntiSpam {
+	if a.Window <= 0 {
+		a.Window = DefaultSpamWindow
+	}
+	if a.MaxRepeats <= 0 {
+		a.MaxRepeats = DefaultSpamMaxRepeats
+	}
+	if a.MaxRequests <= 0 {
+		a.MaxRequests = DefaultSpamMaxRequests
+	}
+	if a.Cooldown <= 0 {
+		a.Cooldown = DefaultSpamCooldown
+	}
+	return a
+}
@@ -0,0 +1,19 @@
+package settings
+
+import "time"
+
+// MaxBans es la cantidad máxima de usuarios que se pueden bloquear por servidor.
+const MaxBans = 100
+
+// Ban es un usuario que no puede usar el bot en un servidor.
+type Ban struct {
+	Reason   string    `json:"reason,omitempty"`
+	BannedBy string    `json:"banned_by"`
+	At       time.Time `json:"at"`
+}
+
+// Banned indica si el usuario no puede usar el bot en el servidor, y por qué.
+func (g Guild) Banned(userID string) (Ban, bool) {
+	ban, ok := g.Bans[userID]
+	return ban, ok
+}
@@ -0,0 +1,124 @@
+package settings
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"net/url"
+	"strings"
+)
+
+// BlockKind es el tipo de una entrada de la lista de bloqueo.
+type BlockKind string
+
+const (
+	// BlockVideo bloquea una canción por su ID canónico (el ID del video en YouTube).
+	BlockVideo BlockKind = "video"
+	// BlockChannel bloquea las canciones de un canal, por su ID (UC...) o su nombre.
+	BlockChannel BlockKind = "channel"
+	// BlockKeyword bloquea las canciones cuyo título contiene la palabra, sin distinguir mayúsculas.
+	BlockKeyword BlockKind = "keyword"
+)
+
+// Blocklist son las canciones, canales y palabras que no se pueden agregar a la lista de reproducción de un servidor.
+type Blocklist struct {
+	Videos   []string `json:"videos,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// NormalizeBlock devuelve el valor de una entrada como se guarda y se compara: el ID canónico de un video (acepta
+// la URL o el ID), el ID o el nombre de un canal (acepta la URL youtube.com/channel/...) o la palabra en minúsculas.
+func NormalizeBlock(kind BlockKind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("%w: valor vacío", ErrInvalidValue)
+	}
+	switch kind {
+	case BlockVideo:
+		if strings.Contains(value, "://") {
+			return (&voice.Song{URL: value}).CanonicalID(), nil
+		}
+		return "youtube:" + value, nil
+	case BlockChannel:
+		if u, err := url.Parse(value); err == nil && u.Host != "" {
+			value = strings.Trim(u.Path, "/")
+			value = strings.TrimPrefix(value, "channel/")
+		}
+		return strings.ToLower(value), nil
+	case BlockKeyword:
+		return strings.ToLower(value), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, kind)
+	}
+}
+
+// Add agrega la entrada ya normalizada. Devuelve false si ya estaba.
+func (b *Blocklist) Add(kind BlockKind, value string) bool {
+	entries := b.entries(kind)
+	if entries == nil {
+		return false
+	}
+	for _, entry := range *entries {
+		if entry == value {
+			return false
+		}
+	}
+	*entries = append(*entries, value)
+	return true
+}
+
+// Remove quita la entrada ya normalizada. Devuelve false si no estaba.
+func (b *Blocklist) Remove(kind BlockKind, value string) bool {
+	entries := b.entries(kind)
+	if entries == nil {
+		return false
+	}
+	for i, entry := range *entries {
+		if entry == value {
+			*entries = append((*entries)[:i], (*entries)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Len devuelve la cantidad de entradas.
+func (b Blocklist) Len() int {
+	return len(b.Videos) + len(b.Channels) + len(b.Keywords)
+}
+
+// Match devuelve el motivo por el que la canción está bloqueada, o una cadena vacía si no lo está.
+func (b Blocklist) Match(song *voice.Song) string {
+	id := song.CanonicalID()
+	for _, video := range b.Videos {
+		if video == id {
+			return "la canción está bloqueada en este servidor"
+		}
+	}
+	uploader, uploaderID := strings.ToLower(song.Uploader), strings.ToLower(song.UploaderID)
+	for _, channel := range b.Channels {
+		if (uploaderID != "" && channel == uploaderID) || (uploader != "" && channel == uploader) {
+			return fmt.Sprintf("el canal %s está bloqueado en este servidor", song.Uploader)
+		}
+	}
+	title := strings.ToLower(song.Title)
+	for _, keyword := range b.Keywords {
+		if strings.Contains(title, keyword) {
+			return fmt.Sprintf("el título contiene una palabra bloqueada (%s)", keyword)
+		}
+	}
+	return ""
+}
+
+func (b *Blocklist) entries(kind BlockKind) *[]string {
+	switch kind {
+	case BlockVideo:
+		return &b.Videos
+	case BlockChannel:
+		return &b.Channels
+	case BlockKeyword:
+		return &b.Keywords
+	default:
+		return nil
+	}
+}
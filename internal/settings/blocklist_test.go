@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeBlock(t *testing.T) {
+	tests := []struct {
+		kind  BlockKind
+		value string
+		want  string
+	}{
+		{kind: BlockVideo, value: "https://youtu.be/abc123", want: "youtube:abc123"},
+		{kind: BlockVideo, value: "abc123", want: "youtube:abc123"},
+		{kind: BlockChannel, value: "https://www.youtube.com/channel/UCabc/", want: "ucabc"},
+		{kind: BlockChannel, value: "Canal Trucho", want: "canal trucho"},
+		{kind: BlockKeyword, value: " Remix ", want: "remix"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBlock(tt.kind, tt.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := NormalizeBlock("artist", "x")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	_, err = NormalizeBlock(BlockKeyword, "  ")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestBlocklist_Match(t *testing.T) {
+	var blocklist Blocklist
+	assert.True(t, blocklist.Add(BlockVideo, "youtube:abc123"))
+	assert.False(t, blocklist.Add(BlockVideo, "youtube:abc123"))
+	assert.True(t, blocklist.Add(BlockChannel, "ucabc"))
+	assert.True(t, blocklist.Add(BlockKeyword, "remix"))
+	assert.Equal(t, 3, blocklist.Len())
+
+	assert.NotEmpty(t, blocklist.Match(&voice.Song{URL: "https://www.youtube.com/watch?v=abc123"}))
+	assert.NotEmpty(t, blocklist.Match(&voice.Song{URL: "https://youtu.be/otro", UploaderID: "UCabc"}))
+	assert.NotEmpty(t, blocklist.Match(&voice.Song{URL: "https://youtu.be/otro", Title: "La Bamba (REMIX)"}))
+	assert.Empty(t, blocklist.Match(&voice.Song{URL: "https://youtu.be/otro", Title: "La Bamba"}))
+
+	assert.True(t, blocklist.Remove(BlockKeyword, "remix"))
+	assert.False(t, blocklist.Remove(BlockKeyword, "remix"))
+	assert.Empty(t, blocklist.Match(&voice.Song{URL: "https://youtu.be/otro", Title: "La Bamba (REMIX)"}))
+}
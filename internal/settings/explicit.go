@@ -0,0 +1,82 @@
+package settings
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"strings"
+)
+
+// ExplicitPolicy indica qué hacer con las canciones con contenido explícito.
+type ExplicitPolicy string
+
+const (
+	// ExplicitAllow las agrega sin marcarlas.
+	ExplicitAllow ExplicitPolicy = "allow"
+	// ExplicitTag las agrega y las marca como explícitas en los mensajes del bot.
+	ExplicitTag ExplicitPolicy = "tag"
+	// ExplicitReject no las agrega.
+	ExplicitReject ExplicitPolicy = "reject"
+)
+
+// explicitTitleMarkers son las marcas que suelen llevar en el título las versiones explícitas de las canciones.
+var explicitTitleMarkers = []string{
+	"explicit",
+	"uncensored",
+	"sin censura",
+	"nsfw",
+	"dirty version",
+	"(dirty)",
+	"[dirty]",
+	"+18",
+	"18+",
+}
+
+// ExplicitPolicy devuelve qué hacer con las canciones explícitas; por defecto se agregan sin marcarlas.
+func (g Guild) ExplicitPolicy() ExplicitPolicy {
+	if g.Explicit == "" {
+		return ExplicitAllow
+	}
+	return g.Explicit
+}
+
+// ParseExplicitPolicy valida la política de contenido explícito pedida.
+func ParseExplicitPolicy(value string) (ExplicitPolicy, error) {
+	switch p := ExplicitPolicy(value); p {
+	case ExplicitAllow, ExplicitTag, ExplicitReject:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+}
+
+// IsExplicit indica si la canción tiene contenido explícito, según lo que informa la fuente o, si no lo informa,
+// según las marcas habituales en el título.
+func IsExplicit(song *voice.Song) bool {
+	if song.Explicit {
+		return true
+	}
+	title := strings.ToLower(song.Title)
+	for _, marker := range explicitTitleMarkers {
+		if strings.Contains(title, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagExplicit indica si la canción se tiene que marcar como explícita en los mensajes del servidor.
+func (g Guild) TagExplicit(song *voice.Song) bool {
+	return g.ExplicitPolicy() == ExplicitTag && IsExplicit(song)
+}
+
+// Reject devuelve el motivo por el que la configuración del servidor no deja agregar la canción, o una cadena vacía
+// si se puede agregar.
+func (g Guild) Reject(song *voice.Song) string {
+	if reason := g.Blocklist.Match(song); reason != "" {
+		return reason
+	}
+	if g.ExplicitPolicy() == ExplicitReject && IsExplicit(song) {
+		return "el servidor no permite canciones con contenido explícito"
+	}
+	return ""
+}
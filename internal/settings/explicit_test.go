@@ -0,0 +1,38 @@
+package settings
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsExplicit(t *testing.T) {
+	assert.True(t, IsExplicit(&voice.Song{Title: "Tema", Explicit: true}))
+	assert.True(t, IsExplicit(&voice.Song{Title: "Tema (Explicit Version)"}))
+	assert.True(t, IsExplicit(&voice.Song{Title: "Tema [Sin Censura]"}))
+	assert.False(t, IsExplicit(&voice.Song{Title: "Tema (Radio Edit)"}))
+}
+
+func TestGuild_ExplicitPolicy(t *testing.T) {
+	explicit := &voice.Song{Title: "Tema (Uncensored)"}
+	clean := &voice.Song{Title: "Tema"}
+
+	allow := Guild{}
+	assert.Equal(t, ExplicitAllow, allow.ExplicitPolicy())
+	assert.Empty(t, allow.Reject(explicit))
+	assert.False(t, allow.TagExplicit(explicit))
+
+	tag := Guild{Explicit: ExplicitTag}
+	assert.Empty(t, tag.Reject(explicit))
+	assert.True(t, tag.TagExplicit(explicit))
+	assert.False(t, tag.TagExplicit(clean))
+
+	reject := Guild{Explicit: ExplicitReject, Blocklist: Blocklist{Keywords: []string{"tema"}}}
+	assert.Contains(t, reject.Reject(explicit), "palabra bloqueada")
+	reject.Blocklist = Blocklist{}
+	assert.Contains(t, reject.Reject(explicit), "contenido explícito")
+	assert.Empty(t, reject.Reject(clean))
+
+	_, err := ParseExplicitPolicy("censor")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"sync"
+)
+
+// FileStore implementa Store guardando la configuración de todos los servidores en un archivo JSON.
+type FileStore struct {
+	mu       sync.RWMutex
+	filepath string
+	guilds   map[string]Guild
+}
+
+// NewFileStore crea una nueva instancia de FileStore y carga la configuración del archivo, si existe.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{filepath: path, guilds: make(map[string]Guild)}
+	if err := jsonfile.Load(path, &s.guilds); err != nil {
+		return nil, fmt.Errorf("error al cargar la configuración: %w", err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) Get(guildID string) (Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings, ok := s.guilds[guildID]
+	if !ok {
+		return Guild{GuildID: guildID}, nil
+	}
+	return settings, nil
+}
+
+func (s *FileStore) Put(settings Guild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return jsonfile.Update(s.guilds, settings.GuildID, func() { s.guilds[settings.GuildID] = settings }, s.persist)
+}
+
+// persist guarda la configuración en el archivo. Se llama con mu tomado.
+func (s *FileStore) persist() error {
+	if err := jsonfile.Save(s.filepath, s.guilds); err != nil {
+		return fmt.Errorf("error al guardar la configuración: %w", err)
+	}
+	return nil
+}
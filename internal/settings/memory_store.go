@@ -0,0 +1,33 @@
+package settings
+
+import "sync"
+
+// InMemoryStore implementa Store guardando la configuración en memoria.
+type InMemoryStore struct {
+	mu     sync.RWMutex
+	guilds map[string]Guild
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{guilds: make(map[string]Guild)}
+}
+
+func (s *InMemoryStore) Get(guildID string) (Guild, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings, ok := s.guilds[guildID]
+	if !ok {
+		return Guild{GuildID: guildID}, nil
+	}
+	return settings, nil
+}
+
+func (s *InMemoryStore) Put(settings Guild) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.guilds[settings.GuildID] = settings
+	return nil
+}
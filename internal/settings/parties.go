@@ -0,0 +1,16 @@
+package settings
+
+import "time"
+
+// MaxParties es la cantidad máxima de escuchas grupales programadas por servidor.
+const MaxParties = 10
+
+// Party es una escucha grupal: un evento programado de Discord en un canal de voz que, cuando empieza, reproduce
+// una lista guardada.
+type Party struct {
+	Playlist       string    `json:"playlist"`
+	VoiceChannelID string    `json:"voice_channel_id"`
+	TextChannelID  string    `json:"text_channel_id"` // TextChannelID es el canal en el que se anuncian las canciones.
+	Start          time.Time `json:"start"`
+	CreatedBy      string    `json:"created_by"`
+}
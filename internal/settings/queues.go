@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultQueue es el nombre de la cola con la que arranca cada servidor. No se puede borrar.
+const DefaultQueue = "principal"
+
+// MaxQueues es la cantidad máxima de colas con nombre por servidor, sin contar la principal.
+const MaxQueues = 10
+
+// queueNamePattern son los nombres de cola válidos, ya en minúsculas.
+var queueNamePattern = regexp.MustCompile(`^[\p{Ll}\p{N}][-_ \p{Ll}\p{N}]{0,31}$`)
+
+// Queues son las colas con nombre de un servidor, como "noche de rock" o "tranqui". Una sola está activa y alimenta
+// al reproductor; las canciones de las demás quedan guardadas hasta que se activen.
+type Queues struct {
+	Active string   `json:"active,omitempty"`
+	Names  []string `json:"names,omitempty"`
+}
+
+// ActiveName devuelve el nombre de la cola activa; por defecto es la principal.
+func (q Queues) ActiveName() string {
+	if q.Active == "" {
+		return DefaultQueue
+	}
+	return q.Active
+}
+
+// Has indica si la cola existe. La principal siempre existe.
+func (q Queues) Has(name string) bool {
+	return name == DefaultQueue || contains(q.Names, name)
+}
+
+// All devuelve los nombres de todas las colas, empezando por la principal.
+func (q Queues) All() []string {
+	return append([]string{DefaultQueue}, q.Names...)
+}
+
+// Add crea la cola. Devuelve false si ya existía o si se llegó a MaxQueues.
+func (q *Queues) Add(name string) bool {
+	if q.Has(name) || len(q.Names) >= MaxQueues {
+		return false
+	}
+	q.Names = append(q.Names, name)
+	return true
+}
+
+// Remove borra la cola. Devuelve false si no existía, si es la principal o si es la activa.
+func (q *Queues) Remove(name string) bool {
+	if !contains(q.Names, name) || name == q.ActiveName() {
+		return false
+	}
+	q.Names = without(q.Names, name)
+	return true
+}
+
+// ParseQueueName valida el nombre de una cola y lo devuelve en minúsculas y sin espacios de más.
+func ParseQueueName(value string) (string, error) {
+	name := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	if !queueNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+	return name, nil
+}
@@ -0,0 +1,171 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DuplicatePolicy indica qué hacer cuando se pide una canción que ya está en la lista de reproducción.
+type DuplicatePolicy string
+
+const (
+	// DuplicatesConfirm pregunta a quien la pidió si la agrega igual.
+	DuplicatesConfirm DuplicatePolicy = "confirm"
+	// DuplicatesSkip no la agrega y le avisa a quien la pidió.
+	DuplicatesSkip DuplicatePolicy = "skip"
+	// DuplicatesAllow la agrega sin preguntar.
+	DuplicatesAllow DuplicatePolicy = "allow"
+)
+
+// MaxAliases es la cantidad máxima de alias de comandos por servidor.
+const MaxAliases = 25
+
+// ErrInvalidValue indica que el valor de una configuración no es uno de los soportados.
+var ErrInvalidValue = errors.New("valor de configuración inválido")
+
+// aliasPattern son los nombres que Discord acepta para un comando, en minúsculas.
+var aliasPattern = regexp.MustCompile(`^[-_\p{Ll}\p{N}]{1,32}$`)
+
+type (
+	// Guild es la configuración de un servidor. Los campos vacíos usan el valor por defecto.
+	Guild struct {
+		GuildID    string          `json:"guild_id"`
+		Duplicates DuplicatePolicy `json:"duplicates,omitempty"`
+		Explicit   ExplicitPolicy  `json:"explicit,omitempty"`
+		Blocklist  Blocklist       `json:"blocklist"`
+		AutoJoin   AutoJoin        `json:"auto_join,omitempty"`
+		Timezone   string          `json:"timezone,omitempty"`
+		// VoiceChannels son los canales de voz a los que el bot puede entrar.
+		VoiceChannels VoiceChannels `json:"voice_channels"`
+		// AntiSpam son los límites a los pedidos repetidos o en ráfaga de cada usuario.
+		AntiSpam AntiSpam `json:"anti_spam"`
+		// Queues son las colas con nombre del servidor y cuál está activa.
+		Queues Queues `json:"queues"`
+		// Parties son las escuchas grupales programadas, por ID del evento de Discord.
+		Parties map[string]Party `json:"parties,omitempty"`
+		// MaxVolume es el volumen máximo de la reproducción, en porcentaje del original; con 0 no hay tope.
+		MaxVolume int `json:"max_volume,omitempty"`
+		// VoteSkip es el porcentaje de las personas del canal de voz que tienen que votar con /skip para saltar la
+		// canción; con 0 cualquiera la salta.
+		VoteSkip int `json:"vote_skip,omitempty"`
+		// DJRole es el ID del rol que puede detener la reproducción, eliminar canciones y saltarlas sin votar; sin
+		// rol cualquiera puede.
+		DJRole string `json:"dj_role,omitempty"`
+		// Bans son los usuarios que no pueden usar el bot, por ID.
+		Bans map[string]Ban `json:"bans,omitempty"`
+		// Aliases son los alias de comandos del servidor: el nombre del alias y el subcomando al que apunta, como
+		// "play" o "playlist save".
+		Aliases map[string]string `json:"aliases,omitempty"`
+	}
+
+	// AutoJoin es el canal de voz al que el bot entra solo cuando alguien se conecta, y la lista guardada que
+	// reproduce. Sin canal está deshabilitado.
+	AutoJoin struct {
+		ChannelID     string `json:"channel_id,omitempty"`
+		TextChannelID string `json:"text_channel_id,omitempty"` // TextChannelID es el canal en el que se anuncian las canciones.
+		Playlist      string `json:"playlist,omitempty"`
+	}
+
+	// Store guarda la configuración de los servidores.
+	Store interface {
+		// Get devuelve la configuración del servidor; si no tiene, la devuelve vacía.
+		Get(guildID string) (Guild, error)
+		// Put guarda la configuración del servidor.
+		Put(settings Guild) error
+	}
+)
+
+// DuplicatePolicy devuelve qué hacer con las canciones repetidas; por defecto se pregunta.
+func (g Guild) DuplicatePolicy() DuplicatePolicy {
+	if g.Duplicates == "" {
+		return DuplicatesConfirm
+	}
+	return g.Duplicates
+}
+
+// ParseDuplicatePolicy valida la política de canciones repetidas pedida.
+func ParseDuplicatePolicy(value string) (DuplicatePolicy, error) {
+	switch p := DuplicatePolicy(value); p {
+	case DuplicatesConfirm, DuplicatesSkip, DuplicatesAllow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+}
+
+// CapVolume limita el volumen pedido al volumen máximo del servidor.
+func (g Guild) CapVolume(volume int) int {
+	if g.MaxVolume > 0 && volume > g.MaxVolume {
+		return g.MaxVolume
+	}
+	return volume
+}
+
+// SkipVotesNeeded devuelve cuántos votos hacen falta para saltar la canción con listeners personas en el canal de
+// voz. Sin votación alcanza con uno.
+func (g Guild) SkipVotesNeeded(listeners int) int {
+	if g.VoteSkip <= 0 {
+		return 1
+	}
+	return max((listeners*g.VoteSkip+99)/100, 1)
+}
+
+// HasDJRole indica si entre los roles está el rol de DJ del servidor.
+func (g Guild) HasDJRole(roles []string) bool {
+	return g.DJRole != "" && slices.Contains(roles, g.DJRole)
+}
+
+// Location devuelve la zona horaria del servidor, en la que se interpretan las horas de las alarmas y las
+// reproducciones programadas. Si no tiene, devuelve fallback.
+func (g Guild) Location(fallback *time.Location) *time.Location {
+	if g.Timezone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// ParseTimezone valida la zona horaria pedida, con el nombre de la base de datos IANA (por ejemplo,
+// "America/Argentina/Buenos_Aires"), y la devuelve con el nombre canónico.
+func ParseTimezone(value string) (string, error) {
+	loc, err := time.LoadLocation(value)
+	if err != nil || value == "" || value == "Local" {
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+	return loc.String(), nil
+}
+
+// ParseAlias valida el nombre de un alias de comando y lo devuelve en minúsculas, sin la barra inicial.
+func ParseAlias(value string) (string, error) {
+	alias := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "/"))
+	if !aliasPattern.MatchString(alias) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+	return alias, nil
+}
+
+// Update lee la configuración del servidor, le aplica update y la guarda.
+func Update(store Store, guildID string, update func(*Guild)) (Guild, error) {
+	settings, err := store.Get(guildID)
+	if err != nil {
+		return Guild{}, err
+	}
+	settings.GuildID = guildID
+	update(&settings)
+	if err := store.Put(settings); err != nil {
+		return Guild{}, err
+	}
+	return settings, nil
+}
+
+// Enabled indica si el servidor tiene configurado un canal para entrar solo.
+func (a AutoJoin) Enabled() bool {
+	return a.ChannelID != "" && a.Playlist != ""
+}
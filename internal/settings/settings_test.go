@@ -0,0 +1,166 @@
+package settings
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuild_DuplicatePolicy(t *testing.T) {
+	assert.Equal(t, DuplicatesConfirm, Guild{}.DuplicatePolicy())
+	assert.Equal(t, DuplicatesSkip, Guild{Duplicates: DuplicatesSkip}.DuplicatePolicy())
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	policy, err := ParseDuplicatePolicy("allow")
+	assert.NoError(t, err)
+	assert.Equal(t, DuplicatesAllow, policy)
+
+	_, err = ParseDuplicatePolicy("never")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestParseAlias(t *testing.T) {
+	alias, err := ParseAlias(" /NP ")
+	require.NoError(t, err)
+	assert.Equal(t, "np", alias)
+
+	for _, value := range []string{"", "/", "now playing", "np!", strings.Repeat("a", 33)} {
+		_, err := ParseAlias(value)
+		assert.ErrorIs(t, err, ErrInvalidValue, value)
+	}
+}
+
+func TestAutoJoin_Enabled(t *testing.T) {
+	assert.False(t, AutoJoin{}.Enabled())
+	assert.False(t, AutoJoin{ChannelID: "voz"}.Enabled())
+	assert.True(t, AutoJoin{ChannelID: "voz", Playlist: "fiesta"}.Enabled())
+}
+
+func TestGuild_SkipVotesNeeded(t *testing.T) {
+	assert.Equal(t, 1, Guild{}.SkipVotesNeeded(10))
+	assert.Equal(t, 2, Guild{VoteSkip: 50}.SkipVotesNeeded(3))
+	assert.Equal(t, 2, Guild{VoteSkip: 50}.SkipVotesNeeded(4))
+	assert.Equal(t, 4, Guild{VoteSkip: 100}.SkipVotesNeeded(4))
+	assert.Equal(t, 1, Guild{VoteSkip: 50}.SkipVotesNeeded(0))
+}
+
+func TestGuild_HasDJRole(t *testing.T) {
+	assert.False(t, Guild{}.HasDJRole([]string{"dj"}))
+	assert.False(t, Guild{DJRole: "dj"}.HasDJRole([]string{"otro"}))
+	assert.True(t, Guild{DJRole: "dj"}.HasDJRole([]string{"otro", "dj"}))
+}
+
+func TestGuild_CapVolume(t *testing.T) {
+	assert.Equal(t, 150, Guild{}.CapVolume(150))
+	assert.Equal(t, 80, Guild{MaxVolume: 100}.CapVolume(80))
+	assert.Equal(t, 100, Guild{MaxVolume: 100}.CapVolume(150))
+}
+
+func TestGuild_Location(t *testing.T) {
+	assert.Equal(t, time.UTC, Guild{}.Location(time.UTC))
+	assert.Equal(t, "America/Argentina/Buenos_Aires", Guild{Timezone: "America/Argentina/Buenos_Aires"}.Location(time.UTC).String())
+}
+
+func TestParseTimezone(t *testing.T) {
+	timezone, err := ParseTimezone("Europe/Madrid")
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Madrid", timezone)
+
+	for _, value := range []string{"", "Local", "Marte/Olympus"} {
+		_, err = ParseTimezone(value)
+		assert.ErrorIs(t, err, ErrInvalidValue, value)
+	}
+}
+
+func TestFileStore_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings", "settings.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	settings, err := store.Get("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, Guild{GuildID: "guild-1"}, settings)
+
+	_, err = Update(store, "guild-1", func(g *Guild) { g.Duplicates = DuplicatesSkip })
+	require.NoError(t, err)
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	settings, err = reopened.Get("guild-1")
+	require.NoError(t, err)
+	assert.Equal(t, DuplicatesSkip, settings.DuplicatePolicy())
+}
+
+func TestVoiceChannels(t *testing.T) {
+	var channels VoiceChannels
+	assert.True(t, channels.Allows("general"), "sin reglas puede entrar a cualquier canal")
+
+	assert.True(t, channels.Deny("afk"))
+	assert.False(t, channels.Deny("afk"))
+	assert.False(t, channels.Allows("afk"))
+	assert.True(t, channels.Allows("general"))
+
+	// Con canales permitidos solo puede entrar a esos.
+	assert.True(t, channels.Allow("musica"))
+	assert.True(t, channels.Allows("musica"))
+	assert.False(t, channels.Allows("general"))
+
+	// Permitir un canal prohibido lo saca de los prohibidos.
+	assert.True(t, channels.Allow("afk"))
+	assert.Empty(t, channels.Denied)
+	assert.True(t, channels.Allows("afk"))
+
+	assert.True(t, channels.Reset("musica"))
+	assert.True(t, channels.Reset("afk"))
+	assert.False(t, channels.Reset("afk"))
+	assert.True(t, channels.Allows("general"))
+}
+
+func TestAntiSpamLimits(t *testing.T) {
+	limits := AntiSpam{MaxRepeats: 5, Cooldown: time.Minute}.Limits()
+
+	assert.Equal(t, DefaultSpamWindow, limits.Window)
+	assert.Equal(t, 5, limits.MaxRepeats)
+	assert.Equal(t, DefaultSpamMaxRequests, limits.MaxRequests)
+	assert.Equal(t, time.Minute, limits.Cooldown)
+}
+
+func TestQueues(t *testing.T) {
+	var queues Queues
+	assert.Equal(t, DefaultQueue, queues.ActiveName())
+	assert.True(t, queues.Has(DefaultQueue))
+	assert.False(t, queues.Add(DefaultQueue))
+
+	assert.True(t, queues.Add("noche de rock"))
+	assert.False(t, queues.Add("noche de rock"))
+	assert.Equal(t, []string{DefaultQueue, "noche de rock"}, queues.All())
+
+	// No se puede borrar la principal ni la activa.
+	queues.Active = "noche de rock"
+	assert.False(t, queues.Remove("noche de rock"))
+	assert.False(t, queues.Remove(DefaultQueue))
+	queues.Active = ""
+	assert.True(t, queues.Remove("noche de rock"))
+	assert.False(t, queues.Has("noche de rock"))
+
+	for i := 0; i < MaxQueues; i++ {
+		assert.True(t, queues.Add(fmt.Sprintf("cola %d", i)))
+	}
+	assert.False(t, queues.Add("una más"))
+}
+
+func TestParseQueueName(t *testing.T) {
+	name, err := ParseQueueName("  Noche   de ROCK ")
+	require.NoError(t, err)
+	assert.Equal(t, "noche de rock", name)
+
+	for _, value := range []string{"", "   ", "-rock", "rock/pop", strings.Repeat("a", 33)} {
+		_, err := ParseQueueName(value)
+		assert.ErrorIs(t, err, ErrInvalidValue, value)
+	}
+}
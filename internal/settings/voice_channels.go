@@ -0,0 +1,63 @@
+package settings
+
+// VoiceChannels son los canales de voz a los que el bot puede entrar en un servidor. Si Allowed no está vacía, solo
+// puede entrar a esos; nunca entra a los de Denied, como el canal AFK o uno del staff.
+type VoiceChannels struct {
+	Allowed []string `json:"allowed,omitempty"`
+	Denied  []string `json:"denied,omitempty"`
+}
+
+// Allows indica si el bot puede entrar al canal de voz.
+func (v VoiceChannels) Allows(channelID string) bool {
+	if contains(v.Denied, channelID) {
+		return false
+	}
+	return len(v.Allowed) == 0 || contains(v.Allowed, channelID)
+}
+
+// Allow agrega el canal a los permitidos y lo saca de los prohibidos. Devuelve false si ya estaba permitido.
+func (v *VoiceChannels) Allow(channelID string) bool {
+	v.Denied = without(v.Denied, channelID)
+	if contains(v.Allowed, channelID) {
+		return false
+	}
+	v.Allowed = append(v.Allowed, channelID)
+	return true
+}
+
+// Deny agrega el canal a los prohibidos y lo saca de los permitidos. Devuelve false si ya estaba prohibido.
+func (v *VoiceChannels) Deny(channelID string) bool {
+	v.Allowed = without(v.Allowed, channelID)
+	if contains(v.Denied, channelID) {
+		return false
+	}
+	v.Denied = append(v.Denied, channelID)
+	return true
+}
+
+// Reset saca el canal de las dos listas. Devuelve false si no estaba en ninguna.
+func (v *VoiceChannels) Reset(channelID string) bool {
+	found := contains(v.Allowed, channelID) || contains(v.Denied, channelID)
+	v.Allowed = without(v.Allowed, channelID)
+	v.Denied = without(v.Denied, channelID)
+	return found
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func without(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,311 @@
+// Package spotify lee canciones, álbumes y listas de reproducción públicas de la API web de Spotify, con las
+// credenciales de una aplicación (client credentials). El audio no sale de Spotify: las canciones se buscan después en YouTube.
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIURL es la API web de Spotify.
+	DefaultAPIURL = "https://api.spotify.com/v1"
+	// DefaultTokenURL es el endpoint que entrega los tokens de acceso de la aplicación.
+	DefaultTokenURL = "https://accounts.spotify.com/api/token"
+	// requestTimeout es el tiempo máximo de cada pedido a Spotify.
+	requestTimeout = 10 * time.Second
+	// tokenMargin es cuánto antes de que venza se renueva el token, para no usarlo justo cuando expira.
+	tokenMargin = time.Minute
+	// pageSize es la cantidad de canciones que se piden por página; es el máximo que acepta Spotify.
+	pageSize = 100
+)
+
+var (
+	// ErrInvalidPlaylist indica que el texto no es un link, URI ni ID de una lista de Spotify.
+	ErrInvalidPlaylist = errors.New("no es una lista de Spotify")
+	// ErrNotFound indica que la lista no existe o no es pública.
+	ErrNotFound = errors.New("la lista de Spotify no existe o no es pública")
+	// ErrInvalidLink indica que el texto no es un link ni una URI de una canción, álbum o lista de Spotify.
+	ErrInvalidLink = errors.New("no es un link de Spotify")
+
+	playlistIDPattern = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+)
+
+// Tipos de link de Spotify que se pueden reproducir.
+const (
+	KindTrack    = "track"
+	KindAlbum    = "album"
+	KindPlaylist = "playlist"
+)
+
+type (
+	// Playlist es una lista de reproducción de Spotify.
+	Playlist struct {
+		ID   string
+		Name string
+	}
+
+	// Track es una canción de una lista de Spotify y el momento en que se agregó a la lista.
+	Track struct {
+		ID         string
+		Name       string
+		Artists    []string
+		DurationMs int64
+		AddedAt    time.Time
+	}
+)
+
+// Query devuelve la búsqueda con la que se encuentra la canción en YouTube, como "Artista - Título".
+func (t Track) Query() string {
+	if len(t.Artists) == 0 {
+		return t.Name
+	}
+	return strings.Join(t.Artists, ", ") + " - " + t.Name
+}
+
+// ParsePlaylistID devuelve el ID de la lista a partir de un link (https://open.spotify.com/playlist/...), una URI
+// (spotify:playlist:...) o el ID.
+func ParsePlaylistID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if id, ok := strings.CutPrefix(input, "spotify:playlist:"); ok {
+		input = id
+	} else if u, err := url.Parse(input); err == nil && u.Host == "open.spotify.com" {
+		// Los links pueden tener un prefijo de idioma, como /intl-es/playlist/....
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[len(parts)-2] != "playlist" {
+			return "", ErrInvalidPlaylist
+		}
+		input = parts[len(parts)-1]
+	}
+	if !playlistIDPattern.MatchString(input) {
+		return "", ErrInvalidPlaylist
+	}
+	return input, nil
+}
+
+// Link es un link a una canción, un álbum o una lista de Spotify.
+type Link struct {
+	Kind string
+	ID   string
+}
+
+// ParseLink reconoce los links (https://open.spotify.com/track/...) y las URIs (spotify:album:...) de canciones,
+// álbumes y listas. A diferencia de ParsePlaylistID, no acepta un ID suelto, que no se distingue de una búsqueda.
+func ParseLink(input string) (Link, error) {
+	input = strings.TrimSpace(input)
+	var parts []string
+	if uri, ok := strings.CutPrefix(input, "spotify:"); ok {
+		parts = strings.Split(uri, ":")
+	} else if u, err := url.Parse(input); err == nil && u.Host == "open.spotify.com" {
+		parts = strings.Split(strings.Trim(u.Path, "/"), "/")
+	}
+	if len(parts) < 2 {
+		return Link{}, ErrInvalidLink
+	}
+	link := Link{Kind: parts[len(parts)-2], ID: parts[len(parts)-1]}
+	switch link.Kind {
+	case KindTrack, KindAlbum, KindPlaylist:
+	default:
+		return Link{}, ErrInvalidLink
+	}
+	if !playlistIDPattern.MatchString(link.ID) {
+		return Link{}, ErrInvalidLink
+	}
+	return link, nil
+}
+
+// Client lee listas de la API web de Spotify. Es seguro usarlo desde varias goroutines.
+type Client struct {
+	clientID     string
+	clientSecret string
+	client       *http.Client
+	apiURL       string
+	tokenURL     string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewClient crea un Client con las credenciales de una aplicación de Spotify.
+func NewClient(clientID, clientSecret string) *Client {
+	return &Client{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: requestTimeout},
+		apiURL:       DefaultAPIURL,
+		tokenURL:     DefaultTokenURL,
+	}
+}
+
+// Playlist devuelve el nombre de la lista, o ErrNotFound si no existe o no es pública.
+func (c *Client) Playlist(ctx context.Context, id string) (Playlist, error) {
+	var response struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.get(ctx, c.apiURL+"/playlists/"+url.PathEscape(id)+"?fields=id,name", &response); err != nil {
+		return Playlist{}, err
+	}
+	return Playlist{ID: response.ID, Name: response.Name}, nil
+}
+
+// playlistTracksResponse es una página de las canciones de una lista.
+type playlistTracksResponse struct {
+	Items []struct {
+		AddedAt time.Time      `json:"added_at"`
+		Track   *trackResponse `json:"track"`
+	} `json:"items"`
+	Next string `json:"next"`
+}
+
+// PlaylistTracks devuelve todas las canciones de la lista, en el orden de la lista. Los episodios de podcasts y
+// las canciones que ya no están disponibles se omiten.
+func (c *Client) PlaylistTracks(ctx context.Context, id string) ([]Track, error) {
+	fields := "items(added_at,track(id,name,duration_ms,artists(name))),next"
+	next := fmt.Sprintf("%s/playlists/%s/tracks?limit=%d&additional_types=track&fields=%s", c.apiURL, url.PathEscape(id), pageSize, url.QueryEscape(fields))
+	var tracks []Track
+	for next != "" {
+		var page playlistTracksResponse
+		if err := c.get(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			if item.Track == nil || item.Track.ID == "" {
+				continue
+			}
+			track := item.Track.track()
+			track.AddedAt = item.AddedAt
+			tracks = append(tracks, track)
+		}
+		next = page.Next
+	}
+	return tracks, nil
+}
+
+// trackResponse es una canción de la API, como la devuelven los endpoints de canciones y de álbumes.
+type trackResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	DurationMs int64  `json:"duration_ms"`
+	Artists    []struct {
+		Name string `json:"name"`
+	} `json:"artists"`
+}
+
+func (t trackResponse) track() Track {
+	track := Track{ID: t.ID, Name: t.Name, DurationMs: t.DurationMs}
+	for _, artist := range t.Artists {
+		track.Artists = append(track.Artists, artist.Name)
+	}
+	return track
+}
+
+// Track devuelve la canción con ese ID, o ErrNotFound si no existe.
+func (c *Client) Track(ctx context.Context, id string) (Track, error) {
+	var response trackResponse
+	if err := c.get(ctx, c.apiURL+"/tracks/"+url.PathEscape(id), &response); err != nil {
+		return Track{}, err
+	}
+	return response.track(), nil
+}
+
+// AlbumTracks devuelve todas las canciones del álbum, en orden, o ErrNotFound si no existe.
+func (c *Client) AlbumTracks(ctx context.Context, id string) ([]Track, error) {
+	next := fmt.Sprintf("%s/albums/%s/tracks?limit=50", c.apiURL, url.PathEscape(id))
+	var tracks []Track
+	for next != "" {
+		var page struct {
+			Items []trackResponse `json:"items"`
+			Next  string          `json:"next"`
+		}
+		if err := c.get(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			tracks = append(tracks, item.track())
+		}
+		next = page.Next
+	}
+	return tracks, nil
+}
+
+// get pide endpoint a la API con el token de la aplicación y decodifica la respuesta en v.
+func (c *Client) get(ctx context.Context, endpoint string, v any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error al crear el pedido a Spotify: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al consultar Spotify: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		return ErrNotFound
+	case http.StatusUnauthorized:
+		// El token se revocó antes de vencer; el próximo pedido pide otro.
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+		return fmt.Errorf("Spotify rechazó el token de acceso")
+	default:
+		return fmt.Errorf("Spotify respondió con el estado %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error al leer la respuesta de Spotify: %w", err)
+	}
+	return nil
+}
+
+// accessToken devuelve el token de la aplicación, y pide uno nuevo si no hay o está por vencer.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error al crear el pedido del token de Spotify: %w", err)
+	}
+	req.SetBasicAuth(c.clientID, c.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error al pedir el token de Spotify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Spotify rechazó las credenciales de la aplicación: estado %d", resp.StatusCode)
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("error al leer el token de Spotify: %w", err)
+	}
+	c.token = response.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - tokenMargin)
+	return c.token, nil
+}
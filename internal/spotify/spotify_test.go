@@ -0,0 +1,152 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testPlaylistID = "37i9dQZF1DXcBWIGoYBM5M"
+
+func TestParsePlaylistID(t *testing.T) {
+	for _, input := range []string{
+		testPlaylistID,
+		"spotify:playlist:" + testPlaylistID,
+		"https://open.spotify.com/playlist/" + testPlaylistID + "?si=abc123",
+		"https://open.spotify.com/intl-es/playlist/" + testPlaylistID,
+	} {
+		id, err := ParsePlaylistID(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, testPlaylistID, id, input)
+	}
+
+	for _, input := range []string{"", "hola", "https://open.spotify.com/album/" + testPlaylistID, "https://youtube.com/playlist?list=PL123"} {
+		_, err := ParsePlaylistID(input)
+		assert.ErrorIs(t, err, ErrInvalidPlaylist, input)
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	for input, expected := range map[string]Link{
+		"https://open.spotify.com/track/" + testPlaylistID + "?si=abc123": {Kind: KindTrack, ID: testPlaylistID},
+		"https://open.spotify.com/intl-es/album/" + testPlaylistID:        {Kind: KindAlbum, ID: testPlaylistID},
+		"spotify:playlist:" + testPlaylistID:                              {Kind: KindPlaylist, ID: testPlaylistID},
+		"  https://open.spotify.com/playlist/" + testPlaylistID + "  ":    {Kind: KindPlaylist, ID: testPlaylistID},
+	} {
+		link, err := ParseLink(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, link, input)
+	}
+
+	for _, input := range []string{testPlaylistID, "la bamba", "https://open.spotify.com/artist/" + testPlaylistID, "spotify:track:corto", "https://youtube.com/watch?v=1"} {
+		_, err := ParseLink(input)
+		assert.ErrorIs(t, err, ErrInvalidLink, input)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *int) {
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "id", user)
+		assert.Equal(t, "secreto", pass)
+		tokens++
+		_, _ = fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		handler(w, r)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := NewClient("id", "secreto")
+	client.apiURL = server.URL + "/v1"
+	client.tokenURL = server.URL + "/token"
+	return client, &tokens
+}
+
+func TestClient_PlaylistTracks(t *testing.T) {
+	var client *Client
+	client, tokens := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			_, _ = fmt.Fprintf(w, `{"items":[
+				{"added_at":"2024-05-01T10:00:00Z","track":{"id":"a","name":"La Bamba","duration_ms":180000,"artists":[{"name":"Ritchie Valens"}]}},
+				{"added_at":"2024-05-02T10:00:00Z","track":null}
+			],"next":"%s/playlists/%s/tracks?offset=100"}`, client.apiURL, testPlaylistID)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"items":[
+			{"added_at":"2024-05-03T10:00:00Z","track":{"id":"b","name":"Zamba","duration_ms":200000,"artists":[{"name":"A"},{"name":"B"}]}}
+		],"next":null}`)
+	})
+
+	tracks, err := client.PlaylistTracks(context.Background(), testPlaylistID)
+
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "Ritchie Valens - La Bamba", tracks[0].Query())
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), tracks[0].AddedAt)
+	assert.Equal(t, "A, B - Zamba", tracks[1].Query())
+	assert.Equal(t, 1, *tokens, "el token se reutiliza entre páginas")
+}
+
+func TestClient_PlaylistNotFound(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.Playlist(context.Background(), testPlaylistID)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Playlist(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/playlists/"+testPlaylistID, r.URL.Path)
+		_, _ = fmt.Fprintf(w, `{"id":"%s","name":"Folklore"}`, testPlaylistID)
+	})
+
+	playlist, err := client.Playlist(context.Background(), testPlaylistID)
+
+	require.NoError(t, err)
+	assert.Equal(t, Playlist{ID: testPlaylistID, Name: "Folklore"}, playlist)
+}
+
+func TestClient_Track(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/tracks/a", r.URL.Path)
+		_, _ = fmt.Fprint(w, `{"id":"a","name":"La Bamba","duration_ms":180000,"artists":[{"name":"Ritchie Valens"}]}`)
+	})
+
+	track, err := client.Track(context.Background(), "a")
+
+	require.NoError(t, err)
+	assert.Equal(t, Track{ID: "a", Name: "La Bamba", Artists: []string{"Ritchie Valens"}, DurationMs: 180000}, track)
+}
+
+func TestClient_AlbumTracks(t *testing.T) {
+	var client *Client
+	client, _ = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/albums/album/tracks", r.URL.Path)
+		if r.URL.Query().Get("offset") == "" {
+			_, _ = fmt.Fprintf(w, `{"items":[{"id":"a","name":"Uno","artists":[{"name":"A"}]}],"next":"%s/albums/album/tracks?offset=50"}`, client.apiURL)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"items":[{"id":"b","name":"Dos","artists":[{"name":"A"}]}],"next":null}`)
+	})
+
+	tracks, err := client.AlbumTracks(context.Background(), "album")
+
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "A - Uno", tracks[0].Query())
+	assert.Equal(t, "A - Dos", tracks[1].Query())
+}
@@ -0,0 +1,79 @@
+package spotifysync
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"sync"
+	"time"
+)
+
+// FileStore implementa Store guardando las listas vinculadas de todos los servidores en un archivo JSON.
+type FileStore struct {
+	mu       sync.RWMutex
+	filepath string
+	links    map[string]Link
+}
+
+// NewFileStore crea una nueva instancia de FileStore y carga las listas vinculadas del archivo, si existe.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{filepath: path, links: make(map[string]Link)}
+	if err := jsonfile.Load(path, &s.links); err != nil {
+		return nil, fmt.Errorf("error al cargar las listas de Spotify: %w", err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) Put(link Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := linkKey(link.GuildID, link.PlaylistID)
+	return jsonfile.Update(s.links, key, func() { s.links[key] = link }, s.persist)
+}
+
+func (s *FileStore) List(guildID string) ([]Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listLinks(s.links, guildID), nil
+}
+
+func (s *FileStore) All() ([]Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listLinks(s.links, ""), nil
+}
+
+func (s *FileStore) Delete(guildID, playlistID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := linkKey(guildID, playlistID)
+	if _, ok := s.links[key]; !ok {
+		return ErrNotFound
+	}
+	return jsonfile.Update(s.links, key, func() { delete(s.links, key) }, s.persist)
+}
+
+func (s *FileStore) MarkSynced(guildID, playlistID string, lastAddedAt, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := linkKey(guildID, playlistID)
+	link, ok := s.links[key]
+	if !ok {
+		return ErrNotFound
+	}
+	link.LastAddedAt = lastAddedAt
+	link.LastSync = at
+	return jsonfile.Update(s.links, key, func() { s.links[key] = link }, s.persist)
+}
+
+// persist guarda las listas de Spotify en el archivo. Se llama con mu tomado.
+func (s *FileStore) persist() error {
+	if err := jsonfile.Save(s.filepath, s.links); err != nil {
+		return fmt.Errorf("error al guardar las listas de Spotify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package spotifysync
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryStore implementa Store guardando las listas vinculadas en memoria.
+type InMemoryStore struct {
+	mu    sync.RWMutex
+	links map[string]Link
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{links: make(map[string]Link)}
+}
+
+func (s *InMemoryStore) Put(link Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.links[linkKey(link.GuildID, link.PlaylistID)] = link
+	return nil
+}
+
+func (s *InMemoryStore) List(guildID string) ([]Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listLinks(s.links, guildID), nil
+}
+
+func (s *InMemoryStore) All() ([]Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listLinks(s.links, ""), nil
+}
+
+func (s *InMemoryStore) Delete(guildID, playlistID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := linkKey(guildID, playlistID)
+	if _, ok := s.links[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.links, key)
+	return nil
+}
+
+func (s *InMemoryStore) MarkSynced(guildID, playlistID string, lastAddedAt, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := linkKey(guildID, playlistID)
+	link, ok := s.links[key]
+	if !ok {
+		return ErrNotFound
+	}
+	link.LastAddedAt = lastAddedAt
+	link.LastSync = at
+	s.links[key] = link
+	return nil
+}
+
+func linkKey(guildID, playlistID string) string {
+	return guildID + "/" + playlistID
+}
+
+// listLinks devuelve las listas vinculadas al servidor, o a todos si guildID está vacío, ordenadas por fecha de
+// creación.
+func listLinks(links map[string]Link, guildID string) []Link {
+	list := make([]Link, 0)
+	for _, link := range links {
+		if guildID == "" || link.GuildID == guildID {
+			list = append(list, link)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
@@ -0,0 +1,38 @@
+package spotifysync
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockPlaylists struct {
+	mock.Mock
+}
+
+func (m *MockPlaylists) PlaylistTracks(ctx context.Context, id string) ([]spotify.Track, error) {
+	args := m.Called(ctx, id)
+	tracks, _ := args.Get(0).([]spotify.Track)
+	return tracks, args.Error(1)
+}
@@ -0,0 +1,169 @@
+// Package spotifysync sigue listas de Spotify vinculadas a los servidores: cada cierto tiempo busca las canciones
+// que se agregaron a cada lista y las entrega para que se sumen a una lista guardada o a la cola.
+package spotifysync
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"go.uber.org/zap"
+	"time"
+)
+
+// MaxLinksPerGuild es la cantidad máxima de listas de Spotify vinculadas por servidor.
+const MaxLinksPerGuild = 10
+
+var (
+	// ErrNotFound indica que la lista no está vinculada al servidor.
+	ErrNotFound = errors.New("la lista de Spotify no está vinculada")
+	// ErrTooMany indica que el servidor llegó a MaxLinksPerGuild.
+	ErrTooMany = errors.New("el servidor tiene demasiadas listas de Spotify vinculadas")
+	// ErrNotReady indica que el destino todavía no puede recibir las canciones, por ejemplo porque el bot no está en
+	// un canal de voz; se vuelven a entregar en la próxima sincronización sin registrarlo como error.
+	ErrNotReady = errors.New("el destino de la lista de Spotify no está disponible")
+)
+
+type (
+	// Link es una lista de Spotify vinculada a un servidor. Las canciones que se agregan a la lista después de
+	// LastAddedAt se suman a la lista guardada Target o, si está vacío, a la cola, y se anuncian en ChannelID.
+	Link struct {
+		GuildID     string    `json:"guild_id"`
+		PlaylistID  string    `json:"playlist_id"`
+		Name        string    `json:"name"`
+		Target      string    `json:"target,omitempty"`
+		ChannelID   string    `json:"channel_id"`
+		CreatedBy   string    `json:"created_by"`
+		CreatedAt   time.Time `json:"created_at"`
+		LastAddedAt time.Time `json:"last_added_at"`
+		LastSync    time.Time `json:"last_sync"`
+	}
+
+	// Store guarda las listas vinculadas de todos los servidores.
+	Store interface {
+		// Put crea o reemplaza la lista vinculada con el mismo servidor e ID de Spotify.
+		Put(link Link) error
+		// List devuelve las listas vinculadas al servidor ordenadas por fecha de creación.
+		List(guildID string) ([]Link, error)
+		// All devuelve las listas vinculadas de todos los servidores.
+		All() ([]Link, error)
+		// Delete desvincula la lista del servidor, o devuelve ErrNotFound.
+		Delete(guildID, playlistID string) error
+		// MarkSynced guarda hasta qué canción se sincronizó la lista y cuándo, o devuelve ErrNotFound si se
+		// desvinculó.
+		MarkSynced(guildID, playlistID string, lastAddedAt, at time.Time) error
+	}
+
+	// Playlists son las listas de Spotify; lo implementa spotify.Client.
+	Playlists interface {
+		PlaylistTracks(ctx context.Context, id string) ([]spotify.Track, error)
+	}
+
+	// SyncFunc agrega las canciones nuevas de una lista vinculada y las anuncia. Si devuelve un error, las mismas
+	// canciones se vuelven a entregar en la próxima sincronización.
+	SyncFunc func(ctx context.Context, link Link, tracks []spotify.Track) error
+)
+
+// Add guarda la lista vinculada si el servidor no llegó al máximo. Si la lista ya estaba vinculada, la reemplaza.
+func Add(store Store, link Link) error {
+	existing, err := store.List(link.GuildID)
+	if err != nil {
+		return err
+	}
+	linked := false
+	for _, l := range existing {
+		linked = linked || l.PlaylistID == link.PlaylistID
+	}
+	if !linked && len(existing) >= MaxLinksPerGuild {
+		return ErrTooMany
+	}
+	return store.Put(link)
+}
+
+// NewTracks devuelve las canciones agregadas a la lista después de since, en el orden de la lista, y el momento en
+// que se agregó la última.
+func NewTracks(tracks []spotify.Track, since time.Time) ([]spotify.Track, time.Time) {
+	var added []spotify.Track
+	latest := since
+	for _, track := range tracks {
+		if !track.AddedAt.After(since) {
+			continue
+		}
+		added = append(added, track)
+		if track.AddedAt.After(latest) {
+			latest = track.AddedAt
+		}
+	}
+	return added, latest
+}
+
+// LatestAddedAt devuelve el momento en que se agregó la última canción de la lista, para vincularla sin importar
+// las canciones que ya tenía.
+func LatestAddedAt(tracks []spotify.Track) time.Time {
+	_, latest := NewTracks(tracks, time.Time{})
+	return latest
+}
+
+// Syncer revisa periódicamente las listas vinculadas.
+type Syncer struct {
+	store     Store
+	playlists Playlists
+	sync      SyncFunc
+	logger    logging.Logger
+	now       func() time.Time
+}
+
+// NewSyncer crea un Syncer que entrega las canciones nuevas de cada lista a sync.
+func NewSyncer(store Store, playlists Playlists, sync SyncFunc, logger logging.Logger) *Syncer {
+	return &Syncer{store: store, playlists: playlists, sync: sync, logger: logger, now: time.Now}
+}
+
+// Run revisa las listas vinculadas cada interval hasta que se cancele ctx.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick sincroniza todas las listas vinculadas.
+func (s *Syncer) tick(ctx context.Context) {
+	links, err := s.store.All()
+	if err != nil {
+		s.logger.Error("Error al obtener las listas de Spotify vinculadas", zap.Error(err))
+		return
+	}
+	for _, link := range links {
+		logger := logging.WithFields(s.logger, zap.String("guildID", link.GuildID), zap.String("playlistID", link.PlaylistID))
+		err := s.syncLink(ctx, link)
+		if errors.Is(err, ErrNotReady) {
+			logger.Info("La lista de Spotify se sincroniza más tarde", zap.Error(err))
+		} else if err != nil {
+			logger.Error("Error al sincronizar la lista de Spotify", zap.Error(err))
+		}
+	}
+}
+
+// syncLink entrega las canciones nuevas de la lista y guarda hasta cuándo se sincronizó.
+func (s *Syncer) syncLink(ctx context.Context, link Link) error {
+	tracks, err := s.playlists.PlaylistTracks(ctx, link.PlaylistID)
+	if err != nil {
+		return err
+	}
+	added, latest := NewTracks(tracks, link.LastAddedAt)
+	if len(added) > 0 {
+		if err := s.sync(ctx, link, added); err != nil {
+			return err
+		}
+	}
+	if err := s.store.MarkSynced(link.GuildID, link.PlaylistID, latest, s.now()); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
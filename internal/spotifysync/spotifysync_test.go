@@ -0,0 +1,115 @@
+package spotifysync
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/spotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewTracks(t *testing.T) {
+	since := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	tracks := []spotify.Track{
+		{ID: "viejo", AddedAt: since.Add(-time.Hour)},
+		{ID: "b", AddedAt: since.Add(2 * time.Minute)},
+		{ID: "igual", AddedAt: since},
+		{ID: "a", AddedAt: since.Add(time.Minute)},
+	}
+
+	added, latest := NewTracks(tracks, since)
+
+	require.Len(t, added, 2)
+	assert.Equal(t, "b", added[0].ID, "se respeta el orden de la lista")
+	assert.Equal(t, "a", added[1].ID)
+	assert.Equal(t, since.Add(2*time.Minute), latest)
+	assert.Equal(t, since.Add(2*time.Minute), LatestAddedAt(tracks))
+
+	none, unchanged := NewTracks(tracks[:1], since)
+	assert.Empty(t, none)
+	assert.Equal(t, since, unchanged)
+}
+
+func TestSyncer_Tick(t *testing.T) {
+	store := NewInMemoryStore()
+	since := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Put(Link{GuildID: "1", PlaylistID: "p1", LastAddedAt: since, CreatedAt: since}))
+	require.NoError(t, store.Put(Link{GuildID: "2", PlaylistID: "p2", LastAddedAt: since, CreatedAt: since.Add(time.Second)}))
+	require.NoError(t, store.Put(Link{GuildID: "3", PlaylistID: "p3", LastAddedAt: since, CreatedAt: since.Add(2 * time.Second)}))
+
+	playlists := new(MockPlaylists)
+	playlists.On("PlaylistTracks", mock.Anything, "p1").Return([]spotify.Track{
+		{ID: "viejo", AddedAt: since},
+		{ID: "nuevo", AddedAt: since.Add(time.Minute)},
+	}, nil)
+	playlists.On("PlaylistTracks", mock.Anything, "p2").Return([]spotify.Track{{ID: "falla", AddedAt: since.Add(time.Minute)}}, nil)
+	playlists.On("PlaylistTracks", mock.Anything, "p3").Return([]spotify.Track{{ID: "espera", AddedAt: since.Add(time.Minute)}}, nil)
+	logger := new(MockLogger)
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+
+	var synced []string
+	syncer := NewSyncer(store, playlists, func(_ context.Context, link Link, tracks []spotify.Track) error {
+		switch link.PlaylistID {
+		case "p2":
+			return errors.New("sin resultados")
+		case "p3":
+			return ErrNotReady
+		}
+		for _, track := range tracks {
+			synced = append(synced, track.ID)
+		}
+		return nil
+	}, logger)
+	now := since.Add(time.Hour)
+	syncer.now = func() time.Time { return now }
+	syncer.tick(context.Background())
+	syncer.tick(context.Background())
+
+	assert.Equal(t, []string{"nuevo"}, synced, "las canciones ya entregadas no se repiten")
+	links, err := store.All()
+	require.NoError(t, err)
+	assert.Equal(t, since.Add(time.Minute), links[0].LastAddedAt)
+	assert.Equal(t, now, links[0].LastSync)
+	assert.Equal(t, since, links[1].LastAddedAt, "si falla, se reintenta en la próxima")
+	assert.Equal(t, since, links[2].LastAddedAt)
+	logger.AssertNumberOfCalls(t, "Error", 2)
+	logger.AssertNumberOfCalls(t, "Info", 2)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spotify", "links.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	require.NoError(t, Add(store, Link{GuildID: "1", PlaylistID: "p1", Name: "Novedades", Target: "lofi"}))
+	syncedAt := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.MarkSynced("1", "p1", syncedAt, syncedAt))
+	assert.ErrorIs(t, store.Delete("2", "p1"), ErrNotFound)
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	links, err := reopened.List("1")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "lofi", links[0].Target)
+	assert.True(t, syncedAt.Equal(links[0].LastAddedAt))
+
+	require.NoError(t, reopened.Delete("1", "p1"))
+	assert.ErrorIs(t, reopened.MarkSynced("1", "p1", syncedAt, syncedAt), ErrNotFound)
+}
+
+func TestAdd_TooMany(t *testing.T) {
+	store := NewInMemoryStore()
+	for i := 0; i < MaxLinksPerGuild; i++ {
+		require.NoError(t, Add(store, Link{GuildID: "1", PlaylistID: string(rune('a' + i))}))
+	}
+
+	assert.ErrorIs(t, Add(store, Link{GuildID: "1", PlaylistID: "otra"}), ErrTooMany)
+	assert.NoError(t, Add(store, Link{GuildID: "1", PlaylistID: "a", Target: "lofi"}), "volver a vincular la misma lista la reemplaza")
+	assert.NoError(t, Add(store, Link{GuildID: "2", PlaylistID: "otra"}))
+}
@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/redis"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+// retention es cuánto se guardan las estadísticas de cada semana. Alcanza para que el resumen semanal las lea
+// aunque se atrase.
+const retention = 35 * 24 * time.Hour
+
+// Store guarda las estadísticas de reproducción de cada servidor.
+type Store interface {
+	// RecordPlay suma una reproducción de la canción, pedida por requester, a la semana de at.
+	RecordPlay(ctx context.Context, guildID, title, requester string, at time.Time) error
+}
+
+// Week devuelve la semana ISO de t, por ejemplo "2024-W05". Es la parte de las claves que agrupa las estadísticas.
+func Week(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// RedisStore guarda las estadísticas en Redis, donde las lee la lambda del resumen semanal. Por cada semana hay:
+//
+//	gomusicbot:stats:{semana}:guilds                  set con los servidores que reprodujeron algo
+//	gomusicbot:stats:{semana}:{guildID}:songs         hash título -> reproducciones
+//	gomusicbot:stats:{semana}:{guildID}:requesters    hash usuario -> canciones pedidas
+type RedisStore struct {
+	client redis.Commander
+}
+
+// NewRedisStore crea un Store sobre Redis.
+func NewRedisStore(client redis.Commander) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) RecordPlay(ctx context.Context, guildID, title, requester string, at time.Time) error {
+	week := Week(at)
+	guildsKey := fmt.Sprintf("gomusicbot:stats:%s:guilds", week)
+	songsKey := fmt.Sprintf("gomusicbot:stats:%s:%s:songs", week, guildID)
+	requestersKey := fmt.Sprintf("gomusicbot:stats:%s:%s:requesters", week, guildID)
+	ttl := strconv.FormatInt(int64(retention/time.Second), 10)
+
+	commands := [][]string{
+		{"SADD", guildsKey, guildID},
+		{"EXPIRE", guildsKey, ttl},
+		{"HINCRBY", songsKey, title, "1"},
+		{"EXPIRE", songsKey, ttl},
+	}
+	if requester != "" {
+		commands = append(commands, []string{"HINCRBY", requestersKey, requester, "1"}, []string{"EXPIRE", requestersKey, ttl})
+	}
+	for _, args := range commands {
+		if _, err := s.client.Do(ctx, args...); err != nil {
+			return fmt.Errorf("error al guardar la estadística del servidor %s: %w", guildID, err)
+		}
+	}
+	return nil
+}
+
+// Recorder registra en el Store las canciones que empiezan a sonar.
+type Recorder struct {
+	store  Store
+	logger logging.Logger
+}
+
+// NewRecorder crea un Recorder.
+func NewRecorder(store Store, logger logging.Logger) *Recorder {
+	return &Recorder{store: store, logger: logger}
+}
+
+// Run registra los eventos recibidos hasta que se cierre el canal o se cancele el contexto.
+func (r *Recorder) Run(ctx context.Context, ch <-chan events.Event) {
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if event.Type != events.TypeTrackStarted || event.Title == "" {
+				continue
+			}
+			if err := r.store.RecordPlay(ctx, event.GuildID, event.Title, event.RequestedBy, event.Time); err != nil {
+				r.logger.Error("Error al registrar la reproducción", zap.String("guildID", event.GuildID), zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
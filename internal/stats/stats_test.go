@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestWeek(t *testing.T) {
+	assert.Equal(t, "2024-W01", Week(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2020-W53", Week(time.Date(2021, 1, 3, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestRedisStore_RecordPlay(t *testing.T) {
+	client := new(MockCommander)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client.On("Do", []string{"SADD", "gomusicbot:stats:2024-W18:guilds", "1"}).Return(int64(1), nil)
+	client.On("Do", []string{"HINCRBY", "gomusicbot:stats:2024-W18:1:songs", "Canción", "1"}).Return(int64(1), nil)
+	client.On("Do", []string{"HINCRBY", "gomusicbot:stats:2024-W18:1:requesters", "tomas", "1"}).Return(int64(1), nil)
+	client.On("Do", mock.MatchedBy(func(args []string) bool { return args[0] == "EXPIRE" && args[2] == "3024000" })).Return(int64(1), nil).Times(3)
+
+	assert.NoError(t, NewRedisStore(client).RecordPlay(context.Background(), "1", "Canción", "tomas", at))
+	client.AssertExpectations(t)
+}
+
+func TestRedisStore_RecordPlayError(t *testing.T) {
+	client := new(MockCommander)
+	client.On("Do", mock.Anything).Return(nil, errors.New("conexión rechazada"))
+
+	err := NewRedisStore(client).RecordPlay(context.Background(), "1", "Canción", "", time.Now())
+
+	assert.ErrorContains(t, err, "conexión rechazada")
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestRecorder_Run(t *testing.T) {
+	store := new(MockStore)
+	logger := new(MockLogger)
+	logger.On("Error", "Error al registrar la reproducción", mock.Anything).Return()
+	at := time.Now()
+	store.On("RecordPlay", "1", "Canción", "tomas", at).Return(nil).Once()
+	store.On("RecordPlay", "2", "Otra", "", at).Return(errors.New("falla")).Once()
+
+	ch := make(chan events.Event, 4)
+	ch <- events.Event{Type: events.TypeTrackStarted, GuildID: "1", Title: "Canción", RequestedBy: "tomas", Time: at}
+	ch <- events.Event{Type: events.TypePosition, GuildID: "1", Title: "Canción", Time: at}
+	ch <- events.Event{Type: events.TypeTrackStarted, GuildID: "2", Title: "Otra", Time: at}
+	close(ch)
+	NewRecorder(store, logger).Run(context.Background(), ch)
+
+	store.AssertExpectations(t)
+	logger.AssertNumberOfCalls(t, "Error", 1)
+}
@@ -0,0 +1,186 @@
+// Package trivia implementa el juego de adivinar canciones: qué canciones suenan en cada ronda, qué respuestas son
+// correctas y el puntaje de cada jugador.
+package trivia
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxRounds es la cantidad máxima de rondas de una partida.
+	MaxRounds = 20
+	// minGuessLength es el largo mínimo de una respuesta parcial para que cuente como correcta.
+	minGuessLength = 4
+)
+
+var (
+	// bracketed son los agregados entre paréntesis o corchetes de los títulos, como "(Official Video)".
+	bracketed = regexp.MustCompile(`[(\[][^)\]]*[)\]]`)
+	// featuring son los artistas invitados al final del título.
+	featuring = regexp.MustCompile(`\s(ft|feat|featuring)\.?\s.*$`)
+	// nonAlphanumeric son los caracteres que no se tienen en cuenta al comparar.
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	accents         = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	// titleNoise son las palabras que YouTube suele agregar al título y que nadie escribe al adivinar.
+	titleNoise = []string{"official music video", "official video", "official audio", "video oficial", "lyric video", "lyrics", "letra"}
+)
+
+type (
+	// Score es el puntaje de un jugador.
+	Score struct {
+		UserID string
+		Name   string
+		Points int
+	}
+
+	// Game es una partida de trivia en un servidor. Es seguro usarla desde varias goroutines.
+	Game struct {
+		songs    []*voice.Song
+		round    int
+		answered bool
+		scores   map[string]*Score
+		mu       sync.Mutex
+	}
+)
+
+// NewGame crea una partida con hasta rounds canciones elegidas al azar entre songs.
+func NewGame(songs []*voice.Song, rounds int) *Game {
+	shuffled := make([]*voice.Song, len(songs))
+	copy(shuffled, songs)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	if rounds > 0 && rounds < len(shuffled) {
+		shuffled = shuffled[:rounds]
+	}
+	return &Game{songs: shuffled, round: -1, scores: make(map[string]*Score)}
+}
+
+// Rounds devuelve la cantidad de rondas de la partida.
+func (g *Game) Rounds() int {
+	return len(g.songs)
+}
+
+// NextRound pasa a la siguiente ronda y devuelve su número (desde 1) y la canción a adivinar. Devuelve false si no
+// quedan rondas.
+func (g *Game) NextRound() (int, *voice.Song, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.round+1 >= len(g.songs) {
+		return 0, nil, false
+	}
+	g.round++
+	g.answered = false
+	return g.round + 1, g.songs[g.round], true
+}
+
+// Guess registra la respuesta de un jugador. Devuelve true si es la primera respuesta correcta de la ronda; en ese
+// caso el jugador suma un punto y la ronda queda cerrada.
+func (g *Game) Guess(userID, name, guess string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.round < 0 || g.answered || !Matches(guess, g.songs[g.round].Title) {
+		return false
+	}
+	g.answered = true
+	score, ok := g.scores[userID]
+	if !ok {
+		score = &Score{UserID: userID}
+		g.scores[userID] = score
+	}
+	score.Name = name
+	score.Points++
+	return true
+}
+
+// CloseRound cierra la ronda actual para que no se acepten más respuestas.
+func (g *Game) CloseRound() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.answered = true
+}
+
+// Leaderboard devuelve los puntajes de mayor a menor.
+func (g *Game) Leaderboard() []Score {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	leaderboard := make([]Score, 0, len(g.scores))
+	for _, score := range g.scores {
+		leaderboard = append(leaderboard, *score)
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].Points != leaderboard[j].Points {
+			return leaderboard[i].Points > leaderboard[j].Points
+		}
+		return leaderboard[i].Name < leaderboard[j].Name
+	})
+	return leaderboard
+}
+
+// Matches indica si la respuesta corresponde al título de la canción. No distingue mayúsculas, acentos ni signos,
+// ignora los agregados como "(Official Video)" y acepta solo el nombre de la canción sin el artista, o una parte
+// que cubra al menos la mitad del nombre.
+func Matches(guess, title string) bool {
+	guess = normalize(guess)
+	if guess == "" {
+		return false
+	}
+	for _, candidate := range titleCandidates(title) {
+		if guess == candidate {
+			return true
+		}
+		if len(guess) < minGuessLength || len(candidate) < minGuessLength {
+			continue
+		}
+		if strings.Contains(guess, candidate) || (strings.Contains(candidate, guess) && 2*len(guess) >= len(candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clip devuelve la canción de la ronda lista para encolar: sin título ni miniatura, para no revelar la respuesta, y
+// empezando en una parte al azar de la canción para que suene length.
+func Clip(song *voice.Song, round int, length time.Duration) *voice.Song {
+	requestedBy := "Trivia"
+	clip := &voice.Song{
+		Type:        song.Type,
+		Title:       fmt.Sprintf("🎲 Trivia: ronda %d", round),
+		URL:         song.URL,
+		Playable:    true,
+		Duration:    song.Duration,
+		RequestedBy: &requestedBy,
+	}
+	// Se evita el principio, que suele ser una intro, y se deja lugar para que el fragmento entre completo.
+	earliest, latest := song.Duration/5, song.Duration-length
+	if latest > earliest {
+		clip.StartPosition = (earliest + time.Duration(rand.Int63n(int64(latest-earliest)))).Truncate(time.Second)
+	}
+	return clip
+}
+
+// titleCandidates devuelve las formas del título que se aceptan como respuesta: el título completo y, si tiene la
+// forma "Artista - Canción", solo la canción.
+func titleCandidates(title string) []string {
+	candidates := []string{normalize(title)}
+	if _, song, ok := strings.Cut(title, " - "); ok {
+		candidates = append(candidates, normalize(song))
+	}
+	return candidates
+}
+
+// normalize deja el texto en minúsculas, sin acentos, agregados, artistas invitados ni signos.
+func normalize(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+	text = bracketed.ReplaceAllString(text, " ")
+	for _, noise := range titleNoise {
+		text = strings.ReplaceAll(text, noise, " ")
+	}
+	text = featuring.ReplaceAllString(text, "")
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(text, " "), " ")
+}
@@ -0,0 +1,67 @@
+package trivia
+
+import (
+	"github.com/Tomas-vilte/GoMusicBot/internal/discord/voice"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		guess string
+		title string
+		want  bool
+	}{
+		{guess: "la bamba", title: "Ritchie Valens - La Bamba (Official Video)", want: true},
+		{guess: "Ritchie Valens La Bamba", title: "Ritchie Valens - La Bamba", want: true},
+		{guess: "cancion animal", title: "Canción Animal [Lyrics]", want: true},
+		{guess: "despacito", title: "Luis Fonsi - Despacito ft. Daddy Yankee", want: true},
+		{guess: "persiana americana", title: "Soda Stereo - Persiana Americana", want: true},
+		{guess: "persiana", title: "Soda Stereo - Persiana Americana", want: false},
+		{guess: "soda", title: "Soda Stereo - Persiana Americana", want: false},
+		{guess: "yo", title: "Artista - Yo", want: true},
+		{guess: "", title: "La Bamba", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Matches(tt.guess, tt.title), "%q / %q", tt.guess, tt.title)
+	}
+}
+
+func TestGame(t *testing.T) {
+	game := NewGame([]*voice.Song{{Title: "Soda Stereo - De Música Ligera"}, {Title: "Los Redondos - Ji Ji Ji"}}, 5)
+	assert.Equal(t, 2, game.Rounds())
+	assert.False(t, game.Guess("1", "Ana", "de musica ligera"), "no se aceptan respuestas antes de la primera ronda")
+
+	for {
+		round, song, ok := game.NextRound()
+		if !ok {
+			break
+		}
+		assert.False(t, game.Guess("2", "Beto", "cualquier cosa"))
+		if round == 1 {
+			assert.True(t, game.Guess("1", "Ana", song.Title))
+			assert.False(t, game.Guess("2", "Beto", song.Title), "solo cuenta la primera respuesta correcta")
+		} else {
+			game.CloseRound()
+			assert.False(t, game.Guess("2", "Beto", song.Title), "la ronda cerrada no acepta respuestas")
+		}
+	}
+
+	assert.Equal(t, []Score{{UserID: "1", Name: "Ana", Points: 1}}, game.Leaderboard())
+}
+
+func TestClip(t *testing.T) {
+	thumbnail := "https://i.ytimg.com/vi/abc/default.jpg"
+	song := &voice.Song{Title: "La Bamba", URL: "https://youtu.be/abc", Duration: 3 * time.Minute, ThumbnailURL: &thumbnail}
+
+	clip := Clip(song, 3, 30*time.Second)
+
+	assert.Equal(t, "🎲 Trivia: ronda 3", clip.Title)
+	assert.Equal(t, song.URL, clip.URL)
+	assert.Nil(t, clip.ThumbnailURL)
+	assert.GreaterOrEqual(t, clip.StartPosition, 36*time.Second)
+	assert.LessOrEqual(t, clip.StartPosition, 150*time.Second)
+
+	assert.Zero(t, Clip(&voice.Song{Duration: 20 * time.Second}, 1, 30*time.Second).StartPosition)
+}
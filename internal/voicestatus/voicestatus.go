@@ -0,0 +1,149 @@
+package voicestatus
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxStatusLength es el largo máximo que Discord acepta para el estado de un canal de voz.
+	maxStatusLength = 500
+	// flushInterval es cada cuánto se revisa si hay estados pendientes de aplicar.
+	flushInterval = time.Second
+)
+
+// Setter cambia el estado de un canal de voz. Con status vacío lo borra.
+type Setter interface {
+	SetStatus(channelID, status string) error
+}
+
+// SessionSetter cambia el estado de los canales de voz con la API de Discord. Los límites de la API los respeta la
+// sesión, que espera y reintenta cuando Discord responde 429.
+type SessionSetter struct {
+	session *discordgo.Session
+}
+
+// NewSessionSetter crea un SessionSetter sobre la sesión de Discord.
+func NewSessionSetter(session *discordgo.Session) *SessionSetter {
+	return &SessionSetter{session: session}
+}
+
+func (s *SessionSetter) SetStatus(channelID, status string) error {
+	endpoint := discordgo.EndpointChannel(channelID) + "/voice-status"
+	_, err := s.session.RequestWithBucketID(http.MethodPut, endpoint, map[string]string{"status": status}, endpoint)
+	return err
+}
+
+// channelStatus es el estado de un canal de voz: el que se le aplicó y el que le corresponde.
+type channelStatus struct {
+	applied  string
+	desired  string
+	notUntil time.Time // notUntil es cuándo se puede aplicar desired: por el intervalo mínimo o la espera para borrar.
+	lastSet  time.Time
+}
+
+// Updater pone en el estado de los canales de voz la canción que está sonando y lo borra cuando dejan de sonar,
+// según los eventos de reproducción. Para no pasarse de los límites de Discord, cada canal se actualiza como mucho
+// una vez por minInterval, y al terminar una canción se espera clearDelay antes de borrar el estado, por si empieza
+// la siguiente.
+type Updater struct {
+	setter      Setter
+	minInterval time.Duration
+	clearDelay  time.Duration
+	logger      logging.Logger
+	channels    map[string]*channelStatus
+}
+
+// NewUpdater crea un Updater.
+func NewUpdater(setter Setter, minInterval, clearDelay time.Duration, logger logging.Logger) *Updater {
+	return &Updater{setter: setter, minInterval: minInterval, clearDelay: clearDelay, logger: logger, channels: make(map[string]*channelStatus)}
+}
+
+// Run actualiza los estados según los eventos recibidos hasta que se cierre el canal o se cancele el contexto.
+func (u *Updater) Run(ctx context.Context, ch <-chan events.Event) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			u.handle(event, time.Now())
+			u.flush(time.Now())
+		case now := <-ticker.C:
+			u.flush(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handle registra el estado que le corresponde al canal del evento.
+func (u *Updater) handle(event events.Event, now time.Time) {
+	if event.VoiceChannelID == "" {
+		return
+	}
+	switch event.Type {
+	case events.TypeTrackStarted:
+		u.want(event.VoiceChannelID, Status(event), now)
+	case events.TypeTrackFinished:
+		u.want(event.VoiceChannelID, "", now.Add(u.clearDelay))
+	case events.TypePlaybackStopped:
+		u.want(event.VoiceChannelID, "", now)
+	}
+}
+
+// want hace que el estado del canal pase a ser status a partir de at, respetando el intervalo mínimo.
+func (u *Updater) want(channelID, status string, at time.Time) {
+	channel, ok := u.channels[channelID]
+	if !ok {
+		if status == "" {
+			return
+		}
+		channel = &channelStatus{}
+		u.channels[channelID] = channel
+	}
+	channel.desired = status
+	channel.notUntil = at
+}
+
+// flush aplica los estados pendientes a los que ya les toca.
+func (u *Updater) flush(now time.Time) {
+	for channelID, channel := range u.channels {
+		if channel.desired == channel.applied {
+			if channel.applied == "" {
+				delete(u.channels, channelID)
+			}
+			continue
+		}
+		if now.Before(channel.notUntil) || now.Sub(channel.lastSet) < u.minInterval {
+			continue
+		}
+		if err := u.setter.SetStatus(channelID, channel.desired); err != nil {
+			u.logger.Warn("Error al cambiar el estado del canal de voz", zap.String("canal", channelID), zap.Error(err))
+		}
+		// Aunque falle se da por aplicado, para no reintentar sin parar si al bot le falta el permiso.
+		channel.applied = channel.desired
+		channel.lastSet = now
+	}
+}
+
+// Status devuelve el estado del canal de voz para la canción del evento, como "🎶 Artista – Título".
+func Status(event events.Event) string {
+	name := event.Title
+	if event.Uploader != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(event.Uploader)) {
+		name = event.Uploader + " – " + name
+	}
+	status := "🎶 " + name
+	if runes := []rune(status); len(runes) > maxStatusLength {
+		status = string(runes[:maxStatusLength-1]) + "…"
+	}
+	return status
+}
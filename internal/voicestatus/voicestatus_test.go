@@ -0,0 +1,78 @@
+package voicestatus
+
+import (
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, "🎶 Queen – Bohemian Rhapsody", Status(events.Event{Title: "Bohemian Rhapsody", Uploader: "Queen"}))
+	assert.Equal(t, "🎶 Queen - Bohemian Rhapsody", Status(events.Event{Title: "Queen - Bohemian Rhapsody", Uploader: "Queen"}))
+	assert.Equal(t, "🎶 La Bamba", Status(events.Event{Title: "La Bamba"}))
+
+	long := Status(events.Event{Title: strings.Repeat("a", 600)})
+	assert.Len(t, []rune(long), maxStatusLength)
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
+
+func TestUpdater(t *testing.T) {
+	setter := new(MockSetter)
+	setter.On("SetStatus", mock.Anything, mock.Anything).Return(nil)
+	updater := NewUpdater(setter, 10*time.Second, 5*time.Second, new(MockLogger))
+	now := time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC)
+	started := func(title string) events.Event {
+		return events.Event{Type: events.TypeTrackStarted, VoiceChannelID: "voz", Title: title}
+	}
+
+	updater.handle(started("La Bamba"), now)
+	updater.flush(now)
+	setter.AssertCalled(t, "SetStatus", "voz", "🎶 La Bamba")
+
+	// Al terminar la canción se espera antes de borrar, y la siguiente reemplaza el borrado.
+	updater.handle(events.Event{Type: events.TypeTrackFinished, VoiceChannelID: "voz"}, now.Add(2*time.Minute))
+	updater.flush(now.Add(2*time.Minute + time.Second))
+	updater.handle(started("Oye Como Va"), now.Add(2*time.Minute+2*time.Second))
+	updater.flush(now.Add(2*time.Minute + 2*time.Second))
+	setter.AssertNotCalled(t, "SetStatus", "voz", "")
+	setter.AssertCalled(t, "SetStatus", "voz", "🎶 Oye Como Va")
+
+	// Dentro del intervalo mínimo no se vuelve a actualizar; se aplica el último cuando se puede.
+	updater.handle(started("Despacito"), now.Add(2*time.Minute+3*time.Second))
+	updater.handle(started("Gasolina"), now.Add(2*time.Minute+4*time.Second))
+	updater.flush(now.Add(2*time.Minute + 5*time.Second))
+	updater.flush(now.Add(2*time.Minute + 12*time.Second))
+	setter.AssertNotCalled(t, "SetStatus", "voz", "🎶 Despacito")
+	setter.AssertCalled(t, "SetStatus", "voz", "🎶 Gasolina")
+
+	updater.handle(events.Event{Type: events.TypePlaybackStopped, VoiceChannelID: "voz"}, now.Add(3*time.Minute))
+	updater.flush(now.Add(3 * time.Minute))
+	setter.AssertCalled(t, "SetStatus", "voz", "")
+	setter.AssertNumberOfCalls(t, "SetStatus", 4)
+
+	updater.flush(now.Add(4 * time.Minute))
+	assert.Empty(t, updater.channels)
+}
+
+func TestUpdater_SetterError(t *testing.T) {
+	setter := new(MockSetter)
+	setter.On("SetStatus", "voz", "🎶 La Bamba").Return(errors.New("403 Forbidden")).Once()
+	logger := new(MockLogger)
+	logger.On("Warn", "Error al cambiar el estado del canal de voz", mock.Anything).Return()
+	updater := NewUpdater(setter, 0, 0, logger)
+	now := time.Now()
+
+	updater.handle(events.Event{Type: events.TypeTrackStarted, VoiceChannelID: "voz", Title: "La Bamba"}, now)
+	updater.flush(now)
+	updater.flush(now.Add(time.Minute))
+	setter.AssertNumberOfCalls(t, "SetStatus", 1)
+	logger.AssertExpectations(t)
+
+	// Los eventos sin canal de voz se ignoran.
+	updater.handle(events.Event{Type: events.TypeTrackStarted, Title: "Sin canal"}, now)
+	assert.Len(t, updater.channels, 1)
+}
@@ -0,0 +1,120 @@
+package ytwatch
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultFeedURL es el feed Atom con las últimas subidas de un canal de YouTube.
+	DefaultFeedURL = "https://www.youtube.com/feeds/videos.xml"
+	// feedTimeout es el tiempo máximo de cada pedido del feed.
+	feedTimeout = 10 * time.Second
+)
+
+var (
+	// ErrInvalidChannel indica que el texto no es un link ni un ID de un canal de YouTube.
+	ErrInvalidChannel = errors.New("no es un canal de YouTube")
+	// ErrChannelNotFound indica que el canal no existe.
+	ErrChannelNotFound = errors.New("el canal de YouTube no existe")
+
+	channelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+)
+
+type (
+	// Upload es un video subido a un canal de YouTube.
+	Upload struct {
+		VideoID   string
+		Title     string
+		Published time.Time
+	}
+
+	// Feed son el nombre de un canal y sus últimas subidas, de la más reciente a la más vieja.
+	Feed struct {
+		Title   string
+		Uploads []Upload
+	}
+)
+
+// URL devuelve el link del video.
+func (u Upload) URL() string {
+	return "https://www.youtube.com/watch?v=" + u.VideoID
+}
+
+// ParseChannelID devuelve el ID del canal a partir de un link (https://www.youtube.com/channel/UC...) o del ID. Los
+// links con @usuario no se aceptan porque el feed solo se puede pedir por ID.
+func ParseChannelID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if u, err := url.Parse(input); err == nil && strings.HasSuffix(u.Host, "youtube.com") {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) < 2 || parts[0] != "channel" {
+			return "", ErrInvalidChannel
+		}
+		input = parts[1]
+	}
+	if !channelIDPattern.MatchString(input) {
+		return "", ErrInvalidChannel
+	}
+	return input, nil
+}
+
+// FeedClient lee los feeds Atom de los canales de YouTube, que no necesitan clave de la API.
+type FeedClient struct {
+	client  *http.Client
+	feedURL string
+}
+
+// NewFeedClient crea un FeedClient.
+func NewFeedClient() *FeedClient {
+	return &FeedClient{client: &http.Client{Timeout: feedTimeout}, feedURL: DefaultFeedURL}
+}
+
+// atomFeed es el feed de un canal; las etiquetas yt: usan el espacio de nombres de YouTube.
+type atomFeed struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		VideoID   string    `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		Title     string    `xml:"title"`
+		Published time.Time `xml:"published"`
+	} `xml:"entry"`
+}
+
+// Uploads devuelve el nombre del canal y sus últimas subidas, o ErrChannelNotFound si el canal no existe.
+func (c *FeedClient) Uploads(ctx context.Context, channelID string) (Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.feedURL+"?channel_id="+url.QueryEscape(channelID), nil)
+	if err != nil {
+		return Feed{}, fmt.Errorf("error al crear el pedido del feed: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Feed{}, fmt.Errorf("error al consultar el feed del canal: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Feed{}, ErrChannelNotFound
+	default:
+		return Feed{}, fmt.Errorf("YouTube respondió con el estado %d", resp.StatusCode)
+	}
+	var atom atomFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&atom); err != nil {
+		return Feed{}, fmt.Errorf("error al leer el feed del canal: %w", err)
+	}
+	feed := Feed{Title: atom.Title, Uploads: make([]Upload, 0, len(atom.Entries))}
+	for _, entry := range atom.Entries {
+		if entry.VideoID == "" {
+			continue
+		}
+		feed.Uploads = append(feed.Uploads, Upload{VideoID: entry.VideoID, Title: entry.Title, Published: entry.Published})
+	}
+	return feed, nil
+}
@@ -0,0 +1,70 @@
+package ytwatch
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testChannelID = "UCuAXFkgsw1L7xaCfnd5JJOw"
+
+func TestParseChannelID(t *testing.T) {
+	for _, input := range []string{
+		testChannelID,
+		"https://www.youtube.com/channel/" + testChannelID,
+		"https://youtube.com/channel/" + testChannelID + "/videos",
+		"https://m.youtube.com/channel/" + testChannelID + "?si=abc",
+	} {
+		id, err := ParseChannelID(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, testChannelID, id, input)
+	}
+
+	for _, input := range []string{"", "hola", "https://www.youtube.com/@canal", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"} {
+		_, err := ParseChannelID(input)
+		assert.ErrorIs(t, err, ErrInvalidChannel, input)
+	}
+}
+
+func TestFeedClient_Uploads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("channel_id") != testChannelID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+ <title>Canal de prueba</title>
+ <entry>
+  <yt:videoId>nuevo123456</yt:videoId>
+  <title>Tema nuevo</title>
+  <published>2024-03-08T12:00:00+00:00</published>
+ </entry>
+ <entry>
+  <yt:videoId>viejo123456</yt:videoId>
+  <title>Tema viejo</title>
+  <published>2024-03-01T12:00:00+00:00</published>
+ </entry>
+</feed>`))
+	}))
+	defer server.Close()
+	client := NewFeedClient()
+	client.feedURL = server.URL
+
+	feed, err := client.Uploads(context.Background(), testChannelID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Canal de prueba", feed.Title)
+	require.Len(t, feed.Uploads, 2)
+	assert.Equal(t, "nuevo123456", feed.Uploads[0].VideoID)
+	assert.Equal(t, "Tema nuevo", feed.Uploads[0].Title)
+	assert.True(t, time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC).Equal(feed.Uploads[0].Published))
+	assert.Equal(t, "https://www.youtube.com/watch?v=nuevo123456", feed.Uploads[0].URL())
+
+	_, err = client.Uploads(context.Background(), "UCotro")
+	assert.ErrorIs(t, err, ErrChannelNotFound)
+}
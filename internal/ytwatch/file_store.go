@@ -0,0 +1,79 @@
+package ytwatch
+
+import (
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jsonfile"
+	"sync"
+	"time"
+)
+
+// FileStore implementa Store guardando las suscripciones de todos los servidores en un archivo JSON.
+type FileStore struct {
+	mu            sync.RWMutex
+	filepath      string
+	subscriptions map[string]Subscription
+}
+
+// NewFileStore crea una nueva instancia de FileStore y carga las suscripciones del archivo, si existe.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{filepath: path, subscriptions: make(map[string]Subscription)}
+	if err := jsonfile.Load(path, &s.subscriptions); err != nil {
+		return nil, fmt.Errorf("error al cargar las suscripciones a canales: %w", err)
+	}
+	return s, nil
+}
+
+func (s *FileStore) Put(subscription Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := subscriptionKey(subscription.GuildID, subscription.ChannelID)
+	return jsonfile.Update(s.subscriptions, key, func() { s.subscriptions[key] = subscription }, s.persist)
+}
+
+func (s *FileStore) List(guildID string) ([]Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSubscriptions(s.subscriptions, guildID), nil
+}
+
+func (s *FileStore) All() ([]Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSubscriptions(s.subscriptions, ""), nil
+}
+
+func (s *FileStore) Delete(guildID, channelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := subscriptionKey(guildID, channelID)
+	if _, ok := s.subscriptions[key]; !ok {
+		return ErrNotFound
+	}
+	return jsonfile.Update(s.subscriptions, key, func() { delete(s.subscriptions, key) }, s.persist)
+}
+
+func (s *FileStore) MarkChecked(guildID, channelID string, lastPublished, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := subscriptionKey(guildID, channelID)
+	subscription, ok := s.subscriptions[key]
+	if !ok {
+		return ErrNotFound
+	}
+	subscription.LastPublished = lastPublished
+	subscription.LastCheck = at
+	return jsonfile.Update(s.subscriptions, key, func() { s.subscriptions[key] = subscription }, s.persist)
+}
+
+// persist guarda las suscripciones a canales en el archivo. Se llama con mu tomado.
+func (s *FileStore) persist() error {
+	if err := jsonfile.Save(s.filepath, s.subscriptions); err != nil {
+		return fmt.Errorf("error al guardar las suscripciones a canales: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package ytwatch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryStore implementa Store guardando las suscripciones en memoria.
+type InMemoryStore struct {
+	mu            sync.RWMutex
+	subscriptions map[string]Subscription
+}
+
+// NewInMemoryStore crea una nueva instancia de InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{subscriptions: make(map[string]Subscription)}
+}
+
+func (s *InMemoryStore) Put(subscription Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[subscriptionKey(subscription.GuildID, subscription.ChannelID)] = subscription
+	return nil
+}
+
+func (s *InMemoryStore) List(guildID string) ([]Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSubscriptions(s.subscriptions, guildID), nil
+}
+
+func (s *InMemoryStore) All() ([]Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return listSubscriptions(s.subscriptions, ""), nil
+}
+
+func (s *InMemoryStore) Delete(guildID, channelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := subscriptionKey(guildID, channelID)
+	if _, ok := s.subscriptions[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.subscriptions, key)
+	return nil
+}
+
+func (s *InMemoryStore) MarkChecked(guildID, channelID string, lastPublished, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := subscriptionKey(guildID, channelID)
+	subscription, ok := s.subscriptions[key]
+	if !ok {
+		return ErrNotFound
+	}
+	subscription.LastPublished = lastPublished
+	subscription.LastCheck = at
+	s.subscriptions[key] = subscription
+	return nil
+}
+
+func subscriptionKey(guildID, channelID string) string {
+	return guildID + "/" + channelID
+}
+
+// listSubscriptions devuelve las suscripciones del servidor, o de todos si guildID está vacío, ordenadas por fecha de
+// creación.
+func listSubscriptions(subscriptions map[string]Subscription, guildID string) []Subscription {
+	list := make([]Subscription, 0)
+	for _, subscription := range subscriptions {
+		if guildID == "" || subscription.GuildID == guildID {
+			list = append(list, subscription)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
@@ -0,0 +1,37 @@
+package ytwatch
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Error(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Info(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
+type MockFeeds struct {
+	mock.Mock
+}
+
+func (m *MockFeeds) Uploads(ctx context.Context, channelID string) (Feed, error) {
+	args := m.Called(ctx, channelID)
+	feed, _ := args.Get(0).(Feed)
+	return feed, args.Error(1)
+}
@@ -0,0 +1,169 @@
+// Package ytwatch sigue canales de YouTube a los que se suscribieron los servidores: cada cierto tiempo lee el feed
+// de cada canal y entrega los videos nuevos para que se anuncien y, si se pidió, se sumen a la cola.
+package ytwatch
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+// MaxSubscriptionsPerGuild es la cantidad máxima de canales de YouTube a los que se puede suscribir un servidor.
+const MaxSubscriptionsPerGuild = 20
+
+var (
+	// ErrNotFound indica que el servidor no está suscripto al canal.
+	ErrNotFound = errors.New("el servidor no está suscripto al canal")
+	// ErrTooMany indica que el servidor llegó a MaxSubscriptionsPerGuild.
+	ErrTooMany = errors.New("el servidor tiene demasiadas suscripciones")
+)
+
+type (
+	// Subscription es la suscripción de un servidor a un canal de YouTube. Los videos publicados después de
+	// LastPublished se anuncian en TextChannelID y, con Queue, se suman a la cola si el bot está en un canal de voz.
+	Subscription struct {
+		GuildID       string    `json:"guild_id"`
+		ChannelID     string    `json:"channel_id"`
+		Name          string    `json:"name"`
+		TextChannelID string    `json:"text_channel_id"`
+		Queue         bool      `json:"queue"`
+		CreatedBy     string    `json:"created_by"`
+		CreatedAt     time.Time `json:"created_at"`
+		LastPublished time.Time `json:"last_published"`
+		LastCheck     time.Time `json:"last_check"`
+	}
+
+	// Store guarda las suscripciones de todos los servidores.
+	Store interface {
+		// Put crea o reemplaza la suscripción con el mismo servidor y canal.
+		Put(subscription Subscription) error
+		// List devuelve las suscripciones del servidor ordenadas por fecha de creación.
+		List(guildID string) ([]Subscription, error)
+		// All devuelve las suscripciones de todos los servidores.
+		All() ([]Subscription, error)
+		// Delete elimina la suscripción del servidor al canal, o devuelve ErrNotFound.
+		Delete(guildID, channelID string) error
+		// MarkChecked guarda hasta qué video se anunció y cuándo se revisó el canal, o devuelve ErrNotFound si se
+		// eliminó la suscripción.
+		MarkChecked(guildID, channelID string, lastPublished, at time.Time) error
+	}
+
+	// Feeds son los feeds de los canales; lo implementa FeedClient.
+	Feeds interface {
+		Uploads(ctx context.Context, channelID string) (Feed, error)
+	}
+
+	// NotifyFunc anuncia los videos nuevos de un canal, del más viejo al más nuevo. Si devuelve un error, los mismos
+	// videos se vuelven a entregar en la próxima revisión.
+	NotifyFunc func(ctx context.Context, subscription Subscription, uploads []Upload) error
+)
+
+// Add guarda la suscripción si el servidor no llegó al máximo. Si ya estaba suscripto al canal, la reemplaza.
+func Add(store Store, subscription Subscription) error {
+	existing, err := store.List(subscription.GuildID)
+	if err != nil {
+		return err
+	}
+	subscribed := false
+	for _, s := range existing {
+		subscribed = subscribed || s.ChannelID == subscription.ChannelID
+	}
+	if !subscribed && len(existing) >= MaxSubscriptionsPerGuild {
+		return ErrTooMany
+	}
+	return store.Put(subscription)
+}
+
+// NewUploads devuelve los videos publicados después de since, del más viejo al más nuevo, y la fecha de
+// publicación del último.
+func NewUploads(uploads []Upload, since time.Time) ([]Upload, time.Time) {
+	var added []Upload
+	latest := since
+	// El feed viene del más reciente al más viejo; se recorre al revés para anunciarlos en orden.
+	for i := len(uploads) - 1; i >= 0; i-- {
+		upload := uploads[i]
+		if !upload.Published.After(since) {
+			continue
+		}
+		added = append(added, upload)
+		if upload.Published.After(latest) {
+			latest = upload.Published
+		}
+	}
+	return added, latest
+}
+
+// LatestPublished devuelve la fecha de publicación del último video del feed, para suscribirse sin anunciar los
+// videos que el canal ya tenía.
+func LatestPublished(uploads []Upload) time.Time {
+	_, latest := NewUploads(uploads, time.Time{})
+	return latest
+}
+
+// Watcher revisa periódicamente los canales a los que están suscriptos los servidores.
+type Watcher struct {
+	store  Store
+	feeds  Feeds
+	notify NotifyFunc
+	logger logging.Logger
+	now    func() time.Time
+}
+
+// NewWatcher crea un Watcher que entrega los videos nuevos de cada canal a notify.
+func NewWatcher(store Store, feeds Feeds, notify NotifyFunc, logger logging.Logger) *Watcher {
+	return &Watcher{store: store, feeds: feeds, notify: notify, logger: logger, now: time.Now}
+}
+
+// Run revisa los canales cada interval hasta que se cancele ctx.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+// tick revisa todas las suscripciones. El feed de un canal se pide una sola vez aunque lo sigan varios servidores.
+func (w *Watcher) tick(ctx context.Context) {
+	subscriptions, err := w.store.All()
+	if err != nil {
+		w.logger.Error("Error al obtener las suscripciones a canales de YouTube", zap.Error(err))
+		return
+	}
+	feeds := make(map[string]Feed)
+	for _, subscription := range subscriptions {
+		logger := logging.WithFields(w.logger, zap.String("guildID", subscription.GuildID), zap.String("channelID", subscription.ChannelID))
+		feed, ok := feeds[subscription.ChannelID]
+		if !ok {
+			if feed, err = w.feeds.Uploads(ctx, subscription.ChannelID); err != nil {
+				logger.Error("Error al leer el feed del canal de YouTube", zap.Error(err))
+				continue
+			}
+			feeds[subscription.ChannelID] = feed
+		}
+		if err := w.check(ctx, subscription, feed); err != nil {
+			logger.Error("Error al anunciar los videos nuevos del canal de YouTube", zap.Error(err))
+		}
+	}
+}
+
+// check entrega los videos nuevos del feed y guarda hasta cuál se anunció.
+func (w *Watcher) check(ctx context.Context, subscription Subscription, feed Feed) error {
+	added, latest := NewUploads(feed.Uploads, subscription.LastPublished)
+	if len(added) > 0 {
+		if err := w.notify(ctx, subscription, added); err != nil {
+			return err
+		}
+	}
+	if err := w.store.MarkChecked(subscription.GuildID, subscription.ChannelID, latest, w.now()); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ytwatch
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewUploads(t *testing.T) {
+	since := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	uploads := []Upload{
+		{VideoID: "b", Published: since.Add(2 * time.Hour)},
+		{VideoID: "a", Published: since.Add(time.Hour)},
+		{VideoID: "igual", Published: since},
+		{VideoID: "viejo", Published: since.Add(-time.Hour)},
+	}
+
+	added, latest := NewUploads(uploads, since)
+
+	require.Len(t, added, 2)
+	assert.Equal(t, "a", added[0].VideoID, "del más viejo al más nuevo")
+	assert.Equal(t, "b", added[1].VideoID)
+	assert.Equal(t, since.Add(2*time.Hour), latest)
+	assert.Equal(t, since.Add(2*time.Hour), LatestPublished(uploads))
+}
+
+func TestWatcher_Tick(t *testing.T) {
+	store := NewInMemoryStore()
+	since := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Put(Subscription{GuildID: "1", ChannelID: "c1", LastPublished: since, CreatedAt: since}))
+	require.NoError(t, store.Put(Subscription{GuildID: "2", ChannelID: "c1", LastPublished: since, CreatedAt: since.Add(time.Second)}))
+	require.NoError(t, store.Put(Subscription{GuildID: "3", ChannelID: "c2", LastPublished: since, CreatedAt: since.Add(2 * time.Second)}))
+
+	feeds := new(MockFeeds)
+	feeds.On("Uploads", mock.Anything, "c1").Return(Feed{Uploads: []Upload{
+		{VideoID: "nuevo", Published: since.Add(time.Minute)},
+		{VideoID: "viejo", Published: since},
+	}}, nil)
+	feeds.On("Uploads", mock.Anything, "c2").Return(Feed{}, errors.New("timeout"))
+	logger := new(MockLogger)
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+
+	var notified []string
+	watcher := NewWatcher(store, feeds, func(_ context.Context, subscription Subscription, uploads []Upload) error {
+		if subscription.GuildID == "2" {
+			return errors.New("sin permisos")
+		}
+		for _, upload := range uploads {
+			notified = append(notified, subscription.GuildID+"/"+upload.VideoID)
+		}
+		return nil
+	}, logger)
+	now := since.Add(time.Hour)
+	watcher.now = func() time.Time { return now }
+	watcher.tick(context.Background())
+	watcher.tick(context.Background())
+
+	assert.Equal(t, []string{"1/nuevo"}, notified, "los videos ya anunciados no se repiten")
+	feeds.AssertNumberOfCalls(t, "Uploads", 4)
+	subscriptions, err := store.All()
+	require.NoError(t, err)
+	assert.Equal(t, since.Add(time.Minute), subscriptions[0].LastPublished)
+	assert.Equal(t, now, subscriptions[0].LastCheck)
+	assert.Equal(t, since, subscriptions[1].LastPublished, "si falla, se reintenta en la próxima")
+	assert.Equal(t, since, subscriptions[2].LastPublished)
+	logger.AssertNumberOfCalls(t, "Error", 4)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "youtube", "subscriptions.json")
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	require.NoError(t, Add(store, Subscription{GuildID: "1", ChannelID: "c1", Name: "Canal", Queue: true}))
+	checkedAt := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.MarkChecked("1", "c1", checkedAt, checkedAt))
+	assert.ErrorIs(t, store.Delete("2", "c1"), ErrNotFound)
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	subscriptions, err := reopened.List("1")
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.True(t, subscriptions[0].Queue)
+	assert.True(t, checkedAt.Equal(subscriptions[0].LastPublished))
+
+	require.NoError(t, reopened.Delete("1", "c1"))
+	assert.ErrorIs(t, reopened.MarkChecked("1", "c1", checkedAt, checkedAt), ErrNotFound)
+}
+
+func TestAdd_TooMany(t *testing.T) {
+	store := NewInMemoryStore()
+	for i := 0; i < MaxSubscriptionsPerGuild; i++ {
+		require.NoError(t, Add(store, Subscription{GuildID: "1", ChannelID: string(rune('a' + i))}))
+	}
+
+	assert.ErrorIs(t, Add(store, Subscription{GuildID: "1", ChannelID: "otro"}), ErrTooMany)
+	assert.NoError(t, Add(store, Subscription{GuildID: "1", ChannelID: "a", Queue: true}), "volver a suscribirse al mismo canal la reemplaza")
+}
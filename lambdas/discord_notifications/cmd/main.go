@@ -1,21 +1,36 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/config"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/messaging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/queuing"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/templates"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"go.uber.org/zap"
+	"os"
 )
 
-// handler es la función que maneja los eventos de SQS.
-// Recibe un events.SQSEvent y devuelve un error si ocurre.
-func handler(sqsEvent events.SQSEvent) error {
+// eventSource es la fuente de los registros de un evento: "aws:sqs" para los mensajes de la cola y "aws:sns"
+// para las notificaciones del tópico. SNS usa la clave "EventSource"; encoding/json la reconoce sin importar
+// las mayúsculas.
+type eventSource struct {
+	Records []struct {
+		EventSource string `json:"eventSource"`
+	} `json:"Records"`
+}
+
+// handler es la función que maneja los eventos de SQS y de SNS.
+func handler(ctx context.Context, raw json.RawMessage) error {
 	// Crear un nuevo logger usando la librería zap.
-	logger, err := logging.NewZapLogger()
+	logger, err := logging.NewLambdaLogger(os.Getenv)
 	if err != nil {
 		panic("Error creando el logger: " + err.Error())
 	}
@@ -28,6 +43,26 @@ func handler(sqsEvent events.SQSEvent) error {
 			logger.Error("Error cerrando el logger", zap.Error(err))
 		}
 	}()
+	// Los logs de la invocación llevan el ID de la solicitud de Lambda como ID de correlación.
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		ctx = logging.ContextWithCorrelationID(ctx, lc.AwsRequestID)
+	}
+	requestLogger := logging.FromContext(ctx, logger)
+
+	var source eventSource
+	if err := json.Unmarshal(raw, &source); err != nil {
+		return fmt.Errorf("error al analizar el evento: %v", err)
+	}
+	if len(source.Records) == 0 {
+		logger.Info("El evento no tiene registros")
+		return nil
+	}
+
+	messageTemplates, err := loadTemplates(ctx, configEnv)
+	if err != nil {
+		logger.Error("Error al cargar las plantillas de los mensajes", zap.Error(err))
+		return err
+	}
 
 	// Crear una nueva sesión de Discord.
 	discordSession, err := messaging.NewDiscordSessionImpl(configEnv.DiscordToken)
@@ -37,21 +72,72 @@ func handler(sqsEvent events.SQSEvent) error {
 	}
 
 	// Crear un cliente DiscordGo utilizando la sesión de Discord.
-	discordClient := messaging.NewDiscordGoClient(discordSession, logger)
+	discordClient := messaging.NewDiscordGoClient(discordSession, requestLogger)
+
+	if source.Records[0].EventSource == "aws:sns" {
+		var snsEvent events.SNSEvent
+		if err := json.Unmarshal(raw, &snsEvent); err != nil {
+			return fmt.Errorf("error al analizar el evento de SNS: %v", err)
+		}
+		snsConsumer := queuing.NewSNSConsumer(discordClient, requestLogger).WithTemplates(messageTemplates)
+		for _, record := range snsEvent.Records {
+			if err := snsConsumer.ProcessSNSMessage(record.SNS.Subject, record.SNS.Message); err != nil {
+				countNotification(logger, "sns", err)
+				return fmt.Errorf("error al procesar la notificación de SNS: %v", err)
+			}
+			countNotification(logger, "sns", nil)
+		}
+		return nil
+	}
 
+	var sqsEvent events.SQSEvent
+	if err := json.Unmarshal(raw, &sqsEvent); err != nil {
+		return fmt.Errorf("error al analizar el evento de SQS: %v", err)
+	}
 	// Crear un consumidor SQS para procesar los mensajes de la cola.
-	sqsConsumer := queuing.NewSQSConsumer(discordClient, logger)
+	sqsConsumer := queuing.NewSQSConsumer(discordClient, requestLogger).WithTemplates(messageTemplates)
 
 	// Iterar sobre cada mensaje en el evento SQS.
 	for _, message := range sqsEvent.Records {
 		// Procesar el mensaje utilizando el consumidor SQS.
 		if err := sqsConsumer.ProcessSQSEvent([]byte(message.Body)); err != nil {
+			countNotification(logger, "sqs", err)
 			return fmt.Errorf("error al procesar el mensaje de la cola SQS: %v", err)
 		}
+		countNotification(logger, "sqs", nil)
 	}
 	return nil
 }
 
+// countNotification registra en CloudWatch la notificación enviada o fallida, con la fuente como dimensión.
+func countNotification(logger *logging.ZapLogger, source string, err error) {
+	name := "NotificationsSent"
+	if err != nil {
+		name = "NotificationsFailed"
+	}
+	logger.Count(name, 1, map[string]string{"Source": source})
+}
+
+// loadTemplates carga las plantillas de los mensajes de S3 y de las variables de entorno; las de las variables
+// de entorno tienen prioridad. Se cargan en cada invocación para que los cambios se apliquen enseguida.
+func loadTemplates(ctx context.Context, cfg *config.Config) (*templates.Templates, error) {
+	sources := make(map[string]string)
+	if cfg.TemplatesBucket != "" {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error al cargar la configuración de AWS: %v", err)
+		}
+		sources, err = templates.FromS3(ctx, s3.NewFromConfig(awsCfg), cfg.TemplatesBucket, cfg.TemplatesPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for name, source := range templates.FromEnv(os.Getenv) {
+		sources[name] = source
+	}
+	return templates.Parse(sources)
+}
+
 func main() {
 	// Iniciar la función de lambda pasando la función handler como argumento.
 	lambda.Start(handler)
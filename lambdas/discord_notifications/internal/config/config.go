@@ -6,11 +6,20 @@ import (
 
 type Config struct {
 	DiscordToken string
+	// TemplatesBucket es el bucket de S3 con las plantillas de los mensajes, guardadas como {TemplatesPrefix}{nombre}.tmpl.
+	// Si está vacío, las plantillas se toman solo de las variables de entorno TEMPLATE_{NOMBRE}.
+	TemplatesBucket string
+	TemplatesPrefix string
 }
 
 func LoadConfig() *Config {
 	config := &Config{
-		DiscordToken: os.Getenv("DISCORD_TOKEN"),
+		DiscordToken:    os.Getenv("DISCORD_TOKEN"),
+		TemplatesBucket: os.Getenv("TEMPLATES_S3_BUCKET"),
+		TemplatesPrefix: os.Getenv("TEMPLATES_S3_PREFIX"),
+	}
+	if config.TemplatesPrefix == "" {
+		config.TemplatesPrefix = "templates/"
 	}
 	return config
 }
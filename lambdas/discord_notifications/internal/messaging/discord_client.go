@@ -1,7 +1,7 @@
 package messaging
 
 import (
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 )
@@ -10,6 +10,7 @@ import (
 type DiscordMessenger interface {
 	SendMessage(channelID string, embed *discordgo.MessageEmbed) error // SendMessage envía un mensaje con un embed al canal especificado.
 	SendMessageToServers(embed *discordgo.MessageEmbed) error          // SendMessageToServers envía un mensaje a todos los servidores conectados.
+	SendComplexMessageToServers(msg *discordgo.MessageSend) error      // SendComplexMessageToServers envía un mensaje con texto y embeds a todos los servidores conectados.
 }
 
 // DiscordGoClient es una implementación de la interfaz DiscordMessenger utilizando DiscordGo.
@@ -39,6 +40,27 @@ func (d *DiscordGoClient) SendMessage(channelID string, embed *discordgo.Message
 
 // SendMessageToServers envía un mensaje a todos los servidores conectados.
 func (d *DiscordGoClient) SendMessageToServers(embed *discordgo.MessageEmbed) error {
+	return d.sendToServers(func(channelID string) error {
+		return d.SendMessage(channelID, embed)
+	})
+}
+
+// SendComplexMessageToServers envía un mensaje con texto, embeds y menciones a todos los servidores conectados.
+func (d *DiscordGoClient) SendComplexMessageToServers(msg *discordgo.MessageSend) error {
+	return d.sendToServers(func(channelID string) error {
+		_, err := d.Session.ChannelMessageSendComplex(channelID, msg)
+		if err != nil {
+			d.Logger.Error("Error al enviar mensaje al canal", zap.String("ID del canal:", channelID), zap.Error(err))
+			return err
+		}
+		d.Logger.Info("Mensaje enviado al canal", zap.String("ID del canal:", channelID))
+		return nil
+	})
+}
+
+// sendToServers envía el mensaje con send al canal 'status-bot' de cada servidor conectado; si el canal no
+// existe, lo crea.
+func (d *DiscordGoClient) sendToServers(send func(channelID string) error) error {
 	// Obtener la lista de servidores
 	guilds, err := d.Session.UserGuilds(0, "", "", true)
 	if err != nil {
@@ -67,8 +89,8 @@ func (d *DiscordGoClient) SendMessageToServers(embed *discordgo.MessageEmbed) er
 			}
 		}
 
-		// Enviar el mensaje embed al canal 'statusBot'
-		if err := d.SendMessage(channel.ID, embed); err != nil {
+		// Enviar el mensaje al canal 'statusBot'
+		if err := send(channel.ID); err != nil {
 			d.Logger.Error("Error al enviar el mensaje a Discord en el servidor", zap.String("Servidor:", guild.Name), zap.Error(err))
 		} else {
 			d.Logger.Info("Mensaje enviado a Discord en el servidor",
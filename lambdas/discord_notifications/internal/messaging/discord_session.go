@@ -7,6 +7,7 @@ import (
 // DiscordSession define la interfaz para la sesión de Discord.
 type DiscordSession interface {
 	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)                // ChannelMessageSendEmbed envía un mensaje con un embed al canal especificado.
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)                // ChannelMessageSendComplex envía un mensaje con texto, embeds y menciones al canal especificado.
 	UserGuilds(limit int, beforeID, afterID string, withCounts bool, options ...discordgo.RequestOption) (st []*discordgo.UserGuild, err error)             // UserGuilds obtiene los gremios a los que pertenece el usuario.
 	GuildChannels(guildID string, options ...discordgo.RequestOption) (st []*discordgo.Channel, err error)                                                  // GuildChannels obtiene los canales de un gremio especificado.
 	GuildChannelCreate(guildID, name string, ctype discordgo.ChannelType, options ...discordgo.RequestOption) (st *discordgo.Channel, err error)            // GuildChannelCreate crea un nuevo canal en un gremio.
@@ -40,6 +41,11 @@ func (d *DiscordSessionImpl) ChannelMessageSendEmbed(channelID string, embed *di
 	return d.session.ChannelMessageSendEmbed(channelID, embed, options...)
 }
 
+// ChannelMessageSendComplex envía un mensaje con texto, embeds y menciones al canal especificado.
+func (d *DiscordSessionImpl) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return d.session.ChannelMessageSendComplex(channelID, data, options...)
+}
+
 // UserGuilds obtiene los gremios a los que pertenece el usuario.
 func (d *DiscordSessionImpl) UserGuilds(limit int, beforeID, afterID string, withCounts bool, options ...discordgo.RequestOption) (st []*discordgo.UserGuild, err error) {
 	return d.session.UserGuilds(limit, beforeID, afterID, withCounts, options...)
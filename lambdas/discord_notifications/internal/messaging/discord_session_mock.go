@@ -16,6 +16,11 @@ func (m *MockDiscordSession) ChannelMessageSendEmbed(channelID string, embed *di
 	return args.Get(0).(*discordgo.Message), args.Error(1)
 }
 
+func (m *MockDiscordSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	args := m.Called(channelID, data, options)
+	return args.Get(0).(*discordgo.Message), args.Error(1)
+}
+
 func (m *MockDiscordSession) UserGuilds(limit int, beforeID, afterID string, withCounts bool, options ...discordgo.RequestOption) ([]*discordgo.UserGuild, error) {
 	args := m.Called(limit, beforeID, afterID, withCounts, options)
 	return args.Get(0).([]*discordgo.UserGuild), args.Error(1)
@@ -48,3 +53,11 @@ func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
 func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
 	m.Called(msg, fields)
 }
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
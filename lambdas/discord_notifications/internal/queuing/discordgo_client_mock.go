@@ -20,6 +20,11 @@ func (m *MockDiscordGoClient) SendMessageToServers(embed *discordgo.MessageEmbed
 	return args.Error(0)
 }
 
+func (m *MockDiscordGoClient) SendComplexMessageToServers(msg *discordgo.MessageSend) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
 // MockLogger Mock para la interfaz Logger
 type MockLogger struct {
 	mock.Mock
@@ -32,3 +37,11 @@ func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
 func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
 	m.Called(msg, fields)
 }
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
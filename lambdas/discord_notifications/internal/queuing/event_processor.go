@@ -3,8 +3,9 @@ package queuing
 import (
 	"encoding/json"
 	"errors"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/messaging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/templates"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +17,7 @@ type EventProcessor interface {
 // SQSConsumer es una implementación de EventProcessor que consume eventos desde una cola SQS.
 type SQSConsumer struct {
 	discordClient messaging.DiscordMessenger // Cliente Discord para enviar mensajes.
+	templates     *templates.Templates       // Plantillas que reemplazan al formato por defecto de cada evento.
 	logger        logging.Logger             // Logger para registrar eventos.
 }
 
@@ -27,6 +29,13 @@ func NewSQSConsumer(discordClient messaging.DiscordMessenger, logger logging.Log
 	}
 }
 
+// WithTemplates hace que los eventos que tienen plantilla se armen con ella. La plantilla recibe el evento de
+// GitHub tal como llega, por ejemplo {{ .release.tag_name }}.
+func (s *SQSConsumer) WithTemplates(t *templates.Templates) *SQSConsumer {
+	s.templates = t
+	return s
+}
+
 // ProcessSQSEvent procesa un evento proveniente de una cola SQS.
 func (s *SQSConsumer) ProcessSQSEvent(body []byte) error {
 	var event map[string]interface{}
@@ -43,6 +52,9 @@ func (s *SQSConsumer) ProcessSQSEvent(body []byte) error {
 	var formatter EventFormatter
 	switch action {
 	case "published":
+		if s.templates.Has(templates.Release) {
+			return s.sendTemplate(templates.Release, event)
+		}
 		formatter = &ReleaseEventFormatter{}
 	//case "completed":
 	//	formatter = &WorkflowActionEventFormatter{}
@@ -63,3 +75,17 @@ func (s *SQSConsumer) ProcessSQSEvent(body []byte) error {
 	}
 	return nil
 }
+
+// sendTemplate arma el evento con la plantilla y lo envía.
+func (s *SQSConsumer) sendTemplate(name string, event map[string]interface{}) error {
+	msg, err := s.templates.Render(name, event)
+	if err != nil {
+		s.logger.Error("Error al formatear el evento con la plantilla", zap.Error(err))
+		return err
+	}
+	if err := s.discordClient.SendComplexMessageToServers(msg); err != nil {
+		s.logger.Error("Error al enviar el mensaje a Discord", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package queuing
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+)
+
+const (
+	// NotificationTypeDeployment es una notificación de un despliegue del bot.
+	NotificationTypeDeployment = "deployment"
+	// NotificationTypeIncident es una notificación de un incidente (caída, degradación) del bot.
+	NotificationTypeIncident = "incident"
+)
+
+const (
+	colorSuccess    = 0x34a853
+	colorFailure    = 0xea4335
+	colorInProgress = 0xfbbc05
+	colorDefault    = 0x5865F2
+)
+
+// maxEmbedDescription es el largo máximo de la descripción de un embed de Discord.
+const maxEmbedDescription = 4096
+
+type (
+	// Notification es el mensaje que se publica en el tópico de SNS para avisar de un despliegue o un incidente.
+	Notification struct {
+		Type        string              `json:"type"`
+		Status      string              `json:"status"`
+		Title       string              `json:"title"`
+		Description string              `json:"description"`
+		Service     string              `json:"service"`
+		Environment string              `json:"environment"`
+		URL         string              `json:"url"`
+		Commit      *NotificationCommit `json:"commit"`
+		Links       []NotificationLink  `json:"links"`
+		Timestamp   string              `json:"timestamp"`
+	}
+
+	// NotificationCommit es el commit que se desplegó.
+	NotificationCommit struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+		Author  string `json:"author"`
+		URL     string `json:"url"`
+	}
+
+	// NotificationLink es un link que se muestra en el mensaje, por ejemplo al pipeline o al dashboard.
+	NotificationLink struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+)
+
+// NotificationFormatter formatea las notificaciones de despliegues e incidentes en mensajes de Discord.
+type NotificationFormatter struct{}
+
+// FormatNotification formatea la notificación en un embed con el color de su estado, el commit y los links.
+func (f *NotificationFormatter) FormatNotification(notification *Notification) (*discordgo.MessageEmbed, error) {
+	icon, defaultTitle, err := notificationKind(notification.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	title := notification.Title
+	if title == "" {
+		title = defaultTitle
+	}
+	if notification.Status != "" {
+		title = fmt.Sprintf("%s [%s]", title, strings.ToUpper(notification.Status))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s %s", icon, title),
+		Description: truncate(notification.Description, maxEmbedDescription),
+		URL:         notification.URL,
+		Color:       statusColor(notification.Status),
+		Timestamp:   notification.Timestamp,
+		Author: &discordgo.MessageEmbedAuthor{
+			Name: "ButakeroMusicBotGo",
+		},
+	}
+
+	if notification.Service != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Servicio", Value: notification.Service, Inline: true})
+	}
+	if notification.Environment != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Entorno", Value: notification.Environment, Inline: true})
+	}
+	if commit := notification.Commit; commit != nil && commit.SHA != "" {
+		sha := commit.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		value := fmt.Sprintf("`%s`", sha)
+		if commit.URL != "" {
+			value = fmt.Sprintf("[`%s`](%s)", sha, commit.URL)
+		}
+		if message := strings.SplitN(commit.Message, "\n", 2)[0]; message != "" {
+			value += " " + message
+		}
+		if commit.Author != "" {
+			value += fmt.Sprintf(" (%s)", commit.Author)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Commit", Value: truncate(value, 1024)})
+	}
+
+	var links []string
+	for _, link := range notification.Links {
+		if link.URL == "" {
+			continue
+		}
+		name := link.Name
+		if name == "" {
+			name = link.URL
+		}
+		links = append(links, fmt.Sprintf("[%s](%s)", name, link.URL))
+	}
+	if len(links) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Links", Value: truncate(strings.Join(links, " · "), 1024)})
+	}
+	return embed, nil
+}
+
+// notificationKind devuelve el ícono y el título por defecto del tipo de notificación.
+func notificationKind(notificationType string) (icon, defaultTitle string, err error) {
+	switch notificationType {
+	case NotificationTypeDeployment:
+		return "🚀", "Despliegue", nil
+	case NotificationTypeIncident:
+		return "🚨", "Incidente", nil
+	default:
+		return "", "", errors.New("tipo de notificación desconocido: " + notificationType)
+	}
+}
+
+// statusColor devuelve el color del embed según el estado: verde si terminó bien, rojo si falló y amarillo si
+// está en curso.
+func statusColor(status string) int {
+	switch strings.ToLower(status) {
+	case "success", "succeeded", "resolved", "completed":
+		return colorSuccess
+	case "failure", "failed", "error", "critical", "outage":
+		return colorFailure
+	case "started", "in_progress", "pending", "investigating", "degraded":
+		return colorInProgress
+	default:
+		return colorDefault
+	}
+}
+
+// truncate corta el texto al largo máximo que acepta Discord.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
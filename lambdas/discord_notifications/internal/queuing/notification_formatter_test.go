@@ -0,0 +1,68 @@
+package queuing
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNotificationFormatter_FormatDeployment(t *testing.T) {
+	formatter := &NotificationFormatter{}
+
+	embed, err := formatter.FormatNotification(&Notification{
+		Type:        NotificationTypeDeployment,
+		Status:      "success",
+		Title:       "Despliegue v1.2.3",
+		Description: "Se desplegó la nueva versión",
+		Service:     "bot",
+		Environment: "production",
+		URL:         "https://example.com/deploy",
+		Commit: &NotificationCommit{
+			SHA:     "0123456789abcdef",
+			Message: "Agregar comando /loop\n\nDetalles",
+			Author:  "tomas",
+			URL:     "https://github.com/example/commit/0123456",
+		},
+		Links:     []NotificationLink{{Name: "Pipeline", URL: "https://example.com/pipeline"}, {Name: "Vacío"}},
+		Timestamp: "2024-05-01T12:00:00Z",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "🚀 Despliegue v1.2.3 [SUCCESS]", embed.Title)
+	assert.Equal(t, colorSuccess, embed.Color)
+	assert.Equal(t, "https://example.com/deploy", embed.URL)
+	assert.Equal(t, "2024-05-01T12:00:00Z", embed.Timestamp)
+	assert.Len(t, embed.Fields, 4)
+	assert.Equal(t, "[`0123456`](https://github.com/example/commit/0123456) Agregar comando /loop (tomas)", embed.Fields[2].Value)
+	assert.Equal(t, "[Pipeline](https://example.com/pipeline)", embed.Fields[3].Value)
+}
+
+func TestNotificationFormatter_FormatIncident(t *testing.T) {
+	formatter := &NotificationFormatter{}
+
+	embed, err := formatter.FormatNotification(&Notification{
+		Type:        NotificationTypeIncident,
+		Status:      "investigating",
+		Description: strings.Repeat("a", maxEmbedDescription+10),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "🚨 Incidente [INVESTIGATING]", embed.Title)
+	assert.Equal(t, colorInProgress, embed.Color)
+	assert.Len(t, []rune(embed.Description), maxEmbedDescription)
+	assert.Empty(t, embed.Fields)
+}
+
+func TestNotificationFormatter_UnknownType(t *testing.T) {
+	formatter := &NotificationFormatter{}
+
+	_, err := formatter.FormatNotification(&Notification{Type: "otro"})
+
+	assert.Error(t, err)
+}
+
+func TestStatusColor(t *testing.T) {
+	assert.Equal(t, colorFailure, statusColor("FAILED"))
+	assert.Equal(t, colorSuccess, statusColor("resolved"))
+	assert.Equal(t, colorDefault, statusColor(""))
+}
@@ -0,0 +1,95 @@
+package queuing
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/messaging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/templates"
+	"go.uber.org/zap"
+)
+
+// SNSConsumer procesa las notificaciones de despliegues e incidentes publicadas en un tópico de SNS.
+type SNSConsumer struct {
+	discordClient messaging.DiscordMessenger // Cliente Discord para enviar mensajes.
+	formatter     *NotificationFormatter
+	templates     *templates.Templates // Plantillas que reemplazan al formato por defecto de cada tipo.
+	logger        logging.Logger       // Logger para registrar eventos.
+}
+
+// NotificationTemplateData son los datos que reciben las plantillas de las notificaciones: los campos de la
+// notificación más el ícono y el color que les corresponden por tipo y estado.
+type NotificationTemplateData struct {
+	*Notification
+	Icon  string
+	Color int
+}
+
+// NewSNSConsumer crea una nueva instancia de SNSConsumer.
+func NewSNSConsumer(discordClient messaging.DiscordMessenger, logger logging.Logger) *SNSConsumer {
+	return &SNSConsumer{
+		discordClient: discordClient,
+		formatter:     &NotificationFormatter{},
+		logger:        logger,
+	}
+}
+
+// WithTemplates hace que las notificaciones de los tipos que tienen plantilla se armen con ella.
+func (s *SNSConsumer) WithTemplates(t *templates.Templates) *SNSConsumer {
+	s.templates = t
+	return s
+}
+
+// ProcessSNSMessage procesa el mensaje de una notificación de SNS. Si el mensaje no tiene título, se usa el
+// subject de la notificación.
+func (s *SNSConsumer) ProcessSNSMessage(subject, message string) error {
+	var notification Notification
+	if err := json.Unmarshal([]byte(message), &notification); err != nil {
+		s.logger.Error("Error al analizar el mensaje de SNS", zap.Error(err))
+		return errors.New("error al analizar el mensaje de SNS")
+	}
+	if notification.Title == "" {
+		notification.Title = subject
+	}
+
+	if s.templates.Has(notification.Type) {
+		if err := s.sendTemplate(&notification); err != nil {
+			return err
+		}
+	} else {
+		embed, err := s.formatter.FormatNotification(&notification)
+		if err != nil {
+			s.logger.Error("Error al formatear la notificación", zap.Error(err))
+			return err
+		}
+		if err := s.discordClient.SendMessageToServers(embed); err != nil {
+			s.logger.Error("Error al enviar el mensaje a Discord", zap.Error(err))
+			return err
+		}
+	}
+	s.logger.Info("Notificación enviada", zap.String("type", notification.Type), zap.String("status", notification.Status))
+	return nil
+}
+
+// sendTemplate arma la notificación con la plantilla de su tipo y la envía.
+func (s *SNSConsumer) sendTemplate(notification *Notification) error {
+	icon, _, err := notificationKind(notification.Type)
+	if err != nil {
+		s.logger.Error("Error al formatear la notificación", zap.Error(err))
+		return err
+	}
+	msg, err := s.templates.Render(notification.Type, NotificationTemplateData{
+		Notification: notification,
+		Icon:         icon,
+		Color:        statusColor(notification.Status),
+	})
+	if err != nil {
+		s.logger.Error("Error al formatear la notificación con la plantilla", zap.Error(err))
+		return err
+	}
+	if err := s.discordClient.SendComplexMessageToServers(msg); err != nil {
+		s.logger.Error("Error al enviar el mensaje a Discord", zap.Error(err))
+		return err
+	}
+	return nil
+}
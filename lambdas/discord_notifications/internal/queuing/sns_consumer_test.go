@@ -0,0 +1,73 @@
+package queuing
+
+import (
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/message_processing/internal/templates"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestProcessSNSMessage_UsesSubjectAsTitle(t *testing.T) {
+	mockDiscordClient := new(MockDiscordGoClient)
+	mockLogger := new(MockLogger)
+	consumer := NewSNSConsumer(mockDiscordClient, mockLogger)
+
+	mockLogger.On("Info", "Notificación enviada", mock.AnythingOfType("[]zapcore.Field")).Return()
+	mockDiscordClient.On("SendMessageToServers", mock.MatchedBy(func(embed *discordgo.MessageEmbed) bool {
+		return embed.Title == "🚨 Caída de la API [RESOLVED]" && embed.Color == colorSuccess
+	})).Return(nil)
+
+	err := consumer.ProcessSNSMessage("Caída de la API", `{"type": "incident", "status": "resolved"}`)
+
+	assert.NoError(t, err)
+	mockDiscordClient.AssertExpectations(t)
+}
+
+func TestProcessSNSMessage_InvalidJSON(t *testing.T) {
+	mockDiscordClient := new(MockDiscordGoClient)
+	mockLogger := new(MockLogger)
+	consumer := NewSNSConsumer(mockDiscordClient, mockLogger)
+
+	mockLogger.On("Error", "Error al analizar el mensaje de SNS", mock.AnythingOfType("[]zapcore.Field")).Return()
+
+	err := consumer.ProcessSNSMessage("", `{"type":`)
+
+	assert.Error(t, err)
+	mockDiscordClient.AssertNotCalled(t, "SendMessageToServers", mock.Anything)
+}
+
+func TestProcessSNSMessage_ErrorSendingMessage(t *testing.T) {
+	mockDiscordClient := new(MockDiscordGoClient)
+	mockLogger := new(MockLogger)
+	consumer := NewSNSConsumer(mockDiscordClient, mockLogger)
+
+	mockLogger.On("Error", "Error al enviar el mensaje a Discord", mock.AnythingOfType("[]zapcore.Field")).Return()
+	mockDiscordClient.On("SendMessageToServers", mock.Anything).Return(errors.New("send message error"))
+
+	err := consumer.ProcessSNSMessage("", `{"type": "deployment", "status": "failed"}`)
+
+	assert.Error(t, err)
+}
+
+func TestProcessSNSMessage_WithTemplate(t *testing.T) {
+	mockDiscordClient := new(MockDiscordGoClient)
+	mockLogger := new(MockLogger)
+	tmpl, err := templates.Parse(map[string]string{
+		templates.Deployment: `{"content": "<@&42>", "embeds": [{"title": {{ json (printf "%s %s" .Icon .Service) }}, "color": {{ .Color }}}]}`,
+	})
+	assert.NoError(t, err)
+	consumer := NewSNSConsumer(mockDiscordClient, mockLogger).WithTemplates(tmpl)
+
+	mockLogger.On("Info", "Notificación enviada", mock.AnythingOfType("[]zapcore.Field")).Return()
+	mockDiscordClient.On("SendComplexMessageToServers", mock.MatchedBy(func(msg *discordgo.MessageSend) bool {
+		return msg.Content == "<@&42>" && msg.Embeds[0].Title == "🚀 bot" && msg.Embeds[0].Color == colorSuccess
+	})).Return(nil)
+
+	err = consumer.ProcessSNSMessage("", `{"type": "deployment", "status": "success", "service": "bot"}`)
+
+	assert.NoError(t, err)
+	mockDiscordClient.AssertExpectations(t)
+	mockDiscordClient.AssertNotCalled(t, "SendMessageToServers", mock.Anything)
+}
@@ -0,0 +1,51 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"io"
+	"strings"
+)
+
+// S3API es la operación de S3 que usa FromS3.
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// FromEnv devuelve las plantillas de las variables de entorno TEMPLATE_{NOMBRE}, por ejemplo TEMPLATE_RELEASE.
+func FromEnv(getenv func(string) string) map[string]string {
+	sources := make(map[string]string)
+	for _, name := range Names {
+		if source := getenv("TEMPLATE_" + strings.ToUpper(name)); source != "" {
+			sources[name] = source
+		}
+	}
+	return sources
+}
+
+// FromS3 devuelve las plantillas guardadas en el bucket como {prefix}{nombre}.tmpl. Las que no existen se omiten.
+func FromS3(ctx context.Context, client S3API, bucket, prefix string) (map[string]string, error) {
+	sources := make(map[string]string)
+	for _, name := range Names {
+		key := prefix + name + ".tmpl"
+		out, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			var noSuchKey *types.NoSuchKey
+			if errors.As(err, &noSuchKey) {
+				continue
+			}
+			return nil, fmt.Errorf("error al descargar la plantilla %s de S3: %w", key, err)
+		}
+		data, err := io.ReadAll(out.Body)
+		out.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error al leer la plantilla %s de S3: %w", key, err)
+		}
+		sources[name] = string(data)
+	}
+	return sources, nil
+}
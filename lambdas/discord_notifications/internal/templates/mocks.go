@@ -0,0 +1,17 @@
+package templates
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockS3API struct {
+	mock.Mock
+}
+
+func (m *MockS3API) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	args := m.Called(*params.Key)
+	out, _ := args.Get(0).(*s3.GetObjectOutput)
+	return out, args.Error(1)
+}
@@ -0,0 +1,120 @@
+// Package templates arma los mensajes de Discord a partir de plantillas de Go (text/template), para cambiar el
+// diseño de los embeds, las menciones y los textos de cada despliegue sin recompilar la lambda.
+//
+// Cada plantilla genera el JSON de un mensaje de Discord con content, embeds y allowed_mentions. Los textos se
+// escapan con la función json, por ejemplo:
+//
+//	{
+//	  "content": "<@&123456789012345678>",
+//	  "allowed_mentions": {"roles": ["123456789012345678"]},
+//	  "embeds": [{"title": {{ json (printf "Nueva versión %s" .release.tag_name) }}, "color": 5793266}]
+//	}
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"text/template"
+)
+
+// Nombres de las plantillas: la de los eventos de release de GitHub y una por tipo de notificación de SNS.
+const (
+	Release    = "release"
+	Deployment = "deployment"
+	Incident   = "incident"
+)
+
+// Names son las plantillas que se buscan en las variables de entorno y en S3.
+var Names = []string{Release, Deployment, Incident}
+
+// message es el mensaje que genera una plantilla. No se decodifica directo en discordgo.MessageSend porque sus
+// componentes son interfaces.
+type message struct {
+	Content         string                            `json:"content"`
+	Embeds          []*discordgo.MessageEmbed         `json:"embeds"`
+	AllowedMentions *discordgo.MessageAllowedMentions `json:"allowed_mentions"`
+}
+
+// Templates son las plantillas cargadas, por nombre. Un Templates nil no tiene ninguna plantilla.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// Parse compila las plantillas. Falla si alguna tiene un error de sintaxis, para no publicar mensajes rotos.
+func Parse(sources map[string]string) (*Templates, error) {
+	t := &Templates{templates: make(map[string]*template.Template, len(sources))}
+	for name, source := range sources {
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("error al compilar la plantilla %s: %w", name, err)
+		}
+		t.templates[name] = tmpl
+	}
+	return t, nil
+}
+
+// Has indica si hay una plantilla con ese nombre.
+func (t *Templates) Has(name string) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.templates[name]
+	return ok
+}
+
+// Render ejecuta la plantilla con los datos y devuelve el mensaje que generó.
+func (t *Templates) Render(name string, data interface{}) (*discordgo.MessageSend, error) {
+	if !t.Has(name) {
+		return nil, fmt.Errorf("no hay una plantilla %s", name)
+	}
+	var buf bytes.Buffer
+	if err := t.templates[name].Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error al ejecutar la plantilla %s: %w", name, err)
+	}
+	var msg message
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		return nil, fmt.Errorf("la plantilla %s no generó un mensaje válido: %w", name, err)
+	}
+	if msg.Content == "" && len(msg.Embeds) == 0 {
+		return nil, errors.New("la plantilla " + name + " generó un mensaje vacío")
+	}
+	return &discordgo.MessageSend{Content: msg.Content, Embeds: msg.Embeds, AllowedMentions: msg.AllowedMentions}, nil
+}
+
+// funcs son las funciones disponibles en las plantillas.
+var funcs = template.FuncMap{
+	// json escapa el valor para usarlo dentro del JSON del mensaje, comillas incluidas.
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"truncate": func(max int, s string) string {
+		runes := []rune(s)
+		if len(runes) <= max {
+			return s
+		}
+		return string(runes[:max-1]) + "…"
+	},
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"firstLine": func(s string) string { return strings.SplitN(s, "\n", 2)[0] },
+	"shortSHA": func(sha string) string {
+		if len(sha) > 7 {
+			return sha[:7]
+		}
+		return sha
+	},
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
@@ -0,0 +1,82 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+const releaseTemplate = `{
+	"content": "<@&42> salió {{ .release.tag_name }}",
+	"allowed_mentions": {"roles": ["42"]},
+	"embeds": [{
+		"title": {{ json (printf "Nueva versión %s" .release.tag_name) }},
+		"description": {{ json (truncate 11 .release.body) }}
+	}]
+}`
+
+func TestTemplates_Render(t *testing.T) {
+	tmpl, err := Parse(map[string]string{Release: releaseTemplate, Incident: "  "})
+	assert.NoError(t, err)
+	assert.True(t, tmpl.Has(Release))
+	assert.False(t, tmpl.Has(Incident))
+
+	msg, err := tmpl.Render(Release, map[string]interface{}{
+		"release": map[string]interface{}{"tag_name": "v1.2.0", "body": "Arreglos \"varios\" y mejoras"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<@&42> salió v1.2.0", msg.Content)
+	assert.Equal(t, []string{"42"}, msg.AllowedMentions.Roles)
+	assert.Equal(t, "Nueva versión v1.2.0", msg.Embeds[0].Title)
+	assert.Equal(t, "Arreglos \"…", msg.Embeds[0].Description)
+}
+
+func TestTemplates_Errors(t *testing.T) {
+	_, err := Parse(map[string]string{Release: "{{ .release"})
+	assert.ErrorContains(t, err, "release")
+
+	tmpl, err := Parse(map[string]string{Release: "no es json", Deployment: `{"content": ""}`})
+	assert.NoError(t, err)
+	_, err = tmpl.Render(Release, nil)
+	assert.ErrorContains(t, err, "no generó un mensaje válido")
+	_, err = tmpl.Render(Deployment, nil)
+	assert.ErrorContains(t, err, "vacío")
+
+	var none *Templates
+	assert.False(t, none.Has(Release))
+}
+
+func TestFromEnv(t *testing.T) {
+	env := map[string]string{"TEMPLATE_DEPLOYMENT": `{"content": "deploy"}`}
+
+	sources := FromEnv(func(key string) string { return env[key] })
+
+	assert.Equal(t, map[string]string{Deployment: `{"content": "deploy"}`}, sources)
+}
+
+func TestFromS3(t *testing.T) {
+	client := new(MockS3API)
+	client.On("GetObject", "templates/release.tmpl").Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("release"))}, nil)
+	client.On("GetObject", "templates/deployment.tmpl").Return(nil, &types.NoSuchKey{})
+	client.On("GetObject", "templates/incident.tmpl").Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("incident"))}, nil)
+
+	sources, err := FromS3(context.Background(), client, "bucket", "templates/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{Release: "release", Incident: "incident"}, sources)
+}
+
+func TestFromS3_Error(t *testing.T) {
+	client := new(MockS3API)
+	client.On("GetObject", "templates/release.tmpl").Return(nil, errors.New("acceso denegado"))
+
+	_, err := FromS3(context.Background(), client, "bucket", "templates/")
+
+	assert.ErrorContains(t, err, "acceso denegado")
+}
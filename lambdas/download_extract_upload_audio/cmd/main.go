@@ -1,5 +1,46 @@
 package main
 
-func main() {
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/downloader"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/s3uploader"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/transcoder"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"os"
+)
+
+// handler transcodifica las canciones que el bot publica en la cola de SQS (TRANSCODE_QUEUEURL del bot). La
+// función tiene que tener habilitado ReportBatchItemFailures para que solo se reentreguen los mensajes fallidos.
+func handler(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	logger, err := logging.NewLambdaLogger(os.Getenv)
+	if err != nil {
+		panic("Error creando el logger: " + err.Error())
+	}
+	defer func() {
+		err := logger.Close()
+		if err != nil {
+			logger.Error("Error cerrando el logger", zap.Error(err))
+		}
+	}()
+
+	cfg := config.LoadConfig()
+	uploader, err := s3uploader.NewS3UploaderWithDefaultCredentials(cfg.Region)
+	if err != nil {
+		return events.SQSEventResponse{}, err
+	}
 
+	service := transcoder.NewService(downloader.NewCommandDownloader(), uploader, cfg.Bucket, cfg.WorkDir, logger)
+	response := service.Process(ctx, event)
+	failed := len(response.BatchItemFailures)
+	logger.Count("SongsTranscoded", float64(len(event.Records)-failed), nil)
+	logger.Count("TranscodeFailures", float64(failed), nil)
+	return response, nil
+}
+
+func main() {
+	lambda.Start(handler)
 }
@@ -2,13 +2,25 @@ module github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio
 
 go 1.21.2
 
-require github.com/aws/aws-sdk-go v1.54.10
+require (
+	github.com/Tomas-vilte/GoMusicBot v0.0.0-00010101000000-000000000000
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/aws/aws-sdk-go v1.54.10
+	github.com/stretchr/testify v1.9.0
+	go.uber.org/zap v1.27.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/getsentry/sentry-go v0.27.0 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	github.com/stretchr/testify v1.9.0 // indirect
+	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/text v0.15.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/Tomas-vilte/GoMusicBot => ../..
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+)
+
+type Config struct {
+	// Region es la región de AWS del bucket.
+	Region string
+	// Bucket es el bucket de S3 en el que se guarda el audio transcodificado; el bot lo lee de ahí.
+	Bucket string
+	// WorkDir es el directorio en el que se transcodifica el audio antes de subirlo. En Lambda solo se puede escribir
+	// en /tmp.
+	WorkDir string
+}
+
+// LoadConfig carga la configuración de las variables de entorno.
+func LoadConfig() *Config {
+	config := &Config{
+		Region:  os.Getenv("AWS_REGION"),
+		Bucket:  os.Getenv("S3_BUCKET"),
+		WorkDir: os.Getenv("WORK_DIR"),
+	}
+	if config.WorkDir == "" {
+		config.WorkDir = os.TempDir()
+	}
+	return config
+}
@@ -1 +1,57 @@
 package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// stderrTailSize es la cantidad de bytes de la salida de error del pipeline que se adjuntan a los errores.
+const stderrTailSize = 2048
+
+// pipeline descarga el audio con yt-dlp, lo convierte a PCM con ffmpeg y lo codifica a DCA, igual que el bot
+// cuando transcodifica las canciones él mismo. La URL se pasa como $1 para que el shell no la interprete.
+const pipeline = `yt-dlp -f "bestaudio[ext=m4a]" --audio-quality 0 -o - --force-overwrites --http-chunk-size 100K "$1" | ` +
+	`ffmpeg -i pipe:0 -b:a 192k -f s16le -ar 48000 -ac 2 pipe:1 | dca`
+
+// Downloader descarga el audio de una canción y lo deja codificado a DCA en un archivo.
+type Downloader interface {
+	DownloadDCA(ctx context.Context, url, path string) error
+}
+
+// CommandDownloader implementa Downloader con yt-dlp, ffmpeg y dca, que tienen que estar en el PATH (en Lambda,
+// en una capa).
+type CommandDownloader struct{}
+
+// NewCommandDownloader crea un CommandDownloader.
+func NewCommandDownloader() *CommandDownloader {
+	return &CommandDownloader{}
+}
+
+// DownloadDCA escribe el audio de la URL en path. Si falla, borra el archivo para no subir audio incompleto.
+func (d *CommandDownloader) DownloadDCA(ctx context.Context, url, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error al crear el archivo %s: %w", path, err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "bash", "-o", "pipefail", "-c", pipeline, "bash", url)
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		output := stderr.Bytes()
+		if len(output) > stderrTailSize {
+			output = output[len(output)-stderrTailSize:]
+		}
+		return fmt.Errorf("error al transcodificar %s: %w: %s", url, err, output)
+	}
+	return nil
+}
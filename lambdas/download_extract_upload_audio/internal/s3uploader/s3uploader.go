@@ -38,6 +38,16 @@ func NewS3Uploader(region, accessKey, secretAccessKey string) (*S3Uploader, erro
 	return &S3Uploader{S3Client: svc}, nil
 }
 
+// NewS3UploaderWithDefaultCredentials crea un nuevo S3Uploader con las credenciales de la cadena estándar de AWS,
+// por ejemplo las del rol de ejecución de la lambda.
+func NewS3UploaderWithDefaultCredentials(region string) (*S3Uploader, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, fmt.Errorf("error al crear la sesión AWS: %v", err)
+	}
+	return &S3Uploader{S3Client: s3.New(sess)}, nil
+}
+
 // NewS3UploaderWithClient crea un nuevo S3Uploader usando un cliente S3 personalizado.
 func NewS3UploaderWithClient(client s3iface.S3API) *S3Uploader {
 	return &S3Uploader{S3Client: client}
@@ -0,0 +1,51 @@
+package transcoder
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+	"io"
+)
+
+type MockDownloader struct {
+	mock.Mock
+}
+
+func (m *MockDownloader) DownloadDCA(ctx context.Context, url, path string) error {
+	args := m.Called(ctx, url, path)
+	return args.Error(0)
+}
+
+type MockUploader struct {
+	mock.Mock
+}
+
+func (m *MockUploader) UploadFile(ctx context.Context, filePath, bucketName, key string) error {
+	args := m.Called(ctx, filePath, bucketName, key)
+	return args.Error(0)
+}
+
+func (m *MockUploader) UploadContent(ctx context.Context, content io.Reader, bucketName, key string) error {
+	args := m.Called(ctx, content, bucketName, key)
+	return args.Error(0)
+}
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
@@ -0,0 +1,101 @@
+// Package transcoder procesa los pedidos de transcodificación que publica el bot en la cola de SQS: descarga el
+// audio de cada canción, lo codifica a DCA y lo sube al bucket, de donde lo lee la caché de audio del bot.
+package transcoder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/downloader"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/download_extract_upload_audio/internal/s3uploader"
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// JobTypePrefetch es el tipo de trabajo con el que el bot pide la transcodificación de una canción.
+const JobTypePrefetch = "prefetch"
+
+type (
+	// Job es el mensaje que publica el bot en la cola, con el mismo formato que sus trabajos.
+	Job struct {
+		Type          string          `json:"type"`
+		GuildID       string          `json:"guild_id"`
+		CorrelationID string          `json:"correlation_id"`
+		Payload       json.RawMessage `json:"payload"`
+	}
+
+	// Song son los campos de la canción del bot que usa la lambda.
+	Song struct {
+		Title string
+		URL   string
+	}
+
+	// PrefetchPayload es el payload de JobTypePrefetch. Key es la clave de S3 en la que el bot busca el audio.
+	PrefetchPayload struct {
+		Song *Song  `json:"song"`
+		Key  string `json:"key"`
+	}
+)
+
+// Service transcodifica las canciones pedidas y las sube al bucket.
+type Service struct {
+	downloader downloader.Downloader
+	uploader   s3uploader.Uploader
+	bucket     string
+	workDir    string
+	logger     logging.Logger
+}
+
+// NewService crea un Service que transcodifica en workDir y sube el audio a bucket.
+func NewService(downloader downloader.Downloader, uploader s3uploader.Uploader, bucket, workDir string, logger logging.Logger) *Service {
+	return &Service{downloader: downloader, uploader: uploader, bucket: bucket, workDir: workDir, logger: logger}
+}
+
+// Process procesa los mensajes del lote. Los que no se pudieron transcodificar o subir se devuelven como fallidos
+// para que SQS los reentregue; los mensajes inválidos se descartan, porque reintentarlos no los arregla.
+func (s *Service) Process(ctx context.Context, event events.SQSEvent) events.SQSEventResponse {
+	var response events.SQSEventResponse
+	for _, message := range event.Records {
+		if err := s.processMessage(ctx, message.Body); err != nil {
+			s.logger.Error("Error al transcodificar la canción", zap.String("messageID", message.MessageId), zap.Error(err))
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: message.MessageId})
+		}
+	}
+	return response
+}
+
+func (s *Service) processMessage(ctx context.Context, body string) error {
+	var job Job
+	if err := json.Unmarshal([]byte(body), &job); err != nil {
+		s.logger.Error("Se descartó un mensaje inválido", zap.Error(err))
+		return nil
+	}
+	if job.Type != JobTypePrefetch {
+		s.logger.Error("Se descartó un trabajo de otro tipo", zap.String("type", job.Type))
+		return nil
+	}
+	var payload PrefetchPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || payload.Song == nil || payload.Song.URL == "" || payload.Key == "" {
+		s.logger.Error("Se descartó un trabajo sin canción o sin clave", zap.String("correlationID", job.CorrelationID), zap.Error(err))
+		return nil
+	}
+
+	file := filepath.Join(s.workDir, path.Base(payload.Key))
+	defer os.Remove(file)
+	if err := s.downloader.DownloadDCA(ctx, payload.Song.URL, file); err != nil {
+		return err
+	}
+	if err := s.uploader.UploadFile(ctx, file, s.bucket, payload.Key); err != nil {
+		return fmt.Errorf("error al subir %s: %w", payload.Key, err)
+	}
+	s.logger.Info("Canción transcodificada",
+		zap.String("guildID", job.GuildID),
+		zap.String("correlationID", job.CorrelationID),
+		zap.String("URL", payload.Song.URL),
+		zap.String("key", payload.Key))
+	return nil
+}
@@ -0,0 +1,62 @@
+package transcoder
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"path/filepath"
+	"testing"
+)
+
+const prefetchMessage = `{"type":"prefetch","guild_id":"1","correlation_id":"ab12cd","payload":{"song":{"Title":"cancion","URL":"https://www.youtube.com/watch?v=abc"},"key":"audio/abc.dca"}}`
+
+func TestService_Process(t *testing.T) {
+	workDir := t.TempDir()
+	file := filepath.Join(workDir, "abc.dca")
+
+	t.Run("transcodifica y sube la canción", func(t *testing.T) {
+		downloader, uploader, logger := new(MockDownloader), new(MockUploader), new(MockLogger)
+		downloader.On("DownloadDCA", mock.Anything, "https://www.youtube.com/watch?v=abc", file).Return(nil)
+		uploader.On("UploadFile", mock.Anything, file, "bucket", "audio/abc.dca").Return(nil)
+		logger.On("Info", "Canción transcodificada", mock.Anything).Return()
+
+		response := NewService(downloader, uploader, "bucket", workDir, logger).Process(context.Background(), events.SQSEvent{
+			Records: []events.SQSMessage{{MessageId: "1", Body: prefetchMessage}},
+		})
+
+		assert.Empty(t, response.BatchItemFailures)
+		downloader.AssertExpectations(t)
+		uploader.AssertExpectations(t)
+	})
+
+	t.Run("devuelve como fallidos los mensajes que no se pudieron transcodificar", func(t *testing.T) {
+		downloader, uploader, logger := new(MockDownloader), new(MockUploader), new(MockLogger)
+		downloader.On("DownloadDCA", mock.Anything, mock.Anything, file).Return(errors.New("HTTP Error 429"))
+		logger.On("Error", "Error al transcodificar la canción", mock.Anything).Return()
+
+		response := NewService(downloader, uploader, "bucket", workDir, logger).Process(context.Background(), events.SQSEvent{
+			Records: []events.SQSMessage{{MessageId: "1", Body: prefetchMessage}},
+		})
+
+		assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "1"}}, response.BatchItemFailures)
+		uploader.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("descarta los mensajes inválidos", func(t *testing.T) {
+		downloader, uploader, logger := new(MockDownloader), new(MockUploader), new(MockLogger)
+		logger.On("Error", mock.Anything, mock.Anything).Return()
+
+		response := NewService(downloader, uploader, "bucket", workDir, logger).Process(context.Background(), events.SQSEvent{
+			Records: []events.SQSMessage{
+				{MessageId: "1", Body: "no es json"},
+				{MessageId: "2", Body: `{"type":"lookup","payload":{}}`},
+				{MessageId: "3", Body: `{"type":"prefetch","payload":{"song":{"URL":"https://www.youtube.com/watch?v=abc"}}}`},
+			},
+		})
+
+		assert.Empty(t, response.BatchItemFailures)
+		downloader.AssertNotCalled(t, "DownloadDCA", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
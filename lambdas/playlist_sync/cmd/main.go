@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/playlist_sync/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/playlist_sync/internal/playlistsync"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.uber.org/zap"
+	"os"
+)
+
+// handler replica los cambios de la tabla de playlists. El event source mapping del stream tiene que tener
+// activado ReportBatchItemFailures para que se reintente desde el registro que falló.
+func handler(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	logger, err := logging.NewLambdaLogger(os.Getenv)
+	if err != nil {
+		panic("Error creando el logger: " + err.Error())
+	}
+	defer func() {
+		err := logger.Close()
+		if err != nil {
+			logger.Error("Error cerrando el logger", zap.Error(err))
+		}
+	}()
+
+	cfg := config.LoadConfig()
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return events.DynamoDBEventResponse{}, err
+	}
+
+	var notifier playlistsync.Notifier
+	if cfg.WebhookURL != "" {
+		notifier = playlistsync.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret)
+	}
+	service := playlistsync.NewService(playlistsync.NewS3Storage(s3.NewFromConfig(awsCfg), cfg.Bucket), notifier, cfg.Prefix, logger)
+	return service.Process(ctx, event), nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
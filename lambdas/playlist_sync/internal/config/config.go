@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+)
+
+type Config struct {
+	// Bucket es el bucket de S3 donde se guarda la copia de cada playlist.
+	Bucket string
+	// Prefix es el prefijo de las claves de las copias; cada playlist se guarda en {Prefix}{guildID}.json.
+	Prefix string
+	// WebhookURL es la URL a la que se notifica cada cambio. Si está vacía, no se notifica.
+	WebhookURL string
+	// WebhookSecret firma el cuerpo de las notificaciones con HMAC-SHA256 en el header X-Signature-256.
+	WebhookSecret string
+}
+
+// LoadConfig carga la configuración de las variables de entorno.
+func LoadConfig() *Config {
+	config := &Config{
+		Bucket:        os.Getenv("S3_BUCKET"),
+		Prefix:        os.Getenv("S3_PREFIX"),
+		WebhookURL:    os.Getenv("WEBHOOK_URL"),
+		WebhookSecret: os.Getenv("WEBHOOK_SECRET"),
+	}
+	if config.Prefix == "" {
+		config.Prefix = "playlists/"
+	}
+	return config
+}
@@ -0,0 +1,47 @@
+package playlistsync
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) Put(ctx context.Context, key string, body []byte) error {
+	return m.Called(key, body).Error(0)
+}
+
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
+	return m.Called(key).Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, change Change) error {
+	return m.Called(change).Error(0)
+}
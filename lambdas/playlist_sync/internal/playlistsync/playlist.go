@@ -0,0 +1,120 @@
+// Package playlistsync replica en S3 los cambios de las playlists guardadas en DynamoDB y los notifica a un
+// webhook, a partir del stream de la tabla.
+//
+// Cada ítem de la tabla es la playlist de un servidor:
+//
+//	guild_id   (S, clave de partición)
+//	songs      (L de M: title S, url S, duration N en segundos, requested_by S)
+//	updated_at (S, RFC 3339)
+//
+// El stream tiene que tener el tipo de vista NEW_AND_OLD_IMAGES para poder calcular qué canciones cambiaron.
+package playlistsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"strconv"
+)
+
+type (
+	// Playlist es la playlist de un servidor, con el formato de la copia en S3.
+	Playlist struct {
+		GuildID   string `json:"guild_id"`
+		Songs     []Song `json:"songs"`
+		UpdatedAt string `json:"updated_at,omitempty"`
+	}
+
+	// Song es una canción de la playlist.
+	Song struct {
+		Title       string  `json:"title"`
+		URL         string  `json:"url"`
+		Duration    float64 `json:"duration,omitempty"`
+		RequestedBy string  `json:"requested_by,omitempty"`
+	}
+)
+
+// playlistFromImage convierte la imagen de un ítem del stream en una playlist. Devuelve nil si la imagen está
+// vacía, por ejemplo la imagen vieja de un ítem recién creado.
+func playlistFromImage(image map[string]events.DynamoDBAttributeValue) (*Playlist, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+	// Los atributos se pasan a JSON plano y de ahí a Playlist, para no recorrer cada campo a mano.
+	data, err := json.Marshal(attributeMap(image))
+	if err != nil {
+		return nil, err
+	}
+	var playlist Playlist
+	if err := json.Unmarshal(data, &playlist); err != nil {
+		return nil, fmt.Errorf("error al leer la playlist: %w", err)
+	}
+	if playlist.GuildID == "" {
+		return nil, fmt.Errorf("el ítem no tiene guild_id")
+	}
+	return &playlist, nil
+}
+
+func attributeMap(m map[string]events.DynamoDBAttributeValue) map[string]interface{} {
+	values := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		values[k] = attributeValue(v)
+	}
+	return values
+}
+
+// attributeValue convierte un atributo de DynamoDB en el valor equivalente de JSON.
+func attributeValue(av events.DynamoDBAttributeValue) interface{} {
+	switch av.DataType() {
+	case events.DataTypeString:
+		return av.String()
+	case events.DataTypeNumber:
+		if f, err := strconv.ParseFloat(av.Number(), 64); err == nil {
+			return f
+		}
+		return av.Number()
+	case events.DataTypeBoolean:
+		return av.Boolean()
+	case events.DataTypeList:
+		list := make([]interface{}, 0, len(av.List()))
+		for _, item := range av.List() {
+			list = append(list, attributeValue(item))
+		}
+		return list
+	case events.DataTypeMap:
+		return attributeMap(av.Map())
+	case events.DataTypeStringSet:
+		return av.StringSet()
+	case events.DataTypeNumberSet:
+		return av.NumberSet()
+	case events.DataTypeBinary:
+		return av.Binary()
+	case events.DataTypeBinarySet:
+		return av.BinarySet()
+	default:
+		return nil
+	}
+}
+
+// diff cuenta las canciones que se agregaron y se quitaron entre las dos versiones de la playlist, por URL.
+func diff(oldPlaylist, newPlaylist *Playlist) (added, removed int) {
+	counts := make(map[string]int)
+	if oldPlaylist != nil {
+		for _, song := range oldPlaylist.Songs {
+			counts[song.URL]++
+		}
+	}
+	if newPlaylist != nil {
+		for _, song := range newPlaylist.Songs {
+			counts[song.URL]--
+		}
+	}
+	for _, n := range counts {
+		if n > 0 {
+			removed += n
+		} else {
+			added -= n
+		}
+	}
+	return added, removed
+}
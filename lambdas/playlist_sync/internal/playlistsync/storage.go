@@ -0,0 +1,57 @@
+package playlistsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Storage guarda las copias de las playlists.
+type Storage interface {
+	Put(ctx context.Context, key string, body []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// S3API son las operaciones de S3 que usa S3Storage.
+type S3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Storage guarda las copias en un bucket de S3. Con el versionado del bucket activado, cada cambio queda como
+// una versión del objeto y las playlists borradas se pueden recuperar.
+type S3Storage struct {
+	client S3API
+	bucket string
+}
+
+// NewS3Storage crea un S3Storage sobre el bucket indicado.
+func NewS3Storage(client S3API, bucket string) *S3Storage {
+	return &S3Storage{client: client, bucket: bucket}
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("error al subir %s a S3: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("error al borrar %s de S3: %w", key, err)
+	}
+	return nil
+}
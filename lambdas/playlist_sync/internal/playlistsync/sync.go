@@ -0,0 +1,94 @@
+package playlistsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+// Service replica los cambios del stream en Storage y los notifica con Notifier.
+type Service struct {
+	storage  Storage
+	notifier Notifier
+	prefix   string
+	logger   logging.Logger
+}
+
+// NewService crea un Service. notifier puede ser nil si no se notifican los cambios.
+func NewService(storage Storage, notifier Notifier, prefix string, logger logging.Logger) *Service {
+	return &Service{storage: storage, notifier: notifier, prefix: prefix, logger: logger}
+}
+
+// Process procesa los registros del stream en orden. Si uno falla, se corta ahí y se informa como falla del
+// lote: Lambda vuelve a entregar desde ese registro, así los cambios de una playlist no se aplican desordenados.
+func (s *Service) Process(ctx context.Context, event events.DynamoDBEvent) events.DynamoDBEventResponse {
+	var response events.DynamoDBEventResponse
+	for _, record := range event.Records {
+		if err := s.processRecord(ctx, record); err != nil {
+			s.logger.Error("Error al sincronizar la playlist", zap.String("eventID", record.EventID), zap.Error(err))
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: record.Change.SequenceNumber,
+			})
+			break
+		}
+	}
+	return response
+}
+
+func (s *Service) processRecord(ctx context.Context, record events.DynamoDBEventRecord) error {
+	oldPlaylist, err := playlistFromImage(record.Change.OldImage)
+	if err != nil {
+		return err
+	}
+	newPlaylist, err := playlistFromImage(record.Change.NewImage)
+	if err != nil {
+		return err
+	}
+
+	change := Change{Time: record.Change.ApproximateCreationDateTime.Time}
+	change.Added, change.Removed = diff(oldPlaylist, newPlaylist)
+	switch events.DynamoDBOperationType(record.EventName) {
+	case events.DynamoDBOperationTypeInsert, events.DynamoDBOperationTypeModify:
+		if newPlaylist == nil {
+			return fmt.Errorf("el registro %s no tiene la imagen nueva; el stream tiene que ser NEW_AND_OLD_IMAGES", record.EventID)
+		}
+		change.Event = "updated"
+		if record.EventName == string(events.DynamoDBOperationTypeInsert) {
+			change.Event = "created"
+		}
+		change.GuildID, change.Songs, change.Key = newPlaylist.GuildID, len(newPlaylist.Songs), s.key(newPlaylist.GuildID)
+		body, err := json.MarshalIndent(newPlaylist, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := s.storage.Put(ctx, change.Key, body); err != nil {
+			return err
+		}
+	case events.DynamoDBOperationTypeRemove:
+		if oldPlaylist == nil {
+			return fmt.Errorf("el registro %s no tiene la imagen vieja; el stream tiene que ser NEW_AND_OLD_IMAGES", record.EventID)
+		}
+		change.Event, change.GuildID, change.Key = "deleted", oldPlaylist.GuildID, s.key(oldPlaylist.GuildID)
+		if err := s.storage.Delete(ctx, change.Key); err != nil {
+			return err
+		}
+	default:
+		s.logger.Info("Se ignoró un registro del stream", zap.String("eventName", record.EventName))
+		return nil
+	}
+	s.logger.Info("Playlist sincronizada", zap.String("event", change.Event), zap.String("guildID", change.GuildID),
+		zap.Int("added", change.Added), zap.Int("removed", change.Removed))
+
+	if s.notifier == nil {
+		return nil
+	}
+	// La copia ya está en S3; si el webhook falla, reintentar la vuelve a subir igual, sin efectos de más.
+	return s.notifier.Notify(ctx, change)
+}
+
+func (s *Service) key(guildID string) string {
+	return s.prefix + guildID + ".json"
+}
@@ -0,0 +1,88 @@
+package playlistsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+const streamEvent = `{
+	"Records": [
+		{
+			"eventID": "1",
+			"eventName": "MODIFY",
+			"dynamodb": {
+				"ApproximateCreationDateTime": 1714996800,
+				"SequenceNumber": "100",
+				"OldImage": {
+					"guild_id": {"S": "1"},
+					"songs": {"L": [
+						{"M": {"title": {"S": "Canción A"}, "url": {"S": "https://youtu.be/a"}, "duration": {"N": "180"}}}
+					]}
+				},
+				"NewImage": {
+					"guild_id": {"S": "1"},
+					"songs": {"L": [
+						{"M": {"title": {"S": "Canción B"}, "url": {"S": "https://youtu.be/b"}, "duration": {"N": "200"}, "requested_by": {"S": "tomas"}}},
+						{"M": {"title": {"S": "Canción C"}, "url": {"S": "https://youtu.be/c"}, "duration": {"N": "210"}}}
+					]},
+					"updated_at": {"S": "2024-05-06T12:00:00Z"}
+				}
+			}
+		},
+		{
+			"eventID": "2",
+			"eventName": "REMOVE",
+			"dynamodb": {
+				"SequenceNumber": "101",
+				"OldImage": {"guild_id": {"S": "2"}, "songs": {"L": []}}
+			}
+		}
+	]
+}`
+
+func loadEvent(t *testing.T) events.DynamoDBEvent {
+	t.Helper()
+	var event events.DynamoDBEvent
+	assert.NoError(t, json.Unmarshal([]byte(streamEvent), &event))
+	return event
+}
+
+func TestService_Process(t *testing.T) {
+	storage := new(MockStorage)
+	notifier := new(MockNotifier)
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	storage.On("Put", "playlists/1.json", mock.MatchedBy(func(body []byte) bool {
+		var playlist Playlist
+		return json.Unmarshal(body, &playlist) == nil && len(playlist.Songs) == 2 &&
+			playlist.Songs[0].RequestedBy == "tomas" && playlist.Songs[1].Duration == 210
+	})).Return(nil)
+	storage.On("Delete", "playlists/2.json").Return(nil)
+	notifier.On("Notify", mock.MatchedBy(func(c Change) bool {
+		return c.Event == "updated" && c.GuildID == "1" && c.Songs == 2 && c.Added == 2 && c.Removed == 1
+	})).Return(nil)
+	notifier.On("Notify", mock.MatchedBy(func(c Change) bool { return c.Event == "deleted" && c.Key == "playlists/2.json" })).Return(nil)
+
+	response := NewService(storage, notifier, "playlists/", logger).Process(context.Background(), loadEvent(t))
+
+	assert.Empty(t, response.BatchItemFailures)
+	storage.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestService_ProcessStopsAtFirstFailure(t *testing.T) {
+	storage := new(MockStorage)
+	logger := new(MockLogger)
+	logger.On("Error", "Error al sincronizar la playlist", mock.Anything).Return()
+	storage.On("Put", "playlists/1.json", mock.Anything).Return(errors.New("acceso denegado"))
+
+	response := NewService(storage, nil, "playlists/", logger).Process(context.Background(), loadEvent(t))
+
+	assert.Equal(t, []events.DynamoDBBatchItemFailure{{ItemIdentifier: "100"}}, response.BatchItemFailures)
+	storage.AssertNotCalled(t, "Delete", mock.Anything)
+}
@@ -0,0 +1,68 @@
+package playlistsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Change describe el cambio de una playlist en la notificación al webhook.
+type Change struct {
+	Event   string    `json:"event"`
+	GuildID string    `json:"guild_id"`
+	Songs   int       `json:"songs"`
+	Added   int       `json:"added"`
+	Removed int       `json:"removed"`
+	Key     string    `json:"key"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier notifica los cambios de las playlists.
+type Notifier interface {
+	Notify(ctx context.Context, change Change) error
+}
+
+// WebhookNotifier envía cada cambio como JSON a un webhook. Si tiene un secreto, firma el cuerpo con
+// HMAC-SHA256 en el header X-Signature-256 para que el receptor pueda verificar el origen.
+type WebhookNotifier struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+// NewWebhookNotifier crea un WebhookNotifier.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{client: &http.Client{Timeout: 10 * time.Second}, url: url, secret: secret}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, change Change) error {
+	body, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al notificar el cambio al webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("el webhook respondió %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package playlistsync
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var change Change
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte("secreto"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Signature-256"))
+		assert.NoError(t, json.Unmarshal(body, &change))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, "secreto").Notify(context.Background(), Change{Event: "created", GuildID: "1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "created", change.Event)
+}
+
+func TestWebhookNotifier_NotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, "").Notify(context.Background(), Change{Event: "deleted"})
+
+	assert.ErrorContains(t, err, "502")
+}
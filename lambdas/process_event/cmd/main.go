@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/github_event"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/message_queue"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/service"
 	"github.com/aws/aws-lambda-go/events"
@@ -14,7 +14,7 @@ import (
 
 func main() {
 	// Configuración del logger
-	logger, err := logging.NewZapLogger()
+	logger, err := logging.NewLambdaLogger(os.Getenv)
 	if err != nil {
 		panic("Error creando el logger: " + err.Error())
 	}
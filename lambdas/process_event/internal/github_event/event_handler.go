@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/common"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/logging"
 	"github.com/aws/aws-lambda-go/events"
 	"go.uber.org/zap"
 	"net/http"
@@ -39,6 +39,14 @@ func (m *MockLogger) Error(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
 
+func (m *MockLogger) Warn(msg string, fields ...zap.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zap.Field) {
+	m.Called(fields)
+}
+
 func (m *MockLogger) Info(msg string, fields ...zap.Field) {
 	m.Called(msg, fields)
 }
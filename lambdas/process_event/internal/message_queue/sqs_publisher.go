@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"go.uber.org/zap"
@@ -3,8 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/common"
-	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/logging"
 	"github.com/Tomas-vilte/GoMusicBot/lambdas/process_event/internal/message_queue"
 	"go.uber.org/zap"
 )
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/song_requests/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/song_requests/internal/requests"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"go.uber.org/zap"
+	"os"
+)
+
+// handler recibe los pedidos de canciones del sitio web por API Gateway (integración proxy) y los publica en la
+// cola de comandos externos del bot.
+func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger, err := logging.NewLambdaLogger(os.Getenv)
+	if err != nil {
+		panic("Error creando el logger: " + err.Error())
+	}
+	defer func() {
+		err := logger.Close()
+		if err != nil {
+			logger.Error("Error cerrando el logger", zap.Error(err))
+		}
+	}()
+
+	cfg := config.LoadConfig()
+	if cfg.Secret == "" || cfg.QueueURL == "" {
+		// Sin secreto no se puede verificar ningún pedido: mejor fallar que aceptarlos todos.
+		return events.APIGatewayProxyResponse{}, errors.New("faltan REQUEST_SECRET o QUEUE_URL")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	queue := jobs.NewSQSQueue(sqs.NewFromConfig(awsCfg), cfg.QueueURL, logger)
+	return requests.NewHandler(queue, cfg.Secret, cfg.AllowedGuilds, cfg.MaxSkew, logger).Handle(ctx, req), nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// QueueURL es la cola de SQS de comandos externos del bot (REMOTE_SQS_QUEUEURL del bot).
+	QueueURL string
+	// Secret es el secreto compartido con el sitio web con el que se firman los pedidos.
+	Secret string
+	// AllowedGuilds son los servidores en los que se aceptan pedidos. Si está vacío, se aceptan en todos.
+	AllowedGuilds []string
+	// MaxSkew es la diferencia máxima entre la hora del pedido firmado y la de la lambda.
+	MaxSkew time.Duration
+}
+
+// LoadConfig carga la configuración de las variables de entorno.
+func LoadConfig() *Config {
+	config := &Config{
+		QueueURL: os.Getenv("QUEUE_URL"),
+		Secret:   os.Getenv("REQUEST_SECRET"),
+		MaxSkew:  5 * time.Minute,
+	}
+	for _, guildID := range strings.Split(os.Getenv("ALLOWED_GUILDS"), ",") {
+		if guildID = strings.TrimSpace(guildID); guildID != "" {
+			config.AllowedGuilds = append(config.AllowedGuilds, guildID)
+		}
+	}
+	if maxSkew, err := time.ParseDuration(os.Getenv("MAX_SKEW")); err == nil && maxSkew > 0 {
+		config.MaxSkew = maxSkew
+	}
+	return config
+}
@@ -0,0 +1,135 @@
+// Package requests recibe los pedidos de canciones del sitio web por API Gateway, verifica su firma y los publica
+// en la cola de comandos externos del bot, con el mismo formato que consume internal/control/queueapi.
+package requests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/queueapi"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Source es el origen con el que los pedidos quedan en la auditoría del bot.
+const Source = "website"
+
+// maxBodySize es el tamaño máximo del cuerpo de un pedido.
+const maxBodySize = 16 * 1024
+
+type (
+	// SongRequest es el cuerpo de un pedido: agregar input a la lista del servidor. Si los canales están vacíos,
+	// el bot usa los que tiene guardados el reproductor.
+	SongRequest struct {
+		GuildID        string `json:"guild_id"`
+		Input          string `json:"input"`
+		VoiceChannelID string `json:"voice_channel_id,omitempty"`
+		TextChannelID  string `json:"text_channel_id,omitempty"`
+		RequestedBy    string `json:"requested_by,omitempty"`
+	}
+
+	// Publisher publica los comandos en la cola del bot.
+	Publisher interface {
+		Publish(ctx context.Context, job *jobs.Job) error
+	}
+)
+
+// Handler atiende los pedidos de API Gateway.
+type Handler struct {
+	publisher     Publisher
+	secret        string
+	allowedGuilds map[string]bool
+	maxSkew       time.Duration
+	logger        logging.Logger
+	now           func() time.Time
+}
+
+// NewHandler crea un Handler. Si allowedGuilds está vacío, se aceptan pedidos para cualquier servidor.
+func NewHandler(publisher Publisher, secret string, allowedGuilds []string, maxSkew time.Duration, logger logging.Logger) *Handler {
+	allowed := make(map[string]bool, len(allowedGuilds))
+	for _, guildID := range allowedGuilds {
+		allowed[guildID] = true
+	}
+	return &Handler{publisher: publisher, secret: secret, allowedGuilds: allowed, maxSkew: maxSkew, logger: logger, now: time.Now}
+}
+
+// Handle verifica el pedido y lo publica como un comando enqueue. Responde 202 cuando el pedido quedó en la cola;
+// que la canción se encuentre y se agregue lo resuelve el bot después.
+func (h *Handler) Handle(ctx context.Context, req events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	if req.RequestContext.RequestID != "" {
+		ctx = logging.ContextWithCorrelationID(ctx, req.RequestContext.RequestID)
+	}
+	logger := logging.FromContext(ctx, h.logger)
+
+	if req.HTTPMethod != http.MethodPost {
+		return response(http.StatusMethodNotAllowed, "método no permitido")
+	}
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return response(http.StatusBadRequest, "cuerpo inválido")
+		}
+		body = decoded
+	}
+	if len(body) > maxBodySize {
+		return response(http.StatusRequestEntityTooLarge, "el pedido es demasiado grande")
+	}
+	if err := Verify(h.secret, header(req.Headers, HeaderTimestamp), header(req.Headers, HeaderSignature), body, h.now(), h.maxSkew); err != nil {
+		logger.Warn("Pedido rechazado por la firma", zap.String("sourceIP", req.RequestContext.Identity.SourceIP), zap.Error(err))
+		return response(http.StatusUnauthorized, err.Error())
+	}
+
+	var request SongRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return response(http.StatusBadRequest, "cuerpo inválido")
+	}
+	cmd := queueapi.EnqueueCommand{
+		Input:          request.Input,
+		VoiceChannelID: request.VoiceChannelID,
+		TextChannelID:  request.TextChannelID,
+		RequestedBy:    request.RequestedBy,
+		Source:         Source,
+	}
+	if err := queueapi.ValidateEnqueue(request.GuildID, cmd); err != nil {
+		return response(http.StatusBadRequest, err.Error())
+	}
+	if len(h.allowedGuilds) > 0 && !h.allowedGuilds[request.GuildID] {
+		return response(http.StatusForbidden, "el servidor no acepta pedidos del sitio")
+	}
+
+	job, err := jobs.NewJob(ctx, queueapi.CommandEnqueue, request.GuildID, cmd)
+	if err == nil {
+		err = h.publisher.Publish(ctx, job)
+	}
+	if err != nil {
+		logger.Error("Error al encolar el pedido", zap.String("guildID", request.GuildID), zap.Error(err))
+		return response(http.StatusInternalServerError, "no se pudo encolar el pedido")
+	}
+	logger.Info("Pedido encolado", zap.String("guildID", request.GuildID), zap.String("input", request.Input), zap.String("requestedBy", request.RequestedBy))
+	return response(http.StatusAccepted, "pedido encolado")
+}
+
+// header busca un header sin distinguir mayúsculas: API Gateway HTTP API los pasa en minúsculas.
+func header(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+func response(status int, message string) events.APIGatewayProxyResponse {
+	body, _ := json.Marshal(map[string]string{"message": message})
+	return events.APIGatewayProxyResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
@@ -0,0 +1,126 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"github.com/Tomas-vilte/GoMusicBot/internal/control/queueapi"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	secret  = "secreto"
+	guildID = "123456789012345678"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func signedRequest(body string, at time.Time) events.APIGatewayProxyRequest {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Headers: map[string]string{
+			// API Gateway HTTP API pasa los headers en minúsculas.
+			"x-timestamp":     timestamp,
+			"x-signature-256": Sign(secret, timestamp, []byte(body)),
+		},
+		Body:           body,
+		RequestContext: events.APIGatewayProxyRequestContext{RequestID: "req-1"},
+	}
+}
+
+func newTestHandler(publisher Publisher, allowedGuilds ...string) (*Handler, *MockLogger) {
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	handler := NewHandler(publisher, secret, allowedGuilds, 5*time.Minute, logger)
+	handler.now = func() time.Time { return now }
+	return handler, logger
+}
+
+func TestHandler_Handle(t *testing.T) {
+	t.Run("encola el pedido", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		var published *jobs.Job
+		publisher.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			published = args.Get(1).(*jobs.Job)
+		}).Return(nil)
+		handler, _ := newTestHandler(publisher)
+
+		resp := handler.Handle(context.Background(), signedRequest(`{"guild_id":"`+guildID+`","input":"never gonna give you up","requested_by":"tomas"}`, now))
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, queueapi.CommandEnqueue, published.Type)
+		assert.Equal(t, guildID, published.GuildID)
+		assert.Equal(t, "req-1", published.CorrelationID)
+		var cmd queueapi.EnqueueCommand
+		assert.NoError(t, published.Decode(&cmd))
+		assert.Equal(t, queueapi.EnqueueCommand{Input: "never gonna give you up", RequestedBy: "tomas", Source: Source}, cmd)
+	})
+
+	t.Run("rechaza la firma inválida", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		handler, _ := newTestHandler(publisher)
+		req := signedRequest(`{"guild_id":"`+guildID+`","input":"cancion"}`, now)
+		req.Body = `{"guild_id":"` + guildID + `","input":"otra cancion"}`
+
+		resp := handler.Handle(context.Background(), req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rechaza los pedidos viejos", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		handler, _ := newTestHandler(publisher)
+
+		resp := handler.Handle(context.Background(), signedRequest(`{"guild_id":"`+guildID+`","input":"cancion"}`, now.Add(-10*time.Minute)))
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("rechaza los pedidos inválidos", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		handler, _ := newTestHandler(publisher)
+
+		resp := handler.Handle(context.Background(), signedRequest(`{"guild_id":"abc","input":"cancion"}`, now))
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rechaza los servidores no permitidos", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		handler, _ := newTestHandler(publisher, "999999999999999999")
+
+		resp := handler.Handle(context.Background(), signedRequest(`{"guild_id":"`+guildID+`","input":"cancion"}`, now))
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("solo acepta POST", func(t *testing.T) {
+		handler, _ := newTestHandler(new(MockPublisher))
+		req := signedRequest(`{}`, now)
+		req.HTTPMethod = http.MethodGet
+
+		assert.Equal(t, http.StatusMethodNotAllowed, handler.Handle(context.Background(), req).StatusCode)
+	})
+
+	t.Run("error al encolar", func(t *testing.T) {
+		publisher := new(MockPublisher)
+		publisher.On("Publish", mock.Anything, mock.Anything).Return(errors.New("sqs caído"))
+		handler, logger := newTestHandler(publisher)
+
+		resp := handler.Handle(context.Background(), signedRequest(`{"guild_id":"`+guildID+`","input":"cancion"}`, now))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		logger.AssertCalled(t, "Error", "Error al encolar el pedido", mock.Anything)
+	})
+}
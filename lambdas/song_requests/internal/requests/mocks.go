@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/jobs"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, job *jobs.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
@@ -0,0 +1,52 @@
+package requests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers de los pedidos firmados.
+const (
+	HeaderSignature = "X-Signature-256"
+	HeaderTimestamp = "X-Timestamp"
+)
+
+var (
+	errMissingSignature = errors.New("falta la firma del pedido")
+	errInvalidTimestamp = errors.New("la hora del pedido es inválida o está fuera de rango")
+	errInvalidSignature = errors.New("la firma del pedido es inválida")
+)
+
+// Sign devuelve la firma de un pedido: HMAC-SHA256 de "{timestamp}.{body}" con el secreto compartido, en
+// hexadecimal y con el prefijo sha256=. Incluir la hora en la firma evita que se reenvíe un pedido capturado.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify comprueba la firma del pedido y que timestamp (segundos Unix) no difiera de now en más de maxSkew.
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, maxSkew time.Duration) error {
+	if signature == "" || timestamp == "" {
+		return errMissingSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidTimestamp
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > maxSkew || skew < -maxSkew {
+		return errInvalidTimestamp
+	}
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+		return errInvalidSignature
+	}
+	return nil
+}
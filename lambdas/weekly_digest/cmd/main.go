@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/weekly_digest/internal/config"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/weekly_digest/internal/digest"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/weekly_digest/internal/redis"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+// handler publica el resumen semanal. Se ejecuta con una regla programada de EventBridge, por ejemplo
+// cron(0 12 ? * MON *), y resume la semana anterior a la hora del evento.
+func handler(ctx context.Context, event events.CloudWatchEvent) error {
+	logger, err := logging.NewLambdaLogger(os.Getenv)
+	if err != nil {
+		panic("Error creando el logger: " + err.Error())
+	}
+	defer func() {
+		err := logger.Close()
+		if err != nil {
+			logger.Error("Error cerrando el logger", zap.Error(err))
+		}
+	}()
+
+	cfg := config.LoadConfig()
+	client := redis.NewClient(cfg.RedisAddress, cfg.RedisPassword, cfg.RedisDB)
+	defer client.Close()
+
+	service := digest.NewService(digest.NewRedisStore(client), digest.NewWebhookSender(), cfg.Webhooks, cfg.Top, logger)
+	now := event.Time
+	if now.IsZero() {
+		// Invocación manual, sin evento programado.
+		now = time.Now()
+	}
+	return service.Run(ctx, now)
+}
+
+func main() {
+	lambda.Start(handler)
+}
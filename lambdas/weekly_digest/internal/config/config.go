@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Config struct {
+	RedisAddress  string
+	RedisPassword string
+	RedisDB       int
+	// Webhooks son los webhooks del canal de anuncios de cada servidor, por ID de servidor.
+	Webhooks map[string]string
+	// Top es la cantidad de canciones y usuarios que se muestran en el resumen.
+	Top int
+}
+
+// LoadConfig carga la configuración de las variables de entorno. DIGEST_WEBHOOKS tiene el formato
+// guildID=URL,guildID=URL.
+func LoadConfig() *Config {
+	config := &Config{
+		RedisAddress:  os.Getenv("REDIS_ADDRESS"),
+		RedisPassword: os.Getenv("REDIS_PASSWORD"),
+		Webhooks:      parseWebhooks(os.Getenv("DIGEST_WEBHOOKS")),
+		Top:           5,
+	}
+	if db, err := strconv.Atoi(os.Getenv("REDIS_DB")); err == nil {
+		config.RedisDB = db
+	}
+	if top, err := strconv.Atoi(os.Getenv("DIGEST_TOP")); err == nil && top > 0 {
+		config.Top = top
+	}
+	return config
+}
+
+func parseWebhooks(value string) map[string]string {
+	webhooks := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		guildID, url, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && guildID != "" && url != "" {
+			webhooks[guildID] = url
+		}
+	}
+	return webhooks
+}
@@ -0,0 +1,68 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/internal/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Service publica el resumen semanal de cada servidor en su canal de anuncios.
+type Service struct {
+	store    Store
+	sender   Sender
+	webhooks map[string]string
+	top      int
+	logger   logging.Logger
+}
+
+// NewService crea un Service. webhooks son los webhooks del canal de anuncios de cada servidor; los servidores
+// sin webhook se saltean.
+func NewService(store Store, sender Sender, webhooks map[string]string, top int, logger logging.Logger) *Service {
+	return &Service{store: store, sender: sender, webhooks: webhooks, top: top, logger: logger}
+}
+
+// Week devuelve la semana ISO de t con el formato de las claves del bot, por ejemplo "2024-W05".
+func Week(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Run publica el resumen de la semana anterior a now. Si falla el envío a algún servidor, sigue con los demás
+// y devuelve los errores al final.
+func (s *Service) Run(ctx context.Context, now time.Time) error {
+	week := Week(now.AddDate(0, 0, -7))
+	guilds, err := s.store.Guilds(ctx, week)
+	if err != nil {
+		s.logger.Error("Error al obtener los servidores", zap.String("week", week), zap.Error(err))
+		return err
+	}
+
+	var errs []error
+	sent := 0
+	for _, guildID := range guilds {
+		webhookURL, ok := s.webhooks[guildID]
+		if !ok {
+			continue
+		}
+		stats, err := s.store.GuildStats(ctx, week, guildID, s.top)
+		if err != nil {
+			s.logger.Error("Error al obtener las estadísticas del servidor", zap.String("guildID", guildID), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		if stats.Plays == 0 {
+			continue
+		}
+		if err := s.sender.Send(ctx, webhookURL, FormatDigest(week, stats)); err != nil {
+			s.logger.Error("Error al enviar el resumen del servidor", zap.String("guildID", guildID), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+	s.logger.Info("Resumen semanal enviado", zap.String("week", week), zap.Int("guilds", sent))
+	return errors.Join(errs...)
+}
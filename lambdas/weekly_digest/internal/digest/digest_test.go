@@ -0,0 +1,64 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestService_Run(t *testing.T) {
+	store := new(MockStore)
+	sender := new(MockSender)
+	logger := new(MockLogger)
+	logger.On("Info", "Resumen semanal enviado", mock.Anything).Return()
+	logger.On("Error", "Error al enviar el resumen del servidor", mock.Anything).Return()
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC) // Lunes de la semana 19: se resume la 18.
+
+	store.On("Guilds", "2024-W18").Return([]string{"1", "2", "3", "4"}, nil)
+	store.On("GuildStats", "2024-W18", "1", 5).Return(&GuildStats{GuildID: "1", Plays: 3, TopSongs: []Count{{Name: "Canción", Count: 3}}}, nil)
+	store.On("GuildStats", "2024-W18", "2", 5).Return(&GuildStats{GuildID: "2", Plays: 1}, nil)
+	store.On("GuildStats", "2024-W18", "4", 5).Return(&GuildStats{GuildID: "4"}, nil)
+	sender.On("Send", "https://discord.com/api/webhooks/1", mock.MatchedBy(func(embed *Embed) bool {
+		return embed.Footer.Text == "Semana 2024-W18"
+	})).Return(nil)
+	sender.On("Send", "https://discord.com/api/webhooks/2", mock.Anything).Return(errors.New("404 Not Found"))
+
+	service := NewService(store, sender, map[string]string{
+		"1": "https://discord.com/api/webhooks/1",
+		"2": "https://discord.com/api/webhooks/2",
+		"4": "https://discord.com/api/webhooks/4",
+	}, 5, logger)
+	err := service.Run(context.Background(), now)
+
+	assert.ErrorContains(t, err, "404 Not Found")
+	store.AssertNotCalled(t, "GuildStats", "2024-W18", "3", 5)
+	sender.AssertNotCalled(t, "Send", "https://discord.com/api/webhooks/4", mock.Anything)
+	sender.AssertExpectations(t)
+}
+
+func TestRedisStore_GuildStats(t *testing.T) {
+	client := new(MockCommander)
+	client.On("Do", []string{"HGETALL", "gomusicbot:stats:2024-W18:1:songs"}).Return([]interface{}{"B", "2", "A", "2", "C", "5"}, nil)
+	client.On("Do", []string{"HGETALL", "gomusicbot:stats:2024-W18:1:requesters"}).Return([]interface{}{"tomas", "9"}, nil)
+
+	stats, err := NewRedisStore(client).GuildStats(context.Background(), "2024-W18", "1", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), stats.Plays)
+	assert.Equal(t, []Count{{Name: "C", Count: 5}, {Name: "A", Count: 2}}, stats.TopSongs)
+	assert.Equal(t, []Count{{Name: "tomas", Count: 9}}, stats.TopRequesters)
+}
+
+func TestFormatDigest(t *testing.T) {
+	embed := FormatDigest("2024-W18", &GuildStats{
+		Plays:    12,
+		TopSongs: []Count{{Name: "A", Count: 5}, {Name: "B", Count: 4}, {Name: "C", Count: 2}, {Name: "D", Count: 1}},
+	})
+
+	assert.Equal(t, "Esta semana sonaron **12** canciones en el servidor.", embed.Description)
+	assert.Equal(t, "🥇 A — 5 reproducciones\n🥈 B — 4 reproducciones\n🥉 C — 2 reproducciones\n4. D — 1 reproducciones", embed.Fields[0].Value)
+	assert.Equal(t, "Sin datos", embed.Fields[1].Value)
+}
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// digestColor es el color del embed del resumen.
+const digestColor = 0x5865F2
+
+type (
+	// Embed es un embed de Discord, con los campos que usa el resumen.
+	Embed struct {
+		Title       string       `json:"title"`
+		Description string       `json:"description,omitempty"`
+		Color       int          `json:"color"`
+		Fields      []EmbedField `json:"fields,omitempty"`
+		Footer      *EmbedFooter `json:"footer,omitempty"`
+	}
+
+	// EmbedField es un campo de un embed.
+	EmbedField struct {
+		Name   string `json:"name"`
+		Value  string `json:"value"`
+		Inline bool   `json:"inline,omitempty"`
+	}
+
+	// EmbedFooter es el pie de un embed.
+	EmbedFooter struct {
+		Text string `json:"text"`
+	}
+)
+
+// medals son los íconos de los tres primeros puestos.
+var medals = []string{"🥇", "🥈", "🥉"}
+
+// FormatDigest arma el embed del resumen semanal del servidor.
+func FormatDigest(week string, stats *GuildStats) *Embed {
+	return &Embed{
+		Title:       "📊 Resumen semanal de música",
+		Description: fmt.Sprintf("Esta semana sonaron **%d** canciones en el servidor.", stats.Plays),
+		Color:       digestColor,
+		Fields: []EmbedField{
+			{Name: "🎵 Top canciones", Value: ranking(stats.TopSongs, "reproducciones"), Inline: false},
+			{Name: "🙋 Top DJs", Value: ranking(stats.TopRequesters, "canciones pedidas"), Inline: false},
+		},
+		Footer: &EmbedFooter{Text: "Semana " + week},
+	}
+}
+
+// ranking arma la lista numerada de los contadores.
+func ranking(counts []Count, unit string) string {
+	if len(counts) == 0 {
+		return "Sin datos"
+	}
+	var builder strings.Builder
+	for i, count := range counts {
+		position := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			position = medals[i]
+		}
+		line := fmt.Sprintf("%s %s — %d %s\n", position, count.Name, count.Count, unit)
+		// Los campos de los embeds aceptan hasta 1024 caracteres.
+		if builder.Len()+len(line) > 1024 {
+			break
+		}
+		builder.WriteString(line)
+	}
+	return strings.TrimSpace(builder.String())
+}
@@ -0,0 +1,59 @@
+package digest
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+type MockLogger struct {
+	mock.Mock
+}
+
+func (m *MockLogger) Info(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Error(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) Warn(msg string, fields ...zapcore.Field) {
+	m.Called(msg, fields)
+}
+
+func (m *MockLogger) With(fields ...zapcore.Field) {
+	m.Called(fields)
+}
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Guilds(ctx context.Context, week string) ([]string, error) {
+	args := m.Called(week)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStore) GuildStats(ctx context.Context, week, guildID string, top int) (*GuildStats, error) {
+	args := m.Called(week, guildID, top)
+	stats, _ := args.Get(0).(*GuildStats)
+	return stats, args.Error(1)
+}
+
+type MockSender struct {
+	mock.Mock
+}
+
+func (m *MockSender) Send(ctx context.Context, webhookURL string, embed *Embed) error {
+	return m.Called(webhookURL, embed).Error(0)
+}
+
+type MockCommander struct {
+	mock.Mock
+}
+
+func (m *MockCommander) Do(ctx context.Context, args ...string) (interface{}, error) {
+	ret := m.Called(args)
+	return ret.Get(0), ret.Error(1)
+}
@@ -0,0 +1,100 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"github.com/Tomas-vilte/GoMusicBot/lambdas/weekly_digest/internal/redis"
+	"sort"
+	"strconv"
+)
+
+type (
+	// Count es la cantidad de reproducciones de una canción o de canciones pedidas por un usuario.
+	Count struct {
+		Name  string
+		Count int64
+	}
+
+	// GuildStats son las estadísticas de una semana de un servidor.
+	GuildStats struct {
+		GuildID       string
+		Plays         int64
+		TopSongs      []Count
+		TopRequesters []Count
+	}
+
+	// Store lee las estadísticas de reproducción que guarda el bot.
+	Store interface {
+		// Guilds devuelve los servidores que reprodujeron algo en la semana.
+		Guilds(ctx context.Context, week string) ([]string, error)
+		// GuildStats devuelve las estadísticas del servidor en la semana, con las top canciones y usuarios.
+		GuildStats(ctx context.Context, week, guildID string, top int) (*GuildStats, error)
+	}
+)
+
+// RedisStore lee las estadísticas con el mismo formato de claves que usa el bot (internal/stats).
+type RedisStore struct {
+	client redis.Commander
+}
+
+// NewRedisStore crea un Store sobre Redis.
+func NewRedisStore(client redis.Commander) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Guilds(ctx context.Context, week string) ([]string, error) {
+	guilds, err := redis.Strings(s.client.Do(ctx, "SMEMBERS", fmt.Sprintf("gomusicbot:stats:%s:guilds", week)))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los servidores de la semana %s: %w", week, err)
+	}
+	sort.Strings(guilds)
+	return guilds, nil
+}
+
+func (s *RedisStore) GuildStats(ctx context.Context, week, guildID string, top int) (*GuildStats, error) {
+	songs, err := s.counts(ctx, fmt.Sprintf("gomusicbot:stats:%s:%s:songs", week, guildID))
+	if err != nil {
+		return nil, err
+	}
+	requesters, err := s.counts(ctx, fmt.Sprintf("gomusicbot:stats:%s:%s:requesters", week, guildID))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &GuildStats{GuildID: guildID, TopSongs: topCounts(songs, top), TopRequesters: topCounts(requesters, top)}
+	for _, song := range songs {
+		stats.Plays += song.Count
+	}
+	return stats, nil
+}
+
+// counts lee un hash de contadores.
+func (s *RedisStore) counts(ctx context.Context, key string) ([]Count, error) {
+	values, err := redis.Strings(s.client.Do(ctx, "HGETALL", key))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener %s: %w", key, err)
+	}
+	counts := make([]Count, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		n, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("contador inválido en %s: %w", key, err)
+		}
+		counts = append(counts, Count{Name: values[i], Count: n})
+	}
+	return counts, nil
+}
+
+// topCounts ordena los contadores de mayor a menor (y por nombre si empatan) y devuelve los primeros n.
+func topCounts(counts []Count, n int) []Count {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
@@ -0,0 +1,51 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sender envía un embed a un webhook de Discord.
+type Sender interface {
+	Send(ctx context.Context, webhookURL string, embed *Embed) error
+}
+
+// WebhookSender envía los embeds con la API de webhooks de Discord.
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender crea un WebhookSender.
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type webhookPayload struct {
+	Username string   `json:"username"`
+	Embeds   []*Embed `json:"embeds"`
+}
+
+func (s *WebhookSender) Send(ctx context.Context, webhookURL string, embed *Embed) error {
+	body, err := json.Marshal(webhookPayload{Username: "ButakeroMusicBotGo", Embeds: []*Embed{embed}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al enviar el resumen al webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("el webhook respondió %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSender().Send(context.Background(), server.URL, &Embed{Title: "Resumen"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Resumen", payload.Embeds[0].Title)
+}
+
+func TestWebhookSender_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSender().Send(context.Background(), server.URL, &Embed{Title: "Resumen"})
+
+	assert.ErrorContains(t, err, "404")
+}
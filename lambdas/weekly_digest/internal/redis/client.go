@@ -0,0 +1,246 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNil se devuelve cuando Redis responde con un valor nulo (por ejemplo, GET de una clave inexistente).
+var ErrNil = errors.New("redis: valor nulo")
+
+// Error es un error devuelto por el servidor de Redis (respuesta "-ERR ...").
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Commander define la interfaz para ejecutar comandos de Redis.
+type Commander interface {
+	Do(ctx context.Context, args ...string) (interface{}, error)
+}
+
+// Client es un cliente mínimo de Redis que habla el protocolo RESP sobre una única conexión.
+// Las respuestas se devuelven como string, int64, nil o []interface{}; los errores del servidor como Error.
+type Client struct {
+	addr        string
+	password    string
+	db          int
+	dialTimeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewClient crea un cliente de Redis. La conexión se abre con el primer comando y se reabre si se corta.
+func NewClient(addr, password string, db int) *Client {
+	return &Client{
+		addr:        addr,
+		password:    password,
+		db:          db,
+		dialTimeout: 5 * time.Second,
+	}
+}
+
+// Do ejecuta un comando y devuelve su respuesta.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTrip(ctx, args)
+	var redisErr Error
+	if err != nil && !errors.Is(err, ErrNil) && !errors.As(err, &redisErr) {
+		// La conexión quedó en un estado desconocido; se descarta para reconectar en el próximo comando.
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	return reply, err
+}
+
+// Close cierra la conexión con Redis.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("error al conectar con Redis: %w", err)
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if c.password != "" {
+		if _, err := c.roundTrip(ctx, []string{"AUTH", c.password}); err != nil {
+			_ = c.Close()
+			return fmt.Errorf("error al autenticar con Redis: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTrip(ctx, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			_ = c.Close()
+			return fmt.Errorf("error al seleccionar la base de Redis: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeCommand(c.conn, args); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// writeCommand escribe el comando como un array de bulk strings.
+func writeCommand(w io.Writer, args []string) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readReply lee una respuesta RESP completa.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: respuesta vacía")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: longitud inválida: %w", err)
+		}
+		if size < 0 {
+			return nil, ErrNil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: longitud inválida: %w", err)
+		}
+		if size < 0 {
+			return nil, ErrNil
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			item, err := readReply(r)
+			if err != nil && !errors.Is(err, ErrNil) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: tipo de respuesta desconocido %q", line[0])
+	}
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("redis: línea mal formada")
+	}
+	return line[:len(line)-2], nil
+}
+
+// String convierte la respuesta de un comando en string.
+func String(reply interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	s, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("redis: se esperaba un string y se recibió %T", reply)
+	}
+	return s, nil
+}
+
+// Int convierte la respuesta de un comando en entero.
+func Int(reply interface{}, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: se esperaba un entero y se recibió %T", reply)
+	}
+	return n, nil
+}
+
+// Strings convierte la respuesta de un comando en una lista de strings. Los elementos nulos quedan vacíos.
+func Strings(reply interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("redis: se esperaba un array y se recibió %T", reply)
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("redis: se esperaba un string y se recibió %T", item)
+		}
+		values[i] = s
+	}
+	return values, nil
+}